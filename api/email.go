@@ -0,0 +1,333 @@
+package api
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// emailTemplatesDir is the directory in the repo holding customized
+	// email templates. A template named after a notification type, such as
+	// order.html or order.txt, replaces default.html or default.txt for
+	// that type.
+	emailTemplatesDir = "email_templates"
+
+	// defaultEmailImageGateway serves the listing thumbnails shown in emails
+	// unless the SMTP settings name another gateway
+	defaultEmailImageGateway = "https://gateway.phore.io/ob/images/"
+)
+
+const defaultTextEmailTemplate = `{{.Body}}
+{{with .Order}}
+Listing: {{.ListingTitle}}
+{{if .Amount}}Amount: {{.Amount}}
+{{end}}{{if .Link}}Open in Phore Marketplace: {{.Link}}
+{{end}}{{end}}`
+
+const defaultHTMLEmailTemplate = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<h2>{{.Title}}</h2>
+{{with .Order}}{{if .ThumbnailURL}}<img src="{{.ThumbnailURL}}" alt="{{.ListingTitle}}" width="160">
+{{end}}<p><strong>{{.ListingTitle}}</strong></p>
+{{end}}<p>{{range lines .Body}}{{.}}<br>
+{{end}}</p>
+{{with .Order}}<table>
+<tr><td>Order ID</td><td>{{.OrderID}}</td></tr>
+{{if .Amount}}<tr><td>Amount</td><td>{{.Amount}}</td></tr>
+{{end}}</table>
+{{if .Link}}<p><a href="{{.Link}}">Open in Phore Marketplace</a></p>
+{{end}}{{end}}</body>
+</html>
+`
+
+// emailData is passed to the email templates
+type emailData struct {
+	Type  repo.NotificationType
+	Title string
+	Body  string
+	Order *emailOrder
+}
+
+// emailOrder holds the details of the order a notification is about
+type emailOrder struct {
+	OrderID      string
+	ListingTitle string
+	Thumbnail    string
+	ThumbnailURL string
+	Amount       string
+	// Link opens the listing in the desktop client
+	Link htmltemplate.URL
+}
+
+var emailTemplateFuncs = map[string]interface{}{
+	"lines": func(s string) []string { return strings.Split(strings.TrimRight(s, "\n"), "\n") },
+}
+
+// renderEmail returns the text and HTML bodies for a notification using
+// the templates in templateDir, falling back to the built in ones
+func renderEmail(templateDir string, data emailData) (string, string, error) {
+	textSource, err := loadEmailTemplate(templateDir, data.Type, ".txt", defaultTextEmailTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	htmlSource, err := loadEmailTemplate(templateDir, data.Type, ".html", defaultHTMLEmailTemplate)
+	if err != nil {
+		return "", "", err
+	}
+
+	textTemplate, err := texttemplate.New("text").Funcs(emailTemplateFuncs).Parse(textSource)
+	if err != nil {
+		return "", "", err
+	}
+	var text bytes.Buffer
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return "", "", err
+	}
+	htmlTemplate, err := htmltemplate.New("html").Funcs(emailTemplateFuncs).Parse(htmlSource)
+	if err != nil {
+		return "", "", err
+	}
+	var html bytes.Buffer
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
+
+func loadEmailTemplate(templateDir string, t repo.NotificationType, ext, fallback string) (string, error) {
+	if templateDir == "" {
+		return fallback, nil
+	}
+	for _, name := range []string{string(t) + ext, "default" + ext} {
+		source, err := ioutil.ReadFile(filepath.Join(templateDir, name))
+		if err == nil {
+			return string(source), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return fallback, nil
+}
+
+// buildEmail returns a multipart message with a text and an HTML part
+func buildEmail(conf *repo.SMTPSettings, subject, text, html string) ([]byte, error) {
+	var (
+		msg  bytes.Buffer
+		body bytes.Buffer
+	)
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, header := range []string{
+		"From: " + conf.SenderEmail,
+		"To: " + conf.RecipientEmail,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", "[Phore Marketplace] "+subject),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	} {
+		msg.WriteString(header + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// emailOrderDetails returns the details of the order a notification is
+// about, or nil if it isn't about an order
+func emailOrderDetails(node *core.OpenBazaarNode, conf *repo.SMTPSettings, n repo.Notifier) *emailOrder {
+	data, err := n.Data()
+	if err != nil {
+		return nil
+	}
+	var wrapper struct {
+		Notification struct {
+			OrderID   string         `json:"orderId"`
+			Title     string         `json:"title"`
+			Slug      string         `json:"slug"`
+			Thumbnail repo.Thumbnail `json:"thumbnail"`
+		} `json:"notification"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil || wrapper.Notification.OrderID == "" {
+		return nil
+	}
+	notification := wrapper.Notification
+	order := &emailOrder{
+		OrderID:      notification.OrderID,
+		ListingTitle: notification.Title,
+		Thumbnail:    notification.Thumbnail.Small,
+	}
+	if node != nil {
+		contract, _, _, _, _, _, err := node.Datastore.Sales().GetByOrderId(notification.OrderID)
+		if err != nil {
+			contract, _, _, _, _, _, err = node.Datastore.Purchases().GetByOrderId(notification.OrderID)
+		}
+		if err == nil {
+			addContractDetails(order, contract)
+		} else if notification.Slug != "" && node.IpfsNode != nil {
+			// Order notifications are only sent to the vendor
+			order.Link = listingLink(node.IpfsNode.Identity.Pretty(), notification.Slug)
+		}
+	}
+	if order.Thumbnail != "" {
+		order.ThumbnailURL = emailImageGateway(conf) + order.Thumbnail
+	}
+	return order
+}
+
+// emailImageGateway returns the gateway URL thumbnails are loaded from,
+// ending in a slash
+func emailImageGateway(conf *repo.SMTPSettings) string {
+	if conf == nil || conf.ImageGateway == "" {
+		return defaultEmailImageGateway
+	}
+	return strings.TrimRight(conf.ImageGateway, "/") + "/"
+}
+
+func addContractDetails(order *emailOrder, contract *pb.RicardianContract) {
+	if len(contract.VendorListings) > 0 {
+		listing := contract.VendorListings[0]
+		if listing.Item != nil {
+			order.ListingTitle = listing.Item.Title
+			if len(listing.Item.Images) > 0 && order.Thumbnail == "" {
+				order.Thumbnail = listing.Item.Images[0].Small
+			}
+		}
+		if listing.VendorID != nil {
+			order.Link = listingLink(listing.VendorID.PeerID, listing.Slug)
+		}
+	}
+	if contract.BuyerOrder != nil && contract.BuyerOrder.Payment != nil {
+		order.Amount = formatEmailAmount(contract.BuyerOrder.Payment.Amount, contract.BuyerOrder.Payment.Coin)
+	}
+}
+
+// listingLink returns a link which opens the listing in the desktop client
+func listingLink(vendorID, slug string) htmltemplate.URL {
+	return htmltemplate.URL("ob://" + vendorID + "/store/" + slug)
+}
+
+// formatEmailAmount formats an amount in the currency's smallest unit
+func formatEmailAmount(amount uint64, code string) string {
+	def, err := repo.LoadCurrencyDefinitions().Lookup(code)
+	if err != nil || def.Divisibility == 0 {
+		return fmt.Sprintf("%d %s", amount, code)
+	}
+	var (
+		digits   = fmt.Sprintf("%0*d", def.Divisibility+1, amount)
+		split    = len(digits) - int(def.Divisibility)
+		fraction = strings.TrimRight(digits[split:], "0")
+	)
+	if fraction == "" {
+		return digits[:split] + " " + code
+	}
+	return digits[:split] + "." + fraction + " " + code
+}
+
+const (
+	// SMTPTLSModeSTARTTLS requires the connection to be upgraded with STARTTLS
+	SMTPTLSModeSTARTTLS = "starttls"
+	// SMTPTLSModeImplicit connects to the server over TLS
+	SMTPTLSModeImplicit = "tls"
+	// SMTPTLSModeNone never encrypts the connection
+	SMTPTLSModeNone = "none"
+)
+
+// Send email to the server, authenticating with PLAIN if the server
+// supports it. Unless the TLS mode is "tls" or "none" the connection must
+// be upgraded with STARTTLS, so credentials are never sent in plaintext
+// without the user opting in.
+func sendEmail(conf *repo.SMTPSettings, body []byte) error {
+	host, _, err := net.SplitHostPort(conf.ServerAddress)
+	if err != nil {
+		host = conf.ServerAddress
+	}
+	tlsConfig := &tls.Config{ServerName: host}
+
+	var c *smtp.Client
+	if conf.TLSMode == SMTPTLSModeImplicit {
+		conn, err := tls.Dial("tcp", conf.ServerAddress, tlsConfig)
+		if err != nil {
+			return err
+		}
+		if c, err = smtp.NewClient(conn, host); err != nil {
+			conn.Close()
+			return err
+		}
+	} else if c, err = smtp.Dial(conf.ServerAddress); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if conf.TLSMode != SMTPTLSModeImplicit && conf.TLSMode != SMTPTLSModeNone {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not support STARTTLS")
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if ok, _ := c.Extension("AUTH"); ok && conf.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", conf.Username, conf.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(conf.SenderEmail); err != nil {
+		return err
+	}
+	if err := c.Rcpt(conf.RecipientEmail); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
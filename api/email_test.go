@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bufio"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+// startTestSMTPServer accepts a single message on a local port, like the
+// smtpd server used by the qa tests, and sends it to the returned channel
+func startTestSMTPServer(t *testing.T) (string, chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan []byte, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost test server")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch strings.ToUpper(strings.SplitN(line, " ", 2)[0]) {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250-localhost")
+				tp.PrintfLine("250 8BITMIME")
+			case "DATA":
+				tp.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				messages <- data
+				tp.PrintfLine("250 OK")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), messages
+}
+
+func readEmailParts(t *testing.T, data []byte) (*mail.Message, map[string]string) {
+	msg, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("expected a multipart email, got %s", mediaType)
+	}
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		content, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+	return msg, parts
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, messages := startTestSMTPServer(t)
+	notifier := &smtpNotifier{settings: &repo.SMTPSettings{
+		Notifications:  true,
+		ServerAddress:  addr,
+		Username:       "usr",
+		Password:       "passwd",
+		SenderEmail:    "phore@test.org",
+		RecipientEmail: "user.phore@test.org",
+		// The test server doesn't offer STARTTLS
+		TLSMode: SMTPTLSModeNone,
+	}}
+	order := repo.OrderNotification{
+		OrderId:   "QmOrder",
+		Title:     "Ron Swanson <Tshirt>",
+		BuyerID:   "QmBuyer",
+		Thumbnail: repo.Thumbnail{Small: "QmThumbnail"},
+	}
+	if err := notifier.notify(order); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, parts := readEmailParts(t, <-messages)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "[Phore Marketplace] Order received" {
+		t.Errorf("unexpected subject %q", subject)
+	}
+	if !strings.Contains(parts["text/plain"], "Listing: Ron Swanson <Tshirt>") {
+		t.Errorf("expected the listing title in the text part, got %q", parts["text/plain"])
+	}
+	html := parts["text/html"]
+	if !strings.Contains(html, `<img src="`+defaultEmailImageGateway+`QmThumbnail"`) {
+		t.Errorf("expected the thumbnail in the HTML part, got %q", html)
+	}
+	if !strings.Contains(html, "Ron Swanson &lt;Tshirt&gt;") || strings.Contains(html, "<Tshirt>") {
+		t.Error("expected the listing title to be escaped in the HTML part")
+	}
+}
+
+func TestSendEmail_RequireSTARTTLS(t *testing.T) {
+	for _, mode := range []string{"", SMTPTLSModeSTARTTLS} {
+		addr, _ := startTestSMTPServer(t)
+		conf := &repo.SMTPSettings{ServerAddress: addr, TLSMode: mode}
+		if err := sendEmail(conf, []byte("test")); err == nil {
+			t.Errorf("expected a server without STARTTLS to be rejected with TLS mode %q", mode)
+		}
+	}
+}
+
+func TestEmailImageGateway(t *testing.T) {
+	for _, e := range []struct {
+		conf     *repo.SMTPSettings
+		expected string
+	}{
+		{nil, defaultEmailImageGateway},
+		{&repo.SMTPSettings{}, defaultEmailImageGateway},
+		{&repo.SMTPSettings{ImageGateway: "https://example.com/ob/images"}, "https://example.com/ob/images/"},
+	} {
+		if actual := emailImageGateway(e.conf); actual != e.expected {
+			t.Errorf("expected %q, got %q", e.expected, actual)
+		}
+	}
+}
+
+func TestRenderEmail_CustomTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "email_templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "order.txt"), []byte("New order {{.Order.OrderID}}"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	data := emailData{Type: repo.NotifierTypeOrderNewNotification, Title: "Order received", Order: &emailOrder{OrderID: "QmOrder"}}
+	text, html, err := renderEmail(dir, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "New order QmOrder" {
+		t.Errorf("expected the custom text template, got %q", text)
+	}
+	if !strings.Contains(html, "<h2>Order received</h2>") {
+		t.Errorf("expected the default HTML template, got %q", html)
+	}
+
+	data.Type = repo.NotifierTypePaymentNotification
+	if text, _, err = renderEmail(dir, data); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(text, "New order") {
+		t.Error("expected other types to use the default template")
+	}
+}
+
+func TestFormatEmailAmount(t *testing.T) {
+	for _, e := range []struct {
+		amount   uint64
+		code     string
+		expected string
+	}{
+		{150000000, "PHR", "1.5 PHR"},
+		{100000000, "TPHR", "1 TPHR"},
+		{1, "BTC", "0.00000001 BTC"},
+		{1999, "USD", "19.99 USD"},
+		{5, "XYZ", "5 XYZ"},
+	} {
+		if actual := formatEmailAmount(e.amount, e.code); actual != e.expected {
+			t.Errorf("expected %q, got %q", e.expected, actual)
+		}
+	}
+}
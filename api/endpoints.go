@@ -11,12 +11,18 @@ func put(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.PUTProfile(w, r)
 	case strings.HasPrefix(path, "/ob/settings"):
 		i.PUTSettings(w, r)
+	case strings.HasPrefix(path, "/ob/moderatoravailability"):
+		i.PUTModeratorAvailability(w, r)
+	case strings.HasPrefix(path, "/ob/vacation"):
+		i.PUTVacationMode(w, r)
 	case strings.HasPrefix(path, "/ob/moderator"):
 		i.PUTModerator(w, r)
 	case strings.HasPrefix(path, "/ob/listing"):
 		i.PUTListing(w, r)
 	case strings.HasPrefix(path, "/ob/post"):
 		i.PUTPost(w, r)
+	case strings.HasPrefix(path, "/ob/chatmessage/"):
+		blockingStartupMiddleware(i, w, r, i.PUTChatMessage)
 	default:
 		ErrorResponse(w, http.StatusNotFound, "Not Found")
 	}
@@ -55,20 +61,38 @@ func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request
 		blockingStartupMiddleware(i, w, r, i.POSTOrderComplete)
 	case strings.HasPrefix(path, "/ob/orderspend"):
 		blockingStartupMiddleware(i, w, r, i.POSTSpendCoinsForOrder)
+	case strings.HasPrefix(path, "/ob/orderinvoice"):
+		blockingStartupMiddleware(i, w, r, i.POSTPayOrderInvoice)
 	case strings.HasPrefix(path, "/ob/refund"):
 		blockingStartupMiddleware(i, w, r, i.POSTRefund)
 	case strings.HasPrefix(path, "/wallet/resyncblockchain"):
 		i.POSTResyncBlockchain(w, r)
 	case strings.HasPrefix(path, "/wallet/bumpfee"):
 		i.POSTBumpFee(w, r)
+	case strings.HasPrefix(path, "/wallet/signingrequests"):
+		i.POSTSigningRequest(w, r)
+	case strings.HasPrefix(path, "/wallet/labels"):
+		i.POSTWalletLabel(w, r)
 	case strings.HasPrefix(path, "/ob/opendispute"):
 		blockingStartupMiddleware(i, w, r, i.POSTOpenDispute)
 	case strings.HasPrefix(path, "/ob/closedispute"):
 		blockingStartupMiddleware(i, w, r, i.POSTCloseDispute)
+	case strings.HasPrefix(path, "/ob/casehandoverresponse"):
+		blockingStartupMiddleware(i, w, r, i.POSTCaseHandoverResponse)
+	case strings.HasPrefix(path, "/ob/casehandoverresolution"):
+		blockingStartupMiddleware(i, w, r, i.POSTCaseHandoverResolution)
+	case strings.HasPrefix(path, "/ob/casehandover"):
+		blockingStartupMiddleware(i, w, r, i.POSTCaseHandover)
 	case strings.HasPrefix(path, "/ob/releasefunds"):
 		blockingStartupMiddleware(i, w, r, i.POSTReleaseFunds)
 	case strings.HasPrefix(path, "/ob/releaseescrow"):
 		blockingStartupMiddleware(i, w, r, i.POSTReleaseEscrow)
+	case strings.HasPrefix(path, "/ob/chatreaction"):
+		blockingStartupMiddleware(i, w, r, i.POSTChatReaction)
+	case strings.HasPrefix(path, "/ob/chatgroupmembers"):
+		blockingStartupMiddleware(i, w, r, i.POSTChatGroupMembers)
+	case strings.HasPrefix(path, "/ob/chatgroup"):
+		blockingStartupMiddleware(i, w, r, i.POSTChatGroup)
 	case strings.HasPrefix(path, "/ob/chat"):
 		blockingStartupMiddleware(i, w, r, i.POSTChat)
 	case strings.HasPrefix(path, "/ob/signmessage"):
@@ -79,6 +103,8 @@ func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request
 		blockingStartupMiddleware(i, w, r, i.POSTGroupChat)
 	case strings.HasPrefix(path, "/ob/markchatasread"):
 		blockingStartupMiddleware(i, w, r, i.POSTMarkChatAsRead)
+	case strings.HasPrefix(path, "/ob/markchatgroupasread"):
+		i.POSTMarkChatGroupAsRead(w, r)
 	case strings.HasPrefix(path, "/ob/marknotificationasread"):
 		i.POSTMarkNotificationAsRead(w, r)
 	case strings.HasPrefix(path, "/ob/marknotificationsasread"):
@@ -109,6 +135,8 @@ func post(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request
 		i.POSTPurgeCache(w, r)
 	case strings.HasPrefix(path, "/ob/testemailnotifications"):
 		i.POSTTestEmailNotifications(w, r)
+	case strings.HasPrefix(path, "/ob/replaywebhook"):
+		i.POSTReplayWebhook(w, r)
 	case strings.HasPrefix(path, "/ob/post"):
 		i.POSTPost(w, r)
 	case strings.HasPrefix(path, "/ob/bulkupdatecurrency"):
@@ -135,10 +163,18 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETBalance(w, r)
 	case strings.HasPrefix(path, "/wallet/transactions"):
 		i.GETTransactions(w, r)
+	case strings.HasPrefix(path, "/wallet/history"):
+		i.GETWalletHistory(w, r)
+	case strings.HasPrefix(path, "/wallet/labels"):
+		i.GETWalletLabels(w, r)
 	case strings.HasPrefix(path, "/ob/settings"):
 		i.GETSettings(w, r)
 	case strings.HasPrefix(path, "/ob/closestpeers"):
 		i.GETClosestPeers(w, r)
+	case strings.HasPrefix(path, "/ob/exchangeratehistory"):
+		i.GETExchangeRateHistory(w, r)
+	case strings.HasPrefix(path, "/ob/orderexchangerates"):
+		i.GETOrderExchangeRates(w, r)
 	case strings.HasPrefix(path, "/ob/exchangerate"):
 		i.GETExchangeRate(w, r)
 	case strings.HasPrefix(path, "/ob/followers"):
@@ -161,12 +197,28 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETOrder(w, r)
 	case strings.HasPrefix(path, "/ob/moderators"):
 		i.GETModerators(w, r)
+	case strings.HasPrefix(path, "/ob/moderatoravailability"):
+		i.GETModeratorAvailability(w, r)
+	case strings.HasPrefix(path, "/ob/vacation"):
+		i.GETVacationMode(w, r)
+	case strings.HasPrefix(path, "/ob/chatattachment/"):
+		i.GETChatAttachment(w, r)
+	case strings.HasPrefix(path, "/ob/chatmessageedits"):
+		i.GETChatMessageEdits(w, r)
 	case strings.HasPrefix(path, "/ob/chatmessages"):
 		i.GETChatMessages(w, r)
 	case strings.HasPrefix(path, "/ob/chatconversations"):
 		i.GETChatConversations(w, r)
+	case strings.HasPrefix(path, "/ob/chatgroupmessages"):
+		i.GETChatGroupMessages(w, r)
+	case strings.HasPrefix(path, "/ob/chatgroups"):
+		i.GETChatGroups(w, r)
+	case strings.HasPrefix(path, "/ob/chatgroup/"):
+		i.GETChatGroup(w, r)
 	case strings.HasPrefix(path, "/ob/notifications"):
 		i.GETNotifications(w, r)
+	case strings.HasPrefix(path, "/ob/webhookdeliveries"):
+		i.GETWebhookDeliveries(w, r)
 	case strings.HasPrefix(path, "/ob/image"):
 		i.GETImage(w, r)
 	case strings.HasPrefix(path, "/ob/avatar"):
@@ -177,8 +229,16 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETPurchases(w, r)
 	case strings.HasPrefix(path, "/ob/sales"):
 		i.GETSales(w, r)
+	case strings.HasPrefix(path, "/ob/casehandovers"):
+		i.GETCaseHandovers(w, r)
+	case strings.HasPrefix(path, "/ob/casehandover"):
+		i.GETCaseHandover(w, r)
+	case strings.HasPrefix(path, "/ob/panelvotes"):
+		i.GETPanelVotes(w, r)
 	case strings.HasPrefix(path, "/ob/cases"):
 		i.GETCases(w, r)
+	case strings.HasPrefix(path, "/ob/case/") && strings.HasSuffix(path, "/export"):
+		i.GETCaseExport(w, r)
 	case strings.HasPrefix(path, "/ob/case"):
 		i.GETCase(w, r)
 	case strings.HasPrefix(path, "/wallet/estimatefee"):
@@ -193,10 +253,16 @@ func get(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Request)
 		i.GETHealthCheck(w, r)
 	case strings.HasPrefix(path, "/wallet/status"):
 		i.GETWalletStatus(w, r)
+	case strings.HasPrefix(path, "/wallet/signingrequests"):
+		i.GETSigningRequests(w, r)
 	case strings.HasPrefix(path, "/ob/ipns"):
 		i.GETIPNS(w, r)
 	case strings.HasPrefix(path, "/ob/peerinfo"):
 		i.GETPeerInfo(w, r)
+	case strings.HasPrefix(path, "/ob/postcomments"):
+		i.GETPostComments(w, r)
+	case strings.HasPrefix(path, "/ob/feed"):
+		i.GETFeed(w, r)
 	case strings.HasPrefix(path, "/ob/posts"):
 		i.GETPosts(w, r)
 	case strings.HasPrefix(path, "/ob/post"):
@@ -227,19 +293,25 @@ func deleter(i *jsonAPIHandler, path string, w http.ResponseWriter, r *http.Requ
 		i.DELETEChatMessage(w, r)
 	case strings.HasPrefix(path, "/ob/chatconversation"):
 		i.DELETEChatConversation(w, r)
+	case strings.HasPrefix(path, "/ob/chatgroup/"):
+		blockingStartupMiddleware(i, w, r, i.DELETEChatGroup)
+	case strings.HasPrefix(path, "/ob/chatreaction"):
+		blockingStartupMiddleware(i, w, r, i.DELETEChatReaction)
 	case strings.HasPrefix(path, "/ob/notifications"):
 		i.DELETENotification(w, r)
 	case strings.HasPrefix(path, "/ob/blocknode"):
 		i.DELETEBlockNode(w, r)
 	case strings.HasPrefix(path, "/ob/post"):
 		i.DELETEPost(w, r)
+	case strings.HasPrefix(path, "/wallet/labels"):
+		i.DELETEWalletLabel(w, r)
 	default:
 		ErrorResponse(w, http.StatusNotFound, "Not Found")
 	}
 }
 
 func gatewayAllowedPath(path, method string) bool {
-	allowedGets := []string{"/ob/followers", "/ob/following", "/ob/profile", "/ob/listing", "/ob/listings", "/ob/inventory", "/ob/image", "/ob/avatar", "/ob/header", "/ob/rating", "/ob/ratings", "/ob/posts", "/ob/post", "/ob/ipns"}
+	allowedGets := []string{"/ob/followers", "/ob/following", "/ob/profile", "/ob/listing", "/ob/listings", "/ob/inventory", "/ob/image", "/ob/avatar", "/ob/header", "/ob/rating", "/ob/ratings", "/ob/posts", "/ob/postcomments", "/ob/post", "/ob/ipns"}
 	allowedPosts := []string{"/ob/fetchprofiles", "/ob/fetchratings"}
 	if method == "GET" {
 		for _, p := range allowedGets {
@@ -5,12 +5,14 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"gx/ipfs/QmQmhotPUzVrMEWNK3x1R5jQ5ZHWyL7tVUrmRPjrBrvyCb/go-ipfs-files"
 	"gx/ipfs/QmXLwxifxwfc2bAwq6rdjbYqAsGzWsDE9RM5TWMGtykyj6/interface-go-ipfs-core"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -671,8 +673,8 @@ func (i *jsonAPIHandler) GETAddress(w http.ResponseWriter, r *http.Request) {
 	_, coinType := path.Split(r.URL.Path)
 	if coinType == "address" {
 		ret := make(map[string]interface{})
-		for ct, wal := range i.node.Multiwallet {
-			ret[ct.CurrencyCode()] = wal.CurrentAddress(wallet.EXTERNAL).String()
+		for _, wal := range i.node.Multiwallet {
+			ret[strings.ToUpper(wal.CurrencyCode())] = wal.CurrentAddress(wallet.EXTERNAL).String()
 		}
 		out, err := json.MarshalIndent(ret, "", "    ")
 		if err != nil {
@@ -709,10 +711,10 @@ func (i *jsonAPIHandler) GETBalance(w http.ResponseWriter, r *http.Request) {
 	}
 	if coinType == "balance" {
 		ret := make(map[string]interface{})
-		for ct, wal := range i.node.Multiwallet {
+		for _, wal := range i.node.Multiwallet {
 			height, _ := wal.ChainTip()
 			confirmed, unconfirmed := wal.Balance()
-			ret[ct.CurrencyCode()] = balance{Confirmed: confirmed, Unconfirmed: unconfirmed, Height: height}
+			ret[strings.ToUpper(wal.CurrencyCode())] = balance{Confirmed: confirmed, Unconfirmed: unconfirmed, Height: height}
 		}
 		out, err := json.MarshalIndent(ret, "", "    ")
 		if err != nil {
@@ -767,6 +769,30 @@ func (i *jsonAPIHandler) POSTSpendCoinsForOrder(w http.ResponseWriter, r *http.R
 	SanitizedResponse(w, string(ser))
 }
 
+// POSTPayOrderInvoice pays the vendor's invoice for a payment channel
+// purchase
+func (i *jsonAPIHandler) POSTPayOrderInvoice(w http.ResponseWriter, r *http.Request) {
+	type orderInvoice struct {
+		OrderID string `json:"orderId"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var inv orderInvoice
+	err := decoder.Decode(&inv)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.PayOrderInvoice(inv.OrderID)
+	if err == core.ErrOrderNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
 func (i *jsonAPIHandler) POSTSpendCoins(w http.ResponseWriter, r *http.Request) {
 	var spendArgs core.SpendRequest
 	decoder := json.NewDecoder(r.Body)
@@ -796,8 +822,8 @@ func (i *jsonAPIHandler) GETConfig(w http.ResponseWriter, r *http.Request) {
 		usingTor = true
 	}
 	var wallets []string
-	for coinType := range i.node.Multiwallet {
-		wallets = append(wallets, coinType.CurrencyCode())
+	for _, wal := range i.node.Multiwallet {
+		wallets = append(wallets, strings.ToUpper(wal.CurrencyCode()))
 	}
 	c := struct {
 		PeerId  string   `json:"peerID"`
@@ -830,6 +856,14 @@ func (i *jsonAPIHandler) POSTSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateWebhookSettings(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err = validateNotificationPreferences(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err = i.node.ValidateMultiwalletHasPreferredCurrencies(settings); err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -854,6 +888,9 @@ func (i *jsonAPIHandler) POSTSettings(w http.ResponseWriter, r *http.Request) {
 			blockedIds = append(blockedIds, id)
 		}
 		i.node.BanManager.SetBlockedIds(blockedIds)
+		if err := i.node.UpdateCommentIndex(); err != nil {
+			log.Error(err)
+		}
 	}
 	if settings.StoreModerators != nil {
 		modsToAdd, modsToDelete := extractModeratorChanges(*settings.StoreModerators, nil)
@@ -891,6 +928,14 @@ func (i *jsonAPIHandler) PUTSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateWebhookSettings(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err = validateNotificationPreferences(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err = i.node.ValidateMultiwalletHasPreferredCurrencies(settings); err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -910,6 +955,9 @@ func (i *jsonAPIHandler) PUTSettings(w http.ResponseWriter, r *http.Request) {
 			blockedIds = append(blockedIds, id)
 		}
 		i.node.BanManager.SetBlockedIds(blockedIds)
+		if err := i.node.UpdateCommentIndex(); err != nil {
+			log.Error(err)
+		}
 	}
 	if settings.StoreModerators != nil {
 		modsToAdd, modsToDelete := extractModeratorChanges(*settings.StoreModerators, currentSettings.StoreModerators)
@@ -967,6 +1015,14 @@ func (i *jsonAPIHandler) PATCHSettings(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if err = validateWebhookSettings(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err = validateNotificationPreferences(settings); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err = i.node.ValidateMultiwalletHasPreferredCurrencies(settings); err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
@@ -991,6 +1047,19 @@ func (i *jsonAPIHandler) PATCHSettings(w http.ResponseWriter, r *http.Request) {
 			blockedIds = append(blockedIds, id)
 		}
 		i.node.BanManager.SetBlockedIds(blockedIds)
+		if err := i.node.UpdateCommentIndex(); err != nil {
+			log.Error(err)
+		}
+	}
+	if settings.VacationMode != nil {
+		if err := i.node.SetVacationMode(*settings.VacationMode); err != nil {
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := i.node.SeedNode(); err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	}
 	err = i.node.Datastore.Settings().Update(settings)
 	if err != nil {
@@ -1030,8 +1099,18 @@ func (i *jsonAPIHandler) GETExchangeRate(w http.ResponseWriter, r *http.Request)
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	if currencyCode == "" || strings.ToLower(currencyCode) == "exchangerate" {
-		currencyMap, err := wal.ExchangeRates().GetAllRates(true)
+	var (
+		rates    = i.node.ExchangeRatesFor(wal)
+		at       = r.URL.Query().Get("at")
+		allRates = currencyCode == "" || strings.ToLower(currencyCode) == "exchangerate"
+	)
+	// A rate at a past time comes from the recorded history alone
+	if rates == nil && (allRates || at == "") {
+		ErrorResponse(w, http.StatusInternalServerError, core.ErrPriceCalculationRequiresExchangeRates.Error())
+		return
+	}
+	if allRates {
+		currencyMap, err := rates.GetAllRates(true)
 		if err != nil {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -1043,8 +1122,20 @@ func (i *jsonAPIHandler) GETExchangeRate(w http.ResponseWriter, r *http.Request)
 		}
 		SanitizedResponse(w, string(exchangeRateJSON))
 
+	} else if at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			ErrorResponse(w, http.StatusBadRequest, "at must be an RFC 3339 time")
+			return
+		}
+		rate, err := i.node.ExchangeRateAt(wal.CurrencyCode(), currencyCode, t)
+		if err != nil {
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+			return
+		}
+		fmt.Fprintf(w, `%.2f`, rate.Rate)
 	} else {
-		rate, err := wal.ExchangeRates().GetExchangeRate(core.NormalizeCurrencyCode(currencyCode))
+		rate, err := rates.GetExchangeRate(core.NormalizeCurrencyCode(currencyCode))
 		if err != nil {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
@@ -1053,6 +1144,69 @@ func (i *jsonAPIHandler) GETExchangeRate(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// GET the recorded exchange rates of a coin in a currency between two times,
+// the last day by default
+func (i *jsonAPIHandler) GETExchangeRateHistory(w http.ResponseWriter, r *http.Request) {
+	s := strings.Split(strings.TrimSuffix(r.URL.Path, "/"), "/")
+	if len(s) != 5 {
+		ErrorResponse(w, http.StatusBadRequest, "a coin and a currency are required")
+		return
+	}
+	wal, err := i.node.Multiwallet.WalletForCurrencyCode(s[3])
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "Unknown wallet type")
+		return
+	}
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	for _, t := range []struct {
+		param string
+		value *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if v := r.URL.Query().Get(t.param); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s must be an RFC 3339 time", t.param))
+				return
+			}
+			*t.value = parsed
+		}
+	}
+	rates, err := i.node.Datastore.ExchangeRates().GetRange(wal.CurrencyCode(), strings.ToUpper(s[4]), from, to)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if rates == nil {
+		rates = []repo.ExchangeRate{}
+	}
+	ret, err := json.MarshalIndent(rates, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// GET the exchange rates an order was priced at
+func (i *jsonAPIHandler) GETOrderExchangeRates(w http.ResponseWriter, r *http.Request) {
+	_, orderID := path.Split(r.URL.Path)
+	rates, err := i.node.Datastore.ExchangeRates().GetOrderRates(orderID)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if rates == nil {
+		rates = []repo.ExchangeRate{}
+	}
+	ret, err := json.MarshalIndent(rates, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
 func (i *jsonAPIHandler) GETFollowers(w http.ResponseWriter, r *http.Request) {
 	_, peerID := path.Split(r.URL.Path)
 	useCache, _ := strconv.ParseBool(r.URL.Query().Get("usecache"))
@@ -1329,6 +1483,94 @@ func (i *jsonAPIHandler) DELETEModerator(w http.ResponseWriter, r *http.Request)
 	SanitizedResponse(w, "{}")
 }
 
+func (i *jsonAPIHandler) PUTModeratorAvailability(w http.ResponseWriter, r *http.Request) {
+	availability := new(pb.ModeratorAvailability)
+	err := jsonpb.Unmarshal(r.Body, availability)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !i.node.IsModerator() {
+		ErrorResponse(w, http.StatusConflict, "Only moderators can set an availability status")
+		return
+	}
+	if err := i.node.SetModeratorAvailability(availability); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Republish to IPNS
+	if err := i.node.SeedNode(); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "IPNS Error: "+err.Error())
+		return
+	}
+	SanitizedResponse(w, "{}")
+}
+
+func (i *jsonAPIHandler) PUTVacationMode(w http.ResponseWriter, r *http.Request) {
+	var vacation repo.VacationMode
+	if err := json.NewDecoder(r.Body).Decode(&vacation); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := i.node.SetVacationMode(vacation); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Republish to IPNS
+	if err := i.node.SeedNode(); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, "IPNS Error: "+err.Error())
+		return
+	}
+	SanitizedResponse(w, "{}")
+}
+
+func (i *jsonAPIHandler) GETVacationMode(w http.ResponseWriter, r *http.Request) {
+	vacation := i.node.GetVacationMode()
+	ret, err := json.MarshalIndent(struct {
+		repo.VacationMode
+		Active bool `json:"active"`
+	}{vacation, vacation.IsActive(time.Now())}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETModeratorAvailability(w http.ResponseWriter, r *http.Request) {
+	_, peerID := path.Split(r.URL.Path)
+	var (
+		signed *pb.SignedModeratorAvailability
+		err    error
+	)
+	if peerID == "" || strings.ToLower(peerID) == "moderatoravailability" || peerID == i.node.IPFSIdentityString() {
+		signed, err = i.node.GetModeratorAvailability()
+	} else {
+		signed, err = i.node.FetchModeratorAvailability(peerID)
+	}
+	if err == core.ErrModeratorAvailabilityNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(signed)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponseM(w, out, new(pb.SignedModeratorAvailability))
+}
+
 func (i *jsonAPIHandler) GETListings(w http.ResponseWriter, r *http.Request) {
 	_, peerID := path.Split(r.URL.Path)
 	useCache, _ := strconv.ParseBool(r.URL.Query().Get("usecache"))
@@ -1355,6 +1597,9 @@ func (i *jsonAPIHandler) GETListings(w http.ResponseWriter, r *http.Request) {
 			ErrorResponse(w, http.StatusNotFound, err.Error())
 			return
 		}
+		if refreshed, err := core.RefreshListingAvailability(listingsBytes, time.Now()); err == nil {
+			listingsBytes = refreshed
+		}
 		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%s, immutable", maxAge))
 		SanitizedResponse(w, string(listingsBytes))
 	}
@@ -2164,6 +2409,211 @@ func (i *jsonAPIHandler) GETCase(w http.ResponseWriter, r *http.Request) {
 	SanitizedResponseM(w, out, new(pb.CaseRespApi))
 }
 
+func (i *jsonAPIHandler) GETCaseExport(w http.ResponseWriter, r *http.Request) {
+	orderID := path.Base(path.Dir(r.URL.Path))
+	bundle, err := i.node.ExportCase(orderID)
+	if err == core.ErrCaseNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="case-%s.zip"`, orderID))
+	w.Write(bundle)
+}
+
+type caseHandoverResponse struct {
+	OrderID         string          `json:"orderId"`
+	Moderator       string          `json:"moderator"`
+	BackupModerator string          `json:"backupModerator"`
+	BuyerID         string          `json:"buyerId"`
+	VendorID        string          `json:"vendorId"`
+	BuyerAccepted   bool            `json:"buyerAccepted"`
+	VendorAccepted  bool            `json:"vendorAccepted"`
+	Rejected        bool            `json:"rejected"`
+	TransferTxid    string          `json:"transferTxid,omitempty"`
+	Timestamp       time.Time       `json:"timestamp"`
+	Handover        json.RawMessage `json:"handover,omitempty"`
+}
+
+func newCaseHandoverResponse(record *repo.CaseHandoverRecord) caseHandoverResponse {
+	return caseHandoverResponse{
+		OrderID:         record.OrderID,
+		Moderator:       record.Moderator,
+		BackupModerator: record.BackupModerator,
+		BuyerID:         record.BuyerID,
+		VendorID:        record.VendorID,
+		BuyerAccepted:   record.BuyerAccepted,
+		VendorAccepted:  record.VendorAccepted,
+		Rejected:        record.IsRejected(),
+		TransferTxid:    record.TransferTxid,
+		Timestamp:       record.Timestamp,
+	}
+}
+
+func (i *jsonAPIHandler) POSTCaseHandover(w http.ResponseWriter, r *http.Request) {
+	type handoverParams struct {
+		OrderID         string `json:"orderId"`
+		BackupModerator string `json:"backupModerator"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var params handoverParams
+	err := decoder.Decode(&params)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.RequestCaseHandover(params.OrderID, params.BackupModerator)
+	if err != nil {
+		switch err {
+		case core.ErrCaseNotFound:
+			ErrorResponse(w, http.StatusNotFound, err.Error())
+		case core.ErrCloseFailureCaseExpired:
+			ErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) POSTCaseHandoverResponse(w http.ResponseWriter, r *http.Request) {
+	type responseParams struct {
+		OrderID string `json:"orderId"`
+		Accept  bool   `json:"accept"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var params responseParams
+	err := decoder.Decode(&params)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.RespondToCaseHandover(params.OrderID, params.Accept)
+	if err == core.ErrCaseHandoverNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) POSTCaseHandoverResolution(w http.ResponseWriter, r *http.Request) {
+	type resolutionParams struct {
+		OrderID          string  `json:"orderId"`
+		Resolution       string  `json:"resolution"`
+		BuyerPercentage  float32 `json:"buyerPercentage"`
+		VendorPercentage float32 `json:"vendorPercentage"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var params resolutionParams
+	err := decoder.Decode(&params)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	err = i.node.ResolveCaseHandover(params.OrderID, params.BuyerPercentage, params.VendorPercentage, params.Resolution)
+	if err == core.ErrCaseHandoverNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETCaseHandovers(w http.ResponseWriter, r *http.Request) {
+	records, err := i.node.Datastore.CaseHandovers().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret := make([]caseHandoverResponse, 0, len(records))
+	for _, record := range records {
+		ret = append(ret, newCaseHandoverResponse(record))
+	}
+	out, err := json.MarshalIndent(ret, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(out))
+}
+
+func (i *jsonAPIHandler) GETPanelVotes(w http.ResponseWriter, r *http.Request) {
+	_, orderID := path.Split(r.URL.Path)
+	votes, err := i.node.GetPanelVotes(orderID)
+	if err == core.ErrCaseNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	ret := make([]json.RawMessage, 0, len(votes))
+	for _, vote := range votes {
+		out, err := m.MarshalToString(vote)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret = append(ret, json.RawMessage(out))
+	}
+	out, err := json.MarshalIndent(ret, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(out))
+}
+
+func (i *jsonAPIHandler) GETCaseHandover(w http.ResponseWriter, r *http.Request) {
+	_, orderID := path.Split(r.URL.Path)
+	record, err := i.node.Datastore.CaseHandovers().Get(orderID)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, core.ErrCaseHandoverNotFound.Error())
+		return
+	}
+	ret := newCaseHandoverResponse(record)
+	if record.SignedHandover != nil {
+		handover := new(pb.CaseHandover)
+		if err := proto.Unmarshal(record.SignedHandover.SerializedData, handover); err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		m := jsonpb.Marshaler{
+			EnumsAsInts:  false,
+			EmitDefaults: true,
+			Indent:       "    ",
+			OrigName:     false,
+		}
+		handoverJSON, err := m.MarshalToString(handover)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		ret.Handover = json.RawMessage(handoverJSON)
+	}
+	out, err := json.MarshalIndent(ret, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(out))
+}
+
 func (i *jsonAPIHandler) POSTReleaseFunds(w http.ResponseWriter, r *http.Request) {
 	type release struct {
 		OrderID string `json:"orderId"`
@@ -2376,12 +2826,21 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var flag pb.Chat_Flag
-	if chat.Message == "" {
+	if chat.Message == "" && len(chat.Attachments) == 0 {
 		flag = pb.Chat_TYPING
 	} else {
 		flag = pb.Chat_MESSAGE
 	}
-	h := sha256.Sum256([]byte(chat.Message + chat.Subject + ptypes.TimestampString(ts)))
+	attachments, err := i.node.PrepareChatAttachments(chat.PeerId, chat.Attachments)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	preimage := chat.Message + chat.Subject + ptypes.TimestampString(ts)
+	for _, a := range attachments {
+		preimage += a.Hash
+	}
+	h := sha256.Sum256([]byte(preimage))
 	encoded, err := mh.Encode(h[:], mh.SHA2_256)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -2394,11 +2853,12 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	chatPb := &pb.Chat{
-		MessageId: msgID.B58String(),
-		Subject:   chat.Subject,
-		Message:   chat.Message,
-		Timestamp: ts,
-		Flag:      flag,
+		MessageId:   msgID.B58String(),
+		Subject:     chat.Subject,
+		Message:     chat.Message,
+		Timestamp:   ts,
+		Flag:        flag,
+		Attachments: attachments,
 	}
 	err = i.node.SendChat(chat.PeerId, chatPb)
 	if err != nil {
@@ -2412,6 +2872,13 @@ func (i *jsonAPIHandler) POSTChat(w http.ResponseWriter, r *http.Request) {
 			ErrorResponse(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		if len(chat.Attachments) > 0 {
+			err = i.node.Datastore.Chat().PutAttachments(msgID.B58String(), chat.Attachments)
+			if err != nil {
+				ErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
 	}
 	SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msgID.B58String()))
 }
@@ -2428,8 +2895,8 @@ func (i *jsonAPIHandler) POSTGroupChat(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusBadRequest, "Subject line is too long")
 		return
 	}
-	if len(chat.Subject) <= 0 {
-		ErrorResponse(w, http.StatusBadRequest, "Group chats must include a unique subject to be used as the group chat ID")
+	if len(chat.Subject) <= 0 && chat.GroupId == "" {
+		ErrorResponse(w, http.StatusBadRequest, "Group chats must include a group ID or a unique subject to be used as the group chat ID")
 		return
 	}
 	if len(chat.Message) > 20000 {
@@ -2449,7 +2916,7 @@ func (i *jsonAPIHandler) POSTGroupChat(w http.ResponseWriter, r *http.Request) {
 	} else {
 		flag = pb.Chat_MESSAGE
 	}
-	h := sha256.Sum256([]byte(chat.Message + chat.Subject + ptypes.TimestampString(ts)))
+	h := sha256.Sum256([]byte(chat.Message + chat.GroupId + chat.Subject + ptypes.TimestampString(ts)))
 	encoded, err := mh.Encode(h[:], mh.SHA2_256)
 	if err != nil {
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -2468,6 +2935,22 @@ func (i *jsonAPIHandler) POSTGroupChat(w http.ResponseWriter, r *http.Request) {
 		Timestamp: ts,
 		Flag:      flag,
 	}
+	if chat.GroupId != "" {
+		err = i.node.SendGroupChat(chat.GroupId, chatPb)
+		if err != nil {
+			chatGroupErrorResponse(w, err)
+			return
+		}
+		if chatPb.Flag == pb.Chat_MESSAGE {
+			err = i.node.Datastore.Chat().PutGroupMessage(msgID.B58String(), chat.GroupId, i.node.IpfsNode.Identity.Pretty(), chat.Message, t, true, true)
+			if err != nil {
+				ErrorResponse(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+		SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msgID.B58String()))
+		return
+	}
 	for _, pid := range chat.PeerIds {
 		err = i.node.SendChat(pid, chatPb)
 		if err != nil {
@@ -2486,6 +2969,156 @@ func (i *jsonAPIHandler) POSTGroupChat(w http.ResponseWriter, r *http.Request) {
 	SanitizedResponse(w, fmt.Sprintf(`{"messageId": "%s"}`, msgID.B58String()))
 }
 
+func (i *jsonAPIHandler) POSTChatGroup(w http.ResponseWriter, r *http.Request) {
+	type createGroup struct {
+		Name    string   `json:"name"`
+		PeerIds []string `json:"peerIds"`
+	}
+	decoder := json.NewDecoder(r.Body)
+	var req createGroup
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	group, err := i.node.CreateChatGroup(req.Name, req.PeerIds)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTChatGroupMembers(w http.ResponseWriter, r *http.Request) {
+	type updateMembers struct {
+		Add    []string `json:"add"`
+		Remove []string `json:"remove"`
+	}
+	_, groupID := path.Split(r.URL.Path)
+	decoder := json.NewDecoder(r.Body)
+	var req updateMembers
+	err := decoder.Decode(&req)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		ErrorResponse(w, http.StatusBadRequest, "no members to add or remove")
+		return
+	}
+	var group *repo.ChatGroup
+	if len(req.Remove) > 0 {
+		group, err = i.node.RemoveChatGroupMembers(groupID, req.Remove)
+		if err != nil {
+			chatGroupErrorResponse(w, err)
+			return
+		}
+	}
+	if len(req.Add) > 0 {
+		group, err = i.node.AddChatGroupMembers(groupID, req.Add)
+		if err != nil {
+			chatGroupErrorResponse(w, err)
+			return
+		}
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETChatGroups(w http.ResponseWriter, r *http.Request) {
+	conversations := i.node.Datastore.Chat().GetGroupConversations()
+	ret, err := json.MarshalIndent(conversations, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isNullJSON(ret) {
+		ret = []byte("[]")
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETChatGroup(w http.ResponseWriter, r *http.Request) {
+	_, groupID := path.Split(r.URL.Path)
+	group, err := i.node.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, core.ErrChatGroupNotFound.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(group, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETChatGroupMessages(w http.ResponseWriter, r *http.Request) {
+	_, groupID := path.Split(r.URL.Path)
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	messages := i.node.Datastore.Chat().GetGroupMessages(groupID, r.URL.Query().Get("offsetId"), l)
+	ret, err := json.MarshalIndent(messages, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isNullJSON(ret) {
+		ret = []byte("[]")
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTMarkChatGroupAsRead(w http.ResponseWriter, r *http.Request) {
+	_, groupID := path.Split(r.URL.Path)
+	if _, err := i.node.Datastore.Chat().MarkGroupAsRead(groupID); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+// DELETEChatGroup leaves a group conversation and deletes its history
+func (i *jsonAPIHandler) DELETEChatGroup(w http.ResponseWriter, r *http.Request) {
+	_, groupID := path.Split(r.URL.Path)
+	if err := i.node.LeaveChatGroup(groupID); err != nil {
+		chatGroupErrorResponse(w, err)
+		return
+	}
+	if err := i.node.Datastore.Chat().DeleteGroup(groupID); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func chatGroupErrorResponse(w http.ResponseWriter, err error) {
+	switch err {
+	case core.ErrChatGroupNotFound:
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+	case core.ErrNotChatGroupCreator, core.ErrChatGroupInactive:
+		ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+	}
+}
+
 func (i *jsonAPIHandler) GETChatMessages(w http.ResponseWriter, r *http.Request) {
 	_, peerID := path.Split(r.URL.Path)
 	if strings.ToLower(peerID) == "chatmessages" {
@@ -2503,20 +3136,146 @@ func (i *jsonAPIHandler) GETChatMessages(w http.ResponseWriter, r *http.Request)
 	offsetID := r.URL.Query().Get("offsetId")
 	messages := i.node.Datastore.Chat().GetMessages(peerID, r.URL.Query().Get("subject"), offsetID, l)
 
-	ret, err := json.MarshalIndent(messages, "", "    ")
+	ret, err := json.MarshalIndent(messages, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isNullJSON(ret) {
+		ret = []byte("[]")
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// chatAttachmentImageTypes are the attachment media types served as they
+// were sent. Any other attachment is served as plain bytes so a browser
+// won't render or run it.
+var chatAttachmentImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+func (i *jsonAPIHandler) GETChatAttachment(w http.ResponseWriter, r *http.Request) {
+	urlPath, idx := path.Split(r.URL.Path)
+	_, messageID := path.Split(strings.TrimSuffix(urlPath, "/"))
+	index, err := strconv.Atoi(idx)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, "invalid attachment index")
+		return
+	}
+	attachment, err := i.node.FetchChatAttachment(messageID, index)
+	if err == core.ErrChatAttachmentNotFound {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	contentType := "application/octet-stream"
+	if mediaType, _, err := mime.ParseMediaType(attachment.MediaType); err == nil && chatAttachmentImageTypes[mediaType] {
+		contentType = mediaType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	http.ServeContent(w, r, attachment.Filename, time.Now(), bytes.NewReader(attachment.Data))
+}
+
+func (i *jsonAPIHandler) GETChatConversations(w http.ResponseWriter, r *http.Request) {
+	conversations := i.node.Datastore.Chat().GetConversations()
+	ret, err := json.MarshalIndent(conversations, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isNullJSON(ret) {
+		ret = []byte("[]")
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTMarkChatAsRead(w http.ResponseWriter, r *http.Request) {
+	_, peerID := path.Split(r.URL.Path)
+	if strings.ToLower(peerID) == "markchatasread" {
+		peerID = ""
+	}
+	subject := r.URL.Query().Get("subject")
+	lastID, updated, err := i.node.Datastore.Chat().MarkAsRead(peerID, subject, false, "")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if updated && peerID != "" {
+		chatPb := &pb.Chat{
+			MessageId: lastID,
+			Subject:   subject,
+			Flag:      pb.Chat_READ,
+		}
+		err = i.node.SendChat(peerID, chatPb)
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if subject != "" {
+		go func() {
+			i.node.Datastore.Purchases().MarkAsRead(subject)
+			i.node.Datastore.Sales().MarkAsRead(subject)
+			i.node.Datastore.Cases().MarkAsRead(subject)
+		}()
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+// DELETEChatMessage deletes a message from our database. With retract=true
+// one of our own messages is deleted for the other side as well.
+func (i *jsonAPIHandler) DELETEChatMessage(w http.ResponseWriter, r *http.Request) {
+	_, messageID := path.Split(r.URL.Path)
+	if retract, _ := strconv.ParseBool(r.URL.Query().Get("retract")); retract {
+		if err := i.node.RetractChatMessage(messageID); err != nil {
+			chatChangeErrorResponse(w, err)
+			return
+		}
+		SanitizedResponse(w, `{}`)
+		return
+	}
+	err := i.node.Datastore.Chat().DeleteMessage(messageID)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) PUTChatMessage(w http.ResponseWriter, r *http.Request) {
+	type editMessage struct {
+		Message string `json:"message"`
+	}
+	_, messageID := path.Split(r.URL.Path)
+	decoder := json.NewDecoder(r.Body)
+	var edit editMessage
+	err := decoder.Decode(&edit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := i.node.EditChatMessage(messageID, edit.Message); err != nil {
+		chatChangeErrorResponse(w, err)
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+func (i *jsonAPIHandler) GETChatMessageEdits(w http.ResponseWriter, r *http.Request) {
+	_, messageID := path.Split(r.URL.Path)
+	edits, err := i.node.Datastore.Chat().GetEdits(messageID)
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	if isNullJSON(ret) {
-		ret = []byte("[]")
-	}
-	SanitizedResponse(w, string(ret))
-}
-
-func (i *jsonAPIHandler) GETChatConversations(w http.ResponseWriter, r *http.Request) {
-	conversations := i.node.Datastore.Chat().GetConversations()
-	ret, err := json.MarshalIndent(conversations, "", "    ")
+	ret, err := json.MarshalIndent(edits, "", "    ")
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
@@ -2527,49 +3286,45 @@ func (i *jsonAPIHandler) GETChatConversations(w http.ResponseWriter, r *http.Req
 	SanitizedResponse(w, string(ret))
 }
 
-func (i *jsonAPIHandler) POSTMarkChatAsRead(w http.ResponseWriter, r *http.Request) {
-	_, peerID := path.Split(r.URL.Path)
-	if strings.ToLower(peerID) == "markchatasread" {
-		peerID = ""
+func (i *jsonAPIHandler) POSTChatReaction(w http.ResponseWriter, r *http.Request) {
+	type reaction struct {
+		Reaction string `json:"reaction"`
 	}
-	subject := r.URL.Query().Get("subject")
-	lastID, updated, err := i.node.Datastore.Chat().MarkAsRead(peerID, subject, false, "")
+	_, messageID := path.Split(r.URL.Path)
+	decoder := json.NewDecoder(r.Body)
+	var react reaction
+	err := decoder.Decode(&react)
 	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	if updated && peerID != "" {
-		chatPb := &pb.Chat{
-			MessageId: lastID,
-			Subject:   subject,
-			Flag:      pb.Chat_READ,
-		}
-		err = i.node.SendChat(peerID, chatPb)
-		if err != nil {
-			ErrorResponse(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-	}
-	if subject != "" {
-		go func() {
-			i.node.Datastore.Purchases().MarkAsRead(subject)
-			i.node.Datastore.Sales().MarkAsRead(subject)
-			i.node.Datastore.Cases().MarkAsRead(subject)
-		}()
+	if err := i.node.ReactToChatMessage(messageID, react.Reaction, false); err != nil {
+		chatChangeErrorResponse(w, err)
+		return
 	}
 	SanitizedResponse(w, `{}`)
 }
 
-func (i *jsonAPIHandler) DELETEChatMessage(w http.ResponseWriter, r *http.Request) {
+func (i *jsonAPIHandler) DELETEChatReaction(w http.ResponseWriter, r *http.Request) {
 	_, messageID := path.Split(r.URL.Path)
-	err := i.node.Datastore.Chat().DeleteMessage(messageID)
-	if err != nil {
-		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+	if err := i.node.ReactToChatMessage(messageID, r.URL.Query().Get("reaction"), true); err != nil {
+		chatChangeErrorResponse(w, err)
 		return
 	}
 	SanitizedResponse(w, `{}`)
 }
 
+func chatChangeErrorResponse(w http.ResponseWriter, err error) {
+	switch err {
+	case core.ErrChatMessageNotFound:
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+	case core.ErrNotChatMessageAuthor:
+		ErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		chatGroupErrorResponse(w, err)
+	}
+}
+
 func (i *jsonAPIHandler) DELETEChatConversation(w http.ResponseWriter, r *http.Request) {
 	_, peerID := path.Split(r.URL.Path)
 	err := i.node.Datastore.Chat().DeleteConversation(peerID)
@@ -2876,6 +3631,8 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 		OrderID       string    `json:"orderId"`
 		Thumbnail     string    `json:"thumbnail"`
 		CanBumpFee    bool      `json:"canBumpFee"`
+		Category      string    `json:"category"`
+		Labels        []string  `json:"labels"`
 	}
 	wal, err := i.node.Multiwallet.WalletForCurrencyCode(coinType)
 	if err != nil {
@@ -2892,6 +3649,11 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	labels, err := i.node.Datastore.TxLabels().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	var txs []Tx
 	passedOffset := false
 	for i := len(transactions) - 1; i >= 0; i-- {
@@ -2914,6 +3676,10 @@ func (i *jsonAPIHandler) GETTransactions(w http.ResponseWriter, r *http.Request)
 			tx.Thumbnail = m.Thumbnail
 			tx.CanBumpFee = m.CanBumpFee
 		}
+		if label, ok := labels[t.Txid]; ok {
+			tx.Category = label.Category
+			tx.Labels = label.Labels
+		}
 		if t.Status == wallet.StatusDead {
 			tx.CanBumpFee = false
 		}
@@ -3182,6 +3948,9 @@ func (i *jsonAPIHandler) POSTBlockNode(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	i.node.BanManager.AddBlockedId(pid)
+	if err := i.node.UpdateCommentIndex(); err != nil {
+		log.Error(err)
+	}
 	SanitizedResponse(w, `{}`)
 }
 
@@ -3211,6 +3980,9 @@ func (i *jsonAPIHandler) DELETEBlockNode(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	i.node.BanManager.RemoveBlockedId(pid)
+	if err := i.node.UpdateCommentIndex(); err != nil {
+		log.Error(err)
+	}
 	SanitizedResponse(w, `{}`)
 }
 
@@ -3353,11 +4125,11 @@ func (i *jsonAPIHandler) GETFees(w http.ResponseWriter, r *http.Request) {
 	}
 	if coinType == "fees" {
 		ret := make(map[string]interface{})
-		for ct, wal := range i.node.Multiwallet {
+		for _, wal := range i.node.Multiwallet {
 			priority := wal.GetFeePerByte(wallet.PRIOIRTY)
 			normal := wal.GetFeePerByte(wallet.NORMAL)
 			economic := wal.GetFeePerByte(wallet.ECONOMIC)
-			ret[ct.CurrencyCode()] = fees{Priority: priority, Normal: normal, Economic: economic}
+			ret[strings.ToUpper(wal.CurrencyCode())] = fees{Priority: priority, Normal: normal, Economic: economic}
 		}
 		out, err := json.MarshalIndent(ret, "", "    ")
 		if err != nil {
@@ -3728,9 +4500,9 @@ func (i *jsonAPIHandler) GETWalletStatus(w http.ResponseWriter, r *http.Request)
 	}
 	if coinType == "status" {
 		ret := make(map[string]interface{})
-		for ct, wal := range i.node.Multiwallet {
+		for _, wal := range i.node.Multiwallet {
 			height, hash := wal.ChainTip()
-			ret[ct.CurrencyCode()] = status{height, hash.String()}
+			ret[strings.ToUpper(wal.CurrencyCode())] = status{height, hash.String()}
 		}
 		out, err := json.MarshalIndent(ret, "", "    ")
 		if err != nil {
@@ -3810,7 +4582,7 @@ func (i *jsonAPIHandler) POSTTestEmailNotifications(w http.ResponseWriter, r *ht
 		ErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	notifier := smtpNotifier{&settings}
+	notifier := smtpNotifier{settings: &settings, node: i.node}
 	err = notifier.notify(repo.TestNotification{})
 	if err != nil {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -3820,6 +4592,84 @@ func (i *jsonAPIHandler) POSTTestEmailNotifications(w http.ResponseWriter, r *ht
 	SanitizedResponse(w, "{}")
 }
 
+func (i *jsonAPIHandler) GETWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	deliveries, err := i.node.Datastore.WebhookDeliveries().GetAll(r.URL.Query().Get("status"), r.URL.Query().Get("offsetId"), l)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if deliveries == nil {
+		deliveries = []repo.WebhookDelivery{}
+	}
+	ret, err := json.MarshalIndent(deliveries, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) GETFeed(w http.ResponseWriter, r *http.Request) {
+	limit := r.URL.Query().Get("limit")
+	if limit == "" {
+		limit = "-1"
+	}
+	l, err := strconv.Atoi(limit)
+	if err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	entryType := r.URL.Query().Get("type")
+	if entryType != "" && entryType != repo.FeedEntryTypePost && entryType != repo.FeedEntryTypeListing {
+		ErrorResponse(w, http.StatusBadRequest, "type must be post or listing")
+		return
+	}
+	entries, err := i.node.GetFeed(entryType, r.URL.Query().Get("offsetId"), l)
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(entries, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+func (i *jsonAPIHandler) POSTReplayWebhook(w http.ResponseWriter, r *http.Request) {
+	_, deliveryID := path.Split(r.URL.Path)
+	settings, err := i.node.Datastore.Settings().Get()
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, errWebhookNotConfigured.Error())
+		return
+	}
+	delivery, err := replayWebhookDelivery(settings, i.node.Datastore.WebhookDeliveries(), deliveryID)
+	switch {
+	case err == errWebhookDeliveryNotFound || err == errWebhookNotConfigured:
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(delivery, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
 func (i *jsonAPIHandler) GETPeerInfo(w http.ResponseWriter, r *http.Request) {
 	_, idb58 := path.Split(r.URL.Path)
 	pid, err := peer.IDB58Decode(idb58)
@@ -3940,6 +4790,10 @@ func (i *jsonAPIHandler) POSTPost(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// Let the author of the post a comment or repost references know about it
+	if err := i.node.SendPostCommentNotice(signedPost); err != nil {
+		log.Errorf("sending notice of post %s: %s", signedPost.Post.Slug, err)
+	}
 	// Update followers/following
 	err = i.node.UpdateFollow()
 	if err != nil {
@@ -4006,6 +4860,10 @@ func (i *jsonAPIHandler) PUTPost(w http.ResponseWriter, r *http.Request) {
 		ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// Let the author of the post a comment or repost references know about it
+	if err := i.node.SendPostCommentNotice(signedPost); err != nil {
+		log.Errorf("sending notice of post %s: %s", signedPost.Post.Slug, err)
+	}
 
 	// Update followers/following
 	err = i.node.UpdateFollow()
@@ -4150,3 +5008,247 @@ func (i *jsonAPIHandler) GETPost(w http.ResponseWriter, r *http.Request) {
 	}
 	SanitizedResponseM(w, out, new(pb.SignedPost))
 }
+
+// GET the threaded comments on a post (self or peer)
+func (i *jsonAPIHandler) GETPostComments(w http.ResponseWriter, r *http.Request) {
+	urlPath, slug := path.Split(r.URL.Path)
+	_, peerID := path.Split(urlPath[:len(urlPath)-1])
+	useCache, _ := strconv.ParseBool(r.URL.Query().Get("usecache"))
+	if peerID == "" || strings.ToLower(peerID) == "postcomments" || peerID == i.node.IPFSIdentityString() {
+		comments, err := i.node.GetPostComments(slug)
+		if err == core.ErrPostCommentsNotFound {
+			writeEmptyPostComments(w, slug)
+			return
+		} else if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		SanitizedResponse(w, string(comments))
+		return
+	}
+
+	index, err := ipfs.ResolveThenCat(i.node.IpfsNode, ipnspath.FromString(path.Join(peerID, "comments.json")), time.Minute, i.node.IPNSQuorumSize, useCache)
+	if err != nil {
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+	comments, err := core.FindPostComments(index, slug)
+	if err == core.ErrPostCommentsNotFound {
+		writeEmptyPostComments(w, slug)
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(comments))
+}
+
+// writeEmptyPostComments responds with the comment thread of a post nobody
+// has commented on
+func writeEmptyPostComments(w http.ResponseWriter, slug string) {
+	ret, err := json.MarshalIndent(struct {
+		Slug     string        `json:"slug"`
+		Count    int           `json:"count"`
+		Comments []interface{} `json:"comments"`
+	}{slug, 0, []interface{}{}}, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+type signingRequestResponse struct {
+	repo.SigningRequest
+	PSBT string `json:"psbt"`
+}
+
+func newSigningRequestResponse(request repo.SigningRequest) signingRequestResponse {
+	return signingRequestResponse{request, base64.StdEncoding.EncodeToString(request.PSBT)}
+}
+
+// GET the transactions exported for signing outside the node, or one of them
+// by its ID
+func (i *jsonAPIHandler) GETSigningRequests(w http.ResponseWriter, r *http.Request) {
+	_, requestID := path.Split(r.URL.Path)
+	if requestID != "" && requestID != "signingrequests" {
+		request, err := i.node.Datastore.SigningRequests().Get(requestID)
+		if err != nil {
+			ErrorResponse(w, http.StatusNotFound, core.ErrSigningRequestNotFound.Error())
+			return
+		}
+		ret, err := json.MarshalIndent(newSigningRequestResponse(*request), "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		SanitizedResponse(w, string(ret))
+		return
+	}
+
+	requests, err := i.node.Datastore.SigningRequests().GetAll(r.URL.Query().Get("state"), r.URL.Query().Get("orderId"))
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	responses := []signingRequestResponse{}
+	for _, request := range requests {
+		responses = append(responses, newSigningRequestResponse(request))
+	}
+	ret, err := json.MarshalIndent(responses, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// POST a signed PSBT for a transaction exported for signing outside the node
+func (i *jsonAPIHandler) POSTSigningRequest(w http.ResponseWriter, r *http.Request) {
+	var args struct {
+		PSBT string `json:"psbt"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	request, err := i.node.ImportSigningRequest(args.PSBT)
+	switch {
+	case err == core.ErrSigningRequestNotFound:
+		ErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	case err == core.ErrInvalidSigningRequest:
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(newSigningRequestResponse(*request), "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// GET the transactions of our wallets, filtered and optionally exported as
+// CSV or OFX for bookkeeping
+func (i *jsonAPIHandler) GETWalletHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := core.TransactionFilter{
+		OrderID:  query.Get("orderId"),
+		Label:    query.Get("label"),
+		Category: query.Get("category"),
+	}
+	if coins := query.Get("coins"); coins != "" {
+		filter.Coins = strings.Split(coins, ",")
+	}
+	for _, t := range []struct {
+		param string
+		value *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		if s := query.Get(t.param); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				ErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s must be an RFC 3339 time", t.param))
+				return
+			}
+			*t.value = parsed
+		}
+	}
+
+	filter.FiatCurrency = query.Get("currency")
+	if filter.FiatCurrency == "" {
+		filter.FiatCurrency = "USD"
+		if settings, err := i.node.Datastore.Settings().Get(); err == nil && settings.LocalCurrency != nil && *settings.LocalCurrency != "" {
+			filter.FiatCurrency = *settings.LocalCurrency
+		}
+	}
+
+	txs, err := i.node.WalletTransactions(filter)
+	if err == core.ErrUnknownWallet {
+		ErrorResponse(w, http.StatusBadRequest, "Unknown wallet type")
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	format := query.Get("format")
+	if format == "" || format == "json" {
+		if txs == nil {
+			txs = []core.WalletTransaction{}
+		}
+		ret, err := json.MarshalIndent(txs, "", "    ")
+		if err != nil {
+			ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		SanitizedResponse(w, string(ret))
+		return
+	}
+
+	var buf bytes.Buffer
+	err = core.WriteTransactions(&buf, format, txs, filter.FiatCurrency)
+	if err == core.ErrUnknownExportFormat {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	} else if _, ok := err.(core.ErrMissingFiatValue); ok {
+		ErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	contentType := "text/csv"
+	if format == core.TransactionExportOFX {
+		contentType = "application/x-ofx"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions.%s"`, format))
+	w.Write(buf.Bytes())
+}
+
+// GET the user's labels for transactions and addresses
+func (i *jsonAPIHandler) GETWalletLabels(w http.ResponseWriter, r *http.Request) {
+	labels, err := i.node.Datastore.TxLabels().GetAll()
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ret, err := json.MarshalIndent(labels, "", "    ")
+	if err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, string(ret))
+}
+
+// POST a category and labels for a transaction or an address
+func (i *jsonAPIHandler) POSTWalletLabel(w http.ResponseWriter, r *http.Request) {
+	var label repo.TransactionLabel
+	if err := json.NewDecoder(r.Body).Decode(&label); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := i.node.PutTransactionLabel(label); err != nil {
+		ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
+
+// DELETE the category and labels of a transaction or an address
+func (i *jsonAPIHandler) DELETEWalletLabel(w http.ResponseWriter, r *http.Request) {
+	_, target := path.Split(r.URL.Path)
+	if target == "" || target == "labels" {
+		ErrorResponse(w, http.StatusBadRequest, "a txid or address is required")
+		return
+	}
+	if err := i.node.Datastore.TxLabels().Delete(target); err != nil {
+		ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	SanitizedResponse(w, `{}`)
+}
@@ -368,6 +368,36 @@ const insuffientFundsJSON = `{
 	"reason": "ERROR_INSUFFICIENT_FUNDS"
 }`
 
+const batchSpendJSON = `{
+	"wallet": "btc",
+	"outputs": [
+		{"address": "1HYhu8e2wv19LZ2umXoo1pMiwzy2rL32UQ", "amount": 1700000},
+		{"address": "1HYhu8e2wv19LZ2umXoo1pMiwzy2rL32UQ", "amount": 300000}
+	],
+	"feePerByte": 20,
+	"dryRun": true
+}`
+
+const zeroAmountSpendJSON = `{
+	"wallet": "btc",
+	"outputs": [{"address": "1HYhu8e2wv19LZ2umXoo1pMiwzy2rL32UQ", "amount": 0}],
+	"dryRun": true
+}`
+
+const invalidUtxoSpendJSON = `{
+	"wallet": "btc",
+	"address": "1HYhu8e2wv19LZ2umXoo1pMiwzy2rL32UQ",
+	"amount": 1700000,
+	"utxos": ["not-an-outpoint"]
+}`
+
+const unavailableUtxoSpendJSON = `{
+	"wallet": "btc",
+	"address": "1HYhu8e2wv19LZ2umXoo1pMiwzy2rL32UQ",
+	"amount": 1700000,
+	"utxos": ["a8c685478265f4c14dada651969c45a65e1aeb8cd6791f2f5bb6a1d9952104d9:1"]
+}`
+
 const invalidCoinJSON = `{
     	"success": false,
     	"reason": "multiwallet does not contain an implementation for the given coin"
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -494,6 +495,10 @@ func TestWallet(t *testing.T) {
 		{"GET", "/wallet/balance", "", 200, walletBalanceJSONResponse},
 		{"GET", "/wallet/mnemonic", "", 200, walletMneumonicJSONResponse},
 		{"POST", "/wallet/spend/", spendJSON, 400, insuffientFundsJSON},
+		{"POST", "/wallet/spend/", batchSpendJSON, 400, insuffientFundsJSON},
+		{"POST", "/wallet/spend/", zeroAmountSpendJSON, 400, errorResponseJSON(core.ErrInvalidSpendAmount)},
+		{"POST", "/wallet/spend/", invalidUtxoSpendJSON, 400, errorResponseJSON(core.ErrInvalidSpendUtxo)},
+		{"POST", "/wallet/spend/", unavailableUtxoSpendJSON, 400, errorResponseJSON(core.ErrSpendUtxoUnavailable)},
 		// TODO: Test successful spend on regnet with coins
 	})
 }
@@ -524,6 +529,23 @@ func Test404(t *testing.T) {
 	})
 }
 
+func TestFeed(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/feed", "", 200, `[]`},
+		{"GET", "/ob/feed?type=listing&limit=10", "", 200, `[]`},
+		{"GET", "/ob/feed?type=rating", "", 400, errorResponseJSON(errors.New("type must be post or listing"))},
+		{"GET", "/ob/feed?limit=x", "", 400, anyResponseJSON},
+	})
+}
+
+func TestPostComments_NoComments(t *testing.T) {
+	runAPITests(t, apiTests{
+		{"GET", "/ob/postcomments/test1", "", 200, `{"slug": "test1", "count": 0, "comments": []}`},
+		// A quote in the slug does not break the JSON and is sanitized
+		{"GET", "/ob/postcomments/test%22%7D", "", 200, `{"slug": "test&#34;}", "count": 0, "comments": []}`},
+	})
+}
+
 func TestPosts(t *testing.T) {
 	runAPITests(t, apiTests{
 		{"GET", "/ob/posts", "", 200, `[]`},
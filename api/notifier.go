@@ -1,10 +1,19 @@
 package api
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"net/smtp"
+	"net/http"
+	"net/url"
+	"path"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/phoreproject/openbazaar-go/core"
 	"github.com/phoreproject/openbazaar-go/repo"
@@ -15,19 +24,43 @@ import (
 // which is listened by websocket API, while adding specific handling for
 // each received object.
 type notificationManager struct {
-	node *core.OpenBazaarNode
+	node   *core.OpenBazaarNode
+	digest *notificationDigest
+}
+
+func newNotificationManager(node *core.OpenBazaarNode) *notificationManager {
+	return &notificationManager{
+		node:   node,
+		digest: &notificationDigest{lastSent: time.Now()},
+	}
 }
 
 func manageNotifications(node *core.OpenBazaarNode, out chan []byte) chan repo.Notifier {
-	manager := &notificationManager{node: node}
+	manager := newNotificationManager(node)
 	nodeBroadcast := make(chan repo.Notifier)
+	if settings, err := node.Datastore.Settings().Get(); err == nil {
+		requeueWebhookDeliveries(settings, node.Datastore.WebhookDeliveries())
+	}
 	go func() {
+		digestTicker := time.NewTicker(time.Minute)
 		for {
-			n := <-nodeBroadcast
+			var n repo.Notifier
+			select {
+			case now := <-digestTicker.C:
+				manager.sendDigest(now)
+				continue
+			case n = <-nodeBroadcast:
+			}
 			// Fixme: right now this assumes that n is a notification but it should be agnostic
 			// enough to let us send any data to the websocket. You can technically do that by
 			// sending over a []byte as the serialize function ignores []bytes but it's kind of hacky.
-			manager.sendNotification(n)
+			settings, err := node.Datastore.Settings().Get()
+			if err == nil {
+				manager.sendNotification(settings, n)
+			}
+			if !settings.NotificationPreferences.Channels(n.GetType()).Websocket {
+				continue
+			}
 			data, err := n.WebsocketData()
 			if err != nil {
 				log.Error("marshal notification:", err)
@@ -49,64 +82,183 @@ type notifier interface {
 }
 
 // Send notification via all supported notifier mechanisms
-func (m *notificationManager) sendNotification(n repo.Notifier) {
-	for _, notifier := range m.getNotifiers() {
+func (m *notificationManager) sendNotification(settings repo.SettingsData, n repo.Notifier) {
+	for _, notifier := range m.getNotifiers(settings, n) {
 		if err := notifier.notify(n); err != nil {
 			log.Errorf("Notification failed: %s", err.Error())
 		}
 	}
 }
 
-// Create list of notifiers based on settings data (currently only SMTP is available)
-// TODO: should be extended to include new notifiers in the list
-func (m *notificationManager) getNotifiers() []notifier {
+// Create list of notifiers for n based on settings data and the
+// notification preferences
+func (m *notificationManager) getNotifiers(settings repo.SettingsData, n repo.Notifier) []notifier {
+	var (
+		notifiers []notifier
+		prefs     = settings.NotificationPreferences
+		channels  = prefs.Channels(n.GetType())
+	)
+
+	// SMTP notifier, which holds emails for the digest during quiet hours
+	// and for low priority notifications
+	conf := settings.SMTPSettings
+	if conf != nil && conf.Notifications && channels.SMTP {
+		if prefs.IsQuiet(time.Now()) || prefs.IsDigested(n.GetType()) {
+			notifiers = append(notifiers, &digestNotifier{digest: m.digest, digested: prefs.IsDigested(n.GetType())})
+		} else {
+			notifiers = append(notifiers, &smtpNotifier{settings: conf, node: m.node})
+		}
+	}
+
+	// Webhook notifiers
+	if settings.Webhooks != nil && channels.Webhook {
+		for _, webhook := range *settings.Webhooks {
+			if webhook.Enabled {
+				notifiers = append(notifiers, newWebhookNotifier(webhook, m.node.Datastore.WebhookDeliveries()))
+			}
+		}
+	}
+	return notifiers
+}
+
+// sendDigest emails the held notifications once the quiet hours are over.
+// Low priority notifications wait for the digest interval unless they go
+// out with an email held by the quiet hours.
+func (m *notificationManager) sendDigest(now time.Time) {
 	settings, err := m.node.Datastore.Settings().Get()
-	var notifiers []notifier
 	if err != nil {
-		return notifiers
+		return
 	}
-
-	// SMTP notifier
 	conf := settings.SMTPSettings
-	if conf != nil && conf.Notifications {
-		notifiers = append(notifiers, &smtpNotifier{settings: conf})
+	if conf == nil || !conf.Notifications {
+		m.digest.take(now, 0)
+		return
+	}
+	prefs := settings.NotificationPreferences
+	if prefs.IsQuiet(now) {
+		return
+	}
+	var interval time.Duration
+	if prefs != nil {
+		interval = prefs.Digest.Interval()
+	}
+	entries := m.digest.take(now, interval)
+	if len(entries) == 0 {
+		return
+	}
+	head, body := digestEmail(entries)
+	data := emailData{Type: notificationDigestType, Title: head, Body: body}
+	if err := sendSMTPNotification(conf, emailTemplatePath(m.node), data); err != nil {
+		log.Errorf("Notification digest failed: %s", err.Error())
 	}
-	return notifiers
 }
 
 // Notifier implementations
 type smtpNotifier struct {
 	settings *repo.SMTPSettings
+	node     *core.OpenBazaarNode
 }
 
 func (notifier *smtpNotifier) notify(n repo.Notifier) error {
-	template := strings.Join([]string{
-		"From: %s",
-		"To: %s",
-		"MIME-Version: 1.0",
-		"Content-Type: text/html; charset=UTF-8",
-		"Subject: [Phore Marketplace] %s\r\n",
-		"%s\r\n",
-	}, "\r\n")
 	head, body, ok := n.GetSMTPTitleAndBody()
 	if !ok {
 		return nil
 	}
-	conf := notifier.settings
-	data := fmt.Sprintf(template, conf.SenderEmail, conf.RecipientEmail, head, body)
-	return sendEmail(notifier.settings, []byte(data))
+	data := emailData{
+		Type:  n.GetType(),
+		Title: head,
+		Body:  body,
+		Order: emailOrderDetails(notifier.node, notifier.settings, n),
+	}
+	return sendSMTPNotification(notifier.settings, emailTemplatePath(notifier.node), data)
+}
+
+// sendSMTPNotification renders the email templates and sends the email
+func sendSMTPNotification(conf *repo.SMTPSettings, templateDir string, data emailData) error {
+	text, html, err := renderEmail(templateDir, data)
+	if err != nil {
+		return err
+	}
+	msg, err := buildEmail(conf, data.Title, text, html)
+	if err != nil {
+		return err
+	}
+	return sendEmail(conf, msg)
+}
+
+// emailTemplatePath returns the directory holding the node's customized
+// email templates
+func emailTemplatePath(node *core.OpenBazaarNode) string {
+	if node == nil {
+		return ""
+	}
+	return path.Join(node.RepoPath, emailTemplatesDir)
+}
+
+// digestEntry is an email held for the digest
+type digestEntry struct {
+	title, body string
+	// digested is false for emails only held by the quiet hours
+	digested bool
+}
+
+// notificationDigest holds emails until they are sent together
+type notificationDigest struct {
+	lock     sync.Mutex
+	entries  []digestEntry
+	lastSent time.Time
+}
+
+// take returns the held emails if the interval has passed since the last
+// digest or any of them was only held by the quiet hours
+func (d *notificationDigest) take(now time.Time, interval time.Duration) []digestEntry {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	due := now.Sub(d.lastSent) >= interval
+	for _, entry := range d.entries {
+		if !entry.digested {
+			due = true
+		}
+	}
+	if !due || len(d.entries) == 0 {
+		return nil
+	}
+	entries := d.entries
+	d.entries = nil
+	d.lastSent = now
+	return entries
+}
+
+type digestNotifier struct {
+	digest   *notificationDigest
+	digested bool
+}
+
+func (notifier *digestNotifier) notify(n repo.Notifier) error {
+	title, body, ok := n.GetSMTPTitleAndBody()
+	if !ok {
+		return nil
+	}
+	notifier.digest.lock.Lock()
+	defer notifier.digest.lock.Unlock()
+	notifier.digest.entries = append(notifier.digest.entries, digestEntry{title, body, notifier.digested})
+	return nil
 }
 
-// Send email using PLAIN authentication to the server
-func sendEmail(conf *repo.SMTPSettings, body []byte) error {
-	hostAndPort := strings.Split(conf.ServerAddress, ":")
-	host := conf.ServerAddress
-	if len(hostAndPort) == 2 {
-		host = hostAndPort[0]
+// notificationDigestType is the type passed to the templates of a digest,
+// so it can be customized with digest.txt and digest.html
+const notificationDigestType repo.NotificationType = "digest"
+
+// digestEmail returns the title and body of the email for the held entries
+func digestEmail(entries []digestEntry) (string, string) {
+	if len(entries) == 1 {
+		return entries[0].title, entries[0].body
+	}
+	var body bytes.Buffer
+	for _, entry := range entries {
+		fmt.Fprintf(&body, "%s\n\n%s\n\n", entry.title, strings.TrimSpace(entry.body))
 	}
-	auth := smtp.PlainAuth("", conf.Username, conf.Password, host)
-	recipients := []string{conf.RecipientEmail}
-	return smtp.SendMail(conf.ServerAddress, auth, conf.SenderEmail, recipients, body)
+	return fmt.Sprintf("%d new notifications", len(entries)), body.String()
 }
 
 func validateSMTPSettings(s repo.SettingsData) error {
@@ -114,5 +266,243 @@ func validateSMTPSettings(s repo.SettingsData) error {
 		(s.SMTPSettings.Password == "" || s.SMTPSettings.Username == "" || s.SMTPSettings.RecipientEmail == "" || s.SMTPSettings.SenderEmail == "" || s.SMTPSettings.ServerAddress == "") {
 		return errors.New("SMTP fields must be set if notifications are turned on")
 	}
+	if s.SMTPSettings != nil {
+		switch s.SMTPSettings.TLSMode {
+		case "", SMTPTLSModeSTARTTLS, SMTPTLSModeImplicit, SMTPTLSModeNone:
+		default:
+			return fmt.Errorf("unknown SMTP TLS mode %q", s.SMTPSettings.TLSMode)
+		}
+	}
+	return nil
+}
+
+const (
+	// webhookMaxAttempts is the number of times a delivery is posted before
+	// it is marked as failed
+	webhookMaxAttempts = 5
+	webhookTimeout     = 10 * time.Second
+)
+
+// webhookNotifier posts notifications to an HTTP endpoint. Every delivery is
+// logged so failed ones can be replayed.
+type webhookNotifier struct {
+	settings repo.WebhookSettings
+	store    repo.WebhookDeliveryStore
+	client   *http.Client
+	// backoff is the delay before the first retry and doubles after each one
+	backoff time.Duration
+}
+
+func newWebhookNotifier(settings repo.WebhookSettings, store repo.WebhookDeliveryStore) *webhookNotifier {
+	return &webhookNotifier{
+		settings: settings,
+		store:    store,
+		client:   &http.Client{Timeout: webhookTimeout},
+		backoff:  time.Second * 2,
+	}
+}
+
+type webhookPayload struct {
+	DeliveryID string                `json:"deliveryId"`
+	Type       repo.NotificationType `json:"type"`
+	Timestamp  time.Time             `json:"timestamp"`
+	Data       json.RawMessage       `json:"data"`
+}
+
+func (notifier *webhookNotifier) notify(n repo.Notifier) error {
+	if !notifier.settings.Wants(n.GetType()) {
+		return nil
+	}
+	data, err := n.Data()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	payload := webhookPayload{
+		DeliveryID: repo.NewNotificationID(),
+		Type:       n.GetType(),
+		Timestamp:  now,
+		Data:       data,
+	}
+	ser, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	delivery := repo.WebhookDelivery{
+		ID:        payload.DeliveryID,
+		URL:       notifier.settings.URL,
+		Type:      string(payload.Type),
+		Payload:   ser,
+		Status:    repo.WebhookDeliveryPending,
+		Timestamp: now,
+		Updated:   now,
+	}
+	if err := notifier.store.Put(delivery); err != nil {
+		return err
+	}
+	go notifier.deliver(delivery)
+	return nil
+}
+
+// deliver posts a delivery until the endpoint accepts it or it runs out of
+// attempts
+func (notifier *webhookNotifier) deliver(delivery repo.WebhookDelivery) {
+	backoff := notifier.backoff
+	for {
+		err := notifier.attempt(&delivery)
+		if err != nil && delivery.Attempts < webhookMaxAttempts {
+			delivery.Status = repo.WebhookDeliveryPending
+		}
+		if err := notifier.store.Put(delivery); err != nil {
+			log.Errorf("Saving webhook delivery %s: %s", delivery.ID, err.Error())
+		}
+		if delivery.Status != repo.WebhookDeliveryPending {
+			if err != nil {
+				log.Errorf("Webhook delivery %s to %s failed: %s", delivery.ID, delivery.URL, err.Error())
+			}
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// attempt posts a delivery once and records the outcome
+func (notifier *webhookNotifier) attempt(delivery *repo.WebhookDelivery) error {
+	delivery.Attempts++
+	delivery.Updated = time.Now()
+	err := notifier.post(delivery)
+	if err != nil {
+		delivery.Status = repo.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+		return err
+	}
+	delivery.Status = repo.WebhookDeliveryDelivered
+	delivery.LastError = ""
+	return nil
+}
+
+func (notifier *webhookNotifier) post(delivery *repo.WebhookDelivery) error {
+	req, err := http.NewRequest("POST", notifier.settings.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Phore-Event", delivery.Type)
+	req.Header.Set("X-Phore-Delivery", delivery.ID)
+	req.Header.Set("X-Phore-Signature", "sha256="+signWebhookPayload(notifier.settings.Secret, delivery.Payload))
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// signWebhookPayload returns the hex encoded HMAC-SHA256 of the payload
+// which lets the endpoint check it came from this node
+func signWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// replayWebhookDelivery posts a logged delivery again to its endpoint, signed
+// with the endpoint's current secret
+func replayWebhookDelivery(settings repo.SettingsData, store repo.WebhookDeliveryStore, deliveryID string) (*repo.WebhookDelivery, error) {
+	delivery, err := store.Get(deliveryID)
+	if err != nil {
+		return nil, errWebhookDeliveryNotFound
+	}
+	if settings.Webhooks == nil {
+		return nil, errWebhookNotConfigured
+	}
+	for _, webhook := range *settings.Webhooks {
+		if webhook.URL != delivery.URL {
+			continue
+		}
+		notifier := newWebhookNotifier(webhook, store)
+		notifier.attempt(delivery)
+		if err := store.Put(*delivery); err != nil {
+			return nil, err
+		}
+		return delivery, nil
+	}
+	return nil, errWebhookNotConfigured
+}
+
+// requeueWebhookDeliveries resumes the deliveries which were still pending
+// when the node stopped. Deliveries to webhooks which are no longer enabled
+// are marked as failed.
+func requeueWebhookDeliveries(settings repo.SettingsData, store repo.WebhookDeliveryStore) {
+	pending, err := store.GetAll(repo.WebhookDeliveryPending, "", -1)
+	if err != nil {
+		log.Errorf("Loading pending webhook deliveries: %s", err.Error())
+		return
+	}
+	for _, delivery := range pending {
+		var notifier *webhookNotifier
+		if settings.Webhooks != nil {
+			for _, webhook := range *settings.Webhooks {
+				if webhook.Enabled && webhook.URL == delivery.URL {
+					notifier = newWebhookNotifier(webhook, store)
+					break
+				}
+			}
+		}
+		if notifier == nil {
+			delivery.Status = repo.WebhookDeliveryFailed
+			delivery.LastError = errWebhookNotConfigured.Error()
+			delivery.Updated = time.Now()
+			if err := store.Put(delivery); err != nil {
+				log.Errorf("Saving webhook delivery %s: %s", delivery.ID, err.Error())
+			}
+			continue
+		}
+		go notifier.deliver(delivery)
+	}
+}
+
+var (
+	errWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	errWebhookNotConfigured    = errors.New("the webhook for this delivery is no longer configured")
+)
+
+func validateNotificationPreferences(s repo.SettingsData) error {
+	prefs := s.NotificationPreferences
+	if prefs == nil {
+		return nil
+	}
+	if prefs.QuietHours != nil && prefs.QuietHours.Enabled {
+		for _, t := range []string{prefs.QuietHours.Start, prefs.QuietHours.End} {
+			if _, err := time.Parse("15:04", t); err != nil {
+				return fmt.Errorf("invalid quiet hours time %q, expected HH:MM", t)
+			}
+		}
+	}
+	if prefs.Digest != nil && prefs.Digest.IntervalHours < 0 {
+		return errors.New("notification digest interval must not be negative")
+	}
+	return nil
+}
+
+func validateWebhookSettings(s repo.SettingsData) error {
+	if s.Webhooks == nil {
+		return nil
+	}
+	for _, webhook := range *s.Webhooks {
+		u, err := url.Parse(webhook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url: %s", webhook.URL)
+		}
+		for _, t := range webhook.Types {
+			if t == "" {
+				return errors.New("webhook notification types must not be empty")
+			}
+		}
+	}
 	return nil
 }
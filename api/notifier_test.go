@@ -0,0 +1,306 @@
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func newWebhookTestStore(t *testing.T) (repo.WebhookDeliveryStore, func()) {
+	dataPath, err := ioutil.TempDir("", "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        dataPath,
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		t.Fatal(err)
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.NewWebhookDeliveryStore(database, new(sync.Mutex)), func() {
+		database.Close()
+		os.RemoveAll(dataPath)
+	}
+}
+
+// waitForDelivery polls the log until the delivery leaves the pending state
+func waitForDelivery(t *testing.T, store repo.WebhookDeliveryStore) repo.WebhookDelivery {
+	for i := 0; i < 200; i++ {
+		deliveries, err := store.GetAll("", "", -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries) == 1 && deliveries[0].Status != repo.WebhookDeliveryPending {
+			return deliveries[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the webhook delivery")
+	return repo.WebhookDelivery{}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	store, teardown := newWebhookTestStore(t)
+	defer teardown()
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	notifier := newWebhookNotifier(repo.WebhookSettings{
+		Enabled: true,
+		URL:     server.URL,
+		Secret:  "secret",
+		Types:   []string{string(repo.NotifierTypeTestNotification)},
+	}, store)
+
+	// Notifications outside the filter are not sent
+	if err := notifier.notify(repo.FollowNotification{ID: "1", Type: repo.NotifierTypeFollowNotification, PeerId: "peer"}); err != nil {
+		t.Fatal(err)
+	}
+	if deliveries, _ := store.GetAll("", "", -1); len(deliveries) != 0 {
+		t.Fatalf("expected filtered notifications to be skipped, got %d deliveries", len(deliveries))
+	}
+
+	if err := notifier.notify(repo.TestNotification{}); err != nil {
+		t.Fatal(err)
+	}
+	r := <-received
+	body := <-bodies
+	if r.Header.Get("X-Phore-Signature") != "sha256="+signWebhookPayload("secret", body) {
+		t.Error("invalid payload signature")
+	}
+	if r.Header.Get("X-Phore-Event") != string(repo.NotifierTypeTestNotification) {
+		t.Errorf("unexpected event header %q", r.Header.Get("X-Phore-Event"))
+	}
+	delivery := waitForDelivery(t, store)
+	if delivery.Status != repo.WebhookDeliveryDelivered || delivery.Attempts != 1 {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+	if r.Header.Get("X-Phore-Delivery") != delivery.ID || string(delivery.Payload) != string(body) {
+		t.Error("expected the logged delivery to match the request")
+	}
+}
+
+func TestWebhookNotifier_RetryAndReplay(t *testing.T) {
+	store, teardown := newWebhookTestStore(t)
+	defer teardown()
+
+	var (
+		lock     sync.Mutex
+		failing  = true
+		attempts int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+		if failing {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	webhook := repo.WebhookSettings{Enabled: true, URL: server.URL, Secret: "secret"}
+	notifier := newWebhookNotifier(webhook, store)
+	notifier.backoff = time.Millisecond
+	if err := notifier.notify(repo.TestNotification{}); err != nil {
+		t.Fatal(err)
+	}
+	delivery := waitForDelivery(t, store)
+	if delivery.Status != repo.WebhookDeliveryFailed || delivery.Attempts != webhookMaxAttempts || delivery.LastError == "" {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+	lock.Lock()
+	if attempts != webhookMaxAttempts {
+		t.Errorf("expected %d attempts, got %d", webhookMaxAttempts, attempts)
+	}
+	failing = false
+	lock.Unlock()
+
+	settings := repo.SettingsData{Webhooks: &[]repo.WebhookSettings{webhook}}
+	replayed, err := replayWebhookDelivery(settings, store, delivery.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Status != repo.WebhookDeliveryDelivered || replayed.Attempts != webhookMaxAttempts+1 || replayed.LastError != "" {
+		t.Errorf("unexpected replayed delivery: %+v", replayed)
+	}
+	if _, err := replayWebhookDelivery(repo.SettingsData{}, store, delivery.ID); err != errWebhookNotConfigured {
+		t.Errorf("expected a removed webhook to fail, got %v", err)
+	}
+	if _, err := replayWebhookDelivery(settings, store, "unknown"); err != errWebhookDeliveryNotFound {
+		t.Errorf("expected an unknown delivery to fail, got %v", err)
+	}
+}
+
+func TestRequeueWebhookDeliveries(t *testing.T) {
+	store, teardown := newWebhookTestStore(t)
+	defer teardown()
+
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-Phore-Delivery")
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	for _, delivery := range []repo.WebhookDelivery{
+		{ID: "pending", URL: server.URL, Status: repo.WebhookDeliveryPending, Attempts: 1, Timestamp: now, Updated: now},
+		{ID: "removed", URL: "https://example.com/hook", Status: repo.WebhookDeliveryPending, Timestamp: now, Updated: now},
+	} {
+		if err := store.Put(delivery); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settings := repo.SettingsData{Webhooks: &[]repo.WebhookSettings{{Enabled: true, URL: server.URL}}}
+	requeueWebhookDeliveries(settings, store)
+	select {
+	case id := <-received:
+		if id != "pending" {
+			t.Errorf("unexpected delivery %q", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the pending delivery")
+	}
+	for i := 0; i < 200; i++ {
+		if delivery, err := store.Get("pending"); err == nil && delivery.Status == repo.WebhookDeliveryDelivered {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if delivery, err := store.Get("pending"); err != nil || delivery.Status != repo.WebhookDeliveryDelivered || delivery.Attempts != 2 {
+		t.Errorf("expected the pending delivery to be delivered, got %+v, %v", delivery, err)
+	}
+	if delivery, err := store.Get("removed"); err != nil || delivery.Status != repo.WebhookDeliveryFailed {
+		t.Errorf("expected the delivery to a removed webhook to fail, got %+v, %v", delivery, err)
+	}
+}
+
+func TestValidateWebhookSettings(t *testing.T) {
+	for _, u := range []string{"ftp://example.com", "example.com/hook", "http://"} {
+		settings := repo.SettingsData{Webhooks: &[]repo.WebhookSettings{{URL: u}}}
+		if err := validateWebhookSettings(settings); err == nil {
+			t.Errorf("expected %q to be rejected", u)
+		}
+	}
+	settings := repo.SettingsData{Webhooks: &[]repo.WebhookSettings{{URL: "https://example.com/hook"}}}
+	if err := validateWebhookSettings(settings); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNotificationManager_GetNotifiers(t *testing.T) {
+	var (
+		manager = &notificationManager{digest: &notificationDigest{lastSent: time.Now()}}
+		smtp    = &repo.SMTPSettings{Notifications: true}
+		follow  = repo.FollowNotification{Type: repo.NotifierTypeFollowNotification}
+	)
+	notifiers := manager.getNotifiers(repo.SettingsData{SMTPSettings: smtp}, follow)
+	if len(notifiers) != 1 {
+		t.Fatalf("expected one notifier, got %d", len(notifiers))
+	}
+	if _, ok := notifiers[0].(*smtpNotifier); !ok {
+		t.Error("expected notifications without preferences to be emailed")
+	}
+
+	prefs := &repo.NotificationPreferences{
+		Types: map[repo.NotificationType]repo.NotificationChannels{
+			repo.NotifierTypeFollowNotification: {Websocket: true},
+		},
+	}
+	if notifiers := manager.getNotifiers(repo.SettingsData{SMTPSettings: smtp, NotificationPreferences: prefs}, follow); len(notifiers) != 0 {
+		t.Error("expected no email for a type with the SMTP channel off")
+	}
+
+	prefs = &repo.NotificationPreferences{Digest: &repo.NotificationDigest{Enabled: true}}
+	notifiers = manager.getNotifiers(repo.SettingsData{SMTPSettings: smtp, NotificationPreferences: prefs}, follow)
+	if len(notifiers) != 1 {
+		t.Fatalf("expected one notifier, got %d", len(notifiers))
+	}
+	if n, ok := notifiers[0].(*digestNotifier); !ok || !n.digested {
+		t.Error("expected follows to be held for the digest")
+	}
+	notifiers = manager.getNotifiers(repo.SettingsData{SMTPSettings: smtp, NotificationPreferences: prefs}, repo.TestNotification{})
+	if _, ok := notifiers[0].(*smtpNotifier); !ok {
+		t.Error("expected types outside the digest to be emailed")
+	}
+
+	// Quiet hours around the current time hold every email
+	quiet := &repo.QuietHours{
+		Enabled: true,
+		Start:   time.Now().Add(-time.Hour).Format("15:04"),
+		End:     time.Now().Add(time.Hour).Format("15:04"),
+	}
+	prefs = &repo.NotificationPreferences{QuietHours: quiet}
+	notifiers = manager.getNotifiers(repo.SettingsData{SMTPSettings: smtp, NotificationPreferences: prefs}, repo.TestNotification{})
+	if n, ok := notifiers[0].(*digestNotifier); !ok || n.digested {
+		t.Error("expected emails during quiet hours to be held")
+	}
+}
+
+func TestNotificationDigest(t *testing.T) {
+	now := time.Now()
+	digest := &notificationDigest{lastSent: now}
+	low := &digestNotifier{digest: digest, digested: true}
+	if err := low.notify(repo.TestNotification{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := low.notify(repo.TestNotification{}); err != nil {
+		t.Fatal(err)
+	}
+	if entries := digest.take(now.Add(time.Hour), 24*time.Hour); len(entries) != 0 {
+		t.Error("expected the digest to wait for its interval")
+	}
+	entries := digest.take(now.Add(25*time.Hour), 24*time.Hour)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 held emails, got %d", len(entries))
+	}
+	if title, _ := digestEmail(entries); title != "2 new notifications" {
+		t.Errorf("unexpected digest title %q", title)
+	}
+
+	// An email held by the quiet hours is sent as soon as they end
+	quiet := &digestNotifier{digest: digest}
+	if err := quiet.notify(repo.TestNotification{}); err != nil {
+		t.Fatal(err)
+	}
+	if entries := digest.take(now.Add(26*time.Hour), 24*time.Hour); len(entries) != 1 {
+		t.Errorf("expected the held email to be sent, got %d", len(entries))
+	}
+}
+
+func TestValidateNotificationPreferences(t *testing.T) {
+	settings := repo.SettingsData{NotificationPreferences: &repo.NotificationPreferences{
+		QuietHours: &repo.QuietHours{Enabled: true, Start: "22:00", End: "7am"},
+	}}
+	if err := validateNotificationPreferences(settings); err == nil {
+		t.Error("expected an invalid quiet hours time to be rejected")
+	}
+	settings.NotificationPreferences.QuietHours.End = "07:00"
+	if err := validateNotificationPreferences(settings); err != nil {
+		t.Error(err)
+	}
+}
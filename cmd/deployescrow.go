@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	wi "github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/wallet"
+)
+
+type DeployEscrow struct {
+	DataDir  string `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	Token    string `short:"c" long:"token" description:"the code of the configured token to deploy the escrow contract for" required:"true"`
+}
+
+func (x *DeployEscrow) Execute(args []string) error {
+	repoPath, err := repo.GetRepoPath(x.Testnet)
+	if err != nil {
+		return err
+	}
+	if x.DataDir != "" {
+		repoPath = x.DataDir
+	}
+	sqliteDB, err := db.Create(repoPath, x.Password, x.Testnet, util.CoinTypePhore)
+	if err != nil {
+		return err
+	}
+	defer sqliteDB.Close()
+	mn, err := sqliteDB.Config().GetMnemonic()
+	if err != nil {
+		return err
+	}
+
+	configFile, err := ioutil.ReadFile(path.Join(repoPath, "config"))
+	if err != nil {
+		return err
+	}
+	walletsConfig, err := schema.GetWalletsConfig(configFile)
+	if err != nil {
+		return err
+	}
+	var tokenConfig *schema.TokenConfig
+	for _, t := range walletsConfig.Tokens {
+		if strings.EqualFold(t.Code, x.Token) {
+			tokenConfig = t
+		}
+	}
+	if tokenConfig == nil {
+		return fmt.Errorf("token %s is not configured", x.Token)
+	}
+
+	params := &chaincfg.MainNetParams
+	if x.Testnet {
+		params = &chaincfg.TestNet3Params
+	}
+	w, err := wallet.NewTokenWallet(tokenConfig, &wallet.WalletConfig{
+		ConfigFile: walletsConfig,
+		RepoPath:   repoPath,
+		DB:         sqliteDB.DB(),
+		Mnemonic:   mn,
+		Params:     params,
+	})
+	if err != nil {
+		return err
+	}
+	escrow, txid, err := w.DeployEscrow(wi.NORMAL)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deploying the escrow contract from %s in transaction %s\n", w.Account().Hex(), txid)
+	fmt.Printf("Set the token's Escrow to %s once the transaction is mined\n", escrow.Hex())
+	return nil
+}
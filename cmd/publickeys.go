@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/wallet"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+)
+
+type PublicKeys struct {
+	DataDir  string `short:"d" long:"datadir" description:"specify the data directory holding the keys"`
+	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+}
+
+func (x *PublicKeys) Execute(args []string) error {
+	_, identityKey, mPrivKey, err := loadNodeKeys(x.DataDir, x.Testnet, x.Password)
+	if err != nil {
+		return err
+	}
+	peerID, err := peer.IDFromPrivateKey(identityKey)
+	if err != nil {
+		return err
+	}
+
+	local := signer.NewLocalSigner(identityKey, mPrivKey)
+	pubKeys, err := local.PublicKeys(wallet.AccountCoinTypes)
+	if err != nil {
+		return err
+	}
+	bitcoinSig, err := local.SignBitcoin([]byte(peerID.Pretty()))
+	if err != nil {
+		return err
+	}
+	keys := schema.WatchOnlyKeys{
+		MasterKey:   pubKeys.Master.String(),
+		AccountKeys: make(map[string]string),
+		BitcoinSig:  hex.EncodeToString(bitcoinSig),
+	}
+	for coinType, account := range pubKeys.Accounts {
+		keys.AccountKeys[strconv.Itoa(int(coinType))] = account.String()
+	}
+
+	out, err := json.MarshalIndent(map[string]interface{}{
+		"ExternalSigning": true,
+		"WatchOnlyKeys":   keys,
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// parseWatchOnlyKeys returns the public keys and peer ID signature a watch-only
+// node runs from
+func parseWatchOnlyKeys(keys *schema.WatchOnlyKeys) (*signer.PublicKeys, []byte, error) {
+	master, err := parsePublicKey(keys.MasterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubKeys := &signer.PublicKeys{Master: master, Accounts: make(map[uint32]*hdkeychain.ExtendedKey)}
+	for ct, key := range keys.AccountKeys {
+		coinType, err := strconv.ParseUint(ct, 10, 32)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid coin type %s", ct)
+		}
+		if pubKeys.Accounts[uint32(coinType)], err = parsePublicKey(key); err != nil {
+			return nil, nil, err
+		}
+	}
+	bitcoinSig, err := hex.DecodeString(keys.BitcoinSig)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pubKeys, bitcoinSig, nil
+}
+
+// parsePublicKey parses an extended public key, refusing a private one
+func parsePublicKey(s string) (*hdkeychain.ExtendedKey, error) {
+	key, err := hdkeychain.NewKeyFromString(s)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("watch-only keys must be public keys")
+	}
+	return key, nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/signer/remote"
+	"github.com/tyler-smith/go-bip39"
+	crypto "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+)
+
+type SignerDaemon struct {
+	DataDir  string `short:"d" long:"datadir" description:"specify the data directory holding the keys"`
+	Testnet  bool   `short:"t" long:"testnet" description:"use the test network"`
+	Password string `short:"p" long:"password" description:"the encryption password if the database is encrypted"`
+	Socket   string `short:"s" long:"socket" description:"the Unix socket to listen on, signer.sock in the data directory by default"`
+}
+
+func (x *SignerDaemon) Execute(args []string) error {
+	repoPath, identityKey, mPrivKey, err := loadNodeKeys(x.DataDir, x.Testnet, x.Password)
+	if err != nil {
+		return err
+	}
+
+	socket := x.Socket
+	if socket == "" {
+		socket = path.Join(repoPath, "signer.sock")
+	}
+	os.Remove(socket)
+	var l net.Listener
+	err = listenPrivate(func() error {
+		l, err = net.Listen("unix", socket)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	if err := os.Chmod(socket, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Signing daemon listening on %s\n", socket)
+	return remote.Serve(l, signer.NewLocalSigner(identityKey, mPrivKey))
+}
+
+// loadNodeKeys returns the data directory of a node, its identity key and
+// the master key derived from its seed
+func loadNodeKeys(dataDir string, testnet bool, password string) (string, crypto.PrivKey, *hdkeychain.ExtendedKey, error) {
+	repoPath, err := repo.GetRepoPath(testnet)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if dataDir != "" {
+		repoPath = dataDir
+	}
+	sqliteDB, err := db.Create(repoPath, password, testnet, util.CoinTypePhore)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	defer sqliteDB.Close()
+	identityKeyBytes, err := sqliteDB.Config().GetIdentityKey()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	identityKey, err := crypto.UnmarshalPrivateKey(identityKeyBytes)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	mn, err := sqliteDB.Config().GetMnemonic()
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	params := &chaincfg.MainNetParams
+	if testnet {
+		params = &chaincfg.TestNet3Params
+	}
+	mPrivKey, err := hdkeychain.NewMaster(bip39.NewSeed(mn, ""), params)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return repoPath, identityKey, mPrivKey, nil
+}
@@ -0,0 +1,11 @@
+// +build !darwin
+// +build !linux
+// +build !netbsd
+// +build !openbsd
+
+package cmd
+
+// listenPrivate runs listen. There is no umask on non-unix systems.
+func listenPrivate(listen func() error) error {
+	return listen()
+}
@@ -0,0 +1,13 @@
+// +build darwin linux netbsd openbsd
+
+package cmd
+
+import "syscall"
+
+// listenPrivate runs listen with a umask which leaves only the owner access
+// to the files it creates, so the socket is never reachable by other users
+func listenPrivate(listen func() error) error {
+	old := syscall.Umask(0077)
+	defer syscall.Umask(old)
+	return listen()
+}
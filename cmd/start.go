@@ -42,6 +42,7 @@ import (
 	"github.com/ipfs/go-ipfs/repo/fsrepo"
 	"github.com/natefinch/lumberjack"
 	"github.com/op/go-logging"
+	"github.com/phoreproject/multiwallet"
 	"github.com/phoreproject/multiwallet/util"
 	"github.com/phoreproject/openbazaar-go/api"
 	"github.com/phoreproject/openbazaar-go/core"
@@ -52,10 +53,14 @@ import (
 	"github.com/phoreproject/openbazaar-go/repo/db"
 	"github.com/phoreproject/openbazaar-go/repo/migrations"
 	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/signer/remote"
 	sto "github.com/phoreproject/openbazaar-go/storage"
 	"github.com/phoreproject/openbazaar-go/storage/dropbox"
 	"github.com/phoreproject/openbazaar-go/storage/selfhosted"
 	"github.com/phoreproject/openbazaar-go/wallet"
+	"github.com/phoreproject/openbazaar-go/wallet/channel"
+	"github.com/phoreproject/openbazaar-go/wallet/exchangerates"
 	lis "github.com/phoreproject/openbazaar-go/wallet/listeners"
 	"github.com/phoreproject/openbazaar-go/wallet/resync"
 	"github.com/tyler-smith/go-bip39"
@@ -251,11 +256,46 @@ func (x *Start) Execute(args []string) error {
 		log.Error("scan republish interval config:", err)
 		return err
 	}
+	externalSigning, err := schema.GetExternalSigning(configFile)
+	if err != nil {
+		log.Error("scan external signing config:", err)
+		return err
+	}
+	signerSocket, err := schema.GetSignerSocket(configFile)
+	if err != nil {
+		log.Error("scan signer socket config:", err)
+		return err
+	}
+	watchOnlyKeys, err := schema.GetWatchOnlyKeys(configFile)
+	if err != nil {
+		log.Error("scan watch-only keys config:", err)
+		return err
+	}
+	if externalSigning && signerSocket == "" && watchOnlyKeys == nil {
+		err = errors.New("ExternalSigning needs the WatchOnlyKeys printed by the publickeys command")
+		log.Error(err)
+		return err
+	}
+	if watchOnlyKeys != nil && !externalSigning {
+		err = errors.New("WatchOnlyKeys can only be used with ExternalSigning")
+		log.Error(err)
+		return err
+	}
 	walletsConfig, err := schema.GetWalletsConfig(configFile)
 	if err != nil {
 		log.Error("scan wallets config:", err)
 		return err
 	}
+	exchangeRatesConfig, err := schema.GetExchangeRatesConfig(configFile)
+	if err != nil {
+		log.Error("scan exchange rates config:", err)
+		return err
+	}
+	fundingConfig, err := schema.GetFundingConfirmationsConfig(configFile)
+	if err != nil {
+		log.Error("scan funding confirmations config:", err)
+		return err
+	}
 	ipnsExtraConfig, err := schema.GetIPNSExtraConfig(configFile)
 	if err != nil {
 		log.Error("scan ipns extra config:", err)
@@ -439,11 +479,6 @@ func (x *Start) Execute(args []string) error {
 	}
 
 	// Wallet
-	mn, err := sqliteDB.Config().GetMnemonic()
-	if err != nil {
-		log.Error("get config mnemonic:", err)
-		return err
-	}
 	var params chaincfg.Params
 	if x.Testnet {
 		params = chaincfg.TestNet3Params
@@ -453,6 +488,58 @@ func (x *Start) Execute(args []string) error {
 		params = chaincfg.MainNetParams
 	}
 
+	// Master key and signer setup. A node signing with a daemon, or watch-only
+	// with external signing, never loads its seed and builds its wallets from
+	// public keys.
+	var (
+		mn         string
+		mPubKey    *hdkeychain.ExtendedKey
+		pubKeys    *signer.PublicKeys
+		nodeSigner signer.Signer
+	)
+	if signerSocket != "" {
+		log.Infof("Signing with the daemon at %s", signerSocket)
+		nodeSigner = remote.NewSigner(signerSocket)
+		pubKeys, err = nodeSigner.PublicKeys(wallet.AccountCoinTypes)
+		if err != nil {
+			log.Error("get signer public keys:", err)
+			return err
+		}
+		mPubKey = pubKeys.Master
+	} else if watchOnlyKeys != nil {
+		log.Info("Running watch-only, transactions are signed externally")
+		var bitcoinSig []byte
+		pubKeys, bitcoinSig, err = parseWatchOnlyKeys(watchOnlyKeys)
+		if err != nil {
+			log.Error("parse watch-only keys:", err)
+			return err
+		}
+		nodeSigner, err = signer.NewWatchOnlySigner(nd.PrivateKey, pubKeys, nd.Identity.Pretty(), bitcoinSig)
+		if err != nil {
+			log.Error("watch-only signer:", err)
+			return err
+		}
+		mPubKey = pubKeys.Master
+	} else {
+		mn, err = sqliteDB.Config().GetMnemonic()
+		if err != nil {
+			log.Error("get config mnemonic:", err)
+			return err
+		}
+		seed := bip39.NewSeed(mn, "")
+		mPrivKey, err := hdkeychain.NewMaster(seed, &params)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		mPubKey, err = mPrivKey.Neuter()
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		nodeSigner = signer.NewLocalSigner(nd.PrivateKey, mPrivKey)
+	}
+
 	// Multiwallet setup
 	var walletLogWriter io.Writer
 	if x.NoLogFiles {
@@ -477,6 +564,7 @@ func (x *Start) Execute(args []string) error {
 		Proxy:                torDialer,
 		WalletCreationDate:   creationDate,
 		Mnemonic:             mn,
+		PublicKeys:           pubKeys,
 		DisableExchangeRates: x.DisableExchangeRates,
 	}
 	mw, err := wallet.NewMultiWallet(multiwalletConfig)
@@ -485,12 +573,28 @@ func (x *Start) Execute(args []string) error {
 	}
 	resyncManager := resync.NewResyncManager(sqliteDB.Sales(), mw)
 
-	// Master key setup
-	seed := bip39.NewSeed(mn, "")
-	mPrivKey, err := hdkeychain.NewMaster(seed, &params)
-	if err != nil {
-		log.Error(err)
-		return err
+	// Payment channel daemon
+	var paymentChannel *channel.Wallet
+	if walletsConfig.Channel != nil && !x.DisableWallet {
+		paymentChannel, err = wallet.NewPaymentChannel(walletsConfig.Channel, &params)
+		if err != nil {
+			log.Error("payment channel:", err)
+			return err
+		}
+		if _, err := mw.WalletForCurrencyCode(paymentChannel.Coin()); err != nil {
+			log.Errorf("payment channel: no %s wallet", paymentChannel.Coin())
+			return err
+		}
+	}
+
+	// Exchange rate feeds
+	var exchangeRateFeeds *exchangerates.Feeds
+	if exchangeRatesConfig != nil && !x.DisableExchangeRates {
+		exchangeRateFeeds, err = newExchangeRateFeeds(exchangeRatesConfig, mw, torDialer)
+		if err != nil {
+			log.Error("exchange rate feeds:", err)
+			return err
+		}
 	}
 
 	// Push nodes
@@ -595,14 +699,18 @@ func (x *Start) Execute(args []string) error {
 		Datastore:                     sqliteDB,
 		IpfsNode:                      nd,
 		DHT:                           dhtRouting,
-		MasterPrivateKey:              mPrivKey,
+		ExchangeRateFeeds:             exchangeRateFeeds,
+		ExternalSigning:               externalSigning,
+		MasterPublicKey:               mPubKey,
 		Multiwallet:                   mw,
 		OfflineMessageFailoverTimeout: 30 * time.Second,
+		PaymentChannel:                paymentChannel,
 		Pubsub:                        ps,
 		PushNodes:                     pushNodes,
 		RegressionTestEnable:          x.Regtest,
 		RepoPath:                      repoPath,
 		RootHash:                      string(ourIpnsRecord.Value),
+		Signer:                        nodeSigner,
 		TestnetEnable:                 x.Testnet,
 		TorDialer:                     torDialer,
 		UserAgent:                     core.USERAGENT,
@@ -663,16 +771,31 @@ func (x *Start) Execute(args []string) error {
 			if resyncManager == nil {
 				core.Node.WaitForMessageRetrieverCompletion()
 			}
-			TL := lis.NewTransactionListener(core.Node.Multiwallet, core.Node.Datastore, core.Node.Broadcast)
-			for ct, wal := range mw {
-				WL := lis.NewWalletListener(core.Node.Datastore, core.Node.Broadcast, ct)
+			TL := lis.NewTransactionListener(core.Node.Multiwallet, core.Node.Datastore, core.Node.Broadcast, fundingConfig, core.Node.TestnetEnable)
+			for _, wal := range mw {
+				WL := lis.NewWalletListener(core.Node.Datastore, core.Node.Broadcast, strings.ToUpper(wal.CurrencyCode()), core.Node.ExchangeRatesFor(wal))
 				wal.AddTransactionListener(WL.OnTransactionReceived)
 				wal.AddTransactionListener(TL.OnTransactionReceived)
 			}
+			if core.Node.PaymentChannel != nil {
+				core.Node.PaymentChannel.AddTransactionListener(TL.OnTransactionReceived)
+				core.Node.PaymentChannel.AddExpiryListener(func(orderID string) {
+					if err := core.Node.DeclineExpiredInvoiceOrder(orderID); err != nil {
+						log.Errorf("Declining order %s after its invoice expired: %s", orderID, err)
+					}
+				})
+				core.Node.WatchPaymentChannelInvoices()
+				go core.Node.PaymentChannel.Start()
+			}
 			log.Info("Starting multiwallet...")
 			su := wallet.NewStatusUpdater(mw, core.Node.Broadcast, nd.Context())
 			go su.Start()
 			go mw.Start()
+			fundingInterval := lis.FundingCheckRegularInterval
+			if core.Node.TestnetEnable {
+				fundingInterval = lis.FundingCheckTestingInterval
+			}
+			TL.StartFundingMonitor(fundingInterval)
 			if resyncManager != nil {
 				go resyncManager.Start()
 				go func() {
@@ -688,12 +811,21 @@ func (x *Start) Execute(args []string) error {
 		core.Node.StartMessageRetriever()
 		core.Node.StartPointerRepublisher()
 		core.Node.StartRecordAgingNotifier()
+		core.Node.StartModeratorAvailabilityMonitor()
+		core.Node.StartDisputeFallbackWorker()
+		core.Node.StartFeedAggregator()
+		core.Node.StartExchangeRateRecorder()
+		core.Node.StartIPNSAnnouncementListener()
 
 		core.PublishLock.Unlock()
 		err = core.Node.UpdateFollow()
 		if err != nil {
 			log.Error(err)
 		}
+		err = core.Node.UpdateCommentIndex()
+		if err != nil {
+			log.Error(err)
+		}
 		if !core.InitalPublishComplete {
 			err = core.Node.SeedNode()
 			if err != nil {
@@ -900,6 +1032,46 @@ func serveHTTPApi(cctx *commands.Context) (<-chan error, error) {
 	return errc, nil
 }
 
+// newExchangeRateFeeds builds the configured exchange rate feeds. HTTP feeds
+// are fetched through the Tor dialer when there is one.
+func newExchangeRateFeeds(cfg *schema.ExchangeRatesConfig, mw multiwallet.MultiWallet, dialer proxy.Dialer) (*exchangerates.Feeds, error) {
+	client := &http.Client{Timeout: time.Second * 30}
+	if dialer != nil {
+		client.Transport = &http.Transport{Dial: dialer.Dial}
+	}
+	var providers []exchangerates.Provider
+	for _, p := range cfg.Providers {
+		switch p.Type {
+		case "wallet":
+			providers = append(providers, exchangerates.NewWalletProvider(func(coin string) wi.ExchangeRates {
+				wal, err := mw.WalletForCurrencyCode(coin)
+				if err != nil {
+					return nil
+				}
+				return wal.ExchangeRates()
+			}))
+		case "file":
+			providers = append(providers, exchangerates.NewFileProvider(p.Path))
+		case "http":
+			providers = append(providers, exchangerates.NewHTTPProvider(p.URL, client))
+		default:
+			return nil, fmt.Errorf("unknown exchange rate provider %s", p.Type)
+		}
+	}
+	opts := exchangerates.Options{
+		MaxDeviation: cfg.MaxDeviation,
+		MinSources:   cfg.MinSources,
+	}
+	if cfg.MaxAge != "" {
+		maxAge, err := time.ParseDuration(cfg.MaxAge)
+		if err != nil {
+			return nil, err
+		}
+		opts.MaxAge = maxAge
+	}
+	return exchangerates.New(providers, opts), nil
+}
+
 func InitializeRepo(dataDir, password, mnemonic string, testnet bool, creationDate time.Time, coinType util.ExtCoinType) (*db.SQLiteDatastore, error) {
 	// Database
 	sqliteDB, err := db.Create(dataDir, password, testnet, coinType)
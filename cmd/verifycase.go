@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/phoreproject/openbazaar-go/core"
+)
+
+type VerifyCase struct {
+	File string `short:"f" long:"file" description:"path to the case export bundle" required:"true"`
+}
+
+func (x *VerifyCase) Execute(args []string) error {
+	bundle, err := ioutil.ReadFile(x.File)
+	if err != nil {
+		return err
+	}
+	report, err := core.VerifyCaseExport(bundle)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Case:        %s\n", report.OrderID)
+	fmt.Printf("Exported by: %s\n", report.ExportedBy)
+	fmt.Printf("Exported at: %s\n\n", report.ExportedAt.Format("2006-01-02 15:04:05 MST"))
+	for _, c := range report.Checks {
+		if c.Error == "" {
+			fmt.Printf("[OK]     %s\n", c.Description)
+		} else {
+			fmt.Printf("[FAILED] %s: %s\n", c.Description, c.Error)
+		}
+	}
+	fmt.Println()
+	if !report.Valid() {
+		fmt.Println("Case export failed verification")
+		os.Exit(1)
+	}
+	fmt.Println("Case export verified")
+	return nil
+}
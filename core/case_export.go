@@ -0,0 +1,636 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	"gx/ipfs/QmUadX5EcvrBmxAV9sE7wUWtWSqxns5K84qKJBixmcT1w9/go-datastore"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	routing "gx/ipfs/QmYxUdYY9S6yg5tSPVin5GFTvtfsLauVcr7reHDD3dM8xf/go-libp2p-routing"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/ipfs"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// CaseExportVersion is the version of the case export bundle format
+	CaseExportVersion = 1
+
+	caseExportManifestFile       = "manifest.json"
+	caseExportSignatureFile      = "manifest.sig"
+	caseExportCaseFile           = "case.json"
+	caseExportBuyerContractFile  = "buyerContract.json"
+	caseExportVendorContractFile = "vendorContract.json"
+	caseExportResolutionFile     = "resolution.json"
+	caseExportChatFile           = "chat.json"
+	caseExportEvidenceDir        = "evidence"
+
+	caseExportEvidenceTimeout = time.Second * 30
+)
+
+// ErrCaseExportMissingManifest is returned when a bundle does not contain a
+// manifest or its signature
+var ErrCaseExportMissingManifest = errors.New("case export is missing the manifest or its signature")
+
+// CaseExportManifest lists every file in a case export bundle together with
+// its SHA-256 digest. The manifest is signed with the identity key of the
+// exporting moderator so the transcript and evidence, which carry no
+// signatures of their own, can't be altered after the export.
+type CaseExportManifest struct {
+	Version        int              `json:"version"`
+	OrderID        string           `json:"orderId"`
+	ExportedBy     string           `json:"exportedBy"`
+	ExporterPubkey []byte           `json:"exporterPubkey"`
+	Timestamp      time.Time        `json:"timestamp"`
+	Files          []CaseExportFile `json:"files"`
+}
+
+// CaseExportFile is a single entry of the CaseExportManifest
+type CaseExportFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// CaseExportCheck is the outcome of a single verification step
+type CaseExportCheck struct {
+	Description string `json:"description"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CaseExportReport is the result of verifying a case export bundle
+type CaseExportReport struct {
+	OrderID    string            `json:"orderId"`
+	ExportedBy string            `json:"exportedBy"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Checks     []CaseExportCheck `json:"checks"`
+}
+
+// Valid returns true if every check in the report passed
+func (r *CaseExportReport) Valid() bool {
+	for _, c := range r.Checks {
+		if c.Error != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *CaseExportReport) check(description string, err error) {
+	c := CaseExportCheck{Description: description}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// caseExportMetadata is the case file of a bundle. The payout addresses and
+// outpoints are those the parties sent with the dispute and its update. The
+// public key of the moderator who signed the resolution is included so it
+// can be verified offline when someone else exported the case.
+type caseExportMetadata struct {
+	OrderID                string         `json:"orderId"`
+	Claim                  string         `json:"claim"`
+	State                  string         `json:"state"`
+	BuyerOpened            bool           `json:"buyerOpened"`
+	Timestamp              time.Time      `json:"timestamp"`
+	BuyerPayoutAddress     string         `json:"buyerPayoutAddress,omitempty"`
+	BuyerOutpoints         []*pb.Outpoint `json:"buyerOutpoints,omitempty"`
+	VendorPayoutAddress    string         `json:"vendorPayoutAddress,omitempty"`
+	VendorOutpoints        []*pb.Outpoint `json:"vendorOutpoints,omitempty"`
+	ResolutionSigner       string         `json:"resolutionSigner,omitempty"`
+	ResolutionSignerPubkey []byte         `json:"resolutionSignerPubkey,omitempty"`
+	MissingEvidence        []string       `json:"missingEvidence,omitempty"`
+}
+
+// ExportCase builds a self-contained zip archive of a dispute case holding
+// both parties' contracts with the signed dispute, the resolution with the
+// signature the parties received, the chat transcript and the evidence: the
+// listing images and the files attached to the case chat. The bundle can be
+// checked offline with VerifyCaseExport.
+func (n *OpenBazaarNode) ExportCase(orderID string) ([]byte, error) {
+	buyerContract, vendorContract, _, _, state, _, date, buyerOpened, claim, resolution, err := n.Datastore.Cases().GetCaseMetadata(orderID)
+	if err != nil {
+		return nil, ErrCaseNotFound
+	}
+	dispute, err := n.Datastore.Cases().GetByCaseID(orderID)
+	if err != nil {
+		return nil, ErrCaseNotFound
+	}
+	metadata := caseExportMetadata{
+		OrderID:             orderID,
+		Claim:               claim,
+		State:               state.String(),
+		BuyerOpened:         buyerOpened,
+		Timestamp:           date,
+		BuyerPayoutAddress:  dispute.BuyerPayoutAddress,
+		BuyerOutpoints:      dispute.BuyerOutpoints,
+		VendorPayoutAddress: dispute.VendorPayoutAddress,
+		VendorOutpoints:     dispute.VendorOutpoints,
+	}
+
+	var (
+		paths   []string
+		files   = make(map[string][]byte)
+		addFile = func(name string, b []byte) {
+			paths = append(paths, name)
+			files[name] = b
+		}
+		m = jsonpb.Marshaler{
+			EnumsAsInts:  false,
+			EmitDefaults: true,
+			Indent:       "    ",
+			OrigName:     false,
+		}
+	)
+
+	for _, c := range []struct {
+		name     string
+		contract *pb.RicardianContract
+	}{
+		{caseExportBuyerContractFile, buyerContract},
+		{caseExportVendorContractFile, vendorContract},
+	} {
+		if c.contract == nil {
+			continue
+		}
+		out, err := m.MarshalToString(c.contract)
+		if err != nil {
+			return nil, err
+		}
+		addFile(c.name, []byte(out))
+	}
+
+	if resolution != nil {
+		payment := casePayment(buyerContract, vendorContract)
+		signed := signedCaseResolution(buyerContract, vendorContract)
+		if signed == nil {
+			// The moderator's case only keeps the resolution, the signature
+			// is the same if we sign it again
+			signed = &pb.RicardianContract{DisputeResolution: resolution}
+			if payment != nil && resolutionSigner(resolution, payment) == n.IpfsNode.Identity.Pretty() {
+				if signed, err = n.SignDisputeResolution(signed); err != nil {
+					return nil, err
+				}
+			} else {
+				log.Warningf("the signature on the resolution of case %s was not recorded", orderID)
+			}
+		}
+		if payment != nil {
+			metadata.ResolutionSigner = resolutionSigner(signed.DisputeResolution, payment)
+			metadata.ResolutionSignerPubkey = n.casePeerPubkey(metadata.ResolutionSigner)
+		}
+		out, err := m.MarshalToString(signed)
+		if err != nil {
+			return nil, err
+		}
+		addFile(caseExportResolutionFile, []byte(out))
+	}
+
+	// Messages are returned newest first, the transcript reads oldest first
+	messages := n.Datastore.Chat().GetMessages("", orderID, "", -1)
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	if messages == nil {
+		messages = []repo.ChatMessage{}
+	}
+	transcript, err := json.MarshalIndent(messages, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	addFile(caseExportChatFile, transcript)
+
+	for _, hash := range caseListingImages(buyerContract, vendorContract) {
+		b, err := ipfs.Cat(n.IpfsNode, hash, caseExportEvidenceTimeout)
+		if err != nil {
+			log.Warningf("unable to fetch evidence %s for case %s: %s", hash, orderID, err)
+			metadata.MissingEvidence = append(metadata.MissingEvidence, hash)
+			continue
+		}
+		addFile(path.Join(caseExportEvidenceDir, hash), b)
+	}
+
+	// Attachments are named after their message, the transcript lists their
+	// file names and types
+	for _, msg := range messages {
+		for _, a := range msg.Attachments {
+			name := msg.MessageId + "-" + strconv.Itoa(a.Index)
+			attachment, err := n.FetchChatAttachment(msg.MessageId, a.Index)
+			if err != nil {
+				log.Warningf("unable to fetch attachment %s for case %s: %s", name, orderID, err)
+				metadata.MissingEvidence = append(metadata.MissingEvidence, name)
+				continue
+			}
+			addFile(path.Join(caseExportEvidenceDir, name), attachment.Data)
+		}
+	}
+
+	metadataBytes, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	addFile(caseExportCaseFile, metadataBytes)
+
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	manifest := CaseExportManifest{
+		Version:        CaseExportVersion,
+		OrderID:        orderID,
+		ExportedBy:     n.IpfsNode.Identity.Pretty(),
+		ExporterPubkey: pubkey,
+		Timestamp:      time.Now().UTC(),
+	}
+	for _, p := range paths {
+		digest := sha256.Sum256(files[p])
+		manifest.Files = append(manifest.Files, CaseExportFile{Path: p, SHA256: hex.EncodeToString(digest[:])})
+	}
+	manifestBytes, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	manifestSig, err := n.IpfsNode.PrivateKey.Sign(manifestBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	write := func(name string, b []byte) error {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(b)
+		return err
+	}
+	if err := write(caseExportManifestFile, manifestBytes); err != nil {
+		return nil, err
+	}
+	if err := write(caseExportSignatureFile, manifestSig); err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		if err := write(p, files[p]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// VerifyCaseExport checks a case export bundle without network access. The
+// manifest signature and file digests are verified first, then every
+// signature inside the buyer's and vendor's contracts, including the
+// disputer's signature on the dispute, and the moderator's signature on the
+// resolution. An error is only returned if the bundle can't be read, failed
+// checks are recorded in the report.
+func VerifyCaseExport(bundle []byte) (*CaseExportReport, error) {
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = b
+	}
+	manifestBytes, mok := files[caseExportManifestFile]
+	manifestSig, sok := files[caseExportSignatureFile]
+	if !mok || !sok {
+		return nil, ErrCaseExportMissingManifest
+	}
+	var manifest CaseExportManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, err
+	}
+
+	report := &CaseExportReport{
+		OrderID:    manifest.OrderID,
+		ExportedBy: manifest.ExportedBy,
+		ExportedAt: manifest.Timestamp,
+	}
+	report.check("exporter's signature on manifest", verifyCaseExportManifest(manifestBytes, manifestSig, manifest))
+
+	listed := make(map[string]bool)
+	for _, f := range manifest.Files {
+		listed[f.Path] = true
+		b, ok := files[f.Path]
+		if !ok {
+			report.check("digest of "+f.Path, errors.New("file is missing from the bundle"))
+			continue
+		}
+		digest := sha256.Sum256(b)
+		if hex.EncodeToString(digest[:]) != f.SHA256 {
+			report.check("digest of "+f.Path, errors.New("file does not match the digest in the manifest"))
+			continue
+		}
+		report.check("digest of "+f.Path, nil)
+	}
+	var unlisted []string
+	for name := range files {
+		if name != caseExportManifestFile && name != caseExportSignatureFile && !listed[name] {
+			unlisted = append(unlisted, name)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, name := range unlisted {
+		report.check("manifest entry for "+name, errors.New("file is not covered by the manifest"))
+	}
+
+	var metadata caseExportMetadata
+	if b, ok := files[caseExportCaseFile]; ok {
+		if err := json.Unmarshal(b, &metadata); err != nil {
+			report.check("decoding "+caseExportCaseFile, err)
+		}
+	}
+
+	var payment *pb.Order_Payment
+	for _, c := range []struct {
+		name  string
+		buyer bool
+	}{
+		{caseExportBuyerContractFile, true},
+		{caseExportVendorContractFile, false},
+	} {
+		b, ok := files[c.name]
+		if !ok {
+			continue
+		}
+		contract := new(pb.RicardianContract)
+		if err := jsonpb.UnmarshalString(string(b), contract); err != nil {
+			report.check("decoding "+c.name, err)
+			continue
+		}
+		verifyCaseExportContract(report, c.name, contract, c.buyer)
+		if contract.BuyerOrder != nil && contract.BuyerOrder.Payment != nil && payment == nil {
+			payment = contract.BuyerOrder.Payment
+		}
+	}
+
+	if b, ok := files[caseExportResolutionFile]; ok {
+		signed := new(pb.RicardianContract)
+		if err := jsonpb.UnmarshalString(string(b), signed); err != nil {
+			report.check("decoding "+caseExportResolutionFile, err)
+		} else {
+			report.check("moderator's signature on dispute resolution", verifyCaseExportResolution(signed, payment, metadata, manifest))
+		}
+	}
+	return report, nil
+}
+
+func verifyCaseExportManifest(manifestBytes, sig []byte, manifest CaseExportManifest) error {
+	pubkey, err := libp2p.UnmarshalPublicKey(manifest.ExporterPubkey)
+	if err != nil {
+		return err
+	}
+	valid, err := pubkey.Verify(manifestBytes, sig)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return invalidSigError{}
+	}
+	pid, err := peer.IDB58Decode(manifest.ExportedBy)
+	if err != nil {
+		return err
+	}
+	if !pid.MatchesPublicKey(pubkey) {
+		return matchKeyError{}
+	}
+	return nil
+}
+
+// verifyCaseExportContract checks the signatures in the contract of the
+// buyer, or of the vendor. The party who opened the dispute signed it in
+// their contract.
+func verifyCaseExportContract(report *CaseExportReport, name string, contract *pb.RicardianContract, buyer bool) {
+	if len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil || contract.VendorListings[0].VendorID.Pubkeys == nil {
+		report.check(name+": vendor ID", errors.New("contract is missing the vendor ID information"))
+		return
+	}
+	if contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil || contract.BuyerOrder.BuyerID.Pubkeys == nil {
+		report.check(name+": buyer ID", errors.New("contract is missing the buyer ID information"))
+		return
+	}
+
+	var listingSigs []*pb.Signature
+	for _, sig := range contract.Signatures {
+		if sig.Section == pb.Signature_LISTING {
+			listingSigs = append(listingSigs, sig)
+		}
+	}
+	for i, listing := range contract.VendorListings {
+		description := fmt.Sprintf("%s: vendor's signature on listing %d", name, i)
+		if i >= len(listingSigs) {
+			report.check(description, errors.New("contract does not contain a signature for the listing"))
+			continue
+		}
+		report.check(description, verifySignaturesOnListing(&pb.SignedListing{
+			Listing:   listing,
+			Signature: listingSigs[i].SignatureBytes,
+		}))
+	}
+	report.check(name+": buyer's signature on order", verifySignaturesOnOrder(contract))
+	if contract.VendorOrderConfirmation != nil {
+		report.check(name+": vendor's signature on order confirmation", verifySignaturesOnOrderConfirmation(contract))
+	}
+	if len(contract.VendorOrderFulfillment) > 0 {
+		report.check(name+": vendor's signature on order fulfillment", verifySignaturesOnOrderFulfilment(contract))
+	}
+	if contract.BuyerOrderCompletion != nil {
+		report.check(name+": buyer's signature on order completion", verifySignaturesOnOrderCompletion(contract))
+	}
+	if contract.Refund != nil {
+		report.check(name+": vendor's signature on refund", verifyCaseExportSection(contract.Refund, contract.Signatures, pb.Signature_REFUND, contract.VendorListings[0].VendorID))
+	}
+	if contract.Dispute != nil {
+		party, disputer := "vendor", contract.VendorListings[0].VendorID
+		if buyer {
+			party, disputer = "buyer", contract.BuyerOrder.BuyerID
+		}
+		report.check(name+": "+party+"'s signature on dispute", verifyCaseExportSection(contract.Dispute, contract.Signatures, pb.Signature_DISPUTE, disputer))
+	}
+}
+
+// verifyCaseExportSection checks the signature of the party with id on a
+// section of a contract
+func verifyCaseExportSection(msg proto.Message, sigs []*pb.Signature, section pb.Signature_Section, id *pb.ID) error {
+	if id.Pubkeys == nil {
+		return errors.New("contract is missing the signer's public key")
+	}
+	if err := verifyMessageSignature(msg, id.Pubkeys.Identity, sigs, section, id.PeerID); err != nil {
+		switch err.(type) {
+		case noSigError:
+			return fmt.Errorf("contract does not contain a signature for the %s", section)
+		case invalidSigError:
+			return fmt.Errorf("signature on the %s failed to verify", section)
+		case matchKeyError:
+			return errors.New("public key in contract does not match the signer's ID")
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyCaseExportResolution checks the resolution against the key of the
+// moderator of the order who signed it: the exporter's key if they signed
+// it, otherwise the key the exporter included with the case
+func verifyCaseExportResolution(signed *pb.RicardianContract, payment *pb.Order_Payment, metadata caseExportMetadata, manifest CaseExportManifest) error {
+	if signed.DisputeResolution == nil {
+		return errors.New("resolution file does not contain a dispute resolution")
+	}
+	if payment == nil || payment.Moderator == "" {
+		return errors.New("no contract names the moderator of this case")
+	}
+	signer := resolutionSigner(signed.DisputeResolution, payment)
+	pubkey := metadata.ResolutionSignerPubkey
+	if signer == manifest.ExportedBy {
+		pubkey = manifest.ExporterPubkey
+	} else if metadata.ResolutionSigner != signer || len(pubkey) == 0 {
+		return errors.New("bundle does not include the public key of the moderator who signed the resolution")
+	}
+	if err := verifyMessageSignature(
+		signed.DisputeResolution,
+		pubkey,
+		signed.Signatures,
+		pb.Signature_DISPUTE_RESOLUTION,
+		signer,
+	); err != nil {
+		switch err.(type) {
+		case noSigError:
+			return errors.New("resolution does not contain the moderator's signature")
+		case invalidSigError:
+			return errors.New("moderator's signature on resolution failed to verify")
+		case matchKeyError:
+			return errors.New("public key in bundle does not match the moderator ID")
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// resolutionSigner returns the moderator who signed a resolution: the panel
+// member who proposed it, or the moderator of the order
+func resolutionSigner(d *pb.DisputeResolution, payment *pb.Order_Payment) string {
+	if d.ProposedBy != "" && isOrderModerator(payment, d.ProposedBy) {
+		return d.ProposedBy
+	}
+	return payment.Moderator
+}
+
+// signedCaseResolution returns the resolution of a closed case with the
+// signatures it was sent to the parties with, or nil if they were not
+// recorded with the contracts
+func signedCaseResolution(buyerContract, vendorContract *pb.RicardianContract) *pb.RicardianContract {
+	for _, contract := range []*pb.RicardianContract{buyerContract, vendorContract} {
+		if contract == nil || contract.DisputeResolution == nil {
+			continue
+		}
+		signed := &pb.RicardianContract{DisputeResolution: contract.DisputeResolution}
+		for _, sig := range contract.Signatures {
+			if sig.Section == pb.Signature_DISPUTE_RESOLUTION {
+				signed.Signatures = append(signed.Signatures, sig)
+			}
+		}
+		if len(signed.Signatures) > 0 {
+			return signed
+		}
+	}
+	return nil
+}
+
+func casePayment(buyerContract, vendorContract *pb.RicardianContract) *pb.Order_Payment {
+	for _, contract := range []*pb.RicardianContract{buyerContract, vendorContract} {
+		if contract != nil && contract.BuyerOrder != nil && contract.BuyerOrder.Payment != nil {
+			return contract.BuyerOrder.Payment
+		}
+	}
+	return nil
+}
+
+// casePeerPubkey returns the identity key of a peer, or nil if it can't be
+// found. Like EncryptMessage it falls back to the key cache and a lookup if
+// the peer is not in the peerstore.
+func (n *OpenBazaarNode) casePeerPubkey(peerID string) []byte {
+	var pubkey libp2p.PubKey
+	if peerID == n.IpfsNode.Identity.Pretty() {
+		pubkey = n.IpfsNode.PrivateKey.GetPublic()
+	} else {
+		id, err := peer.IDB58Decode(peerID)
+		if err != nil {
+			return nil
+		}
+		pubkey = n.IpfsNode.Peerstore.PubKey(id)
+		if pubkey == nil {
+			if keyval, err := n.IpfsNode.Repo.Datastore().Get(datastore.NewKey(KeyCachePrefix + peerID)); err == nil {
+				return keyval
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), caseExportEvidenceTimeout)
+			defer cancel()
+			if pubkey, err = routing.GetPublicKey(n.IpfsNode.Routing, ctx, id); err != nil {
+				log.Warningf("unable to find the public key of %s: %s", peerID, err)
+				return nil
+			}
+		}
+	}
+	b, err := pubkey.Bytes()
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// caseListingImages returns the IPFS hashes of the listing images in the
+// contracts
+func caseListingImages(buyerContract, vendorContract *pb.RicardianContract) []string {
+	var (
+		hashes []string
+		seen   = make(map[string]bool)
+	)
+	for _, contract := range []*pb.RicardianContract{buyerContract, vendorContract} {
+		if contract == nil {
+			continue
+		}
+		for _, listing := range contract.VendorListings {
+			if listing.Item == nil {
+				continue
+			}
+			for _, image := range listing.Item.Images {
+				if image.Original != "" && !seen[image.Original] {
+					seen[image.Original] = true
+					hashes = append(hashes, image.Original)
+				}
+			}
+		}
+	}
+	return hashes
+}
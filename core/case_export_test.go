@@ -0,0 +1,165 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	crypto "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/phoreproject/openbazaar-go/core"
+)
+
+// buildCaseExport signs a manifest covering files and zips it together with
+// extra, which are written to the bundle without being listed
+func buildCaseExport(t *testing.T, files, extra map[string][]byte) []byte {
+	sk, pk, err := crypto.GenerateKeyPairWithReader(crypto.Ed25519, 256, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkBytes, err := pk.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := core.CaseExportManifest{
+		Version:        core.CaseExportVersion,
+		OrderID:        "QmOrder",
+		ExportedBy:     id.Pretty(),
+		ExporterPubkey: pkBytes,
+		Timestamp:      time.Now().UTC(),
+	}
+	for name, b := range files {
+		digest := sha256.Sum256(b)
+		manifest.Files = append(manifest.Files, core.CaseExportFile{Path: name, SHA256: hex.EncodeToString(digest[:])})
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.Sign(manifestBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	write := func(name string, b []byte) {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("manifest.json", manifestBytes)
+	write("manifest.sig", sig)
+	for name, b := range files {
+		write(name, b)
+	}
+	for name, b := range extra {
+		write(name, b)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyCaseExport(t *testing.T) {
+	files := map[string][]byte{
+		"chat.json":        []byte(`[{"message": "the item never arrived"}]`),
+		"evidence/QmPhoto": []byte("photo"),
+	}
+	report, err := core.VerifyCaseExport(buildCaseExport(t, files, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Valid() {
+		t.Errorf("expected untampered bundle to verify, got %+v", report.Checks)
+	}
+	if report.OrderID != "QmOrder" {
+		t.Errorf("expected order ID QmOrder, got %s", report.OrderID)
+	}
+}
+
+func TestVerifyCaseExportDetectsUnlistedFiles(t *testing.T) {
+	files := map[string][]byte{"chat.json": []byte(`[]`)}
+	extra := map[string][]byte{"evidence/QmForged": []byte("forged")}
+	report, err := core.VerifyCaseExport(buildCaseExport(t, files, extra))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid() {
+		t.Error("expected bundle with a file outside the manifest to fail verification")
+	}
+}
+
+func TestVerifyCaseExportDetectsTampering(t *testing.T) {
+	bundle := buildCaseExport(t, map[string][]byte{"chat.json": []byte(`["original"]`)}, nil)
+
+	// Rewrite the bundle with a modified transcript
+	zr, err := zip.NewReader(bytes.NewReader(bundle), int64(len(bundle)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := new(bytes.Buffer)
+		if _, err := b.ReadFrom(rc); err != nil {
+			t.Fatal(err)
+		}
+		rc.Close()
+		content := b.Bytes()
+		if f.Name == "chat.json" {
+			content = []byte(`["tampered"]`)
+		}
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := core.VerifyCaseExport(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Valid() {
+		t.Error("expected tampered transcript to fail verification")
+	}
+}
+
+func TestVerifyCaseExportRequiresManifest(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	if _, err := zw.Create("chat.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := core.VerifyCaseExport(buf.Bytes()); err != core.ErrCaseExportMissingManifest {
+		t.Errorf("expected ErrCaseExportMissingManifest, got %v", err)
+	}
+}
@@ -0,0 +1,722 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+)
+
+// caseHandoverMaxFeeMultiple bounds the fee per byte a moderator may take
+// from the escrow it moves to a backup moderator, relative to our own
+// priority fee
+const caseHandoverMaxFeeMultiple = 2
+
+var (
+	// ErrCaseHandoverNotFound is returned when no handover exists for an order
+	ErrCaseHandoverNotFound = errors.New("case handover not found")
+	// ErrCaseHandoverNotAccepted is returned when a backup moderator tries to
+	// resolve a dispute before both parties accepted the handover
+	ErrCaseHandoverNotAccepted = errors.New("case handover has not been accepted by both the buyer and the vendor")
+	// ErrCaseHandedOver is returned when the original moderator tries to act
+	// on a case whose escrow was moved to a backup moderator
+	ErrCaseHandedOver = errors.New("case has been handed over to a backup moderator")
+)
+
+// RequestCaseHandover asks the buyer and the vendor of an open dispute to
+// accept a backup moderator. The escrow only takes our key, so the handover
+// comes with our signatures on a transaction moving the escrow to a new one
+// held by the buyer, the vendor and the backup moderator. Each party signs
+// it when accepting, and once both have accepted our node broadcasts it and
+// forwards the case to the backup moderator, who then resolves the dispute
+// with its own key.
+func (n *OpenBazaarNode) RequestCaseHandover(orderID, backupModerator string) error {
+	dispute, err := n.Datastore.Cases().GetByCaseID(orderID)
+	if err != nil {
+		return ErrCaseNotFound
+	}
+	if dispute.OrderState != pb.OrderState_DISPUTED {
+		return errors.New("a dispute for this order is not open")
+	}
+	if dispute.IsExpiredNow() {
+		return ErrCloseFailureCaseExpired
+	}
+	if n.caseHandedOver(orderID) {
+		return ErrCaseHandedOver
+	}
+	if _, err := peer.IDB58Decode(backupModerator); err != nil {
+		return errors.New("invalid backup moderator peer ID")
+	}
+
+	contract := dispute.Contract()
+	if contract == nil || contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil || contract.BuyerOrder.Payment == nil || len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil {
+		return errors.New("case is missing the order contract")
+	}
+	if IsPanelOrder(contract.BuyerOrder.Payment) {
+		return errors.New("cases moderated by a panel cannot be handed over")
+	}
+	buyerID := contract.BuyerOrder.BuyerID.PeerID
+	vendorID := contract.VendorListings[0].VendorID.PeerID
+	if backupModerator == n.IpfsNode.Identity.Pretty() || backupModerator == buyerID || backupModerator == vendorID {
+		return errors.New("backup moderator must not be a party to the dispute")
+	}
+	outpoints := dispute.ResolutionPaymentOutpoints(repo.PayoutRatio{Buyer: 100})
+	if len(outpoints) == 0 {
+		return ErrCloseFailureNoOutpoints
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	_, redeemScript, err := n.caseHandoverEscrow(wal, contract, backupModerator)
+	if err != nil {
+		return err
+	}
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	handover := &pb.CaseHandover{
+		OrderId:            orderID,
+		Moderator:          n.IpfsNode.Identity.Pretty(),
+		BackupModerator:    backupModerator,
+		Timestamp:          ts,
+		RedeemScript:       hex.EncodeToString(redeemScript),
+		TransferInputs:     outpoints,
+		TransferFeePerByte: wal.GetFeePerByte(wallet.NORMAL),
+	}
+	release, err := n.caseHandoverTransfer(wal, contract, handover)
+	if err != nil {
+		return err
+	}
+	sigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	handover.ModeratorSigs = bitcoinSignatures(sigs)
+
+	sd, err := n.signData(handover)
+	if err != nil {
+		return err
+	}
+	err = n.Datastore.CaseHandovers().Put(&repo.CaseHandoverRecord{
+		OrderID:         orderID,
+		Moderator:       handover.Moderator,
+		BackupModerator: backupModerator,
+		BuyerID:         buyerID,
+		VendorID:        vendorID,
+		SignedHandover:  sd,
+		Timestamp:       time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := n.SendCaseHandover(buyerID, sd); err != nil {
+		return err
+	}
+	return n.SendCaseHandover(vendorID, sd)
+}
+
+// ProcessCaseHandover handles a signed handover from a moderator. Buyers and
+// vendors check the escrow transfer that comes with it and record it so
+// they can accept or reject it, while the backup moderator opens the
+// forwarded case so that it can resolve it.
+func (n *OpenBazaarNode) ProcessCaseHandover(sd *pb.SignedData) error {
+	handover := new(pb.CaseHandover)
+	sender, err := verifySignedData(sd, handover)
+	if err != nil {
+		return err
+	}
+	if handover.Moderator != sender.Pretty() {
+		return errors.New("case handover was not signed by the moderator")
+	}
+
+	record := &repo.CaseHandoverRecord{
+		OrderID:         handover.OrderId,
+		Moderator:       handover.Moderator,
+		BackupModerator: handover.BackupModerator,
+		SignedHandover:  sd,
+		Timestamp:       time.Now(),
+	}
+
+	if handover.BackupModerator == n.IpfsNode.Identity.Pretty() {
+		contract := handover.BuyerContract
+		if contract == nil {
+			contract = handover.VendorContract
+		}
+		if contract == nil {
+			return errors.New("case handover does not include the order contract")
+		}
+		if err := setCaseHandoverParties(record, contract, handover.Moderator); err != nil {
+			return err
+		}
+		if err := n.openHandedOverCase(contract, handover); err != nil {
+			return err
+		}
+		record.BuyerAccepted = true
+		record.VendorAccepted = true
+	} else {
+		contract, state, _, records, _, err := n.getDisputeFallbackOrder(handover.OrderId)
+		if err != nil {
+			return ErrOrderNotFound
+		}
+		if err := setCaseHandoverParties(record, contract, handover.Moderator); err != nil {
+			return err
+		}
+		if state != pb.OrderState_DISPUTED {
+			return errors.New("a dispute for this order is not open")
+		}
+		if err := n.checkCaseHandoverTransfer(contract, records, handover); err != nil {
+			return err
+		}
+		// A resent handover keeps our response. A new request starts over.
+		if existing, err := n.Datastore.CaseHandovers().Get(handover.OrderId); err == nil && existing.SignedHandover != nil && bytes.Equal(existing.SignedHandover.Signature, sd.Signature) {
+			record.BuyerAccepted, record.BuyerRejected = existing.BuyerAccepted, existing.BuyerRejected
+			record.VendorAccepted, record.VendorRejected = existing.VendorAccepted, existing.VendorRejected
+		}
+	}
+	if err := n.Datastore.CaseHandovers().Put(record); err != nil {
+		return err
+	}
+	notif := repo.CaseHandoverNotification{
+		ID:              repo.NewNotificationID(),
+		Type:            repo.NotifierTypeCaseHandover,
+		OrderID:         handover.OrderId,
+		ModeratorID:     handover.Moderator,
+		BackupModerator: handover.BackupModerator,
+	}
+	n.Broadcast <- notif
+	n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, time.Now(), false))
+	return nil
+}
+
+// RespondToCaseHandover accepts or rejects a pending handover as the buyer or
+// vendor of the order and notifies the original moderator. Accepting signs
+// the transfer of the escrow to the backup moderator and withdraws an
+// earlier rejection.
+func (n *OpenBazaarNode) RespondToCaseHandover(orderID string, accept bool) error {
+	record, err := n.Datastore.CaseHandovers().Get(orderID)
+	if err != nil {
+		return ErrCaseHandoverNotFound
+	}
+	if !record.SetResponse(n.IpfsNode.Identity.Pretty(), accept) {
+		return errors.New("only the buyer or the vendor can respond to a case handover")
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	response := &pb.CaseHandoverResponse{
+		OrderId:         orderID,
+		BackupModerator: record.BackupModerator,
+		Accepted:        accept,
+		Timestamp:       ts,
+	}
+	if accept {
+		contract, _, _, _, _, err := n.getDisputeFallbackOrder(orderID)
+		if err != nil {
+			return ErrOrderNotFound
+		}
+		handover, err := storedCaseHandover(record)
+		if err != nil {
+			return err
+		}
+		wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+		if err != nil {
+			return err
+		}
+		release, err := n.caseHandoverTransfer(wal, contract, handover)
+		if err != nil {
+			return err
+		}
+		sigs, err := n.SignEscrowRelease(wal, release)
+		if err != nil {
+			return err
+		}
+		response.TransferSigs = bitcoinSignatures(sigs)
+		if err := wal.AddWatchedAddress(release.Outputs[0].Address); err != nil {
+			return err
+		}
+	}
+	sd, err := n.signData(response)
+	if err != nil {
+		return err
+	}
+	// Record the response before sending it so that it survives a crash.
+	// Responding again resends it if the moderator could not be reached.
+	if err := n.Datastore.CaseHandovers().Put(record); err != nil {
+		return err
+	}
+	return n.SendCaseHandoverResponse(record.Moderator, sd)
+}
+
+// ProcessCaseHandoverResponse records a buyer or vendor response to one of
+// our handovers. Once both parties have accepted, the escrow is moved to the
+// backup moderator and the case is forwarded to it.
+func (n *OpenBazaarNode) ProcessCaseHandoverResponse(sd *pb.SignedData) error {
+	response := new(pb.CaseHandoverResponse)
+	sender, err := verifySignedData(sd, response)
+	if err != nil {
+		return err
+	}
+	record, err := n.Datastore.CaseHandovers().Get(response.OrderId)
+	if err != nil {
+		return ErrCaseHandoverNotFound
+	}
+	if record.Moderator != n.IpfsNode.Identity.Pretty() {
+		return errors.New("received a handover response for a case we did not hand over")
+	}
+	if record.BackupModerator != response.BackupModerator {
+		return errors.New("handover response is for a different backup moderator")
+	}
+	if record.TransferTxid != "" {
+		return ErrCaseHandedOver
+	}
+	if !record.SetResponse(sender.Pretty(), response.Accepted) {
+		return errors.New("handover response was not sent by the buyer or the vendor")
+	}
+	if response.Accepted {
+		if err := n.checkCaseHandoverResponse(record, sender.Pretty(), response); err != nil {
+			return err
+		}
+		record.TransferSigs = response.TransferSigs
+	}
+	if err := n.Datastore.CaseHandovers().Put(record); err != nil {
+		return err
+	}
+	notif := repo.CaseHandoverResponseNotification{
+		ID:       repo.NewNotificationID(),
+		Type:     repo.NotifierTypeCaseHandoverResponse,
+		OrderID:  response.OrderId,
+		PeerID:   sender.Pretty(),
+		Accepted: response.Accepted,
+	}
+	n.Broadcast <- notif
+	n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, time.Now(), false))
+
+	if record.IsAccepted() {
+		return n.transferCaseToBackupModerator(record)
+	}
+	return nil
+}
+
+// checkCaseHandoverResponse verifies a party's signatures on the escrow
+// transfer of one of our handovers
+func (n *OpenBazaarNode) checkCaseHandoverResponse(record *repo.CaseHandoverRecord, peerID string, response *pb.CaseHandoverResponse) error {
+	dispute, err := n.Datastore.Cases().GetByCaseID(record.OrderID)
+	if err != nil {
+		return ErrCaseNotFound
+	}
+	contract := dispute.Contract()
+	handover, err := storedCaseHandover(record)
+	if err != nil {
+		return err
+	}
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	release, err := n.caseHandoverTransfer(wal, contract, handover)
+	if err != nil {
+		return err
+	}
+	masterKey := contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin
+	if peerID == record.VendorID {
+		masterKey = contract.VendorListings[0].VendorID.Pubkeys.Bitcoin
+	}
+	key, err := wal.ChildKey(masterKey, release.Chaincode, false)
+	if err != nil {
+		return err
+	}
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return err
+	}
+	return n.verifyEscrowSigs(wal, release, response.TransferSigs, pubKey.SerializeCompressed())
+}
+
+// transferCaseToBackupModerator broadcasts the transfer of the escrow of an
+// accepted handover and forwards the case to the backup moderator, along
+// with our signatures on the buyer's rating keys since ratings are checked
+// against our escrow key
+func (n *OpenBazaarNode) transferCaseToBackupModerator(record *repo.CaseHandoverRecord) error {
+	dispute, err := n.Datastore.Cases().GetByCaseID(record.OrderID)
+	if err != nil {
+		return ErrCaseNotFound
+	}
+	contract := dispute.Contract()
+	handover, err := storedCaseHandover(record)
+	if err != nil {
+		return err
+	}
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	release, err := n.caseHandoverTransfer(wal, contract, handover)
+	if err != nil {
+		return err
+	}
+	// Both parties' keys come before ours in the escrow
+	rawTx, err := wal.Multisign(release.Inputs, release.Outputs, walletSignatures(record.TransferSigs), walletSignatures(handover.ModeratorSigs), release.RedeemScript, release.FeePerByte, true)
+	if err != nil {
+		return err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return err
+	}
+	record.TransferTxid = tx.TxHash().String()
+	if err := n.Datastore.CaseHandovers().Put(record); err != nil {
+		return err
+	}
+	var escrowOutpoints []*pb.Outpoint
+	for i, out := range tx.TxOut {
+		escrowOutpoints = append(escrowOutpoints, &pb.Outpoint{Hash: record.TransferTxid, Index: uint32(i), Value: uint64(out.Value)})
+	}
+
+	var ratingSigs [][]byte
+	if dispute.BuyerContract != nil {
+		ratingSigs, err = n.moderatorRatingSigs(dispute.BuyerContract)
+		if err != nil {
+			return err
+		}
+	}
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	sd, err := n.signData(&pb.CaseHandover{
+		OrderId:             record.OrderID,
+		Moderator:           record.Moderator,
+		BackupModerator:     record.BackupModerator,
+		Timestamp:           ts,
+		BuyerContract:       dispute.BuyerContract,
+		VendorContract:      dispute.VendorContract,
+		RedeemScript:        handover.RedeemScript,
+		BuyerPayoutAddress:  dispute.BuyerPayoutAddress,
+		VendorPayoutAddress: dispute.VendorPayoutAddress,
+		EscrowOutpoints:     escrowOutpoints,
+		ModeratorRatingSigs: ratingSigs,
+		Claim:               dispute.Claim,
+	})
+	if err != nil {
+		return err
+	}
+	return n.SendCaseHandover(record.BackupModerator, sd)
+}
+
+// openHandedOverCase checks that a forwarded case is held in an escrow with
+// our key and saves it as one of our own cases
+func (n *OpenBazaarNode) openHandedOverCase(contract *pb.RicardianContract, handover *pb.CaseHandover) error {
+	if len(handover.EscrowOutpoints) == 0 {
+		return errors.New("case handover does not include the escrow")
+	}
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	addr, redeemScript, err := n.caseHandoverEscrow(wal, contract, handover.BackupModerator)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(redeemScript) != handover.RedeemScript {
+		return errors.New("case handover escrow does not take our key")
+	}
+	if err := wal.AddWatchedAddress(addr); err != nil {
+		return err
+	}
+
+	orderID := handover.OrderId
+	err = n.Datastore.Cases().Put(orderID, pb.OrderState_DISPUTED, handover.BuyerContract != nil, handover.Claim, db.PaymentCoinForContract(contract), db.CoinTypeForContract(contract))
+	if err != nil {
+		return err
+	}
+	if handover.BuyerContract != nil {
+		err = n.Datastore.Cases().UpdateBuyerInfo(orderID, handover.BuyerContract, nil, handover.BuyerPayoutAddress, handover.EscrowOutpoints)
+		if err != nil {
+			return err
+		}
+	}
+	if handover.VendorContract != nil {
+		err = n.Datastore.Cases().UpdateVendorInfo(orderID, handover.VendorContract, nil, handover.VendorPayoutAddress, handover.EscrowOutpoints)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveCaseHandover closes a case handed over to us. The escrow was moved
+// to one holding our key, so we close it like any other case and the
+// original moderator is told it was resolved.
+func (n *OpenBazaarNode) ResolveCaseHandover(orderID string, buyerPercentage, vendorPercentage float32, resolution string) error {
+	record, err := n.Datastore.CaseHandovers().Get(orderID)
+	if err != nil {
+		return ErrCaseHandoverNotFound
+	}
+	if record.BackupModerator != n.IpfsNode.Identity.Pretty() {
+		return errors.New("only the backup moderator can resolve a handed over case")
+	}
+	if !record.IsAccepted() {
+		return ErrCaseHandoverNotAccepted
+	}
+	dispute, err := n.Datastore.Cases().GetByCaseID(orderID)
+	if err != nil {
+		return ErrCaseNotFound
+	}
+	return n.CloseDispute(orderID, buyerPercentage, vendorPercentage, resolution, dispute.PaymentCoin)
+}
+
+// sendCaseHandoverResolution tells the original moderator of a case handed
+// over to us how we resolved it
+func (n *OpenBazaarNode) sendCaseHandoverResolution(handover *pb.CaseHandover, buyerPercentage, vendorPercentage float32, resolution string) error {
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	sd, err := n.signData(&pb.CaseHandoverResolution{
+		OrderId:          handover.OrderId,
+		BuyerPercentage:  buyerPercentage,
+		VendorPercentage: vendorPercentage,
+		Resolution:       resolution,
+		Timestamp:        ts,
+	})
+	if err != nil {
+		return err
+	}
+	return n.SendCaseHandoverResolution(handover.Moderator, sd)
+}
+
+// ProcessCaseHandoverResolution closes our copy of a case once the backup
+// moderator it was handed over to has resolved it
+func (n *OpenBazaarNode) ProcessCaseHandoverResolution(sd *pb.SignedData) error {
+	resolution := new(pb.CaseHandoverResolution)
+	sender, err := verifySignedData(sd, resolution)
+	if err != nil {
+		return err
+	}
+	record, err := n.Datastore.CaseHandovers().Get(resolution.OrderId)
+	if err != nil {
+		return ErrCaseHandoverNotFound
+	}
+	if record.Moderator != n.IpfsNode.Identity.Pretty() || record.BackupModerator != sender.Pretty() {
+		return errors.New("resolution was not sent by the backup moderator of this case")
+	}
+	if record.TransferTxid == "" {
+		return ErrCaseHandoverNotAccepted
+	}
+	return n.Datastore.Cases().MarkAsClosed(resolution.OrderId, &pb.DisputeResolution{
+		Timestamp:  resolution.Timestamp,
+		OrderId:    resolution.OrderId,
+		ProposedBy: sender.Pretty(),
+		Resolution: resolution.Resolution,
+	})
+}
+
+// caseHandoverEscrow returns the escrow a dispute handed over to the backup
+// moderator is moved to. It takes the buyer's and vendor's keys of the
+// order's escrow, the backup moderator's key derived with the order's
+// chaincode, and the same escrow timeout.
+func (n *OpenBazaarNode) caseHandoverEscrow(wal wallet.Wallet, contract *pb.RicardianContract, backupModerator string) (btcutil.Address, []byte, error) {
+	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+	if err != nil {
+		return nil, nil, err
+	}
+	vendorKey, err := wal.ChildKey(contract.VendorListings[0].VendorID.Pubkeys.Bitcoin, chaincode, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	buyerKey, err := wal.ChildKey(contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin, chaincode, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	backupKey, err := n.resolveModeratorEscrowKey(wal, backupModerator, chaincode)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout, err := time.ParseDuration(strconv.Itoa(int(contract.VendorListings[0].Metadata.EscrowTimeoutHours)) + "h")
+	if err != nil {
+		return nil, nil, err
+	}
+	return wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *backupKey}, 2, timeout, vendorKey)
+}
+
+// caseHandoverTransfer returns the transaction moving the escrow of a
+// handover to the backup moderator. It fails unless the handover's escrow
+// is the one caseHandoverEscrow builds for the order.
+func (n *OpenBazaarNode) caseHandoverTransfer(wal wallet.Wallet, contract *pb.RicardianContract, handover *pb.CaseHandover) (EscrowRelease, error) {
+	addr, redeemScript, err := n.caseHandoverEscrow(wal, contract, handover.BackupModerator)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	if hex.EncodeToString(redeemScript) != handover.RedeemScript {
+		return EscrowRelease{}, errors.New("case handover escrow does not match the order")
+	}
+	var (
+		inputs []wallet.TransactionInput
+		total  int64
+	)
+	for _, o := range handover.TransferInputs {
+		hash, err := hex.DecodeString(o.Hash)
+		if err != nil {
+			return EscrowRelease{}, err
+		}
+		inputs = append(inputs, wallet.TransactionInput{
+			OutpointHash:  hash,
+			OutpointIndex: o.Index,
+			Value:         int64(o.Value),
+		})
+		total += int64(o.Value)
+	}
+	if len(inputs) == 0 {
+		return EscrowRelease{}, errors.New("transaction has no inputs")
+	}
+	outputs := []wallet.TransactionOutput{{Address: addr, Value: total}}
+	return n.NewEscrowRelease(contract, SigningActionCaseHandover, inputs, outputs, handover.TransferFeePerByte)
+}
+
+// checkCaseHandoverTransfer checks, as the buyer or vendor, that a handover
+// moves the whole escrow of our order at a reasonable fee and carries the
+// moderator's signatures
+func (n *OpenBazaarNode) checkCaseHandoverTransfer(contract *pb.RicardianContract, records []*wallet.TransactionRecord, handover *pb.CaseHandover) error {
+	if IsPanelOrder(contract.BuyerOrder.Payment) {
+		return errors.New("cases moderated by a panel cannot be handed over")
+	}
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	release, err := n.caseHandoverTransfer(wal, contract, handover)
+	if err != nil {
+		return err
+	}
+	if outpoints, _ := unspentEscrowOutpoints(records); !spendsOutpoints(handover.TransferInputs, outpoints) {
+		return errors.New("case handover does not move the whole escrow")
+	}
+	if handover.TransferFeePerByte > wal.GetFeePerByte(wallet.PRIOIRTY)*caseHandoverMaxFeeMultiple {
+		return errors.New("case handover fee is too high")
+	}
+	return n.verifyEscrowSigs(wal, release, handover.ModeratorSigs, contract.BuyerOrder.Payment.ModeratorKey)
+}
+
+// handedOverEscrow returns the handover that moved the escrow of an order to
+// the given backup moderator, or nil if we have not accepted one
+func (n *OpenBazaarNode) handedOverEscrow(orderID, backupModerator string) *pb.CaseHandover {
+	record, err := n.Datastore.CaseHandovers().Get(orderID)
+	if err != nil || backupModerator == "" || record.BackupModerator != backupModerator {
+		return nil
+	}
+	switch n.IpfsNode.Identity.Pretty() {
+	case record.BuyerID:
+		if !record.BuyerAccepted {
+			return nil
+		}
+	case record.VendorID:
+		if !record.VendorAccepted {
+			return nil
+		}
+	case record.BackupModerator:
+		if !record.IsAccepted() {
+			return nil
+		}
+	default:
+		return nil
+	}
+	handover, err := storedCaseHandover(record)
+	if err != nil || handover.RedeemScript == "" {
+		return nil
+	}
+	return handover
+}
+
+// caseHandedOver returns true if we moved the escrow of one of our cases to
+// a backup moderator
+func (n *OpenBazaarNode) caseHandedOver(orderID string) bool {
+	record, err := n.Datastore.CaseHandovers().Get(orderID)
+	return err == nil && record.Moderator == n.IpfsNode.Identity.Pretty() && record.TransferTxid != ""
+}
+
+// storedCaseHandover returns the handover a record was saved with
+func storedCaseHandover(record *repo.CaseHandoverRecord) (*pb.CaseHandover, error) {
+	if record.SignedHandover == nil {
+		return nil, errors.New("case handover was not recorded")
+	}
+	handover := new(pb.CaseHandover)
+	if err := proto.Unmarshal(record.SignedHandover.SerializedData, handover); err != nil {
+		return nil, err
+	}
+	return handover, nil
+}
+
+// setCaseHandoverParties fills in the buyer and vendor of the record after
+// checking the handover came from the moderator named in the contract
+func setCaseHandoverParties(record *repo.CaseHandoverRecord, contract *pb.RicardianContract, moderator string) error {
+	if contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil || contract.BuyerOrder.Payment == nil || len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil {
+		return errors.New("order contract is incomplete")
+	}
+	if contract.BuyerOrder.Payment.Moderator != moderator {
+		return errors.New("case handover was not sent by the order's moderator")
+	}
+	record.BuyerID = contract.BuyerOrder.BuyerID.PeerID
+	record.VendorID = contract.VendorListings[0].VendorID.PeerID
+	return nil
+}
+
+func (n *OpenBazaarNode) signData(msg proto.Message) (*pb.SignedData, error) {
+	pubkeyBytes, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	ser, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SignedData{
+		SenderPubkey:   pubkeyBytes,
+		SerializedData: ser,
+		Signature:      sig,
+	}, nil
+}
+
+// verifySignedData checks the signature on the data, unmarshals it into msg
+// and returns the ID of the signer
+func verifySignedData(sd *pb.SignedData, msg proto.Message) (peer.ID, error) {
+	pubkey, err := libp2p.UnmarshalPublicKey(sd.SenderPubkey)
+	if err != nil {
+		return "", err
+	}
+	good, err := pubkey.Verify(sd.SerializedData, sd.Signature)
+	if err != nil || !good {
+		return "", errors.New("bad signature")
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil {
+		return "", err
+	}
+	if err := proto.Unmarshal(sd.SerializedData, msg); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,92 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/test/factory"
+)
+
+func signedCaseHandoverResolution(t *testing.T, key libp2p.PrivKey, resolution *pb.CaseHandoverResolution) *pb.SignedData {
+	ser, err := proto.Marshal(resolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.SignedData{SenderPubkey: pubkey, SerializedData: ser, Signature: sig}
+}
+
+func TestHandedOverCaseIsResolvedByBackupModerator(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupKey, backupID := newTestPeer(t)
+	strangerKey, _ := newTestPeer(t)
+
+	dispute := factory.NewDisputeCaseRecord()
+	dispute.CaseID = "QmHandedOverCase"
+	dispute.Timestamp = time.Now()
+	paymentCoin := repo.CurrencyCode("BTC")
+	dispute.PaymentCoin = &paymentCoin
+	if err := node.Datastore.Cases().PutRecord(dispute); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Datastore.Cases().Delete(dispute.CaseID)
+	record := &repo.CaseHandoverRecord{
+		OrderID:         dispute.CaseID,
+		Moderator:       node.IpfsNode.Identity.Pretty(),
+		BackupModerator: backupID.Pretty(),
+		BuyerAccepted:   true,
+		VendorAccepted:  true,
+		Timestamp:       time.Now(),
+		TransferTxid:    "txid",
+	}
+	if err := node.Datastore.CaseHandovers().Put(record); err != nil {
+		t.Fatal(err)
+	}
+	defer node.Datastore.CaseHandovers().Delete(dispute.CaseID)
+
+	// The escrow has moved so we can no longer close the case ourselves
+	if err := node.CloseDispute(dispute.CaseID, 100, 0, "refund", &paymentCoin); err != core.ErrCaseHandedOver {
+		t.Errorf("expected ErrCaseHandedOver closing the case, got %v", err)
+	}
+	if err := node.RequestCaseHandover(dispute.CaseID, backupID.Pretty()); err != core.ErrCaseHandedOver {
+		t.Errorf("expected ErrCaseHandedOver handing the case over again, got %v", err)
+	}
+
+	resolution := &pb.CaseHandoverResolution{
+		OrderId:         dispute.CaseID,
+		BuyerPercentage: 100,
+		Resolution:      "refund",
+		Timestamp:       ptypes.TimestampNow(),
+	}
+	if err := node.ProcessCaseHandoverResolution(signedCaseHandoverResolution(t, strangerKey, resolution)); err == nil {
+		t.Error("expected a resolution from outside the handover to be rejected")
+	}
+	if err := node.ProcessCaseHandoverResolution(signedCaseHandoverResolution(t, backupKey, resolution)); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := node.Datastore.Cases().GetByCaseID(dispute.CaseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed.OrderState != pb.OrderState_RESOLVED {
+		t.Errorf("expected the case to be resolved, got %s", closed.OrderState)
+	}
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	gonet "net"
+	"net/http"
+	"strings"
+	"time"
+
+	ma "gx/ipfs/QmTZBfrPJmjWsCvHEtX5FE6KimVJhsJg5sBbqEFYf4UZtL/go-multiaddr"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	mh "gx/ipfs/QmerPMzPk1mJVowm8KgmoknWa4yCYvvugMPsgWmDNUvDLW/go-multihash"
+
+	"github.com/phoreproject/openbazaar-go/ipfs"
+	"github.com/phoreproject/openbazaar-go/net"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// ChatAttachmentsMax - limit for attachments on a chat message
+	ChatAttachmentsMax = 5
+	// ChatAttachmentMaxSize - limit in bytes for a chat attachment
+	ChatAttachmentMaxSize = 10 << 20
+	// ChatAttachmentFilenameMaxCharacters - limit for an attachment's filename
+	ChatAttachmentFilenameMaxCharacters = 255
+
+	chatAttachmentFetchTimeout = time.Minute * 5
+)
+
+// ErrChatAttachmentNotFound is returned when a message has no attachment
+// at the requested index
+var ErrChatAttachmentNotFound = errors.New("chat attachment not found")
+
+// PrepareChatAttachments encrypts each attachment to the recipient, leaves
+// it in the offline message storage and returns the references to send in
+// the chat message. The hash, size and location of each attachment are
+// filled in so they can be saved with the outgoing message.
+func (n *OpenBazaarNode) PrepareChatAttachments(peerID string, attachments []repo.ChatAttachment) ([]*pb.Chat_Attachment, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+	if len(attachments) > ChatAttachmentsMax {
+		return nil, fmt.Errorf("a chat message may have at most %d attachments", ChatAttachmentsMax)
+	}
+	p, err := peer.IDB58Decode(peerID)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*pb.Chat_Attachment
+	for i := range attachments {
+		a := &attachments[i]
+		if len(a.Data) == 0 {
+			return nil, errors.New("chat attachment is empty")
+		}
+		if len(a.Data) > ChatAttachmentMaxSize {
+			return nil, fmt.Errorf("chat attachment %s is larger than %d bytes", a.Filename, ChatAttachmentMaxSize)
+		}
+		if len(a.Filename) > ChatAttachmentFilenameMaxCharacters {
+			return nil, errors.New("chat attachment filename is too long")
+		}
+		if a.MediaType == "" {
+			a.MediaType = http.DetectContentType(a.Data)
+		}
+		hash, err := mh.Sum(a.Data, mh.SHA2_256, -1)
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, err := n.EncryptMessage(p, nil, a.Data)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := n.MessageStorage.Store(p, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+		a.Index = i
+		a.Size = uint64(len(a.Data))
+		a.Hash = hash.B58String()
+		a.Location = addr.String()
+		ret = append(ret, &pb.Chat_Attachment{
+			Filename:  a.Filename,
+			MediaType: a.MediaType,
+			Size:      a.Size,
+			Hash:      a.Hash,
+			Location:  a.Location,
+		})
+	}
+	return ret, nil
+}
+
+// ChatAttachmentsFromMessage returns the attachments referenced by an
+// incoming chat message
+func ChatAttachmentsFromMessage(chat *pb.Chat) ([]repo.ChatAttachment, error) {
+	if len(chat.Attachments) > ChatAttachmentsMax {
+		return nil, errors.New("chat message has too many attachments")
+	}
+	var ret []repo.ChatAttachment
+	for i, a := range chat.Attachments {
+		if a.Size > ChatAttachmentMaxSize {
+			return nil, errors.New("chat attachment is too large")
+		}
+		if len(a.Filename) > ChatAttachmentFilenameMaxCharacters {
+			return nil, errors.New("chat attachment filename is too long")
+		}
+		if _, err := mh.FromB58String(a.Hash); err != nil {
+			return nil, errors.New("chat attachment has an invalid hash")
+		}
+		if _, err := ma.NewMultiaddr(a.Location); err != nil {
+			return nil, errors.New("chat attachment has an invalid location")
+		}
+		ret = append(ret, repo.ChatAttachment{
+			Index:     i,
+			Filename:  a.Filename,
+			MediaType: a.MediaType,
+			Size:      a.Size,
+			Hash:      a.Hash,
+			Location:  a.Location,
+		})
+	}
+	return ret, nil
+}
+
+// FetchChatAttachment returns an attachment of a chat message with its file,
+// downloading and decrypting the file if we don't have it yet
+func (n *OpenBazaarNode) FetchChatAttachment(messageID string, index int) (*repo.ChatAttachment, error) {
+	a, err := n.Datastore.Chat().GetAttachment(messageID, index)
+	if err != nil {
+		return nil, ErrChatAttachmentNotFound
+	}
+	if len(a.Data) > 0 {
+		return a, nil
+	}
+
+	ciphertext, err := n.fetchChatAttachmentCiphertext(a.Location)
+	if err != nil {
+		return nil, err
+	}
+	data, err := net.Decrypt(n.IpfsNode.PrivateKey, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := mh.Sum(data, mh.SHA2_256, -1)
+	if err != nil {
+		return nil, err
+	}
+	if hash.B58String() != a.Hash || uint64(len(data)) != a.Size {
+		return nil, errors.New("chat attachment does not match its hash")
+	}
+	if err := n.Datastore.Chat().PutAttachmentData(messageID, index, data); err != nil {
+		return nil, err
+	}
+	a.Data = data
+	return a, nil
+}
+
+// FetchChatAttachments downloads every attachment of an incoming chat
+// message so it is available once the sender goes offline
+func (n *OpenBazaarNode) FetchChatAttachments(messageID string, count int) {
+	for i := 0; i < count; i++ {
+		if _, err := n.FetchChatAttachment(messageID, i); err != nil {
+			log.Errorf("Error fetching attachment %d of chat message %s: %s", i, messageID, err)
+		}
+	}
+}
+
+// fetchChatAttachmentCiphertext downloads an encrypted attachment from the
+// same kinds of location the message retriever reads offline messages from
+func (n *OpenBazaarNode) fetchChatAttachmentCiphertext(location string) ([]byte, error) {
+	addr, err := ma.NewMultiaddr(location)
+	if err != nil {
+		return nil, err
+	}
+	protocols := addr.Protocols()
+	switch {
+	case len(protocols) == 1 && protocols[0].Code == ma.P_IPFS:
+		return ipfs.Cat(n.IpfsNode, strings.TrimSuffix(addr.String(), "/"), chatAttachmentFetchTimeout)
+	case len(protocols) == 2 && protocols[0].Code == ma.P_IPFS && protocols[1].Code == ma.P_HTTPS:
+		enc, err := addr.ValueForProtocol(ma.P_IPFS)
+		if err != nil {
+			return nil, err
+		}
+		h, err := mh.FromB58String(enc)
+		if err != nil {
+			return nil, err
+		}
+		d, err := mh.Decode(h)
+		if err != nil {
+			return nil, err
+		}
+		dial := gonet.Dial
+		if n.TorDialer != nil {
+			dial = n.TorDialer.Dial
+		}
+		client := &http.Client{Transport: &http.Transport{Dial: dial}, Timeout: chatAttachmentFetchTimeout}
+		resp, err := client.Get(string(d.Digest))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		// Allow for the encryption overhead on top of the largest attachment
+		return ioutil.ReadAll(io.LimitReader(resp.Body, ChatAttachmentMaxSize+4096))
+	}
+	return nil, errors.New("unsupported chat attachment location")
+}
@@ -0,0 +1,53 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+func TestChatAttachmentsFromMessage(t *testing.T) {
+	valid := func() *pb.Chat_Attachment {
+		return &pb.Chat_Attachment{
+			Filename:  "receipt.png",
+			MediaType: "image/png",
+			Size:      1024,
+			Hash:      "QmfQkD8pBSBCBxWEwFSu4XaDVSWK6bjnNuaWZjMyQbyDub",
+			Location:  "/ipfs/QmfQkD8pBSBCBxWEwFSu4XaDVSWK6bjnNuaWZjMyQbyDub",
+		}
+	}
+
+	attachments, err := core.ChatAttachmentsFromMessage(&pb.Chat{Attachments: []*pb.Chat_Attachment{valid(), valid()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 2 || attachments[1].Index != 1 || attachments[1].Filename != "receipt.png" {
+		t.Errorf("unexpected attachments: %+v", attachments)
+	}
+
+	invalid := map[string]func(*pb.Chat_Attachment){
+		"too large": func(a *pb.Chat_Attachment) { a.Size = core.ChatAttachmentMaxSize + 1 },
+		"long filename": func(a *pb.Chat_Attachment) {
+			a.Filename = strings.Repeat("a", core.ChatAttachmentFilenameMaxCharacters+1)
+		},
+		"bad hash":     func(a *pb.Chat_Attachment) { a.Hash = "nothash" },
+		"bad location": func(a *pb.Chat_Attachment) { a.Location = "nowhere" },
+	}
+	for name, mutate := range invalid {
+		a := valid()
+		mutate(a)
+		if _, err := core.ChatAttachmentsFromMessage(&pb.Chat{Attachments: []*pb.Chat_Attachment{a}}); err == nil {
+			t.Errorf("expected an error for an attachment with a %s", name)
+		}
+	}
+
+	var tooMany []*pb.Chat_Attachment
+	for i := 0; i <= core.ChatAttachmentsMax; i++ {
+		tooMany = append(tooMany, valid())
+	}
+	if _, err := core.ChatAttachmentsFromMessage(&pb.Chat{Attachments: tooMany}); err == nil {
+		t.Error("expected an error for too many attachments")
+	}
+}
@@ -0,0 +1,324 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+// ChatReactionMaxCharacters - limit in bytes for a reaction to a chat message
+const ChatReactionMaxCharacters = 32
+
+var (
+	// ErrChatMessageNotFound is returned for a change to an unknown message
+	ErrChatMessageNotFound = errors.New("chat message not found")
+	// ErrNotChatMessageAuthor is returned when someone other than the author
+	// edits or retracts a message
+	ErrNotChatMessageAuthor = errors.New("only the author of a chat message can change it")
+	// ErrStaleChatChange is returned for an edit or retraction older than
+	// one already applied to the message
+	ErrStaleChatChange = errors.New("chat message change is older than the latest change")
+)
+
+// chatChangeNonceBytes is the length of the nonce of a signed edit or
+// retraction
+const chatChangeNonceBytes = 16
+
+// IsChatChange returns true for the flags of chat messages which change an
+// earlier message rather than being a message of their own
+func IsChatChange(flag pb.Chat_Flag) bool {
+	switch flag {
+	case pb.Chat_EDIT, pb.Chat_RETRACT, pb.Chat_REACT, pb.Chat_UNREACT:
+		return true
+	}
+	return false
+}
+
+// EditChatMessage replaces the text of one of our messages on both sides.
+// The edit is signed so the other side can keep it as evidence.
+func (n *OpenBazaarNode) EditChatMessage(messageID, message string) error {
+	if message == "" {
+		return errors.New("chat message is empty")
+	}
+	if len(message) > ChatMessageMaxCharacters {
+		return errors.New("chat message over max characters")
+	}
+	msg, err := n.getOwnChatMessage(messageID)
+	if err != nil {
+		return err
+	}
+	chat, err := newChatChange(msg, pb.Chat_EDIT)
+	if err != nil {
+		return err
+	}
+	chat.Message = message
+	return n.sendSignedChatChange(msg, chat, repo.ChatEditActionEdit)
+}
+
+// RetractChatMessage deletes one of our messages on both sides. Both keep
+// the signed retraction and the previous text in the edit history.
+func (n *OpenBazaarNode) RetractChatMessage(messageID string) error {
+	msg, err := n.getOwnChatMessage(messageID)
+	if err != nil {
+		return err
+	}
+	chat, err := newChatChange(msg, pb.Chat_RETRACT)
+	if err != nil {
+		return err
+	}
+	return n.sendSignedChatChange(msg, chat, repo.ChatEditActionRetract)
+}
+
+// ReactToChatMessage adds or removes a reaction to a message in one of our
+// conversations
+func (n *OpenBazaarNode) ReactToChatMessage(messageID, reaction string, remove bool) error {
+	if err := validateChatReaction(reaction); err != nil {
+		return err
+	}
+	msg, err := n.Datastore.Chat().GetMessage(messageID)
+	if err != nil {
+		return ErrChatMessageNotFound
+	}
+	flag := pb.Chat_REACT
+	if remove {
+		flag = pb.Chat_UNREACT
+	}
+	chat, err := newChatChange(msg, flag)
+	if err != nil {
+		return err
+	}
+	chat.Reaction = reaction
+	if err := n.sendChatChange(msg, chat); err != nil {
+		return err
+	}
+	self := n.IpfsNode.Identity.Pretty()
+	if remove {
+		return n.Datastore.Chat().DeleteReaction(messageID, self, reaction)
+	}
+	return n.Datastore.Chat().PutReaction(messageID, self, reaction, time.Now())
+}
+
+// ProcessChatChange applies an edit, retraction or reaction received from
+// peer p. Changes must come from the same conversation as the message and
+// edits and retractions must be signed by its author.
+func (n *OpenBazaarNode) ProcessChatChange(p peer.ID, chat *pb.Chat) (*repo.ChatChange, error) {
+	msg, err := n.Datastore.Chat().GetMessage(chat.MessageId)
+	if err != nil {
+		return nil, ErrChatMessageNotFound
+	}
+	if msg.GroupId != chat.GroupId || (msg.GroupId == "" && msg.PeerId != p.Pretty()) {
+		return nil, errors.New("chat message is not part of this conversation")
+	}
+	t := time.Now()
+	if chat.Timestamp != nil {
+		if t, err = ptypes.Timestamp(chat.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	switch chat.Flag {
+	case pb.Chat_EDIT, pb.Chat_RETRACT:
+		if msg.Outgoing || msg.PeerId != p.Pretty() {
+			return nil, ErrNotChatMessageAuthor
+		}
+		if err := verifyChatSignature(p, chat); err != nil {
+			return nil, err
+		}
+		if err := n.checkChatChangeOrder(chat, t); err != nil {
+			return nil, err
+		}
+		edit := repo.ChatEdit{
+			MessageId: chat.MessageId,
+			PeerId:    p.Pretty(),
+			Action:    repo.ChatEditActionRetract,
+			Previous:  msg.Message,
+			Timestamp: t,
+		}
+		if chat.Flag == pb.Chat_EDIT {
+			if chat.Message == "" || len(chat.Message) > ChatMessageMaxCharacters {
+				return nil, errors.New("invalid chat message length")
+			}
+			edit.Action = repo.ChatEditActionEdit
+			edit.Message = chat.Message
+		}
+		if edit.SignedChat, err = proto.Marshal(chat); err != nil {
+			return nil, err
+		}
+		if err := n.Datastore.Chat().PutEdit(edit); err != nil {
+			return nil, err
+		}
+	case pb.Chat_REACT, pb.Chat_UNREACT:
+		if err := validateChatReaction(chat.Reaction); err != nil {
+			return nil, err
+		}
+		if chat.Flag == pb.Chat_REACT {
+			err = n.Datastore.Chat().PutReaction(chat.MessageId, p.Pretty(), chat.Reaction, t)
+		} else {
+			err = n.Datastore.Chat().DeleteReaction(chat.MessageId, p.Pretty(), chat.Reaction)
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unknown chat message change")
+	}
+
+	return &repo.ChatChange{
+		MessageId: chat.MessageId,
+		PeerId:    p.Pretty(),
+		GroupId:   chat.GroupId,
+		Subject:   msg.Subject,
+		Action:    strings.ToLower(chat.Flag.String()),
+		Message:   chat.Message,
+		Reaction:  chat.Reaction,
+		Timestamp: t,
+	}, nil
+}
+
+// getOwnChatMessage returns a message we sent
+func (n *OpenBazaarNode) getOwnChatMessage(messageID string) (*repo.ChatMessage, error) {
+	msg, err := n.Datastore.Chat().GetMessage(messageID)
+	if err != nil {
+		return nil, ErrChatMessageNotFound
+	}
+	if !msg.Outgoing {
+		return nil, ErrNotChatMessageAuthor
+	}
+	return msg, nil
+}
+
+func newChatChange(msg *repo.ChatMessage, flag pb.Chat_Flag) (*pb.Chat, error) {
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Chat{
+		MessageId: msg.MessageId,
+		Subject:   msg.Subject,
+		GroupId:   msg.GroupId,
+		Timestamp: ts,
+		Flag:      flag,
+	}, nil
+}
+
+// sendSignedChatChange signs an edit or retraction, sends it and applies it
+// to our own copy. The signature covers a random nonce so the change can't
+// be replayed after a later edit.
+func (n *OpenBazaarNode) sendSignedChatChange(msg *repo.ChatMessage, chat *pb.Chat, action string) error {
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return err
+	}
+	chat.Nonce = make([]byte, chatChangeNonceBytes)
+	if _, err := rand.Read(chat.Nonce); err != nil {
+		return err
+	}
+	// SendChat sets the ratchet flag so it must be set before signing
+	chat.Ratchet = n.RatchetSupported()
+	chat.SenderPubkey = pubkey
+	ser, err := proto.Marshal(chat)
+	if err != nil {
+		return err
+	}
+	if chat.Signature, err = n.IpfsNode.PrivateKey.Sign(ser); err != nil {
+		return err
+	}
+	if err := n.sendChatChange(msg, chat); err != nil {
+		return err
+	}
+	signed, err := proto.Marshal(chat)
+	if err != nil {
+		return err
+	}
+	return n.Datastore.Chat().PutEdit(repo.ChatEdit{
+		MessageId:  msg.MessageId,
+		PeerId:     n.IpfsNode.Identity.Pretty(),
+		Action:     action,
+		Previous:   msg.Message,
+		Message:    chat.Message,
+		SignedChat: signed,
+		Timestamp:  time.Now(),
+	})
+}
+
+func (n *OpenBazaarNode) sendChatChange(msg *repo.ChatMessage, chat *pb.Chat) error {
+	if msg.GroupId != "" {
+		return n.SendGroupChat(msg.GroupId, chat)
+	}
+	return n.SendChat(msg.PeerId, chat)
+}
+
+// checkChatChangeOrder rejects a signed edit or retraction without a nonce,
+// with the nonce of a change we already applied or sent before the latest
+// change we applied, so a delayed edit can't overwrite a newer one
+func (n *OpenBazaarNode) checkChatChangeOrder(chat *pb.Chat, t time.Time) error {
+	if len(chat.Nonce) != chatChangeNonceBytes {
+		return errors.New("chat message change has no nonce")
+	}
+	edits, err := n.Datastore.Chat().GetEdits(chat.MessageId)
+	if err != nil {
+		return err
+	}
+	for _, edit := range edits {
+		applied := new(pb.Chat)
+		if err := proto.Unmarshal(edit.SignedChat, applied); err != nil {
+			continue
+		}
+		if bytes.Equal(applied.Nonce, chat.Nonce) {
+			return errors.New("chat message change was already applied")
+		}
+		// The stored timestamp is truncated to seconds so the signed one
+		// is compared
+		if applied.Timestamp == nil {
+			continue
+		}
+		if appliedAt, err := ptypes.Timestamp(applied.Timestamp); err == nil && t.Before(appliedAt) {
+			return ErrStaleChatChange
+		}
+	}
+	return nil
+}
+
+func validateChatReaction(reaction string) error {
+	if reaction == "" {
+		return errors.New("chat reaction is empty")
+	}
+	if len(reaction) > ChatReactionMaxCharacters {
+		return errors.New("chat reaction over max characters")
+	}
+	return nil
+}
+
+func verifyChatSignature(p peer.ID, chat *pb.Chat) error {
+	pubkey, err := libp2p.UnmarshalPublicKey(chat.SenderPubkey)
+	if err != nil {
+		return err
+	}
+	if !p.MatchesPublicKey(pubkey) {
+		return errors.New("chat message was not signed by its sender")
+	}
+	unsigned := proto.Clone(chat).(*pb.Chat)
+	unsigned.Signature = nil
+	ser, err := proto.Marshal(unsigned)
+	if err != nil {
+		return err
+	}
+	valid, err := pubkey.Verify(ser, chat.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid chat message signature")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func signChat(t *testing.T, key libp2p.PrivKey, chat *pb.Chat) *pb.Chat {
+	return signChatAt(t, key, chat, time.Now())
+}
+
+// signChatAt signs a chat message sent at ts
+func signChatAt(t *testing.T, key libp2p.PrivKey, chat *pb.Chat, ts time.Time) *pb.Chat {
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat.SenderPubkey = pubkey
+	if chat.Timestamp, err = ptypes.TimestampProto(ts); err != nil {
+		t.Fatal(err)
+	}
+	ser, err := proto.Marshal(chat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.Signature, err = key.Sign(ser); err != nil {
+		t.Fatal(err)
+	}
+	return chat
+}
+
+func TestOpenBazaarNode_ProcessChatChange(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorKey, author := newTestPeer(t)
+	otherKey, other := newTestPeer(t)
+
+	// The test repository is shared between runs so the message ID is unique
+	messageID := "message-" + author.Pretty()
+	if err := node.Datastore.Chat().Put(messageID, author.Pretty(), "", "helo", time.Now(), false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	edit := &pb.Chat{MessageId: messageID, Message: "hello", Flag: pb.Chat_EDIT}
+	if _, err := node.ProcessChatChange(author, signChat(t, authorKey, proto.Clone(edit).(*pb.Chat))); err == nil {
+		t.Error("expected an edit without a nonce to be rejected")
+	}
+	edit.Nonce = []byte("edit-nonce-00001")
+	if _, err := node.ProcessChatChange(author, edit); err == nil {
+		t.Error("expected an unsigned edit to be rejected")
+	}
+	if _, err := node.ProcessChatChange(author, signChat(t, otherKey, proto.Clone(edit).(*pb.Chat))); err == nil {
+		t.Error("expected an edit signed by another peer to be rejected")
+	}
+	if _, err := node.ProcessChatChange(other, signChat(t, otherKey, proto.Clone(edit).(*pb.Chat))); err == nil {
+		t.Error("expected an edit from outside the conversation to be rejected")
+	}
+
+	change, err := node.ProcessChatChange(author, signChat(t, authorKey, edit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Action != "edit" || change.Message != "hello" {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	msg, err := node.Datastore.Chat().GetMessage(messageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Message != "hello" {
+		t.Errorf("expected the message to be edited, got %q", msg.Message)
+	}
+
+	// An edit sent before the applied one but delivered after it is ignored
+	stale := signChatAt(t, authorKey, &pb.Chat{MessageId: messageID, Message: "hi", Flag: pb.Chat_EDIT, Nonce: []byte("edit-nonce-00000")}, time.Now().Add(-time.Minute))
+	if _, err := node.ProcessChatChange(author, stale); err != core.ErrStaleChatChange {
+		t.Errorf("expected an older edit to be rejected as stale, got %v", err)
+	}
+
+	// Replaying an earlier edit doesn't undo a later one
+	second := signChat(t, authorKey, &pb.Chat{MessageId: messageID, Message: "hello!", Flag: pb.Chat_EDIT, Nonce: []byte("edit-nonce-00002")})
+	if _, err := node.ProcessChatChange(author, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.ProcessChatChange(author, edit); err == nil {
+		t.Error("expected a replayed edit to be rejected")
+	}
+	if msg, err := node.Datastore.Chat().GetMessage(messageID); err != nil || msg.Message != "hello!" {
+		t.Errorf("expected the later edit to be kept, got %+v, %v", msg, err)
+	}
+
+	react := &pb.Chat{MessageId: messageID, Reaction: "+1", Flag: pb.Chat_REACT}
+	if _, err := node.ProcessChatChange(author, react); err != nil {
+		t.Fatal(err)
+	}
+	messages := node.Datastore.Chat().GetMessages(author.Pretty(), "", "", -1)
+	if len(messages) != 1 || len(messages[0].Reactions) != 1 || !messages[0].Edited {
+		t.Errorf("unexpected messages: %+v", messages)
+	}
+
+	retract := signChat(t, authorKey, &pb.Chat{MessageId: messageID, Flag: pb.Chat_RETRACT, Nonce: []byte("retract-nonce-01")})
+	if _, err := node.ProcessChatChange(author, retract); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.Datastore.Chat().GetMessage(messageID); err == nil {
+		t.Error("expected the message to be deleted")
+	}
+	edits, err := node.Datastore.Chat().GetEdits(messageID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 3 || edits[0].Previous != "helo" || edits[2].Action != repo.ChatEditActionRetract || edits[2].Previous != "hello!" {
+		t.Errorf("unexpected edit history: %+v", edits)
+	}
+	if _, err := node.ProcessChatChange(author, react); err != core.ErrChatMessageNotFound {
+		t.Errorf("expected a change to a retracted message to fail, got %v", err)
+	}
+}
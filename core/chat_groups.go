@@ -0,0 +1,438 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	mh "gx/ipfs/QmerPMzPk1mJVowm8KgmoknWa4yCYvvugMPsgWmDNUvDLW/go-multihash"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// ChatGroupMaxMembers - limit for members of a group conversation including the creator
+	ChatGroupMaxMembers = 50
+	// ChatGroupNameMaxCharacters - limit for the name of a group conversation
+	ChatGroupNameMaxCharacters = 100
+)
+
+var (
+	// ErrChatGroupNotFound is returned for an unknown group conversation
+	ErrChatGroupNotFound = errors.New("chat group not found")
+	// ErrNotChatGroupCreator is returned when someone other than the creator
+	// changes the members of a group conversation
+	ErrNotChatGroupCreator = errors.New("only the creator of a chat group can change its members")
+	// ErrNotChatGroupMember is returned for messages to a group conversation
+	// from a peer which is not one of its members
+	ErrNotChatGroupMember = errors.New("peer is not a member of the chat group")
+	// ErrChatGroupInactive is returned when sending to a group conversation
+	// we have left or been removed from
+	ErrChatGroupInactive = errors.New("chat group is no longer active")
+)
+
+// CreateChatGroup starts a group conversation with the given peers and sends
+// them the signed member list
+func (n *OpenBazaarNode) CreateChatGroup(name string, peerIDs []string) (*repo.ChatGroup, error) {
+	if len(name) > ChatGroupNameMaxCharacters {
+		return nil, errors.New("chat group name over max characters")
+	}
+	self := n.IpfsNode.Identity.Pretty()
+	members, err := n.addChatGroupMembers([]string{self}, peerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) < 2 {
+		return nil, errors.New("a chat group needs at least one other member")
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256([]byte(self + name + strings.Join(members, "") + ptypes.TimestampString(ts)))
+	encoded, err := mh.Encode(h[:], mh.SHA2_256)
+	if err != nil {
+		return nil, err
+	}
+	groupID, err := mh.Cast(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &pb.ChatGroup{
+		GroupId:   groupID.B58String(),
+		Name:      name,
+		Creator:   self,
+		Members:   members,
+		Version:   1,
+		Timestamp: ts,
+	}
+	return n.publishChatGroup(group, pb.GroupMembership_ADD, members[1:], nil)
+}
+
+// AddChatGroupMembers adds peers to a group conversation we created
+func (n *OpenBazaarNode) AddChatGroupMembers(groupID string, peerIDs []string) (*repo.ChatGroup, error) {
+	group, err := n.getOwnChatGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := n.addChatGroupMembers(group.Members, peerIDs)
+	if err != nil {
+		return nil, err
+	}
+	added := members[len(group.Members):]
+	if len(added) == 0 {
+		return nil, errors.New("peers are already members of the chat group")
+	}
+	update, err := n.nextChatGroupVersion(group, members)
+	if err != nil {
+		return nil, err
+	}
+	return n.publishChatGroup(update, pb.GroupMembership_ADD, added, nil)
+}
+
+// RemoveChatGroupMembers removes peers from a group conversation we created.
+// The removed peers are sent the new member list so they know they were
+// removed.
+func (n *OpenBazaarNode) RemoveChatGroupMembers(groupID string, peerIDs []string) (*repo.ChatGroup, error) {
+	group, err := n.getOwnChatGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[string]bool)
+	for _, pid := range peerIDs {
+		if pid == group.Creator {
+			return nil, errors.New("the creator can't be removed from a chat group")
+		}
+		remove[pid] = true
+	}
+	var (
+		members []string
+		removed []string
+	)
+	for _, m := range group.Members {
+		if remove[m] {
+			removed = append(removed, m)
+		} else {
+			members = append(members, m)
+		}
+	}
+	if len(removed) == 0 {
+		return nil, errors.New("peers are not members of the chat group")
+	}
+	update, err := n.nextChatGroupVersion(group, members)
+	if err != nil {
+		return nil, err
+	}
+	return n.publishChatGroup(update, pb.GroupMembership_REMOVE, removed, removed)
+}
+
+// LeaveChatGroup tells the other members we have left a group conversation.
+// If the creator leaves nobody can change the members any more so the
+// group is closed for everyone.
+func (n *OpenBazaarNode) LeaveChatGroup(groupID string) error {
+	group, err := n.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		return ErrChatGroupNotFound
+	}
+	if !group.Active {
+		return nil
+	}
+	membership := &pb.GroupMembership{
+		Action:  pb.GroupMembership_LEAVE,
+		GroupId: groupID,
+	}
+	self := n.IpfsNode.Identity.Pretty()
+	for _, m := range group.Members {
+		if m == self {
+			continue
+		}
+		if err := n.SendGroupMembership(m, membership); err != nil {
+			log.Errorf("Error sending chat group leave to %s: %s", m, err)
+		}
+	}
+	group.Active = false
+	return n.Datastore.Chat().PutGroup(*group)
+}
+
+// SendGroupChat sends a chat message to every other member of a group
+// conversation
+func (n *OpenBazaarNode) SendGroupChat(groupID string, chat *pb.Chat) error {
+	group, err := n.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		return ErrChatGroupNotFound
+	}
+	if !group.Active {
+		return ErrChatGroupInactive
+	}
+	chat.GroupId = groupID
+	self := n.IpfsNode.Identity.Pretty()
+	var errs []string
+	for _, m := range group.Members {
+		if m == self {
+			continue
+		}
+		if err := n.SendChat(m, chat); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", m, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error sending group chat to %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ValidateGroupChat checks that a chat message to a group conversation comes
+// from one of its members
+func (n *OpenBazaarNode) ValidateGroupChat(p peer.ID, groupID string) error {
+	group, err := n.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		return ErrChatGroupNotFound
+	}
+	if !group.Active {
+		return ErrChatGroupInactive
+	}
+	if !group.HasMember(p.Pretty()) {
+		return ErrNotChatGroupMember
+	}
+	return nil
+}
+
+// ProcessGroupMembership validates and saves a membership change received
+// from peer p. Member lists must be signed by the creator and newer than the
+// one we have.
+func (n *OpenBazaarNode) ProcessGroupMembership(p peer.ID, membership *pb.GroupMembership) (*repo.ChatGroupUpdate, error) {
+	if membership.Action == pb.GroupMembership_LEAVE {
+		return n.processChatGroupLeave(p, membership.GroupId)
+	}
+
+	group := new(pb.ChatGroup)
+	if err := proto.Unmarshal(membership.SerializedGroup, group); err != nil {
+		return nil, err
+	}
+	if group.GroupId != membership.GroupId {
+		return nil, errors.New("chat group ID does not match the member list")
+	}
+	if group.Creator != p.Pretty() {
+		return nil, ErrNotChatGroupCreator
+	}
+	if err := verifyChatGroupSignature(membership, group.Creator); err != nil {
+		return nil, err
+	}
+	if len(group.Members) > ChatGroupMaxMembers {
+		return nil, errors.New("chat group has too many members")
+	}
+	if len(group.Name) > ChatGroupNameMaxCharacters {
+		return nil, errors.New("chat group name over max characters")
+	}
+
+	existing, err := n.Datastore.Chat().GetGroup(group.GroupId)
+	if err == nil {
+		if existing.Creator != group.Creator {
+			return nil, ErrNotChatGroupCreator
+		}
+		if group.Version <= existing.Version {
+			return nil, errors.New("chat group member list is out of date")
+		}
+	}
+
+	saved, err := n.saveChatGroup(group, membership)
+	if err != nil {
+		return nil, err
+	}
+	return &repo.ChatGroupUpdate{
+		Group:   *saved,
+		Action:  strings.ToLower(membership.Action.String()),
+		PeerId:  p.Pretty(),
+		PeerIds: membership.PeerIds,
+	}, nil
+}
+
+func (n *OpenBazaarNode) processChatGroupLeave(p peer.ID, groupID string) (*repo.ChatGroupUpdate, error) {
+	group, err := n.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		return nil, ErrChatGroupNotFound
+	}
+	if !group.HasMember(p.Pretty()) {
+		return nil, ErrNotChatGroupMember
+	}
+	var members []string
+	for _, m := range group.Members {
+		if m != p.Pretty() {
+			members = append(members, m)
+		}
+	}
+	group.Members = members
+	if p.Pretty() == group.Creator {
+		group.Active = false
+	}
+	if err := n.Datastore.Chat().PutGroup(*group); err != nil {
+		return nil, err
+	}
+
+	// Sign the new member list for the remaining members
+	if group.Active && group.Creator == n.IpfsNode.Identity.Pretty() {
+		update, err := n.nextChatGroupVersion(group, members)
+		if err != nil {
+			return nil, err
+		}
+		saved, err := n.publishChatGroup(update, pb.GroupMembership_REMOVE, []string{p.Pretty()}, nil)
+		if err != nil {
+			return nil, err
+		}
+		group = saved
+	}
+	return &repo.ChatGroupUpdate{
+		Group:   *group,
+		Action:  strings.ToLower(pb.GroupMembership_LEAVE.String()),
+		PeerId:  p.Pretty(),
+		PeerIds: []string{p.Pretty()},
+	}, nil
+}
+
+// getOwnChatGroup returns an active group conversation we created
+func (n *OpenBazaarNode) getOwnChatGroup(groupID string) (*repo.ChatGroup, error) {
+	group, err := n.Datastore.Chat().GetGroup(groupID)
+	if err != nil {
+		return nil, ErrChatGroupNotFound
+	}
+	if group.Creator != n.IpfsNode.Identity.Pretty() {
+		return nil, ErrNotChatGroupCreator
+	}
+	if !group.Active {
+		return nil, ErrChatGroupInactive
+	}
+	return group, nil
+}
+
+// addChatGroupMembers appends the valid peer IDs which aren't already
+// members
+func (n *OpenBazaarNode) addChatGroupMembers(members []string, peerIDs []string) ([]string, error) {
+	ret := append([]string{}, members...)
+	seen := make(map[string]bool)
+	for _, m := range members {
+		seen[m] = true
+	}
+	for _, pid := range peerIDs {
+		if _, err := peer.IDB58Decode(pid); err != nil {
+			return nil, fmt.Errorf("invalid peer ID %s", pid)
+		}
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		ret = append(ret, pid)
+	}
+	if len(ret) > ChatGroupMaxMembers {
+		return nil, fmt.Errorf("a chat group may have at most %d members", ChatGroupMaxMembers)
+	}
+	return ret, nil
+}
+
+func (n *OpenBazaarNode) nextChatGroupVersion(group *repo.ChatGroup, members []string) (*pb.ChatGroup, error) {
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ChatGroup{
+		GroupId:   group.GroupId,
+		Name:      group.Name,
+		Creator:   group.Creator,
+		Members:   members,
+		Version:   group.Version + 1,
+		Timestamp: ts,
+	}, nil
+}
+
+// publishChatGroup signs a member list, saves it and sends it to the members
+// and any removed peers
+func (n *OpenBazaarNode) publishChatGroup(group *pb.ChatGroup, action pb.GroupMembership_Action, changed []string, removed []string) (*repo.ChatGroup, error) {
+	ser, err := proto.Marshal(group)
+	if err != nil {
+		return nil, err
+	}
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
+	if err != nil {
+		return nil, err
+	}
+	membership := &pb.GroupMembership{
+		Action:          action,
+		GroupId:         group.GroupId,
+		SerializedGroup: ser,
+		CreatorPubkey:   pubkey,
+		Signature:       sig,
+		PeerIds:         changed,
+	}
+	saved, err := n.saveChatGroup(group, membership)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range append(append([]string{}, group.Members...), removed...) {
+		if m == group.Creator {
+			continue
+		}
+		if err := n.SendGroupMembership(m, membership); err != nil {
+			log.Errorf("Error sending chat group membership to %s: %s", m, err)
+		}
+	}
+	return saved, nil
+}
+
+func (n *OpenBazaarNode) saveChatGroup(group *pb.ChatGroup, membership *pb.GroupMembership) (*repo.ChatGroup, error) {
+	ser, err := proto.Marshal(membership)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := ptypes.Timestamp(group.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	saved := repo.ChatGroup{
+		GroupId:    group.GroupId,
+		Name:       group.Name,
+		Creator:    group.Creator,
+		Members:    group.Members,
+		Version:    group.Version,
+		Timestamp:  ts,
+		Membership: ser,
+	}
+	saved.Active = saved.HasMember(n.IpfsNode.Identity.Pretty())
+	if err := n.Datastore.Chat().PutGroup(saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func verifyChatGroupSignature(membership *pb.GroupMembership, creator string) error {
+	pubkey, err := libp2p.UnmarshalPublicKey(membership.CreatorPubkey)
+	if err != nil {
+		return err
+	}
+	id, err := peer.IDFromPublicKey(pubkey)
+	if err != nil {
+		return err
+	}
+	if id.Pretty() != creator {
+		return errors.New("chat group was not signed by its creator")
+	}
+	valid, err := pubkey.Verify(membership.SerializedGroup, membership.Signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid chat group signature")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package core_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func newTestPeer(t *testing.T) (libp2p.PrivKey, peer.ID) {
+	priv, pub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, id
+}
+
+func signedGroupMembership(t *testing.T, key libp2p.PrivKey, action pb.GroupMembership_Action, group *pb.ChatGroup) *pb.GroupMembership {
+	group.Timestamp = ptypes.TimestampNow()
+	ser, err := proto.Marshal(group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.GroupMembership{
+		Action:          action,
+		GroupId:         group.GroupId,
+		SerializedGroup: ser,
+		CreatorPubkey:   pubkey,
+		Signature:       sig,
+	}
+}
+
+func TestOpenBazaarNode_ProcessGroupMembership(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	self := node.IpfsNode.Identity.Pretty()
+	creatorKey, creator := newTestPeer(t)
+	otherKey, other := newTestPeer(t)
+
+	// The test repository is shared between runs so the group ID is unique
+	groupID := "group-" + creator.Pretty()
+	group := &pb.ChatGroup{
+		GroupId: groupID,
+		Name:    "Group",
+		Creator: creator.Pretty(),
+		Members: []string{creator.Pretty(), self},
+		Version: 1,
+	}
+
+	// Only the creator can sign and send the member list
+	if _, err := node.ProcessGroupMembership(other, signedGroupMembership(t, creatorKey, pb.GroupMembership_ADD, group)); err != core.ErrNotChatGroupCreator {
+		t.Errorf("expected a member list from another peer to be rejected, got %v", err)
+	}
+	if _, err := node.ProcessGroupMembership(creator, signedGroupMembership(t, otherKey, pb.GroupMembership_ADD, group)); err == nil {
+		t.Error("expected a member list signed by another peer to be rejected")
+	}
+
+	update, err := node.ProcessGroupMembership(creator, signedGroupMembership(t, creatorKey, pb.GroupMembership_ADD, group))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !update.Group.Active || update.Action != "add" {
+		t.Errorf("unexpected group update: %+v", update)
+	}
+	if err := node.ValidateGroupChat(creator, groupID); err != nil {
+		t.Error(err)
+	}
+	if err := node.ValidateGroupChat(other, groupID); err != core.ErrNotChatGroupMember {
+		t.Errorf("expected a message from a non-member to be rejected, got %v", err)
+	}
+	if _, err := node.ProcessGroupMembership(creator, signedGroupMembership(t, creatorKey, pb.GroupMembership_ADD, group)); err == nil {
+		t.Error("expected an old member list to be rejected")
+	}
+
+	group.Members = append(group.Members, other.Pretty())
+	group.Version = 2
+	if _, err := node.ProcessGroupMembership(creator, signedGroupMembership(t, creatorKey, pb.GroupMembership_ADD, group)); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.ValidateGroupChat(other, groupID); err != nil {
+		t.Error(err)
+	}
+
+	update, err = node.ProcessGroupMembership(other, &pb.GroupMembership{Action: pb.GroupMembership_LEAVE, GroupId: groupID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Group.HasMember(other.Pretty()) || !update.Group.Active {
+		t.Errorf("unexpected group after a member left: %+v", update.Group)
+	}
+
+	group.Members = []string{creator.Pretty()}
+	group.Version = 3
+	update, err = node.ProcessGroupMembership(creator, signedGroupMembership(t, creatorKey, pb.GroupMembership_REMOVE, group))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.Group.Active {
+		t.Error("expected the group to be inactive once we were removed")
+	}
+	if err := node.ValidateGroupChat(creator, groupID); err != core.ErrChatGroupInactive {
+		t.Errorf("expected messages to an inactive group to be rejected, got %v", err)
+	}
+}
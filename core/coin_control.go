@@ -0,0 +1,520 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/signer"
+	obwallet "github.com/phoreproject/openbazaar-go/wallet"
+)
+
+// testnetCoinTypeOffset is added to a coin type to give its testnet coin
+// type. Wallets derive their keys with the mainnet coin type on both networks.
+const testnetCoinTypeOffset = 1000000
+
+// SpendOutput is an amount paid to an address by a spend
+type SpendOutput struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+}
+
+// coinControlWallet is implemented by wallets which can broadcast a
+// transaction we built and signed ourselves
+type coinControlWallet interface {
+	Params() *chaincfg.Params
+	Broadcast(tx *wire.MsgTx) error
+}
+
+// spendEstimator is the part of a wallet used to size a spend
+type spendEstimator interface {
+	EstimateFee(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, feePerByte uint64) uint64
+	IsDust(amount int64) bool
+}
+
+// spendCoin is an unspent output the wallet holds the key for
+type spendCoin struct {
+	utxo wallet.Utxo
+	addr btcutil.Address
+}
+
+// spendPlan is the inputs of a spend and where its value goes
+type spendPlan struct {
+	inputs  []spendCoin
+	outputs []wallet.TransactionOutput
+	change  int64
+	fee     int64
+}
+
+// usesCoinControl returns whether the request chooses the outputs, inputs,
+// change address or fee rate of the spend itself, or only asks what the
+// spend would look like
+func (s *SpendRequest) usesCoinControl() bool {
+	return len(s.Outputs) > 0 || len(s.Utxos) > 0 || s.ChangeAddress != "" || s.FeePerByte > 0 || s.DryRun
+}
+
+// walletsSignSpends returns whether our wallets hold their keys and can
+// sign a spend themselves, which they can't when the keys are held by a
+// signing daemon
+func (n *OpenBazaarNode) walletsSignSpends() bool {
+	_, ok := n.Signer.(signer.EscrowKeyHolder)
+	return ok
+}
+
+// spendWithCoinControl builds the transaction described by a coin control
+// request from the UTXOs in our datastore. A dry run returns the unsigned
+// transaction, otherwise it is signed and broadcast, or exported for
+// signing when the node signs externally.
+func (n *OpenBazaarNode) spendWithCoinControl(wal wallet.Wallet, args *SpendRequest, feeLevel wallet.FeeLevel, contract *pb.RicardianContract) (*SpendResponse, error) {
+	ccw, ok := wal.(coinControlWallet)
+	if !ok {
+		return nil, ErrCoinControlUnsupported
+	}
+
+	requested, outputs, err := spendOutputs(wal, args)
+	if err != nil {
+		return nil, err
+	}
+
+	changeAddr := wal.CurrentAddress(wallet.INTERNAL)
+	if args.ChangeAddress != "" {
+		if changeAddr, err = wal.DecodeAddress(args.ChangeAddress); err != nil {
+			return nil, ErrInvalidSpendAddress
+		}
+	}
+
+	feePerByte := args.FeePerByte
+	if feePerByte == 0 {
+		feePerByte = wal.GetFeePerByte(feeLevel)
+	}
+
+	coins, err := n.spendableCoins(wal)
+	if err != nil {
+		return nil, err
+	}
+	useAll := args.SpendAll
+	if len(args.Utxos) > 0 {
+		if coins, err = selectSpendCoins(coins, args.Utxos); err != nil {
+			return nil, err
+		}
+		useAll = true
+	}
+
+	plan, err := planSpend(wal, coins, outputs, changeAddr, feePerByte, useAll, args.SpendAll)
+	if err != nil {
+		return nil, err
+	}
+	if args.SpendAll {
+		requested[0].Amount = plan.outputs[0].Value
+	}
+
+	tx, prevScripts, err := buildSpendTx(plan, changeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SpendResponse{
+		Fee:        plan.fee,
+		FeePerByte: feePerByte,
+		Outputs:    requested,
+		OrderID:    args.OrderID,
+		DryRun:     args.DryRun,
+	}
+	for _, in := range tx.TxIn {
+		response.Inputs = append(response.Inputs, in.PreviousOutPoint.String())
+	}
+	if plan.change > 0 {
+		response.ChangeAddress = changeAddr.EncodeAddress()
+		response.ChangeAmount = plan.change
+	}
+
+	if args.DryRun {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		response.Transaction = hex.EncodeToString(buf.Bytes())
+		response.Amount = plan.fee
+		for _, out := range plan.outputs {
+			response.Amount += out.Value
+		}
+		response.Memo = args.Memo
+		response.ConfirmedBalance, response.UnconfirmedBalance = wal.Balance()
+		return response, nil
+	}
+
+	if n.ExternalSigning {
+		request, encoded, err := n.exportSpend(wal, args, tx, plan.inputs, requested[0].Address)
+		if err != nil {
+			return nil, err
+		}
+		response.SigningRequest = request.ID
+		response.PSBT = encoded
+		response.Memo = args.Memo
+		response.Timestamp = request.Timestamp
+		return response, nil
+	}
+
+	if err := n.signSpendTx(ccw, wal, tx, plan.inputs, prevScripts); err != nil {
+		return nil, err
+	}
+	if err := ccw.Broadcast(tx); err != nil {
+		return nil, err
+	}
+
+	txid := tx.TxHash()
+	memo, err := n.putSpendMetadata(args, txid.String(), requested[0].Address, contract)
+	if err != nil {
+		return nil, err
+	}
+	sent, err := n.spendResponse(wal, args, txid, memo)
+	if err != nil {
+		return nil, err
+	}
+	sent.Fee = response.Fee
+	sent.FeePerByte = response.FeePerByte
+	sent.Inputs = response.Inputs
+	sent.Outputs = response.Outputs
+	sent.ChangeAddress = response.ChangeAddress
+	sent.ChangeAmount = response.ChangeAmount
+	return sent, nil
+}
+
+// spendOutputs returns the outputs a request pays to, with the single
+// address and amount form of the request first
+func spendOutputs(wal wallet.Wallet, args *SpendRequest) ([]SpendOutput, []wallet.TransactionOutput, error) {
+	var requested []SpendOutput
+	if args.Address != "" {
+		requested = append(requested, SpendOutput{Address: args.Address, Amount: args.Amount})
+	}
+	requested = append(requested, args.Outputs...)
+	if len(requested) == 0 {
+		return nil, nil, ErrInvalidSpendAddress
+	}
+	if args.SpendAll && len(requested) > 1 {
+		return nil, nil, errors.New("spendAll requires a single output")
+	}
+
+	outputs := make([]wallet.TransactionOutput, 0, len(requested))
+	for i, out := range requested {
+		addr, err := wal.DecodeAddress(out.Address)
+		if err != nil {
+			return nil, nil, ErrInvalidSpendAddress
+		}
+		if !args.SpendAll {
+			if out.Amount <= 0 {
+				return nil, nil, ErrInvalidSpendAmount
+			}
+			if wal.IsDust(out.Amount) {
+				return nil, nil, ErrSpendAmountIsDust
+			}
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: out.Amount, Index: uint32(i)})
+	}
+	return requested, outputs, nil
+}
+
+// spendableCoins returns the UTXOs we hold the key for, largest first
+func (n *OpenBazaarNode) spendableCoins(wal wallet.Wallet) ([]spendCoin, error) {
+	store, err := n.walletStore(wal)
+	if err != nil {
+		return nil, err
+	}
+	utxos, err := store.Utxos().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var coins []spendCoin
+	for _, u := range utxos {
+		if u.WatchOnly {
+			continue
+		}
+		addr, err := wal.ScriptToAddress(u.ScriptPubkey)
+		if err != nil || !wal.HasKey(addr) {
+			continue
+		}
+		coins = append(coins, spendCoin{utxo: u, addr: addr})
+	}
+	sort.SliceStable(coins, func(i, j int) bool {
+		return coins[i].utxo.Value > coins[j].utxo.Value
+	})
+	return coins, nil
+}
+
+// selectSpendCoins returns the coins for the requested outpoints, each
+// given as a txid and output index separated by a colon
+func selectSpendCoins(coins []spendCoin, outpoints []string) ([]spendCoin, error) {
+	var (
+		selected []spendCoin
+		seen     = make(map[wire.OutPoint]bool)
+	)
+	for _, s := range outpoints {
+		op, err := parseOutPoint(s)
+		if err != nil {
+			return nil, err
+		}
+		if seen[op] {
+			continue
+		}
+		seen[op] = true
+		found := false
+		for _, c := range coins {
+			if c.utxo.Op == op {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, ErrSpendUtxoUnavailable
+		}
+	}
+	return selected, nil
+}
+
+func parseOutPoint(s string) (wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return wire.OutPoint{}, ErrInvalidSpendUtxo
+	}
+	hash, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return wire.OutPoint{}, ErrInvalidSpendUtxo
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return wire.OutPoint{}, ErrInvalidSpendUtxo
+	}
+	return *wire.NewOutPoint(hash, uint32(index)), nil
+}
+
+// planSpend chooses the inputs of a spend and works out its fee and change.
+// If useAll is set every coin is spent, otherwise coins are taken in order
+// until they cover the outputs and fee. A spendAll request pays everything
+// but the fee to its only output. Change too small to relay goes to the fee.
+func planSpend(est spendEstimator, coins []spendCoin, outputs []wallet.TransactionOutput, changeAddr btcutil.Address, feePerByte uint64, useAll, spendAll bool) (*spendPlan, error) {
+	var target int64
+	for _, out := range outputs {
+		target += out.Value
+	}
+	withChange := append(append([]wallet.TransactionOutput{}, outputs...), wallet.TransactionOutput{Address: changeAddr})
+
+	var (
+		plan  = &spendPlan{outputs: append([]wallet.TransactionOutput{}, outputs...)}
+		total int64
+	)
+	for _, c := range coins {
+		plan.inputs = append(plan.inputs, c)
+		total += c.utxo.Value
+		if !useAll && total >= target+int64(est.EstimateFee(spendInputs(plan.inputs), withChange, feePerByte)) {
+			break
+		}
+	}
+	if len(plan.inputs) == 0 {
+		return nil, ErrInsufficientFunds
+	}
+	ins := spendInputs(plan.inputs)
+
+	if spendAll {
+		plan.fee = int64(est.EstimateFee(ins, plan.outputs, feePerByte))
+		plan.outputs[0].Value = total - plan.fee
+		if plan.outputs[0].Value <= 0 {
+			return nil, ErrInsufficientFunds
+		}
+		if est.IsDust(plan.outputs[0].Value) {
+			return nil, ErrSpendAmountIsDust
+		}
+		return plan, nil
+	}
+
+	fee := int64(est.EstimateFee(ins, withChange, feePerByte))
+	if change := total - target - fee; change > 0 && !est.IsDust(change) {
+		plan.change = change
+		plan.fee = fee
+		return plan, nil
+	}
+	if total < target+int64(est.EstimateFee(ins, plan.outputs, feePerByte)) {
+		return nil, ErrInsufficientFunds
+	}
+	plan.fee = total - target
+	return plan, nil
+}
+
+func spendInputs(coins []spendCoin) []wallet.TransactionInput {
+	ins := make([]wallet.TransactionInput, 0, len(coins))
+	for _, c := range coins {
+		ins = append(ins, wallet.TransactionInput{
+			OutpointHash:  c.utxo.Op.Hash.CloneBytes(),
+			OutpointIndex: c.utxo.Op.Index,
+			LinkedAddress: c.addr,
+			Value:         c.utxo.Value,
+		})
+	}
+	return ins
+}
+
+// buildSpendTx returns the unsigned, BIP69 sorted transaction for a plan and
+// the output script each input spends
+func buildSpendTx(plan *spendPlan, changeAddr btcutil.Address) (*wire.MsgTx, map[wire.OutPoint][]byte, error) {
+	tx := wire.NewMsgTx(1)
+	prevScripts := make(map[wire.OutPoint][]byte)
+	for _, c := range plan.inputs {
+		op := c.utxo.Op
+		tx.AddTxIn(wire.NewTxIn(&op, nil, nil))
+		prevScripts[op] = c.utxo.ScriptPubkey
+	}
+	outputs := plan.outputs
+	if plan.change > 0 {
+		outputs = append(outputs, wallet.TransactionOutput{Address: changeAddr, Value: plan.change})
+	}
+	for _, out := range outputs {
+		script, err := txscript.PayToAddrScript(out.Address)
+		if err != nil {
+			return nil, nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(out.Value, script))
+	}
+	txsort.InPlaceSort(tx)
+	return tx, prevScripts, nil
+}
+
+// signSpendTx signs each input of a spend with the key for the address it
+// spends from. Keys derived from our seed are used by the signer, keys
+// imported into the wallet by the node.
+func (n *OpenBazaarNode) signSpendTx(ccw coinControlWallet, wal wallet.Wallet, tx *wire.MsgTx, coins []spendCoin, prevScripts map[wire.OutPoint][]byte) error {
+	internal, external, err := n.walletChainKeys(wal)
+	if err != nil {
+		return err
+	}
+	store, err := n.walletStore(wal)
+	if err != nil {
+		return err
+	}
+	coinType := uint32(n.walletCoinType(wal))
+
+	for i, in := range tx.TxIn {
+		coin, err := spendCoinFor(coins, in.PreviousOutPoint)
+		if err != nil {
+			return err
+		}
+		hash, err := txscript.CalcSignatureHash(prevScripts[in.PreviousOutPoint], txscript.SigHashAll, tx, i)
+		if err != nil {
+			return err
+		}
+
+		var sig []byte
+		var pubKey *btcec.PublicKey
+		if keyPath, err := store.Keys().GetPathForKey(coin.addr.ScriptAddress()); err == nil {
+			if pubKey, err = spendPublicKey(internal, external, keyPath); err != nil {
+				return err
+			}
+			if sig, err = n.Signer.SignWallet(coinType, uint32(keyPath.Purpose), uint32(keyPath.Index), hash); err != nil {
+				return err
+			}
+		} else if key, err := store.Keys().GetKey(coin.addr.ScriptAddress()); err == nil {
+			signature, err := key.Sign(hash)
+			if err != nil {
+				return err
+			}
+			sig, pubKey = signature.Serialize(), key.PubKey()
+		} else {
+			return ErrSpendUtxoUnavailable
+		}
+
+		derived, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeCompressed()), ccw.Params())
+		if err != nil || derived.EncodeAddress() != coin.addr.EncodeAddress() {
+			return ErrSpendUtxoUnavailable
+		}
+		parsed, err := btcec.ParseDERSignature(sig, btcec.S256())
+		if err != nil || !parsed.Verify(hash, pubKey) {
+			return errors.New("failed to sign transaction")
+		}
+		in.SignatureScript, err = txscript.NewScriptBuilder().
+			AddData(append(sig, byte(txscript.SigHashAll))).
+			AddData(pubKey.SerializeCompressed()).
+			Script()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spendCoinFor returns the coin spent by an outpoint of a spend
+func spendCoinFor(coins []spendCoin, op wire.OutPoint) (*spendCoin, error) {
+	for i := range coins {
+		if coins[i].utxo.Op == op {
+			return &coins[i], nil
+		}
+	}
+	return nil, ErrSpendUtxoUnavailable
+}
+
+// walletCoinType returns the coin type a wallet derives its keys with
+func (n *OpenBazaarNode) walletCoinType(wal wallet.Wallet) util.ExtCoinType {
+	for ct, w := range n.Multiwallet {
+		if w == wal {
+			return ct % testnetCoinTypeOffset
+		}
+	}
+	return 0
+}
+
+// walletStore returns the store a wallet keeps its keys and coins in, which
+// is bound to the coin type the wallet derives its keys with
+func (n *OpenBazaarNode) walletStore(wal wallet.Wallet) (*obwallet.WalletDatastore, error) {
+	sqliteDB, ok := n.Datastore.(*db.SQLiteDatastore)
+	if !ok {
+		return nil, ErrSpendUtxoUnavailable
+	}
+	return obwallet.CreateWalletDB(sqliteDB.DB(), n.walletCoinType(wal)), nil
+}
+
+// walletChainKeys returns the public keys of the internal and external
+// chains of a wallet's BIP44 account, which our signer gives us
+func (n *OpenBazaarNode) walletChainKeys(wal wallet.Wallet) (internal, external *hd.ExtendedKey, err error) {
+	coinType := uint32(n.walletCoinType(wal))
+	pub, err := n.Signer.PublicKeys([]uint32{coinType})
+	if err != nil {
+		return nil, nil, err
+	}
+	account, ok := pub.Accounts[coinType]
+	if !ok {
+		return nil, nil, ErrSpendUtxoUnavailable
+	}
+	if external, err = account.Child(uint32(wallet.EXTERNAL)); err != nil {
+		return nil, nil, err
+	}
+	if internal, err = account.Child(uint32(wallet.INTERNAL)); err != nil {
+		return nil, nil, err
+	}
+	return internal, external, nil
+}
+
+// spendPublicKey returns the public key at a path of a wallet's account
+func spendPublicKey(internal, external *hd.ExtendedKey, keyPath wallet.KeyPath) (*btcec.PublicKey, error) {
+	parent := external
+	if keyPath.Purpose == wallet.INTERNAL {
+		parent = internal
+	}
+	child, err := parent.Child(uint32(keyPath.Index))
+	if err != nil {
+		return nil, err
+	}
+	return child.ECPubKey()
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+type testSpendEstimator struct{}
+
+func (testSpendEstimator) EstimateFee(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, feePerByte uint64) uint64 {
+	return uint64(10+148*len(ins)+34*len(outs)) * feePerByte
+}
+
+func (testSpendEstimator) IsDust(amount int64) bool {
+	return amount < 546
+}
+
+func testSpendCoins(t *testing.T, values ...int64) []spendCoin {
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var coins []spendCoin
+	for i, value := range values {
+		hash := chainhash.DoubleHashH([]byte{byte(i)})
+		coins = append(coins, spendCoin{
+			utxo: wallet.Utxo{Op: *wire.NewOutPoint(&hash, uint32(i)), Value: value},
+			addr: addr,
+		})
+	}
+	return coins
+}
+
+func testSpendOutputs(t *testing.T, values ...int64) []wallet.TransactionOutput {
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var outs []wallet.TransactionOutput
+	for _, value := range values {
+		outs = append(outs, wallet.TransactionOutput{Address: addr, Value: value})
+	}
+	return outs
+}
+
+func TestPlanSpendSelectsCoinsUntilCovered(t *testing.T) {
+	coins := testSpendCoins(t, 50000, 30000, 20000)
+	outs := testSpendOutputs(t, 40000, 20000)
+
+	plan, err := planSpend(testSpendEstimator{}, coins, outs, outs[0].Address, 10, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.inputs) != 2 {
+		t.Fatalf("expected two inputs, got %d", len(plan.inputs))
+	}
+	// 2 inputs and 3 outputs including change
+	if plan.fee != (10+148*2+34*3)*10 {
+		t.Errorf("unexpected fee %d", plan.fee)
+	}
+	if plan.change != 80000-60000-plan.fee {
+		t.Errorf("unexpected change %d", plan.change)
+	}
+}
+
+func TestPlanSpendUsesEveryChosenCoin(t *testing.T) {
+	coins := testSpendCoins(t, 50000, 30000)
+	outs := testSpendOutputs(t, 10000)
+
+	plan, err := planSpend(testSpendEstimator{}, coins, outs, outs[0].Address, 1, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.inputs) != 2 || plan.change != 80000-10000-plan.fee {
+		t.Errorf("unexpected plan: %d inputs, change %d, fee %d", len(plan.inputs), plan.change, plan.fee)
+	}
+}
+
+func TestPlanSpendDustChangeGoesToFee(t *testing.T) {
+	coins := testSpendCoins(t, 10000)
+	outs := testSpendOutputs(t, 10000-(10+148+34)-100)
+
+	plan, err := planSpend(testSpendEstimator{}, coins, outs, outs[0].Address, 1, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.change != 0 || plan.fee != 10000-outs[0].Value {
+		t.Errorf("expected dust change to be added to the fee, got change %d and fee %d", plan.change, plan.fee)
+	}
+}
+
+func TestPlanSpendAll(t *testing.T) {
+	coins := testSpendCoins(t, 50000, 30000)
+	outs := testSpendOutputs(t, 0)
+
+	plan, err := planSpend(testSpendEstimator{}, coins, outs, outs[0].Address, 1, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.change != 0 || plan.outputs[0].Value != 80000-plan.fee {
+		t.Errorf("expected everything but the fee to be sent, got %d", plan.outputs[0].Value)
+	}
+}
+
+func TestPlanSpendInsufficientFunds(t *testing.T) {
+	coins := testSpendCoins(t, 5000)
+	outs := testSpendOutputs(t, 5000)
+
+	if _, err := planSpend(testSpendEstimator{}, coins, outs, outs[0].Address, 1, false, false); err != ErrInsufficientFunds {
+		t.Errorf("expected insufficient funds, got %v", err)
+	}
+	if _, err := planSpend(testSpendEstimator{}, nil, outs, outs[0].Address, 1, false, false); err != ErrInsufficientFunds {
+		t.Errorf("expected insufficient funds without coins, got %v", err)
+	}
+}
+
+func TestSelectSpendCoins(t *testing.T) {
+	coins := testSpendCoins(t, 50000, 30000)
+
+	selected, err := selectSpendCoins(coins, []string{coins[1].utxo.Op.String(), coins[1].utxo.Op.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 1 || selected[0].utxo.Value != 30000 {
+		t.Errorf("unexpected selection: %+v", selected)
+	}
+
+	missing := *wire.NewOutPoint(&coins[0].utxo.Op.Hash, 7)
+	if _, err := selectSpendCoins(coins, []string{missing.String()}); err != ErrSpendUtxoUnavailable {
+		t.Errorf("expected unavailable utxo error, got %v", err)
+	}
+	for _, s := range []string{"nothex:0", coins[0].utxo.Op.Hash.String(), coins[0].utxo.Op.Hash.String() + ":x"} {
+		if _, err := selectSpendCoins(coins, []string{s}); err != ErrInvalidSpendUtxo {
+			t.Errorf("expected invalid utxo error for %q, got %v", s, err)
+		}
+	}
+}
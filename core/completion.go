@@ -145,20 +145,11 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 			return err
 		}
 
-		ratingKey, err := n.MasterPrivateKey.Child(uint32(contract.BuyerOrder.Timestamp.Seconds))
-		if err != nil {
-			return err
-		}
-		ecRatingKey, err := ratingKey.ECPrivKey()
-		if err != nil {
-			return err
-		}
 		hashed := sha256.Sum256(ser)
-		sig, err := ecRatingKey.Sign(hashed[:])
+		rating.Signature, err = n.Signer.SignRating(uint32(contract.BuyerOrder.Timestamp.Seconds), hashed[:])
 		if err != nil {
 			return err
 		}
-		rating.Signature = sig.Serialize()
 		oc.Ratings = append(oc.Ratings, rating)
 	}
 
@@ -201,25 +192,11 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 			Value:   outValue,
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return err
-		}
-		mPrivKey := n.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return err
-		}
-		buyerKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
+		release, err := n.NewEscrowRelease(contract, SigningActionCompletion, ins, []wallet.TransactionOutput{output}, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
-
-		buyerSignatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, buyerKey, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
+		buyerSignatures, err := n.SignEscrowRelease(wal, release)
 		if err != nil {
 			return err
 		}
@@ -236,7 +213,7 @@ func (n *OpenBazaarNode) CompleteOrder(orderRatings *OrderRatings, contract *pb.
 			sig := wallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		_, err = wal.Multisign(ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte, true)
+		err = MultisignEscrow(wal, ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, release.RedeemScript, release.FeePerByte)
 		if err != nil {
 			return err
 		}
@@ -350,24 +327,7 @@ func (n *OpenBazaarNode) ReleaseFundsAfterTimeout(contract *pb.RicardianContract
 		}
 	}
 
-	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-	if err != nil {
-		return err
-	}
-	mPrivKey := n.MasterPrivateKey
-	mECKey, err := mPrivKey.ECPrivKey()
-	if err != nil {
-		return err
-	}
-	vendorKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-	if err != nil {
-		return err
-	}
-	redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-	if err != nil {
-		return err
-	}
-	_, err = wal.SweepAddress(txInputs, nil, vendorKey, &redeemScript, wallet.NORMAL)
+	err = n.SweepEscrow(wal, txInputs, nil, contract)
 	if err != nil {
 		return err
 	}
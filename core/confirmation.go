@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
 )
 
 // NewOrderConfirmation - add order confirmation to the contract
@@ -35,6 +36,17 @@ func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, ad
 		oc.PaymentAddress = addr.EncodeAddress()
 	}
 
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL {
+		if !n.AcceptsPaymentChannel(contract.BuyerOrder.Payment.Coin) {
+			return nil, ErrPaymentChannelUnavailable
+		}
+		invoice, err := n.PaymentChannel.CreateInvoice(orderID, int64(contract.BuyerOrder.Payment.Amount))
+		if err != nil {
+			return nil, err
+		}
+		oc.PaymentInvoice = invoice.PaymentRequest
+	}
+
 	ts, err := ptypes.TimestampProto(time.Now())
 	if err != nil {
 		return nil, err
@@ -80,10 +92,12 @@ func (n *OpenBazaarNode) NewOrderConfirmation(contract *pb.RicardianContract, ad
 	}
 
 	if calculateNewTotal {
-		oc.RequestedAmount, err = n.CalculateOrderTotal(contract)
+		var rates []repo.ExchangeRate
+		oc.RequestedAmount, rates, err = n.CalculateOrderTotalAndRates(contract)
 		if err != nil {
 			return nil, err
 		}
+		n.RecordOrderRates(orderID, rates)
 	} else {
 		oc.RequestedAmount = contract.BuyerOrder.Payment.Amount
 	}
@@ -132,24 +146,7 @@ func (n *OpenBazaarNode) ConfirmOfflineOrder(contract *pb.RicardianContract, rec
 			return errors.New("no unspent transactions found to fund order")
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return err
-		}
-		mPrivKey := n.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return err
-		}
-		vendorKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return err
-		}
-		_, err = wal.SweepAddress(txInputs, nil, vendorKey, &redeemScript, wallet.NORMAL)
+		err = n.SweepEscrow(wal, txInputs, nil, contract)
 		if err != nil {
 			return err
 		}
@@ -212,25 +209,14 @@ func (n *OpenBazaarNode) RejectOfflineOrder(contract *pb.RicardianContract, reco
 			Value:   outValue,
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return fmt.Errorf("decode buyer chaincode: %s", err.Error())
-		}
-		mPrivKey := n.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
+		release, err := n.NewEscrowRelease(contract, SigningActionDecline, ins, []wallet.TransactionOutput{output}, contract.BuyerOrder.RefundFee)
 		if err != nil {
-			return fmt.Errorf("generate ec private key: %s", err.Error())
+			return fmt.Errorf("decode escrow: %s", err.Error())
 		}
-		vendorKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return fmt.Errorf("generate child key: %s", err.Error())
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return fmt.Errorf("generate child key: %s", err.Error())
-		}
-		signatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
-		if err != nil {
+		signatures, err := n.SignEscrowRelease(wal, release)
+		if err == ErrAwaitingExternalSignature {
+			return err
+		} else if err != nil {
 			return fmt.Errorf("generate multisig: %s", err.Error())
 		}
 		var sigs []*pb.BitcoinSignature
@@ -22,7 +22,10 @@ import (
 	rep "github.com/phoreproject/openbazaar-go/net/repointer"
 	ret "github.com/phoreproject/openbazaar-go/net/retriever"
 	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/signer"
 	sto "github.com/phoreproject/openbazaar-go/storage"
+	"github.com/phoreproject/openbazaar-go/wallet/channel"
+	"github.com/phoreproject/openbazaar-go/wallet/exchangerates"
 
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/gosimple/slug"
@@ -110,8 +113,32 @@ type OpenBazaarNode struct {
 	// Generic pubsub interface
 	Pubsub ipfs.Pubsub
 
-	// The master private key derived from the mnemonic
-	MasterPrivateKey *hdkeychain.ExtendedKey
+	// The public key of the master key derived from the mnemonic, which our
+	// Bitcoin, escrow and rating public keys are derived from
+	MasterPublicKey *hdkeychain.ExtendedKey
+
+	// When set every transaction, spends, refunds, escrow releases and
+	// sweeps alike, is exported as a PSBT to be signed on another machine and
+	// imported again instead of being signed by the Signer. The node is
+	// usually watch-only, built from the public keys of its seed.
+	ExternalSigning bool
+
+	// Makes every signature which needs the keys derived from the mnemonic,
+	// and the identity signatures for listings and orders, with the keys
+	// held by the node or by a signing daemon
+	Signer signer.Signer
+
+	// Gives the exchange rates sampled into the rate history. When nil the
+	// rates come from the wallets' exchange rate providers.
+	ExchangeRateSource RateSource
+
+	// The exchange rate feeds orders are priced with. When nil each wallet's
+	// own exchange rate provider is used.
+	ExchangeRateFeeds *exchangerates.Feeds
+
+	// Pays and issues the invoices of payment channel orders through a
+	// local payment channel daemon. Nil when none is configured.
+	PaymentChannel *channel.Wallet
 
 	// The number of DHT records to collect before returning. The larger the number
 	// the slower the query but the less likely we will get an old record.
@@ -173,6 +200,10 @@ func (n *OpenBazaarNode) publish(hash string) {
 		n.Broadcast <- repo.StatusNotification{Status: "publishing"}
 	}
 
+	if err := n.announceRootHash(hash); err != nil {
+		log.Errorf("announcing root hash: %s", err)
+	}
+
 	err := n.sendToPushNodes(hash)
 	if err != nil {
 		log.Error(err)
@@ -262,6 +293,7 @@ func (n *OpenBazaarNode) SetUpRepublisher(interval time.Duration) {
 	go func() {
 		for range ticker.C {
 			n.UpdateFollow()
+			n.UpdateCommentIndex()
 			n.SeedNode()
 		}
 	}()
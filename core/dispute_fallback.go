@@ -0,0 +1,793 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/op/go-logging"
+	"github.com/phoreproject/openbazaar-go/net"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// MaxDisputeFallbackDays is the longest a listing may wait for the
+	// moderator before its dispute fallback can be applied
+	MaxDisputeFallbackDays = 365
+
+	// disputeFallbackClockAllowance tolerates the proposer's clock running
+	// ahead of ours when checking that a fallback is due
+	disputeFallbackClockAllowance = time.Hour
+
+	// disputeFallbackMaxFeeMultiple bounds the fee the proposer may take
+	// out of the escrow relative to our own priority fee estimate
+	disputeFallbackMaxFeeMultiple = 2
+
+	disputeFallbackTestingInterval = time.Duration(5) * time.Minute
+	disputeFallbackRegularInterval = time.Duration(1) * time.Hour
+)
+
+// disputeFallbackStates are the states of an order whose escrow is held and
+// may be paid out by the dispute fallback
+var disputeFallbackStates = []pb.OrderState{
+	pb.OrderState_PENDING,
+	pb.OrderState_AWAITING_FULFILLMENT,
+	pb.OrderState_PARTIALLY_FULFILLED,
+	pb.OrderState_FULFILLED,
+	pb.OrderState_DISPUTED,
+}
+
+var (
+	// ErrNoDisputeFallback is returned when the listing in the contract has
+	// no dispute fallback rule
+	ErrNoDisputeFallback = errors.New("contract has no dispute fallback")
+
+	// ErrDisputeFallbackNotDue is returned when the dispute has not been
+	// open for the number of days set by the fallback rule
+	ErrDisputeFallbackNotDue = errors.New("dispute fallback is not yet due")
+
+	// ErrDisputeFallbackAlreadyProposed is returned when a fallback payout
+	// has already been sent for the order
+	ErrDisputeFallbackAlreadyProposed = errors.New("dispute fallback payout has already been proposed")
+
+	// ErrNotDisputeFallbackProposer is returned when the other party is the
+	// one expected to propose the fallback payout
+	ErrNotDisputeFallbackProposer = errors.New("dispute fallback payout is proposed by the other party")
+
+	// ErrDisputeFallbackUnsupported is returned when the order's currency
+	// can't be paid out by a transaction with a lock time
+	ErrDisputeFallbackUnsupported = errors.New("dispute fallbacks are not supported for this currency")
+)
+
+// DisputeFallbackForContract returns the fallback rule from the listing the
+// buyer ordered, or nil if the vendor didn't set one
+func DisputeFallbackForContract(contract *pb.RicardianContract) *pb.DisputeFallback {
+	if contract == nil || len(contract.VendorListings) == 0 || contract.VendorListings[0].Metadata == nil {
+		return nil
+	}
+	fallback := contract.VendorListings[0].Metadata.DisputeFallback
+	if fallback == nil || fallback.Policy == pb.DisputeFallback_NONE || fallback.Days == 0 {
+		return nil
+	}
+	return fallback
+}
+
+// DisputeFallbackDueAt returns the time from which the contract's dispute
+// fallback may be applied
+func DisputeFallbackDueAt(contract *pb.RicardianContract) (time.Time, error) {
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil {
+		return time.Time{}, ErrNoDisputeFallback
+	}
+	if contract.Dispute == nil || contract.Dispute.Timestamp == nil {
+		return time.Time{}, errors.New("contract has not been disputed")
+	}
+	disputedAt, err := ptypes.Timestamp(contract.Dispute.Timestamp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return disputedAt.Add(time.Duration(fallback.Days) * 24 * time.Hour), nil
+}
+
+// DisputeFallbackProposer returns the peer ID of the party which proposes
+// the fallback payout. The party being paid proposes so that only one side
+// builds the transaction; the vendor proposes a split.
+func DisputeFallbackProposer(contract *pb.RicardianContract) string {
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil {
+		return ""
+	}
+	if fallback.Policy == pb.DisputeFallback_REFUND_BUYER {
+		return contract.BuyerOrder.BuyerID.PeerID
+	}
+	return contract.VendorListings[0].VendorID.PeerID
+}
+
+// DisputeFallbackAmounts divides the escrow left after the transaction fee
+// between the buyer and vendor according to the policy
+func DisputeFallbackAmounts(policy pb.DisputeFallback_Policy, total int64) (buyer, vendor int64) {
+	switch policy {
+	case pb.DisputeFallback_REFUND_BUYER:
+		return total, 0
+	case pb.DisputeFallback_RELEASE_VENDOR:
+		return 0, total
+	case pb.DisputeFallback_SPLIT:
+		buyer = total / 2
+		return buyer, total - buyer
+	}
+	return 0, 0
+}
+
+func validateDisputeFallback(fallback *pb.DisputeFallback) error {
+	if fallback == nil {
+		return nil
+	}
+	if fallback.Policy > pb.DisputeFallback_SPLIT {
+		return errors.New("invalid dispute fallback policy")
+	}
+	if fallback.Policy != pb.DisputeFallback_NONE && (fallback.Days == 0 || fallback.Days > MaxDisputeFallbackDays) {
+		return fmt.Errorf("dispute fallback days must be between 1 and %d", MaxDisputeFallbackDays)
+	}
+	return nil
+}
+
+// ProposeDisputeFallbackPayout signs a payout of a funded escrow following
+// the contract's dispute fallback and sends it to the other party to sign
+// too. Both parties keep the signed payout, which can't be mined before its
+// lock time, so that either can broadcast it if a dispute is not resolved
+// in time without the other's help.
+func (n *OpenBazaarNode) ProposeDisputeFallbackPayout(orderID string) error {
+	contract, state, funded, records, isBuyer, err := n.getDisputeFallbackOrder(orderID)
+	if err != nil {
+		return err
+	}
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil || contract.BuyerOrder.Payment.Method != pb.Order_Payment_MODERATED {
+		return ErrNoDisputeFallback
+	}
+	if !funded || !escrowHeldIn(state) {
+		return errors.New("order escrow is not funded")
+	}
+	if DisputeFallbackProposer(contract) != n.IpfsNode.Identity.Pretty() {
+		return ErrNotDisputeFallbackProposer
+	}
+	outpoints, totalIn := unspentEscrowOutpoints(records)
+	if len(outpoints) == 0 {
+		return errors.New("transaction has no inputs")
+	}
+	if payout := contract.DisputeFallbackPayout; payout != nil && spendsOutpoints(payout.Inputs, outpoints) {
+		return ErrDisputeFallbackAlreadyProposed
+	}
+	lockTime, err := DisputeFallbackLockTime(contract)
+	if err != nil {
+		return err
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	// Bitcoin Cash signs with its own sighash algorithm, which we don't
+	// implement for the transactions we build ourselves
+	if code := strings.ToUpper(wal.CurrencyCode()); code == "BCH" || code == "TBCH" {
+		return ErrDisputeFallbackUnsupported
+	}
+
+	buyerAddr := contract.BuyerOrder.RefundAddress
+	var vendorAddr string
+	if !isBuyer {
+		vendorAddr = wal.CurrentAddress(wallet.EXTERNAL).EncodeAddress()
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	payout := &pb.DisputeFallbackPayout{
+		Timestamp:  ts,
+		OrderId:    orderID,
+		ProposedBy: n.IpfsNode.Identity.Pretty(),
+		Policy:     fallback.Policy,
+		Inputs:     outpoints,
+		LockTime:   lockTime,
+	}
+
+	// Work out the fee using the full escrow value then divide what remains
+	setDisputeFallbackOutputs(payout, buyerAddr, vendorAddr, totalIn)
+	fee, err := n.disputeFallbackFee(wal, contract, payout, wallet.NORMAL)
+	if err != nil {
+		return err
+	}
+	setDisputeFallbackOutputs(payout, buyerAddr, vendorAddr, totalIn-fee)
+	release, err := n.disputeFallbackRelease(wal, contract, payout)
+	if err != nil {
+		return err
+	}
+	for _, output := range release.Outputs {
+		if output.Value <= 0 || wal.IsDust(output.Value) {
+			return errors.New("escrow is too small to pay out after fees")
+		}
+	}
+
+	sigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	for _, sig := range sigs {
+		payout.Sigs = append(payout.Sigs, &pb.BitcoinSignature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+
+	counterparty, counterpartyKey, err := disputeFallbackCounterparty(contract, isBuyer)
+	if err != nil {
+		return err
+	}
+	if err := n.SendDisputeFallbackPayout(counterparty, &counterpartyKey, payout); err != nil {
+		return err
+	}
+	contract.DisputeFallbackPayout = payout
+	return n.putDisputeFallbackOrder(orderID, contract, state, isBuyer)
+}
+
+// ProcessDisputeFallbackPayout handles a fallback payout from the other
+// party. A payout proposed to us is checked against the contract, signed and
+// sent back, and our signed proposal coming back is kept. The order's
+// moderators are sent the payout once it has been broadcast so they can
+// close the case.
+func (n *OpenBazaarNode) ProcessDisputeFallbackPayout(peerID string, payout *pb.DisputeFallbackPayout) error {
+	contract, state, funded, records, isBuyer, err := n.getDisputeFallbackOrder(payout.OrderId)
+	if err != nil {
+		if dispute, err := n.Datastore.Cases().GetByCaseID(payout.OrderId); err == nil {
+			return n.closeDisputeFallbackCase(peerID, dispute, payout)
+		}
+		return net.OutOfOrderMessage
+	}
+	if !funded || !escrowHeldIn(state) {
+		return errors.New("order escrow is not funded")
+	}
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil {
+		return ErrNoDisputeFallback
+	}
+	if payout.Policy != fallback.Policy {
+		return errors.New("dispute fallback payout does not follow the contract's policy")
+	}
+	proposer := DisputeFallbackProposer(contract)
+	counterparty, counterpartyKey, err := disputeFallbackCounterparty(contract, isBuyer)
+	if err != nil {
+		return err
+	}
+	if peerID != counterparty || payout.ProposedBy != proposer {
+		return errors.New("dispute fallback payout was not sent by the expected party")
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	release, err := n.disputeFallbackRelease(wal, contract, payout)
+	if err != nil {
+		return err
+	}
+	pubKeys, _ := escrowScriptKeys(release.RedeemScript)
+	if len(pubKeys) < 2 {
+		return errors.New("invalid escrow script")
+	}
+	// The escrow's first key is the buyer's and its second the vendor's
+	theirKey := pubKeys[1]
+	if !isBuyer {
+		theirKey = pubKeys[0]
+	}
+
+	// Our own proposal coming back with the other party's signatures
+	if proposer == n.IpfsNode.Identity.Pretty() {
+		ours := contract.DisputeFallbackPayout
+		if ours == nil || !sameDisputeFallbackPayout(ours, payout) {
+			return errors.New("dispute fallback payout does not match our proposal")
+		}
+		if err := n.verifyEscrowSigs(wal, release, payout.CounterpartySigs, theirKey); err != nil {
+			return err
+		}
+		ours.CounterpartySigs = payout.CounterpartySigs
+		if err := n.putDisputeFallbackOrder(payout.OrderId, contract, state, isBuyer); err != nil {
+			return err
+		}
+		n.notifyDisputeFallbackPayout(ours, false)
+		return nil
+	}
+
+	if err := n.checkDisputeFallbackPayout(wal, contract, records, payout, release); err != nil {
+		return err
+	}
+	if err := n.verifyEscrowSigs(wal, release, payout.Sigs, theirKey); err != nil {
+		return err
+	}
+	mySigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	payout.CounterpartySigs = nil
+	for _, sig := range mySigs {
+		payout.CounterpartySigs = append(payout.CounterpartySigs, &pb.BitcoinSignature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+	if err := n.SendDisputeFallbackPayout(counterparty, &counterpartyKey, payout); err != nil {
+		return err
+	}
+	contract.DisputeFallbackPayout = payout
+	if err := n.putDisputeFallbackOrder(payout.OrderId, contract, state, isBuyer); err != nil {
+		return err
+	}
+	n.notifyDisputeFallbackPayout(payout, false)
+	return nil
+}
+
+// checkDisputeFallbackPayout checks a payout proposed by the other party
+// spends the whole escrow following the contract's policy, after a fee in
+// range, and can't be mined before the contract's lock time
+func (n *OpenBazaarNode) checkDisputeFallbackPayout(wal wallet.Wallet, contract *pb.RicardianContract, records []*wallet.TransactionRecord, payout *pb.DisputeFallbackPayout, release EscrowRelease) error {
+	lockTime, err := DisputeFallbackLockTime(contract)
+	if err != nil {
+		return err
+	}
+	if payout.LockTime != lockTime {
+		return errors.New("dispute fallback payout has the wrong lock time")
+	}
+
+	// Every input must be an unspent escrow output of this order
+	outpoints, totalIn := unspentEscrowOutpoints(records)
+	if !spendsOutpoints(payout.Inputs, outpoints) {
+		return errors.New("dispute fallback payout does not spend the whole escrow")
+	}
+
+	if payout.BuyerOutput != nil {
+		addr, err := pb.DisputeResolutionPayoutOutputToAddress(wal, payout.BuyerOutput)
+		if err != nil {
+			return err
+		}
+		refundAddr, err := wal.DecodeAddress(contract.BuyerOrder.RefundAddress)
+		if err != nil {
+			return err
+		}
+		if addr.String() != refundAddr.String() {
+			return errors.New("dispute fallback payout does not pay the buyer's refund address")
+		}
+	}
+
+	var buyerAmount, vendorAmount int64
+	if payout.BuyerOutput != nil {
+		buyerAmount = int64(payout.BuyerOutput.Amount)
+	}
+	if payout.VendorOutput != nil {
+		vendorAmount = int64(payout.VendorOutput.Amount)
+	}
+	expectedBuyer, expectedVendor := DisputeFallbackAmounts(payout.Policy, buyerAmount+vendorAmount)
+	if buyerAmount != expectedBuyer || vendorAmount != expectedVendor {
+		return errors.New("dispute fallback payout amounts do not follow the contract's policy")
+	}
+	maxFee, err := n.disputeFallbackFee(wal, contract, payout, wallet.PRIOIRTY)
+	if err != nil {
+		return err
+	}
+	fee := totalIn - buyerAmount - vendorAmount
+	if fee < 0 || fee > maxFee*disputeFallbackMaxFeeMultiple {
+		return errors.New("dispute fallback payout fee is out of range")
+	}
+	return nil
+}
+
+// BroadcastDisputeFallbackPayout broadcasts the payout both parties signed
+// when the order was funded once its dispute has been open for the number
+// of days set by the fallback rule
+func (n *OpenBazaarNode) BroadcastDisputeFallbackPayout(orderID string) error {
+	contract, state, _, records, isBuyer, err := n.getDisputeFallbackOrder(orderID)
+	if err != nil {
+		return err
+	}
+	if state != pb.OrderState_DISPUTED {
+		return errors.New("order is not disputed")
+	}
+	payout := contract.DisputeFallbackPayout
+	if payout == nil || len(payout.CounterpartySigs) == 0 {
+		return ErrNoDisputeFallback
+	}
+	dueAt, err := DisputeFallbackDueAt(contract)
+	if err != nil {
+		return err
+	}
+	if time.Now().Before(dueAt) {
+		return ErrDisputeFallbackNotDue
+	}
+	if outpoints, _ := unspentEscrowOutpoints(records); !spendsOutpoints(payout.Inputs, outpoints) {
+		return errors.New("dispute fallback payout does not spend the whole escrow")
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	release, err := n.disputeFallbackRelease(wal, contract, payout)
+	if err != nil {
+		return err
+	}
+	pubKeys, _ := escrowScriptKeys(release.RedeemScript)
+	sigs := make([][]wallet.Signature, len(pubKeys))
+	proposerSigs, counterpartySigs := walletSignatures(payout.Sigs), walletSignatures(payout.CounterpartySigs)
+	if DisputeFallbackProposer(contract) == contract.BuyerOrder.BuyerID.PeerID {
+		sigs[0], sigs[1] = proposerSigs, counterpartySigs
+	} else {
+		sigs[0], sigs[1] = counterpartySigs, proposerSigs
+	}
+	if err := broadcastEscrowRelease(wal, release, sigs); err != nil {
+		return err
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	contract.DisputeAcceptance = &pb.DisputeAcceptance{
+		Timestamp: ts,
+		ClosedBy:  n.IpfsNode.Identity.Pretty(),
+	}
+	if err := n.putDisputeFallbackOrder(orderID, contract, pb.OrderState_RESOLVED, isBuyer); err != nil {
+		return err
+	}
+	n.notifyDisputeFallbackPayout(payout, true)
+
+	// The moderators can close the case now it has been paid out
+	for _, mod := range PanelModerators(contract.BuyerOrder.Payment) {
+		if err := n.SendDisputeFallbackPayout(mod, nil, payout); err != nil {
+			log.Errorf("sending dispute fallback payout for order %s to moderator %s: %s", orderID, mod, err)
+		}
+	}
+	return nil
+}
+
+// closeDisputeFallbackCase closes our case for an order whose parties paid
+// out the escrow following the contract's dispute fallback
+func (n *OpenBazaarNode) closeDisputeFallbackCase(peerID string, dispute *repo.DisputeCaseRecord, payout *pb.DisputeFallbackPayout) error {
+	contract := dispute.BuyerContract
+	if contract == nil {
+		contract = dispute.VendorContract
+	}
+	if contract == nil || contract.BuyerOrder == nil || contract.BuyerOrder.BuyerID == nil ||
+		len(contract.VendorListings) == 0 || contract.VendorListings[0].VendorID == nil {
+		return errors.New("case is missing the order contract")
+	}
+	if dispute.OrderState != pb.OrderState_DISPUTED {
+		return nil
+	}
+	if peerID != contract.BuyerOrder.BuyerID.PeerID && peerID != contract.VendorListings[0].VendorID.PeerID {
+		return errors.New("dispute fallback payout was not sent by a party to the order")
+	}
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil {
+		return ErrNoDisputeFallback
+	}
+	if payout.Policy != fallback.Policy || payout.ProposedBy != DisputeFallbackProposer(contract) {
+		return errors.New("dispute fallback payout does not follow the contract's policy")
+	}
+	dueAt := dispute.Timestamp.Add(time.Duration(fallback.Days) * 24 * time.Hour)
+	if time.Now().Add(disputeFallbackClockAllowance).Before(dueAt) {
+		return ErrDisputeFallbackNotDue
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	resolution := &pb.DisputeResolution{
+		Timestamp:  ts,
+		OrderId:    payout.OrderId,
+		Resolution: fmt.Sprintf("Paid out by the contract's dispute fallback (%s)", payout.Policy),
+		Payout: &pb.DisputeResolution_Payout{
+			Inputs:       payout.Inputs,
+			BuyerOutput:  payout.BuyerOutput,
+			VendorOutput: payout.VendorOutput,
+		},
+	}
+	if err := n.Datastore.Cases().MarkAsClosed(payout.OrderId, resolution); err != nil {
+		return err
+	}
+	n.notifyDisputeFallbackPayout(payout, true)
+	return nil
+}
+
+// ApplyDueDisputeFallbacks proposes a fallback payout for each funded
+// order we are expected to propose one for and broadcasts the signed payout
+// of each disputed order whose fallback is due
+func (n *OpenBazaarNode) ApplyDueDisputeFallbacks() error {
+	purchases, _, err := n.Datastore.Purchases().GetAll(disputeFallbackStates, "", false, false, -1, nil)
+	if err != nil {
+		return err
+	}
+	sales, _, err := n.Datastore.Sales().GetAll(disputeFallbackStates, "", false, false, -1, nil)
+	if err != nil {
+		return err
+	}
+	var orderIDs []string
+	for _, p := range purchases {
+		orderIDs = append(orderIDs, p.OrderId)
+	}
+	for _, s := range sales {
+		orderIDs = append(orderIDs, s.OrderId)
+	}
+
+	for _, orderID := range orderIDs {
+		switch err := n.ProposeDisputeFallbackPayout(orderID); err {
+		case nil:
+			log.Infof("proposed dispute fallback payout for order %s", orderID)
+		case ErrNoDisputeFallback, ErrDisputeFallbackAlreadyProposed, ErrNotDisputeFallbackProposer:
+		default:
+			log.Errorf("proposing dispute fallback payout for order %s: %s", orderID, err)
+		}
+		switch err := n.BroadcastDisputeFallbackPayout(orderID); err {
+		case nil:
+			log.Infof("broadcast dispute fallback payout for order %s", orderID)
+		default:
+			log.Debugf("dispute fallback payout for order %s not broadcast: %s", orderID, err)
+		}
+	}
+	return nil
+}
+
+type disputeFallbackWorker struct {
+	node          *OpenBazaarNode
+	intervalDelay time.Duration
+	logger        *logging.Logger
+}
+
+// StartDisputeFallbackWorker starts a worker which periodically proposes
+// the fallback payouts of disputes the moderator has not resolved in time
+func (n *OpenBazaarNode) StartDisputeFallbackWorker() {
+	interval := disputeFallbackRegularInterval
+	if n.TestnetEnable {
+		interval = disputeFallbackTestingInterval
+	}
+	worker := &disputeFallbackWorker{
+		node:          n,
+		intervalDelay: interval,
+		logger:        logging.MustGetLogger("disputeFallbackWorker"),
+	}
+	go worker.Run()
+}
+
+func (w *disputeFallbackWorker) Run() {
+	ticker := time.NewTicker(w.intervalDelay)
+	for range ticker.C {
+		if err := w.node.ApplyDueDisputeFallbacks(); err != nil {
+			w.logger.Errorf("applying dispute fallbacks: %s", err)
+		}
+	}
+}
+
+func (n *OpenBazaarNode) getDisputeFallbackOrder(orderID string) (*pb.RicardianContract, pb.OrderState, bool, []*wallet.TransactionRecord, bool, error) {
+	contract, state, funded, records, _, _, err := n.Datastore.Purchases().GetByOrderId(orderID)
+	if err == nil {
+		return contract, state, funded, records, true, nil
+	}
+	contract, state, funded, records, _, _, err = n.Datastore.Sales().GetByOrderId(orderID)
+	if err != nil {
+		return nil, 0, false, nil, false, err
+	}
+	return contract, state, funded, records, false, nil
+}
+
+func (n *OpenBazaarNode) putDisputeFallbackOrder(orderID string, contract *pb.RicardianContract, state pb.OrderState, isBuyer bool) error {
+	if isBuyer {
+		return n.Datastore.Purchases().Put(orderID, *contract, state, false)
+	}
+	return n.Datastore.Sales().Put(orderID, *contract, state, false)
+}
+
+func (n *OpenBazaarNode) notifyDisputeFallbackPayout(payout *pb.DisputeFallbackPayout, completed bool) {
+	notif := repo.DisputeFallbackPayoutNotification{
+		ID:         repo.NewNotificationID(),
+		Type:       repo.NotifierTypeDisputeFallbackPayout,
+		OrderID:    payout.OrderId,
+		Policy:     payout.Policy.String(),
+		ProposedBy: payout.ProposedBy,
+		Completed:  completed,
+	}
+	n.Broadcast <- notif
+	n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, time.Now(), false))
+}
+
+func disputeFallbackCounterparty(contract *pb.RicardianContract, isBuyer bool) (string, libp2p.PubKey, error) {
+	id := contract.VendorListings[0].VendorID
+	if !isBuyer {
+		id = contract.BuyerOrder.BuyerID
+	}
+	key, err := libp2p.UnmarshalPublicKey(id.Pubkeys.Identity)
+	if err != nil {
+		return "", nil, err
+	}
+	return id.PeerID, key, nil
+}
+
+func setDisputeFallbackOutputs(payout *pb.DisputeFallbackPayout, buyerAddr, vendorAddr string, total int64) {
+	buyerAmount, vendorAmount := DisputeFallbackAmounts(payout.Policy, total)
+	payout.BuyerOutput, payout.VendorOutput = nil, nil
+	if buyerAmount > 0 {
+		payout.BuyerOutput = &pb.DisputeResolution_Payout_Output{
+			ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: buyerAddr},
+			Amount:          uint64(buyerAmount),
+		}
+	}
+	if vendorAmount > 0 {
+		payout.VendorOutput = &pb.DisputeResolution_Payout_Output{
+			ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: vendorAddr},
+			Amount:          uint64(vendorAmount),
+		}
+	}
+}
+
+// disputeFallbackTransaction returns the inputs and outputs described by a
+// fallback payout. Both parties build the transaction from the payout so
+// their signatures cover the same transaction.
+func disputeFallbackTransaction(wal wallet.Wallet, payout *pb.DisputeFallbackPayout) ([]wallet.TransactionInput, []wallet.TransactionOutput, error) {
+	var inputs []wallet.TransactionInput
+	for _, o := range payout.Inputs {
+		hash, err := hex.DecodeString(o.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs = append(inputs, wallet.TransactionInput{
+			OutpointHash:  hash,
+			OutpointIndex: o.Index,
+			Value:         int64(o.Value),
+		})
+	}
+	if len(inputs) == 0 {
+		return nil, nil, errors.New("transaction has no inputs")
+	}
+
+	var outputs []wallet.TransactionOutput
+	for _, o := range []*pb.DisputeResolution_Payout_Output{payout.BuyerOutput, payout.VendorOutput} {
+		if o == nil {
+			continue
+		}
+		addr, err := pb.DisputeResolutionPayoutOutputToAddress(wal, o)
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: int64(o.Amount)})
+	}
+	if len(outputs) == 0 {
+		return nil, nil, errors.New("transaction has no outputs")
+	}
+	return inputs, outputs, nil
+}
+
+// disputeFallbackRelease returns the escrow release of a fallback payout
+func (n *OpenBazaarNode) disputeFallbackRelease(wal wallet.Wallet, contract *pb.RicardianContract, payout *pb.DisputeFallbackPayout) (EscrowRelease, error) {
+	inputs, outputs, err := disputeFallbackTransaction(wal, payout)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	release, err := n.NewEscrowRelease(contract, SigningActionFallbackPayout, inputs, outputs, 0)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	release.LockTime = payout.LockTime
+	return release, nil
+}
+
+// disputeFallbackFee returns the fee of a fallback payout at a fee level
+func (n *OpenBazaarNode) disputeFallbackFee(wal wallet.Wallet, contract *pb.RicardianContract, payout *pb.DisputeFallbackPayout, level wallet.FeeLevel) (int64, error) {
+	release, err := n.disputeFallbackRelease(wal, contract, payout)
+	if err != nil {
+		return 0, err
+	}
+	segwit, err := escrowSegwit(wal, release.RedeemScript)
+	if err != nil {
+		return 0, err
+	}
+	release.FeePerByte = wal.GetFeePerByte(level)
+	tx, err := releaseTx(release, segwit)
+	if err != nil {
+		return 0, err
+	}
+	var fee int64
+	for _, out := range release.Outputs {
+		fee += out.Value
+	}
+	for _, out := range tx.TxOut {
+		fee -= out.Value
+	}
+	return fee, nil
+}
+
+// DisputeFallbackLockTime returns the lock time of a contract's fallback
+// payout, the number of days set by the fallback rule after the order. The
+// payout can't be mined earlier, and the parties only broadcast it once a
+// dispute has been open for as long.
+func DisputeFallbackLockTime(contract *pb.RicardianContract) (uint32, error) {
+	fallback := DisputeFallbackForContract(contract)
+	if fallback == nil {
+		return 0, ErrNoDisputeFallback
+	}
+	orderedAt, err := ptypes.Timestamp(contract.BuyerOrder.Timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(orderedAt.Add(time.Duration(fallback.Days) * 24 * time.Hour).Unix()), nil
+}
+
+// escrowHeldIn returns true if the escrow of an order in this state has not
+// been paid out
+func escrowHeldIn(state pb.OrderState) bool {
+	for _, s := range disputeFallbackStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// unspentEscrowOutpoints returns the unspent outputs of an order's escrow
+// and their total value
+func unspentEscrowOutpoints(records []*wallet.TransactionRecord) ([]*pb.Outpoint, int64) {
+	var (
+		outpoints []*pb.Outpoint
+		total     int64
+	)
+	for _, r := range records {
+		if r.Spent || r.Value <= 0 {
+			continue
+		}
+		outpoints = append(outpoints, &pb.Outpoint{Hash: r.Txid, Index: r.Index, Value: uint64(r.Value)})
+		total += r.Value
+	}
+	return outpoints, total
+}
+
+// spendsOutpoints returns true if the inputs are exactly the outpoints
+func spendsOutpoints(inputs, outpoints []*pb.Outpoint) bool {
+	if len(inputs) != len(outpoints) {
+		return false
+	}
+	unspent := make(map[string]uint64)
+	for _, o := range outpoints {
+		unspent[fmt.Sprintf("%s:%d", o.Hash, o.Index)] = o.Value
+	}
+	for _, o := range inputs {
+		value, ok := unspent[fmt.Sprintf("%s:%d", o.Hash, o.Index)]
+		if !ok || value != o.Value {
+			return false
+		}
+		delete(unspent, fmt.Sprintf("%s:%d", o.Hash, o.Index))
+	}
+	return true
+}
+
+// sameDisputeFallbackPayout returns true if two payouts describe the same
+// transaction signed by the same proposer
+func sameDisputeFallbackPayout(a, b *pb.DisputeFallbackPayout) bool {
+	a, b = proto.Clone(a).(*pb.DisputeFallbackPayout), proto.Clone(b).(*pb.DisputeFallbackPayout)
+	a.CounterpartySigs, b.CounterpartySigs = nil, nil
+	return proto.Equal(a, b)
+}
+
+func walletSignatures(sigs []*pb.BitcoinSignature) []wallet.Signature {
+	var out []wallet.Signature
+	for _, sig := range sigs {
+		out = append(out, wallet.Signature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+	return out
+}
+
+func bitcoinSignatures(sigs []wallet.Signature) []*pb.BitcoinSignature {
+	var out []*pb.BitcoinSignature
+	for _, sig := range sigs {
+		out = append(out, &pb.BitcoinSignature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+	return out
+}
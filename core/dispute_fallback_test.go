@@ -0,0 +1,162 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/test/factory"
+)
+
+func TestDisputeFallbackAmounts(t *testing.T) {
+	examples := []struct {
+		policy pb.DisputeFallback_Policy
+		total  int64
+		buyer  int64
+		vendor int64
+	}{
+		{pb.DisputeFallback_NONE, 1000, 0, 0},
+		{pb.DisputeFallback_REFUND_BUYER, 1000, 1000, 0},
+		{pb.DisputeFallback_RELEASE_VENDOR, 1000, 0, 1000},
+		{pb.DisputeFallback_SPLIT, 1000, 500, 500},
+		{pb.DisputeFallback_SPLIT, 1001, 500, 501},
+	}
+	for _, e := range examples {
+		buyer, vendor := core.DisputeFallbackAmounts(e.policy, e.total)
+		if buyer != e.buyer || vendor != e.vendor {
+			t.Errorf("expected %s of %d to pay buyer %d and vendor %d, got %d and %d",
+				e.policy, e.total, e.buyer, e.vendor, buyer, vendor)
+		}
+	}
+}
+
+func TestDisputeFallbackDueAt(t *testing.T) {
+	contract := factory.NewDisputedContract()
+	if _, err := core.DisputeFallbackDueAt(contract); err != core.ErrNoDisputeFallback {
+		t.Errorf("expected ErrNoDisputeFallback without a rule, got %v", err)
+	}
+
+	contract.VendorListings[0].Metadata.DisputeFallback = &pb.DisputeFallback{
+		Policy: pb.DisputeFallback_SPLIT,
+		Days:   30,
+	}
+	disputedAt, err := ptypes.Timestamp(contract.Dispute.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dueAt, err := core.DisputeFallbackDueAt(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := disputedAt.Add(30 * 24 * time.Hour); !dueAt.Equal(expected) {
+		t.Errorf("expected fallback to be due at %s, got %s", expected, dueAt)
+	}
+
+	contract.Dispute = nil
+	if _, err := core.DisputeFallbackDueAt(contract); err == nil {
+		t.Error("expected an error for a contract which has not been disputed")
+	}
+}
+
+func TestDisputeFallbackProposer(t *testing.T) {
+	contract := factory.NewDisputedContract()
+	contract.VendorListings[0].VendorID.PeerID = "vendorID"
+	if proposer := core.DisputeFallbackProposer(contract); proposer != "" {
+		t.Errorf("expected no proposer without a rule, got %s", proposer)
+	}
+
+	examples := map[pb.DisputeFallback_Policy]string{
+		pb.DisputeFallback_REFUND_BUYER:   "buyerID",
+		pb.DisputeFallback_RELEASE_VENDOR: "vendorID",
+		pb.DisputeFallback_SPLIT:          "vendorID",
+	}
+	for policy, expected := range examples {
+		contract.VendorListings[0].Metadata.DisputeFallback = &pb.DisputeFallback{Policy: policy, Days: 10}
+		if proposer := core.DisputeFallbackProposer(contract); proposer != expected {
+			t.Errorf("expected %s to be proposed by %s, got %s", policy, expected, proposer)
+		}
+	}
+}
+
+func TestDisputeFallbackLockTime(t *testing.T) {
+	contract := factory.NewDisputedContract()
+	if _, err := core.DisputeFallbackLockTime(contract); err != core.ErrNoDisputeFallback {
+		t.Errorf("expected ErrNoDisputeFallback without a rule, got %v", err)
+	}
+
+	contract.VendorListings[0].Metadata.DisputeFallback = &pb.DisputeFallback{
+		Policy: pb.DisputeFallback_REFUND_BUYER,
+		Days:   14,
+	}
+	orderedAt, err := ptypes.Timestamp(contract.BuyerOrder.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockTime, err := core.DisputeFallbackLockTime(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := orderedAt.Add(14 * 24 * time.Hour).Unix(); int64(lockTime) != expected {
+		t.Errorf("expected a lock time of %d, got %d", expected, lockTime)
+	}
+}
+
+func deleteDisputeFallbackNotifications(t *testing.T, node *core.OpenBazaarNode) {
+	notifications, _, err := node.Datastore.Notifications().GetAll("", -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range notifications {
+		if n.NotifierType != repo.NotifierTypeDisputeFallbackPayout {
+			continue
+		}
+		if err := node.Datastore.Notifications().Delete(n.GetID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDisputeFallbackPayoutClosesModeratorCase(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Broadcast = make(chan repo.Notifier, 10)
+	defer deleteDisputeFallbackNotifications(t, node)
+
+	dispute := factory.NewDisputeCaseRecord()
+	dispute.CaseID = "QmFallbackCase"
+	dispute.Timestamp = time.Now().Add(-48 * time.Hour)
+	dispute.BuyerContract.VendorListings[0].Metadata.DisputeFallback = &pb.DisputeFallback{Policy: pb.DisputeFallback_REFUND_BUYER, Days: 1}
+	paymentCoin := repo.CurrencyCode("BTC")
+	dispute.PaymentCoin = &paymentCoin
+	if err := node.Datastore.Cases().PutRecord(dispute); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Datastore.Cases().UpdateBuyerInfo(dispute.CaseID, dispute.BuyerContract, nil, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	payout := &pb.DisputeFallbackPayout{
+		OrderId:    dispute.CaseID,
+		ProposedBy: dispute.BuyerContract.BuyerOrder.BuyerID.PeerID,
+		Policy:     pb.DisputeFallback_REFUND_BUYER,
+	}
+
+	if err := node.ProcessDisputeFallbackPayout("QmStranger", payout); err == nil {
+		t.Error("expected a payout from outside the order to be rejected")
+	}
+	if err := node.ProcessDisputeFallbackPayout(payout.ProposedBy, payout); err != nil {
+		t.Fatal(err)
+	}
+	closed, err := node.Datastore.Cases().GetByCaseID(dispute.CaseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if closed.OrderState != pb.OrderState_RESOLVED {
+		t.Errorf("expected the case to be resolved, got %s", closed.OrderState)
+	}
+}
@@ -0,0 +1,688 @@
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	ipfspath "gx/ipfs/QmQAgv6Gaoe2tQpcabqwKXKChp2MZ7i3UXv9DqTTaxCaTR/go-path"
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	mh "gx/ipfs/QmerPMzPk1mJVowm8KgmoknWa4yCYvvugMPsgWmDNUvDLW/go-multihash"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/OpenBazaar/wallet-interface"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/ipfs"
+	"github.com/phoreproject/openbazaar-go/net"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// PanelSize is the number of moderators on a dispute panel
+	PanelSize = 3
+
+	// panelVoteTolerance is how far apart, in percentage points, two panel
+	// members' buyer shares may be and still count as the same decision
+	panelVoteTolerance = 1
+)
+
+// ErrNotPanelMember is returned when a panel message comes from, or is
+// meant for, a peer which isn't on the order's moderator panel
+var ErrNotPanelMember = errors.New("peer is not a member of this order's moderator panel")
+
+// PanelModerators returns every moderator who may resolve a dispute on the
+// order: the order's moderator followed by the other panel members
+func PanelModerators(payment *pb.Order_Payment) []string {
+	if payment == nil || payment.Moderator == "" {
+		return nil
+	}
+	return append([]string{payment.Moderator}, payment.PanelModerators...)
+}
+
+// IsPanelOrder returns true if the order's escrow is shared by a panel of
+// moderators rather than a single moderator
+func IsPanelOrder(payment *pb.Order_Payment) bool {
+	return payment != nil && len(payment.PanelModerators) > 0
+}
+
+// panelIndex returns the position of a moderator on the order's panel, which
+// is also its position among the moderator keys of the escrow, or -1
+func panelIndex(payment *pb.Order_Payment, peerID string) int {
+	for i, mod := range PanelModerators(payment) {
+		if mod == peerID {
+			return i
+		}
+	}
+	return -1
+}
+
+func isOrderModerator(payment *pb.Order_Payment, peerID string) bool {
+	return panelIndex(payment, peerID) >= 0
+}
+
+// GetPanelVotes returns the resolutions proposed so far by the members of
+// the panel moderating an order
+func (n *OpenBazaarNode) GetPanelVotes(orderID string) ([]*pb.RicardianContract, error) {
+	if _, err := n.Datastore.Cases().GetByCaseID(orderID); err != nil {
+		return nil, ErrCaseNotFound
+	}
+	return n.Datastore.PanelVotes().GetByOrderID(orderID)
+}
+
+// closePanelDispute is CloseDispute for an order moderated by a panel. If
+// another member already proposed the same split we endorse that proposal,
+// otherwise our resolution is proposed to the rest of the panel. The payout
+// is released by the proposer once a second member endorses it.
+func (n *OpenBazaarNode) closePanelDispute(dispute *repo.DisputeCaseRecord, contract *pb.RicardianContract, payDivision repo.PayoutRatio, outpoints []*pb.Outpoint, resolution string) error {
+	votes, err := n.Datastore.PanelVotes().GetByOrderID(dispute.CaseID)
+	if err != nil {
+		return err
+	}
+	for _, vote := range votes {
+		dr := vote.DisputeResolution
+		if dr == nil || dr.ProposedBy == n.IpfsNode.Identity.Pretty() || dr.EndorsedBy != "" || dr.Payout == nil || len(dr.Payout.Sigs) > 0 {
+			continue
+		}
+		if panelVoteMatches(dr, payDivision) {
+			return n.endorsePanelResolution(dispute, contract, payDivision, vote)
+		}
+	}
+	return n.proposePanelResolution(dispute, contract, payDivision, outpoints, resolution)
+}
+
+func (n *OpenBazaarNode) proposePanelResolution(dispute *repo.DisputeCaseRecord, contract *pb.RicardianContract, payDivision repo.PayoutRatio, outpoints []*pb.Outpoint, resolution string) error {
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+
+	var totalOut uint64
+	for _, o := range outpoints {
+		totalOut += o.Value
+	}
+
+	// The panel's fee was fixed in the order and is shared equally by the
+	// two members whose signatures release the payout
+	panelFee := contract.BuyerOrder.Payment.PanelFee
+	if panelFee >= totalOut {
+		return errors.New("panel fee exceeds the escrow")
+	}
+	feeShare := panelFee / 2
+
+	modAddr := wal.CurrentAddress(wallet.EXTERNAL)
+	outMap := make(map[string]wallet.TransactionOutput)
+	if feeShare > 0 {
+		outMap["moderator"] = wallet.TransactionOutput{Address: modAddr, Value: int64(feeShare)}
+		// Stand-in for the endorsing member's output so the fee estimate
+		// covers the final transaction
+		outMap["panel"] = wallet.TransactionOutput{Address: modAddr, Value: int64(feeShare)}
+	}
+	var buyerAddr, vendorAddr = dispute.BuyerPayoutAddress, dispute.VendorPayoutAddress
+	if payDivision.BuyerAny() {
+		addr, err := wal.DecodeAddress(buyerAddr)
+		if err != nil {
+			return err
+		}
+		outMap["buyer"] = wallet.TransactionOutput{
+			Address: addr,
+			Value:   int64((float64(totalOut) - float64(panelFee)) * (float64(payDivision.Buyer) / 100)),
+		}
+	}
+	if payDivision.VendorAny() {
+		addr, err := wal.DecodeAddress(vendorAddr)
+		if err != nil {
+			return err
+		}
+		outMap["vendor"] = wallet.TransactionOutput{
+			Address: addr,
+			Value:   int64((float64(totalOut) - float64(panelFee)) * (float64(payDivision.Vendor) / 100)),
+		}
+	}
+
+	var (
+		inputs  []wallet.TransactionInput
+		outputs []wallet.TransactionOutput
+	)
+	for _, o := range outpoints {
+		decodedHash, err := hex.DecodeString(o.Hash)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, wallet.TransactionInput{
+			OutpointHash:  decodedHash,
+			OutpointIndex: o.Index,
+			Value:         int64(o.Value),
+		})
+	}
+	if len(inputs) == 0 {
+		return errors.New("transaction has no inputs")
+	}
+	for _, out := range outMap {
+		outputs = append(outputs, out)
+	}
+	if len(outputs) == 0 {
+		return errors.New("transaction has no outputs")
+	}
+
+	// Subtract the fee from each output in proportion to its value
+	defaultFee := wal.GetFeePerByte(wallet.NORMAL)
+	txFee := wal.EstimateFee(inputs, outputs, dispute.ResolutionPaymentFeePerByte(payDivision, defaultFee))
+	amounts := make(map[string]uint64)
+	for role, out := range outMap {
+		share := (float64(out.Value) / float64(totalOut)) * float64(txFee)
+		val := out.Value - int64(share)
+		if val > 0 && !wal.IsDust(val) {
+			amounts[role] = uint64(val)
+		}
+	}
+	if _, ok := amounts["panel"]; !ok {
+		delete(amounts, "moderator")
+	}
+
+	payout := &pb.DisputeResolution_Payout{Inputs: outpoints}
+	if amt, ok := amounts["buyer"]; ok {
+		payout.BuyerOutput = &pb.DisputeResolution_Payout_Output{ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: buyerAddr}, Amount: amt}
+	}
+	if amt, ok := amounts["vendor"]; ok {
+		payout.VendorOutput = &pb.DisputeResolution_Payout_Output{ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: vendorAddr}, Amount: amt}
+	}
+	if amt, ok := amounts["moderator"]; ok {
+		payout.ModeratorOutput = &pb.DisputeResolution_Payout_Output{ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: modAddr.String()}, Amount: amt}
+	}
+
+	d := &pb.DisputeResolution{
+		OrderId:    dispute.CaseID,
+		ProposedBy: n.IpfsNode.Identity.Pretty(),
+		Resolution: resolution,
+		Payout:     payout,
+	}
+	d.Timestamp, err = ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Ratings are checked against the order's moderator key so only the
+	// order's moderator signs the buyer's rating keys
+	if n.IpfsNode.Identity.Pretty() == contract.BuyerOrder.Payment.Moderator && dispute.BuyerContract != nil {
+		d.ModeratorRatingSigs, err = n.moderatorRatingSigs(dispute.BuyerContract)
+		if err != nil {
+			return err
+		}
+	}
+
+	rc, err := n.SignDisputeResolution(&pb.RicardianContract{DisputeResolution: d})
+	if err != nil {
+		return err
+	}
+	if err := n.Datastore.PanelVotes().Put(dispute.CaseID, n.IpfsNode.Identity.Pretty(), rc, time.Now()); err != nil {
+		return err
+	}
+	for _, mod := range PanelModerators(contract.BuyerOrder.Payment) {
+		if mod == n.IpfsNode.Identity.Pretty() {
+			continue
+		}
+		if err := n.SendDisputePanelVote(mod, rc); err != nil {
+			log.Errorf("sending panel vote for order %s to %s: %s", dispute.CaseID, mod, err)
+		}
+	}
+	return nil
+}
+
+func (n *OpenBazaarNode) endorsePanelResolution(dispute *repo.DisputeCaseRecord, contract *pb.RicardianContract, payDivision repo.PayoutRatio, vote *pb.RicardianContract) error {
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	d := proto.Clone(vote.DisputeResolution).(*pb.DisputeResolution)
+
+	// Only endorse a payout of the escrow we know about to the addresses
+	// the buyer and vendor gave us
+	expected := make(map[string]uint64)
+	for _, o := range dispute.ResolutionPaymentOutpoints(payDivision) {
+		expected[fmt.Sprintf("%s:%d", o.Hash, o.Index)] = o.Value
+	}
+	if len(expected) != len(d.Payout.Inputs) {
+		return errors.New("panel proposal does not spend the whole escrow")
+	}
+	for _, o := range d.Payout.Inputs {
+		if value, ok := expected[fmt.Sprintf("%s:%d", o.Hash, o.Index)]; !ok || value != o.Value {
+			return errors.New("panel proposal spends an unknown escrow output")
+		}
+	}
+	if d.Payout.BuyerOutput != nil && d.Payout.BuyerOutput.GetAddress() != dispute.BuyerPayoutAddress {
+		return errors.New("panel proposal does not pay the buyer's payout address")
+	}
+	if d.Payout.VendorOutput != nil && d.Payout.VendorOutput.GetAddress() != dispute.VendorPayoutAddress {
+		return errors.New("panel proposal does not pay the vendor's payout address")
+	}
+	if d.Payout.ModeratorOutput != nil && d.Payout.ModeratorOutput.Amount > contract.BuyerOrder.Payment.PanelFee/2 {
+		return errors.New("panel proposal pays more than the order's panel fee")
+	}
+
+	d.EndorsedBy = n.IpfsNode.Identity.Pretty()
+	if d.Payout.ModeratorOutput != nil {
+		d.Payout.PanelOutput = &pb.DisputeResolution_Payout_Output{
+			ScriptOrAddress: &pb.DisputeResolution_Payout_Output_Address{Address: wal.CurrentAddress(wallet.EXTERNAL).String()},
+			Amount:          d.Payout.ModeratorOutput.Amount,
+		}
+	}
+	inputs, outputs, err := panelPayoutTransaction(wal, d.Payout)
+	if err != nil {
+		return err
+	}
+	release, err := n.NewEscrowRelease(contract, SigningActionPanelPayout, inputs, outputs, 0)
+	if err != nil {
+		return err
+	}
+	sigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	for _, sig := range sigs {
+		d.Payout.PanelSigs = append(d.Payout.PanelSigs, &pb.BitcoinSignature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+
+	rc, err := n.SignDisputeResolution(&pb.RicardianContract{DisputeResolution: d})
+	if err != nil {
+		return err
+	}
+	if err := n.Datastore.PanelVotes().Put(dispute.CaseID, n.IpfsNode.Identity.Pretty(), rc, time.Now()); err != nil {
+		return err
+	}
+	return n.SendDisputePanelVote(d.ProposedBy, rc)
+}
+
+// ProcessDisputePanelVote handles a message from another member of an
+// order's panel: a proposed resolution, an endorsement of our own proposal
+// or the final resolution once the payout has been released
+func (n *OpenBazaarNode) ProcessDisputePanelVote(peerID string, rc *pb.RicardianContract) error {
+	d := rc.DisputeResolution
+	if d == nil || d.Payout == nil {
+		return errors.New("panel vote is missing the dispute resolution")
+	}
+	dispute, err := n.Datastore.Cases().GetByCaseID(d.OrderId)
+	if err != nil {
+		return net.OutOfOrderMessage
+	}
+	contract := dispute.BuyerContract
+	if contract == nil {
+		contract = dispute.VendorContract
+	}
+	if contract == nil || contract.BuyerOrder == nil {
+		return errors.New("case is missing the order contract")
+	}
+	payment := contract.BuyerOrder.Payment
+	me := n.IpfsNode.Identity.Pretty()
+	if peerID == me || !isOrderModerator(payment, peerID) || !isOrderModerator(payment, me) {
+		return ErrNotPanelMember
+	}
+	if err := n.verifyDisputeResolutionSignedBy(d, rc.Signatures, peerID); err != nil {
+		return err
+	}
+
+	switch {
+	case d.EndorsedBy == "" && d.ProposedBy == peerID && len(d.Payout.Sigs) == 0:
+		if dispute.OrderState != pb.OrderState_DISPUTED {
+			return nil
+		}
+		if err := n.Datastore.PanelVotes().Put(d.OrderId, peerID, rc, time.Now()); err != nil {
+			return err
+		}
+		n.notifyDisputePanelVote(d.OrderId, peerID, false)
+	case d.EndorsedBy == peerID && d.ProposedBy == me && len(d.Payout.Sigs) == 0:
+		if err := n.releasePanelResolution(dispute, contract, rc); err != nil {
+			return err
+		}
+		n.notifyDisputePanelVote(d.OrderId, peerID, true)
+	case d.ProposedBy == peerID && isOrderModerator(payment, d.EndorsedBy) && len(d.Payout.Sigs) > 0:
+		if err := n.verifyPanelResolution(contract, d, rc.Signatures); err != nil {
+			return err
+		}
+		if err := n.Datastore.Cases().MarkAsClosed(d.OrderId, d); err != nil {
+			return err
+		}
+		if err := n.Datastore.PanelVotes().DeleteByOrderID(d.OrderId); err != nil {
+			return err
+		}
+	default:
+		return errors.New("unexpected panel vote")
+	}
+	return nil
+}
+
+// releasePanelResolution adds our signatures to our proposal once another
+// panel member endorsed it, broadcasts the payout and closes the dispute
+func (n *OpenBazaarNode) releasePanelResolution(dispute *repo.DisputeCaseRecord, contract *pb.RicardianContract, endorsement *pb.RicardianContract) error {
+	d := endorsement.DisputeResolution
+	votes, err := n.Datastore.PanelVotes().GetByOrderID(d.OrderId)
+	if err != nil {
+		return err
+	}
+	var proposal *pb.DisputeResolution
+	for _, vote := range votes {
+		if vote.DisputeResolution != nil && vote.DisputeResolution.ProposedBy == n.IpfsNode.Identity.Pretty() && vote.DisputeResolution.EndorsedBy == "" {
+			proposal = vote.DisputeResolution
+			break
+		}
+	}
+	if proposal == nil {
+		return errors.New("no proposal of ours to release")
+	}
+
+	// The endorser may only add its own fee output and signatures
+	endorsed := proto.Clone(d).(*pb.DisputeResolution)
+	panelOutput := endorsed.Payout.PanelOutput
+	endorsed.EndorsedBy = ""
+	endorsed.Payout.PanelOutput = nil
+	endorsed.Payout.PanelSigs = nil
+	if !proto.Equal(endorsed, proposal) {
+		return errors.New("endorsement does not match our proposal")
+	}
+	if (proposal.Payout.ModeratorOutput == nil) != (panelOutput == nil) {
+		return errors.New("endorsement has an invalid panel fee output")
+	}
+	if panelOutput != nil && panelOutput.Amount != proposal.Payout.ModeratorOutput.Amount {
+		return errors.New("endorsement does not split the panel fee equally")
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return err
+	}
+	inputs, outputs, err := panelPayoutTransaction(wal, d.Payout)
+	if err != nil {
+		return err
+	}
+	release, err := n.NewEscrowRelease(contract, SigningActionPanelPayout, inputs, outputs, 0)
+	if err != nil {
+		return err
+	}
+	mySigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	var endorserSigs []wallet.Signature
+	for _, sig := range d.Payout.PanelSigs {
+		endorserSigs = append(endorserSigs, wallet.Signature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+
+	// The moderators' keys follow the buyer's and vendor's in the escrow
+	payment := contract.BuyerOrder.Payment
+	sigs := make([][]wallet.Signature, PanelSize+2)
+	sigs[panelIndex(payment, n.IpfsNode.Identity.Pretty())+2] = mySigs
+	sigs[panelIndex(payment, d.EndorsedBy)+2] = endorserSigs
+	if err := broadcastEscrowRelease(wal, release, sigs); err != nil {
+		return err
+	}
+
+	for _, sig := range mySigs {
+		d.Payout.Sigs = append(d.Payout.Sigs, &pb.BitcoinSignature{InputIndex: sig.InputIndex, Signature: sig.Signature})
+	}
+	rc, err := n.SignDisputeResolution(&pb.RicardianContract{DisputeResolution: d})
+	if err != nil {
+		return err
+	}
+	// The endorser's signature follows ours so everyone can check two
+	// members agreed on the resolution
+	rc.Signatures = append(rc.Signatures, endorsement.Signatures...)
+
+	buyerKey, err := libp2p.UnmarshalPublicKey(contract.BuyerOrder.BuyerID.Pubkeys.Identity)
+	if err != nil {
+		return err
+	}
+	vendorKey, err := libp2p.UnmarshalPublicKey(contract.VendorListings[0].VendorID.Pubkeys.Identity)
+	if err != nil {
+		return err
+	}
+	if err := n.SendDisputeClose(contract.BuyerOrder.BuyerID.PeerID, &buyerKey, rc); err != nil {
+		return err
+	}
+	if err := n.SendDisputeClose(contract.VendorListings[0].VendorID.PeerID, &vendorKey, rc); err != nil {
+		return err
+	}
+	for _, mod := range PanelModerators(payment) {
+		if mod == n.IpfsNode.Identity.Pretty() {
+			continue
+		}
+		if err := n.SendDisputePanelVote(mod, rc); err != nil {
+			log.Errorf("sending panel resolution for order %s to %s: %s", d.OrderId, mod, err)
+		}
+	}
+
+	if err := n.Datastore.Cases().MarkAsClosed(d.OrderId, d); err != nil {
+		return err
+	}
+	return n.Datastore.PanelVotes().DeleteByOrderID(d.OrderId)
+}
+
+// verifyPanelResolution checks two members of the panel agreed on a released
+// resolution. The proposer's signature comes first and is checked by the
+// caller. The endorser's follows it and covers the resolution as it was
+// before the proposer added its escrow signatures.
+func (n *OpenBazaarNode) verifyPanelResolution(contract *pb.RicardianContract, d *pb.DisputeResolution, sigs []*pb.Signature) error {
+	payment := contract.BuyerOrder.Payment
+	if d.EndorsedBy == d.ProposedBy || !isOrderModerator(payment, d.ProposedBy) || !isOrderModerator(payment, d.EndorsedBy) ||
+		len(d.Payout.Sigs) == 0 || len(d.Payout.PanelSigs) == 0 {
+		return errors.New("resolution was not agreed by a majority of the panel")
+	}
+	if d.Payout.ModeratorOutput != nil && d.Payout.ModeratorOutput.Amount > payment.PanelFee/2 ||
+		d.Payout.PanelOutput != nil && d.Payout.PanelOutput.Amount > payment.PanelFee/2 {
+		return errors.New("resolution pays more than the order's panel fee")
+	}
+
+	var resolutionSigs []*pb.Signature
+	for _, sig := range sigs {
+		if sig.Section == pb.Signature_DISPUTE_RESOLUTION {
+			resolutionSigs = append(resolutionSigs, sig)
+		}
+	}
+	if len(resolutionSigs) < 2 {
+		return errors.New("resolution is missing the panel endorsement")
+	}
+	endorsement := proto.Clone(d).(*pb.DisputeResolution)
+	endorsement.Payout.Sigs = nil
+	if err := n.verifyDisputeResolutionSignedBy(endorsement, resolutionSigs[1:], d.EndorsedBy); err != nil {
+		return err
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(payment.Coin)
+	if err != nil {
+		return err
+	}
+	inputs, outputs, err := panelPayoutTransaction(wal, d.Payout)
+	if err != nil {
+		return err
+	}
+	release, err := n.NewEscrowRelease(contract, SigningActionPanelPayout, inputs, outputs, 0)
+	if err != nil {
+		return err
+	}
+	if err := n.verifyEscrowSigs(wal, release, d.Payout.Sigs, panelMemberEscrowKey(payment, d.ProposedBy)); err != nil {
+		return err
+	}
+	return n.verifyEscrowSigs(wal, release, d.Payout.PanelSigs, panelMemberEscrowKey(payment, d.EndorsedBy))
+}
+
+func (n *OpenBazaarNode) notifyDisputePanelVote(orderID, moderatorID string, endorsed bool) {
+	notif := repo.DisputePanelVoteNotification{
+		ID:          repo.NewNotificationID(),
+		Type:        repo.NotifierTypeDisputePanelVote,
+		OrderID:     orderID,
+		ModeratorID: moderatorID,
+		Endorsed:    endorsed,
+	}
+	n.Broadcast <- notif
+	n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, time.Now(), false))
+}
+
+// panelVoteMatches returns true if a proposed payout gives the buyer and
+// vendor the same shares as the payout ratio
+func panelVoteMatches(d *pb.DisputeResolution, ratio repo.PayoutRatio) bool {
+	var buyer, vendor float64
+	if d.Payout.BuyerOutput != nil {
+		buyer = float64(d.Payout.BuyerOutput.Amount)
+	}
+	if d.Payout.VendorOutput != nil {
+		vendor = float64(d.Payout.VendorOutput.Amount)
+	}
+	if buyer+vendor == 0 {
+		return false
+	}
+	return math.Abs(buyer/(buyer+vendor)*100-float64(ratio.Buyer)) <= panelVoteTolerance
+}
+
+// panelPayoutTransaction returns the inputs and outputs of a panel payout.
+// Every member builds the transaction from the payout so their signatures
+// cover the same transaction.
+func panelPayoutTransaction(wal wallet.Wallet, payout *pb.DisputeResolution_Payout) ([]wallet.TransactionInput, []wallet.TransactionOutput, error) {
+	var inputs []wallet.TransactionInput
+	for _, o := range payout.Inputs {
+		hash, err := hex.DecodeString(o.Hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputs = append(inputs, wallet.TransactionInput{
+			OutpointHash:  hash,
+			OutpointIndex: o.Index,
+			Value:         int64(o.Value),
+		})
+	}
+	if len(inputs) == 0 {
+		return nil, nil, errors.New("transaction has no inputs")
+	}
+
+	var outputs []wallet.TransactionOutput
+	for _, o := range []*pb.DisputeResolution_Payout_Output{payout.BuyerOutput, payout.VendorOutput, payout.ModeratorOutput, payout.PanelOutput} {
+		if o == nil {
+			continue
+		}
+		addr, err := pb.DisputeResolutionPayoutOutputToAddress(wal, o)
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: int64(o.Amount)})
+	}
+	if len(outputs) == 0 {
+		return nil, nil, errors.New("transaction has no outputs")
+	}
+	return inputs, outputs, nil
+}
+
+// acceptPanelResolution records the acceptance of a resolution whose payout
+// was already broadcast by the panel members who agreed on it
+func (n *OpenBazaarNode) acceptPanelResolution(contract *pb.RicardianContract) error {
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+	contract.DisputeAcceptance = &pb.DisputeAcceptance{
+		Timestamp: ts,
+		ClosedBy:  n.IpfsNode.Identity.Pretty(),
+	}
+	orderID, err := n.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	if n.IpfsNode.Identity.Pretty() == contract.BuyerOrder.BuyerID.PeerID {
+		return n.Datastore.Purchases().Put(orderID, *contract, pb.OrderState_DECIDED, true)
+	}
+	return n.Datastore.Sales().Put(orderID, *contract, pb.OrderState_DECIDED, true)
+}
+
+// panelModeratorKeys checks the panel selected for a purchase and returns
+// the bitcoin public keys of the panel members
+func (n *OpenBazaarNode) panelModeratorKeys(data *PurchaseData, contract *pb.RicardianContract) ([][]byte, error) {
+	if len(data.PanelModerators) == 0 {
+		return nil, nil
+	}
+	if len(data.PanelModerators) != PanelSize-1 {
+		return nil, fmt.Errorf("a moderator panel needs %d moderators besides the selected moderator", PanelSize-1)
+	}
+	seen := map[string]bool{data.Moderator: true}
+	var keys [][]byte
+	for _, mod := range data.PanelModerators {
+		if seen[mod] {
+			return nil, errors.New("panel moderators must be distinct")
+		}
+		seen[mod] = true
+		if mod == n.IpfsNode.Identity.Pretty() {
+			return nil, errors.New("cannot select self as moderator")
+		}
+		if mod == contract.VendorListings[0].VendorID.PeerID {
+			return nil, errors.New("cannot select vendor as moderator")
+		}
+		if !data.IgnoreModeratorAvailability {
+			if err := n.CheckModeratorAvailability(mod); err != nil {
+				return nil, err
+			}
+		}
+		profile, err := n.FetchProfile(mod, true)
+		if err != nil {
+			return nil, fmt.Errorf("panel moderator %s could not be found", mod)
+		}
+		if !profile.Moderator || profile.ModeratorInfo == nil || !currencyInAcceptedCurrenciesList(data.PaymentCoin, profile.ModeratorInfo.AcceptedCurrencies) {
+			return nil, fmt.Errorf("panel moderator %s is not capable of moderating this transaction", mod)
+		}
+		keyBytes, err := hex.DecodeString(profile.BitcoinPubkey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, keyBytes)
+	}
+	return keys, nil
+}
+
+// validatePanelModerators checks the panel on an incoming order only names
+// distinct moderators offered by the listings and its fee leaves something
+// to pay out
+func validatePanelModerators(payment *pb.Order_Payment, availableMods []string) error {
+	if len(payment.PanelModerators) == 0 && len(payment.PanelModeratorKeys) == 0 {
+		return nil
+	}
+	if len(payment.PanelModerators) != PanelSize-1 || len(payment.PanelModeratorKeys) != PanelSize-1 {
+		return errors.New("invalid moderator panel")
+	}
+	if payment.PanelFee >= payment.Amount {
+		return errors.New("invalid panel fee")
+	}
+	seen := map[string]bool{payment.Moderator: true}
+	for _, mod := range payment.PanelModerators {
+		if _, err := mh.FromB58String(mod); err != nil || seen[mod] {
+			return errors.New("invalid moderator panel")
+		}
+		seen[mod] = true
+		offered := false
+		for _, m := range availableMods {
+			if m == mod {
+				offered = true
+				break
+			}
+		}
+		if !offered {
+			return errors.New("invalid moderator panel")
+		}
+	}
+	return nil
+}
+
+func (n *OpenBazaarNode) resolveModeratorEscrowKey(wal wallet.Wallet, moderatorID string, chaincode []byte) (*hd.ExtendedKey, error) {
+	ipnsPath := ipfspath.FromString(moderatorID + "/profile.json")
+	profileBytes, err := ipfs.ResolveThenCat(n.IpfsNode, ipnsPath, time.Minute, n.IPNSQuorumSize, true)
+	if err != nil {
+		return nil, err
+	}
+	profile := new(pb.Profile)
+	if err := jsonpb.UnmarshalString(string(profileBytes), profile); err != nil {
+		return nil, err
+	}
+	keyBytes, err := hex.DecodeString(profile.BitcoinPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return wal.ChildKey(keyBytes, chaincode, false)
+}
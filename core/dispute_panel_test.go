@@ -0,0 +1,36 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+func TestPanelModerators(t *testing.T) {
+	if mods := core.PanelModerators(&pb.Order_Payment{}); len(mods) != 0 {
+		t.Errorf("expected no moderators for an unmoderated order, got %v", mods)
+	}
+
+	payment := &pb.Order_Payment{Moderator: "QmModerator"}
+	if core.IsPanelOrder(payment) {
+		t.Error("expected a single moderator order not to be a panel order")
+	}
+	if mods := core.PanelModerators(payment); len(mods) != 1 || mods[0] != "QmModerator" {
+		t.Errorf("expected only the order's moderator, got %v", mods)
+	}
+
+	payment.PanelModerators = []string{"QmPanel1", "QmPanel2"}
+	if !core.IsPanelOrder(payment) {
+		t.Error("expected a panel order")
+	}
+	mods := core.PanelModerators(payment)
+	if len(mods) != core.PanelSize {
+		t.Fatalf("expected %d panel members, got %d", core.PanelSize, len(mods))
+	}
+	for i, expected := range []string{"QmModerator", "QmPanel1", "QmPanel2"} {
+		if mods[i] != expected {
+			t.Errorf("expected panel member %d to be %s, got %s", i, expected, mods[i])
+		}
+	}
+}
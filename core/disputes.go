@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
@@ -15,13 +16,13 @@ import (
 	"github.com/OpenBazaar/wallet-interface"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcutil"
-	hd "github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/phoreproject/openbazaar-go/net"
 	"github.com/phoreproject/openbazaar-go/pb"
 	"github.com/phoreproject/openbazaar-go/repo"
 	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/signer"
 	"golang.org/x/net/context"
 )
 
@@ -103,9 +104,11 @@ func (n *OpenBazaarNode) OpenDispute(orderID string, contract *pb.RicardianContr
 	contract.Signatures = append(contract.Signatures, rc.Signatures[0])
 
 	// Send to moderator
-	err = n.SendDisputeOpen(contract.BuyerOrder.Payment.Moderator, nil, rc)
-	if err != nil {
-		return err
+	for _, mod := range PanelModerators(contract.BuyerOrder.Payment) {
+		err = n.SendDisputeOpen(mod, nil, rc)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Send to counterparty
@@ -139,6 +142,10 @@ func (n *OpenBazaarNode) OpenDispute(orderID string, contract *pb.RicardianContr
 }
 
 func (n *OpenBazaarNode) verifyEscrowFundsAreDisputeable(contract *pb.RicardianContract, records []*wallet.TransactionRecord) bool {
+	// A panel's escrow has no timeout
+	if IsPanelOrder(contract.BuyerOrder.Payment) {
+		return true
+	}
 	confirmationsForTimeout := contract.VendorListings[0].Metadata.EscrowTimeoutHours * ConfirmationsPerHour
 	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
 	if err != nil {
@@ -265,7 +272,7 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 	var DisputerHandle string
 	var DisputeeID string
 	var DisputeeHandle string
-	if contract.BuyerOrder.Payment.Moderator == n.IpfsNode.Identity.Pretty() { // Moderator
+	if isOrderModerator(contract.BuyerOrder.Payment, n.IpfsNode.Identity.Pretty()) { // Moderator
 		validationErrors := n.ValidateCaseContract(contract)
 		var err error
 		if contract.VendorListings[0].VendorID.PeerID == peerID {
@@ -336,9 +343,11 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 		update.Outpoints = outpoints
 
 		// Send the message
-		err = n.SendDisputeUpdate(myContract.BuyerOrder.Payment.Moderator, update)
-		if err != nil {
-			return err
+		for _, mod := range PanelModerators(myContract.BuyerOrder.Payment) {
+			err = n.SendDisputeUpdate(mod, update)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Append the dispute and signature
@@ -393,9 +402,11 @@ func (n *OpenBazaarNode) ProcessDisputeOpen(rc *pb.RicardianContract, peerID str
 		update.Outpoints = outpoints
 
 		// Send the message
-		err = n.SendDisputeUpdate(myContract.BuyerOrder.Payment.Moderator, update)
-		if err != nil {
-			return err
+		for _, mod := range PanelModerators(myContract.BuyerOrder.Payment) {
+			err = n.SendDisputeUpdate(mod, update)
+			if err != nil {
+				return err
+			}
 		}
 
 		// Append the dispute and signature
@@ -450,6 +461,9 @@ func (n *OpenBazaarNode) CloseDispute(orderID string, buyerPercentage, vendorPer
 		log.Errorf("unable to resolve expired dispute for order %s", orderID)
 		return ErrCloseFailureCaseExpired
 	}
+	if n.caseHandedOver(orderID) {
+		return ErrCaseHandedOver
+	}
 
 	var outpoints = dispute.ResolutionPaymentOutpoints(payDivision)
 	if outpoints == nil {
@@ -474,6 +488,11 @@ func (n *OpenBazaarNode) CloseDispute(orderID string, buyerPercentage, vendorPer
 		preferredContract.BuyerOrder.Payment.Coin = paymentCoinHint.String()
 	}
 
+	if IsPanelOrder(preferredContract.BuyerOrder.Payment) {
+		return n.closePanelDispute(dispute, preferredContract, payDivision, outpoints, resolution)
+	}
+	handover := n.handedOverEscrow(orderID, n.IpfsNode.Identity.Pretty())
+
 	var d = new(pb.DisputeResolution)
 
 	// Add timestamp
@@ -611,45 +630,41 @@ func (n *OpenBazaarNode) CloseDispute(orderID string, buyerPercentage, vendorPer
 		}
 	}
 
-	// Create moderator key
 	chaincode := preferredContract.BuyerOrder.Payment.Chaincode
 	chaincodeBytes, err := hex.DecodeString(chaincode)
 	if err != nil {
 		return err
 	}
-	mPrivKey := n.MasterPrivateKey
-	mECKey, err := mPrivKey.ECPrivKey()
-	if err != nil {
-		return err
-	}
-	moderatorKey, err := wal.ChildKey(mECKey.Serialize(), chaincodeBytes, true)
-	if err != nil {
-		return err
-	}
 
-	// Sign buyer rating key
-	if dispute.BuyerContract != nil {
-		ecPriv, err := moderatorKey.ECPrivKey()
+	// Sign buyer rating key. Ratings are checked against the escrow key of
+	// the order's moderator, which signed them for a case handed over to us.
+	if handover != nil {
+		d.ModeratorRatingSigs = handover.ModeratorRatingSigs
+	} else if dispute.BuyerContract != nil {
+		d.ModeratorRatingSigs, err = n.moderatorRatingSigs(dispute.BuyerContract)
 		if err != nil {
 			return err
 		}
-		for _, key := range dispute.BuyerContract.BuyerOrder.RatingKeys {
-			hashed := sha256.Sum256(key)
-			sig, err := ecPriv.Sign(hashed[:])
-			if err != nil {
-				return err
-			}
-			d.ModeratorRatingSigs = append(d.ModeratorRatingSigs, sig.Serialize())
-		}
 	}
 
-	// Create signatures
+	// Create signatures. A case handed over to us is paid from the escrow it
+	// was moved to.
 	redeemScript := preferredContract.BuyerOrder.Payment.RedeemScript
+	if handover != nil {
+		redeemScript = handover.RedeemScript
+	}
 	redeemScriptBytes, err := hex.DecodeString(redeemScript)
 	if err != nil {
 		return err
 	}
-	sigs, err := wal.CreateMultisigSignature(inputs, outs, moderatorKey, redeemScriptBytes, 0)
+	sigs, err := n.SignEscrowRelease(wal, EscrowRelease{
+		OrderID:      orderID,
+		Action:       SigningActionDisputeResolution,
+		Inputs:       inputs,
+		Outputs:      outs,
+		Chaincode:    chaincodeBytes,
+		RedeemScript: redeemScriptBytes,
+	})
 	if err != nil {
 		return err
 	}
@@ -712,9 +727,36 @@ func (n *OpenBazaarNode) CloseDispute(orderID string, buyerPercentage, vendorPer
 	if err != nil {
 		return err
 	}
+	if handover != nil {
+		if err := n.sendCaseHandoverResolution(handover, buyerPercentage, vendorPercentage, resolution); err != nil {
+			log.Errorf("Error telling moderator %s case %s was resolved: %s", handover.Moderator, orderID, err)
+		}
+	}
 	return nil
 }
 
+// moderatorRatingSigs signs the buyer's rating keys with our escrow key for
+// the order. A watch-only node can't, and its ratings go without them.
+func (n *OpenBazaarNode) moderatorRatingSigs(contract *pb.RicardianContract) ([][]byte, error) {
+	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+	if err != nil {
+		return nil, err
+	}
+	var sigs [][]byte
+	for _, key := range contract.BuyerOrder.RatingKeys {
+		hashed := sha256.Sum256(key)
+		sig, err := n.Signer.SignEscrow(chaincode, hashed[:])
+		if err == signer.ErrWatchOnly {
+			log.Warning("Not signing the buyer's rating keys: the node is watch-only")
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
 // SignDisputeResolution - add signature to DisputeResolution
 func (n *OpenBazaarNode) SignDisputeResolution(contract *pb.RicardianContract) (*pb.RicardianContract, error) {
 	serializedDR, err := proto.Marshal(contract.DisputeResolution)
@@ -875,16 +917,25 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
 			return validationErrors
 		}
-		mECKey, err := n.MasterPrivateKey.ECPubKey()
+		mECKey, err := n.MasterPublicKey.ECPubKey()
 		if err != nil {
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
 			return validationErrors
 		}
-		moderatorKey, err := wal.ChildKey(mECKey.SerializeCompressed(), chaincode, false)
+		myKey, err := wal.ChildKey(mECKey.SerializeCompressed(), chaincode, false)
 		if err != nil {
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
 			return validationErrors
 		}
+		if IsPanelOrder(contract.BuyerOrder.Payment) {
+			// The panel escrow is built from the keys in the order so check
+			// ours is the one the buyer put in it
+			myECKey, err := myKey.ECPubKey()
+			if err != nil || !bytes.Equal(panelMemberEscrowKey(contract.BuyerOrder.Payment, n.IpfsNode.Identity.Pretty()), myECKey.SerializeCompressed()) {
+				validationErrors = append(validationErrors, "The panel moderator key in the order is not ours")
+				return validationErrors
+			}
+		}
 		buyerKey, err := wal.ChildKey(contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin, chaincode, false)
 		if err != nil {
 			validationErrors = append(validationErrors, "Error validating bitcoin address and redeem script")
@@ -896,7 +947,7 @@ func (n *OpenBazaarNode) ValidateCaseContract(contract *pb.RicardianContract) []
 			return validationErrors
 		}
 		timeout, _ := time.ParseDuration(strconv.Itoa(int(contract.VendorListings[0].Metadata.EscrowTimeoutHours)) + "h")
-		addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2, timeout, vendorKey)
+		addr, redeemScript, err := orderEscrowScript(wal, buyerKey, vendorKey, myKey, contract.BuyerOrder.Payment, timeout)
 		if err != nil {
 			validationErrors = append(validationErrors, "Error generating multisig script")
 			return validationErrors
@@ -923,6 +974,11 @@ func (n *OpenBazaarNode) ValidateDisputeResolution(contract *pb.RicardianContrac
 	if contract.DisputeResolution.Payout == nil || len(contract.DisputeResolution.Payout.Sigs) == 0 {
 		return errors.New("DisputeResolution contains invalid payout")
 	}
+	if IsPanelOrder(contract.BuyerOrder.Payment) {
+		if err := n.verifyPanelResolution(contract, contract.DisputeResolution, contract.Signatures); err != nil {
+			return err
+		}
+	}
 	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
 	if err != nil {
 		return err
@@ -949,16 +1005,25 @@ func (n *OpenBazaarNode) verifyPaymentDestinationIsInWallet(output *pb.DisputeRe
 }
 
 func (n *OpenBazaarNode) verifySignatureOnDisputeResolution(contract *pb.RicardianContract) error {
+	// Panel resolutions are signed by whichever member proposed them and
+	// handed over cases by the backup moderator
+	signer := resolutionSigner(contract.DisputeResolution, contract.BuyerOrder.Payment)
+	if d := contract.DisputeResolution; n.handedOverEscrow(d.OrderId, d.ProposedBy) != nil {
+		signer = d.ProposedBy
+	}
+	return n.verifyDisputeResolutionSignedBy(contract.DisputeResolution, contract.Signatures, signer)
+}
 
-	moderatorID, err := peer.IDB58Decode(contract.BuyerOrder.Payment.Moderator)
+func (n *OpenBazaarNode) verifyDisputeResolutionSignedBy(d *pb.DisputeResolution, sigs []*pb.Signature, signer string) error {
+	signerID, err := peer.IDB58Decode(signer)
 	if err != nil {
 		return err
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	pubkey, err := n.DHT.GetPublicKey(ctx, moderatorID)
+	pubkey, err := n.DHT.GetPublicKey(ctx, signerID)
 	if err != nil {
-		log.Errorf("Failed to find public key for %s", moderatorID.Pretty())
+		log.Errorf("Failed to find public key for %s", signerID.Pretty())
 		return err
 	}
 	pubKeyBytes, err := pubkey.Bytes()
@@ -966,13 +1031,7 @@ func (n *OpenBazaarNode) verifySignatureOnDisputeResolution(contract *pb.Ricardi
 		return err
 	}
 
-	if err := verifyMessageSignature(
-		contract.DisputeResolution,
-		pubKeyBytes,
-		contract.Signatures,
-		pb.Signature_DISPUTE_RESOLUTION,
-		moderatorID.Pretty(),
-	); err != nil {
+	if err := verifyMessageSignature(d, pubKeyBytes, sigs, pb.Signature_DISPUTE_RESOLUTION, signerID.Pretty()); err != nil {
 		switch err.(type) {
 		case noSigError:
 			return errors.New("contract does not contain a signature for the dispute resolution")
@@ -989,6 +1048,11 @@ func (n *OpenBazaarNode) verifySignatureOnDisputeResolution(contract *pb.Ricardi
 
 // ReleaseFunds - release funds
 func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []*wallet.TransactionRecord) error {
+	// A panel's payout was broadcast by the members who agreed on it
+	if contract.DisputeResolution.EndorsedBy != "" {
+		return n.acceptPanelResolution(contract)
+	}
+
 	// Create inputs
 	var inputs []wallet.TransactionInput
 	for _, o := range contract.DisputeResolution.Payout.Inputs {
@@ -1048,27 +1112,19 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 		outputs = append(outputs, output)
 	}
 
-	// Create signing key
-	chaincodeBytes, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-	if err != nil {
-		return err
-	}
-	mPrivKey := n.MasterPrivateKey
-	mECKey, err := mPrivKey.ECPrivKey()
+	release, err := n.NewEscrowRelease(contract, SigningActionReleaseFunds, inputs, outputs, 0)
 	if err != nil {
 		return err
 	}
-	signingKey, err := wal.ChildKey(mECKey.Serialize(), chaincodeBytes, true)
-	if err != nil {
-		return err
-	}
-
-	// Create signatures
-	redeemScriptBytes, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-	if err != nil {
-		return err
+	// A case handed over to a backup moderator is paid from the escrow it
+	// was moved to
+	if handover := n.handedOverEscrow(release.OrderID, contract.DisputeResolution.ProposedBy); handover != nil {
+		release.RedeemScript, err = hex.DecodeString(handover.RedeemScript)
+		if err != nil {
+			return err
+		}
 	}
-	mySigs, err := wal.CreateMultisigSignature(inputs, outputs, signingKey, redeemScriptBytes, 0)
+	mySigs, err := n.SignEscrowRelease(wal, release)
 	if err != nil {
 		return err
 	}
@@ -1108,7 +1164,7 @@ func (n *OpenBazaarNode) ReleaseFunds(contract *pb.RicardianContract, records []
 	}
 
 	// Build, sign, and broadcast transaction
-	_, err = wal.Multisign(inputs, outputs, mySigs, moderatorSigs, redeemScriptBytes, 0, true)
+	_, err = wal.Multisign(inputs, outputs, mySigs, moderatorSigs, release.RedeemScript, 0, true)
 	if err != nil {
 		return err
 	}
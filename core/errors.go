@@ -5,6 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
 )
 
 var (
@@ -49,8 +53,35 @@ var (
 	// ErrSpendAmountIsDust is returned when the requested amount to spend out of the wallet would be considered "dust" by the network. This means the value is too low for the network to bother sending the amount and has a high likelihood of not being accepted or being outright rejected.
 	ErrSpendAmountIsDust = errors.New("ERROR_DUST_AMOUNT")
 
+	// ErrInvalidSpendAmount is returned when an output of a spend is not a positive amount
+	ErrInvalidSpendAmount = errors.New("ERROR_INVALID_AMOUNT")
+
+	// ErrInvalidSpendUtxo is returned when an input of a spend is not a txid and output index separated by a colon
+	ErrInvalidSpendUtxo = errors.New("ERROR_INVALID_UTXO")
+
+	// ErrSpendUtxoUnavailable is returned when an input of a spend is not an unspent output the wallet holds the key for
+	ErrSpendUtxoUnavailable = errors.New("ERROR_UTXO_UNAVAILABLE")
+
+	// ErrCoinControlUnsupported is returned when the wallet is unable to build and sign a transaction from the inputs and outputs we choose
+	ErrCoinControlUnsupported = errors.New("ERROR_COIN_CONTROL_UNSUPPORTED")
+
 	// ErrUnknownOrder is returned when the requested amount to spend is unable to be associated with the appropriate order
 	ErrOrderNotFound = errors.New("ERROR_ORDER_NOT_FOUND")
+
+	// ErrPaymentChannelUnavailable is returned when the order's coin can't be paid through our payment channel daemon
+	ErrPaymentChannelUnavailable = errors.New("payment channel payments are not available for this currency")
+
+	// ErrPaymentChannelVendorOffline is returned when the vendor can't be reached to issue the invoice of a payment channel order
+	ErrPaymentChannelVendorOffline = errors.New("the vendor must be online to issue the invoice of a payment channel order")
+
+	// ErrNotPaymentChannelOrder is returned when paying the invoice of an order paid another way
+	ErrNotPaymentChannelOrder = errors.New("order is not paid through a payment channel")
+
+	// ErrPanelEscrowUnsupported is returned when a moderator panel is selected for a coin whose wallet can only spend a plain multisig escrow
+	ErrPanelEscrowUnsupported = errors.New("moderator panels are not supported for this currency")
+
+	// ErrPanelEscrowHasNoTimeout is returned when releasing the escrow of an order moderated by a panel after the escrow timeout, which its escrow doesn't have
+	ErrPanelEscrowHasNoTimeout = errors.New("the escrow of an order moderated by a panel has no timeout")
 )
 
 // CodedError is an error that is machine readable
@@ -87,6 +118,103 @@ func (err ErrOutOfInventory) Error() string {
 	return string(jsonBytes)
 }
 
+// ErrModeratorUnavailable is a codedError returned when a buyer selects a
+// moderator who has published an unavailable status
+type ErrModeratorUnavailable struct {
+	CodedError
+	ModeratorID     string `json:"moderatorId"`
+	ReturnDate      string `json:"returnDate,omitempty"`
+	BackupModerator string `json:"backupModerator,omitempty"`
+	Message         string `json:"message,omitempty"`
+}
+
+// NewErrModeratorUnavailable - return moderator unavailable err with the
+// details of the moderator's published status
+func NewErrModeratorUnavailable(moderatorID string, availability *pb.ModeratorAvailability) ErrModeratorUnavailable {
+	err := ErrModeratorUnavailable{
+		CodedError: CodedError{
+			Reason: "moderator is unavailable",
+			Code:   "ERR_MODERATOR_UNAVAILABLE",
+		},
+		ModeratorID:     moderatorID,
+		BackupModerator: availability.BackupModerator,
+		Message:         availability.Message,
+	}
+	if availability.ReturnDate != nil {
+		if returnDate, perr := ptypes.Timestamp(availability.ReturnDate); perr == nil {
+			err.ReturnDate = returnDate.UTC().Format(time.RFC3339)
+		}
+	}
+	return err
+}
+
+func (err ErrModeratorUnavailable) Error() string {
+	jsonBytes, _ := json.Marshal(&err)
+	return string(jsonBytes)
+}
+
+// ErrVendorOnVacation is a codedError returned when an order is placed with a
+// vendor whose store is in vacation mode
+type ErrVendorOnVacation struct {
+	CodedError
+	VendorID   string `json:"vendorId"`
+	ReturnDate string `json:"returnDate,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// NewErrVendorOnVacation - return vendor on vacation err with the details of
+// the vacation published in the vendor's profile
+func NewErrVendorOnVacation(vendorID string, vacation *pb.Profile_VacationMode) ErrVendorOnVacation {
+	err := ErrVendorOnVacation{
+		CodedError: CodedError{
+			Reason: "vendor is on vacation and not accepting orders",
+			Code:   "ERR_VENDOR_ON_VACATION",
+		},
+		VendorID: vendorID,
+	}
+	if vacation == nil {
+		return err
+	}
+	err.Message = vacation.Message
+	if vacation.ReturnDate != nil {
+		if returnDate, perr := ptypes.Timestamp(vacation.ReturnDate); perr == nil {
+			err.ReturnDate = returnDate.UTC().Format(time.RFC3339)
+		}
+	}
+	return err
+}
+
+func (err ErrVendorOnVacation) Error() string {
+	jsonBytes, _ := json.Marshal(&err)
+	return string(jsonBytes)
+}
+
+// ErrMissingFiatValue is a codedError returned when an export in fiat would
+// include transactions with no exchange rate recorded in its currency
+type ErrMissingFiatValue struct {
+	CodedError
+	Currency string   `json:"currency"`
+	Txids    []string `json:"txids"`
+}
+
+// NewErrMissingFiatValue - return missing fiat value err with the
+// transactions lacking a rate
+func NewErrMissingFiatValue(currency string, txids []string) ErrMissingFiatValue {
+	return ErrMissingFiatValue{
+		CodedError: CodedError{
+			Reason: "no exchange rate was recorded for some transactions",
+			Code:   "ERR_MISSING_FIAT_VALUE",
+		},
+		Currency: currency,
+		Txids:    txids,
+	}
+}
+
+func (err ErrMissingFiatValue) Error() string {
+	jsonBytes, _ := json.Marshal(&err)
+	return string(jsonBytes)
+}
+
 // ErrPriceModifierOutOfRange - customize limits for price modifier
 type ErrPriceModifierOutOfRange struct {
 	Min float64
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/OpenBazaar/spvwallet"
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/signer"
+)
+
+// SweepEscrow spends, with our key alone, an escrow we don't need the other
+// party for: the 1 of 2 escrow of a direct payment, or a moderated escrow
+// whose timeout has passed. The funds go to the address, or to the wallet
+// if it is nil. When our signer keeps its keys out of the node we build
+// the transaction and it only signs it. With external signing the sweep is
+// exported and broadcast once the signed packet is imported.
+func (n *OpenBazaarNode) SweepEscrow(wal wallet.Wallet, ins []wallet.TransactionInput, address btcutil.Address, contract *pb.RicardianContract) error {
+	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+	if err != nil {
+		return err
+	}
+	redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
+	if err != nil {
+		return err
+	}
+
+	if holder, ok := n.Signer.(signer.EscrowKeyHolder); ok && !n.ExternalSigning {
+		key, err := holder.EscrowKey(chaincode)
+		if err != nil {
+			return err
+		}
+		if IsPanelOrder(contract.BuyerOrder.Payment) {
+			return sweepPanelEscrow(wal, ins, key, redeemScript)
+		}
+		var to *btcutil.Address
+		if address != nil {
+			to = &address
+		}
+		_, err = wal.SweepAddress(ins, to, key, &redeemScript, wallet.NORMAL)
+		return err
+	}
+
+	orderID, err := n.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		return err
+	}
+	if address == nil {
+		address = wal.CurrentAddress(wallet.INTERNAL)
+	}
+	var total int64
+	for _, in := range ins {
+		total += in.Value
+	}
+	sequenceLock, err := escrowSequenceLock(redeemScript)
+	if err != nil {
+		return err
+	}
+	release := EscrowRelease{
+		OrderID:      orderID,
+		Action:       SigningActionSweep,
+		Inputs:       ins,
+		Outputs:      []wallet.TransactionOutput{{Address: address, Value: total}},
+		Chaincode:    chaincode,
+		RedeemScript: redeemScript,
+		FeePerByte:   wal.GetFeePerByte(wallet.NORMAL),
+		SequenceLock: sequenceLock,
+	}
+	if n.ExternalSigning {
+		packet, request, err := n.escrowSigningRequest(wal, release)
+		if err != nil {
+			return err
+		}
+		return n.finishSigningRequest(wal, packet, request)
+	}
+	sigs, err := n.SignEscrowRelease(wal, release)
+	if err != nil {
+		return err
+	}
+	pubKey, err := n.escrowPublicKey(wal, chaincode)
+	if err != nil {
+		return err
+	}
+	keys, _ := escrowScriptKeys(redeemScript)
+	keySigs := make([][]wallet.Signature, len(keys))
+	for i, key := range keys {
+		if bytes.Equal(key, pubKey) {
+			keySigs[i] = sigs
+		}
+	}
+	return broadcastEscrowRelease(wal, release, keySigs)
+}
+
+// escrowSequenceLock returns the relative lock time of an escrow's timeout
+// branch, or zero for an escrow without one
+func escrowSequenceLock(redeemScript []byte) (uint32, error) {
+	if _, panel := panelEscrowKeys(redeemScript); panel {
+		_, sequenceLock, _ := parsePanelEscrow(redeemScript)
+		if sequenceLock == 0 {
+			return 0, ErrPanelEscrowHasNoTimeout
+		}
+		return sequenceLock, nil
+	}
+	if len(redeemScript) == 0 || redeemScript[0] != txscript.OP_IF {
+		return 0, nil
+	}
+	return spvwallet.LockTimeFromRedeemScript(redeemScript)
+}
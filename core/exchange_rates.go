@@ -0,0 +1,189 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/op/go-logging"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	exchangeRateTestingInterval = time.Duration(5) * time.Minute
+	exchangeRateRegularInterval = time.Duration(1) * time.Hour
+
+	// exchangeRateMaxDistance is how far from the time asked for a sampled
+	// rate may be and still be used as the rate at that time
+	exchangeRateMaxDistance = time.Duration(24) * time.Hour
+)
+
+// ErrExchangeRateNotFound is returned when no rate was sampled near the time
+var ErrExchangeRateNotFound = errors.New("no exchange rate was recorded near that time")
+
+// RateSource gives the current exchange rates of a coin keyed by currency
+// code. The rate recorder samples it for the history of exchange rates.
+type RateSource interface {
+	Rates(coin string) (map[string]float64, error)
+}
+
+// walletRateSource takes the rates the wallets' coins are priced with
+type walletRateSource struct {
+	node *OpenBazaarNode
+}
+
+func (s walletRateSource) Rates(coin string) (map[string]float64, error) {
+	wal, err := s.node.Multiwallet.WalletForCurrencyCode(coin)
+	if err != nil {
+		return nil, err
+	}
+	rates := s.node.ExchangeRatesFor(wal)
+	if rates == nil {
+		return nil, ErrPriceCalculationRequiresExchangeRates
+	}
+	return rates.GetAllRates(true)
+}
+
+// ExchangeRatesFor returns the exchange rates the wallet's coin is priced
+// with. When feeds are configured it is their aggregate, otherwise the
+// wallet's own exchange rate provider, which may be nil.
+func (n *OpenBazaarNode) ExchangeRatesFor(wal wallet.Wallet) wallet.ExchangeRates {
+	own := wal.ExchangeRates()
+	if n.ExchangeRateFeeds == nil {
+		return own
+	}
+	unitsPerCoin := 0
+	if own != nil {
+		unitsPerCoin = own.UnitsPerCoin()
+	}
+	return n.ExchangeRateFeeds.ForCoin(wal.CurrencyCode(), unitsPerCoin)
+}
+
+func (n *OpenBazaarNode) rateSource() RateSource {
+	if n.ExchangeRateSource != nil {
+		return n.ExchangeRateSource
+	}
+	return walletRateSource{n}
+}
+
+// SampleExchangeRates records the current rate of each wallet's coin in
+// every currency we have a definition for
+func (n *OpenBazaarNode) SampleExchangeRates() error {
+	var (
+		currencies = repo.LoadCurrencyDefinitions()
+		source     = n.rateSource()
+		now        = time.Now()
+		samples    []repo.ExchangeRate
+	)
+	for _, wal := range n.Multiwallet {
+		coin := strings.ToUpper(wal.CurrencyCode())
+		rates, err := source.Rates(coin)
+		if err != nil {
+			log.Debugf("sampling %s exchange rates: %s", coin, err)
+			continue
+		}
+		for code, rate := range rates {
+			code = strings.ToUpper(code)
+			if _, err := currencies.Lookup(code); err != nil || rate <= 0 || code == coin {
+				continue
+			}
+			samples = append(samples, repo.ExchangeRate{Coin: coin, Currency: code, Rate: rate, Timestamp: now})
+		}
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	return n.Datastore.ExchangeRates().Put(samples...)
+}
+
+// ExchangeRateAt returns the recorded rate of the coin in the currency
+// nearest to the time
+func (n *OpenBazaarNode) ExchangeRateAt(coin, currency string, t time.Time) (*repo.ExchangeRate, error) {
+	rate, err := n.Datastore.ExchangeRates().GetAt(strings.ToUpper(coin), strings.ToUpper(currency), t)
+	if err != nil {
+		return nil, ErrExchangeRateNotFound
+	}
+	distance := rate.Timestamp.Sub(t)
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance > exchangeRateMaxDistance {
+		return nil, ErrExchangeRateNotFound
+	}
+	return rate, nil
+}
+
+// RecordOrderRates saves the rates an order was priced at, and keeps them
+// as samples of the rate history too
+func (n *OpenBazaarNode) RecordOrderRates(orderID string, rates []repo.ExchangeRate) {
+	if len(rates) == 0 {
+		return
+	}
+	if err := n.Datastore.ExchangeRates().PutOrderRates(orderID, rates); err != nil {
+		log.Errorf("recording exchange rates of order %s: %s", orderID, err)
+	}
+	if err := n.Datastore.ExchangeRates().Put(rates...); err != nil {
+		log.Errorf("recording exchange rate samples: %s", err)
+	}
+}
+
+// usedRates collects the exchange rates a price calculation used. A nil
+// collector drops them.
+type usedRates struct {
+	rates []repo.ExchangeRate
+}
+
+func (u *usedRates) add(coin, currency string, rate float64) {
+	if u == nil || coin == currency {
+		return
+	}
+	for _, r := range u.rates {
+		if r.Coin == coin && r.Currency == currency {
+			return
+		}
+	}
+	u.rates = append(u.rates, repo.ExchangeRate{Coin: coin, Currency: currency, Rate: rate, Timestamp: time.Now()})
+}
+
+func (u *usedRates) list() []repo.ExchangeRate {
+	if u == nil {
+		return nil
+	}
+	return u.rates
+}
+
+type exchangeRateRecorder struct {
+	node          *OpenBazaarNode
+	intervalDelay time.Duration
+	logger        *logging.Logger
+}
+
+// StartExchangeRateRecorder starts a worker which periodically samples the
+// exchange rates into the rate history
+func (n *OpenBazaarNode) StartExchangeRateRecorder() {
+	interval := exchangeRateRegularInterval
+	if n.TestnetEnable {
+		interval = exchangeRateTestingInterval
+	}
+	recorder := &exchangeRateRecorder{
+		node:          n,
+		intervalDelay: interval,
+		logger:        logging.MustGetLogger("exchangeRateRecorder"),
+	}
+	go recorder.Run()
+}
+
+func (r *exchangeRateRecorder) Run() {
+	r.sample()
+	ticker := time.NewTicker(r.intervalDelay)
+	for range ticker.C {
+		r.sample()
+	}
+}
+
+func (r *exchangeRateRecorder) sample() {
+	if err := r.node.SampleExchangeRates(); err != nil {
+		r.logger.Errorf("sampling exchange rates: %s", err)
+	}
+}
@@ -0,0 +1,205 @@
+package core_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/wallet/exchangerates"
+)
+
+// staticRates is an exchange rate provider and rate source with fixed rates
+type staticRates map[string]float64
+
+func (r staticRates) GetExchangeRate(currencyCode string) (float64, error) {
+	rate, ok := r[currencyCode]
+	if !ok {
+		return 0, core.ErrExchangeRateNotFound
+	}
+	return rate, nil
+}
+func (r staticRates) GetLatestRate(currencyCode string) (float64, error) {
+	return r.GetExchangeRate(currencyCode)
+}
+func (r staticRates) GetAllRates(cacheOK bool) (map[string]float64, error) { return r, nil }
+func (r staticRates) UnitsPerCoin() int                                    { return 100000000 }
+func (r staticRates) Rates(coin string) (map[string]float64, error)        { return r, nil }
+
+// ratesWallet is a wallet with an exchange rate provider
+type ratesWallet struct {
+	wallet.Wallet
+	code  string
+	rates wallet.ExchangeRates
+}
+
+func (w *ratesWallet) CurrencyCode() string {
+	if w.code != "" {
+		return w.code
+	}
+	return w.Wallet.CurrencyCode()
+}
+func (w *ratesWallet) ExchangeRates() wallet.ExchangeRates { return w.rates }
+
+func TestSampleExchangeRates(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	coin := "R" + strings.ToUpper(randomTxid(t)[:7])
+	coinType := util.ExtCoinType(999997)
+	node.Multiwallet[coinType] = &ratesWallet{Wallet: btc, code: coin}
+	defer delete(node.Multiwallet, coinType)
+	node.ExchangeRateSource = staticRates{"USD": 6500, "eur": 5500, "NOTACURRENCY": 1, "JPY": 0}
+
+	if err := node.SampleExchangeRates(); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	rate, err := node.ExchangeRateAt(coin, "usd", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Rate != 6500 || rate.Coin != coin || rate.Currency != "USD" {
+		t.Errorf("unexpected rate %+v", rate)
+	}
+	if rate, err := node.ExchangeRateAt(coin, "EUR", now); err != nil || rate.Rate != 5500 {
+		t.Errorf("expected the EUR rate to be sampled, got %v %v", rate, err)
+	}
+	for _, currency := range []string{"NOTACURRENCY", "JPY"} {
+		if _, err := node.ExchangeRateAt(coin, currency, now); err != core.ErrExchangeRateNotFound {
+			t.Errorf("expected no %s rate to be sampled, got %v", currency, err)
+		}
+	}
+	if _, err := node.ExchangeRateAt(coin, "USD", now.Add(-48*time.Hour)); err != core.ErrExchangeRateNotFound {
+		t.Errorf("expected a sample two days away not to be used, got %v", err)
+	}
+}
+
+func TestCalculateOrderTotalRecordsRates(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for coinType, wal := range node.Multiwallet {
+		if wal == btc {
+			node.Multiwallet[coinType] = &ratesWallet{Wallet: wal, rates: staticRates{"BTC": 1, "USD": 5000}}
+			defer func(coinType util.ExtCoinType) { node.Multiwallet[coinType] = btc }(coinType)
+		}
+	}
+
+	contract := usdDigitalGoodOrder(t)
+	total, rates, err := node.CalculateOrderTotalAndRates(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 200000 {
+		t.Errorf("expected $10 at $5000 to be 200000 satoshi, got %d", total)
+	}
+	if len(rates) != 1 || rates[0].Coin != "BTC" || rates[0].Currency != "USD" || rates[0].Rate != 5000 {
+		t.Fatalf("expected the BTC/USD rate to be used, got %+v", rates)
+	}
+
+	orderID := "order-" + randomTxid(t)
+	node.RecordOrderRates(orderID, rates)
+	saved, err := node.Datastore.ExchangeRates().GetOrderRates(orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 1 || saved[0].Rate != 5000 {
+		t.Errorf("expected the order's rate to be saved, got %+v", saved)
+	}
+	if _, err := node.ExchangeRateAt("BTC", "USD", time.Now()); err != nil {
+		t.Errorf("expected the order's rate to be kept in the history, got %v", err)
+	}
+}
+
+// usdDigitalGoodOrder is an order for a $10 digital good paid in BTC
+func usdDigitalGoodOrder(t *testing.T) *pb.RicardianContract {
+	contract := &pb.RicardianContract{
+		VendorListings: []*pb.Listing{{
+			Metadata: &pb.Listing_Metadata{
+				ContractType:       pb.Listing_Metadata_DIGITAL_GOOD,
+				Format:             pb.Listing_Metadata_FIXED_PRICE,
+				AcceptedCurrencies: []string{"BTC"},
+				PricingCurrency:    "USD",
+				Version:            2,
+			},
+			Item: &pb.Listing_Item{
+				Price: 1000,
+			},
+		}},
+	}
+	ser, err := proto.Marshal(contract.VendorListings[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	listingID, err := core.EncodeCID(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract.BuyerOrder = &pb.Order{
+		Items:   []*pb.Order_Item{{ListingHash: listingID.String(), Quantity: 1}},
+		Payment: &pb.Order_Payment{Coin: "BTC"},
+	}
+	return contract
+}
+
+func TestCalculateOrderTotalWithFeeds(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "feeds")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	newFeeds := func(usd ...float64) *exchangerates.Feeds {
+		var providers []exchangerates.Provider
+		for i, rate := range usd {
+			path := filepath.Join(dir, fmt.Sprintf("feed%d.json", i))
+			doc := fmt.Sprintf(`{"timestamp": %d, "rates": {"BTC": {"BTC": 1, "USD": %f}}}`, time.Now().Unix(), rate)
+			if err := ioutil.WriteFile(path, []byte(doc), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			providers = append(providers, exchangerates.NewFileProvider(path))
+		}
+		return exchangerates.New(providers, exchangerates.Options{MaxDeviation: 0.05})
+	}
+	contract := usdDigitalGoodOrder(t)
+
+	node.ExchangeRateFeeds = newFeeds(4990, 5000, 5010, 9000)
+	total, rates, err := node.CalculateOrderTotalAndRates(contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 200000 {
+		t.Errorf("expected $10 at the median $5000 after dropping the outlier, got %d", total)
+	}
+	if len(rates) != 1 || rates[0].Rate != 5000 {
+		t.Errorf("expected the aggregated rate to be recorded, got %+v", rates)
+	}
+
+	node.ExchangeRateFeeds = newFeeds(5000, 8000)
+	if _, err := node.CalculateOrderTotal(contract); err != exchangerates.ErrRatesDisagree {
+		t.Errorf("expected pricing to be refused when the feeds disagree, got %v", err)
+	}
+}
@@ -0,0 +1,994 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcutil/txsort"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/wallet/psbt"
+)
+
+// Actions of the transactions exported for external signing
+const (
+	SigningActionSpend             = "spend"
+	SigningActionCompletion        = "completion"
+	SigningActionDecline           = "decline"
+	SigningActionFulfillment       = "fulfillment"
+	SigningActionRefund            = "refund"
+	SigningActionDisputeResolution = "disputeResolution"
+	SigningActionReleaseFunds      = "releaseFunds"
+	SigningActionPanelPayout       = "panelPayout"
+	SigningActionFallbackPayout    = "fallbackPayout"
+	SigningActionCaseHandover      = "caseHandover"
+	SigningActionSweep             = "sweep"
+)
+
+// escrowChaincodeSubtype is the subtype of the proprietary input entry which
+// tells an external signer how to derive our escrow key. Its key data is the
+// escrow public key and its value the order's chaincode.
+const escrowChaincodeSubtype = 1
+
+var psbtIdentifier = []byte("openbazaar")
+
+var (
+	// ErrAwaitingExternalSignature is returned when a transaction has been
+	// exported for signing outside the node. The action can be retried once
+	// the signed packet has been imported.
+	ErrAwaitingExternalSignature = errors.New("ERROR_AWAITING_EXTERNAL_SIGNATURE")
+
+	// ErrExternalSigningUnsupported is returned for a transaction the node can't export for signing outside of it
+	ErrExternalSigningUnsupported = errors.New("ERROR_EXTERNAL_SIGNING_UNSUPPORTED")
+
+	// ErrSigningRequestNotFound is returned when an imported packet doesn't spend a transaction we exported
+	ErrSigningRequestNotFound = errors.New("ERROR_SIGNING_REQUEST_NOT_FOUND")
+
+	// ErrInvalidSigningRequest is returned when an imported packet can't be parsed or has signatures we didn't ask for
+	ErrInvalidSigningRequest = errors.New("ERROR_INVALID_SIGNING_REQUEST")
+)
+
+// EscrowRelease is a transaction spending an order's escrow
+type EscrowRelease struct {
+	OrderID      string
+	Action       string
+	Inputs       []wallet.TransactionInput
+	Outputs      []wallet.TransactionOutput
+	Chaincode    []byte
+	RedeemScript []byte
+	FeePerByte   uint64
+
+	// LockTime is the time before which the transaction can't be mined, or
+	// zero. The wallet can't build a transaction with a lock time so we do.
+	LockTime uint32
+
+	// SequenceLock is the relative lock time of the escrow's timeout branch
+	// when the release spends it with the vendor's key alone, or zero
+	SequenceLock uint32
+}
+
+// NewEscrowRelease returns a transaction spending the escrow of a contract
+func (n *OpenBazaarNode) NewEscrowRelease(contract *pb.RicardianContract, action string, ins []wallet.TransactionInput, outs []wallet.TransactionOutput, feePerByte uint64) (EscrowRelease, error) {
+	orderID, err := n.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
+	if err != nil {
+		return EscrowRelease{}, err
+	}
+	return EscrowRelease{
+		OrderID:      orderID,
+		Action:       action,
+		Inputs:       ins,
+		Outputs:      outs,
+		Chaincode:    chaincode,
+		RedeemScript: redeemScript,
+		FeePerByte:   feePerByte,
+	}, nil
+}
+
+// SignEscrowRelease returns our signatures for a transaction spending an
+// order's escrow. With external signing the transaction is exported as a
+// signing request and ErrAwaitingExternalSignature is returned until the
+// signed packet has been imported.
+func (n *OpenBazaarNode) SignEscrowRelease(wal wallet.Wallet, release EscrowRelease) ([]wallet.Signature, error) {
+	if !n.ExternalSigning {
+		holder, ok := n.Signer.(signer.EscrowKeyHolder)
+		if !ok {
+			return n.signEscrowRelease(wal, release)
+		}
+		key, err := holder.EscrowKey(release.Chaincode)
+		if err != nil {
+			return nil, err
+		}
+		if buildsOwnReleaseTx(release) {
+			tx, segwit, err := escrowReleaseTx(wal, release)
+			if err != nil {
+				return nil, err
+			}
+			return signReleaseTx(tx, segwit, release, key)
+		}
+		return wal.CreateMultisigSignature(release.Inputs, release.Outputs, key, release.RedeemScript, release.FeePerByte)
+	}
+
+	packet, _, err := n.escrowSigningRequest(wal, release)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := n.escrowPublicKey(wal, release.Chaincode)
+	if err != nil {
+		return nil, err
+	}
+	var sigs []wallet.Signature
+	for i, in := range packet.Inputs {
+		sig := partialSig(in, pubKey)
+		if sig == nil {
+			return nil, ErrAwaitingExternalSignature
+		}
+		sigs = append(sigs, wallet.Signature{InputIndex: uint32(i), Signature: sig})
+	}
+	return sigs, nil
+}
+
+// ReleaseEscrow signs and broadcasts, as the buyer, a transaction spending
+// an order's escrow with the vendor's signatures. With external signing their
+// signatures are kept with the signing request and the transaction is
+// broadcast when ours are imported.
+func (n *OpenBazaarNode) ReleaseEscrow(wal wallet.Wallet, release EscrowRelease, theirSigs []wallet.Signature) error {
+	if !n.ExternalSigning {
+		mySigs, err := n.SignEscrowRelease(wal, release)
+		if err != nil {
+			return err
+		}
+		return MultisignEscrow(wal, release.Inputs, release.Outputs, mySigs, theirSigs, release.RedeemScript, release.FeePerByte)
+	}
+
+	packet, request, err := n.escrowSigningRequest(wal, release)
+	if err != nil {
+		return err
+	}
+	pubKeys, _ := escrowScriptKeys(release.RedeemScript)
+	for _, sig := range theirSigs {
+		i := int(sig.InputIndex)
+		if i >= len(packet.Inputs) {
+			return ErrInvalidSigningRequest
+		}
+		added := false
+		for _, key := range pubKeys {
+			if packet.AddPartialSig(i, key, sig.Signature) == nil {
+				added = true
+				break
+			}
+		}
+		if !added {
+			return fmt.Errorf("invalid signature for input %d", i)
+		}
+	}
+	return n.finishSigningRequest(wal, packet, request)
+}
+
+// ImportSigningRequest adds the signatures of a packet signed outside the
+// node to the signing request for its transaction. The transaction is
+// broadcast once it has every signature it needs.
+func (n *OpenBazaarNode) ImportSigningRequest(encoded string) (*repo.SigningRequest, error) {
+	imported, err := psbt.NewFromBase64(encoded)
+	if err != nil {
+		return nil, ErrInvalidSigningRequest
+	}
+	request, err := n.Datastore.SigningRequests().Get(imported.UnsignedTx.TxHash().String())
+	if err != nil {
+		return nil, ErrSigningRequestNotFound
+	}
+	if request.State == repo.SigningRequestBroadcast {
+		return request, nil
+	}
+	packet, err := psbt.Parse(bytes.NewReader(request.PSBT))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, in := range imported.Inputs {
+		allowed := signingKeys(packet.Inputs[i])
+		for _, sig := range in.PartialSigs {
+			if !containsKey(allowed, sig.PubKey) || packet.AddPartialSig(i, sig.PubKey, sig.Signature) != nil {
+				return nil, ErrInvalidSigningRequest
+			}
+		}
+	}
+
+	wal, err := n.Multiwallet.WalletForCurrencyCode(request.Wallet)
+	if err != nil {
+		return nil, ErrUnknownWallet
+	}
+	if err := n.finishSigningRequest(wal, packet, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// signEscrowRelease signs an escrow release with a signer which doesn't
+// hold its keys in the node, checking each signature it returns
+func (n *OpenBazaarNode) signEscrowRelease(wal wallet.Wallet, release EscrowRelease) ([]wallet.Signature, error) {
+	packet, err := n.escrowPacket(wal, release)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := n.escrowPublicKey(wal, release.Chaincode)
+	if err != nil {
+		return nil, err
+	}
+	var sigs []wallet.Signature
+	for i := range packet.Inputs {
+		hash, err := packet.SignatureHash(i)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := n.Signer.SignEscrow(release.Chaincode, hash)
+		if err != nil {
+			return nil, err
+		}
+		sig = append(sig, byte(txscript.SigHashAll))
+		if err := packet.AddPartialSig(i, pubKey, sig); err != nil {
+			return nil, fmt.Errorf("signer returned an invalid signature for input %d: %s", i, err)
+		}
+		sigs = append(sigs, wallet.Signature{InputIndex: uint32(i), Signature: sig})
+	}
+	return sigs, nil
+}
+
+// escrowSigningRequest returns the packet and signing request for an escrow
+// release, exporting the transaction if it hasn't been. A request for an
+// earlier version of the same release is replaced.
+func (n *OpenBazaarNode) escrowSigningRequest(wal wallet.Wallet, release EscrowRelease) (*psbt.Packet, *repo.SigningRequest, error) {
+	packet, err := n.escrowPacket(wal, release)
+	if err != nil {
+		return nil, nil, err
+	}
+	id := packet.UnsignedTx.TxHash().String()
+
+	if request, err := n.Datastore.SigningRequests().Get(id); err == nil {
+		existing, err := psbt.Parse(bytes.NewReader(request.PSBT))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(existing.Inputs) > 0 && bytes.Equal(escrowScript(existing.Inputs[0]), release.RedeemScript) {
+			return existing, request, nil
+		}
+	}
+
+	if release.OrderID != "" {
+		earlier, err := n.Datastore.SigningRequests().GetAll("", release.OrderID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range earlier {
+			if r.Action == release.Action && r.State != repo.SigningRequestBroadcast {
+				if err := n.Datastore.SigningRequests().Delete(r.ID); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	request := &repo.SigningRequest{
+		ID:        id,
+		OrderID:   release.OrderID,
+		Wallet:    wal.CurrencyCode(),
+		Action:    release.Action,
+		Timestamp: time.Now(),
+	}
+	if err := n.putSigningRequest(packet, request, repo.SigningRequestPending); err != nil {
+		return nil, nil, err
+	}
+	return packet, request, nil
+}
+
+// escrowPacket returns the unsigned packet of an escrow release, built as
+// the wallet builds it
+func (n *OpenBazaarNode) escrowPacket(wal wallet.Wallet, release EscrowRelease) (*psbt.Packet, error) {
+	// Bitcoin Cash signs with its own sighash algorithm, which a packet can't carry
+	if code := strings.ToUpper(wal.CurrencyCode()); code == "BCH" || code == "TBCH" {
+		return nil, ErrExternalSigningUnsupported
+	}
+	tx, segwit, err := escrowReleaseTx(wal, release)
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey, err := n.escrowPublicKey(wal, release.Chaincode)
+	if err != nil {
+		return nil, err
+	}
+	packet, err := psbt.New(tx)
+	if err != nil {
+		return nil, err
+	}
+	scriptHash := sha256.Sum256(release.RedeemScript)
+	witnessPkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+	for i, in := range tx.TxIn {
+		if segwit {
+			packet.Inputs[i].WitnessScript = release.RedeemScript
+			for _, input := range release.Inputs {
+				if hex.EncodeToString(input.OutpointHash) == in.PreviousOutPoint.Hash.String() && input.OutpointIndex == in.PreviousOutPoint.Index {
+					packet.Inputs[i].WitnessUtxo = wire.NewTxOut(input.Value, witnessPkScript)
+				}
+			}
+			if packet.Inputs[i].WitnessUtxo == nil {
+				return nil, ErrExternalSigningUnsupported
+			}
+		} else {
+			packet.Inputs[i].RedeemScript = release.RedeemScript
+			packet.Inputs[i].NonWitnessUtxo = previousTransaction(wal, in.PreviousOutPoint)
+		}
+		packet.Inputs[i].Proprietary = []psbt.Proprietary{{
+			Identifier: psbtIdentifier,
+			Subtype:    escrowChaincodeSubtype,
+			KeyData:    pubKey,
+			Value:      release.Chaincode,
+		}}
+	}
+
+	return packet, nil
+}
+
+// escrowReleaseTx returns the unsigned transaction of an escrow release and
+// whether the escrow is segwit. The wallet builds the spend of a plain
+// multisig escrow and we build the others.
+func escrowReleaseTx(wal wallet.Wallet, release EscrowRelease) (*wire.MsgTx, bool, error) {
+	if buildsOwnReleaseTx(release) {
+		segwit, err := escrowSegwit(wal, release.RedeemScript)
+		if err != nil {
+			return nil, false, err
+		}
+		tx, err := releaseTx(release, segwit)
+		return tx, segwit, err
+	}
+	raw, err := wal.Multisign(release.Inputs, release.Outputs, nil, nil, release.RedeemScript, release.FeePerByte, false)
+	if err != nil {
+		return nil, false, err
+	}
+	tx := wire.NewMsgTx(1)
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, false, ErrExternalSigningUnsupported
+	}
+	segwit := false
+	for _, in := range tx.TxIn {
+		segwit = segwit || len(in.Witness) > 0
+		in.SignatureScript = nil
+		in.Witness = nil
+	}
+	return tx, segwit, nil
+}
+
+// buildsOwnReleaseTx returns true if we build the transaction of an escrow
+// release rather than the wallet, which can only spend a plain multisig
+// escrow with all the signatures it needs and without a lock time
+func buildsOwnReleaseTx(release EscrowRelease) bool {
+	_, panel := panelEscrowKeys(release.RedeemScript)
+	return panel || release.LockTime > 0 || release.SequenceLock > 0 || release.Action == SigningActionSweep
+}
+
+// escrowSegwit returns true if an escrow is paid to a witness script hash,
+// which is the case if the wallet's own multisig addresses are
+func escrowSegwit(wal wallet.Wallet, redeemScript []byte) (bool, error) {
+	pubKeys, _ := escrowScriptKeys(redeemScript)
+	if len(pubKeys) < 2 {
+		return false, errors.New("invalid escrow script")
+	}
+	var parties []hd.ExtendedKey
+	for _, key := range pubKeys[:2] {
+		parties = append(parties, *hd.NewExtendedKey([]byte{0, 0, 0, 0}, key, make([]byte, 32), []byte{0, 0, 0, 0}, 0, 0, false))
+	}
+	addr, _, err := wal.GenerateMultisigScript(parties, 2, 0, nil)
+	if err != nil {
+		return false, err
+	}
+	switch addr.(type) {
+	case *btcutil.AddressScriptHash:
+		return false, nil
+	case *btcutil.AddressWitnessScriptHash:
+		return true, nil
+	}
+	return false, ErrExternalSigningUnsupported
+}
+
+// escrowSpendStack returns the items which satisfy an escrow given one
+// input's signatures in the order of the script's keys, or false if they
+// are not enough to spend it
+func escrowSpendStack(redeemScript []byte, sigs [][]byte) ([][]byte, bool) {
+	if _, sequenceLock, ok := parsePanelEscrow(redeemScript); ok {
+		return panelSpendStack(sigs, sequenceLock > 0)
+	}
+	pubKeys, threshold := escrowScriptKeys(redeemScript)
+	if threshold == 0 || len(sigs) != len(pubKeys) {
+		return nil, false
+	}
+	// OP_CHECKMULTISIG pops an extra item
+	stack := [][]byte{{}}
+	for _, sig := range sigs {
+		if sig != nil && len(stack) <= threshold {
+			stack = append(stack, sig)
+		}
+	}
+	if len(stack) <= threshold {
+		return nil, false
+	}
+	if redeemScript[0] == txscript.OP_IF {
+		stack = append(stack, []byte{0x01})
+	}
+	return stack, true
+}
+
+// releaseTx returns the unsigned transaction of an escrow release. The fee
+// is worked out from the size of the transaction with the largest
+// signatures it could have and split between the outputs. A sequence lock
+// gives the vendor's spend of an escrow after its timeout.
+func releaseTx(release EscrowRelease, segwit bool) (*wire.MsgTx, error) {
+	tx := wire.NewMsgTx(1)
+	if release.SequenceLock > 0 {
+		tx.Version = 2
+	}
+	tx.LockTime = release.LockTime
+	for _, in := range release.Inputs {
+		hash, err := chainhash.NewHashFromStr(hex.EncodeToString(in.OutpointHash))
+		if err != nil {
+			return nil, err
+		}
+		txIn := wire.NewTxIn(wire.NewOutPoint(hash, in.OutpointIndex), nil, nil)
+		switch {
+		case release.SequenceLock > 0:
+			txIn.Sequence = release.SequenceLock
+		case release.LockTime > 0:
+			// The lock time is ignored when every input is final
+			txIn.Sequence = wire.MaxTxInSequenceNum - 1
+		}
+		tx.AddTxIn(txIn)
+	}
+	for _, out := range release.Outputs {
+		script, err := txscript.PayToAddrScript(out.Address)
+		if err != nil {
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(out.Value, script))
+	}
+
+	sig := make([]byte, 73)
+	stack := timeoutSpendStack(release.RedeemScript, sig)
+	if release.SequenceLock == 0 {
+		pubKeys, _ := escrowScriptKeys(release.RedeemScript)
+		sigs := make([][]byte, len(pubKeys))
+		for i := range sigs {
+			sigs[i] = sig
+		}
+		var ok bool
+		if stack, ok = escrowSpendStack(release.RedeemScript, sigs); !ok {
+			return nil, errors.New("invalid escrow script")
+		}
+	}
+	sized := tx.Copy()
+	for _, in := range sized.TxIn {
+		if err := finishEscrowSpend(in, stack, release.RedeemScript, segwit); err != nil {
+			return nil, err
+		}
+	}
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(sized))
+	size := (weight + blockchain.WitnessScaleFactor - 1) / blockchain.WitnessScaleFactor
+	if len(tx.TxOut) > 0 {
+		feePerOutput := size * int64(release.FeePerByte) / int64(len(tx.TxOut))
+		for _, out := range tx.TxOut {
+			if out.Value -= feePerOutput; out.Value <= 0 {
+				return nil, wallet.ErrorInsuffientFunds
+			}
+		}
+	}
+
+	txsort.InPlaceSort(tx)
+	return tx, nil
+}
+
+// signReleaseTx returns the signatures of a key for each input of an escrow
+// release we built
+func signReleaseTx(tx *wire.MsgTx, segwit bool, release EscrowRelease, key *hd.ExtendedKey) ([]wallet.Signature, error) {
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	hashes := txscript.NewTxSigHashes(tx)
+	var sigs []wallet.Signature
+	for i, txIn := range tx.TxIn {
+		var sig []byte
+		if segwit {
+			var value int64
+			for _, in := range release.Inputs {
+				if hex.EncodeToString(in.OutpointHash) == txIn.PreviousOutPoint.Hash.String() && in.OutpointIndex == txIn.PreviousOutPoint.Index {
+					value = in.Value
+				}
+			}
+			sig, err = txscript.RawTxInWitnessSignature(tx, hashes, i, value, release.RedeemScript, txscript.SigHashAll, privKey)
+		} else {
+			sig, err = txscript.RawTxInSignature(tx, i, release.RedeemScript, txscript.SigHashAll, privKey)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, wallet.Signature{InputIndex: uint32(i), Signature: sig})
+	}
+	return sigs, nil
+}
+
+// broadcastEscrowRelease finishes an escrow release we built with the
+// signatures, given in the order of the escrow's keys, and broadcasts it
+func broadcastEscrowRelease(wal wallet.Wallet, release EscrowRelease, sigs [][]wallet.Signature) error {
+	pw, ok := wal.(panelEscrowWallet)
+	if !ok {
+		return ErrPanelEscrowUnsupported
+	}
+	tx, segwit, err := escrowReleaseTx(wal, release)
+	if err != nil {
+		return err
+	}
+	for i, in := range tx.TxIn {
+		inputSigs := make([][]byte, len(sigs))
+		for k, keySigs := range sigs {
+			for _, sig := range keySigs {
+				if int(sig.InputIndex) == i {
+					inputSigs[k] = sig.Signature
+				}
+			}
+		}
+		stack, ok := escrowSpendStack(release.RedeemScript, inputSigs)
+		if release.SequenceLock > 0 {
+			stack, ok = nil, false
+			for _, sig := range inputSigs {
+				if sig != nil {
+					stack, ok = timeoutSpendStack(release.RedeemScript, sig), true
+				}
+			}
+		}
+		if !ok {
+			return errors.New("escrow release is missing signatures")
+		}
+		if err := finishEscrowSpend(in, stack, release.RedeemScript, segwit); err != nil {
+			return err
+		}
+	}
+	return pw.Broadcast(tx)
+}
+
+// verifyEscrowSigs checks the owner of an escrow key signed every input of
+// an escrow release
+func (n *OpenBazaarNode) verifyEscrowSigs(wal wallet.Wallet, release EscrowRelease, sigs []*pb.BitcoinSignature, pubKey []byte) error {
+	packet, err := n.escrowPacket(wal, release)
+	if err != nil {
+		return err
+	}
+	signed := make(map[int]bool)
+	for _, sig := range sigs {
+		i := int(sig.InputIndex)
+		if i >= len(packet.Inputs) || packet.VerifyPartialSig(i, pubKey, sig.Signature) != nil {
+			return errors.New("invalid escrow signature")
+		}
+		signed[i] = true
+	}
+	if len(signed) != len(packet.Inputs) {
+		return errors.New("escrow signatures do not cover every input")
+	}
+	return nil
+}
+
+// exportSpend saves a spend as a signing request for the keys of the coins
+// it spends
+func (n *OpenBazaarNode) exportSpend(wal wallet.Wallet, args *SpendRequest, tx *wire.MsgTx, coins []spendCoin, address string) (*repo.SigningRequest, string, error) {
+	masterPub, err := n.MasterPublicKey.ECPubKey()
+	if err != nil {
+		return nil, "", err
+	}
+	fingerprint := binary.LittleEndian.Uint32(btcutil.Hash160(masterPub.SerializeCompressed())[:4])
+	coinType := n.walletCoinType(wal)
+	internal, external, err := n.walletChainKeys(wal)
+	if err != nil {
+		return nil, "", err
+	}
+
+	store, err := n.walletStore(wal)
+	if err != nil {
+		return nil, "", err
+	}
+
+	packet, err := psbt.New(tx)
+	if err != nil {
+		return nil, "", err
+	}
+	for i, in := range tx.TxIn {
+		coin, err := spendCoinFor(coins, in.PreviousOutPoint)
+		if err != nil {
+			return nil, "", err
+		}
+		keyPath, err := store.Keys().GetPathForKey(coin.addr.ScriptAddress())
+		if err != nil {
+			return nil, "", ErrSpendUtxoUnavailable
+		}
+		pubKey, err := spendPublicKey(internal, external, keyPath)
+		if err != nil {
+			return nil, "", err
+		}
+		packet.Inputs[i].NonWitnessUtxo = previousTransaction(wal, in.PreviousOutPoint)
+		if packet.Inputs[i].NonWitnessUtxo == nil {
+			packet.Inputs[i].WitnessUtxo = wire.NewTxOut(coin.utxo.Value, coin.utxo.ScriptPubkey)
+		}
+		packet.Inputs[i].Bip32Derivation = []psbt.Bip32Derivation{{
+			PubKey:               pubKey.SerializeCompressed(),
+			MasterKeyFingerprint: fingerprint,
+			Path: []uint32{hd.HardenedKeyStart + 44, hd.HardenedKeyStart + uint32(coinType), hd.HardenedKeyStart,
+				uint32(keyPath.Purpose), uint32(keyPath.Index)},
+		}}
+	}
+
+	action := args.action
+	if action == "" {
+		action = SigningActionSpend
+	}
+	request := &repo.SigningRequest{
+		ID:        tx.TxHash().String(),
+		OrderID:   args.OrderID,
+		Wallet:    wal.CurrencyCode(),
+		Action:    action,
+		Address:   address,
+		Memo:      args.Memo,
+		Timestamp: time.Now(),
+	}
+	if err := n.putSigningRequest(packet, request, repo.SigningRequestPending); err != nil {
+		return nil, "", err
+	}
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		return nil, "", err
+	}
+	return request, encoded, nil
+}
+
+// exportedSpendTxid returns the txid of a spend made for an order action
+// once its signed packet has been imported and broadcast. Until then the
+// spend is exported, if it hasn't been, and ErrAwaitingExternalSignature is
+// returned.
+func (n *OpenBazaarNode) exportedSpendTxid(wal wallet.Wallet, args *SpendRequest, contract *pb.RicardianContract) (string, error) {
+	requests, err := n.Datastore.SigningRequests().GetAll("", args.OrderID)
+	if err != nil {
+		return "", err
+	}
+	for _, r := range requests {
+		if r.Action != args.action {
+			continue
+		}
+		if r.State != repo.SigningRequestBroadcast {
+			return "", ErrAwaitingExternalSignature
+		}
+		packet, err := psbt.Parse(bytes.NewReader(r.PSBT))
+		if err != nil {
+			return "", err
+		}
+		tx, complete, err := finalizePacket(packet)
+		if err != nil {
+			return "", err
+		}
+		if !complete {
+			return "", ErrInvalidSigningRequest
+		}
+		return tx.TxHash().String(), nil
+	}
+	if _, err := n.spendWithCoinControl(wal, args, wallet.NORMAL, contract); err != nil {
+		return "", err
+	}
+	return "", ErrAwaitingExternalSignature
+}
+
+// finishSigningRequest broadcasts the transaction of a request if it has
+// every signature it needs, otherwise records whether it holds ours
+func (n *OpenBazaarNode) finishSigningRequest(wal wallet.Wallet, packet *psbt.Packet, request *repo.SigningRequest) error {
+	tx, complete, err := finalizePacket(packet)
+	if err != nil {
+		return err
+	}
+	if !complete {
+		state := repo.SigningRequestPending
+		if hasOurSignatures(packet) {
+			state = repo.SigningRequestSigned
+		}
+		return n.putSigningRequest(packet, request, state)
+	}
+
+	ccw, ok := wal.(coinControlWallet)
+	if !ok {
+		return ErrExternalSigningUnsupported
+	}
+	if err := ccw.Broadcast(tx); err != nil {
+		return err
+	}
+	if request.Action == SigningActionSpend || request.Action == SigningActionRefund {
+		if err := n.Datastore.TxMetadata().Put(repo.Metadata{
+			Txid:    tx.TxHash().String(),
+			Address: request.Address,
+			Memo:    request.Memo,
+			OrderID: request.OrderID,
+		}); err != nil {
+			return fmt.Errorf("failed persisting transaction metadata: %s", err)
+		}
+	}
+	return n.putSigningRequest(packet, request, repo.SigningRequestBroadcast)
+}
+
+// putSigningRequest saves a request with its packet, notifying the user when
+// its state changes
+func (n *OpenBazaarNode) putSigningRequest(packet *psbt.Packet, request *repo.SigningRequest, state string) error {
+	b, err := packet.Bytes()
+	if err != nil {
+		return err
+	}
+	changed := request.State != state
+	request.State = state
+	request.PSBT = b
+	if err := n.Datastore.SigningRequests().Put(*request); err != nil {
+		return err
+	}
+	if changed {
+		notif := repo.SigningRequestNotification{
+			ID:        repo.NewNotificationID(),
+			Type:      repo.NotifierTypeSigningRequest,
+			RequestID: request.ID,
+			OrderID:   request.OrderID,
+			Action:    request.Action,
+			State:     request.State,
+		}
+		n.Broadcast <- notif
+		n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, time.Now(), false))
+	}
+	return nil
+}
+
+// finalizePacket returns the signed transaction of a packet and whether
+// every input has the signatures it needs. Escrow signatures are given in
+// the order of their keys in the redeem script, in the witness of a segwit
+// escrow. An input spending an escrow's timeout branch needs only ours.
+func finalizePacket(packet *psbt.Packet) (*wire.MsgTx, bool, error) {
+	tx := packet.UnsignedTx.Copy()
+	for i := range packet.Inputs {
+		in := &packet.Inputs[i]
+		if script := escrowScript(*in); script != nil && spendsTimeoutBranch(tx, i) {
+			sig := partialSig(*in, ourEscrowKey(*in))
+			if sig == nil {
+				return tx, false, nil
+			}
+			if err := finishEscrowSpend(tx.TxIn[i], timeoutSpendStack(script, sig), script, in.WitnessScript != nil); err != nil {
+				return nil, false, err
+			}
+			in.FinalScriptSig = tx.TxIn[i].SignatureScript
+			in.FinalScriptWitness = tx.TxIn[i].Witness
+			continue
+		}
+		if keys, sequenceLock, ok := parsePanelEscrow(escrowScript(*in)); ok {
+			sigs := make([][]byte, len(keys))
+			for k, key := range keys {
+				sigs[k] = partialSig(*in, key)
+			}
+			stack, ok := panelSpendStack(sigs, sequenceLock > 0)
+			if !ok {
+				return tx, false, nil
+			}
+			if err := finishEscrowSpend(tx.TxIn[i], stack, escrowScript(*in), in.WitnessScript != nil); err != nil {
+				return nil, false, err
+			}
+			in.FinalScriptSig = tx.TxIn[i].SignatureScript
+			in.FinalScriptWitness = tx.TxIn[i].Witness
+			continue
+		}
+		if in.WitnessScript != nil {
+			pubKeys, threshold := escrowScriptKeys(in.WitnessScript)
+			witness := wire.TxWitness{[]byte{}}
+			for _, key := range pubKeys {
+				if sig := partialSig(*in, key); sig != nil && len(witness) <= threshold {
+					witness = append(witness, sig)
+				}
+			}
+			if threshold == 0 || len(witness) <= threshold {
+				return tx, false, nil
+			}
+			if in.WitnessScript[0] == txscript.OP_IF {
+				witness = append(witness, []byte{0x01})
+			}
+			witness = append(witness, in.WitnessScript)
+			tx.TxIn[i].Witness = witness
+			in.FinalScriptWitness = witness
+			continue
+		}
+		builder := txscript.NewScriptBuilder()
+		if in.RedeemScript == nil {
+			if len(in.Bip32Derivation) == 0 {
+				return nil, false, ErrInvalidSigningRequest
+			}
+			pubKey := in.Bip32Derivation[0].PubKey
+			sig := partialSig(*in, pubKey)
+			if sig == nil {
+				return tx, false, nil
+			}
+			builder.AddData(sig).AddData(pubKey)
+		} else {
+			pubKeys, threshold := escrowScriptKeys(in.RedeemScript)
+			builder.AddOp(txscript.OP_0)
+			found := 0
+			for _, key := range pubKeys {
+				if sig := partialSig(*in, key); sig != nil && found < threshold {
+					builder.AddData(sig)
+					found++
+				}
+			}
+			if threshold == 0 || found < threshold {
+				return tx, false, nil
+			}
+			if in.RedeemScript[0] == txscript.OP_IF {
+				builder.AddOp(txscript.OP_1)
+			}
+			builder.AddData(in.RedeemScript)
+		}
+		script, err := builder.Script()
+		if err != nil {
+			return nil, false, err
+		}
+		tx.TxIn[i].SignatureScript = script
+		in.FinalScriptSig = script
+	}
+	return tx, true, nil
+}
+
+// spendsTimeoutBranch returns whether an input of an escrow release has the
+// relative lock time of a spend of the escrow's timeout branch
+func spendsTimeoutBranch(tx *wire.MsgTx, i int) bool {
+	return tx.Version >= 2 && tx.TxIn[i].Sequence&wire.SequenceLockTimeDisabled == 0
+}
+
+// escrowScriptKeys returns the public keys of the multisig branch of an
+// escrow redeem script, in order, and the number of signatures it needs. A
+// panel escrow's keys are the buyer's, the vendor's and the moderators'.
+func escrowScriptKeys(redeemScript []byte) ([][]byte, int) {
+	if keys, ok := panelEscrowKeys(redeemScript); ok {
+		return keys, 2
+	}
+	script := redeemScript
+	if len(script) > 0 && script[0] == txscript.OP_IF {
+		script = script[1:]
+	}
+	if len(script) == 0 || script[0] < txscript.OP_1 || script[0] > txscript.OP_16 {
+		return nil, 0
+	}
+	threshold := int(script[0]-txscript.OP_1) + 1
+	var pubKeys [][]byte
+	for i := 1; i+34 <= len(script) && script[i] == txscript.OP_DATA_33; i += 34 {
+		pubKeys = append(pubKeys, script[i+1:i+34])
+	}
+	return pubKeys, threshold
+}
+
+// escrowScript returns the multisig script of an escrow input, or nil for
+// an input of a spend
+func escrowScript(in psbt.Input) []byte {
+	if in.WitnessScript != nil {
+		return in.WitnessScript
+	}
+	return in.RedeemScript
+}
+
+// signingKeys returns the keys which may sign an input
+func signingKeys(in psbt.Input) [][]byte {
+	if script := escrowScript(in); script != nil {
+		pubKeys, _ := escrowScriptKeys(script)
+		return pubKeys
+	}
+	var pubKeys [][]byte
+	for _, d := range in.Bip32Derivation {
+		pubKeys = append(pubKeys, d.PubKey)
+	}
+	return pubKeys
+}
+
+// hasOurSignatures returns whether every input is signed by the key we
+// exported it for
+func hasOurSignatures(packet *psbt.Packet) bool {
+	for _, in := range packet.Inputs {
+		var ours [][]byte
+		for _, d := range in.Bip32Derivation {
+			ours = append(ours, d.PubKey)
+		}
+		if key := ourEscrowKey(in); key != nil {
+			ours = append(ours, key)
+		}
+		signed := false
+		for _, key := range ours {
+			if partialSig(in, key) != nil {
+				signed = true
+			}
+		}
+		if !signed {
+			return false
+		}
+	}
+	return true
+}
+
+// ourEscrowKey returns the escrow key we exported an input for, or nil for
+// an input of a spend
+func ourEscrowKey(in psbt.Input) []byte {
+	for _, prop := range in.Proprietary {
+		if bytes.Equal(prop.Identifier, psbtIdentifier) && prop.Subtype == escrowChaincodeSubtype {
+			return prop.KeyData
+		}
+	}
+	return nil
+}
+
+func partialSig(in psbt.Input, pubKey []byte) []byte {
+	for _, sig := range in.PartialSigs {
+		if bytes.Equal(sig.PubKey, pubKey) {
+			return sig.Signature
+		}
+	}
+	return nil
+}
+
+func containsKey(pubKeys [][]byte, key []byte) bool {
+	for _, k := range pubKeys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// previousTransaction returns the wallet's copy of the transaction an input
+// spends, if it has one
+func previousTransaction(wal wallet.Wallet, op wire.OutPoint) *wire.MsgTx {
+	txn, err := wal.GetTransaction(op.Hash)
+	if err != nil || len(txn.Bytes) == 0 {
+		return nil
+	}
+	tx := wire.NewMsgTx(1)
+	if err := tx.Deserialize(bytes.NewReader(txn.Bytes)); err != nil || tx.TxHash() != op.Hash {
+		return nil
+	}
+	return tx
+}
+
+// escrowPublicKey returns the serialized public key we hold in the escrow of
+// an order with the chaincode, derived without our private key
+func (n *OpenBazaarNode) escrowPublicKey(wal wallet.Wallet, chaincode []byte) ([]byte, error) {
+	mECKey, err := n.MasterPublicKey.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	key, err := wal.ChildKey(mECKey.SerializeCompressed(), chaincode, false)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := key.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.SerializeCompressed(), nil
+}
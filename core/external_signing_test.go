@@ -0,0 +1,511 @@
+package core_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/signer/remote"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/wallet/psbt"
+)
+
+// broadcastRecorder is a wallet which keeps the transactions it is asked
+// to broadcast
+type broadcastRecorder struct {
+	wallet.Wallet
+	sent []*wire.MsgTx
+}
+
+func (w *broadcastRecorder) CurrencyCode() string           { return "REC" }
+func (w *broadcastRecorder) Params() *chaincfg.Params       { return &chaincfg.RegressionNetParams }
+func (w *broadcastRecorder) Broadcast(tx *wire.MsgTx) error { w.sent = append(w.sent, tx); return nil }
+
+type testEscrow struct {
+	ourKey       *hd.ExtendedKey
+	vendorKey    *hd.ExtendedKey
+	chaincode    []byte
+	redeemScript []byte
+	pkScript     []byte
+}
+
+func newTestEscrow(t *testing.T, node *core.OpenBazaarNode, wal wallet.Wallet) *testEscrow {
+	e := &testEscrow{chaincode: make([]byte, 32)}
+	if _, err := rand.Read(e.chaincode); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	if e.ourKey, err = node.Signer.(signer.EscrowKeyHolder).EscrowKey(e.chaincode); err != nil {
+		t.Fatal(err)
+	}
+	var keys []hd.ExtendedKey
+	for _, seed := range [][]byte{bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)} {
+		master, err := hd.NewMaster(seed, &chaincfg.RegressionNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, err := master.ECPrivKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		key, err := wal.ChildKey(priv.Serialize(), e.chaincode, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, *key)
+	}
+	e.vendorKey = &keys[0]
+
+	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*e.ourKey, keys[0], keys[1]}, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.redeemScript = redeemScript
+	if e.pkScript, err = txscript.PayToAddrScript(addr); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func (e *testEscrow) release(t *testing.T, action string, value int64) core.EscrowRelease {
+	hash := chainhash.DoubleHashH(append([]byte(action), e.chaincode...))
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return core.EscrowRelease{
+		OrderID:      "order",
+		Action:       action,
+		Inputs:       []wallet.TransactionInput{{OutpointHash: hash.CloneBytes(), OutpointIndex: 1, Value: value}},
+		Outputs:      []wallet.TransactionOutput{{Address: addr, Value: value}},
+		Chaincode:    e.chaincode,
+		RedeemScript: e.redeemScript,
+		FeePerByte:   2,
+	}
+}
+
+// signExternally signs every input of an exported request with our escrow
+// key and returns the encoded packet
+func (e *testEscrow) signExternally(t *testing.T, node *core.OpenBazaarNode, requestID string) string {
+	request, err := node.Datastore.SigningRequests().Get(requestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packet, err := psbt.Parse(bytes.NewReader(request.PSBT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := e.ourKey.ECPrivKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, in := range packet.Inputs {
+		chaincode, ok := in.ProprietaryValue([]byte("openbazaar"), 1, key.PubKey().SerializeCompressed())
+		if !ok || !bytes.Equal(chaincode, e.chaincode) {
+			t.Fatal("expected the packet to say how to derive our escrow key")
+		}
+		if err := packet.Sign(i, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+// deleteSigningRequestNotifications removes the notifications the signing
+// requests created, as the test repository is shared with other packages
+func deleteSigningRequestNotifications(t *testing.T, node *core.OpenBazaarNode) {
+	notifications, _, err := node.Datastore.Notifications().GetAll("", -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range notifications {
+		if n.NotifierType != repo.NotifierTypeSigningRequest {
+			continue
+		}
+		if err := node.Datastore.Notifications().Delete(n.GetID()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSignEscrowReleaseWaitsForExternalSignature(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteSigningRequestNotifications(t, node)
+	node.Broadcast = make(chan repo.Notifier, 10)
+	wal, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrow := newTestEscrow(t, node, wal)
+	release := escrow.release(t, core.SigningActionRefund, 100000)
+
+	hotSigs, err := node.SignEscrowRelease(wal, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node.ExternalSigning = true
+	if _, err := node.SignEscrowRelease(wal, release); err != core.ErrAwaitingExternalSignature {
+		t.Fatalf("expected to wait for the external signature, got %v", err)
+	}
+	if _, err := node.SignEscrowRelease(wal, release); err != core.ErrAwaitingExternalSignature {
+		t.Fatalf("expected to keep waiting for the external signature, got %v", err)
+	}
+	if len(node.Broadcast) != 1 {
+		t.Fatalf("expected one notification for the new request, got %d", len(node.Broadcast))
+	}
+	notif := (<-node.Broadcast).(repo.SigningRequestNotification)
+	if notif.OrderID != "order" || notif.State != repo.SigningRequestPending {
+		t.Errorf("unexpected notification: %+v", notif)
+	}
+
+	pending, err := node.Datastore.SigningRequests().GetAll(repo.SigningRequestPending, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Action != core.SigningActionRefund {
+		t.Fatalf("expected one pending refund request, got %+v", pending)
+	}
+
+	if _, err := node.ImportSigningRequest("not a packet"); err != core.ErrInvalidSigningRequest {
+		t.Errorf("expected invalid packet to be rejected, got %v", err)
+	}
+	request, err := node.ImportSigningRequest(escrow.signExternally(t, node, pending[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.State != repo.SigningRequestSigned {
+		t.Errorf("expected request to be signed, got %s", request.State)
+	}
+
+	// The imported signatures are the ones the node would have made itself
+	sigs, err := node.SignEscrowRelease(wal, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != len(hotSigs) || !bytes.Equal(sigs[0].Signature, hotSigs[0].Signature) {
+		t.Error("expected the exported transaction to match the one signed in the node")
+	}
+}
+
+func TestReleaseEscrowBroadcastsOnImport(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteSigningRequestNotifications(t, node)
+	node.Broadcast = make(chan repo.Notifier, 10)
+	node.ExternalSigning = true
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal := &broadcastRecorder{Wallet: btc}
+	coinType := util.ExtCoinType(999999)
+	node.Multiwallet[coinType] = wal
+	defer delete(node.Multiwallet, coinType)
+
+	escrow := newTestEscrow(t, node, wal)
+	release := escrow.release(t, core.SigningActionDecline, 50000)
+	theirSigs, err := wal.CreateMultisigSignature(release.Inputs, release.Outputs, escrow.vendorKey, release.RedeemScript, release.FeePerByte)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.ReleaseEscrow(wal, release, theirSigs); err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.sent) != 0 {
+		t.Fatal("expected nothing to be broadcast before our signature is imported")
+	}
+	pending, err := node.Datastore.SigningRequests().GetAll(repo.SigningRequestPending, "order")
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected one pending request, got %+v (%v)", pending, err)
+	}
+
+	request, err := node.ImportSigningRequest(escrow.signExternally(t, node, pending[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.State != repo.SigningRequestBroadcast || len(wal.sent) != 1 {
+		t.Fatalf("expected the release to be broadcast, got state %s", request.State)
+	}
+
+	tx := wal.sent[0]
+	vm, err := txscript.NewEngine(escrow.pkScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, release.Inputs[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected the broadcast release to spend the escrow: %s", err)
+	}
+}
+
+// startSigningDaemon serves a signer over a Unix socket, as a signing
+// daemon would, and returns a node signer talking to it
+func startSigningDaemon(t *testing.T, s signer.Signer) (*remote.Signer, func()) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := path.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go remote.Serve(l, s)
+	client := remote.NewSigner(socket)
+	return client, func() {
+		client.Close()
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSignEscrowReleaseWithSigningDaemon(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteSigningRequestNotifications(t, node)
+	wal, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrow := newTestEscrow(t, node, wal)
+	release := escrow.release(t, core.SigningActionCompletion, 80000)
+	hotSigs, err := node.SignEscrowRelease(wal, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemonSigner, stop := startSigningDaemon(t, node.Signer)
+	defer stop()
+	node.Signer = daemonSigner
+	sigs, err := node.SignEscrowRelease(wal, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sigs) != len(hotSigs) || !bytes.Equal(sigs[0].Signature, hotSigs[0].Signature) {
+		t.Error("expected the daemon to make the signatures the node would have made")
+	}
+
+	contract := &pb.RicardianContract{BuyerOrder: &pb.Order{RefundAddress: "address"}}
+	if _, err := node.SignOrder(contract); err != nil {
+		t.Fatal(err)
+	}
+	ser, err := proto.Marshal(contract.BuyerOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := node.IpfsNode.PrivateKey.GetPublic().Verify(ser, contract.Signatures[0].SignatureBytes); err != nil || !ok {
+		t.Error("expected the daemon to sign the order with the identity key")
+	}
+
+	// A daemon holding other keys is caught before its signatures are used
+	other, err := hd.NewMaster(bytes.Repeat([]byte{3}, 32), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongSigner, stopWrong := startSigningDaemon(t, signer.NewLocalSigner(node.IpfsNode.PrivateKey, other))
+	defer stopWrong()
+	node.Signer = wrongSigner
+	if _, err := node.SignEscrowRelease(wal, release); err == nil {
+		t.Error("expected signatures for another key to be rejected")
+	}
+}
+
+func TestSweepEscrowWithSigningDaemon(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal := &broadcastRecorder{Wallet: btc}
+	coinType := util.ExtCoinType(999999)
+	node.Multiwallet[coinType] = wal
+	defer delete(node.Multiwallet, coinType)
+
+	escrow := newTestEscrow(t, node, wal)
+	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*escrow.ourKey, *escrow.vendorKey}, 1, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := &pb.RicardianContract{BuyerOrder: &pb.Order{
+		RefundAddress: "address",
+		Payment: &pb.Order_Payment{
+			Method:       pb.Order_Payment_DIRECT,
+			Chaincode:    hex.EncodeToString(escrow.chaincode),
+			RedeemScript: hex.EncodeToString(redeemScript),
+		},
+	}}
+	ins := []wallet.TransactionInput{{OutpointHash: bytes.Repeat([]byte{7}, 32), Value: 100000}}
+	to, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	daemonSigner, stop := startSigningDaemon(t, node.Signer)
+	defer stop()
+	node.Signer = daemonSigner
+	if err := node.SweepEscrow(wal, ins, to, contract); err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.sent) != 1 {
+		t.Fatal("expected the sweep to be broadcast")
+	}
+	tx := wal.sent[0]
+	if len(tx.TxOut) != 1 || tx.TxOut[0].Value >= ins[0].Value {
+		t.Error("expected the sweep to pay everything but the fee to the address")
+	}
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, ins[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected the daemon signed sweep to spend the escrow: %s", err)
+	}
+}
+
+func TestSweepEscrowTimeoutFromWatchOnlyNode(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deleteSigningRequestNotifications(t, node)
+	node.Broadcast = make(chan repo.Notifier, 10)
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal := &broadcastRecorder{Wallet: btc}
+	coinType := util.ExtCoinType(999998)
+	node.Multiwallet[coinType] = wal
+	defer delete(node.Multiwallet, coinType)
+
+	escrow := newTestEscrow(t, node, wal)
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_IF).AddOp(txscript.OP_2)
+	for _, key := range []*hd.ExtendedKey{escrow.vendorKey, escrow.ourKey, escrow.vendorKey} {
+		pub, err := key.ECPubKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		builder.AddData(pub.SerializeCompressed())
+	}
+	ourPub, err := escrow.ourKey.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	redeemScript, err := builder.AddOp(txscript.OP_3).AddOp(txscript.OP_CHECKMULTISIG).
+		AddOp(txscript.OP_ELSE).AddInt64(144).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).AddOp(txscript.OP_DROP).
+		AddData(ourPub.SerializeCompressed()).AddOp(txscript.OP_CHECKSIG).
+		AddOp(txscript.OP_ENDIF).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptHash := sha256.Sum256(redeemScript)
+	pkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+	contract := &pb.RicardianContract{BuyerOrder: &pb.Order{
+		RefundAddress: "address",
+		Payment: &pb.Order_Payment{
+			Method:       pb.Order_Payment_MODERATED,
+			Chaincode:    hex.EncodeToString(escrow.chaincode),
+			RedeemScript: hex.EncodeToString(redeemScript),
+		},
+	}}
+	orderID, err := node.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins := []wallet.TransactionInput{{OutpointHash: bytes.Repeat([]byte{8}, 32), Value: 100000}}
+	to, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The node keeps only the public keys of its seed
+	peerID := node.IpfsNode.Identity.Pretty()
+	pubKeys, err := node.Signer.PublicKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitcoinSig, err := node.Signer.SignBitcoin([]byte(peerID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.NewWatchOnlySigner(node.IpfsNode.PrivateKey, pubKeys, "another peer", bitcoinSig); err != signer.ErrInvalidBitcoinSig {
+		t.Errorf("expected a signature of another peer ID to be refused, got %v", err)
+	}
+	watchOnly, err := signer.NewWatchOnlySigner(node.IpfsNode.PrivateKey, pubKeys, peerID, bitcoinSig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Signer = watchOnly
+	node.ExternalSigning = true
+	if _, err := node.Signer.SignEscrow(escrow.chaincode, make([]byte, 32)); err != signer.ErrWatchOnly {
+		t.Errorf("expected a watch-only node not to sign, got %v", err)
+	}
+
+	if err := node.SweepEscrow(wal, ins, to, contract); err != nil {
+		t.Fatal(err)
+	}
+	if len(wal.sent) != 0 {
+		t.Fatal("expected nothing to be broadcast before the sweep is signed")
+	}
+	pending, err := node.Datastore.SigningRequests().GetAll(repo.SigningRequestPending, orderID)
+	if err != nil || len(pending) != 1 || pending[0].Action != core.SigningActionSweep {
+		t.Fatalf("expected one pending sweep request, got %+v (%v)", pending, err)
+	}
+
+	request, err := node.ImportSigningRequest(escrow.signExternally(t, node, pending[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.State != repo.SigningRequestBroadcast || len(wal.sent) != 1 {
+		t.Fatalf("expected the sweep to be broadcast, got state %s", request.State)
+	}
+	tx := wal.sent[0]
+	if tx.Version != 2 || tx.TxIn[0].Sequence != 144 {
+		t.Error("expected the sweep to spend the timeout branch")
+	}
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, ins[0].Value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected the imported sweep to spend the escrow: %s", err)
+	}
+}
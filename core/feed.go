@@ -0,0 +1,198 @@
+package core
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	ipnspath "gx/ipfs/QmQAgv6Gaoe2tQpcabqwKXKChp2MZ7i3UXv9DqTTaxCaTR/go-path"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/op/go-logging"
+	"github.com/phoreproject/openbazaar-go/ipfs"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	feedTestingInterval = time.Duration(5) * time.Minute
+	feedRegularInterval = time.Duration(30) * time.Minute
+)
+
+// feedIndexEntry holds the fields of a posts.json or listings.json entry the
+// feed needs. The rest of the entry is kept as it was published.
+type feedIndexEntry struct {
+	Hash      string `json:"hash"`
+	Slug      string `json:"slug"`
+	Timestamp string `json:"timestamp"`
+}
+
+// FeedEntryID returns the ID of the feed entry for a peer's post or listing
+func FeedEntryID(peerID, entryType, slug string) string {
+	return path.Join(peerID, entryType, slug)
+}
+
+// RefreshFeed collects the posts and listings published by every peer we
+// follow
+func (n *OpenBazaarNode) RefreshFeed() error {
+	following, err := n.Datastore.Following().Get("", -1)
+	if err != nil {
+		return err
+	}
+	for _, peerID := range following {
+		if err := n.RefreshPeerFeed(peerID); err != nil {
+			log.Warningf("refreshing feed from %s: %s", peerID, err)
+		}
+	}
+	return nil
+}
+
+// RefreshPeerFeed fetches a followed peer's posts.json and listings.json and
+// updates their feed entries. New entries are pushed to the websocket.
+func (n *OpenBazaarNode) RefreshPeerFeed(peerID string) error {
+	pid, err := peer.IDB58Decode(peerID)
+	if err != nil {
+		return err
+	}
+	if n.BanManager != nil && n.BanManager.IsBanned(pid) {
+		return n.Datastore.Feed().DeleteByPeer(peerID)
+	}
+
+	published := make(map[string]map[string]json.RawMessage)
+	for entryType, file := range map[string]string{
+		repo.FeedEntryTypePost:    "posts.json",
+		repo.FeedEntryTypeListing: "listings.json",
+	} {
+		b, err := ipfs.ResolveThenCat(n.IpfsNode, ipnspath.FromString(path.Join(peerID, file)), time.Minute, n.IPNSQuorumSize, false)
+		if err != nil {
+			// The peer may not have published any posts or listings
+			continue
+		}
+		var index []json.RawMessage
+		if err := json.Unmarshal(b, &index); err != nil {
+			return err
+		}
+		published[entryType] = make(map[string]json.RawMessage)
+		for _, data := range index {
+			var e feedIndexEntry
+			if err := json.Unmarshal(data, &e); err != nil || e.Slug == "" {
+				continue
+			}
+			published[entryType][e.Slug] = data
+		}
+	}
+	return n.UpdatePeerFeed(peerID, published, time.Now())
+}
+
+// UpdatePeerFeed replaces a peer's feed entries with the published index
+// entries, keyed by entry type then slug. Entry types missing from published
+// are left as they are.
+func (n *OpenBazaarNode) UpdatePeerFeed(peerID string, published map[string]map[string]json.RawMessage, now time.Time) error {
+	existing, err := n.Datastore.Feed().GetByPeer(peerID)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]repo.FeedEntry)
+	for _, entry := range existing {
+		entries, ok := published[entry.Type]
+		if _, stillPublished := entries[entry.Slug]; ok && !stillPublished {
+			if err := n.Datastore.Feed().Delete(entry.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		known[entry.ID] = entry
+	}
+
+	for entryType, entries := range published {
+		for slug, data := range entries {
+			var e feedIndexEntry
+			if err := json.Unmarshal(data, &e); err != nil {
+				return err
+			}
+			id := FeedEntryID(peerID, entryType, slug)
+			old, seen := known[id]
+			if seen && old.Hash == e.Hash {
+				continue
+			}
+			entry := repo.FeedEntry{
+				ID:        id,
+				PeerID:    peerID,
+				Type:      entryType,
+				Slug:      slug,
+				Hash:      e.Hash,
+				Data:      data,
+				Timestamp: now,
+			}
+			// Listings don't carry a timestamp so they are dated when
+			// first seen
+			if seen {
+				entry.Timestamp = old.Timestamp
+			}
+			if ts, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+				entry.Timestamp = ts
+			}
+			if err := n.Datastore.Feed().Put(entry); err != nil {
+				return err
+			}
+			if !seen {
+				n.Broadcast <- repo.FeedEntryNotification{FeedEntry: entry}
+			}
+		}
+	}
+	return nil
+}
+
+// GetFeed returns a page of the feed, newest first, leaving out peers we
+// have blocked since the entries were collected
+func (n *OpenBazaarNode) GetFeed(entryType, offsetID string, limit int) ([]repo.FeedEntry, error) {
+	// Banned peers are filtered by the query so they don't use up the limit
+	var banned []string
+	if n.BanManager != nil {
+		for _, pid := range n.BanManager.GetBlockedIds() {
+			banned = append(banned, pid.Pretty())
+		}
+	}
+	entries, err := n.Datastore.Feed().GetAll(entryType, offsetID, limit, banned)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []repo.FeedEntry{}
+	}
+	return entries, nil
+}
+
+type feedAggregator struct {
+	node          *OpenBazaarNode
+	intervalDelay time.Duration
+	logger        *logging.Logger
+}
+
+// StartFeedAggregator starts a worker which periodically collects the posts
+// and listings published by the peers we follow
+func (n *OpenBazaarNode) StartFeedAggregator() {
+	interval := feedRegularInterval
+	if n.TestnetEnable {
+		interval = feedTestingInterval
+	}
+	aggregator := &feedAggregator{
+		node:          n,
+		intervalDelay: interval,
+		logger:        logging.MustGetLogger("feedAggregator"),
+	}
+	go aggregator.Run()
+}
+
+func (f *feedAggregator) Run() {
+	f.refresh()
+	ticker := time.NewTicker(f.intervalDelay)
+	for range ticker.C {
+		f.refresh()
+	}
+}
+
+func (f *feedAggregator) refresh() {
+	if err := f.node.RefreshFeed(); err != nil {
+		f.logger.Errorf("refreshing feed: %s", err)
+	}
+}
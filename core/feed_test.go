@@ -0,0 +1,89 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func TestUpdatePeerFeed(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.Broadcast = make(chan repo.Notifier, 10)
+	_, pid := newTestPeer(t)
+	peerID := pid.Pretty()
+	defer node.Datastore.Feed().DeleteByPeer(peerID)
+
+	var (
+		first   = time.Now().Add(-time.Hour).Truncate(time.Second)
+		posted  = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		post    = json.RawMessage(`{"hash":"QmPost","slug":"hello","timestamp":"` + posted.Format(time.RFC3339) + `"}`)
+		listing = json.RawMessage(`{"hash":"QmListing","slug":"shirt","title":"Shirt"}`)
+	)
+	err = node.UpdatePeerFeed(peerID, map[string]map[string]json.RawMessage{
+		repo.FeedEntryTypePost:    {"hello": post},
+		repo.FeedEntryTypeListing: {"shirt": listing},
+	}, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.Broadcast) != 2 {
+		t.Fatalf("expected 2 new entries pushed, got %d", len(node.Broadcast))
+	}
+	<-node.Broadcast
+	<-node.Broadcast
+
+	entry, err := node.Datastore.Feed().Get(core.FeedEntryID(peerID, repo.FeedEntryTypePost, "hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Timestamp.Equal(posted) || entry.Hash != "QmPost" {
+		t.Errorf("unexpected post entry: %+v", entry)
+	}
+
+	// An edited listing keeps the date it was first seen and isn't pushed
+	// again, and the deleted post is dropped
+	edited := json.RawMessage(`{"hash":"QmEdited","slug":"shirt","title":"Blue shirt"}`)
+	err = node.UpdatePeerFeed(peerID, map[string]map[string]json.RawMessage{
+		repo.FeedEntryTypePost:    {},
+		repo.FeedEntryTypeListing: {"shirt": edited},
+	}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.Broadcast) != 0 {
+		t.Errorf("expected no entries pushed, got %d", len(node.Broadcast))
+	}
+	feed, err := node.GetFeed("", "", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromPeer []repo.FeedEntry
+	for _, e := range feed {
+		if e.PeerID == peerID {
+			fromPeer = append(fromPeer, e)
+		}
+	}
+	if len(fromPeer) != 1 || fromPeer[0].Hash != "QmEdited" || !fromPeer[0].Timestamp.Equal(first) {
+		t.Fatalf("unexpected feed: %+v", fromPeer)
+	}
+
+	// Entries from blocked peers are left out of the feed
+	node.BanManager.AddBlockedId(pid)
+	defer node.BanManager.RemoveBlockedId(pid)
+	feed, err = node.GetFeed(repo.FeedEntryTypeListing, "", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range feed {
+		if e.PeerID == peerID {
+			t.Error("expected blocked peer's entries to be left out")
+		}
+	}
+}
@@ -55,25 +55,11 @@ func (n *OpenBazaarNode) FulfillOrder(fulfillment *pb.OrderFulfillment, contract
 			Address: currentAddress,
 			Value:   outValue,
 		}
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+		release, err := n.NewEscrowRelease(contract, SigningActionFulfillment, ins, []wallet.TransactionOutput{output}, payout.PayoutFeePerByte)
 		if err != nil {
 			return err
 		}
-		mPrivKey := n.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return err
-		}
-		vendorKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return err
-		}
-
-		signatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, vendorKey, redeemScript, payout.PayoutFeePerByte)
+		signatures, err := n.SignEscrowRelease(wal, release)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/phoreproject/openbazaar-go/ipfs"
+)
+
+var (
+	ipnsAnnouncementsLock sync.Mutex
+	// ipnsAnnouncementsStop holds a channel per followed peer which is
+	// closed to stop reading their announcements
+	ipnsAnnouncementsStop = make(map[string]chan struct{})
+)
+
+// announceRootHash signs an IPNS record for the new root hash and announces
+// it on our pubsub topic, so followers see the change before the DHT
+// publish completes
+func (n *OpenBazaarNode) announceRootHash(hash string) error {
+	record, err := ipfs.CreateIPNSRecord(n.IpfsNode, hash)
+	if err != nil {
+		return err
+	}
+	if n.Pubsub.Publisher == nil {
+		return nil
+	}
+	return n.Pubsub.Publisher.Publish(context.Background(), ipfs.IPNSAnnounceTopic(n.IpfsNode.Identity), record)
+}
+
+// StartIPNSAnnouncementListener subscribes to the IPNS announcements of
+// every peer we follow
+func (n *OpenBazaarNode) StartIPNSAnnouncementListener() {
+	following, err := n.Datastore.Following().Get("", -1)
+	if err != nil {
+		log.Errorf("loading followed peers: %s", err)
+		return
+	}
+	for _, peerID := range following {
+		if err := n.SubscribeIPNSAnnouncements(peerID); err != nil {
+			log.Warningf("subscribing to IPNS announcements from %s: %s", peerID, err)
+		}
+	}
+}
+
+// SubscribeIPNSAnnouncements listens for a peer's IPNS announcements and
+// updates our cache of their root hash as they arrive
+func (n *OpenBazaarNode) SubscribeIPNSAnnouncements(peerID string) error {
+	if n.Pubsub.Subscriber == nil {
+		return nil
+	}
+	pid, err := peer.IDB58Decode(peerID)
+	if err != nil {
+		return err
+	}
+	topic := ipfs.IPNSAnnounceTopic(pid)
+	for _, sub := range n.Pubsub.Subscriber.GetSubscriptions() {
+		if sub == topic {
+			return nil
+		}
+	}
+	announcements, err := n.Pubsub.Subscriber.Subscribe(context.Background(), topic)
+	if err != nil {
+		return err
+	}
+	stop := make(chan struct{})
+	ipnsAnnouncementsLock.Lock()
+	ipnsAnnouncementsStop[peerID] = stop
+	ipnsAnnouncementsLock.Unlock()
+	go func() {
+		for {
+			select {
+			case record := <-announcements:
+				if err := n.HandleIPNSAnnouncement(pid, record); err != nil {
+					log.Debugf("ignoring IPNS announcement from %s: %s", peerID, err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// UnsubscribeIPNSAnnouncements stops listening for a peer's IPNS announcements
+func (n *OpenBazaarNode) UnsubscribeIPNSAnnouncements(peerID string) {
+	if n.Pubsub.Subscriber == nil {
+		return
+	}
+	pid, err := peer.IDB58Decode(peerID)
+	if err != nil {
+		return
+	}
+	n.Pubsub.Subscriber.Cancel(ipfs.IPNSAnnounceTopic(pid))
+	ipnsAnnouncementsLock.Lock()
+	if stop, ok := ipnsAnnouncementsStop[peerID]; ok {
+		close(stop)
+		delete(ipnsAnnouncementsStop, peerID)
+	}
+	ipnsAnnouncementsLock.Unlock()
+}
+
+// HandleIPNSAnnouncement verifies an IPNS record announced by a peer and
+// caches their new root hash, then refreshes their entries in our feed
+func (n *OpenBazaarNode) HandleIPNSAnnouncement(pid peer.ID, record []byte) error {
+	if n.BanManager != nil && n.BanManager.IsBanned(pid) {
+		return nil
+	}
+	pth, err := ipfs.ProcessIPNSAnnouncement(n.IpfsNode, pid, record)
+	if err != nil {
+		return err
+	}
+	log.Debugf("received IPNS announcement from %s for %s", pid.Pretty(), pth)
+	return n.RefreshPeerFeed(pid.Pretty())
+}
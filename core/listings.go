@@ -80,27 +80,29 @@ type thumbnail struct {
 
 // ListingData - represent a listing
 type ListingData struct {
-	Hash               string    `json:"hash"`
-	Slug               string    `json:"slug"`
-	Title              string    `json:"title"`
-	Tags               []string  `json:"tags"`
-	Categories         []string  `json:"categories"`
-	NSFW               bool      `json:"nsfw"`
-	ContractType       string    `json:"contractType"`
-	Format             string    `json:"format"`
-	Description        string    `json:"description"`
-	Thumbnail          thumbnail `json:"thumbnail"`
-	Price              price     `json:"price"`
-	ShipsTo            []string  `json:"shipsTo"`
-	FreeShipping       []string  `json:"freeShipping"`
-	Language           string    `json:"language"`
-	AverageRating      float32   `json:"averageRating"`
-	RatingCount        uint32    `json:"ratingCount"`
-	ModeratorIDs       []string  `json:"moderators"`
-	AcceptedCurrencies []string  `json:"acceptedCurrencies"`
-	CoinType           string    `json:"coinType"`
-	CoinDivisibility   uint32    `json:"coinDivisibility"`
-	Testnet            bool      `json:"testnet"`
+	Hash               string     `json:"hash"`
+	Slug               string     `json:"slug"`
+	Title              string     `json:"title"`
+	Tags               []string   `json:"tags"`
+	Categories         []string   `json:"categories"`
+	NSFW               bool       `json:"nsfw"`
+	ContractType       string     `json:"contractType"`
+	Format             string     `json:"format"`
+	Description        string     `json:"description"`
+	Thumbnail          thumbnail  `json:"thumbnail"`
+	Price              price      `json:"price"`
+	ShipsTo            []string   `json:"shipsTo"`
+	FreeShipping       []string   `json:"freeShipping"`
+	Language           string     `json:"language"`
+	AverageRating      float32    `json:"averageRating"`
+	RatingCount        uint32     `json:"ratingCount"`
+	ModeratorIDs       []string   `json:"moderators"`
+	AcceptedCurrencies []string   `json:"acceptedCurrencies"`
+	CoinType           string     `json:"coinType"`
+	CoinDivisibility   uint32     `json:"coinDivisibility"`
+	Testnet            bool       `json:"testnet"`
+	Unavailable        bool       `json:"unavailable"`
+	AvailableFrom      *time.Time `json:"availableFrom,omitempty"`
 }
 
 var (
@@ -204,7 +206,7 @@ func (n *OpenBazaarNode) SignListing(listing *pb.Listing) (*pb.SignedListing, er
 	}
 	p := new(pb.ID_Pubkeys)
 	p.Identity = pubkey
-	ecPubKey, err := n.MasterPrivateKey.ECPubKey()
+	ecPubKey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return sl, err
 	}
@@ -213,15 +215,10 @@ func (n *OpenBazaarNode) SignListing(listing *pb.Listing) (*pb.SignedListing, er
 	listing.VendorID = id
 
 	// Sign the GUID with the Bitcoin key
-	ecPrivKey, err := n.MasterPrivateKey.ECPrivKey()
+	id.BitcoinSig, err = n.Signer.SignBitcoin([]byte(id.PeerID))
 	if err != nil {
 		return sl, err
 	}
-	sig, err := ecPrivKey.Sign([]byte(id.PeerID))
-	if err != nil {
-		return sl, err
-	}
-	id.BitcoinSig = sig.Serialize()
 
 	// Update coupon db
 	n.Datastore.Coupons().Delete(listing.Slug)
@@ -252,7 +249,7 @@ func (n *OpenBazaarNode) SignListing(listing *pb.Listing) (*pb.SignedListing, er
 	if err != nil {
 		return sl, err
 	}
-	idSig, err := n.IpfsNode.PrivateKey.Sign(serializedListing)
+	idSig, err := n.Signer.SignIdentity(serializedListing)
 	if err != nil {
 		return sl, err
 	}
@@ -530,6 +527,9 @@ func (n *OpenBazaarNode) extractListingData(listing *pb.SignedListing) (ListingD
 		AcceptedCurrencies: listing.Listing.Metadata.AcceptedCurrencies,
 		Testnet:            listing.Listing.Testnet,
 	}
+	if settings, err := n.Datastore.Settings().Get(); err == nil {
+		setListingVacationStatus(&ld, settings.VacationMode, time.Now())
+	}
 	return ld, nil
 }
 
@@ -814,8 +814,8 @@ func (n *OpenBazaarNode) GetListings() ([]byte, error) {
 		return nil, err
 	}
 
-	// Return bytes read from file
-	return file, nil
+	// Return bytes read from file with any vacation which has ended cleared
+	return RefreshListingAvailability(file, time.Now())
 }
 
 // GetListingFromHash - fetch listing for the specified hash
@@ -948,6 +948,9 @@ func (n *OpenBazaarNode) validateListing(listing *pb.Listing, testnet bool) (err
 			return fmt.Errorf("accepted currency is longer than the max of %d characters", WordMaxCharacters)
 		}
 	}
+	if err := validateDisputeFallback(listing.Metadata.DisputeFallback); err != nil {
+		return err
+	}
 
 	// Item
 	if listing.Item.Title == "" {
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/OpenBazaar/jsonpb"
 	"github.com/phoreproject/openbazaar-go/ipfs"
@@ -67,8 +68,8 @@ func (n *OpenBazaarNode) SetSelfAsModerator(moderator *pb.Moderator) error {
 		if settingsData.PreferredCurrencies != nil {
 			currencies = append(currencies, *settingsData.PreferredCurrencies...)
 		} else {
-			for ct := range n.Multiwallet {
-				currencies = append(currencies, ct.CurrencyCode())
+			for _, wal := range n.Multiwallet {
+				currencies = append(currencies, strings.ToUpper(wal.CurrencyCode()))
 			}
 		}
 		for _, cc := range currencies {
@@ -139,7 +140,16 @@ func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64, paymentCoin, c
 	if err != nil {
 		return 0, err
 	}
+	return n.moderatorFee(profile, transactionTotal, paymentCoin, currencyCode)
+}
 
+// moderatorFee returns the fee a moderator's profile charges for a
+// transaction
+func (n *OpenBazaarNode) moderatorFee(profile *pb.Profile, transactionTotal uint64, paymentCoin, currencyCode string) (uint64, error) {
+	if profile.ModeratorInfo == nil || profile.ModeratorInfo.Fee == nil {
+		return 0, errors.New("unrecognized fee type")
+	}
+	var err error
 	switch profile.ModeratorInfo.Fee.FeeType {
 	case pb.Moderator_Fee_PERCENTAGE:
 		return uint64(float64(transactionTotal) * (float64(profile.ModeratorInfo.Fee.Percentage) / 100)), nil
@@ -152,7 +162,7 @@ func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64, paymentCoin, c
 			return profile.ModeratorInfo.Fee.FixedFee.Amount, nil
 		}
 		// TODO check for CRYPTO + FIX PRICE
-		fee, err := n.getPriceInSatoshi(paymentCoin, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
+		fee, err := n.getPriceInSatoshi(paymentCoin, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount, nil)
 		if err != nil {
 			return 0, err
 		} else if fee >= transactionTotal {
@@ -166,7 +176,7 @@ func (n *OpenBazaarNode) GetModeratorFee(transactionTotal uint64, paymentCoin, c
 			fixed = profile.ModeratorInfo.Fee.FixedFee.Amount
 		} else {
 			// TODO check for CRYPTO + FIX PRICE
-			fixed, err = n.getPriceInSatoshi(paymentCoin, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount)
+			fixed, err = n.getPriceInSatoshi(paymentCoin, profile.ModeratorInfo.Fee.FixedFee.CurrencyCode, profile.ModeratorInfo.Fee.FixedFee.Amount, nil)
 			if err != nil {
 				return 0, err
 			}
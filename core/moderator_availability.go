@@ -0,0 +1,362 @@
+package core
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	ipnspath "gx/ipfs/QmQAgv6Gaoe2tQpcabqwKXKChp2MZ7i3UXv9DqTTaxCaTR/go-path"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/op/go-logging"
+	"github.com/phoreproject/openbazaar-go/ipfs"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// ModeratorAvailabilityFile is the name of the file in the root directory
+	// which holds the moderator's signed availability status
+	ModeratorAvailabilityFile = "availability.json"
+
+	availabilityMonitorTestingInterval = time.Duration(5) * time.Minute
+	availabilityMonitorRegularInterval = time.Duration(1) * time.Hour
+)
+
+var (
+	// ErrModeratorAvailabilityNotFound is returned when a moderator has never
+	// published an availability status
+	ErrModeratorAvailabilityNotFound = errors.New("moderator availability not found")
+	// ErrReturnDateInPast is returned when an unavailable status is published
+	// with a return date which has already passed
+	ErrReturnDateInPast = errors.New("return date must be in the future")
+)
+
+// IsModeratorUnavailable returns true if the availability status marks the
+// moderator as unavailable at the given time. A status with a return date in
+// the past is treated as available.
+func IsModeratorUnavailable(availability *pb.ModeratorAvailability, now time.Time) bool {
+	if availability == nil || availability.Status != pb.ModeratorAvailability_UNAVAILABLE {
+		return false
+	}
+	if availability.ReturnDate == nil {
+		return true
+	}
+	returnDate, err := ptypes.Timestamp(availability.ReturnDate)
+	if err != nil {
+		return true
+	}
+	return now.Before(returnDate)
+}
+
+// SetModeratorAvailability signs the availability status and writes it to the
+// root directory. The caller is responsible for republishing with SeedNode.
+func (n *OpenBazaarNode) SetModeratorAvailability(availability *pb.ModeratorAvailability) error {
+	if !n.IsModerator() {
+		return errors.New("only moderators can set an availability status")
+	}
+	if availability.Status == pb.ModeratorAvailability_UNAVAILABLE && availability.ReturnDate != nil {
+		returnDate, err := ptypes.Timestamp(availability.ReturnDate)
+		if err != nil {
+			return err
+		}
+		if returnDate.Before(time.Now()) {
+			return ErrReturnDateInPast
+		}
+	}
+	if availability.BackupModerator != "" {
+		if _, err := peer.IDB58Decode(availability.BackupModerator); err != nil {
+			return errors.New("invalid backup moderator peer ID")
+		}
+		if availability.BackupModerator == n.IpfsNode.Identity.Pretty() {
+			return errors.New("backup moderator cannot be yourself")
+		}
+	}
+
+	id, err := n.buildModeratorID()
+	if err != nil {
+		return err
+	}
+	availability.Moderator = id
+	availability.Timestamp, err = ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return err
+	}
+
+	ser, err := proto.Marshal(availability)
+	if err != nil {
+		return err
+	}
+	sig, err := n.IpfsNode.PrivateKey.Sign(ser)
+	if err != nil {
+		return err
+	}
+	signed := &pb.SignedModeratorAvailability{
+		Availability: availability,
+		Signature:    sig,
+	}
+
+	m := jsonpb.Marshaler{
+		EnumsAsInts:  false,
+		EmitDefaults: true,
+		Indent:       "    ",
+		OrigName:     false,
+	}
+	out, err := m.MarshalToString(signed)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(n.RepoPath, "root", ModeratorAvailabilityFile), []byte(out), os.ModePerm)
+}
+
+// GetModeratorAvailability returns our own signed availability status
+func (n *OpenBazaarNode) GetModeratorAvailability() (*pb.SignedModeratorAvailability, error) {
+	b, err := ioutil.ReadFile(path.Join(n.RepoPath, "root", ModeratorAvailabilityFile))
+	if os.IsNotExist(err) {
+		return nil, ErrModeratorAvailabilityNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	signed := new(pb.SignedModeratorAvailability)
+	if err := jsonpb.UnmarshalString(string(b), signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// FetchModeratorAvailability resolves the signed availability status of
+// another moderator and verifies it was signed by that peer
+func (n *OpenBazaarNode) FetchModeratorAvailability(peerID string) (*pb.SignedModeratorAvailability, error) {
+	if peerID == n.IpfsNode.Identity.Pretty() {
+		return n.GetModeratorAvailability()
+	}
+	b, err := ipfs.ResolveThenCat(n.IpfsNode, ipnspath.FromString(path.Join(peerID, ModeratorAvailabilityFile)), time.Minute, n.IPNSQuorumSize, true)
+	if err != nil || len(b) == 0 {
+		return nil, ErrModeratorAvailabilityNotFound
+	}
+	signed := new(pb.SignedModeratorAvailability)
+	if err := jsonpb.UnmarshalString(string(b), signed); err != nil {
+		return nil, err
+	}
+	if err := verifySignaturesOnModeratorAvailability(signed, peerID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// CheckModeratorAvailability returns an ErrModeratorUnavailable if the
+// moderator has published an unavailable status. A moderator who has never
+// published a status, or whose status can't be fetched, is assumed available.
+func (n *OpenBazaarNode) CheckModeratorAvailability(peerID string) error {
+	signed, err := n.FetchModeratorAvailability(peerID)
+	if err != nil {
+		if err != ErrModeratorAvailabilityNotFound {
+			log.Warningf("unable to verify availability of moderator %s: %s", peerID, err)
+		}
+		return nil
+	}
+	if IsModeratorUnavailable(signed.Availability, time.Now()) {
+		return NewErrModeratorUnavailable(peerID, signed.Availability)
+	}
+	return nil
+}
+
+func verifySignaturesOnModeratorAvailability(signed *pb.SignedModeratorAvailability, peerID string) error {
+	if signed.Availability == nil || signed.Availability.Moderator == nil || signed.Availability.Moderator.Pubkeys == nil {
+		return errors.New("availability status is missing the moderator ID")
+	}
+	if signed.Availability.Moderator.PeerID != peerID {
+		return errors.New("availability status was published for a different peer")
+	}
+	if err := verifySignature(
+		signed.Availability,
+		signed.Availability.Moderator.Pubkeys.Identity,
+		signed.Signature,
+		peerID,
+	); err != nil {
+		switch err.(type) {
+		case invalidSigError:
+			return errors.New("moderator's identity signature on availability status failed to verify")
+		case matchKeyError:
+			return errors.New("public key in availability status does not match moderator ID")
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *OpenBazaarNode) buildModeratorID() (*pb.ID, error) {
+	id := new(pb.ID)
+	id.PeerID = n.IpfsNode.Identity.Pretty()
+	pubkey, err := n.IpfsNode.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	profile, err := n.GetProfile()
+	if err == nil {
+		id.Handle = profile.Handle
+	}
+	ecPubKey, err := n.MasterPublicKey.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	id.Pubkeys = &pb.ID_Pubkeys{
+		Identity: pubkey,
+		Bitcoin:  ecPubKey.SerializeCompressed(),
+	}
+	id.BitcoinSig, err = n.Signer.SignBitcoin([]byte(id.PeerID))
+	if err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ReplaceUnavailableModerators applies the vendor's moderator availability
+// policy. Each store moderator who has published an unavailable status is
+// swapped for the first available backup from the policy, and the listings
+// are re-signed with the new moderators. The backup a moderator advertises
+// is tried first, but only if the vendor's policy lists it.
+func (n *OpenBazaarNode) ReplaceUnavailableModerators() error {
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return err
+	}
+	policy := settings.ModeratorAvailabilityPolicy
+	if policy == nil || !policy.ReplaceUnavailable || settings.StoreModerators == nil {
+		return nil
+	}
+
+	var (
+		now          = time.Now()
+		current      = *settings.StoreModerators
+		updated      = make([]string, 0, len(current))
+		inUse        = make(map[string]bool)
+		availability = make(map[string]*pb.ModeratorAvailability)
+		replacements = make(map[string]string)
+	)
+	isAvailable := func(peerID string) bool {
+		a, ok := availability[peerID]
+		if !ok {
+			signed, err := n.FetchModeratorAvailability(peerID)
+			if err == nil {
+				a = signed.Availability
+			}
+			availability[peerID] = a
+		}
+		return !IsModeratorUnavailable(a, now)
+	}
+	for _, mod := range current {
+		inUse[mod] = true
+	}
+
+	for _, mod := range current {
+		if isAvailable(mod) {
+			updated = append(updated, mod)
+			continue
+		}
+		var advertised string
+		if a := availability[mod]; a != nil {
+			advertised = a.BackupModerator
+		}
+		replaced := false
+		for _, candidate := range backupModeratorCandidates(policy.BackupModerators, advertised) {
+			if candidate == "" || inUse[candidate] || candidate == n.IpfsNode.Identity.Pretty() {
+				continue
+			}
+			if !isAvailable(candidate) {
+				continue
+			}
+			updated = append(updated, candidate)
+			inUse[candidate] = true
+			replacements[mod] = candidate
+			replaced = true
+			break
+		}
+		if !replaced {
+			// Keep the moderator rather than leave listings unmoderated
+			updated = append(updated, mod)
+		}
+	}
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	if err := n.SetModeratorsOnListings(updated); err != nil {
+		return err
+	}
+	settings.StoreModerators = &updated
+	if err := n.Datastore.Settings().Put(settings); err != nil {
+		return err
+	}
+	if err := n.SeedNode(); err != nil {
+		return err
+	}
+
+	var added, removed []string
+	for mod, replacement := range replacements {
+		removed = append(removed, mod)
+		added = append(added, replacement)
+
+		notif := repo.ModeratorReplacedNotification{
+			ID:          repo.NewNotificationID(),
+			Type:        repo.NotifierTypeModeratorReplacedNotification,
+			ModeratorID: mod,
+			ReplacedBy:  replacement,
+		}
+		n.Broadcast <- notif
+		n.Datastore.Notifications().PutRecord(repo.NewNotification(notif, now, false))
+	}
+	go n.NotifyModerators(added, removed)
+	return nil
+}
+
+// backupModeratorCandidates orders the vendor's backup moderators with the
+// backup advertised by the unavailable moderator first. An advertised backup
+// the vendor hasn't listed is never used.
+func backupModeratorCandidates(allowed []string, advertised string) []string {
+	candidates := make([]string, 0, len(allowed))
+	for _, candidate := range allowed {
+		if candidate == advertised {
+			candidates = append([]string{candidate}, candidates...)
+		} else {
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+type moderatorAvailabilityMonitor struct {
+	node          *OpenBazaarNode
+	intervalDelay time.Duration
+	logger        *logging.Logger
+}
+
+// StartModeratorAvailabilityMonitor starts a worker which periodically
+// applies the vendor's moderator availability policy
+func (n *OpenBazaarNode) StartModeratorAvailabilityMonitor() {
+	interval := availabilityMonitorRegularInterval
+	if n.TestnetEnable {
+		interval = availabilityMonitorTestingInterval
+	}
+	monitor := &moderatorAvailabilityMonitor{
+		node:          n,
+		intervalDelay: interval,
+		logger:        logging.MustGetLogger("moderatorAvailabilityMonitor"),
+	}
+	go monitor.Run()
+}
+
+func (m *moderatorAvailabilityMonitor) Run() {
+	ticker := time.NewTicker(m.intervalDelay)
+	for range ticker.C {
+		if err := m.node.ReplaceUnavailableModerators(); err != nil {
+			m.logger.Errorf("replacing unavailable moderators: %s", err)
+		}
+	}
+}
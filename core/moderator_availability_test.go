@@ -0,0 +1,65 @@
+package core_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+func TestIsModeratorUnavailable(t *testing.T) {
+	var (
+		now       = time.Now()
+		future, _ = ptypes.TimestampProto(now.Add(24 * time.Hour))
+		past, _   = ptypes.TimestampProto(now.Add(-24 * time.Hour))
+		examples  = []struct {
+			availability *pb.ModeratorAvailability
+			expected     bool
+		}{
+			{nil, false},
+			{&pb.ModeratorAvailability{Status: pb.ModeratorAvailability_AVAILABLE}, false},
+			{&pb.ModeratorAvailability{Status: pb.ModeratorAvailability_AVAILABLE, ReturnDate: future}, false},
+			{&pb.ModeratorAvailability{Status: pb.ModeratorAvailability_UNAVAILABLE}, true},
+			{&pb.ModeratorAvailability{Status: pb.ModeratorAvailability_UNAVAILABLE, ReturnDate: future}, true},
+			{&pb.ModeratorAvailability{Status: pb.ModeratorAvailability_UNAVAILABLE, ReturnDate: past}, false},
+		}
+	)
+	for i, e := range examples {
+		if actual := core.IsModeratorUnavailable(e.availability, now); actual != e.expected {
+			t.Errorf("example %d: expected %t, got %t", i, e.expected, actual)
+		}
+	}
+}
+
+func TestErrModeratorUnavailableIsMachineReadable(t *testing.T) {
+	returnDate := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts, err := ptypes.TimestampProto(returnDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := core.NewErrModeratorUnavailable("QmModerator", &pb.ModeratorAvailability{
+		Status:          pb.ModeratorAvailability_UNAVAILABLE,
+		ReturnDate:      ts,
+		BackupModerator: "QmBackup",
+	})
+
+	var decoded map[string]string
+	if err := json.Unmarshal([]byte(subject.Error()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["code"] != "ERR_MODERATOR_UNAVAILABLE" {
+		t.Errorf("expected code ERR_MODERATOR_UNAVAILABLE, got %s", decoded["code"])
+	}
+	if decoded["moderatorId"] != "QmModerator" {
+		t.Errorf("expected moderatorId QmModerator, got %s", decoded["moderatorId"])
+	}
+	if decoded["backupModerator"] != "QmBackup" {
+		t.Errorf("expected backupModerator QmBackup, got %s", decoded["backupModerator"])
+	}
+	if decoded["returnDate"] != returnDate.Format(time.RFC3339) {
+		t.Errorf("expected returnDate %s, got %s", returnDate.Format(time.RFC3339), decoded["returnDate"])
+	}
+}
@@ -203,6 +203,14 @@ func (n *OpenBazaarNode) Follow(peerID string) error {
 	if err != nil {
 		return err
 	}
+	if err := n.SubscribeIPNSAnnouncements(peerID); err != nil {
+		log.Warningf("subscribing to IPNS announcements from %s: %s", peerID, err)
+	}
+	go func() {
+		if err := n.RefreshPeerFeed(peerID); err != nil {
+			log.Warningf("refreshing feed from %s: %s", peerID, err)
+		}
+	}()
 	return nil
 }
 
@@ -251,6 +259,11 @@ func (n *OpenBazaarNode) Unfollow(peerID string) error {
 	if err != nil {
 		return err
 	}
+	err = n.Datastore.Feed().DeleteByPeer(peerID)
+	if err != nil {
+		return err
+	}
+	n.UnsubscribeIPNSAnnouncements(peerID)
 	err = n.UpdateFollow()
 	if err != nil {
 		return err
@@ -434,6 +447,97 @@ func (n *OpenBazaarNode) SendDisputeClose(peerID string, k *libp2p.PubKey, resol
 	return n.sendMessage(peerID, k, m)
 }
 
+// SendDisputePanelVote - send a proposed, endorsed or final panel resolution to another panel member
+func (n *OpenBazaarNode) SendDisputePanelVote(peerID string, vote *pb.RicardianContract) error {
+	a, err := ptypes.MarshalAny(vote)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_DISPUTE_PANEL_VOTE,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
+// SendGroupMembership - send a group conversation membership change to peer
+func (n *OpenBazaarNode) SendGroupMembership(peerID string, membership *pb.GroupMembership) error {
+	a, err := ptypes.MarshalAny(membership)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_GROUP_MEMBERSHIP,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
+// SendPostComment - send a signed comment or repost to the author of the post it references
+func (n *OpenBazaarNode) SendPostComment(peerID string, post *pb.SignedPost) error {
+	a, err := ptypes.MarshalAny(post)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_POST_COMMENT,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
+// SendDisputeFallbackPayout - send a dispute fallback payout to the other party to sign, or to a moderator once broadcast
+func (n *OpenBazaarNode) SendDisputeFallbackPayout(peerID string, k *libp2p.PubKey, payout *pb.DisputeFallbackPayout) error {
+	a, err := ptypes.MarshalAny(payout)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_DISPUTE_FALLBACK,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, k, m)
+}
+
+// SendCaseHandover - send a signed case handover to peer
+func (n *OpenBazaarNode) SendCaseHandover(peerID string, handover *pb.SignedData) error {
+	a, err := ptypes.MarshalAny(handover)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_CASE_HANDOVER,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
+// SendCaseHandoverResponse - send a signed case handover response to peer
+func (n *OpenBazaarNode) SendCaseHandoverResponse(peerID string, response *pb.SignedData) error {
+	a, err := ptypes.MarshalAny(response)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_CASE_HANDOVER_RESPONSE,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
+// SendCaseHandoverResolution - tell the original moderator a backup moderator closed the case
+func (n *OpenBazaarNode) SendCaseHandoverResolution(peerID string, resolution *pb.SignedData) error {
+	a, err := ptypes.MarshalAny(resolution)
+	if err != nil {
+		return err
+	}
+	m := pb.Message{
+		MessageType: pb.Message_CASE_HANDOVER_RESOLUTION,
+		Payload:     a,
+	}
+	return n.sendMessage(peerID, nil, m)
+}
+
 // SendFundsReleasedByVendor - send funds released by vendor msg to peer
 func (n *OpenBazaarNode) SendFundsReleasedByVendor(peerID string, marshalledPeerPublicKey []byte, orderID string) error {
 	peerKey, err := libp2p.UnmarshalPublicKey(marshalledPeerPublicKey)
@@ -453,19 +557,39 @@ func (n *OpenBazaarNode) SendFundsReleasedByVendor(peerID string, marshalledPeer
 
 // SendChat - send chat msg to peer
 func (n *OpenBazaarNode) SendChat(peerID string, chatMessage *pb.Chat) error {
-	a, err := ptypes.MarshalAny(chatMessage)
+	p, err := peer.IDB58Decode(peerID)
 	if err != nil {
 		return err
 	}
-	m := pb.Message{
-		MessageType: pb.Message_CHAT,
-		Payload:     a,
-	}
+	chatMessage.Ratchet = n.RatchetSupported()
 
-	p, err := peer.IDB58Decode(peerID)
-	if err != nil {
-		return err
+	// Changes to earlier messages are sent as CHAT_CHANGE so nodes which
+	// don't know them ignore them rather than showing them as new messages
+	m := pb.Message{MessageType: pb.Message_CHAT}
+	if IsChatChange(chatMessage.Flag) {
+		m.MessageType = pb.Message_CHAT_CHANGE
 	}
+	if chatMessage.Flag == pb.Chat_MESSAGE || chatMessage.Flag == pb.Chat_EDIT {
+		rm, err := n.EncryptRatchetChat(p, chatMessage)
+		if err == nil {
+			m.Payload, err = ptypes.MarshalAny(rm)
+			if err != nil {
+				return err
+			}
+			if m.MessageType == pb.Message_CHAT {
+				m.MessageType = pb.Message_RATCHET_CHAT
+			}
+		} else if err != errRatchetNotSupported {
+			log.Errorf("Error encrypting chat message to %s with ratchet session: %s", peerID, err)
+		}
+	}
+	if m.Payload == nil {
+		m.Payload, err = ptypes.MarshalAny(chatMessage)
+		if err != nil {
+			return err
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), n.OfflineMessageFailoverTimeout)
 	defer cancel()
 	err = n.Service.SendMessage(ctx, p, &m)
@@ -64,6 +64,18 @@ type PurchaseData struct {
 	AlternateContactInfo string  `json:"alternateContactInfo"`
 	RefundAddress        *string `json:"refundAddress"` //optional, can be left out of json
 	PaymentCoin          string  `json:"paymentCoin"`
+
+	// IgnoreModeratorAvailability places the order even though the selected
+	// moderator has published an unavailable status
+	IgnoreModeratorAvailability bool `json:"ignoreModeratorAvailability"`
+
+	// PanelModerators optionally adds two more moderators who, with the
+	// selected moderator, resolve any dispute by a majority of the three
+	PanelModerators []string `json:"panelModerators"`
+
+	// PaymentChannel pays the order through an invoice issued by the
+	// vendor's payment channel daemon instead of an on-chain address
+	PaymentChannel bool `json:"paymentChannel"`
 }
 
 const (
@@ -84,15 +96,34 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderID string, paymentAd
 	if err != nil {
 		return "", "", 0, false, err
 	}
+	if err := n.CheckVendorVacationMode(contract.VendorListings[0].VendorID.PeerID); err != nil {
+		return "", "", 0, false, err
+	}
 	wal, err := n.Multiwallet.WalletForCurrencyCode(data.PaymentCoin)
 	if err != nil {
 		return "", "", 0, false, err
 	}
 
+	// Keep the exchange rates the order was priced at once it is placed
+	var rates []repo.ExchangeRate
+	defer func() {
+		if err == nil {
+			n.RecordOrderRates(orderID, rates)
+		}
+	}()
+
 	// Add payment data and send to vendor
+	if data.Moderator != "" && data.PaymentChannel {
+		return "", "", 0, false, errors.New("payment channel orders can't be moderated")
+	}
 	if data.Moderator != "" { // Moderated payment
+		if !data.IgnoreModeratorAvailability {
+			if err := n.CheckModeratorAvailability(data.Moderator); err != nil {
+				return "", "", 0, false, err
+			}
+		}
 
-		contract, err := prepareModeratedOrderContract(data, n, contract, wal)
+		contract, rates, err = prepareModeratedOrderContract(data, n, contract, wal)
 		if err != nil {
 			return "", "", 0, false, err
 		}
@@ -104,13 +135,44 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderID string, paymentAd
 
 		// Send to order vendor
 		merchantResponse, err := n.SendOrder(contract.VendorListings[0].VendorID.PeerID, contract)
-		if err != nil {
+		if err != nil || IsVacationOrderQueued(merchantResponse) {
 			return processOfflineModeratedOrder(n, contract)
 		}
 		return processOnlineModeratedOrder(merchantResponse, n, contract)
 
 	}
 
+	// Payment channel payment
+	if data.PaymentChannel {
+		if !n.AcceptsPaymentChannel(data.PaymentCoin) {
+			return "", "", 0, false, ErrPaymentChannelUnavailable
+		}
+		payment := new(pb.Order_Payment)
+		payment.Method = pb.Order_Payment_PAYMENT_CHANNEL
+		payment.Coin = data.PaymentCoin
+		contract.BuyerOrder.Payment = payment
+
+		var total uint64
+		total, rates, err = n.CalculateOrderTotalAndRates(contract)
+		if err != nil {
+			return "", "", 0, false, err
+		}
+		payment.Amount = total
+
+		contract, err = n.SignOrder(contract)
+		if err != nil {
+			return "", "", 0, false, err
+		}
+
+		// Only the vendor's daemon can issue the invoice so there is no
+		// offline fallback
+		merchantResponse, err := n.SendOrder(contract.VendorListings[0].VendorID.PeerID, contract)
+		if err != nil {
+			return "", "", 0, false, ErrPaymentChannelVendorOffline
+		}
+		return processPaymentChannelOrder(merchantResponse, n, contract)
+	}
+
 	// Direct payment
 	payment := new(pb.Order_Payment)
 	payment.Method = pb.Order_Payment_ADDRESS_REQUEST
@@ -118,7 +180,7 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderID string, paymentAd
 	contract.BuyerOrder.Payment = payment
 
 	// Calculate payment amount
-	total, err := n.CalculateOrderTotal(contract)
+	total, rates, err := n.CalculateOrderTotalAndRates(contract)
 	if err != nil {
 		return "", "", 0, false, err
 	}
@@ -131,18 +193,18 @@ func (n *OpenBazaarNode) Purchase(data *PurchaseData) (orderID string, paymentAd
 
 	// Send to order vendor and request a payment address
 	merchantResponse, err := n.SendOrder(contract.VendorListings[0].VendorID.PeerID, contract)
-	if err != nil {
+	if err != nil || IsVacationOrderQueued(merchantResponse) {
 		return processOfflineDirectOrder(n, wal, contract, payment)
 	}
 	return processOnlineDirectOrder(merchantResponse, n, wal, contract)
 }
 
-func prepareModeratedOrderContract(data *PurchaseData, n *OpenBazaarNode, contract *pb.RicardianContract, wal wallet.Wallet) (*pb.RicardianContract, error) {
+func prepareModeratedOrderContract(data *PurchaseData, n *OpenBazaarNode, contract *pb.RicardianContract, wal wallet.Wallet) (*pb.RicardianContract, []repo.ExchangeRate, error) {
 	if data.Moderator == n.IpfsNode.Identity.Pretty() {
-		return nil, errors.New("cannot select self as moderator")
+		return nil, nil, errors.New("cannot select self as moderator")
 	}
 	if data.Moderator == contract.VendorListings[0].VendorID.PeerID {
-		return nil, errors.New("cannot select vendor as moderator")
+		return nil, nil, errors.New("cannot select vendor as moderator")
 	}
 	payment := new(pb.Order_Payment)
 	payment.Method = pb.Order_Payment_MODERATED
@@ -151,28 +213,40 @@ func prepareModeratedOrderContract(data *PurchaseData, n *OpenBazaarNode, contra
 
 	profile, err := n.FetchProfile(data.Moderator, true)
 	if err != nil {
-		return nil, errors.New("moderator could not be found")
+		return nil, nil, errors.New("moderator could not be found")
 	}
 	moderatorKeyBytes, err := hex.DecodeString(profile.BitcoinPubkey)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if !profile.Moderator || profile.ModeratorInfo == nil || len(profile.ModeratorInfo.AcceptedCurrencies) == 0 {
-		return nil, errors.New("moderator is not capable of moderating this transaction")
+		return nil, nil, errors.New("moderator is not capable of moderating this transaction")
 	}
 
 	if !currencyInAcceptedCurrenciesList(data.PaymentCoin, profile.ModeratorInfo.AcceptedCurrencies) {
-		return nil, errors.New("moderator does not accept our currency")
+		return nil, nil, errors.New("moderator does not accept our currency")
+	}
+	panelKeyBytes, err := n.panelModeratorKeys(data, contract)
+	if err != nil {
+		return nil, nil, err
 	}
 	contract.BuyerOrder.Payment = payment
-	total, err := n.CalculateOrderTotal(contract)
+	total, rates, err := n.CalculateOrderTotalAndRates(contract)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	payment.Amount = total
 	fpb := wal.GetFeePerByte(wallet.NORMAL)
 	if (fpb * EscrowReleaseSize) > (payment.Amount / 4) {
-		return nil, errors.New("transaction fee too high for moderated payment")
+		return nil, nil, errors.New("transaction fee too high for moderated payment")
+	}
+	if len(panelKeyBytes) > 0 {
+		// The panel is paid by the order moderator's fee schedule, fixed
+		// here so no member can raise it when resolving a dispute
+		payment.PanelFee, err = n.moderatorFee(&profile, total, payment.Coin, wal.CurrencyCode())
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	/* Generate a payment address using the first child key derived from the buyers's,
@@ -180,33 +254,45 @@ func prepareModeratedOrderContract(data *PurchaseData, n *OpenBazaarNode, contra
 	chaincode := make([]byte, 32)
 	_, err = rand.Read(chaincode)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	vendorKey, err := wal.ChildKey(contract.VendorListings[0].VendorID.Pubkeys.Bitcoin, chaincode, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	buyerKey, err := wal.ChildKey(contract.BuyerOrder.BuyerID.Pubkeys.Bitcoin, chaincode, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	moderatorKey, err := wal.ChildKey(moderatorKeyBytes, chaincode, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	modPub, err := moderatorKey.ECPubKey()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	payment.ModeratorKey = modPub.SerializeCompressed()
+	for i, keyBytes := range panelKeyBytes {
+		panelKey, err := wal.ChildKey(keyBytes, chaincode, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		panelPub, err := panelKey.ECPubKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		payment.PanelModerators = append(payment.PanelModerators, data.PanelModerators[i])
+		payment.PanelModeratorKeys = append(payment.PanelModeratorKeys, panelPub.SerializeCompressed())
+	}
 
 	timeout, err := time.ParseDuration(strconv.Itoa(int(contract.VendorListings[0].Metadata.EscrowTimeoutHours)) + "h")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2, timeout, vendorKey)
+	addr, redeemScript, err := orderEscrowScript(wal, buyerKey, vendorKey, moderatorKey, payment, timeout)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	payment.Address = addr.EncodeAddress()
 	payment.RedeemScript = hex.EncodeToString(redeemScript)
@@ -215,9 +301,9 @@ func prepareModeratedOrderContract(data *PurchaseData, n *OpenBazaarNode, contra
 
 	err = wal.AddWatchedAddress(addr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return contract, nil
+	return contract, rates, nil
 }
 
 func processOnlineDirectOrder(resp *pb.Message, n *OpenBazaarNode, wal wallet.Wallet, contract *pb.RicardianContract) (string, string, uint64, bool, error) {
@@ -640,11 +726,7 @@ func getRatingKeysForOrder(data *PurchaseData, n *OpenBazaarNode, ts *timestamp.
 	for range data.Items {
 		// FIXME: bug here. This should use a different key for each item. This code doesn't look like it will do that.
 		// Also the fix for this will also need to be included in the rating signing code.
-		mPubkey, err := n.MasterPrivateKey.Neuter()
-		if err != nil {
-			return nil, err
-		}
-		ratingKey, err := mPubkey.Child(uint32(ts.Seconds))
+		ratingKey, err := n.MasterPublicKey.Child(uint32(ts.Seconds))
 		if err != nil {
 			return nil, err
 		}
@@ -671,22 +753,17 @@ func getContractIdentity(n *OpenBazaarNode) (*pb.ID, error) {
 	}
 	keys := new(pb.ID_Pubkeys)
 	keys.Identity = pubkey
-	ecPubKey, err := n.MasterPrivateKey.ECPubKey()
+	ecPubKey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return nil, err
 	}
 	keys.Bitcoin = ecPubKey.SerializeCompressed()
 	id.Pubkeys = keys
 	// Sign the PeerID with the Bitcoin key
-	ecPrivKey, err := n.MasterPrivateKey.ECPrivKey()
+	id.BitcoinSig, err = n.Signer.SignBitcoin([]byte(id.PeerID))
 	if err != nil {
 		return nil, err
 	}
-	sig, err := ecPrivKey.Sign([]byte(id.PeerID))
-	if err != nil {
-		return nil, err
-	}
-	id.BitcoinSig = sig.Serialize()
 	return id, nil
 }
 
@@ -787,28 +864,11 @@ func (n *OpenBazaarNode) CancelOfflineOrder(contract *pb.RicardianContract, reco
 		return errors.New("cannot cancel order because utxo has already been spent")
 	}
 
-	chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-	if err != nil {
-		return err
-	}
-	mPrivKey := n.MasterPrivateKey
-	mECKey, err := mPrivKey.ECPrivKey()
-	if err != nil {
-		return err
-	}
-	buyerKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-	if err != nil {
-		return err
-	}
-	redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-	if err != nil {
-		return err
-	}
 	refundAddress, err := wal.DecodeAddress(contract.BuyerOrder.RefundAddress)
 	if err != nil {
 		return err
 	}
-	_, err = wal.SweepAddress(utxos, &refundAddress, buyerKey, &redeemScript, wallet.NORMAL)
+	err = n.SweepEscrow(wal, utxos, refundAddress, contract)
 	if err != nil {
 		return err
 	}
@@ -835,12 +895,24 @@ func (n *OpenBazaarNode) CalcOrderID(order *pb.Order) (string, error) {
 
 // CalculateOrderTotal - calculate the total in satoshi/wei
 func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (uint64, error) {
+	return n.calculateOrderTotal(contract, nil)
+}
+
+// CalculateOrderTotalAndRates - calculate the total in satoshi/wei and return
+// the exchange rates it was priced at
+func (n *OpenBazaarNode) CalculateOrderTotalAndRates(contract *pb.RicardianContract) (uint64, []repo.ExchangeRate, error) {
+	used := new(usedRates)
+	total, err := n.calculateOrderTotal(contract, used)
+	return total, used.list(), err
+}
+
+func (n *OpenBazaarNode) calculateOrderTotal(contract *pb.RicardianContract, used *usedRates) (uint64, error) {
 	wal, err := n.Multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
 	if err != nil {
 		return 0, err
 	}
-	if wal.ExchangeRates() != nil {
-		wal.ExchangeRates().GetLatestRate("") // Refresh the exchange rates
+	if rates := n.ExchangeRatesFor(wal); rates != nil {
+		rates.GetLatestRate("") // Refresh the exchange rates
 	}
 
 	var total uint64
@@ -867,14 +939,14 @@ func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (ui
 		}
 
 		if l.Metadata.Format == pb.Listing_Metadata_MARKET_PRICE { // MARKET + CRYPTO
-			satoshis, err = n.getMarketPriceInSatoshis(contract.BuyerOrder.Payment.Coin, l.Metadata.CoinType, itemQuantity)
+			satoshis, err = n.getMarketPriceInSatoshis(contract.BuyerOrder.Payment.Coin, l.Metadata.CoinType, itemQuantity, used)
 			satoshis += uint64(float32(satoshis) * l.Metadata.PriceModifier / 100.0)
 			itemQuantity = 1
 		} else if l.Metadata.ContractType == pb.Listing_Metadata_CRYPTOCURRENCY { // FIXED + CRYPTO
 			satoshis += l.Item.Price * uint64(float64(itemQuantity)/float64(l.Metadata.CoinDivisibility))
 			itemQuantity = 1
 		} else { // FIXED + NO CRYPTO
-			satoshis, err = n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, l.Item.Price)
+			satoshis, err = n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, l.Item.Price, used)
 		}
 		if err != nil {
 			return 0, err
@@ -893,7 +965,7 @@ func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (ui
 					if sku.Surcharge < 0 {
 						surcharge = uint64(-sku.Surcharge)
 					}
-					satoshis, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, surcharge)
+					satoshis, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, surcharge, used)
 					if err != nil {
 						return 0, err
 					}
@@ -919,7 +991,7 @@ func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (ui
 				if id.B58String() == vendorCoupon.GetHash() {
 					if discount := vendorCoupon.GetPriceDiscount(); discount > 0 {
 						// TODO check for CRYPTO + FIX PRICE
-						satoshis, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, discount)
+						satoshis, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, l.Metadata.PricingCurrency, discount, used)
 						if err != nil {
 							return 0, err
 						}
@@ -943,7 +1015,7 @@ func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (ui
 		total += itemTotal
 	}
 
-	shippingTotal, err := n.calculateShippingTotalForListings(contract, physicalGoods)
+	shippingTotal, err := n.calculateShippingTotalForListings(contract, physicalGoods, used)
 	if err != nil {
 		return 0, err
 	}
@@ -952,7 +1024,7 @@ func (n *OpenBazaarNode) CalculateOrderTotal(contract *pb.RicardianContract) (ui
 	return total, nil
 }
 
-func (n *OpenBazaarNode) calculateShippingTotalForListings(contract *pb.RicardianContract, listings map[string]*pb.Listing) (uint64, error) {
+func (n *OpenBazaarNode) calculateShippingTotalForListings(contract *pb.RicardianContract, listings map[string]*pb.Listing, used *usedRates) (uint64, error) {
 	type itemShipping struct {
 		primary               uint64
 		secondary             uint64
@@ -1006,14 +1078,14 @@ func (n *OpenBazaarNode) calculateShippingTotalForListings(contract *pb.Ricardia
 		if !ok {
 			return 0, errors.New("shipping service not found in listing")
 		}
-		shippingSatoshi, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, listing.Metadata.PricingCurrency, service.Price)
+		shippingSatoshi, err := n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, listing.Metadata.PricingCurrency, service.Price, used)
 		if err != nil {
 			return 0, err
 		}
 
 		var secondarySatoshi uint64
 		if service.AdditionalItemPrice > 0 {
-			secondarySatoshi, err = n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, listing.Metadata.PricingCurrency, service.AdditionalItemPrice)
+			secondarySatoshi, err = n.getPriceInSatoshi(contract.BuyerOrder.Payment.Coin, listing.Metadata.PricingCurrency, service.AdditionalItemPrice, used)
 			if err != nil {
 				return 0, err
 			}
@@ -1082,7 +1154,7 @@ func quantityForItem(version uint32, item *pb.Order_Item) uint64 {
 	}
 }
 
-func (n *OpenBazaarNode) getPriceInSatoshi(paymentCoin, currencyCode string, amount uint64) (uint64, error) {
+func (n *OpenBazaarNode) getPriceInSatoshi(paymentCoin, currencyCode string, amount uint64, used *usedRates) (uint64, error) {
 	const reserveCurrency = "BTC"
 	if NormalizeCurrencyCode(currencyCode) == NormalizeCurrencyCode(paymentCoin) || "T"+NormalizeCurrencyCode(currencyCode) == NormalizeCurrencyCode(paymentCoin) {
 		return amount, nil
@@ -1114,19 +1186,23 @@ func (n *OpenBazaarNode) getPriceInSatoshi(paymentCoin, currencyCode string, amo
 		return 0, fmt.Errorf("%s wallet not found for exchange rates", reserveCurrency)
 	}
 
-	if wal.ExchangeRates() == nil {
+	rates := n.ExchangeRatesFor(wal)
+	if rates == nil {
 		return 0, ErrPriceCalculationRequiresExchangeRates
 	}
-	reserveIntoOriginRate, err := wal.ExchangeRates().GetExchangeRate(currencyCode)
+	reserveIntoOriginRate, err := rates.GetExchangeRate(currencyCode)
 	if err != nil {
 		return 0, err
 	}
+	used.add(reserveCurrency, strings.ToUpper(currencyCode), reserveIntoOriginRate)
 	originIntoReserveRate := 1 / reserveIntoOriginRate
-	reserveIntoResultRate, err := wal.ExchangeRates().GetExchangeRate(paymentCoin)
+	resultCurrency := paymentCoin
+	reserveIntoResultRate, err := rates.GetExchangeRate(paymentCoin)
 	if err != nil {
 		// TODO: remove hack once ExchangeRates can be made aware of testnet currencies
 		if strings.HasPrefix(paymentCoin, "T") {
-			reserveIntoResultRate, err = wal.ExchangeRates().GetExchangeRate(strings.TrimPrefix(paymentCoin, "T"))
+			resultCurrency = strings.TrimPrefix(paymentCoin, "T")
+			reserveIntoResultRate, err = rates.GetExchangeRate(resultCurrency)
 			if err != nil {
 				return 0, err
 			}
@@ -1134,6 +1210,7 @@ func (n *OpenBazaarNode) getPriceInSatoshi(paymentCoin, currencyCode string, amo
 			return 0, err
 		}
 	}
+	used.add(reserveCurrency, strings.ToUpper(resultCurrency), reserveIntoResultRate)
 
 	reserveValue, err := originValue.ConvertTo(reserveCurrencyDef, originIntoReserveRate)
 	if err != nil {
@@ -1150,19 +1227,21 @@ func (n *OpenBazaarNode) getPriceInSatoshi(paymentCoin, currencyCode string, amo
 	return result, nil
 }
 
-func (n *OpenBazaarNode) getMarketPriceInSatoshis(pricingCurrency, currencyCode string, amount uint64) (uint64, error) {
+func (n *OpenBazaarNode) getMarketPriceInSatoshis(pricingCurrency, currencyCode string, amount uint64, used *usedRates) (uint64, error) {
 	wal, err := n.Multiwallet.WalletForCurrencyCode(pricingCurrency)
 	if err != nil {
 		return 0, err
 	}
-	if wal.ExchangeRates() == nil {
+	rates := n.ExchangeRatesFor(wal)
+	if rates == nil {
 		return 0, ErrPriceCalculationRequiresExchangeRates
 	}
 
-	rate, err := wal.ExchangeRates().GetExchangeRate(currencyCode)
+	rate, err := rates.GetExchangeRate(currencyCode)
 	if err != nil {
 		return 0, err
 	}
+	used.add(strings.ToUpper(pricingCurrency), strings.ToUpper(currencyCode), rate)
 
 	return uint64(float64(amount) / rate), nil
 }
@@ -1254,6 +1333,9 @@ func (n *OpenBazaarNode) ValidateOrder(contract *pb.RicardianContract, checkInve
 		if !validMod {
 			return errors.New("invalid moderator")
 		}
+		if err := validatePanelModerators(contract.BuyerOrder.Payment, availableMods); err != nil {
+			return err
+		}
 	}
 
 	// Validate that the hash of the items in the contract match claimed hash in the order
@@ -1464,7 +1546,7 @@ func (n *OpenBazaarNode) ValidateDirectPaymentAddress(order *pb.Order) error {
 	if err != nil {
 		return err
 	}
-	mECKey, err := n.MasterPrivateKey.ECPubKey()
+	mECKey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return err
 	}
@@ -1514,7 +1596,7 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order, timeou
 	if err != nil {
 		return err
 	}
-	mECKey, err := n.MasterPrivateKey.ECPubKey()
+	mECKey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return err
 	}
@@ -1537,7 +1619,20 @@ func (n *OpenBazaarNode) ValidateModeratedPaymentAddress(order *pb.Order, timeou
 	if !bytes.Equal(order.Payment.ModeratorKey, modPub.SerializeCompressed()) {
 		return errors.New("invalid moderator key")
 	}
-	addr, redeemScript, err := wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2, timeout, vendorKey)
+	for i, mod := range order.Payment.PanelModerators {
+		panelKey, err := n.resolveModeratorEscrowKey(wal, mod, chaincode)
+		if err != nil {
+			return err
+		}
+		panelPub, err := panelKey.ECPubKey()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(order.Payment.PanelModeratorKeys[i], panelPub.SerializeCompressed()) {
+			return errors.New("invalid panel moderator key")
+		}
+	}
+	addr, redeemScript, err := orderEscrowScript(wal, buyerKey, vendorKey, moderatorKey, order.Payment, timeout)
 	if err != nil {
 		return err
 	}
@@ -1558,7 +1653,7 @@ func (n *OpenBazaarNode) SignOrder(contract *pb.RicardianContract) (*pb.Ricardia
 	}
 	s := new(pb.Signature)
 	s.Section = pb.Signature_ORDER
-	idSig, err := n.IpfsNode.PrivateKey.Sign(serializedOrder)
+	idSig, err := n.Signer.SignIdentity(serializedOrder)
 	if err != nil {
 		return contract, err
 	}
@@ -0,0 +1,326 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+// panelEscrowSequenceOffset is the position of the sequence lock in a panel
+// escrow with a timeout
+const panelEscrowSequenceOffset = 38
+
+// panelEscrowWallet is implemented by wallets which can broadcast a
+// transaction we finished ourselves. The wallet can only satisfy a plain
+// multisig script so we build the spend of a panel escrow.
+type panelEscrowWallet interface {
+	Params() *chaincfg.Params
+	Broadcast(tx *wire.MsgTx) error
+}
+
+// orderEscrowScript returns the address and redeem script of a moderated
+// order's escrow
+func orderEscrowScript(wal wallet.Wallet, buyerKey, vendorKey, moderatorKey *hd.ExtendedKey, payment *pb.Order_Payment, timeout time.Duration) (btcutil.Address, []byte, error) {
+	if !IsPanelOrder(payment) {
+		return wal.GenerateMultisigScript([]hd.ExtendedKey{*buyerKey, *vendorKey, *moderatorKey}, 2, timeout, vendorKey)
+	}
+	return panelEscrowScript(wal, buyerKey, vendorKey, payment, timeout)
+}
+
+// panelEscrowScript returns the escrow of an order moderated by a panel. The
+// buyer and vendor can release it together, the vendor alone once the escrow
+// timeout has passed, or any two of the moderators:
+//
+//	OP_IF
+//	  OP_IF
+//	    <buyer> OP_CHECKSIGVERIFY
+//	  OP_ELSE
+//	    <sequence lock> OP_CHECKSEQUENCEVERIFY OP_DROP
+//	  OP_ENDIF
+//	  <vendor> OP_CHECKSIG
+//	OP_ELSE
+//	  OP_2 <moderator> <panel 1> <panel 2> OP_3 OP_CHECKMULTISIG
+//	OP_ENDIF
+//
+// Without an escrow timeout the first branch only takes the buyer's and
+// vendor's signatures.
+func panelEscrowScript(wal wallet.Wallet, buyerKey, vendorKey *hd.ExtendedKey, payment *pb.Order_Payment, timeout time.Duration) (btcutil.Address, []byte, error) {
+	// Bitcoin Cash signs with its own sighash algorithm, which we don't
+	// implement for the spends we sign ourselves
+	if code := strings.ToUpper(wal.CurrencyCode()); code == "BCH" || code == "TBCH" {
+		return nil, nil, ErrPanelEscrowUnsupported
+	}
+	if len(payment.PanelModeratorKeys) != PanelSize-1 {
+		return nil, nil, errors.New("invalid moderator panel")
+	}
+	buyerPub, err := buyerKey.ECPubKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	vendorPub, err := vendorKey.ECPubKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	var sequenceLock uint32
+	if hours := uint32(timeout.Hours()); hours > 0 {
+		sequenceLock = blockchain.LockTimeToSequence(false, hours*ConfirmationsPerHour)
+	}
+	keys := [][]byte{buyerPub.SerializeCompressed(), vendorPub.SerializeCompressed(), payment.ModeratorKey}
+	keys = append(keys, payment.PanelModeratorKeys...)
+	redeemScript, err := buildPanelEscrowScript(keys, sequenceLock)
+	if err != nil {
+		return nil, nil, err
+	}
+	addr, err := panelEscrowAddress(wal, redeemScript)
+	if err != nil {
+		return nil, nil, err
+	}
+	return addr, redeemScript, nil
+}
+
+// panelEscrowAddress returns the address of a panel escrow, which is paid
+// to the same kind of script hash as the wallet's own multisig addresses
+func panelEscrowAddress(wal wallet.Wallet, redeemScript []byte) (btcutil.Address, error) {
+	pw, ok := wal.(panelEscrowWallet)
+	if !ok {
+		return nil, ErrPanelEscrowUnsupported
+	}
+	segwit, err := escrowSegwit(wal, redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	if segwit {
+		scriptHash := sha256.Sum256(redeemScript)
+		return btcutil.NewAddressWitnessScriptHash(scriptHash[:], pw.Params())
+	}
+	return btcutil.NewAddressScriptHash(redeemScript, pw.Params())
+}
+
+// buildPanelEscrowScript returns the panel escrow script of the buyer,
+// vendor and panel moderator keys. A zero sequence lock leaves out the
+// vendor's timeout branch.
+func buildPanelEscrowScript(keys [][]byte, sequenceLock uint32) ([]byte, error) {
+	if len(keys) != PanelSize+2 {
+		return nil, errors.New("invalid moderator panel")
+	}
+	for _, key := range keys {
+		if _, err := btcec.ParsePubKey(key, btcec.S256()); err != nil || len(key) != btcec.PubKeyBytesLenCompressed {
+			return nil, errors.New("invalid panel escrow key")
+		}
+	}
+	builder := txscript.NewScriptBuilder()
+	builder.AddOp(txscript.OP_IF)
+	if sequenceLock == 0 {
+		builder.AddData(keys[0]).AddOp(txscript.OP_CHECKSIGVERIFY)
+	} else {
+		builder.AddOp(txscript.OP_IF).
+			AddData(keys[0]).AddOp(txscript.OP_CHECKSIGVERIFY).
+			AddOp(txscript.OP_ELSE).
+			AddInt64(int64(sequenceLock)).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).AddOp(txscript.OP_DROP).
+			AddOp(txscript.OP_ENDIF)
+	}
+	builder.AddData(keys[1]).AddOp(txscript.OP_CHECKSIG).
+		AddOp(txscript.OP_ELSE).
+		AddOp(txscript.OP_2)
+	for _, key := range keys[2:] {
+		builder.AddData(key)
+	}
+	builder.AddOp(txscript.OP_3).
+		AddOp(txscript.OP_CHECKMULTISIG).
+		AddOp(txscript.OP_ENDIF)
+	return builder.Script()
+}
+
+// parsePanelEscrow returns the buyer, vendor and panel moderator keys of a
+// panel escrow script and its sequence lock, or false if the script isn't
+// one
+func parsePanelEscrow(redeemScript []byte) ([][]byte, uint32, bool) {
+	pushes, err := txscript.PushedData(redeemScript)
+	if err != nil {
+		return nil, 0, false
+	}
+	var keys [][]byte
+	for _, data := range pushes {
+		if len(data) == btcec.PubKeyBytesLenCompressed {
+			keys = append(keys, data)
+		}
+	}
+	var sequenceLock uint32
+	if len(redeemScript) > panelEscrowSequenceOffset && redeemScript[1] == txscript.OP_IF {
+		if sequenceLock, err = readSequenceLock(redeemScript[panelEscrowSequenceOffset:]); err != nil {
+			return nil, 0, false
+		}
+	}
+	script, err := buildPanelEscrowScript(keys, sequenceLock)
+	if err != nil || !bytes.Equal(script, redeemScript) {
+		return nil, 0, false
+	}
+	return keys, sequenceLock, true
+}
+
+// readSequenceLock returns the number pushed at the start of a script
+func readSequenceLock(script []byte) (uint32, error) {
+	op := script[0]
+	switch {
+	case op >= txscript.OP_1 && op <= txscript.OP_16:
+		return uint32(op-txscript.OP_1) + 1, nil
+	case op >= txscript.OP_DATA_1 && op <= txscript.OP_DATA_4 && len(script) > int(op):
+		var lock uint32
+		for i, b := range script[1 : 1+op] {
+			lock |= uint32(b) << (8 * uint(i))
+		}
+		return lock, nil
+	}
+	return 0, errors.New("invalid sequence lock")
+}
+
+// panelEscrowKeys returns the buyer, vendor and panel moderator keys of a
+// panel escrow script, or false if the script isn't one
+func panelEscrowKeys(redeemScript []byte) ([][]byte, bool) {
+	keys, _, ok := parsePanelEscrow(redeemScript)
+	return keys, ok
+}
+
+// panelSpendStack returns the items which satisfy a panel escrow given one
+// input's signatures in the order of the script's keys, or false if they
+// are not enough to spend it. The buyer's and vendor's branch takes another
+// item in an escrow with a timeout.
+func panelSpendStack(sigs [][]byte, timeout bool) ([][]byte, bool) {
+	if len(sigs) != PanelSize+2 {
+		return nil, false
+	}
+	if sigs[0] != nil && sigs[1] != nil {
+		if timeout {
+			return [][]byte{sigs[1], sigs[0], {0x01}, {0x01}}, true
+		}
+		return [][]byte{sigs[1], sigs[0], {0x01}}, true
+	}
+	// OP_CHECKMULTISIG pops an extra item
+	stack := [][]byte{{}}
+	for _, sig := range sigs[2:] {
+		if sig != nil && len(stack) < 3 {
+			stack = append(stack, sig)
+		}
+	}
+	if len(stack) < 3 {
+		return nil, false
+	}
+	return append(stack, []byte{}), true
+}
+
+// panelTimeoutStack returns the items which let the vendor spend a panel
+// escrow alone once its timeout has passed
+func panelTimeoutStack(vendorSig []byte) [][]byte {
+	return [][]byte{vendorSig, {}, {0x01}}
+}
+
+// timeoutSpendStack returns the items which let the vendor spend an escrow
+// alone once its timeout has passed
+func timeoutSpendStack(redeemScript []byte, vendorSig []byte) [][]byte {
+	if _, panel := panelEscrowKeys(redeemScript); panel {
+		return panelTimeoutStack(vendorSig)
+	}
+	return [][]byte{vendorSig, {}}
+}
+
+// finishEscrowSpend sets the signature script, or the witness, of an escrow
+// input from its stack items
+func finishEscrowSpend(in *wire.TxIn, stack [][]byte, redeemScript []byte, segwit bool) error {
+	if segwit {
+		in.SignatureScript = nil
+		in.Witness = append(wire.TxWitness(stack), redeemScript)
+		return nil
+	}
+	builder := txscript.NewScriptBuilder()
+	for _, item := range stack {
+		builder.AddData(item)
+	}
+	script, err := builder.AddData(redeemScript).Script()
+	if err != nil {
+		return err
+	}
+	in.SignatureScript = script
+	in.Witness = nil
+	return nil
+}
+
+// sweepPanelEscrow spends, as the vendor, the escrow of a panel order whose
+// timeout has passed to the wallet
+func sweepPanelEscrow(wal wallet.Wallet, ins []wallet.TransactionInput, vendorKey *hd.ExtendedKey, redeemScript []byte) error {
+	pw, ok := wal.(panelEscrowWallet)
+	if !ok {
+		return ErrPanelEscrowUnsupported
+	}
+	_, sequenceLock, ok := parsePanelEscrow(redeemScript)
+	if !ok {
+		return errors.New("invalid panel escrow script")
+	}
+	if sequenceLock == 0 {
+		return ErrPanelEscrowHasNoTimeout
+	}
+	segwit, err := escrowSegwit(wal, redeemScript)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, in := range ins {
+		total += in.Value
+	}
+	release := EscrowRelease{
+		Inputs:       ins,
+		Outputs:      []wallet.TransactionOutput{{Address: wal.CurrentAddress(wallet.INTERNAL), Value: total}},
+		RedeemScript: redeemScript,
+		FeePerByte:   wal.GetFeePerByte(wallet.NORMAL),
+		SequenceLock: sequenceLock,
+	}
+	tx, err := releaseTx(release, segwit)
+	if err != nil {
+		return err
+	}
+	sigs, err := signReleaseTx(tx, segwit, release, vendorKey)
+	if err != nil {
+		return err
+	}
+	for _, sig := range sigs {
+		if err := finishEscrowSpend(tx.TxIn[sig.InputIndex], panelTimeoutStack(sig.Signature), redeemScript, segwit); err != nil {
+			return err
+		}
+	}
+	return pw.Broadcast(tx)
+}
+
+// MultisignEscrow broadcasts a transaction releasing a moderated order's
+// escrow with the buyer's and vendor's signatures
+func MultisignEscrow(wal wallet.Wallet, ins []wallet.TransactionInput, outs []wallet.TransactionOutput, buyerSigs, vendorSigs []wallet.Signature, redeemScript []byte, feePerByte uint64) error {
+	if _, ok := panelEscrowKeys(redeemScript); ok {
+		release := EscrowRelease{Inputs: ins, Outputs: outs, RedeemScript: redeemScript, FeePerByte: feePerByte}
+		return broadcastEscrowRelease(wal, release, [][]wallet.Signature{buyerSigs, vendorSigs, nil, nil, nil})
+	}
+	_, err := wal.Multisign(ins, outs, buyerSigs, vendorSigs, redeemScript, feePerByte, true)
+	return err
+}
+
+// panelMemberEscrowKey returns the escrow key of a member of an order's
+// panel
+func panelMemberEscrowKey(payment *pb.Order_Payment, peerID string) []byte {
+	i := panelIndex(payment, peerID)
+	switch {
+	case i == 0:
+		return payment.ModeratorKey
+	case i > 0 && i <= len(payment.PanelModeratorKeys):
+		return payment.PanelModeratorKeys[i-1]
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+)
+
+func panelEscrowTestKeys(t *testing.T) ([]*btcec.PrivateKey, [][]byte) {
+	var (
+		privKeys []*btcec.PrivateKey
+		pubKeys  [][]byte
+	)
+	for i := 0; i < 5; i++ {
+		key, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		privKeys = append(privKeys, key)
+		pubKeys = append(pubKeys, key.PubKey().SerializeCompressed())
+	}
+	return privKeys, pubKeys
+}
+
+func TestPanelEscrowKeys(t *testing.T) {
+	_, pubKeys := panelEscrowTestKeys(t)
+	for _, sequenceLock := range []uint32{0, 6, 864} {
+		script, err := buildPanelEscrowScript(pubKeys, sequenceLock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys, lock, ok := parsePanelEscrow(script)
+		if !ok {
+			t.Fatalf("sequence lock %d: expected the script to be a panel escrow", sequenceLock)
+		}
+		if lock != sequenceLock {
+			t.Errorf("expected a sequence lock of %d, got %d", sequenceLock, lock)
+		}
+		for i := range pubKeys {
+			if !bytes.Equal(keys[i], pubKeys[i]) {
+				t.Errorf("sequence lock %d: expected key %d to be parsed back", sequenceLock, i)
+			}
+		}
+		if keys, threshold := escrowScriptKeys(script); len(keys) != 5 || threshold != 2 {
+			t.Errorf("expected 5 escrow keys and a threshold of 2, got %d and %d", len(keys), threshold)
+		}
+	}
+
+	// A plain 2-of-5 multisig isn't a panel escrow
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_2)
+	for _, key := range pubKeys {
+		builder.AddData(key)
+	}
+	plain, err := builder.AddOp(txscript.OP_5).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := panelEscrowKeys(plain); ok {
+		t.Error("expected a plain multisig not to be a panel escrow")
+	}
+	if _, err := buildPanelEscrowScript(pubKeys[:4], 0); err == nil {
+		t.Error("expected an error building a panel escrow with four keys")
+	}
+}
+
+func TestPanelSpendStackNeedsBothPartiesOrTwoModerators(t *testing.T) {
+	sig := []byte{0x30}
+	for _, test := range []struct {
+		signers []int
+		ok      bool
+	}{
+		{[]int{0, 1}, true},
+		{[]int{2, 3}, true},
+		{[]int{2, 4}, true},
+		{[]int{3, 4}, true},
+		{[]int{0, 2}, false},
+		{[]int{1, 4}, false},
+		{[]int{0, 1, 2}, true},
+		{[]int{3}, false},
+	} {
+		sigs := make([][]byte, 5)
+		for _, i := range test.signers {
+			sigs[i] = sig
+		}
+		for _, timeout := range []bool{false, true} {
+			if _, ok := panelSpendStack(sigs, timeout); ok != test.ok {
+				t.Errorf("signers %v: expected %t, got %t", test.signers, test.ok, ok)
+			}
+		}
+	}
+}
+
+// executePanelSpend checks a transaction spending a panel escrow with
+// the signatures of the signers is valid, or isn't if ok is false
+func executePanelSpend(t *testing.T, privKeys []*btcec.PrivateKey, script []byte, segwit bool, tx *wire.MsgTx, signers []int, timeout bool) {
+	scriptHash := sha256.Sum256(script)
+	pkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+	if !segwit {
+		var err error
+		pkScript, err = txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).
+			AddData(btcutil.Hash160(script)).AddOp(txscript.OP_EQUAL).Script()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	const value = 100000
+	sigs := make([][]byte, 5)
+	for _, i := range signers {
+		var (
+			sig []byte
+			err error
+		)
+		if segwit {
+			sig, err = txscript.RawTxInWitnessSignature(tx, txscript.NewTxSigHashes(tx), 0, value, script, txscript.SigHashAll, privKeys[i])
+		} else {
+			sig, err = txscript.RawTxInSignature(tx, 0, script, txscript.SigHashAll, privKeys[i])
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i] = sig
+	}
+	var (
+		stack [][]byte
+		ok    bool
+	)
+	if len(signers) == 1 && signers[0] == 1 {
+		stack, ok = panelTimeoutStack(sigs[1]), true
+	} else if stack, ok = panelSpendStack(sigs, timeout); !ok {
+		// The buyer and a moderator must not be able to spend, so try the
+		// multisig branch with their signatures anyway
+		stack = [][]byte{{}, sigs[0], sigs[2], {}}
+	}
+	if err := finishEscrowSpend(tx.TxIn[0], stack, script, segwit); err != nil {
+		t.Fatal(err)
+	}
+
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(tx), value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = vm.Execute()
+	if ok && err != nil {
+		t.Errorf("segwit %t, signers %v: expected the spend to be valid, got %s", segwit, signers, err)
+	}
+	if !ok && err == nil {
+		t.Errorf("segwit %t, signers %v: expected the spend to be invalid", segwit, signers)
+	}
+}
+
+func panelSpendTestTx(version int32, sequence uint32) *wire.MsgTx {
+	tx := wire.NewMsgTx(version)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+	tx.TxIn[0].Sequence = sequence
+	tx.AddTxOut(wire.NewTxOut(99000, []byte{txscript.OP_TRUE}))
+	return tx
+}
+
+func TestPanelEscrowSpendExecutes(t *testing.T) {
+	privKeys, pubKeys := panelEscrowTestKeys(t)
+	for _, sequenceLock := range []uint32{0, 144} {
+		script, err := buildPanelEscrowScript(pubKeys, sequenceLock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, segwit := range []bool{false, true} {
+			for _, signers := range [][]int{{0, 1}, {2, 4}, {0, 2}} {
+				executePanelSpend(t, privKeys, script, segwit, panelSpendTestTx(1, wire.MaxTxInSequenceNum), signers, sequenceLock > 0)
+			}
+		}
+	}
+}
+
+func TestPanelEscrowTimeoutSpend(t *testing.T) {
+	privKeys, pubKeys := panelEscrowTestKeys(t)
+	script, err := buildPanelEscrowScript(pubKeys, 144)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, segwit := range []bool{false, true} {
+		executePanelSpend(t, privKeys, script, segwit, panelSpendTestTx(2, 144), []int{1}, true)
+	}
+
+	// The vendor can't spend alone before the timeout
+	tx := panelSpendTestTx(2, 143)
+	sig, err := txscript.RawTxInWitnessSignature(tx, txscript.NewTxSigHashes(tx), 0, 100000, script, txscript.SigHashAll, privKeys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := finishEscrowSpend(tx.TxIn[0], panelTimeoutStack(sig), script, true); err != nil {
+		t.Fatal(err)
+	}
+	scriptHash := sha256.Sum256(script)
+	pkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(tx), 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err == nil {
+		t.Error("expected the vendor's spend before the timeout to be invalid")
+	}
+}
+
+func TestReleaseTx(t *testing.T) {
+	_, pubKeys := panelEscrowTestKeys(t)
+	script, err := buildPanelEscrowScript(pubKeys, 144)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := EscrowRelease{
+		Inputs:       []wallet.TransactionInput{{OutpointHash: make([]byte, 32), OutpointIndex: 1, Value: 100000}},
+		Outputs:      []wallet.TransactionOutput{{Address: addr, Value: 60000}, {Address: addr, Value: 40000}},
+		RedeemScript: script,
+		FeePerByte:   10,
+	}
+	fees := make(map[bool]int64)
+	for _, segwit := range []bool{false, true} {
+		tx, err := releaseTx(release, segwit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.Version != 1 || tx.TxIn[0].Sequence != wire.MaxTxInSequenceNum {
+			t.Error("expected a release without a relative lock time")
+		}
+		fee := 100000 - tx.TxOut[0].Value - tx.TxOut[1].Value
+		if fee <= 0 || fee%2 != 0 {
+			t.Errorf("expected the fee to be split between the outputs, got %d", fee)
+		}
+		fees[segwit] = fee
+
+		timeout := release
+		timeout.SequenceLock = 144
+		timeoutTx, err := releaseTx(timeout, segwit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if timeoutTx.Version != 2 || timeoutTx.TxIn[0].Sequence != 144 {
+			t.Error("expected the vendor's release to have a relative lock time")
+		}
+	}
+	if fees[true] >= fees[false] {
+		t.Errorf("expected the witness to be discounted, got fees of %d and %d", fees[true], fees[false])
+	}
+
+	release.FeePerByte = 1000
+	if _, err := releaseTx(release, false); err != wallet.ErrorInsuffientFunds {
+		t.Errorf("expected a fee larger than an output to be refused, got %v", err)
+	}
+}
+
+func TestLockTimeReleaseOfPlainEscrowExecutes(t *testing.T) {
+	privKeys, pubKeys := panelEscrowTestKeys(t)
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_IF).AddOp(txscript.OP_2)
+	for _, key := range pubKeys[:3] {
+		builder.AddData(key)
+	}
+	script, err := builder.AddOp(txscript.OP_3).AddOp(txscript.OP_CHECKMULTISIG).
+		AddOp(txscript.OP_ELSE).AddInt64(144).AddOp(txscript.OP_CHECKSEQUENCEVERIFY).AddOp(txscript.OP_DROP).
+		AddData(pubKeys[1]).AddOp(txscript.OP_CHECKSIG).
+		AddOp(txscript.OP_ENDIF).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const value = 100000
+	release := EscrowRelease{
+		Inputs:       []wallet.TransactionInput{{OutpointHash: make([]byte, 32), Value: value}},
+		Outputs:      []wallet.TransactionOutput{{Address: addr, Value: value - 1000}},
+		RedeemScript: script,
+		LockTime:     1600000000,
+	}
+	if !buildsOwnReleaseTx(release) {
+		t.Fatal("expected a release with a lock time to be built without the wallet")
+	}
+	scriptHash := sha256.Sum256(script)
+	pkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+
+	tx, err := releaseTx(release, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.LockTime != release.LockTime || tx.TxIn[0].Sequence == wire.MaxTxInSequenceNum {
+		t.Error("expected the release to have a lock time which is enforced")
+	}
+	sigs := make([][]wallet.Signature, 3)
+	for i := 0; i < 2; i++ {
+		key := hd.NewExtendedKey(chaincfg.MainNetParams.HDPrivateKeyID[:], privKeys[i].Serialize(), make([]byte, 32), []byte{0, 0, 0, 0}, 0, 0, true)
+		if sigs[i], err = signReleaseTx(tx, true, release, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stack, ok := escrowSpendStack(script, [][]byte{sigs[0][0].Signature, sigs[1][0].Signature, nil})
+	if !ok {
+		t.Fatal("expected the buyer's and vendor's signatures to spend the escrow")
+	}
+	if _, ok := escrowSpendStack(script, [][]byte{sigs[0][0].Signature, nil, nil}); ok {
+		t.Error("expected one signature not to spend the escrow")
+	}
+	if err := finishEscrowSpend(tx.TxIn[0], stack, script, true); err != nil {
+		t.Fatal(err)
+	}
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(tx), value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected the release to be valid, got %s", err)
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+// AcceptsPaymentChannel returns true if orders in coin can be paid through
+// our payment channel daemon
+func (n *OpenBazaarNode) AcceptsPaymentChannel(coin string) bool {
+	if n.PaymentChannel == nil {
+		return false
+	}
+	wal, err := n.Multiwallet.WalletForCurrencyCode(coin)
+	if err != nil {
+		return false
+	}
+	channelWal, err := n.Multiwallet.WalletForCurrencyCode(n.PaymentChannel.Coin())
+	return err == nil && wal == channelWal
+}
+
+// PayOrderInvoice pays the vendor's invoice for a payment channel purchase.
+// The transaction listener marks the order funded once it is paid.
+func (n *OpenBazaarNode) PayOrderInvoice(orderID string) error {
+	contract, state, funded, _, _, _, err := n.Datastore.Purchases().GetByOrderId(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+	if contract.BuyerOrder.Payment.Method != pb.Order_Payment_PAYMENT_CHANNEL || contract.VendorOrderConfirmation == nil {
+		return ErrNotPaymentChannelOrder
+	}
+	if funded || state != pb.OrderState_AWAITING_PAYMENT {
+		return errors.New("order is not awaiting payment")
+	}
+	if !n.AcceptsPaymentChannel(contract.BuyerOrder.Payment.Coin) {
+		return ErrPaymentChannelUnavailable
+	}
+	return n.PaymentChannel.PayInvoice(orderID, contract.VendorOrderConfirmation.PaymentInvoice, int64(contract.BuyerOrder.Payment.Amount))
+}
+
+// WatchPaymentChannelInvoices watches the invoices of our unpaid payment
+// channel sales, which were issued before the node started
+func (n *OpenBazaarNode) WatchPaymentChannelInvoices() {
+	if n.PaymentChannel == nil {
+		return
+	}
+	sales, _, err := n.Datastore.Sales().GetAll([]pb.OrderState{pb.OrderState_AWAITING_PAYMENT}, "", false, false, -1, nil)
+	if err != nil {
+		log.Errorf("loading unpaid sales: %s", err)
+		return
+	}
+	for _, sale := range sales {
+		contract, _, funded, _, _, _, err := n.Datastore.Sales().GetByOrderId(sale.OrderId)
+		if err != nil || funded {
+			continue
+		}
+		if contract.BuyerOrder.Payment.Method != pb.Order_Payment_PAYMENT_CHANNEL || contract.VendorOrderConfirmation == nil {
+			continue
+		}
+		if err := n.PaymentChannel.Watch(sale.OrderId, contract.VendorOrderConfirmation.PaymentInvoice); err != nil {
+			log.Warningf("watching the invoice of order %s: %s", sale.OrderId, err)
+		}
+	}
+}
+
+// DeclineExpiredInvoiceOrder declines a payment channel sale whose invoice
+// expired unpaid, so neither side is left waiting on the payment. The buyer
+// can order again to be issued a new invoice.
+func (n *OpenBazaarNode) DeclineExpiredInvoiceOrder(orderID string) error {
+	contract, state, funded, _, _, _, err := n.Datastore.Sales().GetByOrderId(orderID)
+	if err != nil {
+		return ErrOrderNotFound
+	}
+	if contract.BuyerOrder.Payment.Method != pb.Order_Payment_PAYMENT_CHANNEL {
+		return ErrNotPaymentChannelOrder
+	}
+	if funded || state != pb.OrderState_AWAITING_PAYMENT {
+		return nil
+	}
+	return n.RejectOfflineOrder(contract, nil)
+}
+
+func processPaymentChannelOrder(resp *pb.Message, n *OpenBazaarNode, contract *pb.RicardianContract) (string, string, uint64, bool, error) {
+	// Vendor responded
+	if resp.MessageType == pb.Message_ERROR {
+		return "", "", 0, false, extractErrorMessage(resp)
+	}
+	if resp.MessageType != pb.Message_ORDER_CONFIRMATION {
+		return "", "", 0, false, errors.New("vendor responded to the order with an incorrect message type")
+	}
+	if resp.Payload == nil {
+		return "", "", 0, false, errors.New("vendor responded with nil payload")
+	}
+	rc := new(pb.RicardianContract)
+	err := proto.Unmarshal(resp.Payload.Value, rc)
+	if err != nil {
+		return "", "", 0, false, errors.New("error parsing the vendor's response")
+	}
+	contract.VendorOrderConfirmation = rc.VendorOrderConfirmation
+	for _, sig := range rc.Signatures {
+		if sig.Section == pb.Signature_ORDER_CONFIRMATION {
+			contract.Signatures = append(contract.Signatures, sig)
+		}
+	}
+	err = n.ValidateOrderConfirmation(contract, false)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	orderID, err := n.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	_, err = n.PaymentChannel.CheckInvoice(orderID, contract.VendorOrderConfirmation.PaymentInvoice, int64(contract.BuyerOrder.Payment.Amount))
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	err = n.Datastore.Purchases().Put(orderID, *contract, pb.OrderState_AWAITING_PAYMENT, false)
+	if err != nil {
+		return "", "", 0, false, err
+	}
+	return orderID, contract.VendorOrderConfirmation.PaymentInvoice, contract.BuyerOrder.Payment.Amount, true, nil
+}
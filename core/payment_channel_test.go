@@ -0,0 +1,169 @@
+package core_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/test/factory"
+	"github.com/phoreproject/openbazaar-go/wallet/channel"
+)
+
+// memoryDaemon is a payment channel daemon paying its own invoices
+type memoryDaemon struct {
+	invoices map[string]*channel.Invoice
+}
+
+func newMemoryDaemon() *memoryDaemon {
+	return &memoryDaemon{invoices: make(map[string]*channel.Invoice)}
+}
+
+func (d *memoryDaemon) AddInvoice(amount int64, description string, expiry time.Duration) (*channel.Invoice, error) {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", description, len(d.invoices))))
+	invoice := &channel.Invoice{
+		PaymentHash:    hex.EncodeToString(hash[:]),
+		PaymentRequest: fmt.Sprintf("lnbcrt%dn1%x", amount, hash),
+		Amount:         amount,
+		Description:    description,
+		Created:        time.Now(),
+		Expiry:         time.Now().Add(expiry),
+	}
+	d.invoices[invoice.PaymentRequest] = invoice
+	return invoice, nil
+}
+
+func (d *memoryDaemon) LookupInvoice(paymentHash string) (*channel.Invoice, error) {
+	for _, invoice := range d.invoices {
+		if invoice.PaymentHash == paymentHash {
+			i := *invoice
+			return &i, nil
+		}
+	}
+	return nil, channel.ErrInvoiceNotFound
+}
+
+func (d *memoryDaemon) DecodeInvoice(paymentRequest string) (*channel.Invoice, error) {
+	invoice, ok := d.invoices[paymentRequest]
+	if !ok {
+		return nil, fmt.Errorf("invalid payment request")
+	}
+	i := *invoice
+	return &i, nil
+}
+
+func (d *memoryDaemon) PayInvoice(paymentRequest string) ([]byte, error) {
+	invoice, ok := d.invoices[paymentRequest]
+	if !ok || invoice.Settled {
+		return nil, fmt.Errorf("unable to pay invoice")
+	}
+	invoice.Settled = true
+	invoice.SettledAt = time.Now()
+	return []byte("preimage"), nil
+}
+
+func newPaymentChannelContract() *pb.RicardianContract {
+	contract := factory.NewContract()
+	contract.BuyerOrder.Payment = &pb.Order_Payment{
+		Method: pb.Order_Payment_PAYMENT_CHANNEL,
+		Amount: 25000,
+		Coin:   "TBTC",
+	}
+	return contract
+}
+
+func TestAcceptsPaymentChannel(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.AcceptsPaymentChannel("TBTC") {
+		t.Error("accepted payment channel payments without a daemon")
+	}
+	node.PaymentChannel = channel.NewWallet(newMemoryDaemon(), "TBTC", 0)
+	if !node.AcceptsPaymentChannel("TBTC") || !node.AcceptsPaymentChannel("btc") {
+		t.Error("expected payment channel payments in the daemon's coin to be accepted")
+	}
+	if node.AcceptsPaymentChannel("TLTC") {
+		t.Error("accepted payment channel payments in another coin")
+	}
+}
+
+func TestPaymentChannelOrderInvoice(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := newPaymentChannelContract()
+	if _, err := node.NewOrderConfirmation(contract, false, false); err != core.ErrPaymentChannelUnavailable {
+		t.Fatalf("expected ErrPaymentChannelUnavailable, got %v", err)
+	}
+
+	daemon := newMemoryDaemon()
+	node.PaymentChannel = channel.NewWallet(daemon, "TBTC", 0)
+	var callbacks []wallet.TransactionCallback
+	node.PaymentChannel.AddTransactionListener(func(cb wallet.TransactionCallback) {
+		callbacks = append(callbacks, cb)
+	})
+
+	contract, err = node.NewOrderConfirmation(contract, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderID := contract.VendorOrderConfirmation.OrderID
+	invoice, err := daemon.DecodeInvoice(contract.VendorOrderConfirmation.PaymentInvoice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Description != orderID || invoice.Amount != 25000 {
+		t.Errorf("invoice %+v is not bound to order %s", invoice, orderID)
+	}
+	if contract.VendorOrderConfirmation.PaymentAddress != "" {
+		t.Error("payment channel order was given a payment address")
+	}
+
+	// Pay the invoice as the buyer
+	if err := node.PayOrderInvoice(orderID); err != core.ErrOrderNotFound {
+		t.Errorf("expected ErrOrderNotFound, got %v", err)
+	}
+	if err := node.Datastore.Purchases().Put(orderID, *contract, pb.OrderState_AWAITING_PAYMENT, false); err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, _, err = node.Datastore.Purchases().GetByPaymentAddress(channel.InvoiceAddress(invoice.PaymentRequest))
+	if err != nil {
+		t.Errorf("purchase is not kept under its invoice: %s", err)
+	}
+	if err := node.PayOrderInvoice(orderID); err != nil {
+		t.Fatal(err)
+	}
+	if !daemon.invoices[invoice.PaymentRequest].Settled {
+		t.Error("invoice was not paid")
+	}
+	if len(callbacks) != 1 || callbacks[0].Outputs[0].OrderID != orderID || callbacks[0].Txid != invoice.PaymentHash {
+		t.Errorf("expected the payment of order %s to be reported, got %+v", orderID, callbacks)
+	}
+}
+
+func TestPayOrderInvoiceRequiresPaymentChannelOrder(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.PaymentChannel = channel.NewWallet(newMemoryDaemon(), "TBTC", 0)
+	contract := factory.NewContract()
+	orderID, err := node.CalcOrderID(contract.BuyerOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Datastore.Purchases().Put(orderID, *contract, pb.OrderState_AWAITING_PAYMENT, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.PayOrderInvoice(orderID); err != core.ErrNotPaymentChannelOrder {
+		t.Errorf("expected ErrNotPaymentChannelOrder, got %v", err)
+	}
+}
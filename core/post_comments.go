@@ -0,0 +1,277 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+var (
+	// ErrPostReferenceInvalid is returned for a reference which isn't a peer
+	// ID and a slug separated by a slash
+	ErrPostReferenceInvalid = errors.New("reference must be a peer ID and a post slug separated by a slash")
+	// ErrPostCommentNotOurs is returned for a comment on a post which isn't
+	// ours or in one of our threads
+	ErrPostCommentNotOurs = errors.New("comment does not reference one of our posts")
+	// ErrPostCommentAuthorBlocked is returned for a comment from a blocked peer
+	ErrPostCommentAuthorBlocked = errors.New("comment author is blocked")
+	// ErrPostCommentsNotFound is returned when a post has no comment index entry
+	ErrPostCommentsNotFound = errors.New("post has no comments")
+)
+
+// PostReference returns the reference a comment or repost uses for a post
+func PostReference(peerID, slug string) string {
+	return peerID + "/" + slug
+}
+
+// ParsePostReference returns the peer ID of the author and the slug of the
+// post a comment or repost references
+func ParsePostReference(reference string) (peer.ID, string, error) {
+	parts := strings.SplitN(reference, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", ErrPostReferenceInvalid
+	}
+	pid, err := peer.IDB58Decode(parts[0])
+	if err != nil {
+		return "", "", ErrPostReferenceInvalid
+	}
+	return pid, parts[1], nil
+}
+
+// SendPostCommentNotice sends one of our comments or reposts to the author
+// of the post it references
+func (n *OpenBazaarNode) SendPostCommentNotice(post *pb.SignedPost) error {
+	if post.Post.PostType != pb.Post_COMMENT && post.Post.PostType != pb.Post_REPOST {
+		return nil
+	}
+	pid, _, err := ParsePostReference(post.Post.Reference)
+	if err != nil {
+		return err
+	}
+	if pid != n.IpfsNode.Identity {
+		return n.SendPostComment(pid.Pretty(), post)
+	}
+	if _, err := n.ProcessPostComment(post); err != nil {
+		return err
+	}
+	return n.UpdateCommentIndex()
+}
+
+// ProcessPostComment verifies and saves a comment or repost of one of our
+// posts, or a reply to a comment already in one of our threads. It returns
+// nil if the same comment was saved before.
+func (n *OpenBazaarNode) ProcessPostComment(sp *pb.SignedPost) (*repo.PostComment, error) {
+	post := sp.Post
+	if post == nil || post.VendorID == nil || post.VendorID.Pubkeys == nil {
+		return nil, errors.New("comment is missing its author")
+	}
+	if post.PostType != pb.Post_COMMENT && post.PostType != pb.Post_REPOST {
+		return nil, ErrPostInvalidType
+	}
+	if err := validatePost(post); err != nil {
+		return nil, err
+	}
+	if err := verifySignature(post, post.VendorID.Pubkeys.Identity, sp.Signature, post.VendorID.PeerID); err != nil {
+		return nil, errors.New("invalid comment signature")
+	}
+	author, err := peer.IDB58Decode(post.VendorID.PeerID)
+	if err != nil {
+		return nil, err
+	}
+	if n.BanManager != nil && n.BanManager.IsBanned(author) {
+		return nil, ErrPostCommentAuthorBlocked
+	}
+	ser, err := proto.Marshal(sp)
+	if err != nil {
+		return nil, err
+	}
+	id := PostReference(post.VendorID.PeerID, post.Slug)
+	if existing, err := n.Datastore.PostComments().Get(id); err == nil && string(existing.SignedPost) == string(ser) {
+		return nil, nil
+	}
+
+	// The comment is either on one of our posts or a reply within a thread
+	var postSlug string
+	refPeer, refSlug, err := ParsePostReference(post.Reference)
+	if err != nil {
+		return nil, err
+	}
+	if refPeer == n.IpfsNode.Identity {
+		if _, err := n.GetPostFromSlug(refSlug); err != nil {
+			return nil, ErrPostCommentNotOurs
+		}
+		postSlug = refSlug
+	} else if parent, err := n.Datastore.PostComments().Get(post.Reference); err == nil {
+		postSlug = parent.PostSlug
+	} else {
+		return nil, ErrPostCommentNotOurs
+	}
+
+	timestamp := time.Now()
+	if post.Timestamp != nil {
+		if timestamp, err = ptypes.Timestamp(post.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+	comment := repo.PostComment{
+		ID:         id,
+		PostSlug:   postSlug,
+		Reference:  post.Reference,
+		AuthorID:   post.VendorID.PeerID,
+		PostType:   post.PostType.String(),
+		SignedPost: ser,
+		Timestamp:  timestamp,
+	}
+	if err := n.Datastore.PostComments().Put(comment); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ForwardPostComment passes a reply to one of our own comments on to the
+// author of the post our comment is on, so that author holds the whole thread
+func (n *OpenBazaarNode) ForwardPostComment(comment *repo.PostComment) error {
+	ours, err := n.GetPostFromSlug(comment.PostSlug)
+	if err != nil {
+		return err
+	}
+	if ours.Post.PostType != pb.Post_COMMENT {
+		return nil
+	}
+	pid, _, err := ParsePostReference(ours.Post.Reference)
+	if err != nil || pid == n.IpfsNode.Identity {
+		return nil
+	}
+	sp := new(pb.SignedPost)
+	if err := proto.Unmarshal(comment.SignedPost, sp); err != nil {
+		return err
+	}
+	return n.SendPostComment(pid.Pretty(), sp)
+}
+
+// JSON structure of each post in comments.json
+type postCommentThread struct {
+	Slug     string             `json:"slug"`
+	Count    int                `json:"count"`
+	Comments []*postCommentData `json:"comments"`
+}
+
+type postCommentData struct {
+	ID         string             `json:"id"`
+	AuthorID   string             `json:"authorId"`
+	Handle     string             `json:"handle"`
+	PostType   string             `json:"postType"`
+	Status     string             `json:"status"`
+	Reference  string             `json:"reference"`
+	Timestamp  string             `json:"timestamp"`
+	SignedPost json.RawMessage    `json:"signedPost"`
+	Replies    []*postCommentData `json:"replies"`
+}
+
+// UpdateCommentIndex writes comments.json, which holds the threaded comments
+// on each of our posts. Comments by blocked peers are left out along with
+// the replies to them.
+func (n *OpenBazaarNode) UpdateCommentIndex() error {
+	posts, err := n.getPostIndex()
+	if err != nil {
+		return err
+	}
+	self := n.IpfsNode.Identity.Pretty()
+	m := jsonpb.Marshaler{Indent: "    "}
+	index := []postCommentThread{}
+	for _, p := range posts {
+		comments, err := n.Datastore.PostComments().GetByPost(p.Slug)
+		if err != nil {
+			return err
+		}
+		thread := postCommentThread{Slug: p.Slug, Comments: []*postCommentData{}}
+		byID := make(map[string]*postCommentData)
+		for _, comment := range comments {
+			if author, err := peer.IDB58Decode(comment.AuthorID); err != nil || (n.BanManager != nil && n.BanManager.IsBanned(author)) {
+				continue
+			}
+			sp := new(pb.SignedPost)
+			if err := proto.Unmarshal(comment.SignedPost, sp); err != nil {
+				return err
+			}
+			signed, err := m.MarshalToString(sp)
+			if err != nil {
+				return err
+			}
+			data := &postCommentData{
+				ID:         comment.ID,
+				AuthorID:   comment.AuthorID,
+				Handle:     sp.Post.VendorID.Handle,
+				PostType:   comment.PostType,
+				Status:     sp.Post.Status,
+				Reference:  comment.Reference,
+				Timestamp:  comment.Timestamp.UTC().Format(time.RFC3339),
+				SignedPost: json.RawMessage(signed),
+				Replies:    []*postCommentData{},
+			}
+			// Comments are oldest first so a parent is seen before its replies
+			if comment.Reference == PostReference(self, p.Slug) {
+				thread.Comments = append(thread.Comments, data)
+			} else if parent, ok := byID[comment.Reference]; ok {
+				parent.Replies = append(parent.Replies, data)
+			} else {
+				continue
+			}
+			byID[comment.ID] = data
+			thread.Count++
+		}
+		if thread.Count > 0 {
+			index = append(index, thread)
+		}
+	}
+
+	j, err := json.MarshalIndent(index, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(n.RepoPath, "root", "comments.json"), j, os.ModePerm)
+}
+
+// GetPostComments returns the threaded comments on one of our posts from
+// comments.json
+func (n *OpenBazaarNode) GetPostComments(slug string) ([]byte, error) {
+	file, err := ioutil.ReadFile(path.Join(n.RepoPath, "root", "comments.json"))
+	if os.IsNotExist(err) {
+		return nil, ErrPostCommentsNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return FindPostComments(file, slug)
+}
+
+// FindPostComments returns the entry for a post from a comments.json index
+func FindPostComments(index []byte, slug string) ([]byte, error) {
+	var threads []json.RawMessage
+	if err := json.Unmarshal(index, &threads); err != nil {
+		return nil, err
+	}
+	for _, thread := range threads {
+		var entry struct {
+			Slug string `json:"slug"`
+		}
+		if err := json.Unmarshal(thread, &entry); err != nil {
+			return nil, err
+		}
+		if entry.Slug == slug {
+			return thread, nil
+		}
+	}
+	return nil, ErrPostCommentsNotFound
+}
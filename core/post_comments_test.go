@@ -0,0 +1,176 @@
+package core_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/OpenBazaar/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func signTestComment(t *testing.T, key libp2p.PrivKey, author peer.ID, slug, reference string) *pb.SignedPost {
+	pubkey, err := key.GetPublic().Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := &pb.Post{
+		Slug:      slug,
+		PostType:  pb.Post_COMMENT,
+		Reference: reference,
+		Status:    "Nice post",
+		Timestamp: ptypes.TimestampNow(),
+		VendorID: &pb.ID{
+			PeerID:  author.Pretty(),
+			Pubkeys: &pb.ID_Pubkeys{Identity: pubkey},
+		},
+	}
+	ser, err := proto.Marshal(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := key.Sign(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &pb.SignedPost{Post: post, Signature: sig}
+}
+
+func publishTestPost(t *testing.T, node *core.OpenBazaarNode, slug string) {
+	signed, err := node.SignPost(&pb.Post{Slug: slug, Status: "Hello", Timestamp: ptypes.TimestampNow()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := (&jsonpb.Marshaler{Indent: "    "}).MarshalToString(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	postsPath := path.Join(node.RepoPath, "root", "posts")
+	if err := os.MkdirAll(postsPath, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(postsPath, slug+".json"), []byte(out), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.UpdatePostIndex(signed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessPostCommentThreads(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slug := "comments-" + time.Now().Format("150405.000000000")
+	publishTestPost(t, node, slug)
+	defer node.DeletePost(slug)
+
+	aliceKey, alice := newTestPeer(t)
+	bobKey, bob := newTestPeer(t)
+	comment := signTestComment(t, aliceKey, alice, "reply", core.PostReference(node.IPFSIdentityString(), slug))
+	stored, err := node.ProcessPostComment(comment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored == nil || stored.PostSlug != slug || stored.ID != core.PostReference(alice.Pretty(), "reply") {
+		t.Fatalf("unexpected stored comment: %+v", stored)
+	}
+	if stored, err := node.ProcessPostComment(comment); err != nil || stored != nil {
+		t.Error("expected a repeated comment to be ignored")
+	}
+
+	reply := signTestComment(t, bobKey, bob, "reply", core.PostReference(alice.Pretty(), "reply"))
+	if stored, err := node.ProcessPostComment(reply); err != nil || stored == nil || stored.PostSlug != slug {
+		t.Fatalf("expected reply to be stored under %s: %v", slug, err)
+	}
+
+	if err := node.UpdateCommentIndex(); err != nil {
+		t.Fatal(err)
+	}
+	thread := getTestThread(t, node, slug)
+	if thread.Count != 2 || len(thread.Comments) != 1 || len(thread.Comments[0].Replies) != 1 ||
+		thread.Comments[0].Replies[0].AuthorID != bob.Pretty() {
+		t.Fatalf("unexpected thread: %+v", thread)
+	}
+
+	// Blocking the first commenter hides their reply tree
+	node.BanManager.AddBlockedId(alice)
+	defer node.BanManager.RemoveBlockedId(alice)
+	if err := node.UpdateCommentIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.GetPostComments(slug); err != core.ErrPostCommentsNotFound {
+		t.Errorf("expected blocked comments to be left out of the index, got %v", err)
+	}
+	another := signTestComment(t, aliceKey, alice, "another", core.PostReference(node.IPFSIdentityString(), slug))
+	if _, err := node.ProcessPostComment(another); err != core.ErrPostCommentAuthorBlocked {
+		t.Errorf("expected blocked author to be rejected, got %v", err)
+	}
+}
+
+func TestProcessPostCommentRejectsInvalid(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slug := "invalid-" + time.Now().Format("150405.000000000")
+	publishTestPost(t, node, slug)
+	defer node.DeletePost(slug)
+
+	key, author := newTestPeer(t)
+	otherKey, _ := newTestPeer(t)
+
+	forged := signTestComment(t, otherKey, author, "forged", core.PostReference(node.IPFSIdentityString(), slug))
+	if _, err := node.ProcessPostComment(forged); err == nil {
+		t.Error("expected comment signed by another key to be rejected")
+	}
+
+	_, stranger := newTestPeer(t)
+	elsewhere := signTestComment(t, key, author, "elsewhere", core.PostReference(stranger.Pretty(), slug))
+	if _, err := node.ProcessPostComment(elsewhere); err != core.ErrPostCommentNotOurs {
+		t.Errorf("expected comment on another peer's post to be rejected, got %v", err)
+	}
+
+	missing := signTestComment(t, key, author, "missing", core.PostReference(node.IPFSIdentityString(), slug+"-missing"))
+	if _, err := node.ProcessPostComment(missing); err != core.ErrPostCommentNotOurs {
+		t.Errorf("expected comment on unknown post to be rejected, got %v", err)
+	}
+
+	if _, _, err := core.ParsePostReference("not-a-reference"); err != core.ErrPostReferenceInvalid {
+		t.Errorf("expected invalid reference error, got %v", err)
+	}
+}
+
+type testCommentThread struct {
+	Slug     string `json:"slug"`
+	Count    int    `json:"count"`
+	Comments []struct {
+		AuthorID string `json:"authorId"`
+		Replies  []struct {
+			AuthorID string `json:"authorId"`
+		} `json:"replies"`
+	} `json:"comments"`
+}
+
+func getTestThread(t *testing.T, node *core.OpenBazaarNode, slug string) testCommentThread {
+	raw, err := node.GetPostComments(slug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var thread testCommentThread
+	if err := json.Unmarshal(raw, &thread); err != nil {
+		t.Fatal(err)
+	}
+	return thread
+}
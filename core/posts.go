@@ -172,7 +172,7 @@ func (n *OpenBazaarNode) SignPost(post *pb.Post) (*pb.SignedPost, error) {
 	}
 	p := new(pb.ID_Pubkeys)
 	p.Identity = pubkey
-	ecPubKey, err := n.MasterPrivateKey.ECPubKey()
+	ecPubKey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return sp, err
 	}
@@ -181,15 +181,10 @@ func (n *OpenBazaarNode) SignPost(post *pb.Post) (*pb.SignedPost, error) {
 	post.VendorID = id
 
 	// Sign the GUID with the Bitcoin key
-	ecPrivKey, err := n.MasterPrivateKey.ECPrivKey()
+	id.BitcoinSig, err = n.Signer.SignBitcoin([]byte(id.PeerID))
 	if err != nil {
 		return sp, err
 	}
-	sig, err := ecPrivKey.Sign([]byte(id.PeerID))
-	if err != nil {
-		return sp, err
-	}
-	id.BitcoinSig = sig.Serialize()
 
 	// Sign post
 	serializedPost, err := proto.Marshal(post)
@@ -452,6 +447,14 @@ func (n *OpenBazaarNode) DeletePost(slug string) error {
 		return werr
 	}
 
+	// Drop the comments on the deleted post
+	if err := n.Datastore.PostComments().DeleteByPost(slug); err != nil {
+		return err
+	}
+	if err := n.UpdateCommentIndex(); err != nil {
+		return err
+	}
+
 	return n.updateProfileCounts()
 }
 
@@ -60,7 +60,7 @@ func (n *OpenBazaarNode) FetchProfile(peerID string, useCache bool) (pb.Profile,
 
 // UpdateProfile - update user profile
 func (n *OpenBazaarNode) UpdateProfile(profile *pb.Profile) error {
-	mPubkey, err := n.MasterPrivateKey.ECPubKey()
+	mPubkey, err := n.MasterPublicKey.ECPubKey()
 	if err != nil {
 		return err
 	}
@@ -84,12 +84,16 @@ func (n *OpenBazaarNode) UpdateProfile(profile *pb.Profile) error {
 			acceptedCurrencies = append(acceptedCurrencies, NormalizeCurrencyCode(ct))
 		}
 	} else {
-		for ct := range n.Multiwallet {
-			acceptedCurrencies = append(acceptedCurrencies, NormalizeCurrencyCode(ct.CurrencyCode()))
+		for _, wal := range n.Multiwallet {
+			acceptedCurrencies = append(acceptedCurrencies, NormalizeCurrencyCode(wal.CurrencyCode()))
 		}
 	}
 
 	profile.Currencies = acceptedCurrencies
+	profile.VacationMode, err = vacationModeForProfile(settingsData.VacationMode)
+	if err != nil {
+		return err
+	}
 	if profile.ModeratorInfo != nil {
 		profile.ModeratorInfo.AcceptedCurrencies = acceptedCurrencies
 	}
@@ -288,6 +292,9 @@ func ValidateProfile(profile *pb.Profile) error {
 	if len(profile.ShortDescription) > ShortDescriptionLength {
 		return fmt.Errorf("short description character length is greater than the max of %d", ShortDescriptionLength)
 	}
+	if profile.VacationMode != nil && len(profile.VacationMode.Message) > ChatMessageMaxCharacters {
+		return fmt.Errorf("vacation message character length is greater than the max of %d", ChatMessageMaxCharacters)
+	}
 	if profile.ContactInfo != nil {
 		if len(profile.ContactInfo.Website) > URLMaxCharacters {
 			return fmt.Errorf("website character length is greater than the max of %d", URLMaxCharacters)
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"errors"
+	"sync"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	"gx/ipfs/QmUadX5EcvrBmxAV9sE7wUWtWSqxns5K84qKJBixmcT1w9/go-datastore"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	routing "gx/ipfs/QmYxUdYY9S6yg5tSPVin5GFTvtfsLauVcr7reHDD3dM8xf/go-libp2p-routing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/net"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"golang.org/x/net/context"
+)
+
+// ratchetSessionLock serializes loading, advancing and saving sessions so
+// concurrent chat messages don't reuse a message key
+var ratchetSessionLock sync.Mutex
+
+var errRatchetNotSupported = errors.New("peer does not support ratchet sessions")
+
+// RatchetSupported returns true if this node can take part in ratcheted
+// chat sessions. They require an ed25519 identity key.
+func (n *OpenBazaarNode) RatchetSupported() bool {
+	_, ok := n.IpfsNode.PrivateKey.(*libp2p.Ed25519PrivateKey)
+	return ok
+}
+
+// UpdateRatchetCapability records whether a peer can receive ratcheted chat
+// messages from the flag on the peer's chat messages. A peer which stops
+// setting it has gone back to a version without sessions so its session is
+// dropped.
+func (n *OpenBazaarNode) UpdateRatchetCapability(peerID string, capable bool) error {
+	if capable && n.RatchetSupported() {
+		return n.Datastore.RatchetSessions().MarkCapable(peerID)
+	}
+	return n.Datastore.RatchetSessions().Delete(peerID)
+}
+
+// EncryptRatchetChat encrypts a chat message with the session with a peer,
+// starting a session if there is none. It returns errRatchetNotSupported if
+// the message must be sent with the identity key encryption instead.
+func (n *OpenBazaarNode) EncryptRatchetChat(p peer.ID, chat *pb.Chat) (*pb.RatchetMessage, error) {
+	if !n.RatchetSupported() {
+		return nil, errRatchetNotSupported
+	}
+	ratchetSessionLock.Lock()
+	defer ratchetSessionLock.Unlock()
+
+	state, capable, err := n.Datastore.RatchetSessions().Get(p.Pretty())
+	if err != nil {
+		return nil, err
+	}
+	if !capable {
+		return nil, errRatchetNotSupported
+	}
+	var session *net.RatchetSession
+	if state != nil {
+		session, err = net.UnmarshalRatchetSession(state)
+	} else {
+		session, err = n.newRatchetInitiator(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ser, err := proto.Marshal(chat)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := session.Encrypt(ser)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.saveRatchetSession(p, session); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DecryptRatchetChat decrypts a chat message sent with a ratchet session
+func (n *OpenBazaarNode) DecryptRatchetChat(p peer.ID, msg *pb.RatchetMessage) (*pb.Chat, error) {
+	if !n.RatchetSupported() {
+		return nil, errRatchetNotSupported
+	}
+	ratchetSessionLock.Lock()
+	defer ratchetSessionLock.Unlock()
+
+	state, _, err := n.Datastore.RatchetSessions().Get(p.Pretty())
+	if err != nil {
+		return nil, err
+	}
+	var existing *net.RatchetSession
+	if state != nil {
+		existing, err = net.UnmarshalRatchetSession(state)
+		if err != nil {
+			return nil, err
+		}
+		if plaintext, err := existing.Decrypt(msg); err == nil {
+			if err := n.saveRatchetSession(p, existing); err != nil {
+				return nil, err
+			}
+			return unmarshalRatchetChat(plaintext)
+		}
+	}
+	if msg.Header == nil || !msg.Header.Init {
+		return nil, net.ErrRatchetDecryption
+	}
+
+	// The peer started a new session with our identity key. One we already
+	// replaced the session with is a replay, while one we only read is
+	// still read but never replaces the session.
+	replaced, seen := false, false
+	if existing != nil {
+		replaced, seen = existing.InitSeen(msg.Header.RatchetKey)
+	}
+	if replaced {
+		return nil, net.ErrRatchetReplayedInit
+	}
+	session, err := n.newRatchetResponder(p)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := session.Decrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	if seen {
+		return unmarshalRatchetChat(plaintext)
+	}
+
+	// If both sides started a session before hearing from each other the
+	// session started by the lower peer ID is kept. The other session is
+	// still used to read the messages sent with it.
+	if existing == nil || existing.Confirmed || n.IpfsNode.Identity.Pretty() > p.Pretty() {
+		if existing != nil {
+			session.Inits = existing.Inits
+		}
+		session.RecordInit(msg.Header.RatchetKey, true)
+		if err := n.saveRatchetSession(p, session); err != nil {
+			return nil, err
+		}
+	} else {
+		existing.RecordInit(msg.Header.RatchetKey, false)
+		if err := n.saveRatchetSession(p, existing); err != nil {
+			return nil, err
+		}
+	}
+	return unmarshalRatchetChat(plaintext)
+}
+
+// ratchetPeerKey returns the identity key of a peer. Peer IDs don't embed
+// the key so it is taken from the peerstore or the key cache filled by
+// offline messages, falling back to a lookup like EncryptMessage.
+func (n *OpenBazaarNode) ratchetPeerKey(p peer.ID) (libp2p.PubKey, error) {
+	pubKey, err := p.ExtractPublicKey()
+	if err != nil || pubKey == nil {
+		pubKey = n.IpfsNode.Peerstore.PubKey(p)
+	}
+	if pubKey == nil {
+		keyval, err := n.IpfsNode.Repo.Datastore().Get(datastore.NewKey(KeyCachePrefix + p.Pretty()))
+		if err == nil {
+			pubKey, err = libp2p.UnmarshalPublicKey(keyval)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if pubKey == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), n.OfflineMessageFailoverTimeout)
+		defer cancel()
+		pubKey, err = routing.GetPublicKey(n.IpfsNode.Routing, ctx, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !p.MatchesPublicKey(pubKey) {
+		return nil, errors.New("peer public key and id do not match")
+	}
+	if _, ok := pubKey.(*libp2p.Ed25519PublicKey); !ok {
+		return nil, errRatchetNotSupported
+	}
+	return pubKey, nil
+}
+
+func (n *OpenBazaarNode) newRatchetInitiator(p peer.ID) (*net.RatchetSession, error) {
+	pubKey, err := n.ratchetPeerKey(p)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := net.RatchetSharedSecret(n.IpfsNode.PrivateKey, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	remoteKey, err := net.RatchetIdentityKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return net.NewRatchetInitiator(secret, remoteKey)
+}
+
+func (n *OpenBazaarNode) newRatchetResponder(p peer.ID) (*net.RatchetSession, error) {
+	pubKey, err := n.ratchetPeerKey(p)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := net.RatchetSharedSecret(n.IpfsNode.PrivateKey, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	priv, pub, err := net.RatchetIdentityKeyPair(n.IpfsNode.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return net.NewRatchetResponder(secret, priv, pub), nil
+}
+
+func (n *OpenBazaarNode) saveRatchetSession(p peer.ID, session *net.RatchetSession) error {
+	ser, err := session.Marshal()
+	if err != nil {
+		return err
+	}
+	return n.Datastore.RatchetSessions().Put(p.Pretty(), ser)
+}
+
+func unmarshalRatchetChat(plaintext []byte) (*pb.Chat, error) {
+	chat := new(pb.Chat)
+	if err := proto.Unmarshal(plaintext, chat); err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
@@ -0,0 +1,135 @@
+package core_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/net"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func TestOpenBazaarNode_RatchetChat(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodePriv, nodePub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.IpfsNode.PrivateKey = nodePriv
+	node.IpfsNode.Identity, err = peer.IDFromPublicKey(nodePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remotePriv, remotePub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteID, err := peer.IDFromPublicKey(remotePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node.IpfsNode.Peerstore.AddPubKey(remoteID, remotePub)
+
+	chat := &pb.Chat{MessageId: "1", Message: "hello", Flag: pb.Chat_MESSAGE}
+	if _, err := node.EncryptRatchetChat(remoteID, chat); err == nil {
+		t.Fatal("expected a peer which never advertised sessions to get the legacy encryption")
+	}
+	if err := node.UpdateRatchetCapability(remoteID.Pretty(), true); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := node.EncryptRatchetChat(remoteID, chat)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The remote peer answers the first message from its identity key alone
+	secret, err := net.RatchetSharedSecret(remotePriv, nodePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub, err := net.RatchetIdentityKeyPair(remotePriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := net.NewRatchetResponder(secret, priv, pub)
+	plaintext, err := remote.Decrypt(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := new(pb.Chat)
+	if err := proto.Unmarshal(plaintext, received); err != nil {
+		t.Fatal(err)
+	}
+	if received.Message != "hello" {
+		t.Errorf("expected %q, got %q", "hello", received.Message)
+	}
+
+	ser, err := proto.Marshal(&pb.Chat{MessageId: "2", Message: "hi", Flag: pb.Chat_MESSAGE})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := remote.Encrypt(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := node.DecryptRatchetChat(remoteID, reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.Message != "hi" {
+		t.Errorf("expected %q, got %q", "hi", decrypted.Message)
+	}
+	if _, err := node.DecryptRatchetChat(remoteID, reply); err == nil {
+		t.Error("expected a replayed message to fail")
+	}
+
+	// A peer which lost its session starts a new one, but replaying the
+	// start of an earlier session doesn't replace the newer one
+	nodeKey, err := net.RatchetIdentityKey(nodePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inits []*pb.RatchetMessage
+	for i := 0; i < 2; i++ {
+		restarted, err := net.NewRatchetInitiator(secret, nodeKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		init, err := restarted.Encrypt(ser)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := node.DecryptRatchetChat(remoteID, init); err != nil {
+			t.Fatal(err)
+		}
+		inits = append(inits, init)
+		remote = restarted
+	}
+	if _, err := node.DecryptRatchetChat(remoteID, inits[0]); err != net.ErrRatchetReplayedInit {
+		t.Errorf("expected a replayed session start to be rejected, got %v", err)
+	}
+	next, err := remote.Encrypt(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.DecryptRatchetChat(remoteID, next); err != nil {
+		t.Errorf("expected the newest session to be kept, got %s", err)
+	}
+
+	// A peer which downgrades loses its session
+	if err := node.UpdateRatchetCapability(remoteID.Pretty(), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.EncryptRatchetChat(remoteID, chat); err == nil {
+		t.Error("expected the legacy encryption after the peer downgraded")
+	}
+}
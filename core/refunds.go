@@ -52,25 +52,11 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 			Value:   outValue,
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
+		release, err := n.NewEscrowRelease(contract, SigningActionRefund, ins, []wallet.TransactionOutput{output}, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return err
 		}
-		mPrivKey := n.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return err
-		}
-		vendorKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return err
-		}
-
-		signatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, vendorKey, redeemScript, contract.BuyerOrder.RefundFee)
+		signatures, err := n.SignEscrowRelease(wal, release)
 		if err != nil {
 			return err
 		}
@@ -91,12 +77,34 @@ func (n *OpenBazaarNode) RefundOrder(contract *pb.RicardianContract, records []*
 		if err != nil {
 			return err
 		}
-		txid, err := wal.Spend(outValue, refundAddr, wallet.NORMAL, orderID, false)
-		if err != nil {
-			return err
+		spend := &SpendRequest{
+			Address: contract.BuyerOrder.RefundAddress,
+			Amount:  outValue,
+			OrderID: orderID,
+			Wallet:  wal.CurrencyCode(),
+			action:  SigningActionRefund,
+		}
+		var txid string
+		switch {
+		case n.ExternalSigning:
+			if txid, err = n.exportedSpendTxid(wal, spend, contract); err != nil {
+				return err
+			}
+		case n.walletsSignSpends():
+			hash, err := wal.Spend(outValue, refundAddr, wallet.NORMAL, orderID, false)
+			if err != nil {
+				return err
+			}
+			txid = hash.String()
+		default:
+			sent, err := n.spendWithCoinControl(wal, spend, wallet.NORMAL, contract)
+			if err != nil {
+				return err
+			}
+			txid = sent.Txid
 		}
 		txinfo := new(pb.Refund_TransactionInfo)
-		txinfo.Txid = txid.String()
+		txinfo.Txid = txid
 		txinfo.Value = uint64(outValue)
 		refundMsg.RefundTransaction = txinfo
 	}
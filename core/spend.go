@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcutil"
 	"github.com/phoreproject/openbazaar-go/pb"
 	"github.com/phoreproject/openbazaar-go/repo"
@@ -16,27 +17,50 @@ import (
 // defined otherwise
 const DefaultCurrencyDivisibility uint32 = 1e8
 
+// SpendRequest describes a payment out of one of the node's wallets. Setting
+// any of Outputs, Utxos, ChangeAddress, FeePerByte or DryRun builds the
+// transaction from the UTXOs in our datastore rather than leaving the
+// wallet to choose the inputs. Nodes with external signing, or whose keys
+// are held by a signing daemon, always do so.
 type SpendRequest struct {
 	decodedAddress btcutil.Address
 
-	Address                string `json:"address"`
-	Amount                 int64  `json:"amount"`
-	FeeLevel               string `json:"feeLevel"`
-	Memo                   string `json:"memo"`
-	OrderID                string `json:"orderId"`
-	RequireAssociatedOrder bool   `json:"requireOrder"`
-	Wallet                 string `json:"wallet"`
-	SpendAll               bool   `json:"spendAll"`
+	// action is the action recorded with an exported spend made for an order
+	action string
+
+	Address                string        `json:"address"`
+	Amount                 int64         `json:"amount"`
+	FeeLevel               string        `json:"feeLevel"`
+	Memo                   string        `json:"memo"`
+	OrderID                string        `json:"orderId"`
+	RequireAssociatedOrder bool          `json:"requireOrder"`
+	Wallet                 string        `json:"wallet"`
+	SpendAll               bool          `json:"spendAll"`
+	Outputs                []SpendOutput `json:"outputs"`
+	Utxos                  []string      `json:"utxos"`
+	ChangeAddress          string        `json:"changeAddress"`
+	FeePerByte             uint64        `json:"feePerByte"`
+	DryRun                 bool          `json:"dryRun"`
 }
 
 type SpendResponse struct {
-	Amount             int64     `json:"amount"`
-	ConfirmedBalance   int64     `json:"confirmedBalance"`
-	Memo               string    `json:"memo"`
-	OrderID            string    `json:"orderId"`
-	Timestamp          time.Time `json:"timestamp"`
-	Txid               string    `json:"txid"`
-	UnconfirmedBalance int64     `json:"unconfirmedBalance"`
+	Amount             int64         `json:"amount"`
+	ConfirmedBalance   int64         `json:"confirmedBalance"`
+	Memo               string        `json:"memo"`
+	OrderID            string        `json:"orderId"`
+	Timestamp          time.Time     `json:"timestamp"`
+	Txid               string        `json:"txid"`
+	UnconfirmedBalance int64         `json:"unconfirmedBalance"`
+	Fee                int64         `json:"fee,omitempty"`
+	FeePerByte         uint64        `json:"feePerByte,omitempty"`
+	Inputs             []string      `json:"inputs,omitempty"`
+	Outputs            []SpendOutput `json:"outputs,omitempty"`
+	ChangeAddress      string        `json:"changeAddress,omitempty"`
+	ChangeAmount       int64         `json:"changeAmount,omitempty"`
+	DryRun             bool          `json:"dryRun,omitempty"`
+	Transaction        string        `json:"transaction,omitempty"`
+	SigningRequest     string        `json:"signingRequest,omitempty"`
+	PSBT               string        `json:"psbt,omitempty"`
 }
 
 // Spend will attempt to move funds from the node to the destination address described in the
@@ -49,11 +73,13 @@ func (n *OpenBazaarNode) Spend(args *SpendRequest) (*SpendResponse, error) {
 		return nil, ErrUnknownWallet
 	}
 
-	addr, err := wal.DecodeAddress(args.Address)
-	if err != nil {
-		return nil, ErrInvalidSpendAddress
+	var addr btcutil.Address
+	if args.Address != "" || !args.usesCoinControl() {
+		if addr, err = wal.DecodeAddress(args.Address); err != nil {
+			return nil, ErrInvalidSpendAddress
+		}
+		args.decodedAddress = addr
 	}
-	args.decodedAddress = addr
 
 	contract, err := n.getOrderContractBySpendRequest(args)
 	if err != nil && args.RequireAssociatedOrder {
@@ -71,6 +97,10 @@ func (n *OpenBazaarNode) Spend(args *SpendRequest) (*SpendResponse, error) {
 		feeLevel = wallet.NORMAL
 	}
 
+	if args.usesCoinControl() || n.ExternalSigning || !n.walletsSignSpends() {
+		return n.spendWithCoinControl(wal, args, feeLevel, contract)
+	}
+
 	txid, err := wal.Spend(args.Amount, addr, feeLevel, args.OrderID, args.SpendAll)
 	if err != nil {
 		switch {
@@ -83,6 +113,16 @@ func (n *OpenBazaarNode) Spend(args *SpendRequest) (*SpendResponse, error) {
 		}
 	}
 
+	memo, err := n.putSpendMetadata(args, txid.String(), args.Address, contract)
+	if err != nil {
+		return nil, err
+	}
+	return n.spendResponse(wal, args, *txid, memo)
+}
+
+// putSpendMetadata saves the memo and order details of a spend, taking the
+// memo from the order's listing if none was given
+func (n *OpenBazaarNode) putSpendMetadata(args *SpendRequest, txid, address string, contract *pb.RicardianContract) (string, error) {
 	var (
 		thumbnail string
 		title     string
@@ -99,18 +139,21 @@ func (n *OpenBazaarNode) Spend(args *SpendRequest) (*SpendResponse, error) {
 	}
 
 	if err := n.Datastore.TxMetadata().Put(repo.Metadata{
-		Txid:       txid.String(),
-		Address:    args.Address,
+		Txid:       txid,
+		Address:    address,
 		Memo:       memo,
 		OrderID:    args.OrderID,
 		Thumbnail:  thumbnail,
 		CanBumpFee: false,
 	}); err != nil {
-		return nil, fmt.Errorf("failed persisting transaction metadata: %s", err)
+		return "", fmt.Errorf("failed persisting transaction metadata: %s", err)
 	}
+	return memo, nil
+}
 
+func (n *OpenBazaarNode) spendResponse(wal wallet.Wallet, args *SpendRequest, txid chainhash.Hash, memo string) (*SpendResponse, error) {
 	confirmed, unconfirmed := wal.Balance()
-	txn, err := wal.GetTransaction(*txid)
+	txn, err := wal.GetTransaction(txid)
 	if err != nil {
 		return nil, fmt.Errorf("failed retrieving new wallet balance: %s", err)
 	}
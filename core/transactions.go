@@ -0,0 +1,366 @@
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+// Formats of the wallet transaction export
+const (
+	TransactionExportCSV = "csv"
+	TransactionExportOFX = "ofx"
+)
+
+const ofxDateFormat = "20060102150405"
+
+// ErrUnknownExportFormat is returned when asked to export transactions in a
+// format we don't write
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// TransactionFilter selects wallet transactions. Zero fields match every
+// transaction.
+type TransactionFilter struct {
+	// Coins are the currency codes of the wallets to include
+	Coins    []string
+	From     time.Time
+	To       time.Time
+	OrderID  string
+	Label    string
+	Category string
+
+	// FiatCurrency is the currency transactions are valued in. Transactions
+	// with no rate recorded in it take the rate history's rate at their time.
+	FiatCurrency string
+}
+
+// WalletTransaction is a wallet transaction with its metadata, the user's
+// labels for it and its address, and its value in fiat when the wallet saw
+// it. The fiat fields are empty when no rate was recorded.
+type WalletTransaction struct {
+	Wallet        string    `json:"wallet"`
+	Txid          string    `json:"txid"`
+	Value         int64     `json:"value"`
+	Amount        string    `json:"amount"`
+	Address       string    `json:"address"`
+	Status        string    `json:"status"`
+	Memo          string    `json:"memo"`
+	OrderID       string    `json:"orderId"`
+	Timestamp     time.Time `json:"timestamp"`
+	Confirmations int64     `json:"confirmations"`
+	Height        int32     `json:"height"`
+	Category      string    `json:"category"`
+	Labels        []string  `json:"labels"`
+	FiatCurrency  string    `json:"fiatCurrency,omitempty"`
+	FiatRate      float64   `json:"fiatRate,omitempty"`
+	FiatValue     string    `json:"fiatValue,omitempty"`
+}
+
+// PutTransactionLabel saves the user's category and labels for a txid or an
+// address, or deletes them when both are empty
+func (n *OpenBazaarNode) PutTransactionLabel(label repo.TransactionLabel) error {
+	if label.Target == "" {
+		return errors.New("a txid or address to label is required")
+	}
+	if label.Kind != repo.TransactionLabelKindTransaction && label.Kind != repo.TransactionLabelKindAddress {
+		return fmt.Errorf("unknown label kind %q", label.Kind)
+	}
+	var labels []string
+	for _, l := range label.Labels {
+		if l = strings.TrimSpace(l); l != "" && !containsFold(labels, l) {
+			labels = append(labels, l)
+		}
+	}
+	label.Labels = labels
+	label.Category = strings.TrimSpace(label.Category)
+	if label.Category == "" && len(label.Labels) == 0 {
+		return n.Datastore.TxLabels().Delete(label.Target)
+	}
+	label.Timestamp = time.Now()
+	return n.Datastore.TxLabels().Put(label)
+}
+
+// WalletTransactions returns the transactions of our wallets selected by the
+// filter, newest first
+func (n *OpenBazaarNode) WalletTransactions(filter TransactionFilter) ([]WalletTransaction, error) {
+	var wallets []wallet.Wallet
+	if len(filter.Coins) > 0 {
+		for _, code := range filter.Coins {
+			wal, err := n.Multiwallet.WalletForCurrencyCode(code)
+			if err != nil {
+				return nil, ErrUnknownWallet
+			}
+			wallets = append(wallets, wal)
+		}
+	} else {
+		for _, wal := range n.Multiwallet {
+			wallets = append(wallets, wal)
+		}
+	}
+
+	metadata, err := n.Datastore.TxMetadata().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	labels, err := n.Datastore.TxLabels().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	fiatValues, err := n.Datastore.TxFiatValues().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	// Transactions without a recorded fiat value are valued from the rate
+	// history in the requested currency or the user's local currency
+	historyCurrency := filter.FiatCurrency
+	if historyCurrency == "" {
+		historyCurrency = "USD"
+		if settings, err := n.Datastore.Settings().Get(); err == nil && settings.LocalCurrency != nil && *settings.LocalCurrency != "" {
+			historyCurrency = *settings.LocalCurrency
+		}
+	}
+
+	var txs []WalletTransaction
+	for _, wal := range wallets {
+		transactions, err := wal.Transactions()
+		if err != nil {
+			return nil, err
+		}
+		unitsPerCoin := int64(DefaultCurrencyDivisibility)
+		if wal.ExchangeRates() != nil && wal.ExchangeRates().UnitsPerCoin() > 0 {
+			unitsPerCoin = int64(wal.ExchangeRates().UnitsPerCoin())
+		}
+		for _, t := range transactions {
+			if t.WatchOnly {
+				continue
+			}
+			tx := WalletTransaction{
+				Wallet:        wal.CurrencyCode(),
+				Txid:          t.Txid,
+				Value:         t.Value,
+				Amount:        formatUnits(t.Value, unitsPerCoin),
+				Address:       t.ToAddress,
+				Status:        string(t.Status),
+				Timestamp:     t.Timestamp,
+				Confirmations: t.Confirmations,
+				Height:        t.Height,
+			}
+			if m, ok := metadata[t.Txid]; ok {
+				if m.Address != "" {
+					tx.Address = m.Address
+				}
+				tx.Memo = m.Memo
+				tx.OrderID = m.OrderID
+			}
+			for _, target := range []string{tx.Txid, tx.Address} {
+				label, ok := labels[target]
+				if !ok || target == "" {
+					continue
+				}
+				if tx.Category == "" {
+					tx.Category = label.Category
+				}
+				for _, l := range label.Labels {
+					if !containsFold(tx.Labels, l) {
+						tx.Labels = append(tx.Labels, l)
+					}
+				}
+			}
+			if v, ok := fiatValues[t.Txid]; ok && (filter.FiatCurrency == "" || strings.EqualFold(v.Currency, filter.FiatCurrency)) {
+				tx.FiatCurrency = v.Currency
+				tx.FiatRate = v.Rate
+			} else if rate, err := n.ExchangeRateAt(tx.Wallet, historyCurrency, t.Timestamp); err == nil {
+				tx.FiatCurrency = rate.Currency
+				tx.FiatRate = rate.Rate
+			}
+			if tx.FiatRate != 0 {
+				tx.FiatValue = strconv.FormatFloat(float64(t.Value)/float64(unitsPerCoin)*tx.FiatRate, 'f', 2, 64)
+			}
+			if filter.matches(tx) {
+				txs = append(txs, tx)
+			}
+		}
+	}
+	sort.SliceStable(txs, func(i, j int) bool {
+		return txs[i].Timestamp.After(txs[j].Timestamp)
+	})
+	return txs, nil
+}
+
+func (f TransactionFilter) matches(tx WalletTransaction) bool {
+	if !f.From.IsZero() && tx.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !tx.Timestamp.Before(f.To) {
+		return false
+	}
+	if f.OrderID != "" && tx.OrderID != f.OrderID {
+		return false
+	}
+	if f.Label != "" && !containsFold(tx.Labels, f.Label) {
+		return false
+	}
+	if f.Category != "" && !strings.EqualFold(tx.Category, f.Category) {
+		return false
+	}
+	return true
+}
+
+// WriteTransactions exports transactions as CSV or as an OFX statement in
+// the currency
+func WriteTransactions(w io.Writer, format string, txs []WalletTransaction, currency string) error {
+	switch format {
+	case TransactionExportCSV:
+		return WriteTransactionsCSV(w, txs)
+	case TransactionExportOFX:
+		return WriteTransactionsOFX(w, txs, currency, time.Now())
+	default:
+		return ErrUnknownExportFormat
+	}
+}
+
+// WriteTransactionsCSV writes a row for each transaction
+func WriteTransactionsCSV(w io.Writer, txs []WalletTransaction) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"Date", "Wallet", "Txid", "Amount", "Fiat Currency", "Fiat Rate", "Fiat Value", "Category", "Labels", "Order ID", "Memo", "Address", "Status", "Confirmations"})
+	for _, tx := range txs {
+		rate := ""
+		if tx.FiatRate != 0 {
+			rate = strconv.FormatFloat(tx.FiatRate, 'f', -1, 64)
+		}
+		writer.Write([]string{
+			tx.Timestamp.UTC().Format(time.RFC3339),
+			tx.Wallet,
+			tx.Txid,
+			tx.Amount,
+			tx.FiatCurrency,
+			rate,
+			tx.FiatValue,
+			tx.Category,
+			strings.Join(tx.Labels, ";"),
+			tx.OrderID,
+			tx.Memo,
+			tx.Address,
+			tx.Status,
+			strconv.FormatInt(tx.Confirmations, 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteTransactionsOFX writes an OFX 2 bank statement for each wallet with
+// the transactions valued in the currency, noting the coin amount and the
+// rate used. Every transaction needs a value recorded in the currency.
+func WriteTransactionsOFX(w io.Writer, txs []WalletTransaction, currency string, now time.Time) error {
+	currency = strings.ToUpper(currency)
+	byWallet := make(map[string][]WalletTransaction)
+	var wallets, missing []string
+	for _, tx := range txs {
+		if tx.FiatValue == "" || !strings.EqualFold(tx.FiatCurrency, currency) {
+			missing = append(missing, tx.Txid)
+			continue
+		}
+		if _, ok := byWallet[tx.Wallet]; !ok {
+			wallets = append(wallets, tx.Wallet)
+		}
+		byWallet[tx.Wallet] = append(byWallet[tx.Wallet], tx)
+	}
+	if len(missing) > 0 {
+		return NewErrMissingFiatValue(currency, missing)
+	}
+	sort.Strings(wallets)
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	b.WriteString(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	b.WriteString("<OFX>\n")
+	b.WriteString("<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(&b, "<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE></SONRS></SIGNONMSGSRSV1>\n", now.UTC().Format(ofxDateFormat))
+	b.WriteString("<BANKMSGSRSV1>\n")
+	for i, code := range wallets {
+		statement := byWallet[code]
+		start, end := statement[0].Timestamp, statement[0].Timestamp
+		for _, tx := range statement {
+			if tx.Timestamp.Before(start) {
+				start = tx.Timestamp
+			}
+			if tx.Timestamp.After(end) {
+				end = tx.Timestamp
+			}
+		}
+		fmt.Fprintf(&b, "<STMTTRNRS><TRNUID>%d</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n", i+1)
+		fmt.Fprintf(&b, "<STMTRS><CURDEF>%s</CURDEF>\n", ofxEscape(currency))
+		fmt.Fprintf(&b, "<BANKACCTFROM><BANKID>OpenBazaar</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n", ofxEscape(code))
+		fmt.Fprintf(&b, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", start.UTC().Format(ofxDateFormat), end.UTC().Format(ofxDateFormat))
+		for _, tx := range statement {
+			trnType := "CREDIT"
+			if tx.Value < 0 {
+				trnType = "DEBIT"
+			}
+			name := tx.Category
+			if name == "" {
+				name = code + " transaction"
+			}
+			memo := strings.TrimSpace(strings.Join(append([]string{tx.Memo}, tx.Labels...), " "))
+			if tx.OrderID != "" {
+				memo = strings.TrimSpace(memo + " order " + tx.OrderID)
+			}
+			fmt.Fprintf(&b, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>%s</NAME>",
+				trnType, tx.Timestamp.UTC().Format(ofxDateFormat), tx.FiatValue, ofxEscape(tx.Txid), ofxEscape(truncate(name, 32)))
+			if memo != "" {
+				fmt.Fprintf(&b, "<MEMO>%s</MEMO>", ofxEscape(truncate(memo, 255)))
+			}
+			fmt.Fprintf(&b, "<ORIGCURRENCY><CURRATE>%s</CURRATE><CURSYM>%s</CURSYM></ORIGCURRENCY></STMTTRN>\n",
+				strconv.FormatFloat(tx.FiatRate, 'f', -1, 64), ofxEscape(code))
+		}
+		b.WriteString("</BANKTRANLIST></STMTRS></STMTTRNRS>\n")
+	}
+	b.WriteString("</BANKMSGSRSV1>\n</OFX>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatUnits writes an amount in a coin's smallest units as a decimal
+// number of coins
+func formatUnits(value, unitsPerCoin int64) string {
+	digits := len(strconv.FormatInt(unitsPerCoin, 10)) - 1
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	if digits <= 0 {
+		return sign + strconv.FormatInt(value, 10)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, value/unitsPerCoin, digits, value%unitsPerCoin)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func ofxEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
@@ -0,0 +1,144 @@
+package core_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+// historyWallet is a wallet with a fixed transaction history
+type historyWallet struct {
+	wallet.Wallet
+	txns []wallet.Txn
+}
+
+func (w *historyWallet) CurrencyCode() string                { return "HST" }
+func (w *historyWallet) ExchangeRates() wallet.ExchangeRates { return nil }
+func (w *historyWallet) Transactions() ([]wallet.Txn, error) { return w.txns, nil }
+
+func randomTxid(t *testing.T) string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func TestWalletTransactionsExport(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc, err := node.Multiwallet.WalletForCurrencyCode("BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2018, 3, 14, 12, 0, 0, 0, time.UTC)
+	sale, refund, unlabelled := randomTxid(t), randomTxid(t), randomTxid(t)
+	address := "addr-" + randomTxid(t)[:16]
+	wal := &historyWallet{Wallet: btc, txns: []wallet.Txn{
+		{Txid: sale, Value: 150000000, Timestamp: day, Status: wallet.StatusConfirmed, Confirmations: 6},
+		{Txid: refund, Value: -2500, Timestamp: day.Add(24 * time.Hour), ToAddress: address, Status: wallet.StatusConfirmed, Confirmations: 5},
+		{Txid: unlabelled, Value: 1, Timestamp: day.Add(48 * time.Hour), Status: wallet.StatusUnconfirmed},
+		{Txid: randomTxid(t), Value: 7, Timestamp: day, WatchOnly: true},
+	}}
+	coinType := util.ExtCoinType(999998)
+	node.Multiwallet[coinType] = wal
+	defer delete(node.Multiwallet, coinType)
+
+	orderID := "order-" + sale[:16]
+	if err := node.Datastore.TxMetadata().Put(repo.Metadata{Txid: sale, Memo: "sale", OrderID: orderID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.PutTransactionLabel(repo.TransactionLabel{Target: sale, Kind: repo.TransactionLabelKindTransaction, Category: "Income", Labels: []string{"shop", " shop ", ""}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.PutTransactionLabel(repo.TransactionLabel{Target: address, Kind: repo.TransactionLabelKindAddress, Category: "Refunds", Labels: []string{"customer"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.PutTransactionLabel(repo.TransactionLabel{Target: address, Kind: "account"}); err == nil {
+		t.Error("expected an unknown label kind to be rejected")
+	}
+	for txid, rate := range map[string]float64{sale: 8000, refund: 9000} {
+		if err := node.Datastore.TxFiatValues().Put(repo.TransactionFiatValue{Txid: txid, Currency: "USD", Rate: rate, Timestamp: day}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	txs, err := node.WalletTransactions(core.TransactionFilter{Coins: []string{"HST"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 || txs[0].Txid != unlabelled || txs[2].Txid != sale {
+		t.Fatalf("expected the three wallet transactions newest first, got %+v", txs)
+	}
+	if txs[2].Amount != "1.50000000" || txs[2].FiatValue != "12000.00" || txs[2].Category != "Income" || len(txs[2].Labels) != 1 {
+		t.Errorf("unexpected sale %+v", txs[2])
+	}
+	if txs[1].Amount != "-0.00002500" || txs[1].FiatValue != "-0.23" || txs[1].Category != "Refunds" {
+		t.Errorf("expected the refund to take its address's labels, got %+v", txs[1])
+	}
+
+	for _, c := range []struct {
+		filter core.TransactionFilter
+		txid   string
+	}{
+		{core.TransactionFilter{Coins: []string{"HST"}, OrderID: orderID}, sale},
+		{core.TransactionFilter{Coins: []string{"HST"}, Label: "CUSTOMER"}, refund},
+		{core.TransactionFilter{Coins: []string{"HST"}, Category: "income"}, sale},
+		{core.TransactionFilter{Coins: []string{"HST"}, From: day.Add(time.Hour), To: day.Add(25 * time.Hour)}, refund},
+	} {
+		txs, err := node.WalletTransactions(c.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txs) != 1 || txs[0].Txid != c.txid {
+			t.Errorf("expected the filter %+v to select %s, got %+v", c.filter, c.txid, txs)
+		}
+	}
+	if _, err := node.WalletTransactions(core.TransactionFilter{Coins: []string{"NOPE"}}); err != core.ErrUnknownWallet {
+		t.Errorf("expected an unknown coin to be rejected, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := core.WriteTransactions(&buf, core.TransactionExportCSV, txs, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[3][2] != sale || rows[3][6] != "12000.00" || rows[3][9] != orderID || rows[1][6] != "" {
+		t.Errorf("unexpected CSV %v", rows)
+	}
+
+	buf.Reset()
+	if err := core.WriteTransactions(&buf, core.TransactionExportOFX, txs, "usd"); err == nil {
+		t.Error("expected an OFX statement with an unvalued transaction to be refused")
+	} else if missing, ok := err.(core.ErrMissingFiatValue); !ok || len(missing.Txids) != 1 || missing.Txids[0] != unlabelled {
+		t.Errorf("expected the unvalued transaction to be reported, got %v", err)
+	}
+	if err := core.WriteTransactions(&buf, core.TransactionExportOFX, txs[1:], "usd"); err != nil {
+		t.Fatal(err)
+	}
+	ofx := buf.String()
+	for _, s := range []string{"<CURDEF>USD</CURDEF>", "<ACCTID>HST</ACCTID>", "<TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20180315120000</DTPOSTED><TRNAMT>-0.23</TRNAMT><FITID>" + refund, "<TRNAMT>12000.00</TRNAMT>", "<CURRATE>8000</CURRATE><CURSYM>HST</CURSYM>"} {
+		if !strings.Contains(ofx, s) {
+			t.Errorf("expected the OFX statement to contain %s", s)
+		}
+	}
+
+	if err := core.WriteTransactions(&buf, "qif", txs, "USD"); err != core.ErrUnknownExportFormat {
+		t.Errorf("expected an unknown format to be rejected, got %v", err)
+	}
+}
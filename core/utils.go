@@ -91,6 +91,13 @@ func (n *OpenBazaarNode) BuildTransactionRecords(contract *pb.RicardianContract,
 				return paymentRecords, nil, err
 			}
 			tx.Timestamp = ts
+			if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL {
+				// The txid is the invoice's payment hash. A settled invoice
+				// is final and never confirms on chain.
+				tx.Confirmations = 1
+				payments[r.Txid] = tx
+				continue
+			}
 			ch, err := chainhash.NewHashFromStr(tx.Txid)
 			if err != nil {
 				return paymentRecords, nil, err
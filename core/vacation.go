@@ -0,0 +1,315 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	mh "gx/ipfs/QmerPMzPk1mJVowm8KgmoknWa4yCYvvugMPsgWmDNUvDLW/go-multihash"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+// vacationAutoReplyInterval is the minimum time between two automatic
+// replies sent to the same peer while the store is in vacation mode
+const vacationAutoReplyInterval = time.Hour * 24
+
+// vacationOrderPrefix keys the orders queued while on vacation among the
+// saved offline messages
+const vacationOrderPrefix = "vacation-order:"
+
+// VacationOrderQueuedCode is the error code a vendor on vacation answers an
+// online order with when it is queued rather than processed. The buyer then
+// records the order as it would one sent to an offline vendor.
+const VacationOrderQueuedCode = 1
+
+var (
+	// ErrInvalidVacationOrderPolicy is returned when the vacation order policy
+	// is neither reject nor queue
+	ErrInvalidVacationOrderPolicy = errors.New("vacation order policy must be either reject or queue")
+
+	autoReplyLock sync.Mutex
+	lastAutoReply = make(map[string]time.Time)
+)
+
+// GetVacationMode returns the store's vacation settings. A store which has
+// never been put into vacation mode returns a disabled VacationMode.
+func (n *OpenBazaarNode) GetVacationMode() repo.VacationMode {
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil || settings.VacationMode == nil {
+		return repo.VacationMode{OrderPolicy: repo.VacationOrderPolicyReject}
+	}
+	return *settings.VacationMode
+}
+
+// SetVacationMode saves the vacation settings and mirrors them into the
+// profile and listing index. The caller is responsible for republishing
+// with SeedNode.
+func (n *OpenBazaarNode) SetVacationMode(vacation repo.VacationMode) error {
+	if vacation.OrderPolicy == "" {
+		vacation.OrderPolicy = repo.VacationOrderPolicyReject
+	}
+	if vacation.OrderPolicy != repo.VacationOrderPolicyReject && vacation.OrderPolicy != repo.VacationOrderPolicyQueue {
+		return ErrInvalidVacationOrderPolicy
+	}
+	if vacation.Enabled && vacation.ReturnDate != nil && vacation.ReturnDate.Before(time.Now()) {
+		return ErrReturnDateInPast
+	}
+	if len(vacation.AutoReply) > ChatMessageMaxCharacters {
+		return fmt.Errorf("auto reply character length is greater than the max of %d", ChatMessageMaxCharacters)
+	}
+
+	settings, err := n.Datastore.Settings().Get()
+	if err != nil {
+		return err
+	}
+	settings.VacationMode = &vacation
+	if err := n.Datastore.Settings().Put(settings); err != nil {
+		return err
+	}
+
+	profile, err := n.GetProfile()
+	if err == nil {
+		if err := n.UpdateProfile(&profile); err != nil {
+			return err
+		}
+	} else if err != ErrorProfileNotFound {
+		return err
+	}
+
+	now := time.Now()
+	return n.UpdateEachListingOnIndex(func(ld *ListingData) error {
+		setListingVacationStatus(ld, &vacation, now)
+		return nil
+	})
+}
+
+// CheckVendorVacationMode returns an ErrVendorOnVacation if the vendor's
+// profile says the store is in vacation mode. A profile which can't be
+// fetched is not treated as a vacation so the order can still fall back
+// to the vendor's own checks.
+func (n *OpenBazaarNode) CheckVendorVacationMode(vendorID string) error {
+	if vendorID == n.IpfsNode.Identity.Pretty() {
+		return nil
+	}
+	profile, err := n.FetchProfile(vendorID, true)
+	if err != nil {
+		log.Warningf("unable to check vacation mode of vendor %s: %s", vendorID, err)
+		return nil
+	}
+	if IsVendorOnVacation(profile.VacationMode, time.Now()) {
+		return NewErrVendorOnVacation(vendorID, profile.VacationMode)
+	}
+	return nil
+}
+
+// IsVendorOnVacation returns true if the vacation published in a vendor's
+// profile is enabled and has not yet reached its return date
+func IsVendorOnVacation(vacation *pb.Profile_VacationMode, now time.Time) bool {
+	if vacation == nil || !vacation.Enabled {
+		return false
+	}
+	if vacation.ReturnDate == nil {
+		return true
+	}
+	returnDate, err := ptypes.Timestamp(vacation.ReturnDate)
+	if err != nil {
+		return true
+	}
+	return now.Before(returnDate)
+}
+
+// SendVacationAutoReply answers an incoming chat message with the vacation
+// auto reply. At most one reply is sent to each peer per
+// vacationAutoReplyInterval so two stores on vacation can't loop.
+func (n *OpenBazaarNode) SendVacationAutoReply(peerID, subject string) error {
+	vacation := n.GetVacationMode()
+	now := time.Now()
+	if !vacation.IsActive(now) || vacation.AutoReply == "" {
+		return nil
+	}
+
+	autoReplyLock.Lock()
+	if last, ok := lastAutoReply[peerID]; ok && now.Sub(last) < vacationAutoReplyInterval {
+		autoReplyLock.Unlock()
+		return nil
+	}
+	lastAutoReply[peerID] = now
+	autoReplyLock.Unlock()
+
+	return n.sendVacationChat(peerID, subject, vacation.AutoReply, now)
+}
+
+// sendVacationChat sends a chat message on behalf of the store while it is
+// on vacation and saves it with the conversation
+func (n *OpenBazaarNode) sendVacationChat(peerID, subject, message string, now time.Time) error {
+	ts, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256([]byte(message + subject + ptypes.TimestampString(ts)))
+	encoded, err := mh.Encode(h[:], mh.SHA2_256)
+	if err != nil {
+		return err
+	}
+	msgID, err := mh.Cast(encoded)
+	if err != nil {
+		return err
+	}
+	chat := &pb.Chat{
+		MessageId: msgID.B58String(),
+		Subject:   subject,
+		Message:   message,
+		Timestamp: ts,
+		Flag:      pb.Chat_MESSAGE,
+		AutoReply: true,
+	}
+	if err := n.SendChat(peerID, chat); err != nil {
+		return err
+	}
+	return n.Datastore.Chat().Put(chat.MessageId, peerID, subject, chat.Message, now, false, true)
+}
+
+// QueueVacationOrder saves an order received while the store is on vacation
+// with the offline messages awaiting processing. The message retriever
+// hands it back to the order handler on each run until the vacation ends.
+// It returns true the first time the order is queued.
+func (n *OpenBazaarNode) QueueVacationOrder(sender peer.ID, orderID string, msg *pb.Message) (bool, error) {
+	pubkey := n.IpfsNode.Peerstore.PubKey(sender)
+	if pubkey == nil {
+		return false, fmt.Errorf("public key of %s not found", sender.Pretty())
+	}
+	pubkeyBytes, err := libp2p.MarshalPublicKey(pubkey)
+	if err != nil {
+		return false, err
+	}
+	ser, err := proto.Marshal(&pb.Envelope{Message: msg, Pubkey: pubkeyBytes})
+	if err != nil {
+		return false, err
+	}
+	key := vacationOrderPrefix + orderID
+	if n.Datastore.OfflineMessages().Has(key) {
+		return false, n.Datastore.OfflineMessages().SetMessage(key, ser)
+	}
+	if err := n.Datastore.OfflineMessages().Put(key); err != nil {
+		return false, err
+	}
+	return true, n.Datastore.OfflineMessages().SetMessage(key, ser)
+}
+
+// NewVacationOrderQueuedResponse returns the response to an online order
+// which is queued until the vacation ends
+func NewVacationOrderQueuedResponse(orderID string) (*pb.Message, error) {
+	a, err := ptypes.MarshalAny(&pb.Error{
+		Code:         VacationOrderQueuedCode,
+		ErrorMessage: "the store is on vacation and the order was queued until the vendor returns",
+		OrderID:      orderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Message{MessageType: pb.Message_ERROR, Payload: a}, nil
+}
+
+// IsVacationOrderQueued returns true if the vendor answered an order by
+// queueing it until the end of its vacation
+func IsVacationOrderQueued(resp *pb.Message) bool {
+	if resp == nil || resp.MessageType != pb.Message_ERROR || resp.Payload == nil {
+		return false
+	}
+	e := new(pb.Error)
+	if err := ptypes.UnmarshalAny(resp.Payload, e); err != nil {
+		return false
+	}
+	return e.Code == VacationOrderQueuedCode
+}
+
+// SendVacationOrderQueued tells a buyer in the order's chat that the order
+// was queued until the vacation ends
+func (n *OpenBazaarNode) SendVacationOrderQueued(buyerID, orderID string) error {
+	return n.sendVacationChat(buyerID, orderID, vacationOrderQueuedMessage(n.GetVacationMode()), time.Now())
+}
+
+// vacationOrderQueuedMessage is the chat message telling a buyer their order
+// was queued until the vacation ends
+func vacationOrderQueuedMessage(vacation repo.VacationMode) string {
+	message := "This store is on vacation. Your order has been queued and will be processed when the vendor returns"
+	if vacation.ReturnDate != nil {
+		message += " on " + vacation.ReturnDate.UTC().Format("January 2, 2006")
+	}
+	message += "."
+	if vacation.AutoReply != "" {
+		message += "\n\n" + vacation.AutoReply
+	}
+	return message
+}
+
+// RefreshListingAvailability marks the listings of a listing index whose
+// vacation has reached its return date as available again, as the index is
+// only rewritten when the vacation settings change. An index which needs
+// no change is returned as is.
+func RefreshListingAvailability(index []byte, now time.Time) ([]byte, error) {
+	var listings []map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(index))
+	decoder.UseNumber()
+	if err := decoder.Decode(&listings); err != nil {
+		return nil, err
+	}
+	changed := false
+	for _, l := range listings {
+		if unavailable, _ := l["unavailable"].(bool); !unavailable {
+			continue
+		}
+		from, ok := l["availableFrom"].(string)
+		if !ok {
+			continue
+		}
+		availableFrom, err := time.Parse(time.RFC3339Nano, from)
+		if err != nil || now.Before(availableFrom) {
+			continue
+		}
+		l["unavailable"] = false
+		delete(l, "availableFrom")
+		changed = true
+	}
+	if !changed {
+		return index, nil
+	}
+	return json.MarshalIndent(listings, "", "    ")
+}
+
+func vacationModeForProfile(vacation *repo.VacationMode) (*pb.Profile_VacationMode, error) {
+	if vacation == nil || !vacation.Enabled {
+		return nil, nil
+	}
+	pv := &pb.Profile_VacationMode{
+		Enabled: true,
+		Message: vacation.AutoReply,
+	}
+	if vacation.ReturnDate != nil {
+		ts, err := ptypes.TimestampProto(*vacation.ReturnDate)
+		if err != nil {
+			return nil, err
+		}
+		pv.ReturnDate = ts
+	}
+	return pv, nil
+}
+
+func setListingVacationStatus(ld *ListingData, vacation *repo.VacationMode, now time.Time) {
+	ld.Unavailable = vacation.IsActive(now)
+	ld.AvailableFrom = nil
+	if ld.Unavailable && vacation.ReturnDate != nil {
+		returnDate := *vacation.ReturnDate
+		ld.AvailableFrom = &returnDate
+	}
+}
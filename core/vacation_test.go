@@ -0,0 +1,202 @@
+package core_test
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/phoreproject/openbazaar-go/core"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+func TestIsVendorOnVacation(t *testing.T) {
+	var (
+		now       = time.Now()
+		future, _ = ptypes.TimestampProto(now.Add(24 * time.Hour))
+		past, _   = ptypes.TimestampProto(now.Add(-24 * time.Hour))
+		examples  = []struct {
+			vacation *pb.Profile_VacationMode
+			expected bool
+		}{
+			{nil, false},
+			{&pb.Profile_VacationMode{Enabled: false}, false},
+			{&pb.Profile_VacationMode{Enabled: true}, true},
+			{&pb.Profile_VacationMode{Enabled: true, ReturnDate: future}, true},
+			{&pb.Profile_VacationMode{Enabled: true, ReturnDate: past}, false},
+		}
+	)
+	for i, e := range examples {
+		if actual := core.IsVendorOnVacation(e.vacation, now); actual != e.expected {
+			t.Errorf("example %d: expected %t, got %t", i, e.expected, actual)
+		}
+	}
+}
+
+func TestErrVendorOnVacationIsMachineReadable(t *testing.T) {
+	returnDate := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	ts, err := ptypes.TimestampProto(returnDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject := core.NewErrVendorOnVacation("QmVendor", &pb.Profile_VacationMode{
+		Enabled:    true,
+		ReturnDate: ts,
+		Message:    "Back soon",
+	})
+
+	var decoded map[string]string
+	if err := json.Unmarshal([]byte(subject.Error()), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["code"] != "ERR_VENDOR_ON_VACATION" {
+		t.Errorf("expected code ERR_VENDOR_ON_VACATION, got %s", decoded["code"])
+	}
+	if decoded["vendorId"] != "QmVendor" {
+		t.Errorf("expected vendorId QmVendor, got %s", decoded["vendorId"])
+	}
+	if decoded["message"] != "Back soon" {
+		t.Errorf("expected message Back soon, got %s", decoded["message"])
+	}
+	if decoded["returnDate"] != returnDate.Format(time.RFC3339) {
+		t.Errorf("expected returnDate %s, got %s", returnDate.Format(time.RFC3339), decoded["returnDate"])
+	}
+
+	// A vendor rejecting an order before its profile carries the vacation
+	// must still produce a valid error
+	if core.NewErrVendorOnVacation("QmVendor", nil).Error() == "" {
+		t.Error("expected error string for vacation without details")
+	}
+}
+
+func TestQueueVacationOrder(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, buyerPub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyerID, err := peer.IDFromPublicKey(buyerPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The test repository is shared between runs so the order ID is unique
+	orderID := "QmOrder" + buyerID.Pretty()
+	msg := &pb.Message{MessageType: pb.Message_ORDER}
+	if _, err := node.QueueVacationOrder(buyerID, orderID, msg); err == nil {
+		t.Error("expected an order from a peer without a known key to be refused")
+	}
+
+	node.IpfsNode.Peerstore.AddPubKey(buyerID, buyerPub)
+	// Queueing the same order again replaces it
+	for i := 0; i < 2; i++ {
+		queued, err := node.QueueVacationOrder(buyerID, orderID, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if queued != (i == 0) {
+			t.Errorf("expected only the first copy of the order to be newly queued, got %t for copy %d", queued, i)
+		}
+	}
+	messages, err := node.Datastore.OfflineMessages().GetMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var queued [][]byte
+	for key, ser := range messages {
+		if strings.HasSuffix(key, orderID) {
+			queued = append(queued, ser)
+		}
+	}
+	if len(queued) != 1 {
+		t.Fatalf("expected one queued order, got %d", len(queued))
+	}
+	for _, ser := range queued {
+		env := new(pb.Envelope)
+		if err := proto.Unmarshal(ser, env); err != nil {
+			t.Fatal(err)
+		}
+		if env.Message.MessageType != pb.Message_ORDER {
+			t.Errorf("expected the queued message to be an order, got %s", env.Message.MessageType)
+		}
+		pubkey, err := libp2p.UnmarshalPublicKey(env.Pubkey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pubkey.Equals(buyerPub) {
+			t.Error("expected the queued order to carry the buyer's key")
+		}
+	}
+}
+
+func TestIsVacationOrderQueued(t *testing.T) {
+	queued, err := core.NewVacationOrderQueuedResponse("QmOrder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !core.IsVacationOrderQueued(queued) {
+		t.Error("expected the response to say the order was queued")
+	}
+	a, err := ptypes.MarshalAny(&pb.Error{ErrorMessage: "rejected", OrderID: "QmOrder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resp := range []*pb.Message{
+		nil,
+		{MessageType: pb.Message_ERROR, Payload: a},
+		{MessageType: pb.Message_ORDER_CONFIRMATION},
+	} {
+		if core.IsVacationOrderQueued(resp) {
+			t.Errorf("expected %v not to say the order was queued", resp)
+		}
+	}
+}
+
+func TestRefreshListingAvailability(t *testing.T) {
+	now := time.Now()
+	index := []core.ListingData{
+		{Slug: "back", Unavailable: true},
+		{Slug: "away", Unavailable: true},
+		{Slug: "open"},
+	}
+	returned, away := now.Add(-time.Hour), now.Add(time.Hour)
+	index[0].AvailableFrom = &returned
+	index[1].AvailableFrom = &away
+	ser, err := json.Marshal(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := core.RefreshListingAvailability(ser, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listings []core.ListingData
+	if err := json.Unmarshal(refreshed, &listings); err != nil {
+		t.Fatal(err)
+	}
+	if listings[0].Unavailable || listings[0].AvailableFrom != nil {
+		t.Error("expected a listing past its return date to be available")
+	}
+	if !listings[1].Unavailable || listings[1].AvailableFrom == nil {
+		t.Error("expected a listing before its return date to stay unavailable")
+	}
+	if listings[2].Unavailable {
+		t.Error("expected an available listing to stay available")
+	}
+
+	unchanged, err := core.RefreshListingAvailability(refreshed[:0], now)
+	if err == nil {
+		t.Errorf("expected an invalid index to fail, got %s", unchanged)
+	}
+}
@@ -0,0 +1,164 @@
+package ipfs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	ipath "gx/ipfs/QmQAgv6Gaoe2tQpcabqwKXKChp2MZ7i3UXv9DqTTaxCaTR/go-path"
+	ds "gx/ipfs/QmUadX5EcvrBmxAV9sE7wUWtWSqxns5K84qKJBixmcT1w9/go-datastore"
+	ipns "gx/ipfs/QmUwMnKKjH3JwGKNVZ3TcP37W93xzqNA4ECFFiMo6sXkkc/go-ipns"
+	ipnspb "gx/ipfs/QmUwMnKKjH3JwGKNVZ3TcP37W93xzqNA4ECFFiMo6sXkkc/go-ipns/pb"
+	peer "gx/ipfs/QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h/go-libp2p-peer"
+	"gx/ipfs/QmddjPSGZb3ieihSseFeCfVRpZzcqczPNsD2DvarSwnjJB/gogo-protobuf/proto"
+	"gx/ipfs/QmfVj3x4D6Jkq9SEoi5n2NmoUomLwoeiwnYz2KQa15wRw6/base32"
+
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/namesys"
+)
+
+const (
+	// IPNSAnnounceTopicPrefix is prepended to a peer ID to form the pubsub
+	// topic the peer announces its new IPNS records on
+	IPNSAnnounceTopicPrefix = "/ipnsannounce/"
+
+	announcedRecordDbPrefix = "/ipns/announced/"
+
+	// announcementPrecedence is how long a record received by announcement
+	// is preferred over resolving through the DHT, which may still be
+	// serving the previous record
+	announcementPrecedence = 10 * time.Minute
+)
+
+// ErrStaleIPNSAnnouncement is returned for an announced record which is not
+// newer than the last one received from the peer
+var ErrStaleIPNSAnnouncement = errors.New("announced IPNS record is not newer than the cached record")
+
+// announcedRecord is an IPNS record received by announcement and when it
+// was received
+type announcedRecord struct {
+	Record   []byte    `json:"record"`
+	Received time.Time `json:"received"`
+}
+
+// IPNSAnnounceTopic returns the pubsub topic a peer announces its IPNS
+// records on
+func IPNSAnnounceTopic(p peer.ID) string {
+	return IPNSAnnounceTopicPrefix + p.Pretty()
+}
+
+// CreateIPNSRecord signs an IPNS record pointing our peer ID to the root hash
+// and saves it as our latest record, so that the following Publish keeps its
+// sequence number. The serialized record is returned for announcing.
+func CreateIPNSRecord(n *core.IpfsNode, hash string) ([]byte, error) {
+	value := []byte("/ipfs/" + hash)
+	var seq uint64
+	if b, err := n.Repo.Datastore().Get(namesys.IpnsDsKey(n.Identity)); err == nil {
+		previous := new(ipnspb.IpnsEntry)
+		if err := proto.Unmarshal(b, previous); err != nil {
+			return nil, err
+		}
+		seq = previous.GetSequence()
+		if string(previous.GetValue()) != string(value) {
+			seq++
+		}
+	} else if err != ds.ErrNotFound {
+		return nil, err
+	}
+
+	entry, err := ipns.Create(n.PrivateKey, value, seq, time.Now().Add(namesys.DefaultRecordEOL))
+	if err != nil {
+		return nil, err
+	}
+	if err := ipns.EmbedPublicKey(n.PrivateKey.GetPublic(), entry); err != nil {
+		return nil, err
+	}
+	record, err := proto.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Repo.Datastore().Put(namesys.IpnsDsKey(n.Identity), record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// ProcessIPNSAnnouncement validates an IPNS record announced by a peer and,
+// if it is newer than the last record received from them, updates the
+// cache used to resolve the peer. The path the record points to is returned.
+func ProcessIPNSAnnouncement(n *core.IpfsNode, p peer.ID, record []byte) (ipath.Path, error) {
+	entry := new(ipnspb.IpnsEntry)
+	if err := proto.Unmarshal(record, entry); err != nil {
+		return "", err
+	}
+	pk, err := ipns.ExtractPublicKey(p, entry)
+	if err != nil {
+		return "", err
+	}
+	if pk == nil {
+		return "", errors.New("announced IPNS record has no public key")
+	}
+	if err := ipns.Validate(pk, entry); err != nil {
+		return "", err
+	}
+	pth, err := ipath.ParsePath(string(entry.GetValue()))
+	if err != nil {
+		return "", err
+	}
+
+	if previous, err := getAnnouncedRecord(n.Repo.Datastore(), p); err == nil {
+		cached := new(ipnspb.IpnsEntry)
+		if err := proto.Unmarshal(previous.Record, cached); err == nil {
+			if c, err := ipns.Compare(entry, cached); err != nil || c <= 0 {
+				return "", ErrStaleIPNSAnnouncement
+			}
+		}
+	}
+
+	b, err := json.Marshal(announcedRecord{Record: record, Received: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	if err := n.Repo.Datastore().Put(announcedRecordDsKey(p), b); err != nil {
+		return "", err
+	}
+	if err := putToDatastoreCache(n.Repo.Datastore(), p, pth); err != nil {
+		return "", err
+	}
+	return pth, nil
+}
+
+func getAnnouncedRecord(datastore ds.Datastore, p peer.ID) (*announcedRecord, error) {
+	b, err := datastore.Get(announcedRecordDsKey(p))
+	if err != nil {
+		return nil, err
+	}
+	record := new(announcedRecord)
+	if err := json.Unmarshal(b, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// recentlyAnnounced returns the path from the record the peer announced, if
+// it arrived recently enough that the DHT may still be serving the previous
+// record
+func recentlyAnnounced(datastore ds.Datastore, p peer.ID) (ipath.Path, bool) {
+	announced, err := getAnnouncedRecord(datastore, p)
+	if err != nil || time.Since(announced.Received) >= announcementPrecedence {
+		return "", false
+	}
+	entry := new(ipnspb.IpnsEntry)
+	if err := proto.Unmarshal(announced.Record, entry); err != nil {
+		return "", false
+	}
+	pth, err := ipath.ParsePath(string(entry.GetValue()))
+	if err != nil {
+		return "", false
+	}
+	return pth, true
+}
+
+func announcedRecordDsKey(id peer.ID) ds.Key {
+	return ds.NewKey(announcedRecordDbPrefix + base32.RawStdEncoding.EncodeToString([]byte(id)))
+}
@@ -0,0 +1,66 @@
+package ipfs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-ipfs/core/mock"
+)
+
+func TestIPNSAnnouncement(t *testing.T) {
+	publisher, err := coremock.NewMockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	follower, err := coremock.NewMockNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		first  = "QmYVXrKrKHDC9FobgmcmshCDyWwdrfwfanNQN4oxJ9Fk3h"
+		second = "QmddjPSGZb3ieihSseFeCfVRpZzcqczPNsD2DvarSwnjJB"
+	)
+
+	record, err := CreateIPNSRecord(publisher, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pth, err := ProcessIPNSAnnouncement(follower, publisher.Identity, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pth.String() != "/ipfs/"+first {
+		t.Errorf("expected /ipfs/%s, got %s", first, pth)
+	}
+	hash, err := Resolve(follower, publisher.Identity, time.Second, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != first {
+		t.Errorf("expected announced hash %s to be resolved, got %s", first, hash)
+	}
+
+	// Replayed records and records for another peer are rejected
+	if _, err := ProcessIPNSAnnouncement(follower, publisher.Identity, record); err != ErrStaleIPNSAnnouncement {
+		t.Errorf("expected replayed record to be rejected as stale, got %v", err)
+	}
+	if _, err := ProcessIPNSAnnouncement(follower, follower.Identity, record); err == nil {
+		t.Error("expected record announced for another peer to be rejected")
+	}
+
+	// A record for a new root hash has a higher sequence number
+	record, err = CreateIPNSRecord(publisher, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessIPNSAnnouncement(follower, publisher.Identity, record); err != nil {
+		t.Fatal(err)
+	}
+	hash, err = Resolve(follower, publisher.Identity, time.Second, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != second {
+		t.Errorf("expected announced hash %s to be resolved, got %s", second, hash)
+	}
+}
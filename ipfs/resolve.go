@@ -32,8 +32,16 @@ const (
 // If the DHT query returns nothing it will finally attempt to return from cache.
 // All subsequent resolves will return from cache as the pubsub will update the cache in real time
 // as new records are published.
+//
+// When usecache is set records announced by the peer on its IPNS announcement topic are returned
+// without a lookup for a short while after they arrive.
 func Resolve(n *core.IpfsNode, p peer.ID, timeout time.Duration, quorum uint, usecache bool) (string, error) {
 	if usecache {
+		// A record the peer just announced over pubsub is newer than what
+		// the DHT is likely to return
+		if pth, ok := recentlyAnnounced(n.Repo.Datastore(), p); ok {
+			return pth.Segments()[1], nil
+		}
 		pth, err := getFromDatastore(n.Repo.Datastore(), p)
 		if err == nil {
 			// Update the cache in background
@@ -20,6 +20,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	ipfsconfig "gx/ipfs/QmUAuYuiafnJRZxDDX7MuruMNsicYNuyub5vUeAcupUBNs/go-ipfs-config"
@@ -47,6 +48,7 @@ import (
 	"github.com/phoreproject/openbazaar-go/repo/migrations"
 	"github.com/phoreproject/openbazaar-go/schema"
 	apiSchema "github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/signer"
 	"github.com/phoreproject/openbazaar-go/storage/selfhosted"
 	"github.com/phoreproject/openbazaar-go/wallet"
 	lis "github.com/phoreproject/openbazaar-go/wallet/listeners"
@@ -63,6 +65,7 @@ type Node struct {
 	cancel         context.CancelFunc
 	ipfsConfig     *ipfscore.BuildCfg
 	apiConfig      *apiSchema.APIConfig
+	masterKey      *hdkeychain.ExtendedKey
 }
 
 // NewNode create the configuration file for a new node
@@ -184,6 +187,10 @@ func NewNodeWithConfig(config *NodeConfig, password string, mnemonic string) (*N
 	if err != nil {
 		return nil, err
 	}
+	mPubKey, err := mPrivKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
 
 	// Multiwallet setup
 	multiwalletConfig := &wallet.WalletConfig{
@@ -234,7 +241,7 @@ func NewNodeWithConfig(config *NodeConfig, password string, mnemonic string) (*N
 	core.Node = &core.OpenBazaarNode{
 		BanManager:                    bm,
 		Datastore:                     sqliteDB,
-		MasterPrivateKey:              mPrivKey,
+		MasterPublicKey:               mPubKey,
 		Multiwallet:                   mw,
 		OfflineMessageFailoverTimeout: 5 * time.Second,
 		PushNodes:                     pushNodes,
@@ -252,7 +259,7 @@ func NewNodeWithConfig(config *NodeConfig, password string, mnemonic string) (*N
 	ncfg := ipfs.PrepareIPFSConfig(r, ignoredURI, config.Testnet, config.Testnet)
 	ncfg.Routing = constructMobileRouting
 
-	return &Node{OpenBazaarNode: core.Node, config: *config, ipfsConfig: ncfg, apiConfig: apiConfig}, nil
+	return &Node{OpenBazaarNode: core.Node, config: *config, ipfsConfig: ncfg, apiConfig: apiConfig, masterKey: mPrivKey}, nil
 }
 
 func constructMobileRouting(ctx context.Context, host p2phost.Host, dstore ds.Batching, validator record.Validator) (routing.IpfsRouting, error) {
@@ -320,6 +327,7 @@ func (n *Node) Start() error {
 
 	n.OpenBazaarNode.IpfsNode = nd
 	n.OpenBazaarNode.DHT = dhtRouting
+	n.OpenBazaarNode.Signer = signer.NewLocalSigner(nd.PrivateKey, n.masterKey)
 
 	// Get current directory root hash
 	ipnskey := namesys.IpnsDsKey(nd.Identity)
@@ -342,6 +350,10 @@ func (n *Node) Start() error {
 	if err != nil {
 		return err
 	}
+	fundingConfig, err := apiSchema.GetFundingConfirmationsConfig(configFile)
+	if err != nil {
+		return err
+	}
 
 	// Offline messaging storage
 	n.OpenBazaarNode.MessageStorage = selfhosted.NewSelfHostedStorage(n.OpenBazaarNode.RepoPath, n.OpenBazaarNode.IpfsNode, n.OpenBazaarNode.PushNodes, n.OpenBazaarNode.SendStore)
@@ -373,15 +385,20 @@ func (n *Node) Start() error {
 			if resyncManager == nil {
 				core.Node.WaitForMessageRetrieverCompletion()
 			}
-			TL := lis.NewTransactionListener(n.OpenBazaarNode.Multiwallet, core.Node.Datastore, core.Node.Broadcast)
-			for ct, wal := range n.OpenBazaarNode.Multiwallet {
-				WL := lis.NewWalletListener(core.Node.Datastore, core.Node.Broadcast, ct)
+			TL := lis.NewTransactionListener(n.OpenBazaarNode.Multiwallet, core.Node.Datastore, core.Node.Broadcast, fundingConfig, n.OpenBazaarNode.TestnetEnable)
+			for _, wal := range n.OpenBazaarNode.Multiwallet {
+				WL := lis.NewWalletListener(core.Node.Datastore, core.Node.Broadcast, strings.ToUpper(wal.CurrencyCode()), core.Node.ExchangeRatesFor(wal))
 				wal.AddTransactionListener(WL.OnTransactionReceived)
 				wal.AddTransactionListener(TL.OnTransactionReceived)
 			}
 			su := wallet.NewStatusUpdater(n.OpenBazaarNode.Multiwallet, n.OpenBazaarNode.Broadcast, n.OpenBazaarNode.IpfsNode.Context())
 			go su.Start()
 			go n.OpenBazaarNode.Multiwallet.Start()
+			fundingInterval := lis.FundingCheckRegularInterval
+			if n.OpenBazaarNode.TestnetEnable {
+				fundingInterval = lis.FundingCheckTestingInterval
+			}
+			TL.StartFundingMonitor(fundingInterval)
 			if resyncManager != nil {
 				go resyncManager.Start()
 				go func() {
@@ -413,10 +430,13 @@ func (n *Node) Start() error {
 
 		core.PublishLock.Unlock()
 		core.Node.UpdateFollow()
+		core.Node.UpdateCommentIndex()
 		if !core.InitalPublishComplete {
 			core.Node.SeedNode()
 		}
 		core.Node.SetUpRepublisher(republishInterval)
+		core.Node.StartFeedAggregator()
+		core.Node.StartIPNSAnnouncementListener()
 	}()
 
 	return nil
@@ -13,6 +13,10 @@ import (
 var (
 	OutOfOrderMessage = errors.New("message arrived out of order")
 	DuplicateMessage  = errors.New("duplicate message")
+
+	// DeferredMessage is returned by a handler which saved the message to
+	// be processed later, such as an order received while on vacation
+	DeferredMessage = errors.New("message deferred for later processing")
 )
 
 type NetworkService interface {
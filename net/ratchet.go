@@ -0,0 +1,403 @@
+package net
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	"io"
+
+	"github.com/phoreproject/openbazaar-go/pb"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// RatchetMaxSkip is the number of message keys a session keeps for
+	// messages which have not arrived yet. Any more are discarded.
+	RatchetMaxSkip = 1000
+
+	// Length of a ratchet public key in bytes
+	RatchetKeyBytes = 32
+)
+
+var (
+	// The ratchet message could not be decrypted with the session
+	ErrRatchetDecryption = errors.New("failed to decrypt ratchet message")
+
+	// The session has not received the key it needs to send yet
+	ErrRatchetCannotSend = errors.New("ratchet session cannot send yet")
+
+	// The ratchet message skips more messages than a session keeps keys for
+	ErrRatchetTooManySkipped = errors.New("too many skipped ratchet messages")
+
+	// The ratchet message starts a session which was already started
+	ErrRatchetReplayedInit = errors.New("replayed ratchet session start")
+
+	ratchetSessionInfo = []byte("OpenBazaar Ratchet Session")
+	ratchetRootInfo    = []byte("OpenBazaar Ratchet Root")
+	ratchetMessageInfo = []byte("OpenBazaar Ratchet Message")
+)
+
+// RatchetSession is one side of a double ratchet session with a peer.
+//
+// Both sides derive the same shared secret from their identity keys. The
+// side which sends first starts from the other side's identity key so its
+// first messages can be delivered while the other side is offline. Every
+// reply then carries a new ratchet key so once both sides have sent,
+// leaking an identity key no longer exposes earlier messages.
+type RatchetSession struct {
+	RatchetPriv []byte            `json:"ratchetPriv"`
+	RatchetPub  []byte            `json:"ratchetPub"`
+	RemoteKey   []byte            `json:"remoteKey"`
+	RootKey     []byte            `json:"rootKey"`
+	SendChain   []byte            `json:"sendChain"`
+	RecvChain   []byte            `json:"recvChain"`
+	SendN       uint32            `json:"sendN"`
+	RecvN       uint32            `json:"recvN"`
+	PrevN       uint32            `json:"prevN"`
+	Skipped     map[string][]byte `json:"skipped"`
+
+	// Confirmed is set once the session has received a message
+	Confirmed bool `json:"confirmed"`
+
+	// Inits holds the ratchet keys of the sessions the peer started with
+	// our identity key, and whether each one replaced the session or was
+	// only read because the session started by the lower peer ID was kept
+	Inits map[string]bool `json:"inits,omitempty"`
+}
+
+// RatchetSharedSecret returns the secret two peers start a session from
+func RatchetSharedSecret(privKey libp2p.PrivKey, pubKey libp2p.PubKey) ([]byte, error) {
+	priv, _, err := RatchetIdentityKeyPair(privKey)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := RatchetIdentityKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	var shared [32]byte
+	curve25519.ScalarMult(&shared, priv, pub)
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared[:], Salt, ratchetSessionInfo), secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// RatchetIdentityKeyPair returns the curve25519 form of an identity key
+func RatchetIdentityKeyPair(privKey libp2p.PrivKey) (*[32]byte, *[32]byte, error) {
+	ed25519Privkey, ok := privKey.(*libp2p.Ed25519PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("ratchet sessions require an ed25519 identity key")
+	}
+	rawBytes, err := ed25519Privkey.Raw()
+	if err != nil {
+		return nil, nil, err
+	}
+	var raw [64]byte
+	copy(raw[:], rawBytes)
+	priv := privkeyToCurve25519(raw)
+	var pub [32]byte
+	curve25519.ScalarBaseMult(&pub, priv)
+	return priv, &pub, nil
+}
+
+// RatchetIdentityKey returns the curve25519 form of a peer's identity key
+func RatchetIdentityKey(pubKey libp2p.PubKey) (*[32]byte, error) {
+	ed25519Pubkey, ok := pubKey.(*libp2p.Ed25519PublicKey)
+	if !ok {
+		return nil, errors.New("ratchet sessions require an ed25519 identity key")
+	}
+	rawBytes, err := ed25519Pubkey.Raw()
+	if err != nil {
+		return nil, err
+	}
+	var raw [32]byte
+	copy(raw[:], rawBytes)
+	return pubkeyToCurve25519(raw)
+}
+
+// NewRatchetInitiator starts a session with a peer from the peer's identity key
+func NewRatchetInitiator(secret []byte, remoteKey *[32]byte) (*RatchetSession, error) {
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	s := &RatchetSession{
+		RatchetPriv: priv[:],
+		RatchetPub:  pub[:],
+		RemoteKey:   remoteKey[:],
+		Skipped:     make(map[string][]byte),
+	}
+	s.RootKey, s.SendChain, err = ratchetRootStep(secret, ratchetDH(priv[:], remoteKey[:]))
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewRatchetResponder joins a session a peer started with our identity key
+func NewRatchetResponder(secret []byte, priv, pub *[32]byte) *RatchetSession {
+	return &RatchetSession{
+		RatchetPriv: priv[:],
+		RatchetPub:  pub[:],
+		RootKey:     secret,
+		Skipped:     make(map[string][]byte),
+		Confirmed:   true,
+	}
+}
+
+// UnmarshalRatchetSession loads a session saved with Marshal
+func UnmarshalRatchetSession(ser []byte) (*RatchetSession, error) {
+	s := new(RatchetSession)
+	if err := json.Unmarshal(ser, s); err != nil {
+		return nil, err
+	}
+	if s.Skipped == nil {
+		s.Skipped = make(map[string][]byte)
+	}
+	return s, nil
+}
+
+// Marshal serializes the session so it can be saved
+func (s *RatchetSession) Marshal() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// Encrypt seals a message with the next key of the sending chain
+func (s *RatchetSession) Encrypt(plaintext []byte) (*pb.RatchetMessage, error) {
+	if s.SendChain == nil {
+		return nil, ErrRatchetCannotSend
+	}
+	var (
+		messageKey []byte
+		err        error
+	)
+	s.SendChain, messageKey = ratchetChainStep(s.SendChain)
+	header := &pb.RatchetMessage_Header{
+		RatchetKey:          s.RatchetPub,
+		PreviousChainLength: s.PrevN,
+		MessageNumber:       s.SendN,
+		Init:                !s.Confirmed,
+	}
+	s.SendN++
+	ciphertext, err := ratchetSeal(messageKey, header, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RatchetMessage{Header: header, Ciphertext: ciphertext}, nil
+}
+
+// Decrypt opens a message from the peer. The session is only updated if
+// the message decrypts.
+func (s *RatchetSession) Decrypt(msg *pb.RatchetMessage) ([]byte, error) {
+	if msg.Header == nil || len(msg.Header.RatchetKey) != RatchetKeyBytes {
+		return nil, ErrRatchetDecryption
+	}
+	ser, err := s.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	c, err := UnmarshalRatchetSession(ser)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := c.decrypt(msg)
+	if err != nil {
+		return nil, err
+	}
+	*s = *c
+	return plaintext, nil
+}
+
+func (s *RatchetSession) decrypt(msg *pb.RatchetMessage) ([]byte, error) {
+	h := msg.Header
+	key := skippedKeyID(h.RatchetKey, h.MessageNumber)
+	if messageKey, ok := s.Skipped[key]; ok {
+		delete(s.Skipped, key)
+		return ratchetOpen(messageKey, h, msg.Ciphertext)
+	}
+
+	if s.RecvChain == nil || !bytes.Equal(h.RatchetKey, s.RemoteKey) {
+		if err := s.skipTo(h.PreviousChainLength); err != nil {
+			return nil, err
+		}
+		if err := s.dhRatchet(h.RatchetKey); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.skipTo(h.MessageNumber); err != nil {
+		return nil, err
+	}
+	var messageKey []byte
+	s.RecvChain, messageKey = ratchetChainStep(s.RecvChain)
+	s.RecvN++
+	plaintext, err := ratchetOpen(messageKey, h, msg.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	s.Confirmed = true
+	return plaintext, nil
+}
+
+// InitSeen returns whether the peer already started a session with the
+// ratchet key, and whether that session replaced this one's predecessor
+func (s *RatchetSession) InitSeen(ratchetKey []byte) (replaced, seen bool) {
+	replaced, seen = s.Inits[hex.EncodeToString(ratchetKey)]
+	return replaced, seen
+}
+
+// RecordInit remembers a session the peer started with our identity key so
+// replaying its first message can't start it again
+func (s *RatchetSession) RecordInit(ratchetKey []byte, replaced bool) {
+	if s.Inits == nil {
+		s.Inits = make(map[string]bool)
+	}
+	s.Inits[hex.EncodeToString(ratchetKey)] = replaced
+}
+
+func (s *RatchetSession) skipTo(n uint32) error {
+	if s.RecvChain == nil {
+		return nil
+	}
+	if n > s.RecvN+RatchetMaxSkip {
+		return ErrRatchetTooManySkipped
+	}
+	for s.RecvN < n {
+		var messageKey []byte
+		s.RecvChain, messageKey = ratchetChainStep(s.RecvChain)
+		s.Skipped[skippedKeyID(s.RemoteKey, s.RecvN)] = messageKey
+		s.RecvN++
+	}
+	for id := range s.Skipped {
+		if len(s.Skipped) <= RatchetMaxSkip {
+			break
+		}
+		delete(s.Skipped, id)
+	}
+	return nil
+}
+
+func (s *RatchetSession) dhRatchet(remoteKey []byte) error {
+	var err error
+	s.PrevN = s.SendN
+	s.SendN = 0
+	s.RecvN = 0
+	s.RemoteKey = remoteKey
+	s.RootKey, s.RecvChain, err = ratchetRootStep(s.RootKey, ratchetDH(s.RatchetPriv, remoteKey))
+	if err != nil {
+		return err
+	}
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.RatchetPriv = priv[:]
+	s.RatchetPub = pub[:]
+	s.RootKey, s.SendChain, err = ratchetRootStep(s.RootKey, ratchetDH(s.RatchetPriv, remoteKey))
+	return err
+}
+
+func skippedKeyID(ratchetKey []byte, n uint32) string {
+	return fmt.Sprintf("%s:%d", hex.EncodeToString(ratchetKey), n)
+}
+
+func ratchetDH(priv, pub []byte) []byte {
+	var p, q, out [32]byte
+	copy(p[:], priv)
+	copy(q[:], pub)
+	curve25519.ScalarMult(&out, &p, &q)
+	return out[:]
+}
+
+// ratchetRootStep derives the next root key and a new chain key
+func ratchetRootStep(rootKey, dhOut []byte) ([]byte, []byte, error) {
+	r := hkdf.New(sha256.New, dhOut, rootKey, ratchetRootInfo)
+	newRoot := make([]byte, 32)
+	if _, err := io.ReadFull(r, newRoot); err != nil {
+		return nil, nil, err
+	}
+	chain := make([]byte, 32)
+	if _, err := io.ReadFull(r, chain); err != nil {
+		return nil, nil, err
+	}
+	return newRoot, chain, nil
+}
+
+// ratchetChainStep returns the next chain key and the key for a message
+func ratchetChainStep(chainKey []byte) ([]byte, []byte) {
+	mac := hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{0x01})
+	messageKey := mac.Sum(nil)
+	mac = hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{0x02})
+	return mac.Sum(nil), messageKey
+}
+
+func ratchetAEAD(messageKey []byte) (cipher.AEAD, []byte, error) {
+	r := hkdf.New(sha256.New, messageKey, nil, ratchetMessageInfo)
+	aesKey := make([]byte, AESKeyBytes)
+	if _, err := io.ReadFull(r, aesKey); err != nil {
+		return nil, nil, err
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Every message key is used once so the nonce can be derived with it
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, nil, err
+	}
+	return aead, nonce, nil
+}
+
+// ratchetHeaderData is the header authenticated with the message
+func ratchetHeaderData(h *pb.RatchetMessage_Header) []byte {
+	ad := make([]byte, 0, len(h.RatchetKey)+9)
+	ad = append(ad, h.RatchetKey...)
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], h.PreviousChainLength)
+	ad = append(ad, n[:]...)
+	binary.BigEndian.PutUint32(n[:], h.MessageNumber)
+	ad = append(ad, n[:]...)
+	if h.Init {
+		return append(ad, 1)
+	}
+	return append(ad, 0)
+}
+
+func ratchetSeal(messageKey []byte, h *pb.RatchetMessage_Header, plaintext []byte) ([]byte, error) {
+	aead, nonce, err := ratchetAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, ratchetHeaderData(h)), nil
+}
+
+func ratchetOpen(messageKey []byte, h *pb.RatchetMessage_Header, ciphertext []byte) ([]byte, error) {
+	aead, nonce, err := ratchetAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, ratchetHeaderData(h))
+	if err != nil {
+		return nil, ErrRatchetDecryption
+	}
+	return plaintext, nil
+}
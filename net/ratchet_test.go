@@ -0,0 +1,145 @@
+package net
+
+import (
+	"crypto/rand"
+	libp2p "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+func newRatchetPair(t *testing.T) (*RatchetSession, *RatchetSession) {
+	alicePriv, alicePub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobPriv, bobPub, err := libp2p.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliceSecret, err := RatchetSharedSecret(alicePriv, bobPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobSecret, err := RatchetSharedSecret(bobPriv, alicePub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobKey, err := RatchetIdentityKey(bobPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := NewRatchetInitiator(aliceSecret, bobKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub, err := RatchetIdentityKeyPair(bobPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return alice, NewRatchetResponder(bobSecret, priv, pub)
+}
+
+func ratchetEncrypt(t *testing.T, s *RatchetSession, plaintext string) *pb.RatchetMessage {
+	msg, err := s.Encrypt([]byte(plaintext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func expectRatchetDecrypt(t *testing.T, s *RatchetSession, msg *pb.RatchetMessage, expected string) {
+	plaintext, err := s.Decrypt(msg)
+	if err != nil {
+		t.Fatalf("decrypting %q: %s", expected, err)
+	}
+	if string(plaintext) != expected {
+		t.Errorf("expected %q, got %q", expected, plaintext)
+	}
+}
+
+func TestRatchetSessionConversation(t *testing.T) {
+	alice, bob := newRatchetPair(t)
+
+	if _, err := bob.Encrypt([]byte("too early")); err != ErrRatchetCannotSend {
+		t.Errorf("expected the responder to wait for a message, got %v", err)
+	}
+
+	// Alice's first messages are sent before Bob is online
+	m1 := ratchetEncrypt(t, alice, "hello")
+	m2 := ratchetEncrypt(t, alice, "are you there?")
+	if !m1.Header.Init || !m2.Header.Init {
+		t.Error("expected unanswered messages to be marked as starting the session")
+	}
+	expectRatchetDecrypt(t, bob, m1, "hello")
+	expectRatchetDecrypt(t, bob, m2, "are you there?")
+
+	r1 := ratchetEncrypt(t, bob, "yes")
+	expectRatchetDecrypt(t, alice, r1, "yes")
+	if !alice.Confirmed {
+		t.Error("expected the session to be confirmed by the reply")
+	}
+
+	m3 := ratchetEncrypt(t, alice, "great")
+	if m3.Header.Init {
+		t.Error("expected messages after a reply not to be marked as starting the session")
+	}
+	if string(m3.Header.RatchetKey) == string(m1.Header.RatchetKey) {
+		t.Error("expected a new ratchet key after the reply")
+	}
+	expectRatchetDecrypt(t, bob, m3, "great")
+
+	// A replayed message must not decrypt again
+	if _, err := bob.Decrypt(m3); err == nil {
+		t.Error("expected a replayed message to fail")
+	}
+}
+
+func TestRatchetSessionOutOfOrder(t *testing.T) {
+	alice, bob := newRatchetPair(t)
+	expectRatchetDecrypt(t, bob, ratchetEncrypt(t, alice, "start"), "start")
+	expectRatchetDecrypt(t, alice, ratchetEncrypt(t, bob, "reply"), "reply")
+
+	m1 := ratchetEncrypt(t, alice, "one")
+	m2 := ratchetEncrypt(t, alice, "two")
+	r1 := ratchetEncrypt(t, bob, "crossed")
+	expectRatchetDecrypt(t, alice, r1, "crossed")
+	m3 := ratchetEncrypt(t, alice, "three")
+
+	expectRatchetDecrypt(t, bob, m3, "three")
+	expectRatchetDecrypt(t, bob, m1, "one")
+	expectRatchetDecrypt(t, bob, m2, "two")
+	if len(bob.Skipped) != 0 {
+		t.Errorf("expected skipped keys to be used up, %d left", len(bob.Skipped))
+	}
+}
+
+func TestRatchetSessionMarshal(t *testing.T) {
+	alice, bob := newRatchetPair(t)
+	expectRatchetDecrypt(t, bob, ratchetEncrypt(t, alice, "start"), "start")
+
+	ser, err := bob.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err := UnmarshalRatchetSession(ser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectRatchetDecrypt(t, alice, ratchetEncrypt(t, restored, "after restart"), "after restart")
+}
+
+func TestRatchetSessionTamperedMessage(t *testing.T) {
+	alice, bob := newRatchetPair(t)
+	msg := ratchetEncrypt(t, alice, "hello")
+	msg.Header.MessageNumber = 0
+	msg.Header.Init = false
+	if _, err := bob.Decrypt(msg); err != ErrRatchetDecryption {
+		t.Errorf("expected a tampered header to fail, got %v", err)
+	}
+	if bob.RecvChain != nil {
+		t.Error("expected a failed message to leave the session unchanged")
+	}
+	msg.Header.Init = true
+	expectRatchetDecrypt(t, bob, msg, "hello")
+}
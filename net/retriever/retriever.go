@@ -381,7 +381,10 @@ func (m *MessageRetriever) handleMessage(env pb.Envelope, addr string, id *peer.
 	// Dispatch handler
 	resp, err := handler(*id, env.Message, true)
 	if err != nil {
-		if err == net.OutOfOrderMessage {
+		if err == net.DeferredMessage {
+			// The handler saved the message itself, keep it queued
+			return err
+		} else if err == net.OutOfOrderMessage {
 			ser, err := proto.Marshal(&env)
 			if err == nil {
 				err := m.db.OfflineMessages().SetMessage(addr, ser)
@@ -412,12 +415,21 @@ var MessageProcessingOrder = []pb.Message_MessageType{
 	pb.Message_ORDER_COMPLETION,
 	pb.Message_DISPUTE_OPEN,
 	pb.Message_DISPUTE_UPDATE,
+	pb.Message_CASE_HANDOVER,
+	pb.Message_CASE_HANDOVER_RESPONSE,
+	pb.Message_CASE_HANDOVER_RESOLUTION,
+	pb.Message_DISPUTE_FALLBACK,
+	pb.Message_DISPUTE_PANEL_VOTE,
 	pb.Message_VENDOR_FINALIZED_PAYMENT,
 	pb.Message_DISPUTE_CLOSE,
 	pb.Message_REFUND,
+	pb.Message_GROUP_MEMBERSHIP,
 	pb.Message_CHAT,
+	pb.Message_RATCHET_CHAT,
+	pb.Message_CHAT_CHANGE,
 	pb.Message_FOLLOW,
 	pb.Message_UNFOLLOW,
+	pb.Message_POST_COMMENT,
 	pb.Message_MODERATOR_ADD,
 	pb.Message_MODERATOR_REMOVE,
 	pb.Message_OFFLINE_ACK,
@@ -58,6 +58,14 @@ func (service *OpenBazaarService) HandlerForMsgType(t pb.Message_MessageType) fu
 		return service.handleDisputeClose
 	case pb.Message_CHAT:
 		return service.handleChat
+	case pb.Message_RATCHET_CHAT:
+		return service.handleRatchetChat
+	case pb.Message_CHAT_CHANGE:
+		return service.handleChatChange
+	case pb.Message_GROUP_MEMBERSHIP:
+		return service.handleGroupMembership
+	case pb.Message_POST_COMMENT:
+		return service.handlePostComment
 	case pb.Message_MODERATOR_ADD:
 		return service.handleModeratorAdd
 	case pb.Message_MODERATOR_REMOVE:
@@ -66,6 +74,16 @@ func (service *OpenBazaarService) HandlerForMsgType(t pb.Message_MessageType) fu
 		return service.handleBlock
 	case pb.Message_VENDOR_FINALIZED_PAYMENT:
 		return service.handleVendorFinalizedPayment
+	case pb.Message_CASE_HANDOVER:
+		return service.handleCaseHandover
+	case pb.Message_CASE_HANDOVER_RESPONSE:
+		return service.handleCaseHandoverResponse
+	case pb.Message_CASE_HANDOVER_RESOLUTION:
+		return service.handleCaseHandoverResolution
+	case pb.Message_DISPUTE_FALLBACK:
+		return service.handleDisputeFallback
+	case pb.Message_DISPUTE_PANEL_VOTE:
+		return service.handleDisputePanelVote
 	case pb.Message_STORE:
 		return service.handleStore
 	case pb.Message_ERROR:
@@ -253,6 +271,9 @@ func (service *OpenBazaarService) handleOfflineRelay(p peer.ID, pmes *pb.Message
 
 	// Dispatch handler
 	_, err = handler(id, env.Message, true)
+	if err == net.DeferredMessage {
+		return nil, nil
+	}
 	if err != nil {
 		log.Errorf("Handle message error: %s", err)
 		return nil, err
@@ -301,6 +322,47 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		return errorResponse("the vendor turned his store off and is not accepting orders at this time"), errors.New("store is turned off")
 	}
 
+	// Orders received while the store is on vacation may be queued and
+	// processed once the vendor returns, otherwise they are rejected
+	vacation := service.node.GetVacationMode()
+	if vacation.IsActive(time.Now()) {
+		method := contract.BuyerOrder.GetPayment().GetMethod()
+		// Payment channel invoices can only be issued while the vendor is online
+		if vacation.OrderPolicy != repo.VacationOrderPolicyQueue || (method == pb.Order_Payment_PAYMENT_CHANNEL && !offline) {
+			return errorResponse(core.NewErrVendorOnVacation(service.node.IpfsNode.Identity.Pretty(), pro.VacationMode).Error()), errors.New("store is in vacation mode")
+		}
+		// A payment address can't be issued until the vendor returns. The
+		// buyer is told the order is queued and sends it offline with an
+		// address of its own, which is queued below.
+		if method == pb.Order_Payment_ADDRESS_REQUEST && !offline {
+			m, err := core.NewVacationOrderQueuedResponse(orderId)
+			if err != nil {
+				return errorResponse(err.Error()), err
+			}
+			return m, nil
+		}
+		queued, err := service.node.QueueVacationOrder(peer, orderId, pmes)
+		if err != nil {
+			return errorResponse(err.Error()), err
+		}
+		if queued {
+			go func() {
+				if err := service.node.SendVacationOrderQueued(peer.Pretty(), orderId); err != nil {
+					log.Errorf("Error telling %s order %s was queued: %s", peer.Pretty(), orderId, err)
+				}
+			}()
+		}
+		log.Debugf("Queued ORDER message from %s until the vacation ends", peer.Pretty())
+		if !offline {
+			m, err := core.NewVacationOrderQueuedResponse(orderId)
+			if err != nil {
+				return errorResponse(err.Error()), err
+			}
+			return m, nil
+		}
+		return nil, net.DeferredMessage
+	}
+
 	err = service.node.ValidateOrder(contract, !offline)
 	if err != nil && (err != core.ErrPurchaseUnknownListing || !offline) {
 		return errorResponse(err.Error()), err
@@ -339,6 +401,35 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		}
 		log.Debugf("Received addr-req ORDER message from %s", peer.Pretty())
 		return &m, nil
+	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL && !offline {
+		if !service.node.AcceptsPaymentChannel(contract.BuyerOrder.Payment.Coin) {
+			return errorResponse(core.ErrPaymentChannelUnavailable.Error()), core.ErrPaymentChannelUnavailable
+		}
+		total, rates, err := service.node.CalculateOrderTotalAndRates(contract)
+		if err != nil {
+			return errorResponse("Error calculating payment amount"), err
+		}
+		if !service.node.ValidatePaymentAmount(total, contract.BuyerOrder.Payment.Amount) {
+			return errorResponse("Calculated a different payment amount"), errors.New("calculated different payment amount")
+		}
+		contract, err = service.node.NewOrderConfirmation(contract, false, false)
+		if err != nil {
+			return errorResponse("Error building order confirmation"), err
+		}
+		a, err := ptypes.MarshalAny(contract)
+		if err != nil {
+			return errorResponse("Error building order confirmation"), err
+		}
+		if err := service.node.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_AWAITING_PAYMENT, false); err != nil {
+			return errorResponse("Error saving order"), err
+		}
+		service.node.RecordOrderRates(contract.VendorOrderConfirmation.OrderID, rates)
+		m := pb.Message{
+			MessageType: pb.Message_ORDER_CONFIRMATION,
+			Payload:     a,
+		}
+		log.Debugf("Received payment channel ORDER message from %s", peer.Pretty())
+		return &m, nil
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_DIRECT {
 		err := service.node.ValidateDirectPaymentAddress(contract.BuyerOrder)
 		if err != nil {
@@ -356,7 +447,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 		log.Debugf("Received direct ORDER message from %s", peer.Pretty())
 		return nil, nil
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_MODERATED && !offline {
-		total, err := service.node.CalculateOrderTotal(contract)
+		total, rates, err := service.node.CalculateOrderTotalAndRates(contract)
 		if err != nil {
 			return errorResponse("Error calculating payment amount"), errors.New("error calculating payment amount")
 		}
@@ -385,6 +476,7 @@ func (service *OpenBazaarService) handleOrder(peer peer.ID, pmes *pb.Message, op
 			return errorResponse("Error building order confirmation"), errors.New("error building order confirmation")
 		}
 		service.node.Datastore.Sales().Put(contract.VendorOrderConfirmation.OrderID, *contract, pb.OrderState_AWAITING_PAYMENT, false)
+		service.node.RecordOrderRates(contract.VendorOrderConfirmation.OrderID, rates)
 		if currentTime.After(purchaseTime) {
 			service.node.Datastore.Sales().SetNeedsResync(contract.VendorOrderConfirmation.OrderID, true)
 		}
@@ -560,7 +652,9 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 		return nil, err
 	}
 
-	if contract.BuyerOrder.Payment.Method != pb.Order_Payment_MODERATED {
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL {
+		// The invoice was never paid so there is nothing to sweep or refund
+	} else if contract.BuyerOrder.Payment.Method != pb.Order_Payment_MODERATED {
 		// Sweep the address into our wallet
 		var txInputs []wallet.TransactionInput
 		for _, r := range records {
@@ -584,28 +678,11 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			}
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return nil, err
-		}
-		mPrivKey := service.node.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return nil, err
-		}
-		buyerKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return nil, err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return nil, err
-		}
 		refundAddress, err := wal.DecodeAddress(contract.BuyerOrder.RefundAddress)
 		if err != nil {
 			return nil, err
 		}
-		_, err = wal.SweepAddress(txInputs, &refundAddress, buyerKey, &redeemScript, wallet.NORMAL)
+		err = service.node.SweepEscrow(wal, txInputs, refundAddress, contract)
 		if err != nil {
 			return nil, err
 		}
@@ -633,25 +710,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			Value:   outValue,
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return nil, err
-		}
-		mPrivKey := service.node.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return nil, err
-		}
-		buyerKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return nil, err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return nil, err
-		}
-
-		buyerSignatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		release, err := service.node.NewEscrowRelease(contract, core.SigningActionDecline, ins, []wallet.TransactionOutput{output}, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -660,7 +719,7 @@ func (service *OpenBazaarService) handleReject(p peer.ID, pmes *pb.Message, opti
 			sig := wallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		_, err = wal.Multisign(ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee, true)
+		err = service.node.ReleaseEscrow(wal, release, vendorSignatures)
 		if err != nil {
 			return nil, err
 		}
@@ -749,25 +808,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			Value:   outValue,
 		}
 
-		chaincode, err := hex.DecodeString(contract.BuyerOrder.Payment.Chaincode)
-		if err != nil {
-			return nil, err
-		}
-		mPrivKey := service.node.MasterPrivateKey
-		mECKey, err := mPrivKey.ECPrivKey()
-		if err != nil {
-			return nil, err
-		}
-		buyerKey, err := wal.ChildKey(mECKey.Serialize(), chaincode, true)
-		if err != nil {
-			return nil, err
-		}
-		redeemScript, err := hex.DecodeString(contract.BuyerOrder.Payment.RedeemScript)
-		if err != nil {
-			return nil, err
-		}
-
-		buyerSignatures, err := wal.CreateMultisigSignature(ins, []wallet.TransactionOutput{output}, buyerKey, redeemScript, contract.BuyerOrder.RefundFee)
+		release, err := service.node.NewEscrowRelease(contract, core.SigningActionRefund, ins, []wallet.TransactionOutput{output}, contract.BuyerOrder.RefundFee)
 		if err != nil {
 			return nil, err
 		}
@@ -776,7 +817,7 @@ func (service *OpenBazaarService) handleRefund(p peer.ID, pmes *pb.Message, opti
 			sig := wallet.Signature{InputIndex: s.InputIndex, Signature: s.Signature}
 			vendorSignatures = append(vendorSignatures, sig)
 		}
-		_, err = wal.Multisign(ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.BuyerOrder.RefundFee, true)
+		err = service.node.ReleaseEscrow(wal, release, vendorSignatures)
 		if err != nil {
 			return nil, err
 		}
@@ -964,7 +1005,7 @@ func (service *OpenBazaarService) handleOrderCompletion(p peer.ID, pmes *pb.Mess
 			buyerSignatures = append(buyerSignatures, sig)
 		}
 
-		_, err = wal.Multisign(ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte, true)
+		err = core.MultisignEscrow(wal, ins, []wallet.TransactionOutput{output}, buyerSignatures, vendorSignatures, redeemScript, contract.VendorOrderFulfillment[0].Payout.PayoutFeePerByte)
 		if err != nil {
 			return nil, err
 		}
@@ -1202,9 +1243,16 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 		return nil, err
 	}
 
+	if chat.GroupId != "" {
+		if err := service.node.ValidateGroupChat(p, chat.GroupId); err != nil {
+			return nil, err
+		}
+	}
+
 	if chat.Flag == pb.Chat_TYPING {
 		n := repo.ChatTyping{
 			PeerId:    p.Pretty(),
+			GroupId:   chat.GroupId,
 			Subject:   chat.Subject,
 			MessageId: chat.MessageId,
 		}
@@ -1212,6 +1260,10 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 		return nil, nil
 	}
 	if chat.Flag == pb.Chat_READ {
+		if chat.GroupId != "" {
+			// Read receipts aren't tracked per member of a group
+			return nil, nil
+		}
 		n := repo.ChatRead{
 			PeerId:    p.Pretty(),
 			Subject:   chat.Subject,
@@ -1225,6 +1277,14 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 		return nil, nil
 	}
 
+	if err := service.node.UpdateRatchetCapability(p.Pretty(), chat.Ratchet); err != nil {
+		log.Errorf("Error updating ratchet session capability of %s: %s", p.Pretty(), err)
+	}
+
+	if chat.Flag != pb.Chat_MESSAGE {
+		return nil, fmt.Errorf("chat %s must be sent as a CHAT_CHANGE message", chat.Flag)
+	}
+
 	// Validate
 	if len(chat.Subject) > core.ChatSubjectMaxCharacters {
 		return nil, errors.New("chat subject over max characters")
@@ -1232,6 +1292,13 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 	if len(chat.Message) > core.ChatMessageMaxCharacters {
 		return nil, errors.New("chat message over max characters")
 	}
+	var attachments []repo.ChatAttachment
+	if chat.GroupId == "" {
+		attachments, err = core.ChatAttachmentsFromMessage(chat)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Use correct timestamp
 	offline, _ := options.(bool)
@@ -1249,10 +1316,21 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 	}
 
 	// Put to database
-	err = service.datastore.Chat().Put(chat.MessageId, p.Pretty(), chat.Subject, chat.Message, t, false, false)
+	if chat.GroupId != "" {
+		err = service.datastore.Chat().PutGroupMessage(chat.MessageId, chat.GroupId, p.Pretty(), chat.Message, t, false, false)
+	} else {
+		err = service.datastore.Chat().Put(chat.MessageId, p.Pretty(), chat.Subject, chat.Message, t, false, false)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if len(attachments) > 0 {
+		err = service.datastore.Chat().PutAttachments(chat.MessageId, attachments)
+		if err != nil {
+			return nil, err
+		}
+		go service.node.FetchChatAttachments(chat.MessageId, len(attachments))
+	}
 
 	if chat.Subject != "" {
 		go func() {
@@ -1264,17 +1342,97 @@ func (service *OpenBazaarService) handleChat(p peer.ID, pmes *pb.Message, option
 
 	// Push to websocket
 	n := repo.ChatMessage{
-		MessageId: chat.MessageId,
-		PeerId:    p.Pretty(),
-		Subject:   chat.Subject,
-		Message:   chat.Message,
-		Timestamp: t,
+		MessageId:   chat.MessageId,
+		PeerId:      p.Pretty(),
+		GroupId:     chat.GroupId,
+		Subject:     chat.Subject,
+		Message:     chat.Message,
+		Timestamp:   t,
+		Attachments: attachments,
 	}
 	service.broadcast <- n
+
+	if !chat.AutoReply && chat.GroupId == "" {
+		go func() {
+			if err := service.node.SendVacationAutoReply(p.Pretty(), chat.Subject); err != nil {
+				log.Errorf("Error sending vacation auto reply to %s: %s", p.Pretty(), err)
+			}
+		}()
+	}
 	log.Debugf("Received CHAT message from %s", p.Pretty())
 	return nil, nil
 }
 
+func (service *OpenBazaarService) handleRatchetChat(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+
+	// Unmarshall
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	rm := new(pb.RatchetMessage)
+	err := ptypes.UnmarshalAny(pmes.Payload, rm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt with the session and handle it as a regular chat message
+	chat, err := service.node.DecryptRatchetChat(p, rm)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ptypes.MarshalAny(chat)
+	if err != nil {
+		return nil, err
+	}
+	m := &pb.Message{
+		MessageType: pb.Message_CHAT,
+		Payload:     a,
+	}
+	return service.handleChat(p, m, options)
+}
+
+func (service *OpenBazaarService) handleChatChange(p peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+
+	// Unmarshall, decrypting edits sent with a ratchet session
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	chat := new(pb.Chat)
+	if ptypes.Is(pmes.Payload, new(pb.RatchetMessage)) {
+		rm := new(pb.RatchetMessage)
+		if err := ptypes.UnmarshalAny(pmes.Payload, rm); err != nil {
+			return nil, err
+		}
+		var err error
+		chat, err = service.node.DecryptRatchetChat(p, rm)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := ptypes.UnmarshalAny(pmes.Payload, chat); err != nil {
+		return nil, err
+	}
+	if !core.IsChatChange(chat.Flag) {
+		return nil, errors.New("chat message is not a change")
+	}
+
+	if chat.GroupId != "" {
+		if err := service.node.ValidateGroupChat(p, chat.GroupId); err != nil {
+			return nil, err
+		}
+	}
+	if err := service.node.UpdateRatchetCapability(p.Pretty(), chat.Ratchet); err != nil {
+		log.Errorf("Error updating ratchet session capability of %s: %s", p.Pretty(), err)
+	}
+
+	change, err := service.node.ProcessChatChange(p, chat)
+	if err != nil {
+		return nil, err
+	}
+	service.broadcast <- *change
+	log.Debugf("Received CHAT_CHANGE %s from %s", chat.Flag, p.Pretty())
+	return nil, nil
+}
+
 func (service *OpenBazaarService) handleModeratorAdd(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
 	if pmes.Payload == nil {
 		return nil, errors.New("payload is nil")
@@ -1418,6 +1576,137 @@ func (service *OpenBazaarService) handleVendorFinalizedPayment(pid peer.ID, pmes
 	return nil, nil
 }
 
+func (service *OpenBazaarService) handleCaseHandover(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	sd := new(pb.SignedData)
+	if err := ptypes.UnmarshalAny(pmes.Payload, sd); err != nil {
+		return nil, err
+	}
+	if err := service.node.ProcessCaseHandover(sd); err != nil {
+		return nil, err
+	}
+	log.Debugf("Received CASE_HANDOVER message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleCaseHandoverResponse(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	sd := new(pb.SignedData)
+	if err := ptypes.UnmarshalAny(pmes.Payload, sd); err != nil {
+		return nil, err
+	}
+	if err := service.node.ProcessCaseHandoverResponse(sd); err != nil {
+		return nil, err
+	}
+	log.Debugf("Received CASE_HANDOVER_RESPONSE message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleCaseHandoverResolution(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	sd := new(pb.SignedData)
+	if err := ptypes.UnmarshalAny(pmes.Payload, sd); err != nil {
+		return nil, err
+	}
+	if err := service.node.ProcessCaseHandoverResolution(sd); err != nil {
+		return nil, err
+	}
+	log.Debugf("Received CASE_HANDOVER_RESOLUTION message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleDisputeFallback(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	payout := new(pb.DisputeFallbackPayout)
+	if err := ptypes.UnmarshalAny(pmes.Payload, payout); err != nil {
+		return nil, err
+	}
+	if err := service.node.ProcessDisputeFallbackPayout(pid.Pretty(), payout); err != nil {
+		return nil, err
+	}
+	log.Debugf("Received DISPUTE_FALLBACK message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleDisputePanelVote(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	rc := new(pb.RicardianContract)
+	if err := ptypes.UnmarshalAny(pmes.Payload, rc); err != nil {
+		return nil, err
+	}
+	if err := service.node.ProcessDisputePanelVote(pid.Pretty(), rc); err != nil {
+		return nil, err
+	}
+	log.Debugf("Received DISPUTE_PANEL_VOTE message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handleGroupMembership(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	membership := new(pb.GroupMembership)
+	if err := ptypes.UnmarshalAny(pmes.Payload, membership); err != nil {
+		return nil, err
+	}
+	update, err := service.node.ProcessGroupMembership(pid, membership)
+	if err != nil {
+		return nil, err
+	}
+	service.broadcast <- *update
+	log.Debugf("Received GROUP_MEMBERSHIP message from %s", pid.Pretty())
+	return nil, nil
+}
+
+func (service *OpenBazaarService) handlePostComment(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
+	if pmes.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	post := new(pb.SignedPost)
+	if err := ptypes.UnmarshalAny(pmes.Payload, post); err != nil {
+		return nil, err
+	}
+	comment, err := service.node.ProcessPostComment(post)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Received POST_COMMENT message from %s", pid.Pretty())
+	if comment == nil {
+		return nil, nil
+	}
+	if err := service.node.UpdateCommentIndex(); err != nil {
+		log.Error(err)
+	} else if err := service.node.SeedNode(); err != nil {
+		log.Error(err)
+	}
+	if err := service.node.ForwardPostComment(comment); err != nil {
+		log.Errorf("forwarding comment %s: %s", comment.ID, err)
+	}
+	n := repo.PostCommentNotification{
+		ID:        repo.NewNotificationID(),
+		Type:      repo.NotifierTypePostComment,
+		PostSlug:  comment.PostSlug,
+		CommentID: comment.ID,
+		Reference: comment.Reference,
+		PeerID:    comment.AuthorID,
+		PostType:  comment.PostType,
+		Status:    post.Post.Status,
+	}
+	service.broadcast <- n
+	service.datastore.Notifications().PutRecord(repo.NewNotification(n, time.Now(), false))
+	return nil, nil
+}
+
 func (service *OpenBazaarService) handleStore(pid peer.ID, pmes *pb.Message, options interface{}) (*pb.Message, error) {
 	// If we aren't accepting store requests then ban this peer
 	if !service.node.AcceptStoreRequests {
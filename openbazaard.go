@@ -84,6 +84,22 @@ func main() {
 		"convert this node to a different coin type",
 		"This command will convert the node to use a different cryptocurrency",
 		&cmd.Convert{})
+	parser.AddCommand("verifycase",
+		"verify a dispute case export",
+		"This command checks every signature in a case export bundle produced by /ob/case/{id}/export. It does not need a repo or network access.",
+		&cmd.VerifyCase{})
+	parser.AddCommand("signer",
+		"run a signing daemon",
+		"This command serves the signatures made with a node's seed, and with its identity key, over a Unix socket, so a node with SignerSocket set in its config never loads its seed. Point it at a data directory holding the node's keys.",
+		&cmd.SignerDaemon{})
+	parser.AddCommand("publickeys",
+		"print the public keys of a node",
+		"This command prints the config a node needs to run watch-only with ExternalSigning: the public keys of its seed and the master key's signature of its peer ID. Run it where the seed is kept and copy the output into the watch-only node's config.",
+		&cmd.PublicKeys{})
+	parser.AddCommand("deployescrow",
+		"deploy a token's escrow contract",
+		"This command deploys the escrow contract moderated and offline orders in a configured token are paid through, from the node's account of the token, which pays the gas. Set the printed address as the token's Escrow in the config once the transaction is mined.",
+		&cmd.DeployEscrow{})
 	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
 		fmt.Println(core.VERSION)
 		return
@@ -111,18 +111,21 @@ const (
 	Order_Payment_ADDRESS_REQUEST Order_Payment_Method = 0
 	Order_Payment_DIRECT          Order_Payment_Method = 1
 	Order_Payment_MODERATED       Order_Payment_Method = 2
+	Order_Payment_PAYMENT_CHANNEL Order_Payment_Method = 3
 )
 
 var Order_Payment_Method_name = map[int32]string{
 	0: "ADDRESS_REQUEST",
 	1: "DIRECT",
 	2: "MODERATED",
+	3: "PAYMENT_CHANNEL",
 }
 
 var Order_Payment_Method_value = map[string]int32{
 	"ADDRESS_REQUEST": 0,
 	"DIRECT":          1,
 	"MODERATED":       2,
+	"PAYMENT_CHANNEL": 3,
 }
 
 func (x Order_Payment_Method) String() string {
@@ -133,6 +136,37 @@ func (Order_Payment_Method) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_b6d125f880f9ca35, []int{2, 2, 0}
 }
 
+type DisputeFallback_Policy int32
+
+const (
+	DisputeFallback_NONE           DisputeFallback_Policy = 0
+	DisputeFallback_REFUND_BUYER   DisputeFallback_Policy = 1
+	DisputeFallback_RELEASE_VENDOR DisputeFallback_Policy = 2
+	DisputeFallback_SPLIT          DisputeFallback_Policy = 3
+)
+
+var DisputeFallback_Policy_name = map[int32]string{
+	0: "NONE",
+	1: "REFUND_BUYER",
+	2: "RELEASE_VENDOR",
+	3: "SPLIT",
+}
+
+var DisputeFallback_Policy_value = map[string]int32{
+	"NONE":           0,
+	"REFUND_BUYER":   1,
+	"RELEASE_VENDOR": 2,
+	"SPLIT":          3,
+}
+
+func (x DisputeFallback_Policy) String() string {
+	return proto.EnumName(DisputeFallback_Policy_name, int32(x))
+}
+
+func (DisputeFallback_Policy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_b6d125f880f9ca35, []int{12, 0}
+}
+
 type Signature_Section int32
 
 const (
@@ -173,24 +207,25 @@ func (x Signature_Section) String() string {
 }
 
 func (Signature_Section) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{17, 0}
+	return fileDescriptor_b6d125f880f9ca35, []int{19, 0}
 }
 
 type RicardianContract struct {
-	VendorListings          []*Listing          `protobuf:"bytes,1,rep,name=vendorListings,proto3" json:"vendorListings,omitempty"`
-	BuyerOrder              *Order              `protobuf:"bytes,2,opt,name=buyerOrder,proto3" json:"buyerOrder,omitempty"`
-	VendorOrderConfirmation *OrderConfirmation  `protobuf:"bytes,3,opt,name=vendorOrderConfirmation,proto3" json:"vendorOrderConfirmation,omitempty"`
-	VendorOrderFulfillment  []*OrderFulfillment `protobuf:"bytes,4,rep,name=vendorOrderFulfillment,proto3" json:"vendorOrderFulfillment,omitempty"`
-	BuyerOrderCompletion    *OrderCompletion    `protobuf:"bytes,5,opt,name=buyerOrderCompletion,proto3" json:"buyerOrderCompletion,omitempty"`
-	Dispute                 *Dispute            `protobuf:"bytes,6,opt,name=dispute,proto3" json:"dispute,omitempty"`
-	DisputeResolution       *DisputeResolution  `protobuf:"bytes,7,opt,name=disputeResolution,proto3" json:"disputeResolution,omitempty"`
-	DisputeAcceptance       *DisputeAcceptance  `protobuf:"bytes,8,opt,name=disputeAcceptance,proto3" json:"disputeAcceptance,omitempty"`
-	Refund                  *Refund             `protobuf:"bytes,9,opt,name=refund,proto3" json:"refund,omitempty"`
-	Signatures              []*Signature        `protobuf:"bytes,10,rep,name=signatures,proto3" json:"signatures,omitempty"`
-	Errors                  []string            `protobuf:"bytes,11,rep,name=errors,proto3" json:"errors,omitempty"`
-	XXX_NoUnkeyedLiteral    struct{}            `json:"-"`
-	XXX_unrecognized        []byte              `json:"-"`
-	XXX_sizecache           int32               `json:"-"`
+	VendorListings          []*Listing             `protobuf:"bytes,1,rep,name=vendorListings,proto3" json:"vendorListings,omitempty"`
+	BuyerOrder              *Order                 `protobuf:"bytes,2,opt,name=buyerOrder,proto3" json:"buyerOrder,omitempty"`
+	VendorOrderConfirmation *OrderConfirmation     `protobuf:"bytes,3,opt,name=vendorOrderConfirmation,proto3" json:"vendorOrderConfirmation,omitempty"`
+	VendorOrderFulfillment  []*OrderFulfillment    `protobuf:"bytes,4,rep,name=vendorOrderFulfillment,proto3" json:"vendorOrderFulfillment,omitempty"`
+	BuyerOrderCompletion    *OrderCompletion       `protobuf:"bytes,5,opt,name=buyerOrderCompletion,proto3" json:"buyerOrderCompletion,omitempty"`
+	Dispute                 *Dispute               `protobuf:"bytes,6,opt,name=dispute,proto3" json:"dispute,omitempty"`
+	DisputeResolution       *DisputeResolution     `protobuf:"bytes,7,opt,name=disputeResolution,proto3" json:"disputeResolution,omitempty"`
+	DisputeAcceptance       *DisputeAcceptance     `protobuf:"bytes,8,opt,name=disputeAcceptance,proto3" json:"disputeAcceptance,omitempty"`
+	Refund                  *Refund                `protobuf:"bytes,9,opt,name=refund,proto3" json:"refund,omitempty"`
+	Signatures              []*Signature           `protobuf:"bytes,10,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	Errors                  []string               `protobuf:"bytes,11,rep,name=errors,proto3" json:"errors,omitempty"`
+	DisputeFallbackPayout   *DisputeFallbackPayout `protobuf:"bytes,12,opt,name=disputeFallbackPayout,proto3" json:"disputeFallbackPayout,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}               `json:"-"`
+	XXX_unrecognized        []byte                 `json:"-"`
+	XXX_sizecache           int32                  `json:"-"`
 }
 
 func (m *RicardianContract) Reset()         { *m = RicardianContract{} }
@@ -295,6 +330,13 @@ func (m *RicardianContract) GetErrors() []string {
 	return nil
 }
 
+func (m *RicardianContract) GetDisputeFallbackPayout() *DisputeFallbackPayout {
+	if m != nil {
+		return m.DisputeFallbackPayout
+	}
+	return nil
+}
+
 type Listing struct {
 	Slug                 string                    `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
 	VendorID             *ID                       `protobuf:"bytes,2,opt,name=vendorID,proto3" json:"vendorID,omitempty"`
@@ -426,6 +468,7 @@ type Listing_Metadata struct {
 	CoinType             string                        `protobuf:"bytes,9,opt,name=coinType,proto3" json:"coinType,omitempty"`
 	CoinDivisibility     uint32                        `protobuf:"varint,10,opt,name=coinDivisibility,proto3" json:"coinDivisibility,omitempty"`
 	PriceModifier        float32                       `protobuf:"fixed32,11,opt,name=priceModifier,proto3" json:"priceModifier,omitempty"`
+	DisputeFallback      *DisputeFallback              `protobuf:"bytes,12,opt,name=disputeFallback,proto3" json:"disputeFallback,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
 	XXX_unrecognized     []byte                        `json:"-"`
 	XXX_sizecache        int32                         `json:"-"`
@@ -533,6 +576,13 @@ func (m *Listing_Metadata) GetPriceModifier() float32 {
 	return 0
 }
 
+func (m *Listing_Metadata) GetDisputeFallback() *DisputeFallback {
+	if m != nil {
+		return m.DisputeFallback
+	}
+	return nil
+}
+
 type Listing_Item struct {
 	Title                string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description          string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
@@ -1612,17 +1662,25 @@ func (m *Order_Item_ShippingOption) GetService() string {
 }
 
 type Order_Payment struct {
-	Method               Order_Payment_Method `protobuf:"varint,1,opt,name=method,proto3,enum=Order_Payment_Method" json:"method,omitempty"`
-	Moderator            string               `protobuf:"bytes,2,opt,name=moderator,proto3" json:"moderator,omitempty"`
-	Amount               uint64               `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
-	Chaincode            string               `protobuf:"bytes,4,opt,name=chaincode,proto3" json:"chaincode,omitempty"`
-	Address              string               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
-	RedeemScript         string               `protobuf:"bytes,6,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
-	ModeratorKey         []byte               `protobuf:"bytes,7,opt,name=moderatorKey,proto3" json:"moderatorKey,omitempty"`
-	Coin                 string               `protobuf:"bytes,8,opt,name=coin,proto3" json:"coin,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Method       Order_Payment_Method `protobuf:"varint,1,opt,name=method,proto3,enum=Order_Payment_Method" json:"method,omitempty"`
+	Moderator    string               `protobuf:"bytes,2,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	Amount       uint64               `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Chaincode    string               `protobuf:"bytes,4,opt,name=chaincode,proto3" json:"chaincode,omitempty"`
+	Address      string               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	RedeemScript string               `protobuf:"bytes,6,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	ModeratorKey []byte               `protobuf:"bytes,7,opt,name=moderatorKey,proto3" json:"moderatorKey,omitempty"`
+	Coin         string               `protobuf:"bytes,8,opt,name=coin,proto3" json:"coin,omitempty"`
+	// A panel adds two moderators to the one above. The escrow is then
+	// released by the buyer and vendor together or by two of the three
+	// moderators, and a dispute is resolved once two panel members agree.
+	PanelModerators    []string `protobuf:"bytes,9,rep,name=panelModerators,proto3" json:"panelModerators,omitempty"`
+	PanelModeratorKeys [][]byte `protobuf:"bytes,10,rep,name=panelModeratorKeys,proto3" json:"panelModeratorKeys,omitempty"`
+	// The panel's fee, fixed by the buyer from the order moderator's fee
+	// schedule. Each of the two members releasing a payout is paid half.
+	PanelFee             uint64   `protobuf:"varint,11,opt,name=panelFee,proto3" json:"panelFee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Order_Payment) Reset()         { *m = Order_Payment{} }
@@ -1706,16 +1764,40 @@ func (m *Order_Payment) GetCoin() string {
 	return ""
 }
 
+func (m *Order_Payment) GetPanelModerators() []string {
+	if m != nil {
+		return m.PanelModerators
+	}
+	return nil
+}
+
+func (m *Order_Payment) GetPanelModeratorKeys() [][]byte {
+	if m != nil {
+		return m.PanelModeratorKeys
+	}
+	return nil
+}
+
+func (m *Order_Payment) GetPanelFee() uint64 {
+	if m != nil {
+		return m.PanelFee
+	}
+	return 0
+}
+
 type OrderConfirmation struct {
 	OrderID   string               `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
 	Timestamp *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	// Direct payments only
-	PaymentAddress       string             `protobuf:"bytes,3,opt,name=paymentAddress,proto3" json:"paymentAddress,omitempty"`
-	RequestedAmount      uint64             `protobuf:"varint,4,opt,name=requestedAmount,proto3" json:"requestedAmount,omitempty"`
-	RatingSignatures     []*RatingSignature `protobuf:"bytes,5,rep,name=ratingSignatures,proto3" json:"ratingSignatures,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	PaymentAddress   string             `protobuf:"bytes,3,opt,name=paymentAddress,proto3" json:"paymentAddress,omitempty"`
+	RequestedAmount  uint64             `protobuf:"varint,4,opt,name=requestedAmount,proto3" json:"requestedAmount,omitempty"`
+	RatingSignatures []*RatingSignature `protobuf:"bytes,5,rep,name=ratingSignatures,proto3" json:"ratingSignatures,omitempty"`
+	// Payment channel payments only. The vendor's invoice for the order,
+	// with the order ID as its description.
+	PaymentInvoice       string   `protobuf:"bytes,6,opt,name=paymentInvoice,proto3" json:"paymentInvoice,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *OrderConfirmation) Reset()         { *m = OrderConfirmation{} }
@@ -1778,6 +1860,13 @@ func (m *OrderConfirmation) GetRatingSignatures() []*RatingSignature {
 	return nil
 }
 
+func (m *OrderConfirmation) GetPaymentInvoice() string {
+	if m != nil {
+		return m.PaymentInvoice
+	}
+	return ""
+}
+
 type OrderReject struct {
 	OrderID              string               `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
 	Timestamp            *timestamp.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
@@ -1965,8 +2054,10 @@ type RatingSignature_TransactionMetadata_Image struct {
 func (m *RatingSignature_TransactionMetadata_Image) Reset() {
 	*m = RatingSignature_TransactionMetadata_Image{}
 }
-func (m *RatingSignature_TransactionMetadata_Image) String() string { return proto.CompactTextString(m) }
-func (*RatingSignature_TransactionMetadata_Image) ProtoMessage()    {}
+func (m *RatingSignature_TransactionMetadata_Image) String() string {
+	return proto.CompactTextString(m)
+}
+func (*RatingSignature_TransactionMetadata_Image) ProtoMessage() {}
 func (*RatingSignature_TransactionMetadata_Image) Descriptor() ([]byte, []int) {
 	return fileDescriptor_b6d125f880f9ca35, []int{5, 0, 0}
 }
@@ -2699,6 +2790,7 @@ type DisputeResolution struct {
 	Resolution           string                    `protobuf:"bytes,4,opt,name=resolution,proto3" json:"resolution,omitempty"`
 	Payout               *DisputeResolution_Payout `protobuf:"bytes,5,opt,name=payout,proto3" json:"payout,omitempty"`
 	ModeratorRatingSigs  [][]byte                  `protobuf:"bytes,6,rep,name=moderatorRatingSigs,proto3" json:"moderatorRatingSigs,omitempty"`
+	EndorsedBy           string                    `protobuf:"bytes,7,opt,name=endorsedBy,proto3" json:"endorsedBy,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
 	XXX_unrecognized     []byte                    `json:"-"`
 	XXX_sizecache        int32                     `json:"-"`
@@ -2771,12 +2863,21 @@ func (m *DisputeResolution) GetModeratorRatingSigs() [][]byte {
 	return nil
 }
 
+func (m *DisputeResolution) GetEndorsedBy() string {
+	if m != nil {
+		return m.EndorsedBy
+	}
+	return ""
+}
+
 type DisputeResolution_Payout struct {
 	Sigs                 []*BitcoinSignature              `protobuf:"bytes,1,rep,name=sigs,proto3" json:"sigs,omitempty"`
 	Inputs               []*Outpoint                      `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty"`
 	BuyerOutput          *DisputeResolution_Payout_Output `protobuf:"bytes,3,opt,name=buyerOutput,proto3" json:"buyerOutput,omitempty"`
 	VendorOutput         *DisputeResolution_Payout_Output `protobuf:"bytes,4,opt,name=vendorOutput,proto3" json:"vendorOutput,omitempty"`
 	ModeratorOutput      *DisputeResolution_Payout_Output `protobuf:"bytes,5,opt,name=moderatorOutput,proto3" json:"moderatorOutput,omitempty"`
+	PanelOutput          *DisputeResolution_Payout_Output `protobuf:"bytes,6,opt,name=panelOutput,proto3" json:"panelOutput,omitempty"`
+	PanelSigs            []*BitcoinSignature              `protobuf:"bytes,7,rep,name=panelSigs,proto3" json:"panelSigs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
 	XXX_unrecognized     []byte                           `json:"-"`
 	XXX_sizecache        int32                            `json:"-"`
@@ -2842,6 +2943,20 @@ func (m *DisputeResolution_Payout) GetModeratorOutput() *DisputeResolution_Payou
 	return nil
 }
 
+func (m *DisputeResolution_Payout) GetPanelOutput() *DisputeResolution_Payout_Output {
+	if m != nil {
+		return m.PanelOutput
+	}
+	return nil
+}
+
+func (m *DisputeResolution_Payout) GetPanelSigs() []*BitcoinSignature {
+	if m != nil {
+		return m.PanelSigs
+	}
+	return nil
+}
+
 type DisputeResolution_Payout_Output struct {
 	// Types that are valid to be assigned to ScriptOrAddress:
 	//	*DisputeResolution_Payout_Output_Script
@@ -2930,6 +3045,170 @@ func (*DisputeResolution_Payout_Output) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+// DisputeFallback is the vendor's rule, accepted by the buyer when placing
+// the order, for paying out the escrow when the moderator does not resolve a
+// dispute within the given number of days
+type DisputeFallback struct {
+	Policy               DisputeFallback_Policy `protobuf:"varint,1,opt,name=policy,proto3,enum=DisputeFallback_Policy" json:"policy,omitempty"`
+	Days                 uint32                 `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *DisputeFallback) Reset()         { *m = DisputeFallback{} }
+func (m *DisputeFallback) String() string { return proto.CompactTextString(m) }
+func (*DisputeFallback) ProtoMessage()    {}
+func (*DisputeFallback) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b6d125f880f9ca35, []int{12}
+}
+
+func (m *DisputeFallback) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisputeFallback.Unmarshal(m, b)
+}
+func (m *DisputeFallback) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisputeFallback.Marshal(b, m, deterministic)
+}
+func (m *DisputeFallback) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisputeFallback.Merge(m, src)
+}
+func (m *DisputeFallback) XXX_Size() int {
+	return xxx_messageInfo_DisputeFallback.Size(m)
+}
+func (m *DisputeFallback) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisputeFallback.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisputeFallback proto.InternalMessageInfo
+
+func (m *DisputeFallback) GetPolicy() DisputeFallback_Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return DisputeFallback_NONE
+}
+
+func (m *DisputeFallback) GetDays() uint32 {
+	if m != nil {
+		return m.Days
+	}
+	return 0
+}
+
+// DisputeFallbackPayout is a transaction paying out the escrow according to
+// the contract's DisputeFallback. It is signed by the proposing party and
+// completed by the other party without the moderator.
+type DisputeFallbackPayout struct {
+	Timestamp            *timestamp.Timestamp             `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	OrderId              string                           `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	ProposedBy           string                           `protobuf:"bytes,3,opt,name=proposedBy,proto3" json:"proposedBy,omitempty"`
+	Policy               DisputeFallback_Policy           `protobuf:"varint,4,opt,name=policy,proto3,enum=DisputeFallback_Policy" json:"policy,omitempty"`
+	Inputs               []*Outpoint                      `protobuf:"bytes,5,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	BuyerOutput          *DisputeResolution_Payout_Output `protobuf:"bytes,6,opt,name=buyerOutput,proto3" json:"buyerOutput,omitempty"`
+	VendorOutput         *DisputeResolution_Payout_Output `protobuf:"bytes,7,opt,name=vendorOutput,proto3" json:"vendorOutput,omitempty"`
+	Sigs                 []*BitcoinSignature              `protobuf:"bytes,8,rep,name=sigs,proto3" json:"sigs,omitempty"`
+	LockTime             uint32                           `protobuf:"varint,9,opt,name=lockTime,proto3" json:"lockTime,omitempty"`
+	CounterpartySigs     []*BitcoinSignature              `protobuf:"bytes,10,rep,name=counterpartySigs,proto3" json:"counterpartySigs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *DisputeFallbackPayout) Reset()         { *m = DisputeFallbackPayout{} }
+func (m *DisputeFallbackPayout) String() string { return proto.CompactTextString(m) }
+func (*DisputeFallbackPayout) ProtoMessage()    {}
+func (*DisputeFallbackPayout) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b6d125f880f9ca35, []int{13}
+}
+
+func (m *DisputeFallbackPayout) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DisputeFallbackPayout.Unmarshal(m, b)
+}
+func (m *DisputeFallbackPayout) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DisputeFallbackPayout.Marshal(b, m, deterministic)
+}
+func (m *DisputeFallbackPayout) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DisputeFallbackPayout.Merge(m, src)
+}
+func (m *DisputeFallbackPayout) XXX_Size() int {
+	return xxx_messageInfo_DisputeFallbackPayout.Size(m)
+}
+func (m *DisputeFallbackPayout) XXX_DiscardUnknown() {
+	xxx_messageInfo_DisputeFallbackPayout.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DisputeFallbackPayout proto.InternalMessageInfo
+
+func (m *DisputeFallbackPayout) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *DisputeFallbackPayout) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *DisputeFallbackPayout) GetProposedBy() string {
+	if m != nil {
+		return m.ProposedBy
+	}
+	return ""
+}
+
+func (m *DisputeFallbackPayout) GetPolicy() DisputeFallback_Policy {
+	if m != nil {
+		return m.Policy
+	}
+	return DisputeFallback_NONE
+}
+
+func (m *DisputeFallbackPayout) GetInputs() []*Outpoint {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *DisputeFallbackPayout) GetBuyerOutput() *DisputeResolution_Payout_Output {
+	if m != nil {
+		return m.BuyerOutput
+	}
+	return nil
+}
+
+func (m *DisputeFallbackPayout) GetVendorOutput() *DisputeResolution_Payout_Output {
+	if m != nil {
+		return m.VendorOutput
+	}
+	return nil
+}
+
+func (m *DisputeFallbackPayout) GetSigs() []*BitcoinSignature {
+	if m != nil {
+		return m.Sigs
+	}
+	return nil
+}
+
+func (m *DisputeFallbackPayout) GetLockTime() uint32 {
+	if m != nil {
+		return m.LockTime
+	}
+	return 0
+}
+
+func (m *DisputeFallbackPayout) GetCounterpartySigs() []*BitcoinSignature {
+	if m != nil {
+		return m.CounterpartySigs
+	}
+	return nil
+}
+
 type DisputeAcceptance struct {
 	Timestamp            *timestamp.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	ClosedBy             string               `protobuf:"bytes,2,opt,name=closedBy,proto3" json:"closedBy,omitempty"`
@@ -2942,7 +3221,7 @@ func (m *DisputeAcceptance) Reset()         { *m = DisputeAcceptance{} }
 func (m *DisputeAcceptance) String() string { return proto.CompactTextString(m) }
 func (*DisputeAcceptance) ProtoMessage()    {}
 func (*DisputeAcceptance) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{12}
+	return fileDescriptor_b6d125f880f9ca35, []int{14}
 }
 
 func (m *DisputeAcceptance) XXX_Unmarshal(b []byte) error {
@@ -2990,7 +3269,7 @@ func (m *Outpoint) Reset()         { *m = Outpoint{} }
 func (m *Outpoint) String() string { return proto.CompactTextString(m) }
 func (*Outpoint) ProtoMessage()    {}
 func (*Outpoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{13}
+	return fileDescriptor_b6d125f880f9ca35, []int{15}
 }
 
 func (m *Outpoint) XXX_Unmarshal(b []byte) error {
@@ -3047,7 +3326,7 @@ func (m *Refund) Reset()         { *m = Refund{} }
 func (m *Refund) String() string { return proto.CompactTextString(m) }
 func (*Refund) ProtoMessage()    {}
 func (*Refund) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{14}
+	return fileDescriptor_b6d125f880f9ca35, []int{16}
 }
 
 func (m *Refund) XXX_Unmarshal(b []byte) error {
@@ -3115,7 +3394,7 @@ func (m *Refund_TransactionInfo) Reset()         { *m = Refund_TransactionInfo{}
 func (m *Refund_TransactionInfo) String() string { return proto.CompactTextString(m) }
 func (*Refund_TransactionInfo) ProtoMessage()    {}
 func (*Refund_TransactionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{14, 0}
+	return fileDescriptor_b6d125f880f9ca35, []int{16, 0}
 }
 
 func (m *Refund_TransactionInfo) XXX_Unmarshal(b []byte) error {
@@ -3161,7 +3440,7 @@ func (m *VendorFinalizedPayment) Reset()         { *m = VendorFinalizedPayment{}
 func (m *VendorFinalizedPayment) String() string { return proto.CompactTextString(m) }
 func (*VendorFinalizedPayment) ProtoMessage()    {}
 func (*VendorFinalizedPayment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{15}
+	return fileDescriptor_b6d125f880f9ca35, []int{17}
 }
 
 func (m *VendorFinalizedPayment) XXX_Unmarshal(b []byte) error {
@@ -3203,7 +3482,7 @@ func (m *ID) Reset()         { *m = ID{} }
 func (m *ID) String() string { return proto.CompactTextString(m) }
 func (*ID) ProtoMessage()    {}
 func (*ID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{16}
+	return fileDescriptor_b6d125f880f9ca35, []int{18}
 }
 
 func (m *ID) XXX_Unmarshal(b []byte) error {
@@ -3264,7 +3543,7 @@ func (m *ID_Pubkeys) Reset()         { *m = ID_Pubkeys{} }
 func (m *ID_Pubkeys) String() string { return proto.CompactTextString(m) }
 func (*ID_Pubkeys) ProtoMessage()    {}
 func (*ID_Pubkeys) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{16, 0}
+	return fileDescriptor_b6d125f880f9ca35, []int{18, 0}
 }
 
 func (m *ID_Pubkeys) XXX_Unmarshal(b []byte) error {
@@ -3311,7 +3590,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{17}
+	return fileDescriptor_b6d125f880f9ca35, []int{19}
 }
 
 func (m *Signature) XXX_Unmarshal(b []byte) error {
@@ -3359,7 +3638,7 @@ func (m *SignedListing) Reset()         { *m = SignedListing{} }
 func (m *SignedListing) String() string { return proto.CompactTextString(m) }
 func (*SignedListing) ProtoMessage()    {}
 func (*SignedListing) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b6d125f880f9ca35, []int{18}
+	return fileDescriptor_b6d125f880f9ca35, []int{20}
 }
 
 func (m *SignedListing) XXX_Unmarshal(b []byte) error {
@@ -3406,6 +3685,7 @@ func init() {
 	proto.RegisterEnum("Listing_Metadata_Format", Listing_Metadata_Format_name, Listing_Metadata_Format_value)
 	proto.RegisterEnum("Listing_ShippingOption_ShippingType", Listing_ShippingOption_ShippingType_name, Listing_ShippingOption_ShippingType_value)
 	proto.RegisterEnum("Order_Payment_Method", Order_Payment_Method_name, Order_Payment_Method_value)
+	proto.RegisterEnum("DisputeFallback_Policy", DisputeFallback_Policy_name, DisputeFallback_Policy_value)
 	proto.RegisterEnum("Signature_Section", Signature_Section_name, Signature_Section_value)
 	proto.RegisterType((*RicardianContract)(nil), "RicardianContract")
 	proto.RegisterType((*Listing)(nil), "Listing")
@@ -3443,6 +3723,8 @@ func init() {
 	proto.RegisterType((*DisputeResolution)(nil), "DisputeResolution")
 	proto.RegisterType((*DisputeResolution_Payout)(nil), "DisputeResolution.Payout")
 	proto.RegisterType((*DisputeResolution_Payout_Output)(nil), "DisputeResolution.Payout.Output")
+	proto.RegisterType((*DisputeFallback)(nil), "DisputeFallback")
+	proto.RegisterType((*DisputeFallbackPayout)(nil), "DisputeFallbackPayout")
 	proto.RegisterType((*DisputeAcceptance)(nil), "DisputeAcceptance")
 	proto.RegisterType((*Outpoint)(nil), "Outpoint")
 	proto.RegisterType((*Refund)(nil), "Refund")
@@ -3457,212 +3739,230 @@ func init() {
 func init() { proto.RegisterFile("contracts.proto", fileDescriptor_b6d125f880f9ca35) }
 
 var fileDescriptor_b6d125f880f9ca35 = []byte{
-	// 3307 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x5a, 0xbd, 0x73, 0x5b, 0xc7,
-	0xb5, 0x17, 0xbe, 0x81, 0x43, 0x90, 0x04, 0x57, 0xb4, 0x8c, 0x87, 0xf1, 0xb3, 0x28, 0x8c, 0xac,
-	0x47, 0xcb, 0x32, 0x2c, 0xf3, 0xbd, 0x79, 0xa3, 0x89, 0x33, 0xb6, 0x49, 0x00, 0x34, 0x61, 0x51,
-	0x24, 0xb3, 0x80, 0x9c, 0x38, 0x8d, 0x72, 0x79, 0xef, 0x12, 0xdc, 0x08, 0xb8, 0x17, 0xbe, 0x77,
-	0x2f, 0x45, 0x24, 0x55, 0xba, 0x14, 0x99, 0x49, 0xe1, 0xc2, 0x45, 0xba, 0x54, 0xf9, 0x1f, 0xe2,
-	0x2a, 0x6d, 0x26, 0x4d, 0x8a, 0x8c, 0x53, 0xa5, 0xc9, 0x1f, 0xe0, 0x26, 0x93, 0x22, 0x4d, 0x66,
-	0xcf, 0xee, 0xde, 0x2f, 0x80, 0xb2, 0xe4, 0x4c, 0x26, 0x15, 0xef, 0xf9, 0x9d, 0xb3, 0x7b, 0xf7,
-	0x9e, 0x3d, 0xdf, 0x20, 0xac, 0xdb, 0x9e, 0x2b, 0x7c, 0xcb, 0x16, 0x41, 0x67, 0xe6, 0x7b, 0xc2,
-	0x6b, 0x11, 0xdb, 0x0b, 0x5d, 0xe1, 0xcf, 0x6d, 0xcf, 0x61, 0x06, 0xbb, 0x39, 0xf6, 0xbc, 0xf1,
-	0x84, 0xbd, 0x83, 0xd4, 0x69, 0x78, 0xf6, 0x8e, 0xe0, 0x53, 0x16, 0x08, 0x6b, 0x3a, 0x53, 0x02,
-	0xed, 0x3f, 0x17, 0x61, 0x83, 0x72, 0xdb, 0xf2, 0x1d, 0x6e, 0xb9, 0x5d, 0xbd, 0x23, 0xb9, 0x0f,
-	0x6b, 0x17, 0xcc, 0x75, 0x3c, 0xff, 0x90, 0x07, 0x82, 0xbb, 0xe3, 0xa0, 0x99, 0xdb, 0x2a, 0x6c,
-	0xaf, 0xec, 0x54, 0x3b, 0x1a, 0xa0, 0x19, 0x3e, 0xb9, 0x03, 0x70, 0x1a, 0xce, 0x99, 0x7f, 0xec,
-	0x3b, 0xcc, 0x6f, 0xe6, 0xb7, 0x72, 0xdb, 0x2b, 0x3b, 0xe5, 0x0e, 0x52, 0x34, 0xc1, 0x21, 0x87,
-	0xf0, 0xaa, 0x5a, 0x89, 0x64, 0xd7, 0x73, 0xcf, 0xb8, 0x3f, 0xb5, 0x04, 0xf7, 0xdc, 0x66, 0x01,
-	0x17, 0x91, 0xce, 0x02, 0x87, 0x5e, 0xb5, 0x84, 0x0c, 0xe0, 0x46, 0x82, 0xb5, 0x1f, 0x4e, 0xce,
-	0xf8, 0x64, 0x32, 0x65, 0xae, 0x68, 0x16, 0xf1, 0xbc, 0x1b, 0x9d, 0x2c, 0x83, 0x5e, 0xb1, 0x80,
-	0xf4, 0x60, 0x33, 0x3e, 0x66, 0xd7, 0x9b, 0xce, 0x26, 0x0c, 0x4f, 0x55, 0xc2, 0x53, 0x35, 0x3a,
-	0x19, 0x9c, 0x2e, 0x95, 0x26, 0x6d, 0xa8, 0x38, 0x3c, 0x98, 0x85, 0x82, 0x35, 0xcb, 0xb8, 0xb0,
-	0xda, 0xe9, 0x29, 0x9a, 0x1a, 0x06, 0xf9, 0x10, 0x36, 0xf4, 0x23, 0x65, 0x81, 0x37, 0x09, 0xf1,
-	0x35, 0x15, 0xfd, 0xf1, 0xbd, 0x2c, 0x87, 0x2e, 0x0a, 0x27, 0x76, 0xd8, 0xb5, 0x6d, 0x36, 0x13,
-	0x96, 0x6b, 0xb3, 0x66, 0x35, 0xbd, 0x43, 0xcc, 0xa1, 0x8b, 0xc2, 0xe4, 0x26, 0x94, 0x7d, 0x76,
-	0x16, 0xba, 0x4e, 0xb3, 0x86, 0xcb, 0x2a, 0x1d, 0x8a, 0x24, 0xd5, 0x30, 0xb9, 0x0b, 0x10, 0xf0,
-	0xb1, 0x6b, 0x89, 0xd0, 0x67, 0x41, 0x13, 0x50, 0x9b, 0xd0, 0x19, 0x1a, 0x88, 0x26, 0xb8, 0xe4,
-	0x06, 0x94, 0x99, 0xef, 0x7b, 0x7e, 0xd0, 0x5c, 0xd9, 0x2a, 0x6c, 0xd7, 0xa8, 0xa6, 0xda, 0x5f,
-	0x6f, 0x42, 0x45, 0x1b, 0x08, 0x21, 0x50, 0x0c, 0x26, 0xe1, 0xb8, 0x99, 0xdb, 0xca, 0x6d, 0xd7,
-	0x28, 0x3e, 0x93, 0x9b, 0x50, 0x55, 0x97, 0x31, 0xe8, 0x69, 0x8b, 0x29, 0x74, 0x06, 0x3d, 0x1a,
-	0x81, 0xe4, 0x6d, 0xa8, 0x4e, 0x99, 0xb0, 0x1c, 0x4b, 0x58, 0xda, 0x3a, 0x36, 0x8c, 0x01, 0x76,
-	0x1e, 0x69, 0x06, 0x8d, 0x44, 0xc8, 0x2d, 0x28, 0x72, 0xc1, 0xa6, 0xcd, 0x22, 0x8a, 0xae, 0x46,
-	0xa2, 0x03, 0xc1, 0xa6, 0x14, 0x59, 0x64, 0x17, 0xd6, 0x83, 0x73, 0x3e, 0x9b, 0x71, 0x77, 0x7c,
-	0x3c, 0x93, 0xba, 0x0c, 0x9a, 0x25, 0xfc, 0xb6, 0x57, 0x23, 0xe9, 0x61, 0x8a, 0x4f, 0xb3, 0xf2,
-	0xa4, 0x0d, 0x25, 0x61, 0x5d, 0xb2, 0xa0, 0x59, 0xc6, 0x85, 0xf5, 0x68, 0xe1, 0xc8, 0xba, 0xa4,
-	0x8a, 0x45, 0xde, 0x84, 0x8a, 0xed, 0x85, 0x33, 0xb9, 0x7d, 0x05, 0xa5, 0xd6, 0x23, 0xa9, 0x2e,
-	0xe2, 0xd4, 0xf0, 0xc9, 0xeb, 0x00, 0x53, 0xcf, 0x61, 0xbe, 0x25, 0xa4, 0x02, 0xab, 0xa8, 0xc0,
-	0x04, 0x42, 0x3a, 0x40, 0x04, 0xf3, 0xa7, 0xc1, 0xae, 0xeb, 0x74, 0x3d, 0xd7, 0xe1, 0xea, 0xd0,
-	0x35, 0x54, 0xe3, 0x12, 0x0e, 0x69, 0x43, 0x5d, 0x5d, 0xe1, 0x89, 0x37, 0xe1, 0xf6, 0xbc, 0x09,
-	0x28, 0x99, 0xc2, 0x48, 0x13, 0x2a, 0x82, 0x05, 0xc2, 0x65, 0xa2, 0xb9, 0xb2, 0x95, 0xdb, 0xae,
-	0x52, 0x43, 0xb6, 0xfe, 0x56, 0x84, 0xaa, 0xd1, 0xac, 0x14, 0xbb, 0x60, 0x7e, 0x20, 0xcd, 0x53,
-	0x5e, 0xdb, 0x2a, 0x35, 0x24, 0xd9, 0x83, 0xba, 0x89, 0x3e, 0xa3, 0xf9, 0x8c, 0xe1, 0xed, 0xad,
-	0xed, 0xbc, 0xbe, 0x70, 0x39, 0x9d, 0x6e, 0x42, 0x8a, 0xa6, 0xd6, 0x90, 0xfb, 0x50, 0x3e, 0xf3,
-	0xa4, 0x23, 0xe3, 0xd5, 0xae, 0xed, 0x34, 0x17, 0x57, 0xef, 0x23, 0x9f, 0x6a, 0x39, 0xb2, 0x03,
-	0x65, 0x76, 0x39, 0xe3, 0xfe, 0x5c, 0xdf, 0x70, 0xab, 0xa3, 0xa2, 0x5b, 0xc7, 0x44, 0xb7, 0xce,
-	0xc8, 0x44, 0x37, 0xaa, 0x25, 0xa5, 0xfa, 0x2c, 0x34, 0x7b, 0xe6, 0x74, 0x43, 0xdf, 0x67, 0xae,
-	0xcd, 0x99, 0xba, 0xf3, 0x1a, 0x5d, 0xc2, 0x21, 0xdb, 0xb0, 0x3e, 0xf3, 0xb9, 0xcd, 0xdd, 0xb1,
-	0x06, 0xe7, 0xe8, 0xc8, 0x35, 0x9a, 0x85, 0x49, 0x0b, 0xaa, 0x13, 0xcb, 0x1d, 0x87, 0xd6, 0x98,
-	0xa1, 0xf7, 0xd6, 0x68, 0x44, 0xcb, 0xb7, 0xb2, 0xc0, 0xf6, 0xbd, 0x67, 0xf2, 0x40, 0x5e, 0x28,
-	0x0e, 0xbc, 0x10, 0x2f, 0x57, 0x2a, 0x71, 0x09, 0x47, 0xee, 0x65, 0x7b, 0xdc, 0x45, 0x5d, 0xaa,
-	0xab, 0x8d, 0x68, 0x72, 0x17, 0x1a, 0xf2, 0xb9, 0xc7, 0x2f, 0x78, 0xc0, 0x4f, 0xf9, 0x84, 0x0b,
-	0x75, 0xa9, 0xab, 0x74, 0x01, 0x27, 0xb7, 0x61, 0x55, 0x1e, 0x93, 0x3d, 0xf2, 0x1c, 0x7e, 0xc6,
-	0x99, 0x8f, 0xd7, 0x9b, 0xa7, 0x69, 0xb0, 0xed, 0x40, 0x3d, 0x79, 0x2f, 0x64, 0x03, 0x56, 0x4f,
-	0x0e, 0x3e, 0x1d, 0x0e, 0xba, 0xbb, 0x87, 0x4f, 0x3e, 0x3a, 0x3e, 0xee, 0x35, 0xae, 0x91, 0x06,
-	0xd4, 0x7b, 0x83, 0x8f, 0x06, 0x23, 0x83, 0xe4, 0xc8, 0x0a, 0x54, 0x86, 0x7d, 0xfa, 0xc9, 0xa0,
-	0xdb, 0x6f, 0xe4, 0xc9, 0x1a, 0x40, 0x97, 0x1e, 0x7f, 0xbf, 0xf7, 0x64, 0xff, 0xf1, 0x51, 0xaf,
-	0x51, 0x20, 0x04, 0xd6, 0xba, 0xf4, 0xd3, 0x93, 0xd1, 0x71, 0xf7, 0x31, 0xa5, 0xfd, 0xa3, 0xee,
-	0xa7, 0x8d, 0x62, 0xfb, 0x2d, 0x28, 0xab, 0xfb, 0x23, 0xeb, 0xb0, 0xb2, 0x3f, 0xf8, 0x41, 0xbf,
-	0xf7, 0xe4, 0x84, 0xca, 0xe5, 0xb8, 0xfb, 0xa3, 0x5d, 0xfa, 0xb0, 0x3f, 0xd2, 0x48, 0xbe, 0xf5,
-	0x97, 0x32, 0x14, 0xa5, 0x9b, 0x92, 0x4d, 0x28, 0x09, 0x2e, 0x26, 0x4c, 0x07, 0x0a, 0x45, 0x90,
-	0x2d, 0x58, 0x71, 0xa4, 0xda, 0x38, 0xfa, 0x20, 0x9a, 0x5b, 0x8d, 0x26, 0x21, 0x72, 0x07, 0xd6,
-	0x66, 0xbe, 0x67, 0xb3, 0x20, 0xe0, 0xee, 0x58, 0xea, 0x16, 0xad, 0xaa, 0x46, 0x33, 0xa8, 0xdc,
-	0x1f, 0x95, 0x81, 0x26, 0x54, 0xa4, 0x8a, 0x90, 0xd1, 0xc9, 0x0d, 0xce, 0x9e, 0x61, 0xb0, 0xaf,
-	0x52, 0x7c, 0x96, 0x98, 0xb0, 0xc6, 0xca, 0xcd, 0x6b, 0x14, 0x9f, 0xc9, 0x5b, 0x50, 0xe6, 0x53,
-	0x6b, 0xcc, 0x8c, 0x5b, 0x5f, 0x4f, 0xc5, 0x98, 0xce, 0x40, 0xf2, 0xa8, 0x16, 0x91, 0x9e, 0x6d,
-	0x5b, 0x82, 0x8d, 0x3d, 0x5f, 0x9a, 0x9c, 0xf6, 0xec, 0x18, 0x91, 0x47, 0x19, 0xfb, 0xd6, 0x54,
-	0x39, 0x73, 0x9e, 0x2a, 0x82, 0xbc, 0x06, 0x35, 0xdb, 0x78, 0xb3, 0x76, 0xde, 0x18, 0x20, 0x1d,
-	0xa8, 0x78, 0x3a, 0x6e, 0xad, 0xe0, 0x09, 0x36, 0xd3, 0x27, 0xd0, 0x41, 0xcb, 0x08, 0x91, 0x37,
-	0xa0, 0x18, 0x3c, 0x0d, 0x83, 0x66, 0x5d, 0xa7, 0xc3, 0x94, 0xf0, 0xf0, 0x69, 0x48, 0x91, 0xdd,
-	0xfa, 0x5d, 0x0e, 0xca, 0x6a, 0x29, 0xaa, 0xc2, 0x9a, 0x1a, 0xfd, 0xe3, 0xf3, 0x0b, 0xa8, 0xff,
-	0x01, 0x54, 0x2f, 0x2c, 0x9f, 0x5b, 0xae, 0x08, 0x9a, 0x05, 0x7c, 0xd7, 0x6b, 0xcb, 0x0e, 0xd6,
-	0xf9, 0x44, 0x09, 0xd1, 0x48, 0xba, 0x75, 0x00, 0x15, 0x0d, 0x2e, 0x7d, 0xf5, 0x9b, 0x50, 0x42,
-	0x75, 0xea, 0x04, 0xb1, 0x54, 0xe1, 0x4a, 0xa2, 0xf5, 0xb3, 0x1c, 0x14, 0x86, 0x4f, 0x43, 0x19,
-	0x01, 0xf5, 0xee, 0x5d, 0x6f, 0x7a, 0xea, 0x61, 0xe9, 0xb2, 0x4a, 0x53, 0x98, 0xd4, 0xf2, 0xcc,
-	0xf7, 0x9c, 0xd0, 0x16, 0x3a, 0xf7, 0xd4, 0x68, 0x0c, 0x48, 0x6e, 0x10, 0xfa, 0xf6, 0xb9, 0xe5,
-	0x8f, 0x95, 0x1d, 0x15, 0x68, 0x0c, 0x48, 0x67, 0xfd, 0x2c, 0xb4, 0x5c, 0x21, 0x1d, 0xb1, 0x88,
-	0xcc, 0x88, 0x6e, 0x7d, 0x91, 0x83, 0x12, 0x1e, 0x4a, 0x4a, 0x9d, 0xf1, 0x09, 0x4b, 0x7c, 0x50,
-	0x44, 0x4b, 0x9e, 0xe7, 0xf3, 0x31, 0x77, 0xad, 0x89, 0x7e, 0x79, 0x44, 0x4b, 0xab, 0x98, 0x44,
-	0xef, 0xad, 0x51, 0x45, 0xc8, 0x14, 0x3b, 0x65, 0x0e, 0x0f, 0x55, 0x72, 0xab, 0x51, 0x4d, 0x49,
-	0xe9, 0x60, 0x6a, 0x4d, 0x26, 0x68, 0xb9, 0x35, 0xaa, 0x08, 0x34, 0x5d, 0xee, 0x9a, 0xc8, 0x85,
-	0xcf, 0xad, 0x5f, 0x14, 0x60, 0x2d, 0x9d, 0xda, 0x96, 0xea, 0xfb, 0x01, 0x14, 0x45, 0x1c, 0xd1,
-	0x6f, 0x5f, 0x91, 0x15, 0x23, 0x12, 0xe3, 0x3a, 0xae, 0x20, 0x77, 0xa0, 0xe2, 0xb3, 0x31, 0x9a,
-	0xa6, 0xb4, 0x80, 0xb5, 0x9d, 0xba, 0xcc, 0x75, 0xb2, 0x20, 0xed, 0x7a, 0x0e, 0xa3, 0x86, 0x49,
-	0xde, 0x83, 0x6a, 0xc0, 0xfc, 0x0b, 0x6e, 0x33, 0x93, 0x7b, 0x6f, 0x5e, 0xf9, 0x16, 0x25, 0x47,
-	0xa3, 0x05, 0xad, 0xcf, 0x73, 0x50, 0xd1, 0xe8, 0xd2, 0xe3, 0x47, 0xee, 0x9d, 0x4f, 0xba, 0xf7,
-	0x3d, 0xd8, 0x60, 0x81, 0xe0, 0x53, 0x4b, 0x30, 0xa7, 0xc7, 0x26, 0xfc, 0x82, 0xf9, 0x73, 0xad,
-	0xdf, 0x45, 0x06, 0xb9, 0x0f, 0xd7, 0x2d, 0x47, 0xf9, 0x9b, 0x35, 0x91, 0x66, 0x76, 0x92, 0x08,
-	0x18, 0xcb, 0x58, 0xed, 0x77, 0xa1, 0x9e, 0x54, 0x88, 0x8c, 0x6f, 0x87, 0xc7, 0x32, 0x9a, 0x9e,
-	0x0c, 0xba, 0x0f, 0x1f, 0x9f, 0x34, 0xae, 0x65, 0x43, 0x60, 0xae, 0xf5, 0xcb, 0x1c, 0x14, 0x46,
-	0xd6, 0x25, 0xa6, 0x62, 0xeb, 0x12, 0x03, 0xbf, 0xfa, 0x0e, 0x43, 0x92, 0x7b, 0x00, 0xc2, 0xba,
-	0xa4, 0x5a, 0xa5, 0xf9, 0x25, 0x2a, 0x4d, 0xf0, 0xa5, 0x8b, 0x0a, 0xeb, 0xd2, 0x9c, 0x02, 0x3f,
-	0xae, 0x4a, 0x93, 0x90, 0x0c, 0x47, 0x33, 0xe6, 0xdb, 0xcc, 0x15, 0xd2, 0x9d, 0x8a, 0x18, 0x73,
-	0x12, 0x08, 0xc6, 0x00, 0x55, 0x9c, 0x5c, 0x11, 0x84, 0x37, 0xa1, 0x78, 0x6e, 0x05, 0xe7, 0xca,
-	0x62, 0x0f, 0xae, 0x51, 0xa4, 0xc8, 0x6d, 0xa8, 0x3b, 0x3c, 0xc0, 0xd6, 0x43, 0x1e, 0x4a, 0xa9,
-	0xf5, 0xe0, 0x1a, 0x4d, 0xa1, 0xe4, 0x2e, 0xac, 0xeb, 0x57, 0xf5, 0x34, 0x8c, 0x16, 0x9b, 0x3f,
-	0xc8, 0xd1, 0x2c, 0x83, 0xdc, 0xd1, 0x49, 0x2c, 0x92, 0x94, 0x66, 0x5c, 0x3c, 0xc8, 0xd1, 0x34,
-	0xbc, 0x57, 0x86, 0xa2, 0x6c, 0x75, 0xf6, 0x00, 0xaa, 0xe6, 0x5d, 0xed, 0xdf, 0x03, 0x94, 0x54,
-	0xa3, 0x71, 0x1b, 0x56, 0x55, 0xcd, 0xb3, 0xeb, 0x38, 0x3e, 0x0b, 0x02, 0xfd, 0x2d, 0x69, 0x50,
-	0x7a, 0xba, 0x02, 0xf6, 0x99, 0xb1, 0x99, 0x18, 0x20, 0x6f, 0x41, 0x35, 0x48, 0x6a, 0x54, 0xd6,
-	0x71, 0xb8, 0x7b, 0x64, 0xa8, 0x34, 0x12, 0x20, 0xff, 0x0d, 0x15, 0x6c, 0x09, 0x06, 0x3d, 0x5d,
-	0x9e, 0x60, 0x31, 0x6b, 0x30, 0xf2, 0x00, 0x6a, 0x51, 0xef, 0xa5, 0x9b, 0x8a, 0xe7, 0xd5, 0x2f,
-	0xb1, 0x30, 0xb9, 0x05, 0x25, 0x59, 0xbb, 0x9a, 0x82, 0x73, 0x45, 0x1f, 0x01, 0xab, 0x5a, 0xc5,
-	0x21, 0xdb, 0x50, 0x99, 0x59, 0x73, 0x6c, 0x7c, 0x54, 0x23, 0xb1, 0xa6, 0x85, 0x4e, 0x14, 0x4a,
-	0x0d, 0x5b, 0x5a, 0x81, 0x6f, 0x49, 0x5f, 0x7b, 0xc8, 0xe6, 0x2a, 0x29, 0xd5, 0x69, 0x02, 0x21,
-	0x3b, 0xb0, 0x69, 0x4d, 0x04, 0xf3, 0x5d, 0x4b, 0x30, 0x59, 0x24, 0x58, 0xb6, 0x18, 0xb8, 0x67,
-	0x9e, 0xae, 0x4a, 0x96, 0xf2, 0x92, 0x75, 0x22, 0xa4, 0xea, 0xc4, 0xd6, 0x1f, 0x73, 0x50, 0x8d,
-	0x0c, 0xf0, 0x06, 0x94, 0xa5, 0xb2, 0x46, 0x9e, 0xbe, 0x0a, 0x4d, 0xc9, 0xe5, 0x96, 0xbe, 0x23,
-	0x15, 0x0c, 0x0d, 0x29, 0x3d, 0xdc, 0x96, 0x51, 0x56, 0xb9, 0x2a, 0x3e, 0x63, 0xc4, 0x13, 0x96,
-	0x60, 0x3a, 0x10, 0x2a, 0x02, 0x8d, 0xdb, 0x0b, 0x84, 0x35, 0x41, 0x1b, 0x54, 0xc1, 0x30, 0x81,
-	0xc8, 0xe0, 0xa4, 0xbb, 0x63, 0xb4, 0xa6, 0x85, 0xe0, 0xa4, 0x99, 0x32, 0x77, 0xe8, 0x97, 0x1f,
-	0x79, 0x02, 0xd3, 0x3c, 0x56, 0xcf, 0x49, 0xac, 0xf5, 0x9b, 0x82, 0xae, 0x55, 0xb6, 0x60, 0x65,
-	0xa2, 0x02, 0xd7, 0x81, 0xf4, 0x0b, 0xf5, 0x55, 0x49, 0x28, 0x95, 0x2a, 0xf2, 0xa8, 0x9a, 0x88,
-	0x96, 0x47, 0x36, 0xcf, 0xff, 0xff, 0x7f, 0x58, 0x1b, 0x16, 0x69, 0x02, 0x21, 0xf7, 0xe2, 0x54,
-	0xaf, 0x32, 0x2a, 0x49, 0x5c, 0xfc, 0x42, 0xa2, 0xdf, 0x83, 0xb5, 0x74, 0xa3, 0x12, 0xd5, 0xc8,
-	0x89, 0x45, 0x99, 0xd6, 0x26, 0xb3, 0x42, 0xaa, 0x7b, 0xca, 0xa6, 0x9e, 0x56, 0x1f, 0x3e, 0xcb,
-	0x6f, 0x54, 0x9d, 0x8a, 0xd4, 0x93, 0x29, 0x86, 0x92, 0x10, 0x56, 0x5e, 0xca, 0xb8, 0x8c, 0xa7,
-	0x55, 0x74, 0xe5, 0x95, 0x42, 0x5b, 0x3b, 0xcf, 0x2d, 0x31, 0x36, 0xa1, 0x74, 0x61, 0x4d, 0x42,
-	0xa6, 0x4d, 0x40, 0x11, 0xad, 0xf7, 0x5f, 0x28, 0x67, 0x35, 0xa1, 0xa2, 0x13, 0x84, 0x31, 0x20,
-	0x4d, 0xb6, 0xbe, 0xcc, 0x43, 0x45, 0xbb, 0x00, 0x79, 0x5b, 0xa6, 0x50, 0x71, 0xee, 0x39, 0xb8,
-	0x76, 0x6d, 0xe7, 0x95, 0xb4, 0x8b, 0xc8, 0xae, 0xe3, 0xdc, 0x73, 0xa8, 0x16, 0x92, 0x91, 0x21,
-	0xea, 0xc2, 0x4c, 0x85, 0x10, 0x01, 0xd2, 0x96, 0xad, 0x29, 0x06, 0xa7, 0x02, 0x5e, 0x9c, 0xa6,
-	0xb0, 0x7a, 0x3b, 0xb7, 0xb8, 0x2b, 0x03, 0x93, 0xb6, 0xd0, 0x18, 0x48, 0x5a, 0x7a, 0x29, 0x6d,
-	0xe9, 0xd8, 0xb5, 0x39, 0x8c, 0x4d, 0x87, 0x58, 0x52, 0xe9, 0xcc, 0x9d, 0xc2, 0xa4, 0x4c, 0x74,
-	0x80, 0x87, 0x6c, 0x8e, 0x6a, 0xae, 0xd3, 0x14, 0x86, 0x1e, 0xe3, 0x71, 0x17, 0xcd, 0x49, 0x7a,
-	0x8c, 0xc7, 0xdd, 0xf6, 0x03, 0x28, 0xab, 0x6f, 0x23, 0xd7, 0x61, 0x7d, 0xb7, 0xd7, 0xa3, 0xfd,
-	0xe1, 0xf0, 0x09, 0xed, 0x7f, 0xef, 0x71, 0x7f, 0x38, 0x6a, 0x5c, 0x23, 0x00, 0xe5, 0xde, 0x80,
-	0xf6, 0xbb, 0xa3, 0x46, 0x8e, 0xac, 0x42, 0xed, 0xd1, 0x71, 0xaf, 0x4f, 0x77, 0x47, 0xfd, 0x5e,
-	0x23, 0xdf, 0xfe, 0x7b, 0x0e, 0x36, 0x16, 0x87, 0x2e, 0x4d, 0xa8, 0x78, 0x12, 0x1c, 0xf4, 0x4c,
-	0xca, 0xd2, 0x64, 0x3a, 0xc6, 0xe5, 0x5f, 0x26, 0xc6, 0x2d, 0x1a, 0x51, 0x61, 0x99, 0x11, 0xc9,
-	0xf6, 0xcc, 0x67, 0x9f, 0x85, 0x2c, 0x10, 0xcc, 0xd9, 0x55, 0x17, 0xa0, 0xf2, 0x72, 0x16, 0x26,
-	0xdf, 0x85, 0x86, 0x0a, 0x6b, 0xc3, 0x78, 0x8c, 0xa1, 0xca, 0x8d, 0x46, 0x87, 0xa6, 0x19, 0x74,
-	0x41, 0xb2, 0xfd, 0xf3, 0x1c, 0xac, 0xa8, 0xe1, 0x15, 0xfb, 0x31, 0xb3, 0xc5, 0xbf, 0xe5, 0x9b,
-	0x65, 0x6d, 0xce, 0xc7, 0xc6, 0xbb, 0x37, 0x3a, 0x7b, 0x5c, 0xc8, 0xfb, 0x8a, 0x8f, 0x85, 0xec,
-	0xf6, 0x57, 0x05, 0x58, 0xcf, 0x1c, 0x98, 0x7c, 0x98, 0x18, 0x8c, 0xe4, 0xf0, 0x9d, 0xb7, 0xb3,
-	0x1f, 0xd5, 0x19, 0xf9, 0x96, 0x1b, 0x58, 0xb6, 0xbc, 0xb2, 0x25, 0xb3, 0x12, 0x59, 0xe2, 0x1a,
-	0x51, 0x3c, 0x76, 0x9d, 0xc6, 0x40, 0xeb, 0xaf, 0x79, 0xb8, 0xbe, 0x64, 0x7d, 0x22, 0xe2, 0x0d,
-	0xe3, 0x61, 0x4e, 0x12, 0xc2, 0x84, 0x6a, 0xb2, 0x89, 0xd9, 0x37, 0x02, 0x16, 0x4c, 0xb8, 0xb0,
-	0xc4, 0x84, 0xdb, 0x50, 0xd7, 0x1b, 0x8e, 0xb0, 0x06, 0x51, 0x5e, 0x94, 0xc2, 0xc8, 0x01, 0xd4,
-	0xc4, 0x79, 0x38, 0x3d, 0x75, 0x2d, 0x3e, 0xd1, 0xc9, 0xf4, 0xee, 0x8b, 0x28, 0x40, 0x37, 0x0c,
-	0xf1, 0xe2, 0xd6, 0x4f, 0x4d, 0xbd, 0x6e, 0x6a, 0xe6, 0x5c, 0x5c, 0x33, 0xc7, 0xd5, 0x75, 0x3e,
-	0x59, 0x5d, 0xc7, 0xb5, 0x78, 0x21, 0x5b, 0x8b, 0xab, 0xca, 0xbd, 0x98, 0xac, 0xdc, 0x93, 0xb5,
-	0x7e, 0x29, 0x5d, 0xeb, 0xb7, 0x4f, 0xa0, 0x91, 0xbd, 0x74, 0x99, 0x16, 0xb8, 0x3b, 0x0b, 0xc5,
-	0xc0, 0x75, 0xd8, 0xa5, 0x9e, 0xbb, 0x24, 0x90, 0xe7, 0x5f, 0x5c, 0xfb, 0x4f, 0x65, 0x68, 0x2c,
-	0x8c, 0x36, 0x23, 0xe3, 0x75, 0xd2, 0xc6, 0xeb, 0x44, 0x53, 0xb9, 0x7c, 0x62, 0x2a, 0x97, 0x32,
-	0xe8, 0xc2, 0xcb, 0x18, 0xf4, 0x11, 0x34, 0x66, 0xe7, 0xf3, 0x80, 0xdb, 0xd6, 0x24, 0xaa, 0xb2,
-	0xd5, 0x1c, 0xb6, 0xbd, 0x30, 0x87, 0xed, 0x9c, 0x64, 0x24, 0xe9, 0xc2, 0x5a, 0xf2, 0x10, 0xd6,
-	0x1d, 0x3e, 0xe6, 0x22, 0xb1, 0x9d, 0xf2, 0xe0, 0x5b, 0x8b, 0xdb, 0xf5, 0xd2, 0x82, 0x34, 0xbb,
-	0x92, 0xdc, 0x87, 0xf2, 0xcc, 0x9a, 0x7b, 0xa1, 0xd0, 0x83, 0xd9, 0xe6, 0x92, 0x23, 0x21, 0x9f,
-	0x6a, 0x39, 0xf2, 0x1d, 0x58, 0xcf, 0xc4, 0x05, 0x5d, 0x5c, 0x2d, 0x06, 0x90, 0xac, 0x20, 0xa6,
-	0x29, 0x4f, 0x30, 0x13, 0x87, 0xe5, 0x33, 0xf9, 0x11, 0xdc, 0xb0, 0xfd, 0xf9, 0x4c, 0x78, 0xb6,
-	0x1e, 0x21, 0x45, 0x5f, 0x55, 0xc3, 0xaf, 0xda, 0x5e, 0x3c, 0x51, 0x77, 0xa9, 0x3c, 0xbd, 0x62,
-	0x9f, 0xd6, 0x08, 0x1a, 0x59, 0xb5, 0x62, 0x72, 0x94, 0x29, 0x94, 0xf9, 0xe6, 0xf2, 0x35, 0x29,
-	0x63, 0xae, 0xf0, 0x2d, 0xfb, 0x29, 0x77, 0xc7, 0x47, 0xe1, 0xf4, 0x94, 0x99, 0x34, 0x97, 0x41,
-	0x5b, 0x1f, 0xc0, 0x7a, 0x46, 0xbb, 0xa4, 0x01, 0x85, 0xd0, 0x9f, 0xe8, 0x0d, 0xe5, 0xa3, 0x34,
-	0xf3, 0x99, 0x15, 0x04, 0xcf, 0x3c, 0xdf, 0x31, 0x2d, 0xad, 0xa1, 0x5b, 0xef, 0xc3, 0x8d, 0xe5,
-	0x1f, 0x22, 0x8b, 0x74, 0x11, 0x7b, 0x69, 0x14, 0x5c, 0xd3, 0xa0, 0x6c, 0xec, 0xcb, 0xea, 0x6e,
-	0xa2, 0x98, 0x99, 0x7b, 0x6e, 0xcc, 0xc4, 0x39, 0x18, 0x2e, 0xd8, 0x4d, 0x15, 0x96, 0x69, 0x90,
-	0xdc, 0x85, 0x86, 0x02, 0xf6, 0x19, 0x3b, 0x61, 0xfe, 0xde, 0x5c, 0x30, 0x9d, 0xce, 0x17, 0xf0,
-	0xf6, 0x6f, 0x73, 0xb0, 0x9e, 0x1d, 0xf6, 0x5f, 0xed, 0x57, 0xdf, 0x3e, 0x29, 0xbc, 0x0b, 0xa0,
-	0xde, 0x3d, 0x7c, 0x6e, 0x6a, 0x48, 0x08, 0x91, 0x5b, 0x50, 0x51, 0xe6, 0x17, 0x68, 0x6f, 0xab,
-	0x68, 0xfb, 0xa4, 0x06, 0x6f, 0xff, 0xa1, 0x08, 0x65, 0x85, 0x91, 0x1d, 0xd3, 0x00, 0xf4, 0xe2,
-	0xe4, 0x41, 0xf4, 0x02, 0xfd, 0x47, 0x72, 0x68, 0x42, 0xea, 0x1b, 0x92, 0xc5, 0xd7, 0x05, 0x00,
-	0x9a, 0x12, 0x8e, 0x33, 0x40, 0x2e, 0x9b, 0x01, 0xbe, 0x71, 0xe6, 0xdf, 0x81, 0x9a, 0x7a, 0x1e,
-	0x72, 0xd3, 0x74, 0x2d, 0xfa, 0x5b, 0x2c, 0xf2, 0x4d, 0x6d, 0xd7, 0x6b, 0x50, 0xc3, 0xc7, 0x23,
-	0x59, 0x34, 0xaa, 0xf8, 0x1b, 0x03, 0xd2, 0x6a, 0x91, 0x90, 0xef, 0x2a, 0xe3, 0x51, 0x23, 0x3a,
-	0x95, 0xab, 0x24, 0x3f, 0x5b, 0x6e, 0x49, 0x99, 0xd4, 0x3d, 0x57, 0x5f, 0xe6, 0x9e, 0xa5, 0xed,
-	0x5c, 0x30, 0x5f, 0x26, 0x97, 0x9a, 0xea, 0x99, 0x34, 0x29, 0x39, 0x9f, 0x85, 0x56, 0x62, 0xcc,
-	0x6b, 0xc8, 0xec, 0x18, 0x6e, 0x05, 0xb9, 0xa9, 0x31, 0xdc, 0x6d, 0x58, 0x75, 0xb4, 0x6f, 0x0d,
-	0x67, 0x8c, 0x39, 0xcd, 0x3a, 0xca, 0xa4, 0x41, 0x59, 0x44, 0xd9, 0x61, 0x20, 0xbc, 0x29, 0xf3,
-	0xf5, 0x2c, 0xa5, 0xb9, 0x8a, 0x72, 0x59, 0x58, 0xa6, 0x3a, 0x9f, 0x5d, 0x70, 0xf6, 0xac, 0xb9,
-	0xa6, 0x52, 0x9d, 0xa2, 0xda, 0x5f, 0xe5, 0xa0, 0xa2, 0x7f, 0x67, 0x4a, 0xeb, 0x20, 0xf7, 0x32,
-	0x3a, 0xd8, 0x84, 0x92, 0x3d, 0xb1, 0xf8, 0xd4, 0xa4, 0x57, 0x24, 0x16, 0x7d, 0xb7, 0xb0, 0xcc,
-	0x77, 0xff, 0x07, 0x6a, 0x5e, 0x28, 0x66, 0x1e, 0x77, 0x85, 0x31, 0xfb, 0x5a, 0xe7, 0x58, 0x23,
-	0x34, 0xe6, 0x91, 0x0e, 0x90, 0x80, 0xf9, 0xdc, 0x9a, 0xf0, 0x9f, 0x30, 0xc7, 0x8c, 0xbd, 0xd1,
-	0x12, 0xea, 0x74, 0x09, 0xa7, 0xfd, 0xeb, 0x12, 0x6c, 0x2c, 0xfc, 0x08, 0xf7, 0x2f, 0x7c, 0x64,
-	0x22, 0x48, 0xe4, 0xd3, 0x41, 0x42, 0xf6, 0xac, 0xbe, 0x37, 0xf3, 0x02, 0xe6, 0xec, 0x99, 0x1e,
-	0x37, 0x81, 0x60, 0xab, 0x1e, 0xff, 0x40, 0xa8, 0x8a, 0x8a, 0x04, 0x42, 0xde, 0x8d, 0x32, 0x9a,
-	0xaa, 0x80, 0xfe, 0x6b, 0xf1, 0xc7, 0xc3, 0x6c, 0x4a, 0xbb, 0x0f, 0xd7, 0x23, 0xfb, 0x8d, 0x7c,
-	0x4a, 0x75, 0x75, 0x75, 0xba, 0x8c, 0xd5, 0xfa, 0xbc, 0xf0, 0xb2, 0xb1, 0xf7, 0x16, 0x94, 0xb1,
-	0x5c, 0x51, 0x33, 0xab, 0xd4, 0xb5, 0x68, 0x06, 0xd9, 0x83, 0x15, 0xf5, 0xeb, 0x69, 0x28, 0x66,
-	0xa1, 0xd0, 0x5e, 0xbe, 0x75, 0xe5, 0xf1, 0x3b, 0x4a, 0x8e, 0x26, 0x17, 0x91, 0x1e, 0xd4, 0xf5,
-	0x2f, 0xb9, 0x6a, 0x93, 0xe2, 0x0b, 0x6e, 0x92, 0x5a, 0x45, 0x3e, 0x86, 0xf5, 0xe8, 0xab, 0xf5,
-	0x46, 0xa5, 0x17, 0xdc, 0x28, 0xbb, 0xb0, 0xc5, 0xa1, 0xac, 0x77, 0x6d, 0x42, 0x59, 0xf9, 0xa4,
-	0xca, 0x0b, 0x07, 0xd7, 0xa8, 0xa6, 0x49, 0x2b, 0xee, 0x00, 0xcd, 0xa0, 0x2c, 0xea, 0x01, 0xe3,
-	0x9e, 0x32, 0x9f, 0xec, 0x29, 0xf7, 0x36, 0x60, 0x5d, 0xad, 0x3e, 0xf6, 0xb5, 0xf5, 0xb7, 0x79,
-	0x64, 0xa3, 0x89, 0xdf, 0x74, 0xbf, 0xbd, 0x8d, 0xb6, 0xa0, 0x6a, 0x4f, 0xb4, 0x1d, 0xea, 0xe4,
-	0x6d, 0xe8, 0xf6, 0xc7, 0x50, 0x35, 0xf7, 0x27, 0xab, 0x9a, 0xf3, 0x78, 0xd2, 0xa1, 0xe6, 0x7f,
-	0x9b, 0x50, 0xe2, 0x58, 0xaa, 0xaa, 0xf9, 0x86, 0x22, 0xe2, 0x76, 0x5e, 0xe5, 0x53, 0x45, 0xb4,
-	0x7f, 0x95, 0x87, 0xb2, 0xfa, 0x9d, 0xf9, 0x3f, 0xd8, 0x50, 0x91, 0x3e, 0x6c, 0xa8, 0x11, 0x5f,
-	0xa2, 0x41, 0xd0, 0xe6, 0xf3, 0xaa, 0xfe, 0x19, 0x3c, 0xd9, 0x3b, 0x0c, 0xdc, 0x33, 0x8f, 0x2e,
-	0xae, 0x58, 0x36, 0x2d, 0x69, 0xbd, 0x07, 0xeb, 0x99, 0x95, 0xd8, 0x57, 0x5c, 0x72, 0x27, 0xea,
-	0x2b, 0x2e, 0xb9, 0x93, 0x1e, 0x76, 0x44, 0xda, 0xd9, 0x81, 0x1b, 0x9f, 0xa0, 0x6d, 0xee, 0xcb,
-	0xe6, 0x40, 0x06, 0x25, 0x33, 0xba, 0xb8, 0x52, 0x59, 0xed, 0x2f, 0x73, 0x90, 0x1f, 0xf4, 0xa4,
-	0xe9, 0xcc, 0x58, 0x82, 0xaf, 0x29, 0x89, 0x9f, 0x5b, 0xae, 0x33, 0x31, 0x83, 0x11, 0x4d, 0x91,
-	0x37, 0xa0, 0x32, 0x0b, 0x4f, 0x9f, 0xb2, 0x79, 0xa0, 0x9d, 0x6f, 0xa5, 0x33, 0xe8, 0x75, 0x4e,
-	0x14, 0x44, 0x0d, 0x4f, 0x46, 0xa0, 0xd3, 0x48, 0x87, 0xa8, 0xa2, 0x3a, 0x4d, 0x20, 0xad, 0x0f,
-	0xa0, 0xa2, 0xd7, 0x48, 0x13, 0xe2, 0x0e, 0x53, 0x93, 0x2e, 0x95, 0xf4, 0x23, 0x5a, 0x1e, 0x5f,
-	0x2f, 0xd2, 0xc5, 0x83, 0x21, 0xdb, 0xff, 0xc8, 0x41, 0x2d, 0x2e, 0x9a, 0xef, 0x41, 0x25, 0x60,
-	0xea, 0x3a, 0xd4, 0x88, 0x86, 0xc4, 0xff, 0x70, 0xd0, 0x19, 0x2a, 0x0e, 0x35, 0x22, 0xb2, 0x7c,
-	0x8d, 0x6a, 0x10, 0x59, 0xa2, 0x05, 0x7a, 0xf3, 0x0c, 0xda, 0xfe, 0x02, 0x7f, 0x32, 0x50, 0x6b,
-	0x56, 0xa0, 0x72, 0x38, 0x18, 0x8e, 0x06, 0x47, 0x1f, 0x35, 0xae, 0x91, 0x1a, 0x94, 0x8e, 0x69,
-	0xaf, 0x4f, 0x1b, 0x39, 0x72, 0x03, 0x08, 0x3e, 0x3e, 0xe9, 0x1e, 0x1f, 0xed, 0x0f, 0xe8, 0xa3,
-	0xdd, 0xd1, 0xe0, 0xf8, 0xa8, 0x91, 0x27, 0xaf, 0xc0, 0x86, 0xc2, 0xf7, 0x1f, 0x1f, 0xee, 0x0f,
-	0x0e, 0x0f, 0x1f, 0xf5, 0x8f, 0x46, 0x8d, 0x02, 0xd9, 0x84, 0x86, 0x11, 0x7f, 0x74, 0x72, 0xd8,
-	0x47, 0xe1, 0xa2, 0xdc, 0xbc, 0x37, 0x18, 0x9e, 0x3c, 0x1e, 0xf5, 0x1b, 0x25, 0xb9, 0xa3, 0x26,
-	0x9e, 0xd0, 0xfe, 0xf0, 0xf8, 0xf0, 0x31, 0x0a, 0x95, 0x09, 0x40, 0x99, 0xf6, 0xf1, 0x57, 0xd3,
-	0x4a, 0x9b, 0xc1, 0xaa, 0xfc, 0x3e, 0xe6, 0x98, 0x7f, 0x92, 0x68, 0x43, 0x45, 0xb7, 0xb9, 0xda,
-	0x7f, 0xe3, 0xff, 0xb7, 0x31, 0x8c, 0xc8, 0x07, 0xf3, 0x09, 0x1f, 0x4c, 0xd5, 0x67, 0x85, 0x4c,
-	0x7d, 0xb6, 0x57, 0xfc, 0x61, 0x7e, 0x76, 0x7a, 0x5a, 0x46, 0xdf, 0xf9, 0xdf, 0x7f, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0xfe, 0x1e, 0xab, 0x81, 0x37, 0x24, 0x00, 0x00,
+	// 3586 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x5a, 0x3b, 0x90, 0x23, 0x49,
+	0x5a, 0x1e, 0xbd, 0xa5, 0x5f, 0xea, 0x6e, 0x75, 0xce, 0x63, 0x85, 0x62, 0xb9, 0x9d, 0x51, 0xcc,
+	0x2d, 0x73, 0xbb, 0x7b, 0xb5, 0xbb, 0x0d, 0x41, 0x6c, 0x70, 0x70, 0x77, 0xdd, 0x92, 0x7a, 0x5b,
+	0x37, 0xfd, 0x10, 0x29, 0xcd, 0xc2, 0xe0, 0x34, 0xd5, 0x55, 0xd9, 0xea, 0x64, 0x4a, 0x55, 0xda,
+	0xaa, 0xac, 0xde, 0x6e, 0xb0, 0xf0, 0xce, 0x20, 0x02, 0x03, 0xe3, 0x8c, 0x33, 0x30, 0xc1, 0xc0,
+	0xc1, 0xc2, 0x00, 0x0b, 0x07, 0x0b, 0x07, 0xe3, 0xe2, 0x3c, 0x1c, 0x3c, 0x1c, 0x7c, 0x1c, 0xe2,
+	0xcf, 0x47, 0xbd, 0xa4, 0x9e, 0xc7, 0x02, 0x71, 0x5e, 0xfd, 0xdf, 0xff, 0x67, 0x2a, 0xeb, 0xcf,
+	0xff, 0x5d, 0x82, 0x1d, 0x27, 0xf0, 0x45, 0x68, 0x3b, 0x22, 0xb2, 0x56, 0x61, 0x20, 0x82, 0x3e,
+	0x71, 0x82, 0xd8, 0x17, 0xe1, 0xad, 0x13, 0xb8, 0xcc, 0x60, 0x1f, 0x2c, 0x82, 0x60, 0xe1, 0xb1,
+	0x4f, 0x25, 0x75, 0x11, 0x5f, 0x7e, 0x2a, 0xf8, 0x92, 0x45, 0xc2, 0x5e, 0xae, 0x94, 0xc0, 0xe0,
+	0x6f, 0x6a, 0xb0, 0x4b, 0xb9, 0x63, 0x87, 0x2e, 0xb7, 0xfd, 0xa1, 0xde, 0x91, 0x7c, 0x06, 0xdb,
+	0xd7, 0xcc, 0x77, 0x83, 0xf0, 0x98, 0x47, 0x82, 0xfb, 0x8b, 0xa8, 0x57, 0x7a, 0x5c, 0x79, 0xd6,
+	0xde, 0x6b, 0x5a, 0x1a, 0xa0, 0x05, 0x3e, 0xf9, 0x10, 0xe0, 0x22, 0xbe, 0x65, 0xe1, 0x59, 0xe8,
+	0xb2, 0xb0, 0x57, 0x7e, 0x5c, 0x7a, 0xd6, 0xde, 0xab, 0x5b, 0x92, 0xa2, 0x19, 0x0e, 0x39, 0x86,
+	0xf7, 0xd4, 0x4a, 0x49, 0x0e, 0x03, 0xff, 0x92, 0x87, 0x4b, 0x5b, 0xf0, 0xc0, 0xef, 0x55, 0xe4,
+	0x22, 0x62, 0xad, 0x71, 0xe8, 0x5d, 0x4b, 0xc8, 0x04, 0x1e, 0x65, 0x58, 0x87, 0xb1, 0x77, 0xc9,
+	0x3d, 0x6f, 0xc9, 0x7c, 0xd1, 0xab, 0xca, 0xf3, 0xee, 0x5a, 0x45, 0x06, 0xbd, 0x63, 0x01, 0x19,
+	0xc1, 0x83, 0xf4, 0x98, 0xc3, 0x60, 0xb9, 0xf2, 0x98, 0x3c, 0x55, 0x4d, 0x9e, 0xaa, 0x6b, 0x15,
+	0x70, 0xba, 0x51, 0x9a, 0x0c, 0xa0, 0xe1, 0xf2, 0x68, 0x15, 0x0b, 0xd6, 0xab, 0xcb, 0x85, 0x4d,
+	0x6b, 0xa4, 0x68, 0x6a, 0x18, 0xe4, 0xc7, 0xb0, 0xab, 0x1f, 0x29, 0x8b, 0x02, 0x2f, 0x96, 0x3f,
+	0xd3, 0xd0, 0x2f, 0x3f, 0x2a, 0x72, 0xe8, 0xba, 0x70, 0x66, 0x87, 0x7d, 0xc7, 0x61, 0x2b, 0x61,
+	0xfb, 0x0e, 0xeb, 0x35, 0xf3, 0x3b, 0xa4, 0x1c, 0xba, 0x2e, 0x4c, 0x3e, 0x80, 0x7a, 0xc8, 0x2e,
+	0x63, 0xdf, 0xed, 0xb5, 0xe4, 0xb2, 0x86, 0x45, 0x25, 0x49, 0x35, 0x4c, 0x3e, 0x02, 0x88, 0xf8,
+	0xc2, 0xb7, 0x45, 0x1c, 0xb2, 0xa8, 0x07, 0x52, 0x9b, 0x60, 0xcd, 0x0c, 0x44, 0x33, 0x5c, 0xf2,
+	0x08, 0xea, 0x2c, 0x0c, 0x83, 0x30, 0xea, 0xb5, 0x1f, 0x57, 0x9e, 0xb5, 0xa8, 0xa6, 0xc8, 0x31,
+	0x3c, 0xd4, 0xbf, 0x7c, 0x68, 0x7b, 0xde, 0x85, 0xed, 0xbc, 0x9a, 0xda, 0xb7, 0x41, 0x2c, 0x7a,
+	0x1d, 0xf9, 0x9b, 0x8f, 0xac, 0xd1, 0x26, 0x2e, 0xdd, 0xbc, 0x68, 0xf0, 0x77, 0x0f, 0xa1, 0xa1,
+	0xcd, 0x8d, 0x10, 0xa8, 0x46, 0x5e, 0xbc, 0xe8, 0x95, 0x1e, 0x97, 0x9e, 0xb5, 0xa8, 0x7c, 0x26,
+	0x1f, 0x40, 0x53, 0x5d, 0xed, 0x64, 0xa4, 0xed, 0xaf, 0x62, 0x4d, 0x46, 0x34, 0x01, 0xc9, 0xf7,
+	0xa1, 0xb9, 0x64, 0xc2, 0x76, 0x6d, 0x61, 0x6b, 0x5b, 0xdb, 0x35, 0xe6, 0x6c, 0x9d, 0x68, 0x06,
+	0x4d, 0x44, 0xc8, 0x13, 0xa8, 0x72, 0xc1, 0x96, 0xbd, 0xaa, 0x14, 0xdd, 0x4a, 0x44, 0x27, 0x82,
+	0x2d, 0xa9, 0x64, 0x91, 0x7d, 0xd8, 0x89, 0xae, 0xf8, 0x6a, 0xc5, 0xfd, 0xc5, 0xd9, 0x0a, 0x6f,
+	0x26, 0xea, 0xd5, 0xa4, 0xa6, 0xde, 0x4b, 0xa4, 0x67, 0x39, 0x3e, 0x2d, 0xca, 0x93, 0x01, 0xd4,
+	0x84, 0x7d, 0xc3, 0xa2, 0x5e, 0x5d, 0x2e, 0xec, 0x24, 0x0b, 0xe7, 0xf6, 0x0d, 0x55, 0x2c, 0xf2,
+	0x3d, 0x68, 0x38, 0x41, 0xbc, 0xc2, 0xed, 0x1b, 0x52, 0x6a, 0x27, 0x91, 0x1a, 0x4a, 0x9c, 0x1a,
+	0x3e, 0xf9, 0x0e, 0xc0, 0x32, 0x70, 0x59, 0x68, 0x0b, 0xbc, 0x8e, 0xa6, 0xbc, 0x8e, 0x0c, 0x42,
+	0x2c, 0x20, 0x82, 0x85, 0xcb, 0x68, 0xdf, 0x77, 0x87, 0x81, 0xef, 0x72, 0x75, 0xe8, 0x96, 0x54,
+	0xe3, 0x06, 0x0e, 0x19, 0x40, 0x47, 0x19, 0xc4, 0x34, 0xf0, 0xb8, 0x73, 0xdb, 0x03, 0x29, 0x99,
+	0xc3, 0x48, 0x0f, 0x1a, 0x82, 0x45, 0xc2, 0x67, 0xa2, 0xd7, 0x7e, 0x5c, 0x7a, 0xd6, 0xa4, 0x86,
+	0xec, 0xff, 0x7d, 0x0d, 0x9a, 0x46, 0xb3, 0x28, 0x76, 0xcd, 0xc2, 0x08, 0x8d, 0x1d, 0xaf, 0x6d,
+	0x8b, 0x1a, 0x92, 0x1c, 0x40, 0xc7, 0xc4, 0xb2, 0xf9, 0xed, 0x8a, 0xc9, 0xdb, 0xdb, 0xde, 0xfb,
+	0xce, 0xda, 0xe5, 0x58, 0xc3, 0x8c, 0x14, 0xcd, 0xad, 0x21, 0x9f, 0x41, 0xfd, 0x32, 0xc0, 0xb0,
+	0x20, 0xaf, 0x76, 0x7b, 0xaf, 0xb7, 0xbe, 0xfa, 0x50, 0xf2, 0xa9, 0x96, 0x23, 0x7b, 0x50, 0x67,
+	0x37, 0x2b, 0x1e, 0xde, 0xea, 0x1b, 0xee, 0x5b, 0x2a, 0x56, 0x5a, 0x26, 0x56, 0x5a, 0x73, 0x13,
+	0x2b, 0xa9, 0x96, 0x44, 0xf5, 0xd9, 0xd2, 0x89, 0x98, 0x3b, 0x8c, 0xc3, 0x90, 0xf9, 0x0e, 0x67,
+	0xea, 0xce, 0x5b, 0x74, 0x03, 0x87, 0x3c, 0x83, 0x9d, 0x55, 0xc8, 0x1d, 0xee, 0x2f, 0x34, 0x78,
+	0x2b, 0xc3, 0x42, 0x8b, 0x16, 0x61, 0xd2, 0x87, 0xa6, 0x67, 0xfb, 0x8b, 0xd8, 0x5e, 0x30, 0x19,
+	0x0b, 0x5a, 0x34, 0xa1, 0xf1, 0x57, 0x59, 0xe4, 0x84, 0xc1, 0x37, 0x78, 0xa0, 0x20, 0x16, 0x47,
+	0x41, 0x2c, 0x2f, 0x17, 0x95, 0xb8, 0x81, 0x83, 0x7b, 0x39, 0x01, 0xf7, 0xa5, 0x2e, 0xd5, 0xd5,
+	0x26, 0x34, 0xf9, 0x08, 0xba, 0xf8, 0x3c, 0xe2, 0xd7, 0x3c, 0xe2, 0x17, 0xdc, 0xe3, 0x42, 0x5d,
+	0xea, 0x16, 0x5d, 0xc3, 0xc9, 0x53, 0xd8, 0xc2, 0x63, 0xb2, 0x93, 0xc0, 0xe5, 0x97, 0x9c, 0x85,
+	0xf2, 0x7a, 0xcb, 0x34, 0x0f, 0x92, 0xdf, 0x81, 0x9d, 0x82, 0xc3, 0x6a, 0xff, 0xee, 0x16, 0xfd,
+	0x9b, 0x16, 0x05, 0x07, 0x2e, 0x74, 0xb2, 0x77, 0x4a, 0x76, 0x61, 0x6b, 0x7a, 0xf4, 0x72, 0x36,
+	0x19, 0xee, 0x1f, 0x9f, 0x7f, 0x79, 0x76, 0x36, 0xea, 0xde, 0x23, 0x5d, 0xe8, 0x8c, 0x26, 0x5f,
+	0x4e, 0xe6, 0x06, 0x29, 0x91, 0x36, 0x34, 0x66, 0x63, 0xfa, 0xd5, 0x64, 0x38, 0xee, 0x96, 0xc9,
+	0x36, 0xc0, 0x90, 0x9e, 0xfd, 0xc1, 0xe8, 0xfc, 0xf0, 0xc5, 0xe9, 0xa8, 0x5b, 0x21, 0x04, 0xb6,
+	0x87, 0xf4, 0xe5, 0x74, 0x7e, 0x36, 0x7c, 0x41, 0xe9, 0xf8, 0x74, 0xf8, 0xb2, 0x5b, 0x1d, 0x7c,
+	0x0c, 0x75, 0x75, 0xf7, 0x64, 0x07, 0xda, 0x87, 0x93, 0x3f, 0x1c, 0x8f, 0xce, 0xa7, 0x14, 0x97,
+	0xcb, 0xdd, 0x4f, 0xf6, 0xe9, 0xf3, 0xf1, 0x5c, 0x23, 0xe5, 0xfe, 0xbf, 0xd7, 0xa1, 0x8a, 0x2e,
+	0x4e, 0x1e, 0x40, 0x4d, 0x70, 0xe1, 0x31, 0x1d, 0x64, 0x14, 0x41, 0x1e, 0x43, 0xdb, 0x45, 0x95,
+	0x73, 0xe9, 0xbf, 0xd2, 0x54, 0x5b, 0x34, 0x0b, 0x91, 0x0f, 0x61, 0x7b, 0x15, 0x06, 0x0e, 0x8b,
+	0x22, 0xee, 0x2f, 0xf0, 0x5e, 0xa4, 0x45, 0xb6, 0x68, 0x01, 0xc5, 0xfd, 0xa5, 0x22, 0xa5, 0xf9,
+	0x55, 0xa9, 0x22, 0x30, 0xb2, 0xf9, 0xd1, 0xe5, 0x37, 0x32, 0xed, 0x34, 0xa9, 0x7c, 0x46, 0x4c,
+	0xd8, 0x0b, 0x15, 0x22, 0x5a, 0x54, 0x3e, 0x93, 0x8f, 0xa1, 0xce, 0x97, 0xf6, 0x82, 0x99, 0x90,
+	0x70, 0x3f, 0x17, 0x9f, 0xac, 0x09, 0xf2, 0xa8, 0x16, 0xc1, 0xa8, 0xe0, 0xd8, 0x82, 0x2d, 0x82,
+	0x90, 0xb3, 0x24, 0x2a, 0xa4, 0x08, 0x1e, 0x65, 0x11, 0xda, 0x4b, 0x15, 0x08, 0xca, 0x54, 0x11,
+	0xe4, 0x7d, 0x68, 0x39, 0x26, 0x12, 0x68, 0xc7, 0x4f, 0x01, 0x62, 0x41, 0x23, 0xd0, 0x31, 0xaf,
+	0x2d, 0x4f, 0xf0, 0x20, 0x7f, 0x02, 0x1d, 0xf0, 0x8c, 0x10, 0xf9, 0x2e, 0x54, 0xa3, 0x57, 0x71,
+	0xd4, 0xeb, 0xe8, 0xc4, 0x9c, 0x13, 0x9e, 0xbd, 0x8a, 0xa9, 0x64, 0xf7, 0xff, 0xb9, 0x04, 0x75,
+	0xb5, 0x54, 0xaa, 0xc2, 0x5e, 0x1a, 0xfd, 0xcb, 0xe7, 0xb7, 0x50, 0xff, 0x17, 0xd0, 0xbc, 0xb6,
+	0x43, 0x6e, 0xfb, 0x22, 0xea, 0x55, 0xe4, 0x6f, 0xbd, 0xbf, 0xe9, 0x60, 0xd6, 0x57, 0x4a, 0x88,
+	0x26, 0xd2, 0xfd, 0x23, 0x68, 0x68, 0x70, 0xe3, 0x4f, 0x7f, 0x0f, 0x6a, 0x52, 0x9d, 0x3a, 0xb9,
+	0x6c, 0x54, 0xb8, 0x92, 0xe8, 0xff, 0x79, 0x09, 0x2a, 0xb3, 0x57, 0x31, 0x46, 0x4f, 0xbd, 0xfb,
+	0x30, 0x58, 0x5e, 0x04, 0xb2, 0x88, 0xda, 0xa2, 0x39, 0x0c, 0xb5, 0xbc, 0x0a, 0x03, 0x37, 0x76,
+	0x84, 0xce, 0x5b, 0x2d, 0x9a, 0x02, 0xc8, 0x8d, 0xe2, 0xd0, 0xb9, 0xb2, 0xc3, 0x85, 0xb2, 0xa3,
+	0x0a, 0x4d, 0x01, 0x74, 0xf4, 0xaf, 0x63, 0xdb, 0x17, 0xe8, 0xc4, 0x55, 0xc9, 0x4c, 0xe8, 0xfe,
+	0xcf, 0x4a, 0x50, 0x93, 0x87, 0x42, 0xa9, 0x4b, 0xee, 0xb1, 0xcc, 0x0b, 0x25, 0x34, 0xf2, 0x82,
+	0x90, 0x2f, 0xb8, 0x6f, 0x7b, 0xfa, 0xc7, 0x13, 0x1a, 0xad, 0xc2, 0x4b, 0x7e, 0xb7, 0x45, 0x15,
+	0x81, 0xc9, 0x7e, 0xc9, 0x5c, 0x1e, 0xab, 0xc4, 0xd8, 0xa2, 0x9a, 0x42, 0xe9, 0x68, 0x69, 0x7b,
+	0x9e, 0xb4, 0xdc, 0x16, 0x55, 0x84, 0x34, 0x5d, 0xee, 0x9b, 0xa8, 0x27, 0x9f, 0xfb, 0x7f, 0x51,
+	0x81, 0xed, 0x7c, 0x5a, 0xdc, 0xa8, 0xef, 0x2f, 0xa0, 0x2a, 0xd2, 0x6c, 0xf0, 0xf4, 0x8e, 0x8c,
+	0x9a, 0x90, 0x32, 0x27, 0xc8, 0x15, 0xe4, 0x43, 0x68, 0x84, 0x6c, 0x21, 0x4d, 0x13, 0x2d, 0x60,
+	0x7b, 0xaf, 0x63, 0x0d, 0x55, 0x69, 0x3c, 0x0c, 0x5c, 0x46, 0x0d, 0x93, 0xfc, 0x00, 0x9a, 0x11,
+	0x0b, 0xaf, 0xb9, 0xc3, 0x4c, 0xde, 0xfe, 0xe0, 0xce, 0x5f, 0x51, 0x72, 0x34, 0x59, 0xd0, 0xff,
+	0xab, 0x12, 0x34, 0x34, 0xba, 0xf1, 0xf8, 0x89, 0x7b, 0x97, 0xb3, 0xee, 0xfd, 0x09, 0xec, 0xb2,
+	0x48, 0xf0, 0xa5, 0x2d, 0x98, 0x3b, 0x62, 0x1e, 0xbf, 0x66, 0xe1, 0xad, 0xd6, 0xef, 0x3a, 0x83,
+	0x7c, 0x06, 0xf7, 0x6d, 0x57, 0xf9, 0x9b, 0xed, 0xa1, 0x99, 0x4d, 0x33, 0x01, 0x63, 0x13, 0x6b,
+	0xf0, 0x39, 0x74, 0xb2, 0x0a, 0xc1, 0xf8, 0x76, 0x7c, 0x86, 0xd1, 0x74, 0x3a, 0x19, 0x3e, 0x7f,
+	0x31, 0xed, 0xde, 0x2b, 0x86, 0xc0, 0x52, 0xff, 0x2f, 0x4b, 0x50, 0x99, 0xdb, 0x37, 0x32, 0x8d,
+	0xdb, 0x37, 0xb8, 0x4a, 0xbf, 0x87, 0x21, 0xc9, 0x27, 0x00, 0xc2, 0xbe, 0xa1, 0x5a, 0xa5, 0xe5,
+	0x0d, 0x2a, 0xcd, 0xf0, 0xd1, 0x45, 0x85, 0x7d, 0x63, 0x4e, 0x21, 0x5f, 0xae, 0x49, 0xb3, 0x10,
+	0x86, 0xa3, 0x15, 0x0b, 0x1d, 0xe6, 0x0b, 0x7b, 0xa1, 0xde, 0xa6, 0x4c, 0x33, 0x88, 0x8c, 0x01,
+	0xaa, 0xb0, 0xb9, 0x23, 0x08, 0x3f, 0x80, 0xea, 0x95, 0x1d, 0x5d, 0x29, 0x8b, 0x3d, 0xba, 0x47,
+	0x25, 0x45, 0x9e, 0x42, 0xc7, 0xe5, 0x91, 0x6c, 0x82, 0xf0, 0x50, 0x4a, 0xad, 0x47, 0xf7, 0x68,
+	0x0e, 0x25, 0x1f, 0xc1, 0x8e, 0xfe, 0xa9, 0x91, 0x86, 0xa5, 0xc5, 0x96, 0x8f, 0x4a, 0xb4, 0xc8,
+	0x20, 0x1f, 0xea, 0x04, 0x98, 0x48, 0xa2, 0x19, 0x57, 0x8f, 0x4a, 0x34, 0x0f, 0x1f, 0xd4, 0xa1,
+	0x8a, 0x4d, 0xd7, 0x01, 0x40, 0xd3, 0xfc, 0xd6, 0xe0, 0x17, 0x6d, 0xa8, 0xa9, 0x96, 0xe7, 0x29,
+	0x6c, 0xa9, 0x7a, 0x69, 0xdf, 0x75, 0x43, 0x16, 0x45, 0xfa, 0x5d, 0xf2, 0x20, 0x7a, 0xba, 0x02,
+	0x0e, 0x99, 0xb1, 0x99, 0x14, 0x20, 0x1f, 0x43, 0x33, 0xca, 0x6a, 0x14, 0x6b, 0x40, 0xb9, 0x7b,
+	0x62, 0xa8, 0x34, 0x11, 0x20, 0xbf, 0x0e, 0x0d, 0xd9, 0x9c, 0x4c, 0x46, 0xbd, 0x6a, 0x5a, 0x08,
+	0x1b, 0x8c, 0x7c, 0x01, 0xad, 0xa4, 0x0b, 0xec, 0xd5, 0xde, 0x58, 0xfb, 0xa4, 0xc2, 0xe4, 0x09,
+	0xd4, 0xb0, 0xee, 0x35, 0xc5, 0x6a, 0x5b, 0x1f, 0x41, 0x56, 0xc4, 0x8a, 0x43, 0x9e, 0x41, 0x63,
+	0x65, 0xdf, 0xca, 0x16, 0x4c, 0xb5, 0x34, 0xdb, 0x5a, 0x68, 0xaa, 0x50, 0x6a, 0xd8, 0x68, 0x05,
+	0xa1, 0x8d, 0xbe, 0xf6, 0x9c, 0xdd, 0xaa, 0xa4, 0xd4, 0xa1, 0x19, 0x84, 0xec, 0xc1, 0x03, 0xdb,
+	0x13, 0x2c, 0xf4, 0x6d, 0xc1, 0xb0, 0x48, 0xb0, 0x1d, 0x31, 0xf1, 0x2f, 0x03, 0x5d, 0xd1, 0x6c,
+	0xe4, 0x65, 0x6b, 0x4c, 0xc8, 0xd5, 0x98, 0xfd, 0x7f, 0x2b, 0x41, 0x33, 0x31, 0xc0, 0x47, 0x50,
+	0x47, 0x65, 0xcd, 0x03, 0x7d, 0x15, 0x9a, 0xc2, 0xe5, 0xb6, 0xbe, 0x23, 0x15, 0x0c, 0x0d, 0x89,
+	0x1e, 0xee, 0x60, 0x94, 0x55, 0xae, 0x2a, 0x9f, 0x65, 0xc4, 0x13, 0xb6, 0x60, 0x3a, 0x10, 0x2a,
+	0x42, 0x1a, 0x77, 0x10, 0x09, 0xdb, 0x93, 0x36, 0xa8, 0x82, 0x61, 0x06, 0xc1, 0xe0, 0xa4, 0xfb,
+	0x74, 0x69, 0x4d, 0x6b, 0xc1, 0x49, 0x33, 0x31, 0x77, 0xe8, 0x1f, 0x3f, 0x0d, 0x84, 0x4c, 0xf3,
+	0xb2, 0xf2, 0xce, 0x62, 0xfd, 0xbf, 0xad, 0xe8, 0x5a, 0xe5, 0x31, 0xb4, 0x3d, 0x15, 0xb8, 0x8e,
+	0xd0, 0x2f, 0xd4, 0x5b, 0x65, 0xa1, 0x5c, 0xaa, 0x28, 0x4b, 0xd5, 0x24, 0x34, 0x1e, 0xd9, 0x3c,
+	0xff, 0xf6, 0x6f, 0xc9, 0xba, 0xb2, 0x4a, 0x33, 0x08, 0xf9, 0x24, 0x4d, 0xf5, 0x2a, 0xa3, 0x92,
+	0xcc, 0xc5, 0xaf, 0x25, 0xfa, 0x03, 0xd8, 0xce, 0x37, 0x39, 0x49, 0x7d, 0x9d, 0x59, 0x54, 0x68,
+	0x8b, 0x0a, 0x2b, 0x50, 0xdd, 0x4b, 0xb6, 0x0c, 0xb4, 0xfa, 0xe4, 0x33, 0xbe, 0xa3, 0xea, 0x72,
+	0x50, 0x4f, 0xa6, 0x18, 0xca, 0x42, 0xb2, 0xf2, 0x52, 0xc6, 0x65, 0x3c, 0xad, 0xa1, 0x2b, 0xaf,
+	0x1c, 0xda, 0xdf, 0x7b, 0x6d, 0x89, 0xf1, 0x00, 0x6a, 0xd7, 0xb6, 0x17, 0x33, 0x6d, 0x02, 0x8a,
+	0xe8, 0xff, 0xf0, 0xad, 0x72, 0x56, 0x0f, 0x1a, 0x3a, 0x41, 0x18, 0x03, 0xd2, 0x64, 0xff, 0x5f,
+	0x2a, 0xd0, 0xd0, 0x2e, 0x40, 0xbe, 0x8f, 0x29, 0x54, 0x5c, 0x05, 0xae, 0x5c, 0xbb, 0xbd, 0xf7,
+	0x30, 0xef, 0x22, 0xd8, 0xb1, 0x5c, 0x05, 0x2e, 0xd5, 0x42, 0x18, 0x19, 0x92, 0x0e, 0xce, 0x54,
+	0x08, 0x09, 0x80, 0xb6, 0x6c, 0x2f, 0x65, 0x70, 0xaa, 0xc8, 0x8b, 0xd3, 0x14, 0xae, 0x72, 0xae,
+	0x6c, 0xee, 0x63, 0x60, 0xd2, 0x16, 0x9a, 0x02, 0x59, 0x4b, 0xaf, 0xe5, 0x2d, 0x5d, 0x76, 0x7c,
+	0x2e, 0x63, 0xcb, 0x99, 0x2c, 0xa9, 0x74, 0xe6, 0xce, 0x61, 0x28, 0x93, 0x1c, 0xe0, 0x39, 0xbb,
+	0x95, 0x6a, 0xee, 0xd0, 0x1c, 0x26, 0x3d, 0x26, 0xe0, 0x7e, 0xaf, 0xa9, 0x3d, 0x26, 0xe0, 0xbe,
+	0x6c, 0x87, 0x6c, 0x9f, 0x79, 0x27, 0x69, 0x8b, 0xda, 0x92, 0xd7, 0x58, 0x84, 0xb1, 0xe5, 0xc9,
+	0x43, 0x32, 0x48, 0x80, 0x0c, 0x12, 0x1b, 0x38, 0x68, 0xde, 0x12, 0xc5, 0xe0, 0xd9, 0x96, 0x7a,
+	0x48, 0xe8, 0xc1, 0x09, 0xd4, 0x95, 0x46, 0xc9, 0x7d, 0xd8, 0xd9, 0x1f, 0x8d, 0xe8, 0x78, 0x36,
+	0x3b, 0xa7, 0xe3, 0xdf, 0x7f, 0x31, 0x9e, 0xcd, 0xbb, 0xf7, 0x08, 0x40, 0x7d, 0x34, 0xa1, 0xe3,
+	0xe1, 0xbc, 0x5b, 0x22, 0x5b, 0xd0, 0x3a, 0x39, 0x1b, 0x8d, 0xe9, 0xfe, 0x7c, 0x3c, 0xea, 0x96,
+	0x51, 0x7e, 0xba, 0xff, 0xf2, 0x64, 0x7c, 0x3a, 0x3f, 0x1f, 0x1e, 0xed, 0x9f, 0x9e, 0x8e, 0x8f,
+	0xbb, 0x95, 0xc1, 0x5f, 0x97, 0x61, 0x77, 0x7d, 0x12, 0xd5, 0x83, 0x46, 0x80, 0xe0, 0x64, 0x64,
+	0xb2, 0xa7, 0x26, 0xf3, 0xe1, 0xb6, 0xfc, 0x2e, 0xe1, 0x76, 0xdd, 0x9e, 0x2b, 0x9b, 0xec, 0x19,
+	0xd5, 0x1a, 0xb2, 0xaf, 0x63, 0x16, 0x09, 0xe6, 0xee, 0x2b, 0x5b, 0x50, 0x25, 0x42, 0x11, 0x26,
+	0xbf, 0x0b, 0x5d, 0x15, 0x61, 0x67, 0xe9, 0x6c, 0x47, 0x55, 0x3e, 0x5d, 0x8b, 0xe6, 0x19, 0x74,
+	0x4d, 0x32, 0x73, 0x9e, 0x89, 0x7f, 0x1d, 0xa0, 0x91, 0xd7, 0x73, 0xe7, 0xd1, 0xe8, 0xe0, 0xa7,
+	0x25, 0x68, 0xab, 0xc9, 0x1f, 0xfb, 0x13, 0xe6, 0x88, 0xff, 0x17, 0xdd, 0x60, 0x3b, 0xc1, 0x17,
+	0x26, 0x20, 0xed, 0x5a, 0x07, 0x5c, 0xa0, 0x89, 0xa5, 0xc7, 0x97, 0xec, 0xc1, 0x2f, 0x2b, 0xb0,
+	0x53, 0x78, 0x31, 0xf2, 0xe3, 0xcc, 0x1c, 0xa8, 0x24, 0x7f, 0xf3, 0x69, 0xf1, 0xe5, 0xad, 0x79,
+	0x68, 0xfb, 0x91, 0xed, 0xe0, 0xd5, 0x6e, 0x18, 0x0d, 0x61, 0x55, 0x6e, 0x44, 0xe5, 0xb1, 0x3b,
+	0x34, 0x05, 0xfa, 0xff, 0x51, 0x86, 0xfb, 0x1b, 0xd6, 0x67, 0x82, 0xf4, 0x2c, 0x9d, 0x5d, 0x65,
+	0x21, 0xdc, 0x37, 0x49, 0x80, 0x66, 0xdf, 0x04, 0x58, 0xf3, 0xba, 0xca, 0x06, 0xaf, 0x1b, 0x40,
+	0x47, 0x6f, 0x38, 0x97, 0x65, 0x93, 0x72, 0xfc, 0x1c, 0x46, 0x8e, 0xa0, 0x25, 0xae, 0xe2, 0xe5,
+	0x85, 0x6f, 0x73, 0x4f, 0xe7, 0xff, 0x8f, 0xde, 0x46, 0x01, 0xba, 0xc7, 0x49, 0x17, 0xf7, 0xff,
+	0xcc, 0xb4, 0x18, 0xa6, 0xcc, 0x2f, 0xa5, 0x65, 0x7e, 0xda, 0x10, 0x94, 0xb3, 0x0d, 0x41, 0xda,
+	0x3e, 0x54, 0x8a, 0xed, 0x83, 0x6a, 0x36, 0xaa, 0xd9, 0x66, 0x23, 0xdb, 0x9e, 0xd4, 0xf2, 0xed,
+	0xc9, 0x60, 0x0a, 0xdd, 0xe2, 0xa5, 0x63, 0x26, 0xe3, 0xfe, 0x2a, 0x16, 0x13, 0xdf, 0x65, 0x37,
+	0x7a, 0xcc, 0x94, 0x41, 0x5e, 0x7f, 0x71, 0x83, 0x5f, 0xd4, 0xa1, 0xbb, 0x36, 0x17, 0x4e, 0x8c,
+	0xd7, 0xcd, 0x1b, 0xaf, 0x9b, 0x0c, 0x21, 0xcb, 0x99, 0x21, 0x64, 0xce, 0xa0, 0x2b, 0xef, 0x62,
+	0xd0, 0xa7, 0xd0, 0x5d, 0x5d, 0xdd, 0x46, 0xdc, 0xb1, 0xbd, 0xa4, 0x31, 0x50, 0x43, 0xec, 0xc1,
+	0xda, 0x10, 0xdb, 0x9a, 0x16, 0x24, 0xe9, 0xda, 0x5a, 0xf2, 0x1c, 0xc7, 0x32, 0x0b, 0x2e, 0x32,
+	0xdb, 0x29, 0x4f, 0x7f, 0xb2, 0xbe, 0xdd, 0x28, 0x2f, 0x48, 0x8b, 0x2b, 0x71, 0xba, 0xb6, 0x52,
+	0xa3, 0x5b, 0x35, 0xd5, 0xee, 0x6d, 0x38, 0x92, 0xe4, 0x53, 0x2d, 0x87, 0x53, 0xa1, 0x42, 0xfc,
+	0xd0, 0xf5, 0xe0, 0x7a, 0xa0, 0x29, 0x0a, 0xca, 0xcc, 0x1a, 0x08, 0x66, 0x52, 0x07, 0x3e, 0x93,
+	0x3f, 0x86, 0x47, 0x4e, 0x78, 0xbb, 0x12, 0x81, 0xa3, 0x27, 0x66, 0xc9, 0x5b, 0xb5, 0xe4, 0x5b,
+	0x3d, 0x5b, 0x3f, 0xd1, 0x70, 0xa3, 0x3c, 0xbd, 0x63, 0x9f, 0xfe, 0x1c, 0xba, 0x45, 0xb5, 0xca,
+	0x7c, 0x8e, 0x59, 0x9f, 0x85, 0xe6, 0xf2, 0x35, 0x89, 0xb1, 0x10, 0xc7, 0x56, 0xaf, 0xb8, 0xbf,
+	0x38, 0x8d, 0x97, 0x17, 0xcc, 0x64, 0xe6, 0x02, 0xda, 0xff, 0x11, 0xec, 0x14, 0xb4, 0x4b, 0xba,
+	0x50, 0x89, 0x43, 0x4f, 0x6f, 0x88, 0x8f, 0x2a, 0x7b, 0x45, 0xd1, 0x37, 0x41, 0xe8, 0x9a, 0x2e,
+	0xdc, 0xd0, 0xfd, 0x1f, 0xc2, 0xa3, 0xcd, 0x2f, 0x82, 0x7d, 0x85, 0x48, 0xbd, 0x34, 0x09, 0xae,
+	0x79, 0x10, 0x67, 0x11, 0x75, 0x75, 0x37, 0x49, 0xcc, 0x2c, 0xbd, 0x36, 0x66, 0xe2, 0xbe, 0xea,
+	0x12, 0xf7, 0x73, 0xb5, 0x70, 0x1e, 0xc4, 0x41, 0xa2, 0x02, 0x0e, 0x19, 0x9b, 0xb2, 0xf0, 0xe0,
+	0x56, 0x30, 0x5d, 0x81, 0xac, 0xe1, 0x83, 0x7f, 0x2c, 0xc1, 0x4e, 0xf1, 0x4b, 0xc9, 0xdd, 0x7e,
+	0xf5, 0xed, 0x93, 0xc2, 0xe7, 0x00, 0xea, 0xb7, 0x67, 0xaf, 0x4d, 0x0d, 0x19, 0x21, 0xf2, 0x04,
+	0x1a, 0xca, 0xfc, 0x22, 0xed, 0x6d, 0x0d, 0x6d, 0x9f, 0xd4, 0xe0, 0x83, 0x7f, 0xad, 0x42, 0x5d,
+	0x61, 0x64, 0xcf, 0xf4, 0x2c, 0xa3, 0x34, 0x79, 0x10, 0xbd, 0xc0, 0xa2, 0x09, 0x87, 0x66, 0xa4,
+	0xde, 0x90, 0x2c, 0xfe, 0xab, 0x02, 0x40, 0x73, 0xc2, 0x69, 0x06, 0x28, 0x15, 0x33, 0xc0, 0x1b,
+	0x3f, 0x71, 0x58, 0xd0, 0x52, 0xcf, 0x33, 0x6e, 0xfa, 0xc4, 0x75, 0x7f, 0x4b, 0x45, 0xde, 0xd4,
+	0x29, 0xbe, 0x0f, 0x2d, 0xf9, 0x78, 0x8a, 0x75, 0xae, 0x8a, 0xbf, 0x29, 0x80, 0x56, 0x2b, 0x09,
+	0xfc, 0xad, 0xba, 0x3c, 0x6a, 0x42, 0xe7, 0x72, 0x15, 0xf2, 0x8b, 0x15, 0x22, 0xca, 0xe4, 0xee,
+	0xb9, 0xf9, 0x2e, 0xf7, 0x8c, 0xb6, 0x73, 0xcd, 0x42, 0x4c, 0x2e, 0x2d, 0xd5, 0xe6, 0x69, 0x12,
+	0x39, 0x5f, 0xc7, 0x76, 0x66, 0xaa, 0x6d, 0xc8, 0xe2, 0xe4, 0xb0, 0x2d, 0xb9, 0x59, 0x08, 0xed,
+	0xde, 0xd5, 0xbe, 0x35, 0x5b, 0x31, 0xe6, 0xca, 0x31, 0xf6, 0x16, 0xcd, 0x83, 0x58, 0x6c, 0x39,
+	0x71, 0x24, 0x82, 0x25, 0x0b, 0xf5, 0xf8, 0xa7, 0xb7, 0x25, 0xe5, 0x8a, 0x30, 0xa6, 0xba, 0x90,
+	0x5d, 0x73, 0xf6, 0x4d, 0x6f, 0x5b, 0xa5, 0x3a, 0x45, 0x0d, 0x7e, 0x59, 0x82, 0x86, 0x9e, 0x8c,
+	0xe7, 0x75, 0x50, 0x7a, 0x17, 0x1d, 0x3c, 0x80, 0x9a, 0xe3, 0xd9, 0x7c, 0x69, 0xd2, 0xab, 0x24,
+	0xd6, 0x7d, 0xb7, 0xb2, 0xc9, 0x77, 0x7f, 0x03, 0x5a, 0x41, 0x2c, 0x56, 0x01, 0xf7, 0x85, 0x31,
+	0xfb, 0x96, 0x75, 0xa6, 0x11, 0x9a, 0xf2, 0xb0, 0x0c, 0x8f, 0x58, 0xc8, 0x6d, 0x8f, 0xff, 0x29,
+	0x73, 0xcd, 0xa4, 0x5e, 0x5a, 0x42, 0x87, 0x6e, 0xe0, 0x0c, 0xfe, 0xa1, 0x0e, 0xbb, 0x6b, 0x5f,
+	0x30, 0xff, 0x17, 0x2f, 0x99, 0x09, 0x12, 0xe5, 0x7c, 0x90, 0xc0, 0x36, 0x3b, 0x0c, 0x56, 0x41,
+	0xc4, 0xdc, 0x03, 0xd3, 0x96, 0x67, 0x10, 0xe4, 0x87, 0xc9, 0x09, 0x74, 0x51, 0x91, 0x41, 0xc8,
+	0xe7, 0x49, 0x46, 0x53, 0x15, 0xd0, 0xaf, 0xad, 0x7f, 0x79, 0x2d, 0xa6, 0xb4, 0xcf, 0xe0, 0x7e,
+	0x62, 0xbf, 0x89, 0x4f, 0xa9, 0x46, 0xb4, 0x43, 0x37, 0xb1, 0xf0, 0x10, 0xd2, 0xd5, 0xd4, 0x21,
+	0x55, 0x33, 0x9a, 0x41, 0xfa, 0x3f, 0xad, 0xbe, 0x6b, 0x6c, 0x7e, 0x02, 0x75, 0x59, 0xce, 0xa8,
+	0x31, 0x5c, 0xee, 0xda, 0x34, 0x83, 0x1c, 0x40, 0x5b, 0x7d, 0x9a, 0x8e, 0xc5, 0x2a, 0x16, 0x3a,
+	0x0a, 0x3c, 0xbe, 0xf3, 0xf5, 0x2c, 0x25, 0x47, 0xb3, 0x8b, 0xc8, 0x08, 0x3a, 0xfa, 0x33, 0xb9,
+	0xda, 0xa4, 0xfa, 0x96, 0x9b, 0xe4, 0x56, 0x91, 0x9f, 0xc0, 0x4e, 0xa2, 0x15, 0xbd, 0x51, 0xed,
+	0x2d, 0x37, 0x2a, 0x2e, 0xc4, 0xb7, 0x92, 0x0d, 0x9d, 0xde, 0xa7, 0xfe, 0xb6, 0x6f, 0x95, 0x59,
+	0x44, 0x3e, 0x85, 0x96, 0x24, 0xe5, 0xb5, 0x35, 0xee, 0x52, 0x74, 0x2a, 0xd3, 0xe7, 0x50, 0xd7,
+	0x4b, 0x7b, 0x50, 0x57, 0x81, 0x42, 0x25, 0xab, 0xa3, 0x7b, 0x54, 0xd3, 0xa4, 0x9f, 0x76, 0xd2,
+	0x66, 0xe0, 0x68, 0x80, 0x4c, 0x6f, 0x5e, 0xce, 0xf6, 0xe6, 0x07, 0xbb, 0xb0, 0xa3, 0x56, 0x9f,
+	0x85, 0xda, 0x25, 0x07, 0x3f, 0x2f, 0xc1, 0x8e, 0x7e, 0x19, 0xf3, 0x75, 0x8c, 0x7c, 0x0a, 0xf5,
+	0x95, 0xfa, 0xec, 0xaa, 0xe6, 0x04, 0xef, 0x15, 0x3f, 0xa8, 0x59, 0xea, 0x0b, 0x2c, 0xd5, 0x62,
+	0x58, 0x38, 0xb9, 0xf6, 0x6d, 0xa4, 0x07, 0x3c, 0xf2, 0x79, 0x30, 0x84, 0xba, 0x92, 0x22, 0x4d,
+	0xa8, 0x9e, 0x9e, 0x9d, 0xea, 0xaf, 0x5e, 0x74, 0x8c, 0x1f, 0xcc, 0xce, 0x0f, 0x5e, 0xbc, 0x1c,
+	0xd3, 0x6e, 0x09, 0x3f, 0x9b, 0xd1, 0xf1, 0xf1, 0x78, 0x7f, 0x36, 0x3e, 0xff, 0x6a, 0x7c, 0x3a,
+	0x3a, 0xa3, 0xdd, 0x32, 0x69, 0x41, 0x6d, 0x36, 0x3d, 0x9e, 0xcc, 0xbb, 0x95, 0xc1, 0x7f, 0x56,
+	0xe0, 0xe1, 0xc6, 0x8f, 0xf5, 0xbf, 0x12, 0xdf, 0x4e, 0xf5, 0x52, 0x7d, 0x3b, 0xbd, 0xa4, 0x5e,
+	0x53, 0x7b, 0x4b, 0xaf, 0xa9, 0xff, 0x5f, 0x78, 0x4d, 0xe3, 0x5b, 0x79, 0x8d, 0x89, 0x04, 0xcd,
+	0xd7, 0x47, 0x02, 0xfc, 0x60, 0x1c, 0x38, 0xaf, 0xe4, 0x07, 0x46, 0x95, 0x04, 0x13, 0x9a, 0xfc,
+	0x1e, 0x7e, 0xe4, 0x8d, 0x7d, 0xc1, 0xc2, 0x95, 0x1d, 0x8a, 0x5b, 0x69, 0xef, 0x70, 0xd7, 0x76,
+	0x6b, 0xa2, 0x03, 0x9e, 0x04, 0xf1, 0xcc, 0x3f, 0x46, 0xbe, 0xfd, 0x45, 0xe3, 0xe7, 0x68, 0x4f,
+	0x5f, 0xa6, 0xae, 0x6e, 0x0d, 0x3d, 0xf8, 0x09, 0x34, 0xcd, 0x55, 0xa0, 0xf5, 0x5e, 0xa5, 0xd3,
+	0x4b, 0xf9, 0x8c, 0x59, 0x8e, 0xcb, 0x5e, 0x4e, 0x99, 0xb4, 0x22, 0xd2, 0x11, 0x9d, 0x2a, 0x38,
+	0x15, 0x31, 0xf8, 0x79, 0x19, 0xea, 0xea, 0x5f, 0x2c, 0xbf, 0xc2, 0x89, 0x03, 0x19, 0xc3, 0xae,
+	0x1a, 0xdb, 0x67, 0x3a, 0x68, 0x1d, 0x3f, 0xdf, 0xd3, 0x7f, 0xb2, 0xc9, 0x36, 0xd7, 0x38, 0xb6,
+	0xa6, 0xeb, 0x2b, 0x36, 0x4d, 0x40, 0xfb, 0x3f, 0x80, 0x9d, 0xc2, 0x4a, 0x14, 0x13, 0x37, 0xdc,
+	0x4d, 0x1a, 0xef, 0x1b, 0xee, 0xe6, 0x07, 0x98, 0x89, 0x76, 0xf6, 0xe0, 0xd1, 0x57, 0xd2, 0xcc,
+	0x0e, 0xb9, 0xaf, 0xb2, 0xb6, 0x19, 0x47, 0xde, 0xa9, 0xac, 0xc1, 0x3f, 0x95, 0xa0, 0x3c, 0x19,
+	0x61, 0x18, 0x5b, 0xb1, 0x0c, 0x5f, 0x53, 0x88, 0x5f, 0xd9, 0xbe, 0xeb, 0x99, 0x61, 0xa7, 0xa6,
+	0xc8, 0x77, 0xa1, 0xb1, 0x8a, 0x2f, 0x5e, 0xe1, 0xc4, 0x4e, 0x65, 0x9f, 0xb6, 0x35, 0x19, 0x59,
+	0x53, 0x05, 0x51, 0xc3, 0x43, 0x37, 0xbf, 0x48, 0x74, 0x28, 0x55, 0xd4, 0xa1, 0x19, 0xa4, 0xff,
+	0x23, 0x68, 0xe8, 0x35, 0x68, 0x42, 0xdc, 0x65, 0x6a, 0x7a, 0xad, 0xaa, 0xe2, 0x84, 0xc6, 0xe3,
+	0xeb, 0x45, 0xba, 0xba, 0x36, 0xe4, 0xe0, 0xbf, 0x4b, 0xd0, 0x4a, 0xbb, 0xca, 0x4f, 0x70, 0x36,
+	0xab, 0xae, 0x43, 0x85, 0x53, 0x92, 0xfe, 0x9d, 0xc9, 0x9a, 0x29, 0x0e, 0x35, 0x22, 0xd8, 0xdf,
+	0x25, 0x45, 0x3a, 0xf6, 0x30, 0x91, 0xde, 0xbc, 0x80, 0x0e, 0x7e, 0x26, 0x3f, 0x03, 0xaa, 0x35,
+	0x6d, 0x68, 0x1c, 0x4f, 0x66, 0xf3, 0xc9, 0xe9, 0x97, 0xdd, 0x7b, 0x18, 0x3d, 0xcf, 0xe8, 0x48,
+	0x06, 0xd7, 0x47, 0x40, 0xe4, 0xe3, 0xf9, 0xf0, 0xec, 0xf4, 0x70, 0x42, 0x4f, 0xf6, 0xe7, 0x93,
+	0xb3, 0xd3, 0x6e, 0x99, 0x3c, 0x84, 0x5d, 0x85, 0x1f, 0xbe, 0x38, 0x3e, 0x9c, 0x1c, 0x1f, 0xe3,
+	0xa0, 0xb1, 0x5b, 0x21, 0x0f, 0xa0, 0x6b, 0xc4, 0x4f, 0xa6, 0xc7, 0x63, 0x29, 0x5c, 0xc5, 0xcd,
+	0x47, 0x93, 0xd9, 0xf4, 0xc5, 0x7c, 0xdc, 0xad, 0xe1, 0x8e, 0x9a, 0x38, 0xa7, 0xe3, 0xd9, 0xd9,
+	0xf1, 0x0b, 0x29, 0x54, 0xc7, 0x59, 0xa6, 0x0a, 0xec, 0xdd, 0xc6, 0x80, 0xc1, 0x16, 0xbe, 0x1f,
+	0x73, 0xcd, 0x9f, 0xa6, 0x06, 0xd0, 0xd0, 0x73, 0x20, 0xed, 0xbf, 0xe9, 0xbf, 0xf9, 0x0c, 0x23,
+	0xf1, 0xc1, 0x72, 0xc6, 0x07, 0x73, 0x0d, 0x4c, 0xa5, 0xd0, 0xc0, 0x1c, 0x54, 0xff, 0xa8, 0xbc,
+	0xba, 0xb8, 0xa8, 0x4b, 0xdf, 0xf9, 0xcd, 0xff, 0x19, 0x00, 0x86, 0xc6, 0x2a, 0x9f, 0x95, 0x28,
+	0x00, 0x00,
 }
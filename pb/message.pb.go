@@ -46,6 +46,15 @@ const (
 	Message_STORE                    Message_MessageType = 18
 	Message_BLOCK                    Message_MessageType = 19
 	Message_VENDOR_FINALIZED_PAYMENT Message_MessageType = 20
+	Message_CASE_HANDOVER            Message_MessageType = 21
+	Message_CASE_HANDOVER_RESPONSE   Message_MessageType = 22
+	Message_CASE_HANDOVER_RESOLUTION Message_MessageType = 23
+	Message_DISPUTE_FALLBACK         Message_MessageType = 24
+	Message_DISPUTE_PANEL_VOTE       Message_MessageType = 25
+	Message_RATCHET_CHAT             Message_MessageType = 26
+	Message_GROUP_MEMBERSHIP         Message_MessageType = 27
+	Message_POST_COMMENT             Message_MessageType = 28
+	Message_CHAT_CHANGE              Message_MessageType = 29
 	Message_ERROR                    Message_MessageType = 500
 )
 
@@ -71,6 +80,15 @@ var Message_MessageType_name = map[int32]string{
 	18:  "STORE",
 	19:  "BLOCK",
 	20:  "VENDOR_FINALIZED_PAYMENT",
+	21:  "CASE_HANDOVER",
+	22:  "CASE_HANDOVER_RESPONSE",
+	23:  "CASE_HANDOVER_RESOLUTION",
+	24:  "DISPUTE_FALLBACK",
+	25:  "DISPUTE_PANEL_VOTE",
+	26:  "RATCHET_CHAT",
+	27:  "GROUP_MEMBERSHIP",
+	28:  "POST_COMMENT",
+	29:  "CHAT_CHANGE",
 	500: "ERROR",
 }
 
@@ -96,6 +114,15 @@ var Message_MessageType_value = map[string]int32{
 	"STORE":                    18,
 	"BLOCK":                    19,
 	"VENDOR_FINALIZED_PAYMENT": 20,
+	"CASE_HANDOVER":            21,
+	"CASE_HANDOVER_RESPONSE":   22,
+	"CASE_HANDOVER_RESOLUTION": 23,
+	"DISPUTE_FALLBACK":         24,
+	"DISPUTE_PANEL_VOTE":       25,
+	"RATCHET_CHAT":             26,
+	"GROUP_MEMBERSHIP":         27,
+	"POST_COMMENT":             28,
+	"CHAT_CHANGE":              29,
 	"ERROR":                    500,
 }
 
@@ -107,24 +134,37 @@ func (Message_MessageType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_33c57e4bae7b9afd, []int{0, 0}
 }
 
+// EDIT, RETRACT, REACT and UNREACT change the message with messageId
 type Chat_Flag int32
 
 const (
 	Chat_MESSAGE Chat_Flag = 0
 	Chat_TYPING  Chat_Flag = 1
 	Chat_READ    Chat_Flag = 2
+	Chat_EDIT    Chat_Flag = 3
+	Chat_RETRACT Chat_Flag = 4
+	Chat_REACT   Chat_Flag = 5
+	Chat_UNREACT Chat_Flag = 6
 )
 
 var Chat_Flag_name = map[int32]string{
 	0: "MESSAGE",
 	1: "TYPING",
 	2: "READ",
+	3: "EDIT",
+	4: "RETRACT",
+	5: "REACT",
+	6: "UNREACT",
 }
 
 var Chat_Flag_value = map[string]int32{
 	"MESSAGE": 0,
 	"TYPING":  1,
 	"READ":    2,
+	"EDIT":    3,
+	"RETRACT": 4,
+	"REACT":   5,
+	"UNREACT": 6,
 }
 
 func (x Chat_Flag) String() string {
@@ -135,6 +175,34 @@ func (Chat_Flag) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_33c57e4bae7b9afd, []int{2, 0}
 }
 
+type GroupMembership_Action int32
+
+const (
+	GroupMembership_ADD    GroupMembership_Action = 0
+	GroupMembership_REMOVE GroupMembership_Action = 1
+	GroupMembership_LEAVE  GroupMembership_Action = 2
+)
+
+var GroupMembership_Action_name = map[int32]string{
+	0: "ADD",
+	1: "REMOVE",
+	2: "LEAVE",
+}
+
+var GroupMembership_Action_value = map[string]int32{
+	"ADD":    0,
+	"REMOVE": 1,
+	"LEAVE":  2,
+}
+
+func (x GroupMembership_Action) String() string {
+	return proto.EnumName(GroupMembership_Action_name, int32(x))
+}
+
+func (GroupMembership_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{5, 0}
+}
+
 type Message struct {
 	MessageType          Message_MessageType `protobuf:"varint,1,opt,name=messageType,proto3,enum=Message_MessageType" json:"messageType,omitempty"`
 	Payload              *any.Any            `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
@@ -259,6 +327,14 @@ type Chat struct {
 	Message              string               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
 	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Flag                 Chat_Flag            `protobuf:"varint,5,opt,name=flag,proto3,enum=Chat_Flag" json:"flag,omitempty"`
+	AutoReply            bool                 `protobuf:"varint,6,opt,name=autoReply,proto3" json:"autoReply,omitempty"`
+	Attachments          []*Chat_Attachment   `protobuf:"bytes,7,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	Ratchet              bool                 `protobuf:"varint,8,opt,name=ratchet,proto3" json:"ratchet,omitempty"`
+	GroupId              string               `protobuf:"bytes,9,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	Reaction             string               `protobuf:"bytes,10,opt,name=reaction,proto3" json:"reaction,omitempty"`
+	SenderPubkey         []byte               `protobuf:"bytes,11,opt,name=senderPubkey,proto3" json:"senderPubkey,omitempty"`
+	Signature            []byte               `protobuf:"bytes,12,opt,name=signature,proto3" json:"signature,omitempty"`
+	Nonce                []byte               `protobuf:"bytes,13,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -324,6 +400,406 @@ func (m *Chat) GetFlag() Chat_Flag {
 	return Chat_MESSAGE
 }
 
+func (m *Chat) GetAutoReply() bool {
+	if m != nil {
+		return m.AutoReply
+	}
+	return false
+}
+
+func (m *Chat) GetAttachments() []*Chat_Attachment {
+	if m != nil {
+		return m.Attachments
+	}
+	return nil
+}
+
+func (m *Chat) GetRatchet() bool {
+	if m != nil {
+		return m.Ratchet
+	}
+	return false
+}
+
+func (m *Chat) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *Chat) GetReaction() string {
+	if m != nil {
+		return m.Reaction
+	}
+	return ""
+}
+
+func (m *Chat) GetSenderPubkey() []byte {
+	if m != nil {
+		return m.SenderPubkey
+	}
+	return nil
+}
+
+func (m *Chat) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Chat) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+// Attachment references a file encrypted to the recipient and left in
+// the offline message storage
+type Chat_Attachment struct {
+	Filename             string   `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	MediaType            string   `protobuf:"bytes,2,opt,name=mediaType,proto3" json:"mediaType,omitempty"`
+	Size                 uint64   `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Hash                 string   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Location             string   `protobuf:"bytes,5,opt,name=location,proto3" json:"location,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chat_Attachment) Reset()         { *m = Chat_Attachment{} }
+func (m *Chat_Attachment) String() string { return proto.CompactTextString(m) }
+func (*Chat_Attachment) ProtoMessage()    {}
+func (*Chat_Attachment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{2, 0}
+}
+
+func (m *Chat_Attachment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chat_Attachment.Unmarshal(m, b)
+}
+func (m *Chat_Attachment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chat_Attachment.Marshal(b, m, deterministic)
+}
+func (m *Chat_Attachment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chat_Attachment.Merge(m, src)
+}
+func (m *Chat_Attachment) XXX_Size() int {
+	return xxx_messageInfo_Chat_Attachment.Size(m)
+}
+func (m *Chat_Attachment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chat_Attachment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chat_Attachment proto.InternalMessageInfo
+
+func (m *Chat_Attachment) GetFilename() string {
+	if m != nil {
+		return m.Filename
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetMediaType() string {
+	if m != nil {
+		return m.MediaType
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *Chat_Attachment) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Chat_Attachment) GetLocation() string {
+	if m != nil {
+		return m.Location
+	}
+	return ""
+}
+
+// RatchetMessage is a Chat encrypted with a double ratchet session
+type RatchetMessage struct {
+	Header               *RatchetMessage_Header `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Ciphertext           []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *RatchetMessage) Reset()         { *m = RatchetMessage{} }
+func (m *RatchetMessage) String() string { return proto.CompactTextString(m) }
+func (*RatchetMessage) ProtoMessage()    {}
+func (*RatchetMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{3}
+}
+
+func (m *RatchetMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RatchetMessage.Unmarshal(m, b)
+}
+func (m *RatchetMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RatchetMessage.Marshal(b, m, deterministic)
+}
+func (m *RatchetMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RatchetMessage.Merge(m, src)
+}
+func (m *RatchetMessage) XXX_Size() int {
+	return xxx_messageInfo_RatchetMessage.Size(m)
+}
+func (m *RatchetMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RatchetMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RatchetMessage proto.InternalMessageInfo
+
+func (m *RatchetMessage) GetHeader() *RatchetMessage_Header {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *RatchetMessage) GetCiphertext() []byte {
+	if m != nil {
+		return m.Ciphertext
+	}
+	return nil
+}
+
+type RatchetMessage_Header struct {
+	RatchetKey           []byte   `protobuf:"bytes,1,opt,name=ratchetKey,proto3" json:"ratchetKey,omitempty"`
+	PreviousChainLength  uint32   `protobuf:"varint,2,opt,name=previousChainLength,proto3" json:"previousChainLength,omitempty"`
+	MessageNumber        uint32   `protobuf:"varint,3,opt,name=messageNumber,proto3" json:"messageNumber,omitempty"`
+	Init                 bool     `protobuf:"varint,4,opt,name=init,proto3" json:"init,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RatchetMessage_Header) Reset()         { *m = RatchetMessage_Header{} }
+func (m *RatchetMessage_Header) String() string { return proto.CompactTextString(m) }
+func (*RatchetMessage_Header) ProtoMessage()    {}
+func (*RatchetMessage_Header) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{3, 0}
+}
+
+func (m *RatchetMessage_Header) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RatchetMessage_Header.Unmarshal(m, b)
+}
+func (m *RatchetMessage_Header) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RatchetMessage_Header.Marshal(b, m, deterministic)
+}
+func (m *RatchetMessage_Header) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RatchetMessage_Header.Merge(m, src)
+}
+func (m *RatchetMessage_Header) XXX_Size() int {
+	return xxx_messageInfo_RatchetMessage_Header.Size(m)
+}
+func (m *RatchetMessage_Header) XXX_DiscardUnknown() {
+	xxx_messageInfo_RatchetMessage_Header.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RatchetMessage_Header proto.InternalMessageInfo
+
+func (m *RatchetMessage_Header) GetRatchetKey() []byte {
+	if m != nil {
+		return m.RatchetKey
+	}
+	return nil
+}
+
+func (m *RatchetMessage_Header) GetPreviousChainLength() uint32 {
+	if m != nil {
+		return m.PreviousChainLength
+	}
+	return 0
+}
+
+func (m *RatchetMessage_Header) GetMessageNumber() uint32 {
+	if m != nil {
+		return m.MessageNumber
+	}
+	return 0
+}
+
+func (m *RatchetMessage_Header) GetInit() bool {
+	if m != nil {
+		return m.Init
+	}
+	return false
+}
+
+// ChatGroup is the member list of a group conversation. Each change made by
+// the creator is signed with a higher version.
+type ChatGroup struct {
+	GroupId              string               `protobuf:"bytes,1,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	Name                 string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Creator              string               `protobuf:"bytes,3,opt,name=creator,proto3" json:"creator,omitempty"`
+	Members              []string             `protobuf:"bytes,4,rep,name=members,proto3" json:"members,omitempty"`
+	Version              uint32               `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ChatGroup) Reset()         { *m = ChatGroup{} }
+func (m *ChatGroup) String() string { return proto.CompactTextString(m) }
+func (*ChatGroup) ProtoMessage()    {}
+func (*ChatGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{4}
+}
+
+func (m *ChatGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChatGroup.Unmarshal(m, b)
+}
+func (m *ChatGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChatGroup.Marshal(b, m, deterministic)
+}
+func (m *ChatGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChatGroup.Merge(m, src)
+}
+func (m *ChatGroup) XXX_Size() int {
+	return xxx_messageInfo_ChatGroup.Size(m)
+}
+func (m *ChatGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChatGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChatGroup proto.InternalMessageInfo
+
+func (m *ChatGroup) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *ChatGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChatGroup) GetCreator() string {
+	if m != nil {
+		return m.Creator
+	}
+	return ""
+}
+
+func (m *ChatGroup) GetMembers() []string {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *ChatGroup) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ChatGroup) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type GroupMembership struct {
+	Action               GroupMembership_Action `protobuf:"varint,1,opt,name=action,proto3,enum=GroupMembership_Action" json:"action,omitempty"`
+	GroupId              string                 `protobuf:"bytes,2,opt,name=groupId,proto3" json:"groupId,omitempty"`
+	SerializedGroup      []byte                 `protobuf:"bytes,3,opt,name=serializedGroup,proto3" json:"serializedGroup,omitempty"`
+	CreatorPubkey        []byte                 `protobuf:"bytes,4,opt,name=creatorPubkey,proto3" json:"creatorPubkey,omitempty"`
+	Signature            []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	PeerIds              []string               `protobuf:"bytes,6,rep,name=peerIds,proto3" json:"peerIds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *GroupMembership) Reset()         { *m = GroupMembership{} }
+func (m *GroupMembership) String() string { return proto.CompactTextString(m) }
+func (*GroupMembership) ProtoMessage()    {}
+func (*GroupMembership) Descriptor() ([]byte, []int) {
+	return fileDescriptor_33c57e4bae7b9afd, []int{5}
+}
+
+func (m *GroupMembership) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupMembership.Unmarshal(m, b)
+}
+func (m *GroupMembership) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupMembership.Marshal(b, m, deterministic)
+}
+func (m *GroupMembership) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupMembership.Merge(m, src)
+}
+func (m *GroupMembership) XXX_Size() int {
+	return xxx_messageInfo_GroupMembership.Size(m)
+}
+func (m *GroupMembership) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupMembership.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupMembership proto.InternalMessageInfo
+
+func (m *GroupMembership) GetAction() GroupMembership_Action {
+	if m != nil {
+		return m.Action
+	}
+	return GroupMembership_ADD
+}
+
+func (m *GroupMembership) GetGroupId() string {
+	if m != nil {
+		return m.GroupId
+	}
+	return ""
+}
+
+func (m *GroupMembership) GetSerializedGroup() []byte {
+	if m != nil {
+		return m.SerializedGroup
+	}
+	return nil
+}
+
+func (m *GroupMembership) GetCreatorPubkey() []byte {
+	if m != nil {
+		return m.CreatorPubkey
+	}
+	return nil
+}
+
+func (m *GroupMembership) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *GroupMembership) GetPeerIds() []string {
+	if m != nil {
+		return m.PeerIds
+	}
+	return nil
+}
+
 type SignedData struct {
 	SenderPubkey         []byte   `protobuf:"bytes,1,opt,name=senderPubkey,proto3" json:"senderPubkey,omitempty"`
 	SerializedData       []byte   `protobuf:"bytes,2,opt,name=serializedData,proto3" json:"serializedData,omitempty"`
@@ -337,7 +813,7 @@ func (m *SignedData) Reset()         { *m = SignedData{} }
 func (m *SignedData) String() string { return proto.CompactTextString(m) }
 func (*SignedData) ProtoMessage()    {}
 func (*SignedData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_33c57e4bae7b9afd, []int{3}
+	return fileDescriptor_33c57e4bae7b9afd, []int{6}
 }
 
 func (m *SignedData) XXX_Unmarshal(b []byte) error {
@@ -392,7 +868,7 @@ func (m *SignedData_Command) Reset()         { *m = SignedData_Command{} }
 func (m *SignedData_Command) String() string { return proto.CompactTextString(m) }
 func (*SignedData_Command) ProtoMessage()    {}
 func (*SignedData_Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_33c57e4bae7b9afd, []int{3, 0}
+	return fileDescriptor_33c57e4bae7b9afd, []int{6, 0}
 }
 
 func (m *SignedData_Command) XXX_Unmarshal(b []byte) error {
@@ -445,7 +921,7 @@ func (m *CidList) Reset()         { *m = CidList{} }
 func (m *CidList) String() string { return proto.CompactTextString(m) }
 func (*CidList) ProtoMessage()    {}
 func (*CidList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_33c57e4bae7b9afd, []int{4}
+	return fileDescriptor_33c57e4bae7b9afd, []int{7}
 }
 
 func (m *CidList) XXX_Unmarshal(b []byte) error {
@@ -485,7 +961,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_33c57e4bae7b9afd, []int{5}
+	return fileDescriptor_33c57e4bae7b9afd, []int{8}
 }
 
 func (m *Block) XXX_Unmarshal(b []byte) error {
@@ -533,7 +1009,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_33c57e4bae7b9afd, []int{6}
+	return fileDescriptor_33c57e4bae7b9afd, []int{9}
 }
 
 func (m *Error) XXX_Unmarshal(b []byte) error {
@@ -578,9 +1054,15 @@ func (m *Error) GetOrderID() string {
 func init() {
 	proto.RegisterEnum("Message_MessageType", Message_MessageType_name, Message_MessageType_value)
 	proto.RegisterEnum("Chat_Flag", Chat_Flag_name, Chat_Flag_value)
+	proto.RegisterEnum("GroupMembership_Action", GroupMembership_Action_name, GroupMembership_Action_value)
 	proto.RegisterType((*Message)(nil), "Message")
 	proto.RegisterType((*Envelope)(nil), "Envelope")
 	proto.RegisterType((*Chat)(nil), "Chat")
+	proto.RegisterType((*Chat_Attachment)(nil), "Chat.Attachment")
+	proto.RegisterType((*RatchetMessage)(nil), "RatchetMessage")
+	proto.RegisterType((*RatchetMessage_Header)(nil), "RatchetMessage.Header")
+	proto.RegisterType((*ChatGroup)(nil), "ChatGroup")
+	proto.RegisterType((*GroupMembership)(nil), "GroupMembership")
 	proto.RegisterType((*SignedData)(nil), "SignedData")
 	proto.RegisterType((*SignedData_Command)(nil), "SignedData.Command")
 	proto.RegisterType((*CidList)(nil), "CidList")
@@ -591,55 +1073,90 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 787 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x41, 0x8f, 0x9b, 0x46,
-	0x14, 0x0e, 0x36, 0x5e, 0xec, 0x87, 0x77, 0x33, 0x3b, 0xdd, 0x46, 0x74, 0x95, 0xa6, 0x16, 0x87,
-	0xca, 0xbd, 0x10, 0x69, 0x23, 0x55, 0xbd, 0xb2, 0x30, 0xa4, 0x34, 0x18, 0xac, 0x31, 0x4e, 0xb5,
-	0xb9, 0x58, 0xd8, 0x4c, 0x5c, 0x1a, 0x1b, 0x28, 0xe0, 0x56, 0xee, 0xad, 0x87, 0xfe, 0xa2, 0xfe,
-	0x9e, 0xfe, 0x8b, 0x9e, 0xab, 0x6a, 0x86, 0xa1, 0xde, 0x4d, 0xa5, 0x95, 0x7a, 0xe2, 0xbd, 0xef,
-	0x7d, 0xbc, 0x79, 0xef, 0x9b, 0x0f, 0xe0, 0x7c, 0xcf, 0xea, 0x3a, 0xd9, 0x32, 0xab, 0xac, 0x8a,
-	0xa6, 0xb8, 0xfe, 0x6c, 0x5b, 0x14, 0xdb, 0x1d, 0x7b, 0x29, 0xb2, 0xf5, 0xe1, 0xfd, 0xcb, 0x24,
-	0x3f, 0xca, 0xd2, 0x17, 0x1f, 0x97, 0x9a, 0x6c, 0xcf, 0xea, 0x26, 0xd9, 0x97, 0x2d, 0xc1, 0xfc,
-	0x43, 0x05, 0x6d, 0xd6, 0x76, 0xc3, 0x5f, 0x83, 0x2e, 0x1b, 0xc7, 0xc7, 0x92, 0x19, 0xca, 0x44,
-	0x99, 0x5e, 0xdc, 0x5c, 0x59, 0xb2, 0xdc, 0x3d, 0x79, 0x8d, 0xde, 0x27, 0x62, 0x0b, 0xb4, 0x32,
-	0x39, 0xee, 0x8a, 0x24, 0x35, 0x7a, 0x13, 0x65, 0xaa, 0xdf, 0x5c, 0x59, 0xed, 0xb1, 0x56, 0x77,
-	0xac, 0x65, 0xe7, 0x47, 0xda, 0x91, 0xf0, 0x73, 0x18, 0x55, 0xec, 0xa7, 0x03, 0xab, 0x1b, 0x3f,
-	0x35, 0xfa, 0x13, 0x65, 0x3a, 0xa0, 0x27, 0x00, 0xbf, 0x00, 0xc8, 0x6a, 0xca, 0xea, 0xb2, 0xc8,
-	0x6b, 0x66, 0xa8, 0x13, 0x65, 0x3a, 0xa4, 0xf7, 0x10, 0xf3, 0xb7, 0x3e, 0xe8, 0xf7, 0x46, 0xc1,
-	0x43, 0x50, 0xe7, 0x7e, 0xf8, 0x1a, 0x3d, 0xe1, 0x91, 0xf3, 0xad, 0x1d, 0x23, 0x05, 0x03, 0x9c,
-	0x79, 0x51, 0x10, 0x44, 0xdf, 0xa3, 0x1e, 0x1e, 0xc3, 0x70, 0x19, 0xca, 0xac, 0x8f, 0x47, 0x30,
-	0x88, 0xa8, 0x4b, 0x28, 0x52, 0x31, 0x82, 0xb1, 0x08, 0x57, 0x94, 0x7c, 0x47, 0x9c, 0x18, 0x0d,
-	0x4e, 0x88, 0x63, 0x87, 0x0e, 0x09, 0xd0, 0x19, 0x7e, 0x06, 0x58, 0x22, 0x51, 0xe8, 0xf9, 0x74,
-	0x66, 0xc7, 0x7e, 0x14, 0x22, 0x0d, 0x7f, 0x0a, 0x97, 0x2d, 0xee, 0x2d, 0x03, 0xcf, 0x0f, 0x82,
-	0x19, 0x09, 0x63, 0x34, 0xc4, 0x57, 0x80, 0x3a, 0xfa, 0x6c, 0x1e, 0x10, 0x41, 0x1e, 0xf1, 0xb6,
-	0xae, 0xbf, 0x98, 0x2f, 0x63, 0xb2, 0x8a, 0xe6, 0x24, 0x44, 0x80, 0x31, 0x5c, 0x74, 0xc8, 0x72,
-	0xee, 0xda, 0x31, 0x41, 0x3a, 0xbe, 0x84, 0xf3, 0x0e, 0x73, 0x82, 0x68, 0x41, 0xd0, 0x98, 0xaf,
-	0x41, 0x89, 0xb7, 0x0c, 0x5d, 0x74, 0x8e, 0x9f, 0x82, 0x1e, 0x79, 0x5e, 0xe0, 0x87, 0x64, 0x65,
-	0x3b, 0x6f, 0xd0, 0x05, 0xe7, 0x77, 0x00, 0x25, 0x81, 0x7d, 0x87, 0x9e, 0x72, 0x68, 0x16, 0xb9,
-	0x84, 0xda, 0x71, 0x44, 0x57, 0xb6, 0xeb, 0x22, 0xc4, 0x27, 0x3a, 0x41, 0x94, 0xcc, 0xa2, 0xb7,
-	0x04, 0x5d, 0x72, 0x15, 0x16, 0x71, 0x44, 0x09, 0xc2, 0x3c, 0xbc, 0x0d, 0x22, 0xe7, 0x0d, 0xfa,
-	0x04, 0x3f, 0x07, 0xe3, 0x2d, 0x09, 0xdd, 0x88, 0xae, 0x3c, 0x3f, 0xb4, 0x03, 0xff, 0x1d, 0x71,
-	0x57, 0x73, 0xfb, 0x4e, 0xec, 0x76, 0x85, 0x01, 0x06, 0x84, 0xd2, 0x88, 0xa2, 0xbf, 0xfa, 0x66,
-	0x0a, 0x43, 0x92, 0xff, 0xcc, 0x76, 0x45, 0xc9, 0xb0, 0x09, 0x9a, 0x34, 0x83, 0x70, 0x8c, 0x7e,
-	0x33, 0xec, 0x9c, 0x42, 0xbb, 0x02, 0x7e, 0x06, 0x67, 0xe5, 0x61, 0xfd, 0x81, 0x1d, 0x85, 0x41,
-	0xc6, 0x54, 0x66, 0xdc, 0x09, 0x75, 0xb6, 0xcd, 0x93, 0xe6, 0x50, 0x31, 0xe1, 0x84, 0x31, 0x3d,
-	0x01, 0xe6, 0x9f, 0x0a, 0xa8, 0xce, 0x0f, 0x49, 0xc3, 0x69, 0xb2, 0x93, 0x9f, 0x8a, 0x43, 0x46,
-	0xf4, 0x04, 0x60, 0x03, 0xb4, 0xfa, 0xb0, 0xfe, 0x91, 0x6d, 0x1a, 0xd1, 0x7d, 0x44, 0xbb, 0x94,
-	0x57, 0xba, 0xd1, 0xfa, 0x6d, 0xa5, 0x1b, 0xe8, 0x1b, 0x18, 0xfd, 0xfb, 0x25, 0x08, 0x8f, 0xe9,
-	0x37, 0xd7, 0xff, 0x31, 0x6d, 0xdc, 0x31, 0xe8, 0x89, 0x8c, 0x5f, 0x80, 0xfa, 0x7e, 0x97, 0x6c,
-	0x8d, 0x81, 0xf8, 0x3a, 0xc0, 0xe2, 0x03, 0x5a, 0xde, 0x2e, 0xd9, 0x52, 0x81, 0x9b, 0x5f, 0x81,
-	0xca, 0x33, 0xac, 0x83, 0x36, 0x23, 0x8b, 0x85, 0xfd, 0x9a, 0xa0, 0x27, 0xfc, 0x22, 0xe3, 0x3b,
-	0xe1, 0x52, 0x85, 0xbb, 0x94, 0x12, 0xdb, 0x45, 0x3d, 0xf3, 0x6f, 0x05, 0x60, 0x91, 0x6d, 0x73,
-	0x96, 0xba, 0x49, 0x93, 0x60, 0x13, 0xc6, 0x35, 0xcb, 0x53, 0x56, 0xcd, 0x5b, 0xa9, 0x14, 0xa1,
-	0xc7, 0x03, 0x0c, 0x7f, 0x09, 0x17, 0x35, 0xab, 0xb2, 0x64, 0x97, 0xfd, 0xda, 0xbe, 0x25, 0x05,
-	0xfd, 0x08, 0x7d, 0x5c, 0xd8, 0xeb, 0xdf, 0x15, 0xd0, 0x9c, 0x62, 0xbf, 0x4f, 0xf2, 0x54, 0x5c,
-	0x0d, 0x63, 0x95, 0xef, 0x4a, 0x61, 0x65, 0x86, 0xa7, 0xa0, 0x36, 0xfc, 0x2f, 0xd0, 0x7b, 0xe4,
-	0x2f, 0x20, 0x18, 0x0f, 0xb5, 0xec, 0xff, 0x0f, 0x2d, 0xcd, 0xcf, 0x41, 0x73, 0xb2, 0x34, 0xc8,
-	0xea, 0x06, 0x63, 0x50, 0x37, 0x59, 0x5a, 0x1b, 0xca, 0xa4, 0x3f, 0x1d, 0x51, 0x11, 0x9b, 0xaf,
-	0x60, 0x70, 0xbb, 0x2b, 0x36, 0x1f, 0xf8, 0x3d, 0x56, 0xc9, 0x2f, 0x62, 0xdd, 0x56, 0x94, 0x2e,
-	0xc5, 0x08, 0xfa, 0x9b, 0x2c, 0x95, 0xf7, 0xce, 0x43, 0xf3, 0x0e, 0x06, 0xa4, 0xaa, 0x8a, 0x4a,
-	0x74, 0x2c, 0xd2, 0xd6, 0x94, 0xe7, 0x54, 0xc4, 0x5c, 0x62, 0xc6, 0x8b, 0x72, 0x09, 0xf9, 0xde,
-	0x03, 0x8c, 0x1f, 0x56, 0x54, 0xa9, 0x50, 0x44, 0x9a, 0x46, 0xa6, 0xb7, 0xea, 0xbb, 0x5e, 0xb9,
-	0x5e, 0x9f, 0x89, 0x9d, 0x5e, 0xfd, 0x13, 0x00, 0x00, 0xff, 0xff, 0x67, 0x2d, 0xb7, 0x8c, 0x85,
-	0x05, 0x00, 0x00,
+	// 1351 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0x4f, 0x8f, 0xdb, 0x44,
+	0x14, 0xaf, 0x13, 0xe7, 0xdf, 0x4b, 0xb2, 0x3b, 0x9d, 0x6e, 0xb7, 0x6e, 0x68, 0xcb, 0x2a, 0x42,
+	0x28, 0xa7, 0x14, 0x6d, 0x25, 0xc4, 0xd5, 0x1b, 0x4f, 0x76, 0x4d, 0x1d, 0x3b, 0x9a, 0x38, 0x0b,
+	0xdb, 0x4b, 0xe4, 0x8d, 0xa7, 0x89, 0x69, 0x62, 0x1b, 0xdb, 0x29, 0x6c, 0xef, 0x5c, 0x38, 0x70,
+	0x81, 0x2f, 0xc4, 0x89, 0x2f, 0x84, 0x84, 0xb8, 0x20, 0x34, 0xe3, 0xf1, 0x26, 0xd9, 0x16, 0xa4,
+	0xde, 0xe6, 0xfd, 0xde, 0xcf, 0x6f, 0xde, 0xff, 0x31, 0xb4, 0xd7, 0x2c, 0x4d, 0xbd, 0x05, 0xeb,
+	0xc7, 0x49, 0x94, 0x45, 0x9d, 0xc7, 0x8b, 0x28, 0x5a, 0xac, 0xd8, 0x73, 0x21, 0x5d, 0x6f, 0x5e,
+	0x3f, 0xf7, 0xc2, 0x1b, 0xa9, 0xfa, 0xf4, 0xae, 0x2a, 0x0b, 0xd6, 0x2c, 0xcd, 0xbc, 0x75, 0x9c,
+	0x13, 0xba, 0x7f, 0x57, 0xa0, 0x36, 0xca, 0xad, 0xe1, 0x2f, 0xa1, 0x29, 0x0d, 0xbb, 0x37, 0x31,
+	0xd3, 0x94, 0x13, 0xa5, 0x77, 0x70, 0x7a, 0xd4, 0x97, 0xea, 0xfe, 0x68, 0xab, 0xa3, 0xbb, 0x44,
+	0xdc, 0x87, 0x5a, 0xec, 0xdd, 0xac, 0x22, 0xcf, 0xd7, 0x4a, 0x27, 0x4a, 0xaf, 0x79, 0x7a, 0xd4,
+	0xcf, 0xaf, 0xed, 0x17, 0xd7, 0xf6, 0xf5, 0xf0, 0x86, 0x16, 0x24, 0xfc, 0x04, 0x1a, 0x09, 0xfb,
+	0x7e, 0xc3, 0xd2, 0xcc, 0xf4, 0xb5, 0xf2, 0x89, 0xd2, 0xab, 0xd0, 0x2d, 0x80, 0x9f, 0x01, 0x04,
+	0x29, 0x65, 0x69, 0x1c, 0x85, 0x29, 0xd3, 0xd4, 0x13, 0xa5, 0x57, 0xa7, 0x3b, 0x48, 0xf7, 0x0f,
+	0x15, 0x9a, 0x3b, 0xae, 0xe0, 0x3a, 0xa8, 0x63, 0xd3, 0x3e, 0x47, 0xf7, 0xf8, 0x69, 0x70, 0xa1,
+	0xbb, 0x48, 0xc1, 0x00, 0xd5, 0xa1, 0x63, 0x59, 0xce, 0x37, 0xa8, 0x84, 0x5b, 0x50, 0x9f, 0xda,
+	0x52, 0x2a, 0xe3, 0x06, 0x54, 0x1c, 0x6a, 0x10, 0x8a, 0x54, 0x8c, 0xa0, 0x25, 0x8e, 0x33, 0x4a,
+	0xbe, 0x26, 0x03, 0x17, 0x55, 0xb6, 0xc8, 0x40, 0xb7, 0x07, 0xc4, 0x42, 0x55, 0x7c, 0x0c, 0x58,
+	0x22, 0x8e, 0x3d, 0x34, 0xe9, 0x48, 0x77, 0x4d, 0xc7, 0x46, 0x35, 0xfc, 0x10, 0xee, 0xe7, 0xf8,
+	0x70, 0x6a, 0x0d, 0x4d, 0xcb, 0x1a, 0x11, 0xdb, 0x45, 0x75, 0x7c, 0x04, 0xa8, 0xa0, 0x8f, 0xc6,
+	0x16, 0x11, 0xe4, 0x06, 0x37, 0x6b, 0x98, 0x93, 0xf1, 0xd4, 0x25, 0x33, 0x67, 0x4c, 0x6c, 0x04,
+	0x18, 0xc3, 0x41, 0x81, 0x4c, 0xc7, 0x86, 0xee, 0x12, 0xd4, 0xc4, 0xf7, 0xa1, 0x5d, 0x60, 0x03,
+	0xcb, 0x99, 0x10, 0xd4, 0xe2, 0x61, 0x50, 0x32, 0x9c, 0xda, 0x06, 0x6a, 0xe3, 0x43, 0x68, 0x3a,
+	0xc3, 0xa1, 0x65, 0xda, 0x64, 0xa6, 0x0f, 0x5e, 0xa2, 0x03, 0xce, 0x2f, 0x00, 0x4a, 0x2c, 0xfd,
+	0x0a, 0x1d, 0x72, 0x68, 0xe4, 0x18, 0x84, 0xea, 0xae, 0x43, 0x67, 0xba, 0x61, 0x20, 0xc4, 0x3d,
+	0xda, 0x42, 0x94, 0x8c, 0x9c, 0x4b, 0x82, 0xee, 0xf3, 0x2c, 0x4c, 0x5c, 0x87, 0x12, 0x84, 0xf9,
+	0xf1, 0xcc, 0x72, 0x06, 0x2f, 0xd1, 0x03, 0xfc, 0x04, 0xb4, 0x4b, 0x62, 0x1b, 0x0e, 0x9d, 0x0d,
+	0x4d, 0x5b, 0xb7, 0xcc, 0x57, 0xc4, 0x98, 0x8d, 0xf5, 0x2b, 0x11, 0xdb, 0x11, 0x37, 0x3e, 0xd0,
+	0x27, 0x64, 0x76, 0xa1, 0xdb, 0x86, 0x73, 0x49, 0x28, 0x7a, 0x88, 0x3b, 0x70, 0xbc, 0x07, 0xcd,
+	0x28, 0x99, 0x8c, 0x1d, 0x7b, 0x42, 0xd0, 0x31, 0x37, 0xf6, 0x9e, 0xce, 0xb1, 0xa6, 0x22, 0x25,
+	0x8f, 0xb8, 0x5b, 0x45, 0xb0, 0x43, 0xdd, 0xb2, 0xce, 0x78, 0x48, 0x1a, 0xcf, 0x76, 0x81, 0x8e,
+	0x75, 0x9b, 0x58, 0xb3, 0x4b, 0xc7, 0x25, 0xe8, 0x31, 0x4f, 0x20, 0xd5, 0xdd, 0xc1, 0x05, 0x71,
+	0x67, 0xa2, 0xc0, 0x1d, 0xfe, 0xfd, 0x39, 0x75, 0xa6, 0xe3, 0xd9, 0x88, 0x8c, 0xce, 0x08, 0x9d,
+	0x5c, 0x98, 0x63, 0xf4, 0x09, 0xe7, 0x8d, 0x9d, 0x89, 0xcb, 0xb3, 0x2f, 0x9c, 0x7e, 0xc2, 0xb3,
+	0xc6, 0xbf, 0xe0, 0x9f, 0xd9, 0xe7, 0x04, 0x3d, 0xc5, 0x00, 0x15, 0x42, 0xa9, 0x43, 0xd1, 0x9f,
+	0xe5, 0xae, 0x0f, 0x75, 0x12, 0xbe, 0x65, 0xab, 0x28, 0x66, 0xb8, 0x0b, 0x35, 0xd9, 0xd2, 0xa2,
+	0xef, 0x9b, 0xa7, 0xf5, 0xa2, 0xdf, 0x69, 0xa1, 0xc0, 0xc7, 0x50, 0x8d, 0x37, 0xd7, 0x6f, 0xd8,
+	0x8d, 0x68, 0xf3, 0x16, 0x95, 0x12, 0xef, 0xe7, 0x34, 0x58, 0x84, 0x5e, 0xb6, 0x49, 0x98, 0xe8,
+	0xe7, 0x16, 0xdd, 0x02, 0xdd, 0x5f, 0x2b, 0xa0, 0x0e, 0x96, 0x5e, 0xc6, 0x69, 0xd2, 0x92, 0xe9,
+	0x8b, 0x4b, 0x1a, 0x74, 0x0b, 0x60, 0x0d, 0x6a, 0xe9, 0xe6, 0xfa, 0x3b, 0x36, 0xcf, 0x84, 0xf5,
+	0x06, 0x2d, 0x44, 0xae, 0x29, 0x5c, 0x2b, 0xe7, 0x9a, 0xc2, 0xa1, 0xaf, 0xa0, 0x71, 0x3b, 0xcf,
+	0x62, 0x52, 0x9a, 0xa7, 0x9d, 0xf7, 0x46, 0xcf, 0x2d, 0x18, 0x74, 0x4b, 0xc6, 0xcf, 0x40, 0x7d,
+	0xbd, 0xf2, 0x16, 0x5a, 0x45, 0xcc, 0x38, 0xf4, 0xb9, 0x83, 0xfd, 0xe1, 0xca, 0x5b, 0x50, 0x81,
+	0x73, 0x5f, 0xbd, 0x4d, 0x16, 0x51, 0x16, 0xaf, 0x6e, 0xb4, 0xaa, 0x98, 0xc1, 0x2d, 0x80, 0x4f,
+	0xa1, 0xe9, 0x65, 0x99, 0x37, 0x5f, 0xae, 0x59, 0x98, 0xa5, 0x5a, 0xed, 0xa4, 0xdc, 0x6b, 0x9e,
+	0xa2, 0xdc, 0x88, 0x7e, 0xab, 0xa0, 0xbb, 0x24, 0x1e, 0x45, 0xe2, 0x65, 0xf3, 0x25, 0xcb, 0xb4,
+	0xba, 0xb0, 0x57, 0x88, 0x5c, 0xb3, 0x48, 0xa2, 0x4d, 0x6c, 0xfa, 0x5a, 0x23, 0x8f, 0x4f, 0x8a,
+	0xb8, 0x03, 0xf5, 0x84, 0x79, 0xf3, 0x2c, 0x88, 0x42, 0x0d, 0x84, 0xea, 0x56, 0xc6, 0x5d, 0x68,
+	0xa5, 0x2c, 0xf4, 0x59, 0x32, 0xce, 0x4b, 0xd2, 0x14, 0x79, 0xdf, 0xc3, 0xf6, 0x0b, 0xd3, 0xba,
+	0x53, 0x18, 0x7c, 0x04, 0x95, 0x30, 0x0a, 0xe7, 0x4c, 0x6b, 0x0b, 0x4d, 0x2e, 0x74, 0x7e, 0x56,
+	0x00, 0xb6, 0x31, 0x70, 0x17, 0x5e, 0x07, 0x2b, 0x16, 0x7a, 0x6b, 0x26, 0x6b, 0x76, 0x2b, 0xe7,
+	0x05, 0xf5, 0x03, 0x4f, 0x6c, 0xcb, 0x52, 0x51, 0x50, 0x09, 0x60, 0x0c, 0x6a, 0x1a, 0xbc, 0xcb,
+	0x6b, 0xa6, 0x52, 0x71, 0xe6, 0xd8, 0xd2, 0x4b, 0x97, 0xa2, 0x56, 0x0d, 0x2a, 0xce, 0xfc, 0x86,
+	0x55, 0x34, 0xf7, 0x44, 0x90, 0x95, 0xfc, 0x86, 0x42, 0xee, 0x7e, 0x0b, 0x2a, 0x2f, 0x0a, 0x6e,
+	0x42, 0x6d, 0x44, 0x26, 0x13, 0xfd, 0x9c, 0xa0, 0x7b, 0x7c, 0x2b, 0xb8, 0x57, 0x62, 0xe5, 0x29,
+	0x7c, 0xe5, 0x51, 0xa2, 0x1b, 0xa8, 0xc4, 0x4f, 0xc4, 0x30, 0x5d, 0x54, 0xe6, 0x64, 0x4a, 0x5c,
+	0xaa, 0x0f, 0x5c, 0xa4, 0xf2, 0xf1, 0xa6, 0x44, 0x17, 0xdb, 0xad, 0x09, 0xb5, 0xa9, 0x9d, 0x0b,
+	0xd5, 0xee, 0x5f, 0x0a, 0x1c, 0xd0, 0xbc, 0x00, 0xc5, 0xfa, 0xef, 0x43, 0x75, 0xc9, 0x3c, 0x9f,
+	0x25, 0x72, 0x02, 0x8e, 0xfb, 0xfb, 0x84, 0xfe, 0x85, 0xd0, 0x52, 0xc9, 0xe2, 0x8b, 0x7a, 0x1e,
+	0xc4, 0x4b, 0x96, 0x64, 0xec, 0xc7, 0x4c, 0x8e, 0xc4, 0x0e, 0xd2, 0xf9, 0x4d, 0x81, 0xea, 0xc5,
+	0x2d, 0x55, 0x56, 0xfb, 0x25, 0xbb, 0x11, 0xe6, 0x5b, 0x74, 0x07, 0xc1, 0x5f, 0xc0, 0x83, 0x38,
+	0x61, 0x6f, 0x83, 0x68, 0x93, 0x0e, 0x96, 0x5e, 0x10, 0x5a, 0x2c, 0x5c, 0x64, 0x4b, 0x61, 0xb3,
+	0x4d, 0x3f, 0xa4, 0xc2, 0x9f, 0xdd, 0x3e, 0x82, 0xf6, 0x66, 0x7d, 0xcd, 0x12, 0x91, 0xe6, 0x36,
+	0xdd, 0x07, 0x79, 0xbe, 0x83, 0x30, 0xc8, 0xe4, 0x2b, 0x22, 0xce, 0xdd, 0xdf, 0x15, 0x68, 0xf0,
+	0x4e, 0x3d, 0xe7, 0x4d, 0xb6, 0xdb, 0x7c, 0xca, 0x7e, 0xf3, 0x61, 0x50, 0x45, 0xd5, 0xf3, 0xc2,
+	0x8a, 0x33, 0x67, 0xcf, 0x13, 0xe6, 0x65, 0x51, 0x52, 0x8c, 0xa2, 0x14, 0xf3, 0x21, 0xe5, 0x77,
+	0xa6, 0x9a, 0x7a, 0x52, 0xce, 0x87, 0x54, 0x88, 0x5c, 0xf3, 0x96, 0x25, 0x69, 0x51, 0xde, 0x36,
+	0x2d, 0xc4, 0xfd, 0xf1, 0xad, 0x7e, 0xc4, 0xf8, 0x76, 0x7f, 0x29, 0xc1, 0xa1, 0xf0, 0x7f, 0x94,
+	0x5f, 0xb2, 0x0c, 0x62, 0xfc, 0x1c, 0xaa, 0x72, 0x54, 0xf2, 0x87, 0xfb, 0x51, 0xff, 0x0e, 0xa3,
+	0xaf, 0x0b, 0x35, 0x95, 0xb4, 0xdd, 0xd0, 0x4b, 0xfb, 0xa1, 0xf7, 0xe0, 0x30, 0x65, 0x49, 0xe0,
+	0xad, 0x82, 0x77, 0xcc, 0x17, 0x56, 0xe4, 0x5a, 0xbb, 0x0b, 0xf3, 0x32, 0xc8, 0x0c, 0xc8, 0x31,
+	0x54, 0x05, 0x6f, 0x1f, 0xdc, 0x9f, 0xc3, 0xca, 0xdd, 0x39, 0xd4, 0xa0, 0x16, 0x33, 0x96, 0x98,
+	0x7e, 0xaa, 0x55, 0xf3, 0xd4, 0x49, 0xb1, 0xdb, 0x83, 0x6a, 0xee, 0x33, 0xae, 0x41, 0x99, 0xbf,
+	0x67, 0xf7, 0xf2, 0x27, 0x51, 0xbc, 0x62, 0x0a, 0xef, 0x6d, 0x8b, 0xe8, 0x97, 0x04, 0x95, 0xba,
+	0xff, 0x28, 0x00, 0x93, 0x60, 0x11, 0x32, 0xdf, 0xf0, 0x32, 0xef, 0xbd, 0xe5, 0xa0, 0x7c, 0x60,
+	0x39, 0x7c, 0x0e, 0x07, 0xdb, 0x68, 0xf8, 0x57, 0xb2, 0x85, 0xef, 0xa0, 0xff, 0xbf, 0xdd, 0x3b,
+	0x3f, 0x29, 0x50, 0x1b, 0x44, 0xeb, 0xb5, 0x17, 0xfa, 0xe2, 0x7d, 0xe0, 0x9e, 0x1b, 0xb2, 0x95,
+	0xa4, 0x84, 0x7b, 0xa0, 0x66, 0xc5, 0x8a, 0xf8, 0xaf, 0x1f, 0x2a, 0xc1, 0xd8, 0xef, 0x88, 0xf2,
+	0xc7, 0x74, 0xc4, 0x53, 0xa8, 0x0d, 0x02, 0xdf, 0x0a, 0xd2, 0x8c, 0x37, 0xee, 0x3c, 0xf0, 0x53,
+	0x4d, 0x11, 0xc9, 0x14, 0xe7, 0xee, 0x0b, 0xa8, 0x9c, 0xad, 0xa2, 0xf9, 0x9b, 0x7c, 0x0d, 0xff,
+	0x20, 0xc2, 0xcd, 0x93, 0x52, 0x88, 0x18, 0x41, 0x79, 0x1e, 0x14, 0xad, 0xc0, 0x8f, 0xdd, 0x2b,
+	0xa8, 0x90, 0x24, 0x89, 0xc4, 0x18, 0xcd, 0x23, 0x3f, 0x5f, 0x80, 0x6d, 0x2a, 0xce, 0x3c, 0xc5,
+	0x8c, 0x2b, 0x65, 0x10, 0xf2, 0xbb, 0x3d, 0x8c, 0x5f, 0x16, 0x25, 0xbe, 0xc8, 0x88, 0x1c, 0x17,
+	0x29, 0x9e, 0xa9, 0xaf, 0x4a, 0xf1, 0xf5, 0x75, 0x55, 0xc4, 0xf4, 0xe2, 0xdf, 0x01, 0x00, 0xd1,
+	0xd9, 0x2c, 0x22, 0xd0, 0x0a, 0x00, 0x00,
 }
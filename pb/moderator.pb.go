@@ -6,6 +6,7 @@ package pb
 import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	math "math"
 )
 
@@ -48,6 +49,31 @@ func (Moderator_Fee_FeeType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_44f20453d9230215, []int{0, 0, 0}
 }
 
+type ModeratorAvailability_Status int32
+
+const (
+	ModeratorAvailability_AVAILABLE   ModeratorAvailability_Status = 0
+	ModeratorAvailability_UNAVAILABLE ModeratorAvailability_Status = 1
+)
+
+var ModeratorAvailability_Status_name = map[int32]string{
+	0: "AVAILABLE",
+	1: "UNAVAILABLE",
+}
+
+var ModeratorAvailability_Status_value = map[string]int32{
+	"AVAILABLE":   0,
+	"UNAVAILABLE": 1,
+}
+
+func (x ModeratorAvailability_Status) String() string {
+	return proto.EnumName(ModeratorAvailability_Status_name, int32(x))
+}
+
+func (ModeratorAvailability_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{2, 0}
+}
+
 type Moderator struct {
 	Description          string         `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
 	TermsAndConditions   string         `protobuf:"bytes,2,opt,name=termsAndConditions,proto3" json:"termsAndConditions,omitempty"`
@@ -284,43 +310,501 @@ func (m *DisputeUpdate) GetSerializedContract() []byte {
 	return nil
 }
 
+type ModeratorAvailability struct {
+	Moderator            *ID                          `protobuf:"bytes,1,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	Status               ModeratorAvailability_Status `protobuf:"varint,2,opt,name=status,proto3,enum=ModeratorAvailability_Status" json:"status,omitempty"`
+	ReturnDate           *timestamp.Timestamp         `protobuf:"bytes,3,opt,name=returnDate,proto3" json:"returnDate,omitempty"`
+	BackupModerator      string                       `protobuf:"bytes,4,opt,name=backupModerator,proto3" json:"backupModerator,omitempty"`
+	Message              string                       `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp            *timestamp.Timestamp         `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *ModeratorAvailability) Reset()         { *m = ModeratorAvailability{} }
+func (m *ModeratorAvailability) String() string { return proto.CompactTextString(m) }
+func (*ModeratorAvailability) ProtoMessage()    {}
+func (*ModeratorAvailability) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{2}
+}
+
+func (m *ModeratorAvailability) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ModeratorAvailability.Unmarshal(m, b)
+}
+func (m *ModeratorAvailability) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ModeratorAvailability.Marshal(b, m, deterministic)
+}
+func (m *ModeratorAvailability) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModeratorAvailability.Merge(m, src)
+}
+func (m *ModeratorAvailability) XXX_Size() int {
+	return xxx_messageInfo_ModeratorAvailability.Size(m)
+}
+func (m *ModeratorAvailability) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModeratorAvailability.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModeratorAvailability proto.InternalMessageInfo
+
+func (m *ModeratorAvailability) GetModerator() *ID {
+	if m != nil {
+		return m.Moderator
+	}
+	return nil
+}
+
+func (m *ModeratorAvailability) GetStatus() ModeratorAvailability_Status {
+	if m != nil {
+		return m.Status
+	}
+	return ModeratorAvailability_AVAILABLE
+}
+
+func (m *ModeratorAvailability) GetReturnDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReturnDate
+	}
+	return nil
+}
+
+func (m *ModeratorAvailability) GetBackupModerator() string {
+	if m != nil {
+		return m.BackupModerator
+	}
+	return ""
+}
+
+func (m *ModeratorAvailability) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ModeratorAvailability) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type SignedModeratorAvailability struct {
+	Availability         *ModeratorAvailability `protobuf:"bytes,1,opt,name=availability,proto3" json:"availability,omitempty"`
+	Signature            []byte                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *SignedModeratorAvailability) Reset()         { *m = SignedModeratorAvailability{} }
+func (m *SignedModeratorAvailability) String() string { return proto.CompactTextString(m) }
+func (*SignedModeratorAvailability) ProtoMessage()    {}
+func (*SignedModeratorAvailability) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{3}
+}
+
+func (m *SignedModeratorAvailability) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedModeratorAvailability.Unmarshal(m, b)
+}
+func (m *SignedModeratorAvailability) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedModeratorAvailability.Marshal(b, m, deterministic)
+}
+func (m *SignedModeratorAvailability) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedModeratorAvailability.Merge(m, src)
+}
+func (m *SignedModeratorAvailability) XXX_Size() int {
+	return xxx_messageInfo_SignedModeratorAvailability.Size(m)
+}
+func (m *SignedModeratorAvailability) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedModeratorAvailability.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedModeratorAvailability proto.InternalMessageInfo
+
+func (m *SignedModeratorAvailability) GetAvailability() *ModeratorAvailability {
+	if m != nil {
+		return m.Availability
+	}
+	return nil
+}
+
+func (m *SignedModeratorAvailability) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type CaseHandover struct {
+	OrderId              string               `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Moderator            string               `protobuf:"bytes,2,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	BackupModerator      string               `protobuf:"bytes,3,opt,name=backupModerator,proto3" json:"backupModerator,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	BuyerContract        *RicardianContract   `protobuf:"bytes,5,opt,name=buyerContract,proto3" json:"buyerContract,omitempty"`
+	VendorContract       *RicardianContract   `protobuf:"bytes,6,opt,name=vendorContract,proto3" json:"vendorContract,omitempty"`
+	RedeemScript         string               `protobuf:"bytes,7,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	TransferInputs       []*Outpoint          `protobuf:"bytes,8,rep,name=transferInputs,proto3" json:"transferInputs,omitempty"`
+	TransferFeePerByte   uint64               `protobuf:"varint,9,opt,name=transferFeePerByte,proto3" json:"transferFeePerByte,omitempty"`
+	ModeratorSigs        []*BitcoinSignature  `protobuf:"bytes,10,rep,name=moderatorSigs,proto3" json:"moderatorSigs,omitempty"`
+	BuyerPayoutAddress   string               `protobuf:"bytes,11,opt,name=buyerPayoutAddress,proto3" json:"buyerPayoutAddress,omitempty"`
+	VendorPayoutAddress  string               `protobuf:"bytes,12,opt,name=vendorPayoutAddress,proto3" json:"vendorPayoutAddress,omitempty"`
+	EscrowOutpoints      []*Outpoint          `protobuf:"bytes,13,rep,name=escrowOutpoints,proto3" json:"escrowOutpoints,omitempty"`
+	ModeratorRatingSigs  [][]byte             `protobuf:"bytes,14,rep,name=moderatorRatingSigs,proto3" json:"moderatorRatingSigs,omitempty"`
+	Claim                string               `protobuf:"bytes,15,opt,name=claim,proto3" json:"claim,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CaseHandover) Reset()         { *m = CaseHandover{} }
+func (m *CaseHandover) String() string { return proto.CompactTextString(m) }
+func (*CaseHandover) ProtoMessage()    {}
+func (*CaseHandover) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{4}
+}
+
+func (m *CaseHandover) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CaseHandover.Unmarshal(m, b)
+}
+func (m *CaseHandover) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CaseHandover.Marshal(b, m, deterministic)
+}
+func (m *CaseHandover) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaseHandover.Merge(m, src)
+}
+func (m *CaseHandover) XXX_Size() int {
+	return xxx_messageInfo_CaseHandover.Size(m)
+}
+func (m *CaseHandover) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaseHandover.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaseHandover proto.InternalMessageInfo
+
+func (m *CaseHandover) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetModerator() string {
+	if m != nil {
+		return m.Moderator
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetBackupModerator() string {
+	if m != nil {
+		return m.BackupModerator
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetBuyerContract() *RicardianContract {
+	if m != nil {
+		return m.BuyerContract
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetVendorContract() *RicardianContract {
+	if m != nil {
+		return m.VendorContract
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetRedeemScript() string {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetTransferInputs() []*Outpoint {
+	if m != nil {
+		return m.TransferInputs
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetTransferFeePerByte() uint64 {
+	if m != nil {
+		return m.TransferFeePerByte
+	}
+	return 0
+}
+
+func (m *CaseHandover) GetModeratorSigs() []*BitcoinSignature {
+	if m != nil {
+		return m.ModeratorSigs
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetBuyerPayoutAddress() string {
+	if m != nil {
+		return m.BuyerPayoutAddress
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetVendorPayoutAddress() string {
+	if m != nil {
+		return m.VendorPayoutAddress
+	}
+	return ""
+}
+
+func (m *CaseHandover) GetEscrowOutpoints() []*Outpoint {
+	if m != nil {
+		return m.EscrowOutpoints
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetModeratorRatingSigs() [][]byte {
+	if m != nil {
+		return m.ModeratorRatingSigs
+	}
+	return nil
+}
+
+func (m *CaseHandover) GetClaim() string {
+	if m != nil {
+		return m.Claim
+	}
+	return ""
+}
+
+type CaseHandoverResponse struct {
+	OrderId              string               `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	BackupModerator      string               `protobuf:"bytes,2,opt,name=backupModerator,proto3" json:"backupModerator,omitempty"`
+	Accepted             bool                 `protobuf:"varint,3,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	TransferSigs         []*BitcoinSignature  `protobuf:"bytes,5,rep,name=transferSigs,proto3" json:"transferSigs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CaseHandoverResponse) Reset()         { *m = CaseHandoverResponse{} }
+func (m *CaseHandoverResponse) String() string { return proto.CompactTextString(m) }
+func (*CaseHandoverResponse) ProtoMessage()    {}
+func (*CaseHandoverResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{5}
+}
+
+func (m *CaseHandoverResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CaseHandoverResponse.Unmarshal(m, b)
+}
+func (m *CaseHandoverResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CaseHandoverResponse.Marshal(b, m, deterministic)
+}
+func (m *CaseHandoverResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaseHandoverResponse.Merge(m, src)
+}
+func (m *CaseHandoverResponse) XXX_Size() int {
+	return xxx_messageInfo_CaseHandoverResponse.Size(m)
+}
+func (m *CaseHandoverResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaseHandoverResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaseHandoverResponse proto.InternalMessageInfo
+
+func (m *CaseHandoverResponse) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *CaseHandoverResponse) GetBackupModerator() string {
+	if m != nil {
+		return m.BackupModerator
+	}
+	return ""
+}
+
+func (m *CaseHandoverResponse) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *CaseHandoverResponse) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *CaseHandoverResponse) GetTransferSigs() []*BitcoinSignature {
+	if m != nil {
+		return m.TransferSigs
+	}
+	return nil
+}
+
+type CaseHandoverResolution struct {
+	OrderId              string               `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	BuyerPercentage      float32              `protobuf:"fixed32,2,opt,name=buyerPercentage,proto3" json:"buyerPercentage,omitempty"`
+	VendorPercentage     float32              `protobuf:"fixed32,3,opt,name=vendorPercentage,proto3" json:"vendorPercentage,omitempty"`
+	Resolution           string               `protobuf:"bytes,4,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CaseHandoverResolution) Reset()         { *m = CaseHandoverResolution{} }
+func (m *CaseHandoverResolution) String() string { return proto.CompactTextString(m) }
+func (*CaseHandoverResolution) ProtoMessage()    {}
+func (*CaseHandoverResolution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_44f20453d9230215, []int{6}
+}
+
+func (m *CaseHandoverResolution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CaseHandoverResolution.Unmarshal(m, b)
+}
+func (m *CaseHandoverResolution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CaseHandoverResolution.Marshal(b, m, deterministic)
+}
+func (m *CaseHandoverResolution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CaseHandoverResolution.Merge(m, src)
+}
+func (m *CaseHandoverResolution) XXX_Size() int {
+	return xxx_messageInfo_CaseHandoverResolution.Size(m)
+}
+func (m *CaseHandoverResolution) XXX_DiscardUnknown() {
+	xxx_messageInfo_CaseHandoverResolution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CaseHandoverResolution proto.InternalMessageInfo
+
+func (m *CaseHandoverResolution) GetOrderId() string {
+	if m != nil {
+		return m.OrderId
+	}
+	return ""
+}
+
+func (m *CaseHandoverResolution) GetBuyerPercentage() float32 {
+	if m != nil {
+		return m.BuyerPercentage
+	}
+	return 0
+}
+
+func (m *CaseHandoverResolution) GetVendorPercentage() float32 {
+	if m != nil {
+		return m.VendorPercentage
+	}
+	return 0
+}
+
+func (m *CaseHandoverResolution) GetResolution() string {
+	if m != nil {
+		return m.Resolution
+	}
+	return ""
+}
+
+func (m *CaseHandoverResolution) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("Moderator_Fee_FeeType", Moderator_Fee_FeeType_name, Moderator_Fee_FeeType_value)
+	proto.RegisterEnum("ModeratorAvailability_Status", ModeratorAvailability_Status_name, ModeratorAvailability_Status_value)
 	proto.RegisterType((*Moderator)(nil), "Moderator")
 	proto.RegisterType((*Moderator_Fee)(nil), "Moderator.Fee")
 	proto.RegisterType((*Moderator_Price)(nil), "Moderator.Price")
 	proto.RegisterType((*DisputeUpdate)(nil), "DisputeUpdate")
+	proto.RegisterType((*ModeratorAvailability)(nil), "ModeratorAvailability")
+	proto.RegisterType((*SignedModeratorAvailability)(nil), "SignedModeratorAvailability")
+	proto.RegisterType((*CaseHandover)(nil), "CaseHandover")
+	proto.RegisterType((*CaseHandoverResponse)(nil), "CaseHandoverResponse")
+	proto.RegisterType((*CaseHandoverResolution)(nil), "CaseHandoverResolution")
 }
 
 func init() { proto.RegisterFile("moderator.proto", fileDescriptor_44f20453d9230215) }
 
 var fileDescriptor_44f20453d9230215 = []byte{
-	// 429 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xdf, 0x6a, 0xdb, 0x30,
-	0x14, 0x87, 0xe7, 0x38, 0x69, 0xe6, 0x93, 0x36, 0x0d, 0x82, 0x15, 0x2f, 0x8c, 0x61, 0xc2, 0x60,
-	0xbe, 0x18, 0x66, 0x64, 0x0f, 0x30, 0x32, 0x37, 0x19, 0x85, 0xfd, 0x09, 0x5a, 0x0b, 0x63, 0x37,
-	0x45, 0x95, 0x4e, 0x82, 0xa0, 0x91, 0x84, 0x24, 0xc3, 0xb2, 0x27, 0xda, 0x93, 0xec, 0x25, 0xf6,
-	0x32, 0xc5, 0x8a, 0xd3, 0x3a, 0x90, 0x0b, 0x5f, 0xe8, 0xfb, 0x7e, 0xc8, 0xe7, 0xe8, 0x1c, 0x38,
-	0xdf, 0x68, 0x81, 0x96, 0x79, 0x6d, 0x0b, 0x63, 0xb5, 0xd7, 0xe3, 0x73, 0xae, 0x95, 0xb7, 0x8c,
-	0x7b, 0xb7, 0x03, 0x93, 0xff, 0x31, 0x24, 0x5f, 0xf7, 0x21, 0x92, 0xc1, 0x40, 0xa0, 0xe3, 0x56,
-	0x1a, 0x2f, 0xb5, 0x4a, 0xa3, 0x2c, 0xca, 0x13, 0xda, 0x46, 0xa4, 0x00, 0xe2, 0xd1, 0x6e, 0xdc,
-	0x4c, 0x89, 0x52, 0x2b, 0x21, 0x6b, 0xe8, 0xd2, 0x4e, 0x08, 0x1e, 0x31, 0xe4, 0x15, 0x24, 0xf7,
-	0x4c, 0xad, 0x2b, 0xb6, 0x46, 0x97, 0xc6, 0x59, 0x9c, 0x27, 0xf4, 0x09, 0xd4, 0xb7, 0x31, 0xce,
-	0xd1, 0x78, 0x14, 0x65, 0x65, 0x2d, 0x2a, 0x2e, 0xd1, 0xa5, 0xdd, 0x10, 0x3b, 0x62, 0x48, 0x06,
-	0xf1, 0x0a, 0x31, 0xed, 0x65, 0x51, 0x3e, 0x98, 0x0e, 0x8b, 0xc7, 0xc2, 0x8b, 0x05, 0x22, 0xad,
-	0xd5, 0xf8, 0x5f, 0x04, 0xf1, 0x02, 0x91, 0xbc, 0x83, 0xe7, 0x2b, 0xf9, 0x1b, 0xc5, 0x02, 0x31,
-	0xb4, 0x31, 0x98, 0x8e, 0x5a, 0xf1, 0xa5, 0x95, 0x1c, 0xe9, 0x63, 0x82, 0xbc, 0x06, 0x30, 0x68,
-	0x39, 0x2a, 0xcf, 0xd6, 0x18, 0xba, 0xe9, 0xd0, 0x16, 0x21, 0xef, 0xa1, 0xbf, 0x42, 0xbc, 0xde,
-	0x1a, 0x4c, 0xe3, 0x2c, 0xca, 0x87, 0xd3, 0x8b, 0xc3, 0x7f, 0xd7, 0x5f, 0x6d, 0xe9, 0x3e, 0x36,
-	0xf9, 0x08, 0xfd, 0x86, 0x91, 0x04, 0x7a, 0x8b, 0xab, 0x9f, 0xf3, 0xcb, 0xd1, 0x33, 0x32, 0x04,
-	0x58, 0xce, 0x69, 0x39, 0xff, 0x76, 0x3d, 0xfb, 0x3c, 0x1f, 0x45, 0xe4, 0x25, 0xbc, 0x08, 0xea,
-	0x76, 0xf9, 0xe5, 0xe6, 0xc7, 0x6d, 0x4b, 0x75, 0xc6, 0x25, 0xf4, 0x42, 0x95, 0x64, 0x02, 0xa7,
-	0x7c, 0xf7, 0x02, 0xdb, 0x52, 0x0b, 0x6c, 0x86, 0x72, 0xc0, 0xc8, 0x05, 0x9c, 0xb0, 0x8d, 0xae,
-	0x94, 0x0f, 0xb5, 0x77, 0x69, 0x73, 0x9a, 0xfc, 0x8d, 0xe0, 0xec, 0x52, 0x3a, 0x53, 0x79, 0xbc,
-	0x31, 0x82, 0x79, 0x24, 0x29, 0xf4, 0xb5, 0x15, 0x68, 0xaf, 0x44, 0x73, 0xd1, 0xfe, 0x48, 0xde,
-	0xc0, 0x99, 0x61, 0x5b, 0x5d, 0xf9, 0x99, 0x10, 0x16, 0xdd, 0x7e, 0xa8, 0x87, 0x90, 0xbc, 0x85,
-	0x44, 0x57, 0xde, 0x68, 0xa9, 0xfc, 0x6e, 0x9e, 0x83, 0x69, 0x52, 0x7c, 0x6f, 0x08, 0x7d, 0x72,
-	0xf5, 0x68, 0x1d, 0x5a, 0xc9, 0xee, 0xe5, 0x1f, 0xac, 0x17, 0x22, 0x6c, 0x5d, 0xda, 0xcd, 0xa2,
-	0xfc, 0x94, 0x1e, 0x31, 0x9f, 0xba, 0xbf, 0x3a, 0xe6, 0xee, 0xee, 0x24, 0x6c, 0xe5, 0x87, 0x87,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x85, 0xeb, 0x32, 0x4a, 0xb9, 0x02, 0x00, 0x00,
+	// 948 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xee, 0xda, 0xb1, 0x93, 0x3d, 0xfe, 0x0b, 0x43, 0x1b, 0x2d, 0xa1, 0x80, 0xb1, 0x90, 0xb0,
+	0x10, 0xda, 0x16, 0x57, 0x15, 0x55, 0x6f, 0x90, 0xe3, 0xc4, 0x10, 0x29, 0xb4, 0xd1, 0x38, 0x41,
+	0x88, 0x9b, 0x6a, 0xbc, 0x7b, 0x62, 0x8d, 0xb0, 0x67, 0x56, 0x33, 0xb3, 0x29, 0xe6, 0x89, 0x78,
+	0x0b, 0xee, 0x78, 0x03, 0xae, 0xb8, 0xe3, 0x86, 0xd7, 0x40, 0x3b, 0xfb, 0x63, 0xaf, 0xbb, 0x0d,
+	0xaa, 0xb8, 0x3c, 0xdf, 0xf9, 0x66, 0xce, 0x9c, 0xef, 0xfc, 0x0c, 0xf4, 0x56, 0x32, 0x44, 0xc5,
+	0x8c, 0x54, 0x7e, 0xa4, 0xa4, 0x91, 0xc7, 0xbd, 0x40, 0x0a, 0xa3, 0x58, 0x60, 0x74, 0x06, 0x7c,
+	0xb2, 0x90, 0x72, 0xb1, 0xc4, 0x47, 0xd6, 0x9a, 0xc7, 0x37, 0x8f, 0x0c, 0x5f, 0xa1, 0x36, 0x6c,
+	0x15, 0xa5, 0x84, 0xc1, 0x5f, 0x75, 0x70, 0xbf, 0xcf, 0x6f, 0x21, 0x7d, 0x68, 0x85, 0xa8, 0x03,
+	0xc5, 0x23, 0xc3, 0xa5, 0xf0, 0x9c, 0xbe, 0x33, 0x74, 0xe9, 0x36, 0x44, 0x7c, 0x20, 0x06, 0xd5,
+	0x4a, 0x8f, 0x45, 0x38, 0x91, 0x22, 0xe4, 0x09, 0xa8, 0xbd, 0x9a, 0x25, 0x56, 0x78, 0xc8, 0x43,
+	0x70, 0x97, 0x4c, 0x2c, 0x62, 0xb6, 0x40, 0xed, 0xd5, 0xfb, 0xf5, 0xa1, 0x4b, 0x37, 0x40, 0x72,
+	0x1b, 0x0b, 0x02, 0x8c, 0x0c, 0x86, 0x93, 0x58, 0x29, 0x14, 0x01, 0x47, 0xed, 0xed, 0x59, 0x5a,
+	0x85, 0x87, 0xf4, 0xa1, 0x7e, 0x83, 0xe8, 0x35, 0xfa, 0xce, 0xb0, 0x35, 0xea, 0xfa, 0xc5, 0xc3,
+	0xfd, 0x29, 0x22, 0x4d, 0x5c, 0xc7, 0x7f, 0x38, 0x50, 0x9f, 0x22, 0x92, 0x2f, 0xe1, 0xe0, 0x86,
+	0xff, 0x82, 0xe1, 0x14, 0xd1, 0xa6, 0xd1, 0x1a, 0x1d, 0x6e, 0xd1, 0x2f, 0x15, 0x0f, 0x90, 0x16,
+	0x0c, 0xf2, 0x31, 0x40, 0x84, 0x2a, 0x40, 0x61, 0xd8, 0x02, 0x6d, 0x36, 0x35, 0xba, 0x85, 0x90,
+	0xc7, 0xb0, 0x7f, 0x83, 0x78, 0xb5, 0x8e, 0xd0, 0xab, 0xf7, 0x9d, 0x61, 0x77, 0x74, 0x54, 0x8e,
+	0xed, 0x4f, 0x53, 0x2f, 0xcd, 0x69, 0x83, 0x6f, 0x60, 0x3f, 0xc3, 0x88, 0x0b, 0x8d, 0xe9, 0xf9,
+	0x8f, 0x67, 0xa7, 0x87, 0xf7, 0x48, 0x17, 0xe0, 0xf2, 0x8c, 0x4e, 0xce, 0x5e, 0x5c, 0x8d, 0xbf,
+	0x3d, 0x3b, 0x74, 0xc8, 0x07, 0xf0, 0xc0, 0xba, 0x5e, 0x5d, 0x5e, 0x5c, 0xcf, 0x5e, 0x6d, 0xb9,
+	0x6a, 0xc7, 0x13, 0x68, 0xd8, 0x57, 0x92, 0x01, 0xb4, 0x83, 0x54, 0x81, 0xf5, 0x44, 0x86, 0x98,
+	0x15, 0xa5, 0x84, 0x91, 0x23, 0x68, 0xb2, 0x95, 0x8c, 0x85, 0xb1, 0x6f, 0xdf, 0xa3, 0x99, 0x35,
+	0xf8, 0xcd, 0x81, 0xce, 0x29, 0xd7, 0x51, 0x6c, 0xf0, 0x3a, 0x0a, 0x99, 0x41, 0xe2, 0xc1, 0xbe,
+	0x54, 0x21, 0xaa, 0xf3, 0x30, 0xbb, 0x28, 0x37, 0xc9, 0x67, 0xd0, 0x89, 0xd8, 0x5a, 0xc6, 0x66,
+	0x1c, 0x86, 0x0a, 0x75, 0x5e, 0xd4, 0x32, 0x48, 0x3e, 0x07, 0x57, 0xc6, 0x26, 0x92, 0x5c, 0x98,
+	0xb4, 0x9e, 0xad, 0x91, 0xeb, 0xbf, 0xcc, 0x10, 0xba, 0xf1, 0x25, 0xa5, 0xd5, 0xa8, 0x38, 0x5b,
+	0xf2, 0x5f, 0x31, 0x9c, 0x64, 0x6d, 0xe9, 0xed, 0xf5, 0x9d, 0x61, 0x9b, 0x56, 0x78, 0x06, 0x7f,
+	0xd6, 0xe0, 0x41, 0xa1, 0xe9, 0xf8, 0x96, 0xf1, 0x25, 0x9b, 0xf3, 0x25, 0x37, 0x6b, 0xf2, 0x29,
+	0xb8, 0x45, 0x9f, 0x67, 0xb5, 0xac, 0xfb, 0xe7, 0xa7, 0x74, 0x83, 0x92, 0xa7, 0xd0, 0xd4, 0x86,
+	0x99, 0x38, 0x7d, 0x74, 0x77, 0xf4, 0x91, 0x5f, 0x79, 0x95, 0x3f, 0xb3, 0x24, 0x9a, 0x91, 0xc9,
+	0x73, 0x00, 0x85, 0x26, 0x56, 0xe2, 0x94, 0x99, 0xb4, 0xb2, 0xad, 0xd1, 0xb1, 0x9f, 0x8e, 0x8c,
+	0x9f, 0x8f, 0x8c, 0x7f, 0x95, 0x8f, 0x0c, 0xdd, 0x62, 0x93, 0x21, 0xf4, 0xe6, 0x2c, 0xf8, 0x39,
+	0x8e, 0x8a, 0x48, 0x36, 0x39, 0x97, 0xee, 0xc2, 0x89, 0xe4, 0x2b, 0xd4, 0x9a, 0x2d, 0xd2, 0xc6,
+	0x75, 0x69, 0x6e, 0x92, 0x67, 0xe0, 0x16, 0xf3, 0xe8, 0x35, 0xff, 0x33, 0xfc, 0x86, 0x3c, 0x18,
+	0x42, 0x33, 0xcd, 0x85, 0x74, 0xc0, 0x1d, 0xff, 0x30, 0x3e, 0xbf, 0x18, 0x9f, 0x5c, 0x9c, 0x1d,
+	0xde, 0x23, 0x3d, 0x68, 0x5d, 0xbf, 0xd8, 0x00, 0xce, 0xe0, 0x35, 0x7c, 0x38, 0xe3, 0x0b, 0x81,
+	0x61, 0xb5, 0xb8, 0xcf, 0xa1, 0xcd, 0xb6, 0xec, 0x4c, 0xdf, 0xa3, 0x6a, 0xfd, 0x68, 0x89, 0x9b,
+	0xcc, 0xb6, 0xe6, 0x0b, 0xc1, 0x4c, 0xac, 0xd2, 0xa1, 0x69, 0xd3, 0x0d, 0x30, 0xf8, 0xbd, 0x01,
+	0xed, 0x09, 0xd3, 0xf8, 0x1d, 0x13, 0xa1, 0xbc, 0x45, 0x75, 0x47, 0xeb, 0x3d, 0xdc, 0xae, 0x70,
+	0xda, 0x76, 0x1b, 0xa0, 0x4a, 0xe9, 0x7a, 0xb5, 0xd2, 0x25, 0x3d, 0xf7, 0xde, 0x41, 0x4f, 0xf2,
+	0x0c, 0x3a, 0xf3, 0x78, 0x8d, 0xaa, 0x68, 0xd4, 0x74, 0xc5, 0x10, 0x9f, 0xf2, 0x80, 0xa9, 0x90,
+	0x33, 0x91, 0x7b, 0x68, 0x99, 0x48, 0x9e, 0x43, 0xf7, 0x16, 0x45, 0x28, 0x37, 0x47, 0x9b, 0x6f,
+	0x3d, 0xba, 0xc3, 0x4c, 0x46, 0x5b, 0x61, 0x88, 0xb8, 0x9a, 0xd9, 0xfd, 0xea, 0xed, 0xa7, 0xa3,
+	0xbd, 0x8d, 0x91, 0xaf, 0xa0, 0x6b, 0x14, 0x13, 0xfa, 0x06, 0xd5, 0xb9, 0x88, 0x62, 0xa3, 0xbd,
+	0x83, 0xdd, 0xa9, 0xdb, 0x21, 0xd8, 0x1d, 0x9d, 0x21, 0x53, 0xc4, 0x4b, 0x54, 0x27, 0x6b, 0x83,
+	0x9e, 0x6b, 0x37, 0x43, 0x85, 0x87, 0x7c, 0x0d, 0x9d, 0x42, 0xed, 0x19, 0x5f, 0x68, 0x0f, 0x6c,
+	0x84, 0xf7, 0xfc, 0x13, 0x6e, 0x02, 0xc9, 0xc5, 0x2c, 0xaf, 0x29, 0x2d, 0xf3, 0x92, 0x40, 0x56,
+	0x8c, 0xcb, 0xd2, 0xde, 0x68, 0xa5, 0x9f, 0xc1, 0x9b, 0x1e, 0xf2, 0x18, 0xde, 0x4f, 0x15, 0x28,
+	0x1f, 0x68, 0xdb, 0x03, 0x55, 0x2e, 0xf2, 0x04, 0x7a, 0xc9, 0xe7, 0x23, 0x5f, 0xbf, 0x2c, 0x96,
+	0x4e, 0x67, 0x37, 0xfd, 0x5d, 0x46, 0x12, 0xa6, 0x78, 0x27, 0x65, 0x86, 0x8b, 0x85, 0xcd, 0xaa,
+	0xdb, 0xaf, 0x0f, 0xdb, 0xb4, 0xca, 0x45, 0xee, 0x43, 0x23, 0x58, 0x32, 0xbe, 0xf2, 0x7a, 0xf6,
+	0x29, 0xa9, 0x31, 0xf8, 0xc7, 0x81, 0xfb, 0xdb, 0x1d, 0x4c, 0x51, 0x47, 0x52, 0xe8, 0xbb, 0x96,
+	0x68, 0x45, 0xaf, 0xd6, 0xaa, 0x7b, 0xf5, 0x18, 0x0e, 0xf2, 0x0f, 0xce, 0xb6, 0xf3, 0x01, 0x2d,
+	0xec, 0xff, 0xd1, 0xc7, 0x4f, 0xa1, 0x9d, 0x17, 0xd8, 0xe6, 0xdc, 0x78, 0x5b, 0x25, 0x4b, 0xb4,
+	0xc1, 0xdf, 0x0e, 0x1c, 0xed, 0x64, 0x2a, 0x97, 0xb1, 0xfd, 0xf0, 0xef, 0xce, 0xd5, 0xd6, 0x78,
+	0xf7, 0xe7, 0xdc, 0x85, 0xc9, 0x17, 0x70, 0x98, 0x15, 0x77, 0x43, 0xad, 0x5b, 0xea, 0x1b, 0x78,
+	0xf2, 0x15, 0xab, 0x22, 0x7a, 0xb6, 0x52, 0xb7, 0x90, 0xb2, 0x36, 0x8d, 0x77, 0xd0, 0xe6, 0x64,
+	0xef, 0xa7, 0x5a, 0x34, 0x9f, 0x37, 0x2d, 0xe9, 0xc9, 0xbf, 0x03, 0x00, 0x39, 0xc1, 0x0b, 0x52,
+	0x3c, 0x09, 0x00, 0x00,
 }
@@ -22,27 +22,28 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Profile struct {
-	PeerID               string               `protobuf:"bytes,1,opt,name=peerID,proto3" json:"peerID,omitempty"`
-	Handle               string               `protobuf:"bytes,2,opt,name=handle,proto3" json:"handle,omitempty"`
-	Name                 string               `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Location             string               `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
-	About                string               `protobuf:"bytes,5,opt,name=about,proto3" json:"about,omitempty"`
-	ShortDescription     string               `protobuf:"bytes,6,opt,name=shortDescription,proto3" json:"shortDescription,omitempty"`
-	Nsfw                 bool                 `protobuf:"varint,7,opt,name=nsfw,proto3" json:"nsfw,omitempty"`
-	Vendor               bool                 `protobuf:"varint,8,opt,name=vendor,proto3" json:"vendor,omitempty"`
-	Moderator            bool                 `protobuf:"varint,9,opt,name=moderator,proto3" json:"moderator,omitempty"`
-	ModeratorInfo        *Moderator           `protobuf:"bytes,10,opt,name=moderatorInfo,proto3" json:"moderatorInfo,omitempty"`
-	ContactInfo          *Profile_Contact     `protobuf:"bytes,11,opt,name=contactInfo,proto3" json:"contactInfo,omitempty"`
-	Colors               *Profile_Colors      `protobuf:"bytes,12,opt,name=colors,proto3" json:"colors,omitempty"`
-	AvatarHashes         *Profile_Image       `protobuf:"bytes,13,opt,name=avatarHashes,proto3" json:"avatarHashes,omitempty"`
-	HeaderHashes         *Profile_Image       `protobuf:"bytes,14,opt,name=headerHashes,proto3" json:"headerHashes,omitempty"`
-	Stats                *Profile_Stats       `protobuf:"bytes,15,opt,name=stats,proto3" json:"stats,omitempty"`
-	BitcoinPubkey        string               `protobuf:"bytes,16,opt,name=bitcoinPubkey,proto3" json:"bitcoinPubkey,omitempty"`
-	LastModified         *timestamp.Timestamp `protobuf:"bytes,17,opt,name=lastModified,proto3" json:"lastModified,omitempty"`
-	Currencies           []string             `protobuf:"bytes,18,rep,name=currencies,proto3" json:"currencies,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	PeerID               string                `protobuf:"bytes,1,opt,name=peerID,proto3" json:"peerID,omitempty"`
+	Handle               string                `protobuf:"bytes,2,opt,name=handle,proto3" json:"handle,omitempty"`
+	Name                 string                `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Location             string                `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	About                string                `protobuf:"bytes,5,opt,name=about,proto3" json:"about,omitempty"`
+	ShortDescription     string                `protobuf:"bytes,6,opt,name=shortDescription,proto3" json:"shortDescription,omitempty"`
+	Nsfw                 bool                  `protobuf:"varint,7,opt,name=nsfw,proto3" json:"nsfw,omitempty"`
+	Vendor               bool                  `protobuf:"varint,8,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Moderator            bool                  `protobuf:"varint,9,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	ModeratorInfo        *Moderator            `protobuf:"bytes,10,opt,name=moderatorInfo,proto3" json:"moderatorInfo,omitempty"`
+	ContactInfo          *Profile_Contact      `protobuf:"bytes,11,opt,name=contactInfo,proto3" json:"contactInfo,omitempty"`
+	Colors               *Profile_Colors       `protobuf:"bytes,12,opt,name=colors,proto3" json:"colors,omitempty"`
+	AvatarHashes         *Profile_Image        `protobuf:"bytes,13,opt,name=avatarHashes,proto3" json:"avatarHashes,omitempty"`
+	HeaderHashes         *Profile_Image        `protobuf:"bytes,14,opt,name=headerHashes,proto3" json:"headerHashes,omitempty"`
+	Stats                *Profile_Stats        `protobuf:"bytes,15,opt,name=stats,proto3" json:"stats,omitempty"`
+	BitcoinPubkey        string                `protobuf:"bytes,16,opt,name=bitcoinPubkey,proto3" json:"bitcoinPubkey,omitempty"`
+	LastModified         *timestamp.Timestamp  `protobuf:"bytes,17,opt,name=lastModified,proto3" json:"lastModified,omitempty"`
+	Currencies           []string              `protobuf:"bytes,18,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	VacationMode         *Profile_VacationMode `protobuf:"bytes,19,opt,name=vacationMode,proto3" json:"vacationMode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *Profile) Reset()         { *m = Profile{} }
@@ -196,6 +197,13 @@ func (m *Profile) GetCurrencies() []string {
 	return nil
 }
 
+func (m *Profile) GetVacationMode() *Profile_VacationMode {
+	if m != nil {
+		return m.VacationMode
+	}
+	return nil
+}
+
 type Profile_Contact struct {
 	Website              string                   `protobuf:"bytes,1,opt,name=website,proto3" json:"website,omitempty"`
 	Email                string                   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
@@ -456,6 +464,61 @@ func (m *Profile_Colors) GetHighlightText() string {
 	return ""
 }
 
+type Profile_VacationMode struct {
+	Enabled              bool                 `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ReturnDate           *timestamp.Timestamp `protobuf:"bytes,2,opt,name=returnDate,proto3" json:"returnDate,omitempty"`
+	Message              string               `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Profile_VacationMode) Reset()         { *m = Profile_VacationMode{} }
+func (m *Profile_VacationMode) String() string { return proto.CompactTextString(m) }
+func (*Profile_VacationMode) ProtoMessage()    {}
+func (*Profile_VacationMode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_744bf7a47b381504, []int{0, 4}
+}
+
+func (m *Profile_VacationMode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Profile_VacationMode.Unmarshal(m, b)
+}
+func (m *Profile_VacationMode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Profile_VacationMode.Marshal(b, m, deterministic)
+}
+func (m *Profile_VacationMode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Profile_VacationMode.Merge(m, src)
+}
+func (m *Profile_VacationMode) XXX_Size() int {
+	return xxx_messageInfo_Profile_VacationMode.Size(m)
+}
+func (m *Profile_VacationMode) XXX_DiscardUnknown() {
+	xxx_messageInfo_Profile_VacationMode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Profile_VacationMode proto.InternalMessageInfo
+
+func (m *Profile_VacationMode) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *Profile_VacationMode) GetReturnDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReturnDate
+	}
+	return nil
+}
+
+func (m *Profile_VacationMode) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 type Profile_Stats struct {
 	FollowerCount        uint32   `protobuf:"varint,1,opt,name=followerCount,proto3" json:"followerCount,omitempty"`
 	FollowingCount       uint32   `protobuf:"varint,2,opt,name=followingCount,proto3" json:"followingCount,omitempty"`
@@ -472,7 +535,7 @@ func (m *Profile_Stats) Reset()         { *m = Profile_Stats{} }
 func (m *Profile_Stats) String() string { return proto.CompactTextString(m) }
 func (*Profile_Stats) ProtoMessage()    {}
 func (*Profile_Stats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_744bf7a47b381504, []int{0, 4}
+	return fileDescriptor_744bf7a47b381504, []int{0, 5}
 }
 
 func (m *Profile_Stats) XXX_Unmarshal(b []byte) error {
@@ -541,56 +604,61 @@ func init() {
 	proto.RegisterType((*Profile_SocialAccount)(nil), "Profile.SocialAccount")
 	proto.RegisterType((*Profile_Image)(nil), "Profile.Image")
 	proto.RegisterType((*Profile_Colors)(nil), "Profile.Colors")
+	proto.RegisterType((*Profile_VacationMode)(nil), "Profile.VacationMode")
 	proto.RegisterType((*Profile_Stats)(nil), "Profile.Stats")
 }
 
 func init() { proto.RegisterFile("profile.proto", fileDescriptor_744bf7a47b381504) }
 
 var fileDescriptor_744bf7a47b381504 = []byte{
-	// 705 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x94, 0xdf, 0x6e, 0xdb, 0x36,
-	0x14, 0xc6, 0xe1, 0xff, 0x31, 0x6d, 0x27, 0x19, 0x31, 0x04, 0x84, 0x30, 0x6c, 0x46, 0x10, 0x6c,
-	0xc6, 0x2e, 0x94, 0xc1, 0xbb, 0x1f, 0xb0, 0x25, 0x17, 0xcb, 0x45, 0x8a, 0x40, 0x49, 0x6f, 0x7a,
-	0x47, 0x49, 0xc7, 0x12, 0x51, 0x49, 0x14, 0x48, 0x2a, 0xa9, 0xd1, 0x47, 0xe8, 0x0b, 0xf4, 0xed,
-	0xfa, 0x18, 0xbd, 0x2d, 0x78, 0x48, 0xc9, 0x56, 0xda, 0x3b, 0x7e, 0xbf, 0xf3, 0x1d, 0xfa, 0x90,
-	0xfe, 0x28, 0xb2, 0xaa, 0x95, 0xdc, 0x89, 0x02, 0xc2, 0x5a, 0x49, 0x23, 0x83, 0xdf, 0x32, 0x29,
-	0xb3, 0x02, 0xae, 0x51, 0xc5, 0xcd, 0xee, 0xda, 0x88, 0x12, 0xb4, 0xe1, 0x65, 0xed, 0x0d, 0x67,
-	0xa5, 0x4c, 0x41, 0x71, 0x23, 0x95, 0x03, 0x97, 0x5f, 0x09, 0x99, 0x3d, 0xb8, 0x3d, 0xe8, 0x05,
-	0x99, 0xd6, 0x00, 0xea, 0xee, 0x96, 0x0d, 0xd6, 0x83, 0xcd, 0x3c, 0xf2, 0xca, 0xf2, 0x9c, 0x57,
-	0x69, 0x01, 0x6c, 0xe8, 0xb8, 0x53, 0x94, 0x92, 0x71, 0xc5, 0x4b, 0x60, 0x23, 0xa4, 0xb8, 0xa6,
-	0x01, 0x39, 0x29, 0x64, 0xc2, 0x8d, 0x90, 0x15, 0x1b, 0x23, 0xef, 0x34, 0xfd, 0x99, 0x4c, 0x78,
-	0x2c, 0x1b, 0xc3, 0x26, 0x58, 0x70, 0x82, 0xfe, 0x49, 0xce, 0x75, 0x2e, 0x95, 0xb9, 0x05, 0x9d,
-	0x28, 0x51, 0x63, 0xe7, 0x14, 0x0d, 0xdf, 0x71, 0xfc, 0x45, 0xbd, 0x7b, 0x61, 0xb3, 0xf5, 0x60,
-	0x73, 0x12, 0xe1, 0xda, 0x4e, 0xf7, 0x0c, 0x55, 0x2a, 0x15, 0x3b, 0x41, 0xea, 0x15, 0xfd, 0x85,
-	0xcc, 0xbb, 0xc3, 0xb2, 0x39, 0x96, 0x0e, 0x80, 0xfe, 0x45, 0x56, 0x9d, 0xb8, 0xab, 0x76, 0x92,
-	0x91, 0xf5, 0x60, 0xb3, 0xd8, 0x92, 0xf0, 0xbe, 0xa5, 0x51, 0xdf, 0x40, 0xb7, 0x64, 0x91, 0xc8,
-	0xca, 0xf0, 0xc4, 0xa0, 0x7f, 0x81, 0xfe, 0xf3, 0xd0, 0x5f, 0x5e, 0x78, 0xe3, 0x6a, 0xd1, 0xb1,
-	0x89, 0xfe, 0x41, 0xa6, 0x89, 0x2c, 0xa4, 0xd2, 0x6c, 0x89, 0xf6, 0xb3, 0x23, 0xbb, 0xc5, 0x91,
-	0x2f, 0xd3, 0x2d, 0x59, 0xf2, 0x67, 0x6e, 0xb8, 0xfa, 0x9f, 0xeb, 0x1c, 0x34, 0x5b, 0xa1, 0xfd,
-	0xb4, 0xb3, 0xdf, 0x95, 0x3c, 0x83, 0xa8, 0xe7, 0xb1, 0x3d, 0x39, 0xf0, 0x14, 0xda, 0x9e, 0xd3,
-	0x1f, 0xf7, 0x1c, 0x7b, 0xe8, 0x15, 0x99, 0x68, 0xc3, 0x8d, 0x66, 0x67, 0xaf, 0xcc, 0x8f, 0x96,
-	0x46, 0xae, 0x48, 0xaf, 0xc8, 0x2a, 0x16, 0x26, 0x91, 0xa2, 0x7a, 0x68, 0xe2, 0xf7, 0xb0, 0x67,
-	0xe7, 0xf8, 0x7f, 0xf4, 0x21, 0xfd, 0x87, 0x2c, 0x0b, 0xae, 0xcd, 0xbd, 0x4c, 0xc5, 0x4e, 0x40,
-	0xca, 0x7e, 0xc2, 0x2d, 0x83, 0xd0, 0x65, 0x30, 0x6c, 0x33, 0x18, 0x3e, 0xb5, 0x19, 0x8c, 0x7a,
-	0x7e, 0xfa, 0x2b, 0x21, 0x49, 0xa3, 0x14, 0x54, 0x89, 0x00, 0xcd, 0xe8, 0x7a, 0xb4, 0x99, 0x47,
-	0x47, 0x24, 0xf8, 0x34, 0x20, 0x33, 0x7f, 0xab, 0x94, 0x91, 0xd9, 0x0b, 0xc4, 0x5a, 0x18, 0xf0,
-	0xd9, 0x6c, 0xa5, 0x0d, 0x15, 0x94, 0x5c, 0x14, 0x3e, 0x9b, 0x4e, 0xd0, 0x35, 0x59, 0xd4, 0xb9,
-	0xac, 0xe0, 0x4d, 0x53, 0xc6, 0xa0, 0x7c, 0x42, 0x8f, 0x11, 0x0d, 0xc9, 0x54, 0xcb, 0x44, 0xf0,
-	0x82, 0x8d, 0xd7, 0xa3, 0xcd, 0x62, 0x7b, 0x71, 0xb8, 0x0a, 0xc4, 0xff, 0x26, 0x89, 0x6c, 0x2a,
-	0x13, 0x79, 0x57, 0xf0, 0x96, 0xac, 0x7a, 0x05, 0x9b, 0x45, 0xb3, 0xaf, 0xdb, 0x79, 0x70, 0x6d,
-	0xd3, 0xdf, 0x68, 0x50, 0xf8, 0x2a, 0xdc, 0x3c, 0x9d, 0xb6, 0x83, 0xd6, 0x4a, 0xca, 0x9d, 0x1f,
-	0xc6, 0x89, 0xe0, 0x23, 0x99, 0xe0, 0xff, 0x84, 0xdb, 0x89, 0x6a, 0xdf, 0x6d, 0x27, 0xaa, 0xbd,
-	0x6d, 0xd1, 0x25, 0x2f, 0xba, 0xb3, 0xa1, 0xb0, 0x81, 0x2f, 0x21, 0x15, 0x4d, 0xe9, 0x77, 0xf2,
-	0xca, 0xba, 0x0b, 0xae, 0x32, 0xf0, 0xef, 0xce, 0x09, 0x3b, 0x92, 0x54, 0x22, 0x13, 0x15, 0x2f,
-	0xfc, 0xbb, 0xeb, 0x74, 0xf0, 0x79, 0x40, 0xa6, 0x2e, 0x88, 0xf6, 0x82, 0x6b, 0x25, 0x4a, 0xae,
-	0xda, 0x09, 0x5a, 0x69, 0xdf, 0x91, 0x86, 0x44, 0x56, 0xa9, 0xad, 0xb9, 0x41, 0x0e, 0x00, 0xc7,
-	0x86, 0x0f, 0xa6, 0xfd, 0x06, 0xd8, 0xb5, 0xed, 0xc8, 0x45, 0x96, 0x17, 0x22, 0xcb, 0x8d, 0x1f,
-	0xe6, 0x00, 0x6c, 0xb8, 0x3a, 0xf1, 0x64, 0x5b, 0xdd, 0x54, 0x7d, 0x18, 0x7c, 0x19, 0x90, 0xc9,
-	0x63, 0x1b, 0xc6, 0x9d, 0x2c, 0x0a, 0xf9, 0x02, 0xea, 0xc6, 0x5e, 0x3c, 0xce, 0xb7, 0x8a, 0xfa,
-	0x90, 0xfe, 0x4e, 0x4e, 0x1d, 0x10, 0x55, 0xe6, 0x6c, 0x43, 0xb4, 0xbd, 0xa2, 0xf4, 0x92, 0x2c,
-	0x0b, 0xa1, 0x4d, 0xe7, 0x1a, 0xa1, 0xab, 0xc7, 0x6c, 0x78, 0x14, 0x3f, 0x58, 0xc6, 0x68, 0x39,
-	0x46, 0xf6, 0x84, 0xb5, 0xd4, 0xc6, 0xd5, 0x27, 0x58, 0x3f, 0x00, 0x3b, 0x31, 0x7f, 0x06, 0x65,
-	0x5f, 0x1f, 0xf6, 0xe0, 0xe7, 0x6c, 0x18, 0xf5, 0xe1, 0x7f, 0xe3, 0x77, 0xc3, 0x3a, 0x8e, 0xa7,
-	0xf8, 0x4c, 0xfe, 0xfe, 0x16, 0x00, 0x00, 0xff, 0xff, 0x8b, 0x91, 0xda, 0x73, 0xc9, 0x05, 0x00,
-	0x00,
+	// 771 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xd1, 0x6e, 0xe4, 0x34,
+	0x14, 0xd5, 0xb4, 0x33, 0xd3, 0xf6, 0xce, 0x4c, 0x5b, 0x0c, 0xac, 0xac, 0x08, 0xc1, 0x68, 0xb5,
+	0x82, 0x11, 0x0f, 0xb3, 0xa8, 0x3c, 0xc1, 0x03, 0x12, 0x6c, 0x1f, 0xe8, 0xc3, 0xa2, 0x55, 0x76,
+	0xe1, 0x81, 0x37, 0x27, 0xb9, 0x93, 0x58, 0x24, 0x76, 0x64, 0x3b, 0x2d, 0x15, 0x12, 0x3f, 0xc0,
+	0x0f, 0xf0, 0x1d, 0xfc, 0x10, 0xbf, 0x82, 0x7c, 0xed, 0x64, 0x92, 0x82, 0x78, 0xf3, 0x39, 0xf7,
+	0x5c, 0xe7, 0xc4, 0x3e, 0xd7, 0xb0, 0x69, 0x8d, 0x3e, 0xc8, 0x1a, 0xf7, 0xad, 0xd1, 0x4e, 0x27,
+	0x9f, 0x94, 0x5a, 0x97, 0x35, 0xbe, 0x24, 0x94, 0x75, 0x87, 0x97, 0x4e, 0x36, 0x68, 0x9d, 0x68,
+	0xda, 0x28, 0xb8, 0x6a, 0x74, 0x81, 0x46, 0x38, 0x6d, 0x02, 0xf1, 0xfc, 0xaf, 0x35, 0x9c, 0xbd,
+	0x09, 0x7b, 0xb0, 0x67, 0xb0, 0x6c, 0x11, 0xcd, 0xdd, 0x2d, 0x9f, 0x6d, 0x67, 0xbb, 0x8b, 0x34,
+	0x22, 0xcf, 0x57, 0x42, 0x15, 0x35, 0xf2, 0x93, 0xc0, 0x07, 0xc4, 0x18, 0xcc, 0x95, 0x68, 0x90,
+	0x9f, 0x12, 0x4b, 0x6b, 0x96, 0xc0, 0x79, 0xad, 0x73, 0xe1, 0xa4, 0x56, 0x7c, 0x4e, 0xfc, 0x80,
+	0xd9, 0x07, 0xb0, 0x10, 0x99, 0xee, 0x1c, 0x5f, 0x50, 0x21, 0x00, 0xf6, 0x39, 0x5c, 0xdb, 0x4a,
+	0x1b, 0x77, 0x8b, 0x36, 0x37, 0xb2, 0xa5, 0xce, 0x25, 0x09, 0xfe, 0xc5, 0xd3, 0x17, 0xed, 0xe1,
+	0x81, 0x9f, 0x6d, 0x67, 0xbb, 0xf3, 0x94, 0xd6, 0xde, 0xdd, 0x3d, 0xaa, 0x42, 0x1b, 0x7e, 0x4e,
+	0x6c, 0x44, 0xec, 0x23, 0xb8, 0x18, 0x7e, 0x96, 0x5f, 0x50, 0xe9, 0x48, 0xb0, 0x2f, 0x60, 0x33,
+	0x80, 0x3b, 0x75, 0xd0, 0x1c, 0xb6, 0xb3, 0xdd, 0xea, 0x06, 0xf6, 0xaf, 0x7b, 0x36, 0x9d, 0x0a,
+	0xd8, 0x0d, 0xac, 0x72, 0xad, 0x9c, 0xc8, 0x1d, 0xe9, 0x57, 0xa4, 0xbf, 0xde, 0xc7, 0xc3, 0xdb,
+	0xbf, 0x0a, 0xb5, 0x74, 0x2c, 0x62, 0x9f, 0xc1, 0x32, 0xd7, 0xb5, 0x36, 0x96, 0xaf, 0x49, 0x7e,
+	0x35, 0x92, 0x7b, 0x3a, 0x8d, 0x65, 0x76, 0x03, 0x6b, 0x71, 0x2f, 0x9c, 0x30, 0xdf, 0x0b, 0x5b,
+	0xa1, 0xe5, 0x1b, 0x92, 0x5f, 0x0e, 0xf2, 0xbb, 0x46, 0x94, 0x98, 0x4e, 0x34, 0xbe, 0xa7, 0x42,
+	0x51, 0x60, 0xdf, 0x73, 0xf9, 0xdf, 0x3d, 0x63, 0x0d, 0x7b, 0x01, 0x0b, 0xeb, 0x84, 0xb3, 0xfc,
+	0xea, 0x89, 0xf8, 0xad, 0x67, 0xd3, 0x50, 0x64, 0x2f, 0x60, 0x93, 0x49, 0x97, 0x6b, 0xa9, 0xde,
+	0x74, 0xd9, 0x2f, 0xf8, 0xc8, 0xaf, 0xe9, 0x3e, 0xa6, 0x24, 0xfb, 0x06, 0xd6, 0xb5, 0xb0, 0xee,
+	0xb5, 0x2e, 0xe4, 0x41, 0x62, 0xc1, 0xdf, 0xa3, 0x2d, 0x93, 0x7d, 0xc8, 0xe0, 0xbe, 0xcf, 0xe0,
+	0xfe, 0x5d, 0x9f, 0xc1, 0x74, 0xa2, 0x67, 0x1f, 0x03, 0xe4, 0x9d, 0x31, 0xa8, 0x72, 0x89, 0x96,
+	0xb3, 0xed, 0xe9, 0xee, 0x22, 0x1d, 0x31, 0xec, 0x2b, 0x58, 0xdf, 0x8b, 0x10, 0x1d, 0x7f, 0x29,
+	0xfc, 0x7d, 0xda, 0xff, 0xc3, 0xc1, 0xf2, 0x4f, 0xa3, 0x62, 0x3a, 0x91, 0x26, 0x7f, 0xcc, 0xe0,
+	0x2c, 0x5e, 0x08, 0xe3, 0x70, 0xf6, 0x80, 0x99, 0x95, 0x0e, 0x63, 0xac, 0x7b, 0xe8, 0xf3, 0x88,
+	0x8d, 0x90, 0x75, 0x8c, 0x75, 0x00, 0x6c, 0x0b, 0xab, 0xb6, 0xd2, 0x0a, 0x7f, 0xe8, 0x9a, 0x0c,
+	0x4d, 0x0c, 0xf7, 0x98, 0x62, 0x7b, 0x58, 0x5a, 0x9d, 0x4b, 0x51, 0xf3, 0xf9, 0xf6, 0x74, 0xb7,
+	0xba, 0x79, 0x76, 0x3c, 0x45, 0xa2, 0xbf, 0xcd, 0x73, 0xdd, 0x29, 0x97, 0x46, 0x55, 0xf2, 0x23,
+	0x6c, 0x26, 0x05, 0x1f, 0x63, 0xf7, 0xd8, 0xf6, 0x7e, 0x68, 0xed, 0x07, 0xa7, 0xb3, 0x68, 0x68,
+	0xa0, 0x82, 0x9f, 0x01, 0x7b, 0xa3, 0xad, 0xd1, 0xfa, 0x10, 0xcd, 0x04, 0x90, 0xfc, 0x06, 0x0b,
+	0xba, 0x62, 0xda, 0x4e, 0xaa, 0xc7, 0x61, 0x3b, 0xa9, 0x1e, 0x7d, 0x8b, 0x6d, 0x44, 0x3d, 0xfc,
+	0x1b, 0x01, 0x3f, 0x2b, 0x0d, 0x16, 0xb2, 0x6b, 0xe2, 0x4e, 0x11, 0x79, 0x75, 0x2d, 0x4c, 0x89,
+	0x71, 0x64, 0x03, 0xf0, 0x96, 0xb4, 0x91, 0xa5, 0x54, 0xa2, 0x8e, 0x23, 0x3b, 0xe0, 0xe4, 0xcf,
+	0x19, 0x2c, 0x43, 0x86, 0xfd, 0x01, 0xb7, 0x46, 0x36, 0xc2, 0xf4, 0x0e, 0x7a, 0xe8, 0x47, 0xd0,
+	0x62, 0xae, 0x55, 0xe1, 0x6b, 0xc1, 0xc8, 0x91, 0x20, 0xdb, 0xf8, 0xab, 0xeb, 0x9f, 0x0f, 0xbf,
+	0xf6, 0x1d, 0x95, 0x2c, 0xab, 0x5a, 0x96, 0x95, 0x8b, 0x66, 0x8e, 0x84, 0xcf, 0xe5, 0x00, 0xde,
+	0xf9, 0xd6, 0xe0, 0x6a, 0x4a, 0x26, 0xbf, 0xc3, 0x7a, 0x1c, 0x0d, 0xef, 0x0f, 0x95, 0xc8, 0x6a,
+	0x2c, 0xc8, 0xdf, 0x79, 0xda, 0x43, 0xf6, 0x35, 0x80, 0x41, 0xd7, 0x19, 0x75, 0x2b, 0x5c, 0x38,
+	0xf5, 0xff, 0xcf, 0xef, 0x48, 0xed, 0x77, 0x6d, 0xd0, 0x5a, 0x51, 0xf6, 0xef, 0x5f, 0x0f, 0x93,
+	0xbf, 0x67, 0xb0, 0x78, 0xdb, 0xcf, 0xd1, 0x41, 0xd7, 0xb5, 0x7e, 0x40, 0xf3, 0xca, 0x5f, 0x3c,
+	0x7d, 0x7f, 0x93, 0x4e, 0x49, 0xf6, 0x29, 0x5c, 0x06, 0x42, 0xaa, 0x32, 0xc8, 0x4e, 0x48, 0xf6,
+	0x84, 0x65, 0xcf, 0x61, 0x5d, 0x4b, 0xeb, 0x06, 0xd5, 0x29, 0xa9, 0x26, 0x9c, 0x0f, 0xaf, 0x11,
+	0x47, 0xc9, 0x9c, 0x24, 0x63, 0xca, 0x9f, 0x70, 0xab, 0xad, 0x0b, 0xf5, 0x05, 0xd5, 0x8f, 0x84,
+	0x77, 0x2c, 0xee, 0xd1, 0xf8, 0x87, 0x83, 0x7a, 0xe8, 0x25, 0x3e, 0x49, 0xa7, 0xe4, 0x77, 0xf3,
+	0x9f, 0x4f, 0xda, 0x2c, 0x5b, 0xd2, 0x09, 0x7d, 0xf9, 0xcf, 0x00, 0xfc, 0x62, 0xdd, 0xb6, 0x84,
+	0x06, 0x00, 0x00,
 }
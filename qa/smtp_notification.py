@@ -4,6 +4,8 @@ import time
 import subprocess
 import re
 import os
+import email
+import email.header
 
 from collections import OrderedDict
 from test_framework.test_framework import OpenBazaarTestFramework, TestFailure
@@ -133,26 +135,28 @@ class SMTPTest(OpenBazaarTestFramework):
         proc.terminate()
 
         # check notification
-        expected = '''From: openbazaar@test.org
-To: user.openbazaar@test.org
-MIME-Version: 1.0
-Content-Type: text/html; charset=UTF-8
-Subject: [OpenBazaar] Order received
-
-You received an order "Ron Swanson Tshirt".
-
-Order ID: QmNiPgKNq27qQE8fRxMbtDfRcFDEYMH5wDRgdqtqoWBpGg
-Buyer: Qmd5qDpcYkHCmkj9pMXU9TKBqEDWgEmtoHD5xjdJgumaHg
-Thumbnail: QmXSEqXLCzpCByJU4wqbJ37TcBEj77FKMUWUP1qLh56847
-Timestamp: 1487699826
-'''
-        expected_lines = [e for e in expected.splitlines() if not e.startswith('Timestamp:') and not e.startswith('Order ID:')]
-        with open(SMTP_DUMPFILE, 'r') as f:
-            res_lines = [l.strip() for l in f.readlines() if not l.startswith('Timestamp') and not l.startswith('Order ID:')]
-            if res_lines != expected_lines:
-                os.remove(SMTP_DUMPFILE)
-                raise TestFailure("SMTPTest - FAIL: Incorrect mail data received")
+        with open(SMTP_DUMPFILE, 'rb') as f:
+            msg = email.message_from_bytes(f.read())
         os.remove(SMTP_DUMPFILE)
+        subject = str(email.header.make_header(email.header.decode_header(msg["Subject"])))
+        if msg["From"] != "openbazaar@test.org" or msg["To"] != "user.openbazaar@test.org":
+            raise TestFailure("SMTPTest - FAIL: Incorrect mail addresses received")
+        if subject != "[Phore Marketplace] Order received":
+            raise TestFailure("SMTPTest - FAIL: Incorrect mail subject received")
+        if msg.get_content_type() != "multipart/alternative":
+            raise TestFailure("SMTPTest - FAIL: Expected a multipart email")
+        parts = {}
+        for part in msg.walk():
+            if not part.is_multipart():
+                parts[part.get_content_type()] = part.get_payload(decode=True).decode("utf-8")
+        text = parts.get("text/plain", "")
+        html = parts.get("text/html", "")
+        if 'You received an order "Ron Swanson Tshirt".' not in text or "Listing: Ron Swanson Tshirt" not in text:
+            raise TestFailure("SMTPTest - FAIL: Incorrect text mail data received")
+        if "<strong>Ron Swanson Tshirt</strong>" not in html or orderId not in html:
+            raise TestFailure("SMTPTest - FAIL: Incorrect HTML mail data received")
+        if 'href="ob://' + alice["peerId"] + '/store/' not in html:
+            raise TestFailure("SMTPTest - FAIL: Expected a link to the listing")
         print("SMTPTest - PASS")
 
 if __name__ == '__main__':
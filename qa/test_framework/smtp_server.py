@@ -5,5 +5,7 @@ SMTP_DUMPFILE = 'mail.dump'
 
 class SMTPTestServer(smtpd.SMTPServer):
     def process_message(self, peer, mailfrom, rcpttos, data, **kwargs):
-        with open(SMTP_DUMPFILE, 'w') as f:
+        if isinstance(data, str):
+            data = data.encode('utf-8')
+        with open(SMTP_DUMPFILE, 'wb') as f:
             f.write(data)
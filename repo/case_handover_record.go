@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/pb"
+)
+
+// CaseHandoverRecord tracks a moderator's request to hand an open dispute
+// over to a backup moderator along with the responses of both parties. The
+// same record shape is kept by the original moderator, the buyer, the vendor
+// and the backup moderator.
+type CaseHandoverRecord struct {
+	OrderID         string
+	Moderator       string
+	BackupModerator string
+	BuyerID         string
+	VendorID        string
+	BuyerAccepted   bool
+	VendorAccepted  bool
+	BuyerRejected   bool
+	VendorRejected  bool
+	SignedHandover  *pb.SignedData
+	Timestamp       time.Time
+
+	// TransferSigs holds the original moderator's copy of the first party's
+	// signatures moving the escrow to the backup moderator. TransferTxid is
+	// set once the transfer has been broadcast.
+	TransferSigs []*pb.BitcoinSignature
+	TransferTxid string
+}
+
+// IsAccepted returns true once both the buyer and the vendor have accepted
+// the handover and neither party has rejected it
+func (r *CaseHandoverRecord) IsAccepted() bool {
+	return r.BuyerAccepted && r.VendorAccepted && !r.IsRejected()
+}
+
+// IsRejected returns true while either party's latest response rejects the
+// handover
+func (r *CaseHandoverRecord) IsRejected() bool {
+	return r.BuyerRejected || r.VendorRejected
+}
+
+// SetResponse records the latest response of the buyer or vendor. Accepting
+// withdraws an earlier rejection by the same party.
+func (r *CaseHandoverRecord) SetResponse(peerID string, accepted bool) bool {
+	switch peerID {
+	case r.BuyerID:
+		r.BuyerAccepted, r.BuyerRejected = accepted, !accepted
+	case r.VendorID:
+		r.VendorAccepted, r.VendorRejected = accepted, !accepted
+	default:
+		return false
+	}
+	return true
+}
@@ -8,29 +8,40 @@ const (
 
 	NotifierTypeBuyerDisputeTimeout           NotificationType = "buyerDisputeTimeout"
 	NotifierTypeBuyerDisputeExpiry            NotificationType = "buyerDisputeExpiry"
+	NotifierTypeCaseHandover                  NotificationType = "caseHandover"
+	NotifierTypeCaseHandoverResponse          NotificationType = "caseHandoverResponse"
+	NotifierTypeChatChange                    NotificationType = "chatChange"
+	NotifierTypeChatGroupUpdate               NotificationType = "chatGroupUpdate"
 	NotifierTypeChatMessage                   NotificationType = "chatMessage"
 	NotifierTypeChatRead                      NotificationType = "chatRead"
 	NotifierTypeChatTyping                    NotificationType = "chatTyping"
 	NotifierTypeCompletionNotification        NotificationType = "orderComplete"
 	NotifierTypeDisputeAcceptedNotification   NotificationType = "disputeAccepted"
 	NotifierTypeDisputeCloseNotification      NotificationType = "disputeClose"
+	NotifierTypeDisputeFallbackPayout         NotificationType = "disputeFallbackPayout"
 	NotifierTypeDisputeOpenNotification       NotificationType = "disputeOpen"
+	NotifierTypeDisputePanelVote              NotificationType = "disputePanelVote"
 	NotifierTypeDisputeUpdateNotification     NotificationType = "disputeUpdate"
+	NotifierTypeFeedEntry                     NotificationType = "feedEntry"
 	NotifierTypeFindModeratorResponse         NotificationType = "findModeratorResponse"
 	NotifierTypeFollowNotification            NotificationType = "follow"
 	NotifierTypeFulfillmentNotification       NotificationType = "fulfillment"
+	NotifierTypeFundingRevoked                NotificationType = "fundingRevoked"
 	NotifierTypeIncomingTransaction           NotificationType = "incomingTransaction"
 	NotifierTypeModeratorAddNotification      NotificationType = "moderatorAdd"
 	NotifierTypeModeratorDisputeExpiry        NotificationType = "moderatorDisputeExpiry"
 	NotifierTypeModeratorRemoveNotification   NotificationType = "moderatorRemove"
+	NotifierTypeModeratorReplacedNotification NotificationType = "moderatorReplaced"
 	NotifierTypeOrderCancelNotification       NotificationType = "cancel"
 	NotifierTypeOrderConfirmationNotification NotificationType = "orderConfirmation"
 	NotifierTypeOrderDeclinedNotification     NotificationType = "orderDeclined"
 	NotifierTypeOrderNewNotification          NotificationType = "order"
 	NotifierTypePaymentNotification           NotificationType = "payment"
+	NotifierTypePostComment                   NotificationType = "postComment"
 	NotifierTypePremarshalledNotifier         NotificationType = "premarshalledNotifier"
 	NotifierTypeProcessingErrorNotification   NotificationType = "processingError"
 	NotifierTypeRefundNotification            NotificationType = "refund"
+	NotifierTypeSigningRequest                NotificationType = "signingRequest"
 	NotifierTypeStatusUpdateNotification      NotificationType = "statusUpdate"
 	NotifierTypeTestNotification              NotificationType = "testNotification"
 	NotifierTypeUnfollowNotification          NotificationType = "unfollow"
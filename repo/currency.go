@@ -11,6 +11,7 @@ import (
 const (
 	CurrencyCodeValidMinimumLength = 3
 	CurrencyCodeValidMaximumLength = 4
+	TokenCodeValidMaximumLength    = 5
 )
 
 var (
@@ -19,6 +19,9 @@ var (
 	ErrCurrencyTypeInvalid             = errors.New("currency type must be crypto or fiat")
 	ErrCurrencyDivisibilityNonPositive = errors.New("currency divisibility most be greater than zero")
 	ErrDictionaryIndexMismatchedCode   = errors.New("dictionary index mismatched with definition currency code")
+	ErrTokenCodeLengthInvalid          = errors.New("invalid length for token currency code, must be three to five characters")
+	ErrTokenDivisibilityInvalid        = errors.New("token divisibility must not exceed its chain divisibility")
+	ErrCurrencyDefinitionConflict      = errors.New("currency code is already defined")
 
 	validatedMainnetCurrencyDefs map[string]*CurrencyDefinition
 	mainnetCurrencyDefinitions   = map[string]*CurrencyDefinition{
@@ -200,6 +203,11 @@ type (
 		Code         CurrencyCode
 		Divisibility uint
 		CurrencyType string
+
+		// ChainDivisibility is set for tokens and is the number of decimals
+		// of the token contract. Divisibility is then the precision the node
+		// prices in, which may be lower.
+		ChainDivisibility uint
 	}
 	// CurrencyDictionaryProcessingError represents a list of errors after
 	// processing a CurrencyDictionary
@@ -239,6 +247,27 @@ func LoadCurrencyDefinitions() CurrencyDictionary {
 	return validatedMainnetCurrencyDefs
 }
 
+// RegisterCurrencyDefinition adds a token's mainnet definition to the
+// dictionary returned by LoadCurrencyDefinitions. It must be called while the
+// node starts and before the dictionary is used.
+func RegisterCurrencyDefinition(def *CurrencyDefinition) error {
+	if err := def.Valid(); err != nil {
+		return err
+	}
+	dict := LoadCurrencyDefinitions()
+	if existing, ok := dict[def.Code.String()]; ok {
+		if existing.Equal(def) {
+			return nil
+		}
+		return ErrCurrencyDefinitionConflict
+	}
+	dict[def.Code.String()] = def
+	return nil
+}
+
+// IsToken indicates if the definition is of a token
+func (c *CurrencyDefinition) IsToken() bool { return c != nil && c.ChainDivisibility > 0 }
+
 // NewCurrencyDictionary returns a CurrencyDictionary for managing CurrencyDefinitions
 func NewCurrencyDictionary(defs map[string]*CurrencyDefinition) (CurrencyDictionary, error) {
 	var errs = make(CurrencyDictionaryProcessingError)
@@ -262,11 +291,21 @@ func (c *CurrencyDefinition) Valid() error {
 	if c == nil {
 		return ErrCurrencyDefinitionUndefined
 	}
-	if len(c.Code) < CurrencyCodeValidMinimumLength || len(c.Code) > CurrencyCodeValidMaximumLength {
-		return ErrCurrencyCodeLengthInvalid
-	}
-	if len(c.Code) == 4 && strings.Index(strings.ToLower(string(c.Code)), "t") != 0 {
-		return ErrCurrencyCodeTestSymbolInvalid
+	if c.IsToken() {
+		// Token codes like USDC don't follow the testnet prefix rule
+		if len(c.Code) < CurrencyCodeValidMinimumLength || len(c.Code) > TokenCodeValidMaximumLength {
+			return ErrTokenCodeLengthInvalid
+		}
+		if c.Divisibility > c.ChainDivisibility {
+			return ErrTokenDivisibilityInvalid
+		}
+	} else {
+		if len(c.Code) < CurrencyCodeValidMinimumLength || len(c.Code) > CurrencyCodeValidMaximumLength {
+			return ErrCurrencyCodeLengthInvalid
+		}
+		if len(c.Code) == 4 && strings.Index(strings.ToLower(string(c.Code)), "t") != 0 {
+			return ErrCurrencyCodeTestSymbolInvalid
+		}
 	}
 	if c.CurrencyType != Crypto && c.CurrencyType != Fiat {
 		return ErrCurrencyTypeInvalid
@@ -278,8 +317,8 @@ func (c *CurrencyDefinition) Valid() error {
 	return nil
 }
 
-// Equal indicates if the receiver and other have the same code
-// and divisibility
+// Equal indicates if the receiver and other have the same code,
+// divisibility and type
 func (c *CurrencyDefinition) Equal(other *CurrencyDefinition) bool {
 	if c == nil || other == nil {
 		return false
@@ -293,6 +332,9 @@ func (c *CurrencyDefinition) Equal(other *CurrencyDefinition) bool {
 	if c.CurrencyType != other.CurrencyType {
 		return false
 	}
+	if c.ChainDivisibility != other.ChainDivisibility {
+		return false
+	}
 	return true
 }
 
@@ -307,6 +349,10 @@ func (c CurrencyDictionary) Lookup(code string) (*CurrencyDefinition, error) {
 		def *CurrencyDefinition
 		ok  bool
 	)
+	// Token codes may begin with a T themselves
+	if def, ok = c[upcase]; ok && def.IsToken() {
+		return def, nil
+	}
 	if isTestnet {
 		def, ok = c[strings.TrimPrefix(upcase, "T")]
 	} else {
@@ -327,5 +373,7 @@ func NewTestnetDefinition(def *CurrencyDefinition) *CurrencyDefinition {
 		Code:         CurrencyCode(fmt.Sprintf("T%s", def.Code)),
 		Divisibility: def.Divisibility,
 		CurrencyType: def.CurrencyType,
+
+		ChainDivisibility: def.ChainDivisibility,
 	}
 }
@@ -135,6 +135,42 @@ func TestCurrencyDefinitionValidation(t *testing.T) {
 			expectErr: repo.ErrCurrencyDefinitionUndefined,
 			input:     nil,
 		},
+		{ // valid 4-char token
+			expectErr: nil,
+			input: &repo.CurrencyDefinition{
+				Code:              repo.CurrencyCode("USDC"),
+				Divisibility:      6,
+				CurrencyType:      repo.Crypto,
+				ChainDivisibility: 6,
+			},
+		},
+		{ // valid testnet token
+			expectErr: nil,
+			input: &repo.CurrencyDefinition{
+				Code:              repo.CurrencyCode("TUSDC"),
+				Divisibility:      8,
+				CurrencyType:      repo.Crypto,
+				ChainDivisibility: 18,
+			},
+		},
+		{ // error invalid token code length
+			expectErr: repo.ErrTokenCodeLengthInvalid,
+			input: &repo.CurrencyDefinition{
+				Code:              repo.CurrencyCode("TOOLONG"),
+				Divisibility:      8,
+				CurrencyType:      repo.Crypto,
+				ChainDivisibility: 18,
+			},
+		},
+		{ // error token divisibility beyond its chain divisibility
+			expectErr: repo.ErrTokenDivisibilityInvalid,
+			input: &repo.CurrencyDefinition{
+				Code:              repo.CurrencyCode("USDC"),
+				Divisibility:      8,
+				CurrencyType:      repo.Crypto,
+				ChainDivisibility: 6,
+			},
+		},
 	}
 
 	for _, e := range examples {
@@ -214,6 +250,56 @@ func TestCurrencyDictionaryLookup(t *testing.T) {
 	}
 }
 
+func TestCurrencyDictionaryLookupToken(t *testing.T) {
+	var (
+		token = &repo.CurrencyDefinition{Name: "True USD", Code: "TUSD", Divisibility: 8, CurrencyType: repo.Crypto, ChainDivisibility: 18}
+		dict  = repo.CurrencyDictionary{
+			"USD":  factory.NewCurrencyDefinition("USD"),
+			"TUSD": token,
+		}
+	)
+	def, err := dict.Lookup("tusd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !def.Equal(token) {
+		t.Errorf("expected the token (%s), but got (%s)", token, def)
+	}
+	def, err = dict.Lookup("TTUSD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def.Code != "TTUSD" || def.ChainDivisibility != 18 || def.Divisibility != 8 {
+		t.Errorf("unexpected testnet token definition %+v", def)
+	}
+}
+
+func TestRegisterCurrencyDefinition(t *testing.T) {
+	token := &repo.CurrencyDefinition{Name: "Test Token", Code: "TSTKN", Divisibility: 8, CurrencyType: repo.Crypto, ChainDivisibility: 18}
+	if err := repo.RegisterCurrencyDefinition(token); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.RegisterCurrencyDefinition(token); err != nil {
+		t.Errorf("expected registering the same definition again to succeed, got %s", err)
+	}
+	def, err := repo.LoadCurrencyDefinitions().Lookup("TSTKN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !def.Equal(token) {
+		t.Errorf("expected (%s), but got (%s)", token, def)
+	}
+
+	conflicting := *token
+	conflicting.Divisibility = 6
+	if err := repo.RegisterCurrencyDefinition(&conflicting); err != repo.ErrCurrencyDefinitionConflict {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+	if err := repo.RegisterCurrencyDefinition(&repo.CurrencyDefinition{Code: "XYZ", Divisibility: 8, CurrencyType: repo.Crypto, ChainDivisibility: 6}); err != repo.ErrTokenDivisibilityInvalid {
+		t.Errorf("expected an invalid definition to be refused, got %v", err)
+	}
+}
+
 func TestCurrencyDictionaryValid(t *testing.T) {
 	var (
 		valid      = factory.NewCurrencyDefinition("BTC")
@@ -28,6 +28,16 @@ type Datastore interface {
 	Coupons() CouponStore
 	TxMetadata() TransactionMetadataStore
 	ModeratedStores() ModeratedStore
+	CaseHandovers() CaseHandoverStore
+	PanelVotes() PanelVoteStore
+	RatchetSessions() RatchetSessionStore
+	WebhookDeliveries() WebhookDeliveryStore
+	PostComments() PostCommentStore
+	Feed() FeedStore
+	SigningRequests() SigningRequestStore
+	TxLabels() TransactionLabelStore
+	TxFiatValues() TransactionFiatValueStore
+	ExchangeRates() ExchangeRateStore
 	Ping() error
 	Close()
 }
@@ -340,6 +350,57 @@ type ChatStore interface {
 
 	// Delete all messages from from a peer
 	DeleteConversation(peerID string) error
+
+	// Put the attachments of a chat message
+	PutAttachments(messageID string, attachments []ChatAttachment) error
+
+	// Return an attachment of a chat message including its file if it
+	// has been downloaded
+	GetAttachment(messageID string, index int) (*ChatAttachment, error)
+
+	// Save the downloaded file of an attachment
+	PutAttachmentData(messageID string, index int, data []byte) error
+
+	// Put a group conversation or replace it with a newer member list
+	PutGroup(group ChatGroup) error
+
+	// Return a group conversation
+	GetGroup(groupID string) (*ChatGroup, error)
+
+	// Returns a list of group conversations with their unread counts
+	GetGroupConversations() []ChatGroupConversation
+
+	// Put a new message to a group conversation
+	PutGroupMessage(messageID string, groupID string, peerID string, message string, timestamp time.Time, read bool, outgoing bool) error
+
+	// A list of messages in a group conversation
+	GetGroupMessages(groupID string, offsetID string, limit int) []ChatMessage
+
+	// Mark all incoming messages of a group as read. Returns whether any
+	// messages were updated.
+	MarkGroupAsRead(groupID string) (bool, error)
+
+	// Returns the incoming unread count of a group conversation
+	GetGroupUnreadCount(groupID string) (int, error)
+
+	// Delete a group conversation and its messages
+	DeleteGroup(groupID string) error
+
+	// Return a direct or group chat message
+	GetMessage(messageID string) (*ChatMessage, error)
+
+	// Save an edit or retraction to the edit history and apply it to the
+	// message. A retraction deletes the message but keeps its history.
+	PutEdit(edit ChatEdit) error
+
+	// Return the edit history of a message, oldest first
+	GetEdits(messageID string) ([]ChatEdit, error)
+
+	// Put a reaction to a message
+	PutReaction(messageID string, peerID string, reaction string, timestamp time.Time) error
+
+	// Delete a reaction to a message
+	DeleteReaction(messageID string, peerID string, reaction string) error
 }
 
 // Notifications interface defines basic database operations for notification information
@@ -396,6 +457,195 @@ type TransactionMetadataStore interface {
 	Delete(txid string) error
 }
 
+// TransactionLabelStore interface defines basic database operations for the
+// labels of wallet transactions and addresses
+type TransactionLabelStore interface {
+	Queryable
+
+	// Put a label, replacing any earlier one for its target
+	Put(label TransactionLabel) error
+
+	// Get the label for a txid or address
+	Get(target string) (*TransactionLabel, error)
+
+	// GetAll returns a map of each target to its label
+	GetAll() (map[string]TransactionLabel, error)
+
+	// Delete the label for a target
+	Delete(target string) error
+}
+
+// TransactionFiatValueStore interface defines basic database operations for
+// the exchange rates of wallet transactions
+type TransactionFiatValueStore interface {
+	Queryable
+
+	// Put the rate of a transaction
+	Put(value TransactionFiatValue) error
+
+	// Get the rate of a transaction
+	Get(txid string) (*TransactionFiatValue, error)
+
+	// GetAll returns a map of each txid to its rate
+	GetAll() (map[string]TransactionFiatValue, error)
+}
+
+// ExchangeRateStore interface defines basic database operations for the
+// history of exchange rates and the rates orders were priced at
+type ExchangeRateStore interface {
+	Queryable
+
+	// Put samples of exchange rates
+	Put(rates ...ExchangeRate) error
+
+	// GetAt returns the sample of the coin's rate in the currency nearest
+	// to the time
+	GetAt(coin, currency string, t time.Time) (*ExchangeRate, error)
+
+	// GetRange returns the samples of the coin's rate in the currency taken
+	// from the start up to the end, oldest first
+	GetRange(coin, currency string, from, to time.Time) ([]ExchangeRate, error)
+
+	// PutOrderRates saves the rates used to price an order
+	PutOrderRates(orderID string, rates []ExchangeRate) error
+
+	// GetOrderRates returns the rates used to price an order
+	GetOrderRates(orderID string) ([]ExchangeRate, error)
+}
+
+// CaseHandoverStore interface defines basic database operations for disputes
+// being handed over to a backup moderator
+type CaseHandoverStore interface {
+	Queryable
+
+	// Put a new handover record or replace an existing one
+	Put(record *CaseHandoverRecord) error
+
+	// Get the handover record for an order
+	Get(orderID string) (*CaseHandoverRecord, error)
+
+	// GetAll returns every handover record, newest first
+	GetAll() ([]*CaseHandoverRecord, error)
+
+	// Delete the handover record for an order
+	Delete(orderID string) error
+}
+
+// PanelVoteStore interface defines basic database operations for the
+// dispute resolutions proposed by the members of a moderator panel
+type PanelVoteStore interface {
+	Queryable
+
+	// Put a panel member's proposed resolution, replacing any earlier one
+	Put(orderID, moderatorID string, vote *pb.RicardianContract, timestamp time.Time) error
+
+	// GetByOrderID returns every proposed resolution for an order, oldest first
+	GetByOrderID(orderID string) ([]*pb.RicardianContract, error)
+
+	// DeleteByOrderID removes the proposed resolutions for an order
+	DeleteByOrderID(orderID string) error
+}
+
+// RatchetSessionStore interface defines basic database operations for the
+// double ratchet chat sessions with peers which can receive them
+type RatchetSessionStore interface {
+	Queryable
+
+	// MarkCapable records that a peer can receive ratcheted chat messages
+	MarkCapable(peerID string) error
+
+	// Get returns whether a peer can receive ratcheted chat messages and
+	// the session with the peer if one has been started
+	Get(peerID string) (state []byte, capable bool, err error)
+
+	// Put saves the session with a peer
+	Put(peerID string, state []byte) error
+
+	// Delete removes the session with a peer and forgets it is capable
+	Delete(peerID string) error
+}
+
+// WebhookDeliveryStore interface defines basic database operations for the
+// log of notifications posted to webhooks
+type WebhookDeliveryStore interface {
+	Queryable
+
+	// Put a delivery, replacing any earlier record with the same ID
+	Put(delivery WebhookDelivery) error
+
+	// Get a delivery by its ID
+	Get(deliveryID string) (*WebhookDelivery, error)
+
+	/* GetAll returns the deliveries with the given status, or every delivery
+	   if status is empty, newest first.
+	   The offset and limit arguments can be used to for lazy loading. */
+	GetAll(status, offsetID string, limit int) ([]WebhookDelivery, error)
+}
+
+// PostCommentStore interface defines basic database operations for the
+// comments and reposts other peers made on our posts
+type PostCommentStore interface {
+	Queryable
+
+	// Put a comment, replacing any earlier version of it
+	Put(comment PostComment) error
+
+	// Get a comment by its ID
+	Get(commentID string) (*PostComment, error)
+
+	// GetByPost returns the comments on one of our posts, oldest first
+	GetByPost(postSlug string) ([]PostComment, error)
+
+	// DeleteByPost removes the comments on one of our posts
+	DeleteByPost(postSlug string) error
+}
+
+// FeedStore interface defines basic database operations for the posts and
+// listings collected from the peers we follow
+type FeedStore interface {
+	Queryable
+
+	// Put an entry, replacing any earlier version of it
+	Put(entry FeedEntry) error
+
+	// Get an entry by its ID
+	Get(entryID string) (*FeedEntry, error)
+
+	/* GetAll returns the entries of the given type, or every entry if
+	   entryType is empty, newest first, leaving out the entries of the
+	   excluded peers.
+	   The offset and limit arguments can be used to for lazy loading. */
+	GetAll(entryType, offsetID string, limit int, excludePeers []string) ([]FeedEntry, error)
+
+	// GetByPeer returns the entries collected from a peer
+	GetByPeer(peerID string) ([]FeedEntry, error)
+
+	// Delete an entry
+	Delete(entryID string) error
+
+	// DeleteByPeer removes the entries collected from a peer
+	DeleteByPeer(peerID string) error
+}
+
+// SigningRequestStore interface defines basic database operations for the
+// transactions waiting to be signed outside the node
+type SigningRequestStore interface {
+	Queryable
+
+	// Put a request, replacing any earlier version of it
+	Put(request SigningRequest) error
+
+	// Get a request by its ID
+	Get(requestID string) (*SigningRequest, error)
+
+	// GetAll returns the requests in the given state for the given order,
+	// newest first. Empty arguments match every request.
+	GetAll(state, orderID string) ([]SigningRequest, error)
+
+	// Delete a request
+	Delete(requestID string) error
+}
+
 // ModeratedStores interface defines basic database operations for moderated stores
 type ModeratedStore interface {
 	Queryable
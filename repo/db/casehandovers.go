@@ -0,0 +1,147 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type CaseHandoversDB struct {
+	modelStore
+}
+
+func NewCaseHandoverStore(db *sql.DB, lock *sync.Mutex) repo.CaseHandoverStore {
+	return &CaseHandoversDB{modelStore{db, lock}}
+}
+
+func (c *CaseHandoversDB) Put(record *repo.CaseHandoverRecord) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var (
+		signedHandover, transferSigs []byte
+		err                          error
+	)
+	if record.SignedHandover != nil {
+		signedHandover, err = proto.Marshal(record.SignedHandover)
+		if err != nil {
+			return err
+		}
+	}
+	if len(record.TransferSigs) > 0 {
+		transferSigs, err = json.Marshal(record.TransferSigs)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into casehandovers(orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, buyerRejected, vendorRejected, signedHandover, timestamp, transferSigs, transferTxid) values(?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		record.OrderID,
+		record.Moderator,
+		record.BackupModerator,
+		record.BuyerID,
+		record.VendorID,
+		boolToInt(record.BuyerAccepted),
+		boolToInt(record.VendorAccepted),
+		boolToInt(record.BuyerRejected),
+		boolToInt(record.VendorRejected),
+		signedHandover,
+		record.Timestamp.Unix(),
+		transferSigs,
+		record.TransferTxid,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (c *CaseHandoversDB) Get(orderID string) (*repo.CaseHandoverRecord, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	stmt, err := c.db.Prepare("select orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, buyerRejected, vendorRejected, signedHandover, timestamp, transferSigs, transferTxid from casehandovers where orderID=?")
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	return scanCaseHandover(stmt.QueryRow(orderID))
+}
+
+func (c *CaseHandoversDB) GetAll() ([]*repo.CaseHandoverRecord, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	rows, err := c.db.Query("select orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, buyerRejected, vendorRejected, signedHandover, timestamp, transferSigs, transferTxid from casehandovers order by timestamp desc")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []*repo.CaseHandoverRecord
+	for rows.Next() {
+		record, err := scanCaseHandover(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, record)
+	}
+	return ret, nil
+}
+
+func (c *CaseHandoversDB) Delete(orderID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from casehandovers where orderID=?", orderID)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCaseHandover(row scanner) (*repo.CaseHandoverRecord, error) {
+	var (
+		record                        = new(repo.CaseHandoverRecord)
+		buyerAccepted, vendorAccepted int
+		buyerRejected, vendorRejected int
+		timestamp                     int64
+		signedHandover, transferSigs  []byte
+		transferTxid                  sql.NullString
+	)
+	err := row.Scan(&record.OrderID, &record.Moderator, &record.BackupModerator, &record.BuyerID, &record.VendorID, &buyerAccepted, &vendorAccepted, &buyerRejected, &vendorRejected, &signedHandover, &timestamp, &transferSigs, &transferTxid)
+	if err != nil {
+		return nil, err
+	}
+	if len(signedHandover) > 0 {
+		record.SignedHandover = new(pb.SignedData)
+		if err := proto.Unmarshal(signedHandover, record.SignedHandover); err != nil {
+			return nil, err
+		}
+	}
+	if len(transferSigs) > 0 {
+		if err := json.Unmarshal(transferSigs, &record.TransferSigs); err != nil {
+			return nil, err
+		}
+	}
+	record.TransferTxid = transferTxid.String
+	record.BuyerAccepted = buyerAccepted > 0
+	record.VendorAccepted = vendorAccepted > 0
+	record.BuyerRejected = buyerRejected > 0
+	record.VendorRejected = vendorRejected > 0
+	record.Timestamp = time.Unix(timestamp, 0)
+	return record, nil
+}
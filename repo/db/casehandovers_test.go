@@ -0,0 +1,148 @@
+package db_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewCaseHandoverStore() (repo.CaseHandoverStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewCaseHandoverStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func TestCaseHandoversDB_PutAndGet(t *testing.T) {
+	handoverDB, teardown, err := buildNewCaseHandoverStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	record := &repo.CaseHandoverRecord{
+		OrderID:         "orderID",
+		Moderator:       "moderator",
+		BackupModerator: "backup",
+		BuyerID:         "buyer",
+		VendorID:        "vendor",
+		BuyerAccepted:   true,
+		SignedHandover: &pb.SignedData{
+			SenderPubkey:   []byte("pubkey"),
+			SerializedData: []byte("data"),
+			Signature:      []byte("sig"),
+		},
+		Timestamp:    time.Unix(1234, 0),
+		TransferSigs: []*pb.BitcoinSignature{{InputIndex: 1, Signature: []byte("transfer")}},
+		TransferTxid: "txid",
+	}
+	if err := handoverDB.Put(record); err != nil {
+		t.Fatal(err)
+	}
+
+	retrieved, err := handoverDB.Get("orderID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved.BackupModerator != "backup" || retrieved.Moderator != "moderator" {
+		t.Error("CaseHandoversDB returned incorrect moderators")
+	}
+	if retrieved.BuyerID != "buyer" || retrieved.VendorID != "vendor" {
+		t.Error("CaseHandoversDB returned incorrect parties")
+	}
+	if !retrieved.BuyerAccepted || retrieved.VendorAccepted || retrieved.IsRejected() {
+		t.Error("CaseHandoversDB returned incorrect acceptance state")
+	}
+	if retrieved.IsAccepted() {
+		t.Error("expected handover to not be accepted until the vendor accepts")
+	}
+	if !bytes.Equal(retrieved.SignedHandover.Signature, []byte("sig")) {
+		t.Error("CaseHandoversDB returned incorrect signed handover")
+	}
+	if !retrieved.Timestamp.Equal(record.Timestamp) {
+		t.Error("CaseHandoversDB returned incorrect timestamp")
+	}
+	if len(retrieved.TransferSigs) != 1 || retrieved.TransferSigs[0].InputIndex != 1 || !bytes.Equal(retrieved.TransferSigs[0].Signature, []byte("transfer")) {
+		t.Error("CaseHandoversDB returned incorrect transfer signatures")
+	}
+	if retrieved.TransferTxid != "txid" {
+		t.Error("CaseHandoversDB returned incorrect transfer txid")
+	}
+
+	record.SetResponse("vendor", false)
+	if err := handoverDB.Put(record); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = handoverDB.Get("orderID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !retrieved.VendorRejected || retrieved.BuyerRejected || !retrieved.IsRejected() {
+		t.Error("CaseHandoversDB returned incorrect rejection state")
+	}
+
+	// Accepting withdraws the vendor's rejection
+	retrieved.SetResponse("vendor", true)
+	if err := handoverDB.Put(retrieved); err != nil {
+		t.Fatal(err)
+	}
+	retrieved, err = handoverDB.Get("orderID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrieved.IsRejected() || !retrieved.IsAccepted() {
+		t.Error("expected handover to be accepted by both parties")
+	}
+}
+
+func TestCaseHandoversDB_GetAllAndDelete(t *testing.T) {
+	handoverDB, teardown, err := buildNewCaseHandoverStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for i, id := range []string{"order1", "order2"} {
+		err := handoverDB.Put(&repo.CaseHandoverRecord{
+			OrderID:   id,
+			Timestamp: time.Unix(int64(1000+i), 0),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := handoverDB.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+	if records[0].OrderID != "order2" {
+		t.Error("expected records to be returned newest first")
+	}
+
+	if err := handoverDB.Delete("order1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handoverDB.Get("order1"); err == nil {
+		t.Error("expected deleted record to be missing")
+	}
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"strconv"
 	"sync"
 	"time"
@@ -157,6 +158,16 @@ func (c *ChatDB) GetMessages(peerID string, subject string, offsetID string, lim
 		}
 		ret = append(ret, chatMessage)
 	}
+	rows.Close()
+	for i := range ret {
+		attachments, err := c.getAttachments(ret[i].MessageId)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		ret[i].Attachments = attachments
+	}
+	c.addMessageChanges(ret)
 	return ret
 }
 
@@ -278,12 +289,411 @@ func (c *ChatDB) DeleteMessage(msgID string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	c.db.Exec("delete from chat where messageID=?", msgID)
+	c.db.Exec("delete from chatattachments where messageID=?", msgID)
+	c.db.Exec("delete from chatreactions where messageID=?", msgID)
 	return nil
 }
 
 func (c *ChatDB) DeleteConversation(peerID string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	c.db.Exec("delete from chatattachments where messageID in (select messageID from chat where peerID=? and subject='')", peerID)
+	c.db.Exec("delete from chatreactions where messageID in (select messageID from chat where peerID=? and subject='')", peerID)
 	c.db.Exec("delete from chat where peerID=? and subject=''", peerID)
 	return nil
 }
+
+func (c *ChatDB) PutAttachments(messageID string, attachments []repo.ChatAttachment) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into chatattachments(messageID, idx, filename, mediaType, size, hash, location, data) values(?,?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, a := range attachments {
+		_, err = stmt.Exec(messageID, a.Index, a.Filename, a.MediaType, int64(a.Size), a.Hash, a.Location, a.Data)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *ChatDB) GetAttachment(messageID string, index int) (*repo.ChatAttachment, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	a := repo.ChatAttachment{Index: index}
+	var size int64
+	err := c.db.QueryRow("select filename, mediaType, size, hash, location, data from chatattachments where messageID=? and idx=?", messageID, index).
+		Scan(&a.Filename, &a.MediaType, &size, &a.Hash, &a.Location, &a.Data)
+	if err != nil {
+		return nil, err
+	}
+	a.Size = uint64(size)
+	return &a, nil
+}
+
+func (c *ChatDB) PutAttachmentData(messageID string, index int, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("update chatattachments set data=? where messageID=? and idx=?", data, messageID, index)
+	return err
+}
+
+func (c *ChatDB) getAttachments(messageID string) ([]repo.ChatAttachment, error) {
+	rows, err := c.db.Query("select idx, filename, mediaType, size, hash, location from chatattachments where messageID=? order by idx asc", messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.ChatAttachment
+	for rows.Next() {
+		var (
+			a    repo.ChatAttachment
+			size int64
+		)
+		if err := rows.Scan(&a.Index, &a.Filename, &a.MediaType, &size, &a.Hash, &a.Location); err != nil {
+			return nil, err
+		}
+		a.Size = uint64(size)
+		ret = append(ret, a)
+	}
+	return ret, nil
+}
+
+func (c *ChatDB) PutGroup(group repo.ChatGroup) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	members, err := json.Marshal(group.Members)
+	if err != nil {
+		return err
+	}
+	activeInt := 0
+	if group.Active {
+		activeInt = 1
+	}
+	_, err = c.db.Exec("insert or replace into chatgroups(groupID, name, creator, members, version, membership, active, timestamp) values(?,?,?,?,?,?,?,?)",
+		group.GroupId, group.Name, group.Creator, string(members), int64(group.Version), group.Membership, activeInt, group.Timestamp.Unix())
+	return err
+}
+
+func (c *ChatDB) GetGroup(groupID string) (*repo.ChatGroup, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.getGroup(groupID)
+}
+
+func (c *ChatDB) getGroup(groupID string) (*repo.ChatGroup, error) {
+	var (
+		group     = repo.ChatGroup{GroupId: groupID}
+		members   string
+		version   int64
+		activeInt int
+		ts        int64
+	)
+	err := c.db.QueryRow("select name, creator, members, version, membership, active, timestamp from chatgroups where groupID=?", groupID).
+		Scan(&group.Name, &group.Creator, &members, &version, &group.Membership, &activeInt, &ts)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(members), &group.Members); err != nil {
+		return nil, err
+	}
+	group.Version = uint32(version)
+	group.Active = activeInt == 1
+	group.Timestamp = time.Unix(ts, 0)
+	return &group, nil
+}
+
+func (c *ChatDB) GetGroupConversations() []repo.ChatGroupConversation {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var ret []repo.ChatGroupConversation
+
+	rows, err := c.db.Query("select groupID from chatgroups order by timestamp desc;")
+	if err != nil {
+		log.Error(err)
+		return ret
+	}
+	var ids []string
+	for rows.Next() {
+		var groupID string
+		if err := rows.Scan(&groupID); err != nil {
+			continue
+		}
+		ids = append(ids, groupID)
+	}
+	rows.Close()
+	for _, groupID := range ids {
+		group, err := c.getGroup(groupID)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		convo := repo.ChatGroupConversation{
+			GroupId:   group.GroupId,
+			Name:      group.Name,
+			Members:   group.Members,
+			Active:    group.Active,
+			Timestamp: group.Timestamp,
+		}
+		c.db.QueryRow("select Count(*) from chatgroupmessages where groupID=? and read=0 and outgoing=0;", groupID).Scan(&convo.Unread)
+
+		var (
+			m      sql.NullString
+			pid    sql.NullString
+			ts     sql.NullInt64
+			outInt sql.NullInt64
+		)
+		err = c.db.QueryRow("select message, peerID, timestamp, outgoing from chatgroupmessages where groupID=? order by timestamp desc limit 1;", groupID).Scan(&m, &pid, &ts, &outInt)
+		if err == nil {
+			convo.Last = m.String
+			convo.LastPeer = pid.String
+			convo.Timestamp = time.Unix(ts.Int64, 0)
+			convo.Outgoing = outInt.Int64 > 0
+		}
+		ret = append(ret, convo)
+	}
+	return ret
+}
+
+func (c *ChatDB) PutGroupMessage(messageID string, groupID string, peerID string, message string, timestamp time.Time, read bool, outgoing bool) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	readInt := 0
+	if read {
+		readInt = 1
+	}
+	outgoingInt := 0
+	if outgoing {
+		outgoingInt = 1
+	}
+	_, err := c.db.Exec("insert into chatgroupmessages(messageID, groupID, peerID, message, read, timestamp, outgoing) values(?,?,?,?,?,?,?)",
+		messageID, groupID, peerID, message, readInt, int(timestamp.Unix()), outgoingInt)
+	return err
+}
+
+func (c *ChatDB) GetGroupMessages(groupID string, offsetID string, limit int) []repo.ChatMessage {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var ret []repo.ChatMessage
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if offsetID != "" {
+		rows, err = c.db.Query("select messageID, peerID, message, read, timestamp, outgoing from chatgroupmessages where groupID=? and timestamp<(select timestamp from chatgroupmessages where messageID=?) order by timestamp desc limit ?;", groupID, offsetID, limit)
+	} else {
+		rows, err = c.db.Query("select messageID, peerID, message, read, timestamp, outgoing from chatgroupmessages where groupID=? order by timestamp desc limit ?;", groupID, limit)
+	}
+	if err != nil {
+		log.Error(err)
+		return ret
+	}
+	for rows.Next() {
+		var (
+			msgID        string
+			pid          string
+			message      string
+			readInt      int
+			timestampInt int
+			outgoingInt  int
+		)
+		if err := rows.Scan(&msgID, &pid, &message, &readInt, &timestampInt, &outgoingInt); err != nil {
+			continue
+		}
+		ret = append(ret, repo.ChatMessage{
+			MessageId: msgID,
+			PeerId:    pid,
+			GroupId:   groupID,
+			Message:   message,
+			Read:      readInt == 1,
+			Timestamp: time.Unix(int64(timestampInt), 0),
+			Outgoing:  outgoingInt == 1,
+		})
+	}
+	rows.Close()
+	c.addMessageChanges(ret)
+	return ret
+}
+
+func (c *ChatDB) MarkGroupAsRead(groupID string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res, err := c.db.Exec("update chatgroupmessages set read=1 where groupID=? and outgoing=0 and read=0", groupID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (c *ChatDB) GetGroupUnreadCount(groupID string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var count int
+	err := c.db.QueryRow("select Count(*) from chatgroupmessages where groupID=? and read=0 and outgoing=0;", groupID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (c *ChatDB) DeleteGroup(groupID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range []string{
+		"delete from chatreactions where messageID in (select messageID from chatgroupmessages where groupID=?)",
+		"delete from chatgroupmessages where groupID=?",
+		"delete from chatgroups where groupID=?",
+	} {
+		if _, err := tx.Exec(stmt, groupID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *ChatDB) GetMessage(messageID string) (*repo.ChatMessage, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var (
+		msg          = repo.ChatMessage{MessageId: messageID}
+		readInt      int
+		timestampInt int
+		outgoingInt  int
+	)
+	err := c.db.QueryRow("select peerID, subject, message, read, timestamp, outgoing from chat where messageID=?", messageID).
+		Scan(&msg.PeerId, &msg.Subject, &msg.Message, &readInt, &timestampInt, &outgoingInt)
+	if err == sql.ErrNoRows {
+		err = c.db.QueryRow("select peerID, groupID, message, read, timestamp, outgoing from chatgroupmessages where messageID=?", messageID).
+			Scan(&msg.PeerId, &msg.GroupId, &msg.Message, &readInt, &timestampInt, &outgoingInt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	msg.Read = readInt == 1
+	msg.Timestamp = time.Unix(int64(timestampInt), 0)
+	msg.Outgoing = outgoingInt == 1
+	return &msg, nil
+}
+
+func (c *ChatDB) PutEdit(edit repo.ChatEdit) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("insert into chatedits(messageID, peerID, action, previous, message, signedChat, timestamp) values(?,?,?,?,?,?,?)",
+		edit.MessageId, edit.PeerId, edit.Action, edit.Previous, edit.Message, edit.SignedChat, int(edit.Timestamp.Unix()))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	var stmts []string
+	if edit.Action == repo.ChatEditActionRetract {
+		stmts = []string{
+			"delete from chat where messageID=?",
+			"delete from chatgroupmessages where messageID=?",
+			"delete from chatattachments where messageID=?",
+			"delete from chatreactions where messageID=?",
+		}
+	} else {
+		stmts = []string{
+			"update chat set message=? where messageID=?",
+			"update chatgroupmessages set message=? where messageID=?",
+		}
+	}
+	for _, stmt := range stmts {
+		if edit.Action == repo.ChatEditActionRetract {
+			_, err = tx.Exec(stmt, edit.MessageId)
+		} else {
+			_, err = tx.Exec(stmt, edit.Message, edit.MessageId)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (c *ChatDB) GetEdits(messageID string) ([]repo.ChatEdit, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	rows, err := c.db.Query("select peerID, action, previous, message, signedChat, timestamp from chatedits where messageID=? order by timestamp asc, rowid asc", messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.ChatEdit
+	for rows.Next() {
+		var (
+			edit = repo.ChatEdit{MessageId: messageID}
+			ts   int64
+		)
+		if err := rows.Scan(&edit.PeerId, &edit.Action, &edit.Previous, &edit.Message, &edit.SignedChat, &ts); err != nil {
+			return nil, err
+		}
+		edit.Timestamp = time.Unix(ts, 0)
+		ret = append(ret, edit)
+	}
+	return ret, nil
+}
+
+func (c *ChatDB) PutReaction(messageID string, peerID string, reaction string, timestamp time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("insert or replace into chatreactions(messageID, peerID, reaction, timestamp) values(?,?,?,?)", messageID, peerID, reaction, int(timestamp.Unix()))
+	return err
+}
+
+func (c *ChatDB) DeleteReaction(messageID string, peerID string, reaction string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from chatreactions where messageID=? and peerID=? and reaction=?", messageID, peerID, reaction)
+	return err
+}
+
+// addMessageChanges fills in the reactions to each message and whether it
+// has been edited
+func (c *ChatDB) addMessageChanges(messages []repo.ChatMessage) {
+	for i := range messages {
+		rows, err := c.db.Query("select peerID, reaction from chatreactions where messageID=? order by timestamp asc", messages[i].MessageId)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		for rows.Next() {
+			var r repo.ChatReaction
+			if err := rows.Scan(&r.PeerId, &r.Reaction); err != nil {
+				continue
+			}
+			messages[i].Reactions = append(messages[i].Reactions, r)
+		}
+		rows.Close()
+
+		var edits int
+		c.db.QueryRow("select count(*) from chatedits where messageID=?", messages[i].MessageId).Scan(&edits)
+		messages[i].Edited = edits > 0
+	}
+}
@@ -456,3 +456,225 @@ func TestChatDB_DeleteConversation(t *testing.T) {
 	}
 	stmt.Close()
 }
+
+func TestChatDB_Attachments(t *testing.T) {
+	var chdb, teardown, err = buildNewChatStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	err = chdb.Put("11111", "abc", "", "", time.Now(), false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachments := []repo.ChatAttachment{
+		{Index: 0, Filename: "receipt.png", MediaType: "image/png", Size: 3, Hash: "QmHash1", Location: "/ipfs/QmLocation1/"},
+		{Index: 1, Filename: "notes.txt", MediaType: "text/plain", Size: 5, Hash: "QmHash2", Location: "/ipfs/QmLocation2/"},
+	}
+	if err := chdb.PutAttachments("11111", attachments); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := chdb.GetMessages("abc", "", "", -1)
+	if len(messages) != 1 {
+		t.Fatal("Returned incorrect number of messages")
+	}
+	if len(messages[0].Attachments) != 2 {
+		t.Fatalf("Expected 2 attachments, got %d", len(messages[0].Attachments))
+	}
+	if messages[0].Attachments[1].Filename != "notes.txt" || messages[0].Attachments[1].Location != "/ipfs/QmLocation2/" {
+		t.Error("Returned incorrect attachment")
+	}
+
+	a, err := chdb.GetAttachment("11111", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Hash != "QmHash1" || a.Size != 3 || len(a.Data) != 0 {
+		t.Error("Returned incorrect attachment")
+	}
+	if err := chdb.PutAttachmentData("11111", 0, []byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	a, err = chdb.GetAttachment("11111", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a.Data) != "abc" {
+		t.Error("Attachment data was not saved")
+	}
+
+	if err := chdb.DeleteConversation("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chdb.GetAttachment("11111", 0); err == nil {
+		t.Error("Expected attachments to be deleted with the conversation")
+	}
+}
+
+func TestChatDB_Groups(t *testing.T) {
+	var chdb, teardown, err = buildNewChatStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	group := repo.ChatGroup{
+		GroupId:    "group1",
+		Name:       "Group",
+		Creator:    "abc",
+		Members:    []string{"abc", "def"},
+		Version:    1,
+		Active:     true,
+		Timestamp:  time.Now(),
+		Membership: []byte("membership"),
+	}
+	if err := chdb.PutGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	group.Members = append(group.Members, "ghi")
+	group.Version = 2
+	if err := chdb.PutGroup(group); err != nil {
+		t.Fatal(err)
+	}
+	g, err := chdb.GetGroup("group1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Version != 2 || len(g.Members) != 3 || !g.HasMember("ghi") || !g.Active || string(g.Membership) != "membership" {
+		t.Errorf("Returned incorrect group: %+v", g)
+	}
+	if _, err := chdb.GetGroup("group2"); err == nil {
+		t.Error("Expected an error for an unknown group")
+	}
+
+	now := time.Now()
+	if err := chdb.PutGroupMessage("m1", "group1", "def", "hello", now.Add(-time.Second), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.PutGroupMessage("m2", "group1", "ghi", "hi", now, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.Put("m3", "def", "", "direct", now, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := chdb.GetGroupMessages("group1", "", -1)
+	if len(messages) != 2 || messages[0].MessageId != "m2" || messages[0].GroupId != "group1" {
+		t.Errorf("Returned incorrect group messages: %+v", messages)
+	}
+	messages = chdb.GetGroupMessages("group1", "m2", -1)
+	if len(messages) != 1 || messages[0].MessageId != "m1" {
+		t.Errorf("Returned incorrect group messages after offset: %+v", messages)
+	}
+	if len(chdb.GetMessages("def", "", "", -1)) != 1 {
+		t.Error("Expected group messages to be kept out of direct conversations")
+	}
+
+	convos := chdb.GetGroupConversations()
+	if len(convos) != 1 || convos[0].Unread != 2 || convos[0].Last != "hi" || convos[0].LastPeer != "ghi" {
+		t.Errorf("Returned incorrect group conversations: %+v", convos)
+	}
+	updated, err := chdb.MarkGroupAsRead("group1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated {
+		t.Error("Expected messages to be marked as read")
+	}
+	count, err := chdb.GetGroupUnreadCount("group1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected no unread messages, got %d", count)
+	}
+	if updated, _ := chdb.MarkGroupAsRead("group1"); updated {
+		t.Error("Expected nothing to be updated")
+	}
+
+	if err := chdb.DeleteGroup("group1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chdb.GetGroup("group1"); err == nil {
+		t.Error("Expected the group to be deleted")
+	}
+	if len(chdb.GetGroupMessages("group1", "", -1)) != 0 {
+		t.Error("Expected the group messages to be deleted")
+	}
+}
+
+func TestChatDB_EditsAndReactions(t *testing.T) {
+	var chdb, teardown, err = buildNewChatStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	now := time.Now()
+	if err := chdb.Put("m1", "abc", "", "helo", now, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.PutGroupMessage("m2", "group1", "abc", "hi", now, false, false); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := chdb.GetMessage("m2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.GroupId != "group1" || msg.PeerId != "abc" || msg.Message != "hi" {
+		t.Errorf("Returned incorrect message: %+v", msg)
+	}
+	if _, err := chdb.GetMessage("m3"); err == nil {
+		t.Error("Expected an error for an unknown message")
+	}
+
+	err = chdb.PutEdit(repo.ChatEdit{MessageId: "m1", PeerId: "abc", Action: repo.ChatEditActionEdit, Previous: "helo", Message: "hello", SignedChat: []byte("signed"), Timestamp: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.PutReaction("m1", "def", "+1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.PutReaction("m1", "def", "+1", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := chdb.PutReaction("m1", "abc", "heart", now); err != nil {
+		t.Fatal(err)
+	}
+	messages := chdb.GetMessages("abc", "", "", -1)
+	if len(messages) != 1 || messages[0].Message != "hello" || !messages[0].Edited || len(messages[0].Reactions) != 2 {
+		t.Errorf("Returned incorrect messages: %+v", messages)
+	}
+	if err := chdb.DeleteReaction("m1", "def", "+1"); err != nil {
+		t.Fatal(err)
+	}
+	messages = chdb.GetMessages("abc", "", "", -1)
+	if len(messages[0].Reactions) != 1 || messages[0].Reactions[0].Reaction != "heart" {
+		t.Errorf("Returned incorrect reactions: %+v", messages[0].Reactions)
+	}
+
+	err = chdb.PutEdit(repo.ChatEdit{MessageId: "m2", PeerId: "abc", Action: repo.ChatEditActionRetract, Previous: "hi", Timestamp: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chdb.GetGroupMessages("group1", "", -1)) != 0 {
+		t.Error("Expected the retracted message to be deleted")
+	}
+	edits, err := chdb.GetEdits("m2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 || edits[0].Previous != "hi" || edits[0].Action != repo.ChatEditActionRetract {
+		t.Errorf("Expected the retraction to be kept in the history: %+v", edits)
+	}
+	edits, err = chdb.GetEdits("m1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 || string(edits[0].SignedChat) != "signed" {
+		t.Errorf("Returned incorrect edit history: %+v", edits)
+	}
+}
@@ -37,6 +37,16 @@ type SQLiteDatastore struct {
 	coupons         repo.CouponStore
 	txMetadata      repo.TransactionMetadataStore
 	moderatedStores repo.ModeratedStore
+	caseHandovers   repo.CaseHandoverStore
+	panelVotes      repo.PanelVoteStore
+	ratchetSessions repo.RatchetSessionStore
+	webhooks        repo.WebhookDeliveryStore
+	postComments    repo.PostCommentStore
+	feed            repo.FeedStore
+	signingRequests repo.SigningRequestStore
+	txLabels        repo.TransactionLabelStore
+	txFiatValues    repo.TransactionFiatValueStore
+	exchangeRates   repo.ExchangeRateStore
 	db              *sql.DB
 	lock            *sync.Mutex
 }
@@ -82,6 +92,16 @@ func NewSQLiteDatastore(db *sql.DB, l *sync.Mutex, coinType util.ExtCoinType) *S
 		coupons:         NewCouponStore(db, l),
 		txMetadata:      NewTransactionMetadataStore(db, l),
 		moderatedStores: NewModeratedStore(db, l),
+		caseHandovers:   NewCaseHandoverStore(db, l),
+		panelVotes:      NewPanelVoteStore(db, l),
+		ratchetSessions: NewRatchetSessionStore(db, l),
+		webhooks:        NewWebhookDeliveryStore(db, l),
+		postComments:    NewPostCommentStore(db, l),
+		feed:            NewFeedStore(db, l),
+		signingRequests: NewSigningRequestStore(db, l),
+		txLabels:        NewTransactionLabelStore(db, l),
+		txFiatValues:    NewTransactionFiatValueStore(db, l),
+		exchangeRates:   NewExchangeRateStore(db, l),
 		db:              db,
 		lock:            l,
 	}
@@ -184,6 +204,46 @@ func (d *SQLiteDatastore) ModeratedStores() repo.ModeratedStore {
 	return d.moderatedStores
 }
 
+func (d *SQLiteDatastore) CaseHandovers() repo.CaseHandoverStore {
+	return d.caseHandovers
+}
+
+func (d *SQLiteDatastore) PanelVotes() repo.PanelVoteStore {
+	return d.panelVotes
+}
+
+func (d *SQLiteDatastore) RatchetSessions() repo.RatchetSessionStore {
+	return d.ratchetSessions
+}
+
+func (d *SQLiteDatastore) WebhookDeliveries() repo.WebhookDeliveryStore {
+	return d.webhooks
+}
+
+func (d *SQLiteDatastore) PostComments() repo.PostCommentStore {
+	return d.postComments
+}
+
+func (d *SQLiteDatastore) Feed() repo.FeedStore {
+	return d.feed
+}
+
+func (d *SQLiteDatastore) SigningRequests() repo.SigningRequestStore {
+	return d.signingRequests
+}
+
+func (d *SQLiteDatastore) TxLabels() repo.TransactionLabelStore {
+	return d.txLabels
+}
+
+func (d *SQLiteDatastore) TxFiatValues() repo.TransactionFiatValueStore {
+	return d.txFiatValues
+}
+
+func (d *SQLiteDatastore) ExchangeRates() repo.ExchangeRateStore {
+	return d.exchangeRates
+}
+
 func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	d.lock.Lock()
 	defer d.lock.Unlock()
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type ExchangeRatesDB struct {
+	modelStore
+}
+
+func NewExchangeRateStore(db *sql.DB, lock *sync.Mutex) repo.ExchangeRateStore {
+	return &ExchangeRatesDB{modelStore{db, lock}}
+}
+
+func (e *ExchangeRatesDB) Put(rates ...repo.ExchangeRate) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into exchangerates(coin, currency, rate, timestamp) values(?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, rate := range rates {
+		if _, err := stmt.Exec(rate.Coin, rate.Currency, rate.Rate, rate.Timestamp.Unix()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAt returns the nearest sample to the time, before or after it. It
+// returns sql.ErrNoRows when the rate was never sampled.
+func (e *ExchangeRatesDB) GetAt(coin, currency string, t time.Time) (*repo.ExchangeRate, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	before, err := scanExchangeRate(e.db.QueryRow("select coin, currency, rate, timestamp from exchangerates where coin=? and currency=? and timestamp<=? order by timestamp desc limit 1", coin, currency, t.Unix()))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	after, err := scanExchangeRate(e.db.QueryRow("select coin, currency, rate, timestamp from exchangerates where coin=? and currency=? and timestamp>? order by timestamp asc limit 1", coin, currency, t.Unix()))
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	switch {
+	case before == nil && after == nil:
+		return nil, sql.ErrNoRows
+	case after == nil:
+		return before, nil
+	case before == nil:
+		return after, nil
+	case t.Sub(before.Timestamp) <= after.Timestamp.Sub(t):
+		return before, nil
+	default:
+		return after, nil
+	}
+}
+
+func (e *ExchangeRatesDB) GetRange(coin, currency string, from, to time.Time) ([]repo.ExchangeRate, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	rows, err := e.db.Query("select coin, currency, rate, timestamp from exchangerates where coin=? and currency=? and timestamp>=? and timestamp<=? order by timestamp asc", coin, currency, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *rate)
+	}
+	return ret, rows.Err()
+}
+
+func (e *ExchangeRatesDB) PutOrderRates(orderID string, rates []repo.ExchangeRate) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into orderexchangerates(orderID, coin, currency, rate, timestamp) values(?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, rate := range rates {
+		if _, err := stmt.Exec(orderID, rate.Coin, rate.Currency, rate.Rate, rate.Timestamp.Unix()); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (e *ExchangeRatesDB) GetOrderRates(orderID string) ([]repo.ExchangeRate, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	rows, err := e.db.Query("select coin, currency, rate, timestamp from orderexchangerates where orderID=? order by coin, currency", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *rate)
+	}
+	return ret, rows.Err()
+}
+
+func scanExchangeRate(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.ExchangeRate, error) {
+	var (
+		rate      repo.ExchangeRate
+		timestamp int64
+	)
+	if err := row.Scan(&rate.Coin, &rate.Currency, &rate.Rate, &timestamp); err != nil {
+		return nil, err
+	}
+	rate.Timestamp = time.Unix(timestamp, 0)
+	return &rate, nil
+}
@@ -0,0 +1,107 @@
+package db_test
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewExchangeRateStore() (repo.ExchangeRateStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewExchangeRateStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func TestExchangeRatesDBGetAt(t *testing.T) {
+	store, teardown, err := buildNewExchangeRateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	if _, err := store.GetAt("BTC", "USD", time.Unix(1000, 0)); err != sql.ErrNoRows {
+		t.Errorf("expected no rate before any sample, got %v", err)
+	}
+	err = store.Put(
+		repo.ExchangeRate{Coin: "BTC", Currency: "USD", Rate: 6000, Timestamp: time.Unix(1000, 0)},
+		repo.ExchangeRate{Coin: "BTC", Currency: "USD", Rate: 6500, Timestamp: time.Unix(2000, 0)},
+		repo.ExchangeRate{Coin: "BTC", Currency: "EUR", Rate: 5000, Timestamp: time.Unix(1500, 0)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		at   int64
+		rate float64
+	}{
+		{500, 6000},
+		{1000, 6000},
+		{1400, 6000},
+		{1600, 6500},
+		{5000, 6500},
+	} {
+		rate, err := store.GetAt("BTC", "USD", time.Unix(c.at, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rate.Rate != c.rate {
+			t.Errorf("expected the rate at %d to be %f, got %f", c.at, c.rate, rate.Rate)
+		}
+	}
+
+	rates, err := store.GetRange("BTC", "USD", time.Unix(0, 0), time.Unix(1999, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 1 || rates[0].Rate != 6000 || rates[0].Timestamp.Unix() != 1000 {
+		t.Errorf("unexpected range %v", rates)
+	}
+}
+
+func TestExchangeRatesDBOrderRates(t *testing.T) {
+	store, teardown, err := buildNewExchangeRateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	used := []repo.ExchangeRate{
+		{Coin: "BTC", Currency: "USD", Rate: 6000, Timestamp: time.Unix(1000, 0)},
+		{Coin: "BTC", Currency: "PHR", Rate: 80000, Timestamp: time.Unix(1000, 0)},
+	}
+	if err := store.PutOrderRates("order1", used); err != nil {
+		t.Fatal(err)
+	}
+	rates, err := store.GetOrderRates("order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[0].Currency != "PHR" || rates[1].Rate != 6000 {
+		t.Errorf("unexpected order rates %v", rates)
+	}
+	rates, err = store.GetOrderRates("order2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 0 {
+		t.Errorf("expected no rates for another order, got %v", rates)
+	}
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type FeedDB struct {
+	modelStore
+}
+
+func NewFeedStore(db *sql.DB, lock *sync.Mutex) repo.FeedStore {
+	return &FeedDB{modelStore{db, lock}}
+}
+
+func (f *FeedDB) Put(entry repo.FeedEntry) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	tx, err := f.db.Begin()
+	if err != nil {
+		return err
+	}
+	// An update keeps the rowid, which orders entries with the same
+	// timestamp, where insert or replace would move the entry to the top
+	res, err := tx.Exec("update feed set peerID=?, entryType=?, slug=?, hash=?, data=?, timestamp=? where entryID=?",
+		entry.PeerID, entry.Type, entry.Slug, entry.Hash, []byte(entry.Data), entry.Timestamp.Unix(), entry.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if updated, err := res.RowsAffected(); err != nil || updated == 0 {
+		_, err = tx.Exec("insert into feed(entryID, peerID, entryType, slug, hash, data, timestamp) values(?,?,?,?,?,?,?)",
+			entry.ID, entry.PeerID, entry.Type, entry.Slug, entry.Hash, []byte(entry.Data), entry.Timestamp.Unix())
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (f *FeedDB) Get(entryID string) (*repo.FeedEntry, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	row := f.db.QueryRow("select entryID, peerID, entryType, slug, hash, data, timestamp from feed where entryID=?", entryID)
+	return scanFeedEntry(row)
+}
+
+func (f *FeedDB) GetAll(entryType, offsetID string, limit int, excludePeers []string) ([]repo.FeedEntry, error) {
+	var (
+		stm     = "select entryID, peerID, entryType, slug, hash, data, timestamp from feed"
+		clauses []string
+		args    []interface{}
+	)
+	if entryType != "" {
+		clauses = append(clauses, "entryType=?")
+		args = append(args, entryType)
+	}
+	if len(excludePeers) > 0 {
+		clauses = append(clauses, "peerID not in (?"+strings.Repeat(",?", len(excludePeers)-1)+")")
+		for _, peerID := range excludePeers {
+			args = append(args, peerID)
+		}
+	}
+	if offsetID != "" {
+		clauses = append(clauses, "(timestamp<(select timestamp from feed where entryID=?) or (timestamp=(select timestamp from feed where entryID=?) and rowid<(select rowid from feed where entryID=?)))")
+		args = append(args, offsetID, offsetID, offsetID)
+	}
+	for i, clause := range clauses {
+		if i == 0 {
+			stm += " where " + clause
+		} else {
+			stm += " and " + clause
+		}
+	}
+	stm += " order by timestamp desc, rowid desc"
+	if limit >= 0 {
+		stm += " limit " + strconv.Itoa(limit)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rows, err := f.db.Query(stm+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanFeedEntries(rows)
+}
+
+func (f *FeedDB) GetByPeer(peerID string) ([]repo.FeedEntry, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	rows, err := f.db.Query("select entryID, peerID, entryType, slug, hash, data, timestamp from feed where peerID=?", peerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanFeedEntries(rows)
+}
+
+func (f *FeedDB) Delete(entryID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, err := f.db.Exec("delete from feed where entryID=?", entryID)
+	return err
+}
+
+func (f *FeedDB) DeleteByPeer(peerID string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, err := f.db.Exec("delete from feed where peerID=?", peerID)
+	return err
+}
+
+func scanFeedEntries(rows *sql.Rows) ([]repo.FeedEntry, error) {
+	var ret []repo.FeedEntry
+	for rows.Next() {
+		entry, err := scanFeedEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *entry)
+	}
+	return ret, nil
+}
+
+func scanFeedEntry(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.FeedEntry, error) {
+	var (
+		entry     repo.FeedEntry
+		data      []byte
+		timestamp int64
+	)
+	if err := row.Scan(&entry.ID, &entry.PeerID, &entry.Type, &entry.Slug, &entry.Hash, &data, &timestamp); err != nil {
+		return nil, err
+	}
+	entry.Data = data
+	entry.Timestamp = time.Unix(timestamp, 0)
+	return &entry, nil
+}
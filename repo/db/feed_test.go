@@ -0,0 +1,178 @@
+package db_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewFeedStore() (repo.FeedStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewFeedStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func newFeedEntry(peerID, entryType, slug string, timestamp int64) repo.FeedEntry {
+	return repo.FeedEntry{
+		ID:        peerID + "/" + entryType + "/" + slug,
+		PeerID:    peerID,
+		Type:      entryType,
+		Slug:      slug,
+		Hash:      "hash-" + slug,
+		Data:      []byte(`{"slug":"` + slug + `"}`),
+		Timestamp: time.Unix(timestamp, 0),
+	}
+}
+
+func TestFeedDB_PutGet(t *testing.T) {
+	feedStore, teardown, err := buildNewFeedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	entry := newFeedEntry("peer1", repo.FeedEntryTypePost, "hello", 100)
+	if err := feedStore.Put(entry); err != nil {
+		t.Fatal(err)
+	}
+	entry.Hash = "updated"
+	if err := feedStore.Put(entry); err != nil {
+		t.Fatal(err)
+	}
+	got, err := feedStore.Get(entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PeerID != "peer1" || got.Type != repo.FeedEntryTypePost || got.Slug != "hello" ||
+		got.Hash != "updated" || string(got.Data) != string(entry.Data) || got.Timestamp.Unix() != 100 {
+		t.Errorf("unexpected entry: %+v", got)
+	}
+	if _, err := feedStore.Get("missing"); err == nil {
+		t.Error("expected error for missing entry")
+	}
+}
+
+func TestFeedDB_GetAll(t *testing.T) {
+	feedStore, teardown, err := buildNewFeedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, entry := range []repo.FeedEntry{
+		newFeedEntry("peer1", repo.FeedEntryTypePost, "a", 100),
+		newFeedEntry("peer2", repo.FeedEntryTypeListing, "b", 300),
+		newFeedEntry("peer1", repo.FeedEntryTypeListing, "c", 200),
+		newFeedEntry("peer2", repo.FeedEntryTypePost, "d", 200),
+	} {
+		if err := feedStore.Put(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := feedStore.GetAll("", "", -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"b", "d", "c", "a"}
+	if len(all) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(all))
+	}
+	for i, slug := range expected {
+		if all[i].Slug != slug {
+			t.Errorf("entry %d: expected %s, got %s", i, slug, all[i].Slug)
+		}
+	}
+
+	page, err := feedStore.GetAll("", all[1].ID, 2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Slug != "c" || page[1].Slug != "a" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+
+	listings, err := feedStore.GetAll(repo.FeedEntryTypeListing, "", -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listings) != 2 || listings[0].Slug != "b" || listings[1].Slug != "c" {
+		t.Errorf("unexpected listings: %+v", listings)
+	}
+
+	// Excluded peers don't count towards the limit
+	page, err = feedStore.GetAll("", "", 2, []string{"peer2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Slug != "c" || page[1].Slug != "a" {
+		t.Errorf("unexpected page without excluded peers: %+v", page)
+	}
+
+	// Updating an entry keeps its place among entries with the same timestamp
+	updated := newFeedEntry("peer2", repo.FeedEntryTypePost, "d", 200)
+	updated.Hash = "updated"
+	if err := feedStore.Put(updated); err != nil {
+		t.Fatal(err)
+	}
+	if all, err = feedStore.GetAll("", "", -1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[1].Slug != "d" || all[1].Hash != "updated" || all[2].Slug != "c" {
+		t.Errorf("expected the updated entry to keep its place, got %+v", all)
+	}
+}
+
+func TestFeedDB_DeleteByPeer(t *testing.T) {
+	feedStore, teardown, err := buildNewFeedStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, entry := range []repo.FeedEntry{
+		newFeedEntry("peer1", repo.FeedEntryTypePost, "a", 100),
+		newFeedEntry("peer1", repo.FeedEntryTypeListing, "b", 100),
+		newFeedEntry("peer2", repo.FeedEntryTypePost, "c", 100),
+	} {
+		if err := feedStore.Put(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := feedStore.Delete("peer1/listing/b"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := feedStore.GetByPeer("peer1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Slug != "a" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+	if err := feedStore.DeleteByPeer("peer1"); err != nil {
+		t.Fatal(err)
+	}
+	all, err := feedStore.GetAll("", "", -1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].PeerID != "peer2" {
+		t.Errorf("expected only peer2 entries to remain, got %+v", all)
+	}
+}
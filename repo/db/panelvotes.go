@@ -0,0 +1,76 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type PanelVotesDB struct {
+	modelStore
+}
+
+func NewPanelVoteStore(db *sql.DB, lock *sync.Mutex) repo.PanelVoteStore {
+	return &PanelVotesDB{modelStore{db, lock}}
+}
+
+func (p *PanelVotesDB) Put(orderID, moderatorID string, vote *pb.RicardianContract, timestamp time.Time) error {
+	ser, err := proto.Marshal(vote)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into panelvotes(orderID, moderatorID, vote, timestamp) values(?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(orderID, moderatorID, ser, timestamp.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *PanelVotesDB) GetByOrderID(orderID string) ([]*pb.RicardianContract, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	rows, err := p.db.Query("select vote from panelvotes where orderID=? order by timestamp asc", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []*pb.RicardianContract
+	for rows.Next() {
+		var ser []byte
+		if err := rows.Scan(&ser); err != nil {
+			return nil, err
+		}
+		vote := new(pb.RicardianContract)
+		if err := proto.Unmarshal(ser, vote); err != nil {
+			return nil, err
+		}
+		ret = append(ret, vote)
+	}
+	return ret, nil
+}
+
+func (p *PanelVotesDB) DeleteByOrderID(orderID string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := p.db.Exec("delete from panelvotes where orderID=?", orderID)
+	return err
+}
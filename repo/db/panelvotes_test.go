@@ -0,0 +1,116 @@
+package db_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewPanelVoteStore() (repo.PanelVoteStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewPanelVoteStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func newPanelVote(orderID, moderatorID, resolution string) *pb.RicardianContract {
+	return &pb.RicardianContract{
+		DisputeResolution: &pb.DisputeResolution{
+			OrderId:    orderID,
+			ProposedBy: moderatorID,
+			Resolution: resolution,
+		},
+	}
+}
+
+func TestPanelVotesDB_PutAndGet(t *testing.T) {
+	voteDB, teardown, err := buildNewPanelVoteStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	if err := voteDB.Put("order1", "mod2", newPanelVote("order1", "mod2", "second"), time.Unix(2000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := voteDB.Put("order1", "mod1", newPanelVote("order1", "mod1", "first"), time.Unix(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := voteDB.Put("order2", "mod1", newPanelVote("order2", "mod1", "other"), time.Unix(1500, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	votes, err := voteDB.GetByOrderID("order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 2 {
+		t.Fatalf("expected 2 votes, got %d", len(votes))
+	}
+	if votes[0].DisputeResolution.ProposedBy != "mod1" || votes[1].DisputeResolution.ProposedBy != "mod2" {
+		t.Error("expected votes to be returned oldest first")
+	}
+
+	// A member's new vote replaces its earlier one
+	if err := voteDB.Put("order1", "mod1", newPanelVote("order1", "mod1", "changed"), time.Unix(3000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	votes, err = voteDB.GetByOrderID("order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 2 {
+		t.Fatalf("expected 2 votes, got %d", len(votes))
+	}
+	if votes[1].DisputeResolution.Resolution != "changed" {
+		t.Error("expected the replaced vote to be returned")
+	}
+}
+
+func TestPanelVotesDB_DeleteByOrderID(t *testing.T) {
+	voteDB, teardown, err := buildNewPanelVoteStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	if err := voteDB.Put("order1", "mod1", newPanelVote("order1", "mod1", "first"), time.Unix(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := voteDB.Put("order2", "mod1", newPanelVote("order2", "mod1", "other"), time.Unix(1000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := voteDB.DeleteByOrderID("order1"); err != nil {
+		t.Fatal(err)
+	}
+	votes, err := voteDB.GetByOrderID("order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 0 {
+		t.Errorf("expected no votes after delete, got %d", len(votes))
+	}
+	votes, err = voteDB.GetByOrderID("order2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(votes) != 1 {
+		t.Error("expected votes for other orders to remain")
+	}
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type PostCommentsDB struct {
+	modelStore
+}
+
+func NewPostCommentStore(db *sql.DB, lock *sync.Mutex) repo.PostCommentStore {
+	return &PostCommentsDB{modelStore{db, lock}}
+}
+
+func (p *PostCommentsDB) Put(comment repo.PostComment) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into postcomments(commentID, postSlug, reference, authorID, postType, signedPost, timestamp) values(?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(comment.ID, comment.PostSlug, comment.Reference, comment.AuthorID, comment.PostType, comment.SignedPost, comment.Timestamp.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *PostCommentsDB) Get(commentID string) (*repo.PostComment, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	row := p.db.QueryRow("select commentID, postSlug, reference, authorID, postType, signedPost, timestamp from postcomments where commentID=?", commentID)
+	return scanPostComment(row)
+}
+
+func (p *PostCommentsDB) GetByPost(postSlug string) ([]repo.PostComment, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	rows, err := p.db.Query("select commentID, postSlug, reference, authorID, postType, signedPost, timestamp from postcomments where postSlug=? order by timestamp asc, rowid asc", postSlug)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.PostComment
+	for rows.Next() {
+		comment, err := scanPostComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *comment)
+	}
+	return ret, nil
+}
+
+func (p *PostCommentsDB) DeleteByPost(postSlug string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, err := p.db.Exec("delete from postcomments where postSlug=?", postSlug)
+	return err
+}
+
+func scanPostComment(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.PostComment, error) {
+	var (
+		comment   repo.PostComment
+		timestamp int64
+	)
+	if err := row.Scan(&comment.ID, &comment.PostSlug, &comment.Reference, &comment.AuthorID, &comment.PostType, &comment.SignedPost, &timestamp); err != nil {
+		return nil, err
+	}
+	comment.Timestamp = time.Unix(timestamp, 0)
+	return &comment, nil
+}
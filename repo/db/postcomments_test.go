@@ -0,0 +1,88 @@
+package db_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewPostCommentStore() (repo.PostCommentStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewPostCommentStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func newPostComment(id, postSlug, reference string, timestamp int64) repo.PostComment {
+	return repo.PostComment{
+		ID:         id,
+		PostSlug:   postSlug,
+		Reference:  reference,
+		AuthorID:   "author",
+		PostType:   "COMMENT",
+		SignedPost: []byte(id),
+		Timestamp:  time.Unix(timestamp, 0),
+	}
+}
+
+func TestPostCommentsDB_PutAndGet(t *testing.T) {
+	commentDB, teardown, err := buildNewPostCommentStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, comment := range []repo.PostComment{
+		newPostComment("author/reply", "post1", "author/first", 2000),
+		newPostComment("author/first", "post1", "self/post1", 1000),
+		newPostComment("author/other", "post2", "self/post2", 1500),
+	} {
+		if err := commentDB.Put(comment); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	comment, err := commentDB.Get("author/first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment.PostSlug != "post1" || comment.Reference != "self/post1" || string(comment.SignedPost) != "author/first" || !comment.Timestamp.Equal(time.Unix(1000, 0)) {
+		t.Errorf("unexpected comment: %+v", comment)
+	}
+	if _, err := commentDB.Get("unknown"); err == nil {
+		t.Error("expected an unknown comment to return an error")
+	}
+
+	comments, err := commentDB.GetByPost("post1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].ID != "author/first" || comments[1].ID != "author/reply" {
+		t.Errorf("expected the comments on the post oldest first, got %+v", comments)
+	}
+
+	if err := commentDB.DeleteByPost("post1"); err != nil {
+		t.Fatal(err)
+	}
+	if comments, _ := commentDB.GetByPost("post1"); len(comments) != 0 {
+		t.Error("expected the comments to be deleted")
+	}
+	if comments, _ := commentDB.GetByPost("post2"); len(comments) != 1 {
+		t.Error("expected comments on other posts to remain")
+	}
+}
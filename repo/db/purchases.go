@@ -65,6 +65,8 @@ func (p *PurchasesDB) Put(orderID string, contract pb.RicardianContract, state p
 		paymentAddr = contract.BuyerOrder.Payment.Address
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_ADDRESS_REQUEST {
 		paymentAddr = contract.VendorOrderConfirmation.PaymentAddress
+	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL && contract.VendorOrderConfirmation != nil {
+		paymentAddr = contract.VendorOrderConfirmation.PaymentInvoice
 	}
 
 	if dispute != nil {
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type RatchetSessionsDB struct {
+	modelStore
+}
+
+func NewRatchetSessionStore(db *sql.DB, lock *sync.Mutex) repo.RatchetSessionStore {
+	return &RatchetSessionsDB{modelStore{db, lock}}
+}
+
+func (r *RatchetSessionsDB) MarkCapable(peerID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := r.db.Exec("insert or ignore into ratchetsessions(peerID, timestamp) values(?,?)", peerID, time.Now().Unix())
+	return err
+}
+
+func (r *RatchetSessionsDB) Get(peerID string) ([]byte, bool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var state []byte
+	err := r.db.QueryRow("select state from ratchetsessions where peerID=?", peerID).Scan(&state)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return state, true, nil
+}
+
+func (r *RatchetSessionsDB) Put(peerID string, state []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := r.db.Exec("insert or replace into ratchetsessions(peerID, state, timestamp) values(?,?,?)", peerID, state, time.Now().Unix())
+	return err
+}
+
+func (r *RatchetSessionsDB) Delete(peerID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := r.db.Exec("delete from ratchetsessions where peerID=?", peerID)
+	return err
+}
@@ -0,0 +1,77 @@
+package db_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewRatchetSessionStore() (repo.RatchetSessionStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewRatchetSessionStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func TestRatchetSessionsDB(t *testing.T) {
+	sessionDB, teardown, err := buildNewRatchetSessionStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	state, capable, err := sessionDB.Get("peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capable || state != nil {
+		t.Error("expected an unknown peer not to be capable")
+	}
+
+	if err := sessionDB.MarkCapable("peer"); err != nil {
+		t.Fatal(err)
+	}
+	state, capable, err = sessionDB.Get("peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capable || state != nil {
+		t.Error("expected a capable peer without a session")
+	}
+
+	if err := sessionDB.Put("peer", []byte("state")); err != nil {
+		t.Fatal(err)
+	}
+	// Marking a peer capable again must keep its session
+	if err := sessionDB.MarkCapable("peer"); err != nil {
+		t.Fatal(err)
+	}
+	state, capable, err = sessionDB.Get("peer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !capable || string(state) != "state" {
+		t.Error("expected the saved session to be returned")
+	}
+
+	if err := sessionDB.Delete("peer"); err != nil {
+		t.Fatal(err)
+	}
+	if _, capable, _ = sessionDB.Get("peer"); capable {
+		t.Error("expected a deleted peer not to be capable")
+	}
+}
@@ -68,6 +68,8 @@ func (s *SalesDB) Put(orderID string, contract pb.RicardianContract, state pb.Or
 		address = contract.BuyerOrder.Payment.Address
 	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_ADDRESS_REQUEST {
 		address = contract.VendorOrderConfirmation.PaymentAddress
+	} else if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL && contract.VendorOrderConfirmation != nil {
+		address = contract.VendorOrderConfirmation.PaymentInvoice
 	}
 
 	defer stmt.Close()
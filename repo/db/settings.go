@@ -115,6 +115,18 @@ func (s *SettingsDB) Update(settings repo.SettingsData) error {
 	if settings.Version == nil {
 		settings.Version = current.Version
 	}
+	if settings.ModeratorAvailabilityPolicy == nil {
+		settings.ModeratorAvailabilityPolicy = current.ModeratorAvailabilityPolicy
+	}
+	if settings.VacationMode == nil {
+		settings.VacationMode = current.VacationMode
+	}
+	if settings.Webhooks == nil {
+		settings.Webhooks = current.Webhooks
+	}
+	if settings.NotificationPreferences == nil {
+		settings.NotificationPreferences = current.NotificationPreferences
+	}
 	err = s.Put(settings)
 	if err != nil {
 		return err
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type SigningRequestsDB struct {
+	modelStore
+}
+
+func NewSigningRequestStore(db *sql.DB, lock *sync.Mutex) repo.SigningRequestStore {
+	return &SigningRequestsDB{modelStore{db, lock}}
+}
+
+func (s *SigningRequestsDB) Put(request repo.SigningRequest) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into signingrequests(requestID, orderID, wallet, action, state, psbt, address, memo, timestamp) values(?,?,?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(request.ID, request.OrderID, request.Wallet, request.Action, request.State, request.PSBT, request.Address, request.Memo, request.Timestamp.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SigningRequestsDB) Get(requestID string) (*repo.SigningRequest, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	row := s.db.QueryRow("select requestID, orderID, wallet, action, state, psbt, address, memo, timestamp from signingrequests where requestID=?", requestID)
+	return scanSigningRequest(row)
+}
+
+func (s *SigningRequestsDB) GetAll(state, orderID string) ([]repo.SigningRequest, error) {
+	var (
+		stm     = "select requestID, orderID, wallet, action, state, psbt, address, memo, timestamp from signingrequests"
+		clauses []string
+		args    []interface{}
+	)
+	if state != "" {
+		clauses = append(clauses, "state=?")
+		args = append(args, state)
+	}
+	if orderID != "" {
+		clauses = append(clauses, "orderID=?")
+		args = append(args, orderID)
+	}
+	for i, clause := range clauses {
+		if i == 0 {
+			stm += " where " + clause
+		} else {
+			stm += " and " + clause
+		}
+	}
+	stm += " order by timestamp desc, rowid desc;"
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	rows, err := s.db.Query(stm, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.SigningRequest
+	for rows.Next() {
+		request, err := scanSigningRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *request)
+	}
+	return ret, nil
+}
+
+func (s *SigningRequestsDB) Delete(requestID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.db.Exec("delete from signingrequests where requestID=?", requestID)
+	return err
+}
+
+func scanSigningRequest(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.SigningRequest, error) {
+	var (
+		request   repo.SigningRequest
+		timestamp int64
+	)
+	if err := row.Scan(&request.ID, &request.OrderID, &request.Wallet, &request.Action, &request.State, &request.PSBT, &request.Address, &request.Memo, &timestamp); err != nil {
+		return nil, err
+	}
+	request.Timestamp = time.Unix(timestamp, 0)
+	return &request, nil
+}
@@ -0,0 +1,115 @@
+package db_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewSigningRequestStore() (repo.SigningRequestStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewSigningRequestStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func newSigningRequest(id, orderID, state string, timestamp int64) repo.SigningRequest {
+	return repo.SigningRequest{
+		ID:        id,
+		OrderID:   orderID,
+		Wallet:    "BTC",
+		Action:    "refund",
+		State:     state,
+		PSBT:      []byte("psbt-" + id),
+		Timestamp: time.Unix(timestamp, 0),
+	}
+}
+
+func TestSigningRequestsDB_PutGet(t *testing.T) {
+	store, teardown, err := buildNewSigningRequestStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	request := newSigningRequest("tx1", "order1", repo.SigningRequestPending, 100)
+	if err := store.Put(request); err != nil {
+		t.Fatal(err)
+	}
+	request.State = repo.SigningRequestSigned
+	request.PSBT = []byte("signed")
+	if err := store.Put(request); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get("tx1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != repo.SigningRequestSigned || !bytes.Equal(got.PSBT, []byte("signed")) || got.OrderID != "order1" ||
+		got.Action != "refund" || !got.Timestamp.Equal(request.Timestamp) {
+		t.Errorf("unexpected request: %+v", got)
+	}
+
+	if err := store.Delete("tx1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("tx1"); err == nil {
+		t.Error("expected deleted request to be gone")
+	}
+}
+
+func TestSigningRequestsDB_GetAll(t *testing.T) {
+	store, teardown, err := buildNewSigningRequestStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, request := range []repo.SigningRequest{
+		newSigningRequest("tx1", "order1", repo.SigningRequestPending, 100),
+		newSigningRequest("tx2", "order1", repo.SigningRequestSigned, 200),
+		newSigningRequest("tx3", "order2", repo.SigningRequestPending, 300),
+	} {
+		if err := store.Put(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := store.GetAll("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].ID != "tx3" || all[2].ID != "tx1" {
+		t.Errorf("expected every request newest first, got %+v", all)
+	}
+	pending, err := store.GetAll(repo.SigningRequestPending, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("expected two pending requests, got %d", len(pending))
+	}
+	order, err := store.GetAll(repo.SigningRequestPending, "order1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(order) != 1 || order[0].ID != "tx1" {
+		t.Errorf("unexpected requests for order: %+v", order)
+	}
+}
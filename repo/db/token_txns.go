@@ -0,0 +1,144 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+// TokenTxnsDB keeps the transactions of a token wallet in the txns table.
+// Tokens have no coin type the table could be keyed by, so their rows are
+// kept under the token's currency code.
+type TokenTxnsDB struct {
+	modelStore
+	code string
+}
+
+// NewTokenTransactionStore returns the transaction store of the token with
+// the currency code
+func NewTokenTransactionStore(db *sql.DB, lock *sync.Mutex, code string) repo.TransactionStore {
+	return &TokenTxnsDB{modelStore{db, lock}, code}
+}
+
+func (t *TokenTxnsDB) Put(raw []byte, txid string, value, height int, timestamp time.Time, watchOnly bool) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into txns(coin, txid, value, height, timestamp, watchOnly, tx) values(?,?,?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	watchOnlyInt := 0
+	if watchOnly {
+		watchOnlyInt = 1
+	}
+	_, err = stmt.Exec(t.code, txid, value, height, int(timestamp.Unix()), watchOnlyInt, raw)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (t *TokenTxnsDB) Get(txid chainhash.Hash) (wallet.Txn, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var txn wallet.Txn
+	stmt, err := t.db.Prepare("select tx, value, height, timestamp, watchOnly from txns where txid=? and coin=?")
+	if err != nil {
+		return txn, err
+	}
+	defer stmt.Close()
+	var raw []byte
+	var height int
+	var timestamp int
+	var value int
+	var watchOnlyInt int
+	err = stmt.QueryRow(txid.String(), t.code).Scan(&raw, &value, &height, &timestamp, &watchOnlyInt)
+	if err != nil {
+		return txn, err
+	}
+	txn = wallet.Txn{
+		Txid:      txid.String(),
+		Value:     int64(value),
+		Height:    int32(height),
+		Timestamp: time.Unix(int64(timestamp), 0),
+		WatchOnly: watchOnlyInt > 0,
+		Bytes:     raw,
+	}
+	return txn, nil
+}
+
+func (t *TokenTxnsDB) GetAll(includeWatchOnly bool) ([]wallet.Txn, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var ret []wallet.Txn
+	stm := "select tx, txid, value, height, timestamp, watchOnly from txns where coin=?"
+	rows, err := t.db.Query(stm, t.code)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var raw []byte
+		var txid string
+		var value int
+		var height int
+		var timestamp int
+		var watchOnlyInt int
+		if err := rows.Scan(&raw, &txid, &value, &height, &timestamp, &watchOnlyInt); err != nil {
+			continue
+		}
+		if watchOnlyInt > 0 && !includeWatchOnly {
+			continue
+		}
+		ret = append(ret, wallet.Txn{
+			Txid:      txid,
+			Value:     int64(value),
+			Height:    int32(height),
+			Timestamp: time.Unix(int64(timestamp), 0),
+			WatchOnly: watchOnlyInt > 0,
+			Bytes:     raw,
+		})
+	}
+	return ret, nil
+}
+
+func (t *TokenTxnsDB) Delete(txid *chainhash.Hash) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("delete from txns where txid=? and coin=?", txid.String(), t.code)
+	return err
+}
+
+func (t *TokenTxnsDB) UpdateHeight(txid chainhash.Hash, height int, timestamp time.Time) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("update txns set height=?, timestamp=? where txid=? and coin=?")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(height, int(timestamp.Unix()), txid.String(), t.code)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/phoreproject/multiwallet/util"
+)
+
+func TestTokenTxnsDB(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	lock := new(sync.Mutex)
+	coinDB := NewTransactionStore(conn, lock, util.CoinTypePhore)
+	tokenDB := NewTokenTransactionStore(conn, lock, "TDAI")
+	otherDB := NewTokenTransactionStore(conn, lock, "TUSDC")
+
+	coinTxid := chainhash.DoubleHashH([]byte("coin"))
+	txid := chainhash.DoubleHashH([]byte("token"))
+	if err := coinDB.Put([]byte("coin"), coinTxid.String(), 1, 1, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokenDB.Put([]byte("token"), txid.String(), 5, 0, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokenDB.Put([]byte("watched"), chainhash.DoubleHashH([]byte("watched")).String(), 0, 0, time.Now(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	txn, err := tokenDB.Get(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(txn.Bytes) != "token" || txn.Value != 5 {
+		t.Errorf("expected the token's transaction, got %+v", txn)
+	}
+	if txns, _ := tokenDB.GetAll(false); len(txns) != 1 {
+		t.Errorf("expected one transaction without watch only ones, got %d", len(txns))
+	}
+	if txns, _ := tokenDB.GetAll(true); len(txns) != 2 {
+		t.Errorf("expected two transactions, got %d", len(txns))
+	}
+	if txns, _ := otherDB.GetAll(true); len(txns) != 0 {
+		t.Errorf("expected another token to see none of them, got %d", len(txns))
+	}
+
+	timestamp := time.Unix(1500000000, 0)
+	if err := tokenDB.UpdateHeight(txid, 7, timestamp); err != nil {
+		t.Fatal(err)
+	}
+	if txn, _ := tokenDB.Get(txid); txn.Height != 7 || !txn.Timestamp.Equal(timestamp) {
+		t.Errorf("expected height 7 at %s, got %d at %s", timestamp, txn.Height, txn.Timestamp)
+	}
+	if _, err := coinDB.Get(txid); err == nil {
+		t.Error("expected the coin's store not to see the token's transaction")
+	}
+	if txn, _ := coinDB.Get(coinTxid); txn.Height != 1 {
+		t.Error("expected the coin's transaction to be left alone")
+	}
+
+	if err := tokenDB.Delete(&txid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tokenDB.Get(txid); err == nil {
+		t.Error("expected the token's transaction to be deleted")
+	}
+	if _, err := coinDB.Get(coinTxid); err != nil {
+		t.Errorf("expected the coin's transaction to remain: %s", err)
+	}
+}
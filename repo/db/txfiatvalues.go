@@ -0,0 +1,79 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type TxFiatValuesDB struct {
+	modelStore
+}
+
+func NewTransactionFiatValueStore(db *sql.DB, lock *sync.Mutex) repo.TransactionFiatValueStore {
+	return &TxFiatValuesDB{modelStore{db, lock}}
+}
+
+func (t *TxFiatValuesDB) Put(value repo.TransactionFiatValue) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into txfiatvalues(txid, currency, rate, timestamp) values(?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(value.Txid, value.Currency, value.Rate, value.Timestamp.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *TxFiatValuesDB) Get(txid string) (*repo.TransactionFiatValue, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	row := t.db.QueryRow("select txid, currency, rate, timestamp from txfiatvalues where txid=?", txid)
+	return scanTransactionFiatValue(row)
+}
+
+func (t *TxFiatValuesDB) GetAll() (map[string]repo.TransactionFiatValue, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	rows, err := t.db.Query("select txid, currency, rate, timestamp from txfiatvalues")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]repo.TransactionFiatValue)
+	for rows.Next() {
+		value, err := scanTransactionFiatValue(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret[value.Txid] = *value
+	}
+	return ret, nil
+}
+
+func scanTransactionFiatValue(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.TransactionFiatValue, error) {
+	var (
+		value     repo.TransactionFiatValue
+		timestamp int64
+	)
+	if err := row.Scan(&value.Txid, &value.Currency, &value.Rate, &timestamp); err != nil {
+		return nil, err
+	}
+	value.Timestamp = time.Unix(timestamp, 0)
+	return &value, nil
+}
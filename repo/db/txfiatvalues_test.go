@@ -0,0 +1,63 @@
+package db_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewTransactionFiatValueStore() (repo.TransactionFiatValueStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewTransactionFiatValueStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func TestTxFiatValuesDB(t *testing.T) {
+	store, teardown, err := buildNewTransactionFiatValueStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	value := repo.TransactionFiatValue{Txid: "txid1", Currency: "USD", Rate: 6512.25, Timestamp: time.Unix(100, 0)}
+	if err := store.Put(value); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(repo.TransactionFiatValue{Txid: "txid2", Currency: "EUR", Rate: 0.5, Timestamp: time.Unix(200, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get("txid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != value {
+		t.Errorf("expected %+v, got %+v", value, *got)
+	}
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("expected an error for a missing rate")
+	}
+
+	all, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all["txid2"].Currency != "EUR" || all["txid2"].Rate != 0.5 {
+		t.Errorf("unexpected rates: %+v", all)
+	}
+}
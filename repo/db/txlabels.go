@@ -0,0 +1,95 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type TxLabelsDB struct {
+	modelStore
+}
+
+func NewTransactionLabelStore(db *sql.DB, lock *sync.Mutex) repo.TransactionLabelStore {
+	return &TxLabelsDB{modelStore{db, lock}}
+}
+
+func (t *TxLabelsDB) Put(label repo.TransactionLabel) error {
+	labels, err := json.Marshal(label.Labels)
+	if err != nil {
+		return err
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into txlabels(target, kind, category, labels, timestamp) values(?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(label.Target, label.Kind, label.Category, string(labels), label.Timestamp.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (t *TxLabelsDB) Get(target string) (*repo.TransactionLabel, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	row := t.db.QueryRow("select target, kind, category, labels, timestamp from txlabels where target=?", target)
+	return scanTransactionLabel(row)
+}
+
+func (t *TxLabelsDB) GetAll() (map[string]repo.TransactionLabel, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	rows, err := t.db.Query("select target, kind, category, labels, timestamp from txlabels")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(map[string]repo.TransactionLabel)
+	for rows.Next() {
+		label, err := scanTransactionLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret[label.Target] = *label
+	}
+	return ret, nil
+}
+
+func (t *TxLabelsDB) Delete(target string) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.db.Exec("delete from txlabels where target=?", target)
+	return err
+}
+
+func scanTransactionLabel(row interface {
+	Scan(dest ...interface{}) error
+}) (*repo.TransactionLabel, error) {
+	var (
+		label     repo.TransactionLabel
+		labels    string
+		timestamp int64
+	)
+	if err := row.Scan(&label.Target, &label.Kind, &label.Category, &labels, &timestamp); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(labels), &label.Labels); err != nil {
+		return nil, err
+	}
+	label.Timestamp = time.Unix(timestamp, 0)
+	return &label, nil
+}
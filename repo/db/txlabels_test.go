@@ -0,0 +1,108 @@
+package db_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewTransactionLabelStore() (repo.TransactionLabelStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewTransactionLabelStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func TestTxLabelsDB_PutGet(t *testing.T) {
+	store, teardown, err := buildNewTransactionLabelStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	label := repo.TransactionLabel{
+		Target:    "txid1",
+		Kind:      repo.TransactionLabelKindTransaction,
+		Category:  "sales",
+		Labels:    []string{"tax", "2018"},
+		Timestamp: time.Unix(100, 0),
+	}
+	if err := store.Put(label); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get("txid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*got, label) {
+		t.Errorf("expected %+v, got %+v", label, *got)
+	}
+
+	label.Category = "refunds"
+	label.Labels = nil
+	if err := store.Put(label); err != nil {
+		t.Fatal(err)
+	}
+	got, err = store.Get("txid1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Category != "refunds" || len(got.Labels) != 0 {
+		t.Errorf("expected the label to be replaced, got %+v", *got)
+	}
+
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("expected an error for a missing label")
+	}
+}
+
+func TestTxLabelsDB_GetAllDelete(t *testing.T) {
+	store, teardown, err := buildNewTransactionLabelStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, label := range []repo.TransactionLabel{
+		{Target: "txid1", Kind: repo.TransactionLabelKindTransaction, Labels: []string{"tax"}},
+		{Target: "1Address", Kind: repo.TransactionLabelKindAddress, Category: "donations"},
+	} {
+		if err := store.Put(label); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all["1Address"].Kind != repo.TransactionLabelKindAddress || all["txid1"].Labels[0] != "tax" {
+		t.Errorf("unexpected labels: %+v", all)
+	}
+
+	if err := store.Delete("txid1"); err != nil {
+		t.Fatal(err)
+	}
+	all, err = store.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := all["txid1"]; ok || len(all) != 1 {
+		t.Errorf("expected the label to be deleted, got %+v", all)
+	}
+}
@@ -24,3 +24,10 @@ func CoinTypeForContract(contract *pb.RicardianContract) string {
 
 	return coinType
 }
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -13,11 +13,17 @@ import (
 // Sets a pointer to SQL database and syncs reader/writer mutex-based lock.
 type WatchedScriptsDB struct {
 	modelStore
-	coinType util.ExtCoinType
+	coin string
 }
 
 func NewWatchedScriptStore(db *sql.DB, lock *sync.Mutex, coinType util.ExtCoinType) repo.WatchedScriptStore {
-	return &WatchedScriptsDB{modelStore{db, lock}, coinType}
+	return &WatchedScriptsDB{modelStore{db, lock}, coinType.CurrencyCode()}
+}
+
+// NewTokenWatchedScriptStore returns the watched script store of a token,
+// kept under its currency code
+func NewTokenWatchedScriptStore(db *sql.DB, lock *sync.Mutex, code string) repo.WatchedScriptStore {
+	return &WatchedScriptsDB{modelStore{db, lock}, code}
 }
 
 // WatchdScriptsDB Put method insert and replace operations based on watched script public keys.
@@ -31,7 +37,7 @@ func (w *WatchedScriptsDB) Put(scriptPubKey []byte) error {
 		return err
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec(w.coinType.CurrencyCode(), hex.EncodeToString(scriptPubKey))
+	_, err = stmt.Exec(w.coin, hex.EncodeToString(scriptPubKey))
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -46,7 +52,7 @@ func (w *WatchedScriptsDB) GetAll() ([][]byte, error) {
 	defer w.lock.Unlock()
 	var ret [][]byte
 	stm := "select scriptPubKey from watchedscripts where coin=?"
-	rows, err := w.db.Query(stm, w.coinType.CurrencyCode())
+	rows, err := w.db.Query(stm, w.coin)
 	if err != nil {
 		return ret, err
 	}
@@ -69,7 +75,7 @@ func (w *WatchedScriptsDB) GetAll() ([][]byte, error) {
 func (w *WatchedScriptsDB) Delete(scriptPubKey []byte) error {
 	w.lock.Lock()
 	defer w.lock.Unlock()
-	_, err := w.db.Exec("delete from watchedscripts where scriptPubKey=? and coin=?", hex.EncodeToString(scriptPubKey), w.coinType.CurrencyCode())
+	_, err := w.db.Exec("delete from watchedscripts where scriptPubKey=? and coin=?", hex.EncodeToString(scriptPubKey), w.coin)
 	if err != nil {
 		return err
 	}
@@ -80,3 +80,31 @@ func TestWatchedScriptsDB_Delete(t *testing.T) {
 		}
 	}
 }
+
+func TestWatchedScriptsDB_TokenIsolation(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	lock := new(sync.Mutex)
+	coinDB := NewWatchedScriptStore(conn, lock, util.CoinTypePhore)
+	tokenDB := NewTokenWatchedScriptStore(conn, lock, "TDAI")
+
+	if err := coinDB.Put([]byte("coin")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokenDB.Put([]byte("token")); err != nil {
+		t.Fatal(err)
+	}
+	scripts, err := tokenDB.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scripts) != 1 || !bytes.Equal(scripts[0], []byte("token")) {
+		t.Errorf("expected only the token's script, got %q", scripts)
+	}
+	if err := tokenDB.Delete([]byte("coin")); err != nil {
+		t.Fatal(err)
+	}
+	if scripts, _ := coinDB.GetAll(); len(scripts) != 1 {
+		t.Error("expected the token store to leave the coin's scripts alone")
+	}
+}
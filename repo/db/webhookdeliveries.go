@@ -0,0 +1,115 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+type WebhookDeliveriesDB struct {
+	modelStore
+}
+
+func NewWebhookDeliveryStore(db *sql.DB, lock *sync.Mutex) repo.WebhookDeliveryStore {
+	return &WebhookDeliveriesDB{modelStore{db, lock}}
+}
+
+func (w *WebhookDeliveriesDB) Put(delivery repo.WebhookDelivery) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	// Update in place so retries keep the delivery's position in the log
+	res, err := tx.Exec("update webhookdeliveries set url=?, type=?, payload=?, status=?, attempts=?, lastError=?, timestamp=?, updated=? where deliveryID=?",
+		delivery.URL, delivery.Type, []byte(delivery.Payload), delivery.Status, delivery.Attempts, delivery.LastError, delivery.Timestamp.Unix(), delivery.Updated.Unix(), delivery.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if updated, err := res.RowsAffected(); err == nil && updated > 0 {
+		return tx.Commit()
+	}
+	_, err = tx.Exec("insert into webhookdeliveries(deliveryID, url, type, payload, status, attempts, lastError, timestamp, updated) values(?,?,?,?,?,?,?,?,?)",
+		delivery.ID, delivery.URL, delivery.Type, []byte(delivery.Payload), delivery.Status, delivery.Attempts, delivery.LastError, delivery.Timestamp.Unix(), delivery.Updated.Unix())
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (w *WebhookDeliveriesDB) Get(deliveryID string) (*repo.WebhookDelivery, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	row := w.db.QueryRow("select deliveryID, url, type, payload, status, attempts, lastError, timestamp, updated from webhookdeliveries where deliveryID=?", deliveryID)
+	return scanWebhookDelivery(row)
+}
+
+func (w *WebhookDeliveriesDB) GetAll(status, offsetID string, limit int) ([]repo.WebhookDelivery, error) {
+	stm := "select deliveryID, url, type, payload, status, attempts, lastError, timestamp, updated from webhookdeliveries"
+	var (
+		clauses []string
+		args    []interface{}
+	)
+	if status != "" {
+		clauses = append(clauses, "status=?")
+		args = append(args, status)
+	}
+	if offsetID != "" {
+		clauses = append(clauses, "rowid<(select rowid from webhookdeliveries where deliveryID=?)")
+		args = append(args, offsetID)
+	}
+	for i, clause := range clauses {
+		if i == 0 {
+			stm += " where " + clause
+		} else {
+			stm += " and " + clause
+		}
+	}
+	stm += " order by rowid desc"
+	if limit >= 0 {
+		stm += " limit " + strconv.Itoa(limit)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	rows, err := w.db.Query(stm+";", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ret []repo.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *delivery)
+	}
+	return ret, nil
+}
+
+type webhookDeliveryScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhookDelivery(row webhookDeliveryScanner) (*repo.WebhookDelivery, error) {
+	var (
+		delivery           repo.WebhookDelivery
+		payload            []byte
+		timestamp, updated int64
+	)
+	if err := row.Scan(&delivery.ID, &delivery.URL, &delivery.Type, &payload, &delivery.Status, &delivery.Attempts, &delivery.LastError, &timestamp, &updated); err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	delivery.Timestamp = time.Unix(timestamp, 0)
+	delivery.Updated = time.Unix(updated, 0)
+	return &delivery, nil
+}
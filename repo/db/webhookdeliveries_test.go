@@ -0,0 +1,123 @@
+package db_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/repo/db"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func buildNewWebhookDeliveryStore() (repo.WebhookDeliveryStore, func(), error) {
+	appSchema := schema.MustNewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err := appSchema.BuildSchemaDirectories(); err != nil {
+		return nil, nil, err
+	}
+	if err := appSchema.InitializeDatabase(); err != nil {
+		return nil, nil, err
+	}
+	database, err := appSchema.OpenDatabase()
+	if err != nil {
+		return nil, nil, err
+	}
+	return db.NewWebhookDeliveryStore(database, new(sync.Mutex)), appSchema.DestroySchemaDirectories, nil
+}
+
+func newWebhookDelivery(id, status string) repo.WebhookDelivery {
+	return repo.WebhookDelivery{
+		ID:        id,
+		URL:       "http://localhost/hook",
+		Type:      "order",
+		Payload:   json.RawMessage(`{"deliveryId":"` + id + `"}`),
+		Status:    status,
+		Timestamp: time.Unix(1000, 0),
+		Updated:   time.Unix(1000, 0),
+	}
+}
+
+func TestWebhookDeliveriesDB_PutAndGet(t *testing.T) {
+	deliveryDB, teardown, err := buildNewWebhookDeliveryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	delivery := newWebhookDelivery("delivery1", repo.WebhookDeliveryPending)
+	if err := deliveryDB.Put(delivery); err != nil {
+		t.Fatal(err)
+	}
+	delivery.Status = repo.WebhookDeliveryFailed
+	delivery.Attempts = 5
+	delivery.LastError = "timeout"
+	delivery.Updated = time.Unix(2000, 0)
+	if err := deliveryDB.Put(delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	ret, err := deliveryDB.Get("delivery1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ret.Status != repo.WebhookDeliveryFailed || ret.Attempts != 5 || ret.LastError != "timeout" {
+		t.Errorf("unexpected delivery: %+v", ret)
+	}
+	if string(ret.Payload) != string(delivery.Payload) || ret.URL != delivery.URL || ret.Type != "order" {
+		t.Errorf("unexpected delivery: %+v", ret)
+	}
+	if !ret.Timestamp.Equal(time.Unix(1000, 0)) || !ret.Updated.Equal(time.Unix(2000, 0)) {
+		t.Error("incorrect timestamps")
+	}
+	if _, err := deliveryDB.Get("unknown"); err == nil {
+		t.Error("expected an unknown delivery to return an error")
+	}
+}
+
+func TestWebhookDeliveriesDB_GetAll(t *testing.T) {
+	deliveryDB, teardown, err := buildNewWebhookDeliveryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+
+	for _, delivery := range []repo.WebhookDelivery{
+		newWebhookDelivery("delivery1", repo.WebhookDeliveryFailed),
+		newWebhookDelivery("delivery2", repo.WebhookDeliveryDelivered),
+		newWebhookDelivery("delivery3", repo.WebhookDeliveryFailed),
+	} {
+		if err := deliveryDB.Put(delivery); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Updating a delivery must not move it in the log
+	if err := deliveryDB.Put(newWebhookDelivery("delivery1", repo.WebhookDeliveryFailed)); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := deliveryDB.GetAll("", "", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].ID != "delivery3" || all[2].ID != "delivery1" {
+		t.Errorf("expected every delivery newest first, got %+v", all)
+	}
+	failed, err := deliveryDB.GetAll(repo.WebhookDeliveryFailed, "", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 2 || failed[0].ID != "delivery3" || failed[1].ID != "delivery1" {
+		t.Errorf("unexpected failed deliveries: %+v", failed)
+	}
+	page, err := deliveryDB.GetAll(repo.WebhookDeliveryFailed, "delivery3", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].ID != "delivery1" {
+		t.Errorf("unexpected page of deliveries: %+v", page)
+	}
+}
@@ -20,7 +20,7 @@ import (
 	"github.com/tyler-smith/go-bip39"
 )
 
-const RepoVersion = "23"
+const RepoVersion = "37"
 
 var log = logging.MustGetLogger("repo")
 var ErrRepoExists = errors.New("IPFS configuration file exists. Reinitializing would overwrite your keys. Use -f to force overwrite.")
@@ -198,6 +198,9 @@ func addConfigExtensions(repoRoot string) error {
 	if err := r.SetConfigKey("Wallets", schema.DefaultWalletsConfig()); err != nil {
 		return err
 	}
+	if err := r.SetConfigKey("FundingConfirmations", schema.DefaultFundingConfirmationsConfig()); err != nil {
+		return err
+	}
 	if err := r.SetConfigKey("DataSharing", ds); err != nil {
 		return err
 	}
@@ -43,6 +43,20 @@ var (
 		migrations.Migration020{},
 		migrations.Migration021{},
 		migrations.Migration022{},
+		migrations.Migration023{},
+		migrations.Migration024{},
+		migrations.Migration025{},
+		migrations.Migration026{},
+		migrations.Migration027{},
+		migrations.Migration028{},
+		migrations.Migration029{},
+		migrations.Migration030{},
+		migrations.Migration031{},
+		migrations.Migration032{},
+		migrations.Migration033{},
+		migrations.Migration034{},
+		migrations.Migration035{},
+		migrations.Migration036{},
 	}
 )
 
@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration023CreateTableCaseHandoversSQL = "create table casehandovers (orderID text primary key not null, moderator text, backupModerator text, buyerID text, vendorID text, buyerAccepted integer, vendorAccepted integer, rejected integer, signedHandover blob, timestamp integer);"
+	Migration023CreateIndexCaseHandoversSQL = "create index index_casehandovers on casehandovers (backupModerator, timestamp);"
+)
+
+// Migration023 creates the casehandovers table which tracks disputes being
+// handed over from an unavailable moderator to a backup moderator.
+type Migration023 struct{}
+
+func (Migration023) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration023CreateTableCaseHandoversSQL,
+			Migration023CreateIndexCaseHandoversSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 24); err != nil {
+		return fmt.Errorf("bumping repover to 24: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration023) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_casehandovers;",
+			"drop table if exists casehandovers;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 23); err != nil {
+		return fmt.Errorf("dropping repover to 23: %s", err.Error())
+	}
+	return nil
+}
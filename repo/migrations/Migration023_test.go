@@ -0,0 +1,86 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration023(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(schemaSQL); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("23"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration023{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("24"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("insert into casehandovers(orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, rejected, signedHandover, timestamp) values(?,?,?,?,?,?,?,?,?,?)",
+		"orderID", "moderator", "backup", "buyer", "vendor", 1, 0, 0, []byte("handover"), 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var backupModerator string
+	if err := db.QueryRow("select backupModerator from casehandovers where orderID = ?", "orderID").Scan(&backupModerator); err != nil {
+		t.Fatal(err)
+	}
+	if backupModerator != "backup" {
+		t.Errorf("expected backupModerator to be 'backup', was '%s'", backupModerator)
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("23"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("select count(*) from casehandovers;")
+	if err == nil {
+		t.Error("expected casehandovers table to be dropped")
+	}
+	if err != nil && !strings.Contains(err.Error(), "no such table: casehandovers") {
+		t.Error("expected error to be 'no such table', was:", err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration024CreateTablePanelVotesSQL = "create table panelvotes (orderID text not null, moderatorID text not null, vote blob, timestamp integer, primary key (orderID, moderatorID));"
+)
+
+// Migration024 creates the panelvotes table which holds the dispute
+// resolutions proposed by the members of a moderator panel.
+type Migration024 struct{}
+
+func (Migration024) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration024CreateTablePanelVotesSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 25); err != nil {
+		return fmt.Errorf("bumping repover to 25: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration024) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop table if exists panelvotes;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 24); err != nil {
+		return fmt.Errorf("dropping repover to 24: %s", err.Error())
+	}
+	return nil
+}
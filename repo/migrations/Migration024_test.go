@@ -0,0 +1,84 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration024(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(schemaSQL); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("24"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration024{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("25"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("insert into panelvotes(orderID, moderatorID, vote, timestamp) values(?,?,?,?)",
+		"orderID", "moderator", []byte("vote"), 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("insert into panelvotes(orderID, moderatorID, vote, timestamp) values(?,?,?,?)",
+		"orderID", "moderator", []byte("vote"), 1234)
+	if err == nil {
+		t.Error("expected one vote per moderator and order")
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("24"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("select count(*) from panelvotes;")
+	if err == nil {
+		t.Error("expected panelvotes table to be dropped")
+	}
+	if err != nil && !strings.Contains(err.Error(), "no such table: panelvotes") {
+		t.Error("expected error to be 'no such table', was:", err.Error())
+	}
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration025CreateTableChatAttachmentsSQL = "create table chatattachments (messageID text not null, idx integer not null, filename text, mediaType text, size integer, hash text, location text, data blob, primary key (messageID, idx));"
+)
+
+// Migration025 creates the chatattachments table which holds the files
+// attached to chat messages.
+type Migration025 struct{}
+
+func (Migration025) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration025CreateTableChatAttachmentsSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 26); err != nil {
+		return fmt.Errorf("bumping repover to 26: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration025) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop table if exists chatattachments;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 25); err != nil {
+		return fmt.Errorf("dropping repover to 25: %s", err.Error())
+	}
+	return nil
+}
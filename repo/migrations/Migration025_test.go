@@ -0,0 +1,84 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration025(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(schemaSQL); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("25"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration025{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("26"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("insert into chatattachments(messageID, idx, filename, mediaType, size, hash, location) values(?,?,?,?,?,?,?)",
+		"messageID", 0, "receipt.png", "image/png", 1234, "QmHash", "/ipfs/QmLocation/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("insert into chatattachments(messageID, idx, filename, mediaType, size, hash, location) values(?,?,?,?,?,?,?)",
+		"messageID", 0, "receipt.png", "image/png", 1234, "QmHash", "/ipfs/QmLocation/")
+	if err == nil {
+		t.Error("expected attachment indexes to be unique per message")
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("25"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("select count(*) from chatattachments;")
+	if err == nil {
+		t.Error("expected chatattachments table to be dropped")
+	}
+	if err != nil && !strings.Contains(err.Error(), "no such table: chatattachments") {
+		t.Error("expected error to be 'no such table', was:", err.Error())
+	}
+}
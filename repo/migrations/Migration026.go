@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration026CreateTableRatchetSessionsSQL = "create table ratchetsessions (peerID text primary key not null, state blob, timestamp integer);"
+)
+
+// Migration026 creates the ratchetsessions table which holds the double
+// ratchet chat sessions with other peers.
+type Migration026 struct{}
+
+func (Migration026) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration026CreateTableRatchetSessionsSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 27); err != nil {
+		return fmt.Errorf("bumping repover to 27: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration026) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop table if exists ratchetsessions;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 26); err != nil {
+		return fmt.Errorf("dropping repover to 26: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration027CreateTableChatGroupsSQL        = "create table chatgroups (groupID text primary key not null, name text, creator text, members text, version integer, membership blob, active integer, timestamp integer);"
+	Migration027CreateTableChatGroupMessagesSQL = "create table chatgroupmessages (messageID text primary key not null, groupID text, peerID text, message text, read integer, timestamp integer, outgoing integer);"
+	Migration027CreateIndexChatGroupMessagesSQL = "create index index_chatgroupmessages on chatgroupmessages (groupID, read, timestamp);"
+)
+
+// Migration027 creates the chatgroups and chatgroupmessages tables which hold
+// group conversations, their signed member lists and their history.
+type Migration027 struct{}
+
+func (Migration027) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration027CreateTableChatGroupsSQL,
+			Migration027CreateTableChatGroupMessagesSQL,
+			Migration027CreateIndexChatGroupMessagesSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 28); err != nil {
+		return fmt.Errorf("bumping repover to 28: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration027) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_chatgroupmessages;",
+			"drop table if exists chatgroupmessages;",
+			"drop table if exists chatgroups;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 27); err != nil {
+		return fmt.Errorf("dropping repover to 27: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration028CreateTableChatEditsSQL     = "create table chatedits (messageID text not null, peerID text, action text, previous text, message text, signedChat blob, timestamp integer);"
+	Migration028CreateIndexChatEditsSQL     = "create index index_chatedits on chatedits (messageID, timestamp);"
+	Migration028CreateTableChatReactionsSQL = "create table chatreactions (messageID text not null, peerID text not null, reaction text not null, timestamp integer, primary key (messageID, peerID, reaction));"
+)
+
+// Migration028 creates the chatedits table which keeps the signed edits and
+// retractions of chat messages and the chatreactions table.
+type Migration028 struct{}
+
+func (Migration028) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration028CreateTableChatEditsSQL,
+			Migration028CreateIndexChatEditsSQL,
+			Migration028CreateTableChatReactionsSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 29); err != nil {
+		return fmt.Errorf("bumping repover to 29: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration028) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_chatedits;",
+			"drop table if exists chatedits;",
+			"drop table if exists chatreactions;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 28); err != nil {
+		return fmt.Errorf("dropping repover to 28: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration028(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(schemaSQL); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("28"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration028{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("29"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("insert into chatedits(messageID, peerID, action, previous, message, signedChat, timestamp) values(?,?,?,?,?,?,?)", "messageID", "peerID", "edit", "helo", "hello", []byte("signed"), 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("insert into chatreactions(messageID, peerID, reaction, timestamp) values(?,?,?,?)", "messageID", "peerID", "+1", 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("insert into chatreactions(messageID, peerID, reaction, timestamp) values(?,?,?,?)", "messageID", "peerID", "+1", 1234)
+	if err == nil {
+		t.Error("expected one reaction of each kind per peer")
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("28"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, table := range []string{"chatedits", "chatreactions"} {
+		_, err = db.Exec("select count(*) from " + table + ";")
+		if err == nil {
+			t.Errorf("expected %s table to be dropped", table)
+		}
+		if err != nil && !strings.Contains(err.Error(), "no such table: "+table) {
+			t.Error("expected error to be 'no such table', was:", err.Error())
+		}
+	}
+}
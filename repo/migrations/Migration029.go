@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration029CreateTableWebhookDeliveriesSQL = "create table webhookdeliveries (deliveryID text primary key not null, url text, type text, payload blob, status text, attempts integer, lastError text, timestamp integer, updated integer);"
+	Migration029CreateIndexWebhookDeliveriesSQL = "create index index_webhookdeliveries on webhookdeliveries (status, timestamp);"
+)
+
+// Migration029 creates the webhookdeliveries table which logs the
+// notifications sent to webhooks so failed deliveries can be replayed.
+type Migration029 struct{}
+
+func (Migration029) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration029CreateTableWebhookDeliveriesSQL,
+			Migration029CreateIndexWebhookDeliveriesSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 30); err != nil {
+		return fmt.Errorf("bumping repover to 30: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration029) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_webhookdeliveries;",
+			"drop table if exists webhookdeliveries;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 29); err != nil {
+		return fmt.Errorf("dropping repover to 29: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration029(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(schemaSQL); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("29"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration029{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("30"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("insert into webhookdeliveries(deliveryID, url, type, payload, status, attempts, lastError, timestamp, updated) values(?,?,?,?,?,?,?,?,?)", "deliveryID", "http://localhost/hook", "order", []byte("{}"), "failed", 5, "timeout", 1234, 1240)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("29"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("select count(*) from webhookdeliveries;")
+	if err == nil {
+		t.Error("expected webhookdeliveries table to be dropped")
+	}
+	if err != nil && !strings.Contains(err.Error(), "no such table: webhookdeliveries") {
+		t.Error("expected error to be 'no such table', was:", err.Error())
+	}
+}
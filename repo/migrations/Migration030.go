@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration030CreateTablePostCommentsSQL = "create table postcomments (commentID text primary key not null, postSlug text not null, reference text, authorID text, postType text, signedPost blob, timestamp integer);"
+	Migration030CreateIndexPostCommentsSQL = "create index index_postcomments on postcomments (postSlug, timestamp);"
+)
+
+// Migration030 creates the postcomments table which holds the signed
+// comments and reposts other peers made on our posts.
+type Migration030 struct{}
+
+func (Migration030) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration030CreateTablePostCommentsSQL,
+			Migration030CreateIndexPostCommentsSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 31); err != nil {
+		return fmt.Errorf("bumping repover to 31: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration030) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_postcomments;",
+			"drop table if exists postcomments;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 30); err != nil {
+		return fmt.Errorf("dropping repover to 30: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration031CreateTableFeedSQL     = "create table feed (entryID text primary key not null, peerID text not null, entryType text not null, slug text not null, hash text, data blob, timestamp integer);"
+	Migration031CreateIndexFeedSQL     = "create index index_feed on feed (timestamp);"
+	Migration031CreateIndexFeedPeerSQL = "create index index_feed_peer on feed (peerID);"
+)
+
+// Migration031 creates the feed table which holds the posts and listings
+// collected from the peers we follow.
+type Migration031 struct{}
+
+func (Migration031) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration031CreateTableFeedSQL,
+			Migration031CreateIndexFeedSQL,
+			Migration031CreateIndexFeedPeerSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 32); err != nil {
+		return fmt.Errorf("bumping repover to 32: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration031) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_feed_peer;",
+			"drop index if exists index_feed;",
+			"drop table if exists feed;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 31); err != nil {
+		return fmt.Errorf("dropping repover to 31: %s", err.Error())
+	}
+	return nil
+}
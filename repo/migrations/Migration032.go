@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration032CreateTableSigningRequestsSQL = "create table signingrequests (requestID text primary key not null, orderID text, wallet text not null, action text not null, state text not null, psbt blob not null, address text, memo text, timestamp integer);"
+	Migration032CreateIndexSigningRequestsSQL = "create index index_signingrequests on signingrequests (orderID, state);"
+)
+
+// Migration032 creates the signingrequests table which holds the
+// transactions exported as PSBTs to be signed outside the node.
+type Migration032 struct{}
+
+func (Migration032) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration032CreateTableSigningRequestsSQL,
+			Migration032CreateIndexSigningRequestsSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 33); err != nil {
+		return fmt.Errorf("bumping repover to 33: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration032) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_signingrequests;",
+			"drop table if exists signingrequests;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 32); err != nil {
+		return fmt.Errorf("dropping repover to 32: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration033CreateTableTransactionLabelsSQL     = "create table txlabels (target text primary key not null, kind text not null, category text, labels text, timestamp integer);"
+	Migration033CreateTableTransactionFiatValuesSQL = "create table txfiatvalues (txid text primary key not null, currency text not null, rate real not null, timestamp integer);"
+)
+
+// Migration033 creates the txlabels table holding the user's labels for
+// wallet transactions and addresses, and the txfiatvalues table holding the
+// exchange rate of each transaction when the wallet saw it.
+type Migration033 struct{}
+
+func (Migration033) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration033CreateTableTransactionLabelsSQL,
+			Migration033CreateTableTransactionFiatValuesSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 34); err != nil {
+		return fmt.Errorf("bumping repover to 34: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration033) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop table if exists txlabels;",
+			"drop table if exists txfiatvalues;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 33); err != nil {
+		return fmt.Errorf("dropping repover to 33: %s", err.Error())
+	}
+	return nil
+}
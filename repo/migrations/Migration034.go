@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration034CreateTableExchangeRatesSQL      = "create table exchangerates (coin text not null, currency text not null, rate real not null, timestamp integer not null, primary key (coin, currency, timestamp));"
+	Migration034CreateTableOrderExchangeRatesSQL = "create table orderexchangerates (orderID text not null, coin text not null, currency text not null, rate real not null, timestamp integer, primary key (orderID, coin, currency));"
+)
+
+// Migration034 creates the exchangerates table holding samples of the
+// exchange rates over time, and the orderexchangerates table holding the
+// rates each order was priced at.
+type Migration034 struct{}
+
+func (Migration034) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			Migration034CreateTableExchangeRatesSQL,
+			Migration034CreateTableOrderExchangeRatesSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 35); err != nil {
+		return fmt.Errorf("bumping repover to 35: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration034) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop table if exists exchangerates;",
+			"drop table if exists orderexchangerates;",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 34); err != nil {
+		return fmt.Errorf("dropping repover to 34: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+// Migration035 writes the FundingConfirmations option to the config file.
+// Orders used to be funded as soon as their payment was seen; they now wait
+// for one confirmation of the payment unless the option says otherwise.
+// Setting the Default confirmations to 0 restores the old behaviour.
+type Migration035 struct{}
+
+func (Migration035) Up(repoPath, dbPassword string, testnet bool) error {
+	var (
+		configMap        = map[string]interface{}{}
+		configBytes, err = ioutil.ReadFile(path.Join(repoPath, "config"))
+	)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err.Error())
+	}
+
+	if err = json.Unmarshal(configBytes, &configMap); err != nil {
+		return fmt.Errorf("unmarshal config: %s", err.Error())
+	}
+
+	if _, ok := configMap["FundingConfirmations"]; !ok {
+		configMap["FundingConfirmations"] = map[string]interface{}{
+			"Default": []map[string]interface{}{
+				{"MinValue": 0, "Confirmations": 1},
+			},
+		}
+	}
+
+	newConfigBytes, err := json.MarshalIndent(configMap, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal migrated config: %s", err.Error())
+	}
+
+	if err := ioutil.WriteFile(path.Join(repoPath, "config"), newConfigBytes, os.ModePerm); err != nil {
+		return fmt.Errorf("writing migrated config: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 36); err != nil {
+		return fmt.Errorf("bumping repover to 36: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration035) Down(repoPath, dbPassword string, testnet bool) error {
+	var (
+		configMap        = map[string]interface{}{}
+		configBytes, err = ioutil.ReadFile(path.Join(repoPath, "config"))
+	)
+	if err != nil {
+		return fmt.Errorf("reading config: %s", err.Error())
+	}
+
+	if err = json.Unmarshal(configBytes, &configMap); err != nil {
+		return fmt.Errorf("unmarshal config: %s", err.Error())
+	}
+
+	delete(configMap, "FundingConfirmations")
+
+	newConfigBytes, err := json.MarshalIndent(configMap, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshal migrated config: %s", err.Error())
+	}
+
+	if err := ioutil.WriteFile(path.Join(repoPath, "config"), newConfigBytes, os.ModePerm); err != nil {
+		return fmt.Errorf("writing migrated config: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 35); err != nil {
+		return fmt.Errorf("dropping repover to 35: %s", err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package migrations_test
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+const preMigration035Config = `{
+	"DataSharing": {
+		"AcceptStoreRequests": false
+	}
+}`
+
+const postMigration035Config = `{
+	"DataSharing": {
+		"AcceptStoreRequests": false
+	},
+	"FundingConfirmations": {
+		"Default": [
+			{
+				"Confirmations": 1,
+				"MinValue": 0
+			}
+		]
+	}
+}`
+
+func TestMigration035(t *testing.T) {
+	var testRepo, err = schema.NewCustomSchemaManager(schema.SchemaContext{
+		DataPath:        schema.GenerateTempPath(),
+		TestModeEnabled: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = testRepo.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer testRepo.DestroySchemaDirectories()
+
+	var (
+		configPath  = testRepo.DataPathJoin("config")
+		repoverPath = testRepo.DataPathJoin("repover")
+	)
+	if err = ioutil.WriteFile(configPath, []byte(preMigration035Config), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(repoverPath, []byte("35"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	var m migrations.Migration035
+	err = m.Up(testRepo.DataPath(), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configBytes, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var re = regexp.MustCompile(`\s`)
+	if re.ReplaceAllString(string(configBytes), "") != re.ReplaceAllString(string(postMigration035Config), "") {
+		t.Logf("actual: %s", re.ReplaceAllString(string(configBytes), ""))
+		t.Fatal("incorrect post-migration config")
+	}
+
+	assertCorrectRepoVer(t, repoverPath, "36")
+
+	err = m.Down(testRepo.DataPath(), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configBytes, err = ioutil.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if re.ReplaceAllString(string(configBytes), "") != re.ReplaceAllString(string(preMigration035Config), "") {
+		t.Logf("actual: %s", re.ReplaceAllString(string(configBytes), ""))
+		t.Fatal("incorrect post-migration config")
+	}
+
+	assertCorrectRepoVer(t, repoverPath, "35")
+}
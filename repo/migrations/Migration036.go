@@ -0,0 +1,83 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	Migration036CreateTableCaseHandoversSQL = "create table casehandovers (orderID text primary key not null, moderator text, backupModerator text, buyerID text, vendorID text, buyerAccepted integer, vendorAccepted integer, buyerRejected integer, vendorRejected integer, signedHandover blob, timestamp integer, transferSigs blob, transferTxid text);"
+	Migration036CreateIndexCaseHandoversSQL = "create index index_casehandovers on casehandovers (backupModerator, timestamp);"
+)
+
+// Migration036 rebuilds the casehandovers table. A rejection is now tracked
+// for each party so that it can be withdrawn by accepting later, and the
+// table gains the columns a moderator uses to move the escrow of a handed
+// over dispute to the backup moderator: the transfer signatures of the first
+// party to accept and the txid of the broadcast transfer. Rejections made
+// before the migration are kept against both parties.
+type Migration036 struct{}
+
+func (Migration036) Up(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_casehandovers;",
+			"alter table casehandovers rename to casehandovers_old;",
+			Migration036CreateTableCaseHandoversSQL,
+			"insert into casehandovers select orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, rejected, rejected, signedHandover, timestamp, null, null from casehandovers_old;",
+			"drop table casehandovers_old;",
+			Migration036CreateIndexCaseHandoversSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating up: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 37); err != nil {
+		return fmt.Errorf("bumping repover to 37: %s", err.Error())
+	}
+	return nil
+}
+
+func (Migration036) Down(repoPath, dbPassword string, testnet bool) error {
+	db, err := OpenDB(repoPath, dbPassword, testnet)
+	if err != nil {
+		return fmt.Errorf("opening db: %s", err.Error())
+	}
+	defer db.Close()
+
+	err = withTransaction(db, func(tx *sql.Tx) error {
+		for _, stmt := range []string{
+			"drop index if exists index_casehandovers;",
+			"alter table casehandovers rename to casehandovers_old;",
+			Migration023CreateTableCaseHandoversSQL,
+			"insert into casehandovers select orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, max(buyerRejected, vendorRejected), signedHandover, timestamp from casehandovers_old;",
+			"drop table casehandovers_old;",
+			Migration023CreateIndexCaseHandoversSQL,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("migrating down: %s", err.Error())
+	}
+
+	if err := writeRepoVer(repoPath, 36); err != nil {
+		return fmt.Errorf("dropping repover to 36: %s", err.Error())
+	}
+	return nil
+}
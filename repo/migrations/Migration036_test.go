@@ -0,0 +1,114 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/openbazaar-go/repo/migrations"
+	"github.com/phoreproject/openbazaar-go/schema"
+)
+
+func TestMigration036(t *testing.T) {
+	var (
+		basePath          = schema.GenerateTempPath()
+		testRepoPath, err = schema.OpenbazaarPathTransform(basePath, true)
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appSchema, err := schema.NewCustomSchemaManager(schema.SchemaContext{DataPath: testRepoPath, TestModeEnabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.BuildSchemaDirectories(); err != nil {
+		t.Fatal(err)
+	}
+	defer appSchema.DestroySchemaDirectories()
+
+	var (
+		databasePath = appSchema.DatabasePath()
+		schemaPath   = appSchema.DataPathJoin("repover")
+		schemaSQL    = "pragma key = 'foobarbaz';"
+	)
+
+	db, err := sql.Open("sqlite3", databasePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		schemaSQL,
+		migrations.Migration023CreateTableCaseHandoversSQL,
+		migrations.Migration023CreateIndexCaseHandoversSQL,
+	} {
+		if _, err = db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = db.Exec("insert into casehandovers(orderID, moderator, backupModerator, buyerID, vendorID, buyerAccepted, vendorAccepted, rejected, signedHandover, timestamp) values(?,?,?,?,?,?,?,?,?,?)",
+		"orderID", "moderator", "backup", "buyer", "vendor", 1, 0, 1, []byte("handover"), 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(schemaPath, []byte("36"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute Migration Up
+	migration := migrations.Migration036{}
+	if err := migration.Up(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("37"); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		backupModerator, transferTxid string
+		buyerRejected, vendorRejected int
+	)
+	if err := db.QueryRow("select backupModerator, buyerRejected, vendorRejected from casehandovers where orderID = ?", "orderID").Scan(&backupModerator, &buyerRejected, &vendorRejected); err != nil {
+		t.Fatal(err)
+	}
+	if backupModerator != "backup" {
+		t.Errorf("expected backupModerator to be 'backup', was '%s'", backupModerator)
+	}
+	if buyerRejected != 1 || vendorRejected != 1 {
+		t.Error("expected the rejection to be kept against both parties")
+	}
+	if _, err = db.Exec("update casehandovers set buyerRejected = 0, transferSigs = ?, transferTxid = ? where orderID = ?", []byte("sigs"), "txid", "orderID"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("select transferTxid from casehandovers where orderID = ?", "orderID").Scan(&transferTxid); err != nil {
+		t.Fatal(err)
+	}
+	if transferTxid != "txid" {
+		t.Errorf("expected transferTxid to be 'txid', was '%s'", transferTxid)
+	}
+
+	// Execute Migration Down
+	if err := migration.Down(testRepoPath, "foobarbaz", true); err != nil {
+		t.Fatal(err)
+	}
+	if err = appSchema.VerifySchemaVersion("36"); err != nil {
+		t.Fatal(err)
+	}
+
+	var rejected int
+	if err := db.QueryRow("select backupModerator, rejected from casehandovers where orderID = ?", "orderID").Scan(&backupModerator, &rejected); err != nil {
+		t.Fatal(err)
+	}
+	if backupModerator != "backup" {
+		t.Errorf("expected backupModerator to be 'backup', was '%s'", backupModerator)
+	}
+	if rejected != 1 {
+		t.Error("expected the vendor's rejection to be kept")
+	}
+	_, err = db.Exec("select transferTxid from casehandovers;")
+	if err == nil || !strings.Contains(err.Error(), "no such column: transferTxid") {
+		t.Error("expected transferTxid column to be dropped, got:", err)
+	}
+}
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -19,6 +20,11 @@ type SettingsData struct {
 	SMTPSettings        *SMTPSettings      `json:"smtpSettings"`
 	Version             *string            `json:"version"`
 	PreferredCurrencies *[]string          `json:"preferredCurrencies"`
+
+	ModeratorAvailabilityPolicy *ModeratorAvailabilityPolicy `json:"moderatorAvailabilityPolicy,omitempty"`
+	VacationMode                *VacationMode                `json:"vacationMode,omitempty"`
+	Webhooks                    *[]WebhookSettings           `json:"webhooks,omitempty"`
+	NotificationPreferences     *NotificationPreferences     `json:"notificationPreferences,omitempty"`
 }
 
 type ShippingAddress struct {
@@ -40,6 +46,248 @@ type SMTPSettings struct {
 	Password       string `json:"password"`
 	SenderEmail    string `json:"senderEmail"`
 	RecipientEmail string `json:"recipientEmail"`
+	// TLSMode is "starttls" to require STARTTLS, "tls" for implicit TLS or
+	// "none" to send over a plaintext connection. When empty STARTTLS is
+	// required.
+	TLSMode string `json:"tlsMode,omitempty"`
+	// ImageGateway is the gateway URL the listing thumbnails shown in
+	// emails are loaded from. When empty the Phore gateway is used.
+	ImageGateway string `json:"imageGateway,omitempty"`
+}
+
+// WebhookSettings configures an HTTP endpoint which is posted notifications.
+// Only the notification types in Types are sent, or all of them if it is
+// empty. Payloads are signed with an HMAC-SHA256 of Secret.
+type WebhookSettings struct {
+	Enabled bool     `json:"enabled"`
+	URL     string   `json:"url"`
+	Secret  string   `json:"secret"`
+	Types   []string `json:"types"`
+}
+
+// Wants returns true if the webhook is sent notifications of type t
+func (w WebhookSettings) Wants(t NotificationType) bool {
+	if len(w.Types) == 0 {
+		return true
+	}
+	for _, wanted := range w.Types {
+		if wanted == string(t) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// WebhookDeliveryPending is a delivery which is still being attempted
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliveryDelivered is a delivery the endpoint accepted
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryFailed is a delivery which ran out of attempts
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookDelivery is a notification posted to a webhook and the outcome of
+// the attempts to deliver it
+type WebhookDelivery struct {
+	ID        string          `json:"id"`
+	URL       string          `json:"url"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError"`
+	Timestamp time.Time       `json:"timestamp"`
+	Updated   time.Time       `json:"updated"`
+}
+
+// ModeratorAvailabilityPolicy controls how a vendor's store reacts when one of
+// its moderators publishes an unavailable status. When ReplaceUnavailable is
+// set the moderator is swapped out on every listing for the backup moderator
+// they advertise or, failing that, the first available BackupModerators entry.
+type ModeratorAvailabilityPolicy struct {
+	ReplaceUnavailable bool     `json:"replaceUnavailable"`
+	BackupModerators   []string `json:"backupModerators"`
+}
+
+const (
+	// VacationOrderPolicyReject rejects every order received while the store
+	// is in vacation mode
+	VacationOrderPolicyReject = "reject"
+	// VacationOrderPolicyQueue holds orders which were delivered offline
+	// and processes them once the vacation ends
+	VacationOrderPolicyQueue = "queue"
+)
+
+// VacationMode pauses a vendor's store without removing its listings. The
+// status is mirrored into the published profile and listing index, AutoReply
+// is sent in response to incoming chat messages and OrderPolicy decides what
+// happens to orders that arrive anyway. A vacation with a ReturnDate ends
+// automatically once that date has passed.
+type VacationMode struct {
+	Enabled     bool       `json:"enabled"`
+	ReturnDate  *time.Time `json:"returnDate,omitempty"`
+	AutoReply   string     `json:"autoReply"`
+	OrderPolicy string     `json:"orderPolicy"`
+}
+
+// IsActive returns true if the vacation is enabled and has not yet reached
+// its return date
+func (v *VacationMode) IsActive(now time.Time) bool {
+	if v == nil || !v.Enabled {
+		return false
+	}
+	return v.ReturnDate == nil || now.Before(*v.ReturnDate)
+}
+
+// NotificationChannels are the channels a notification is sent on
+type NotificationChannels struct {
+	Websocket bool `json:"websocket"`
+	SMTP      bool `json:"smtp"`
+	Webhook   bool `json:"webhook"`
+}
+
+// NotificationPreferences chooses the channels each type of notification is
+// sent on. Types without an entry are sent on every channel. Emails which
+// arrive during QuietHours, or whose type is in the Digest, are held and
+// sent together in one digest email.
+type NotificationPreferences struct {
+	Types      map[NotificationType]NotificationChannels `json:"types"`
+	QuietHours *QuietHours                               `json:"quietHours,omitempty"`
+	Digest     *NotificationDigest                       `json:"digest,omitempty"`
+}
+
+// Channels returns the channels notifications of type t are sent on
+func (p *NotificationPreferences) Channels(t NotificationType) NotificationChannels {
+	if p != nil {
+		if channels, ok := p.Types[t]; ok {
+			return channels
+		}
+	}
+	return NotificationChannels{Websocket: true, SMTP: true, Webhook: true}
+}
+
+// IsQuiet returns true if now is within the quiet hours
+func (p *NotificationPreferences) IsQuiet(now time.Time) bool {
+	return p != nil && p.QuietHours.Contains(now)
+}
+
+// IsDigested returns true if emails for notifications of type t are batched
+// into the digest
+func (p *NotificationPreferences) IsDigested(t NotificationType) bool {
+	if p == nil || p.Digest == nil || !p.Digest.Enabled {
+		return false
+	}
+	for _, digested := range p.Digest.DigestTypes() {
+		if digested == t {
+			return true
+		}
+	}
+	return false
+}
+
+// QuietHours is a daily period, in the node's local time, during which no
+// notification emails are sent. Start and End are formatted as "15:04" and
+// the period wraps past midnight if End is before Start.
+type QuietHours struct {
+	Enabled bool   `json:"enabled"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// Contains returns true if the quiet hours are enabled and now is within them
+func (q *QuietHours) Contains(now time.Time) bool {
+	if q == nil || !q.Enabled {
+		return false
+	}
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return false
+	}
+	var (
+		minute      = now.Hour()*60 + now.Minute()
+		startMinute = start.Hour()*60 + start.Minute()
+		endMinute   = end.Hour()*60 + end.Minute()
+	)
+	if startMinute <= endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
+
+// DefaultDigestTypes are the low priority notifications batched into the
+// digest when no types are chosen. orderComplete carries the buyer's rating.
+var DefaultDigestTypes = []NotificationType{
+	NotifierTypeFollowNotification,
+	NotifierTypeUnfollowNotification,
+	NotifierTypeModeratorAddNotification,
+	NotifierTypeModeratorRemoveNotification,
+	NotifierTypeCompletionNotification,
+}
+
+// NotificationDigest batches the emails for low priority notifications into
+// one email sent every IntervalHours
+type NotificationDigest struct {
+	Enabled       bool               `json:"enabled"`
+	Types         []NotificationType `json:"types"`
+	IntervalHours int                `json:"intervalHours"`
+}
+
+// DigestTypes returns the types batched into the digest
+func (d *NotificationDigest) DigestTypes() []NotificationType {
+	if len(d.Types) == 0 {
+		return DefaultDigestTypes
+	}
+	return d.Types
+}
+
+// Interval returns the time between digest emails, which defaults to a day
+func (d *NotificationDigest) Interval() time.Duration {
+	if d == nil || d.IntervalHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(d.IntervalHours) * time.Hour
+}
+
+// PostComment is a signed comment or repost another peer made on one of our
+// posts, or a reply to one of those comments
+type PostComment struct {
+	// ID is the author's peer ID and the slug of the comment, joined by a slash
+	ID string `json:"id"`
+	// PostSlug is the slug of our post the comment belongs to
+	PostSlug string `json:"postSlug"`
+	// Reference is the post or comment being replied to
+	Reference  string    `json:"reference"`
+	AuthorID   string    `json:"authorId"`
+	PostType   string    `json:"postType"`
+	SignedPost []byte    `json:"signedPost"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+const (
+	// FeedEntryTypePost is a feed entry for a post
+	FeedEntryTypePost = "post"
+	// FeedEntryTypeListing is a feed entry for a listing
+	FeedEntryTypeListing = "listing"
+)
+
+// FeedEntry is a post or listing published by a peer we follow
+type FeedEntry struct {
+	// ID is the peer ID, the entry type and the slug, joined by slashes
+	ID     string `json:"id"`
+	PeerID string `json:"peerId"`
+	Type   string `json:"type"`
+	Slug   string `json:"slug"`
+	Hash   string `json:"hash"`
+	// Data is the entry from the peer's posts.json or listings.json
+	Data json.RawMessage `json:"data"`
+	// Timestamp is when the post was published, or when a listing was
+	// first seen
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type Follower struct {
@@ -55,10 +303,84 @@ type Coupon struct {
 
 type GroupChatMessage struct {
 	PeerIds []string `json:"peerIds"`
+	GroupId string   `json:"groupId"`
 	Subject string   `json:"subject"`
 	Message string   `json:"message"`
 }
 
+// ChatGroup is a group conversation. Members is taken from the member list
+// signed by the creator which is kept in Membership so it can be passed on
+// to new members. Active is false once we have left or been removed.
+type ChatGroup struct {
+	GroupId    string    `json:"groupId"`
+	Name       string    `json:"name"`
+	Creator    string    `json:"creator"`
+	Members    []string  `json:"members"`
+	Version    uint32    `json:"version"`
+	Active     bool      `json:"active"`
+	Timestamp  time.Time `json:"timestamp"`
+	Membership []byte    `json:"-"`
+}
+
+// HasMember returns true if the peer is on the group's member list
+func (g *ChatGroup) HasMember(peerID string) bool {
+	for _, m := range g.Members {
+		if m == peerID {
+			return true
+		}
+	}
+	return false
+}
+
+type ChatGroupConversation struct {
+	GroupId   string    `json:"groupId"`
+	Name      string    `json:"name"`
+	Members   []string  `json:"members"`
+	Active    bool      `json:"active"`
+	Unread    int       `json:"unread"`
+	Last      string    `json:"lastMessage"`
+	LastPeer  string    `json:"lastPeerId"`
+	Timestamp time.Time `json:"timestamp"`
+	Outgoing  bool      `json:"outgoing"`
+}
+
+// ChatAttachment is a file attached to a chat message. Data holds the file
+// when uploading and once it has been downloaded from the sender.
+type ChatAttachment struct {
+	Index     int    `json:"index"`
+	Filename  string `json:"filename"`
+	MediaType string `json:"mediaType"`
+	Size      uint64 `json:"size"`
+	Hash      string `json:"hash"`
+	Location  string `json:"-"`
+	Data      []byte `json:"data,omitempty"`
+}
+
+// ChatReaction is a reaction of a peer to a chat message
+type ChatReaction struct {
+	PeerId   string `json:"peerId"`
+	Reaction string `json:"reaction"`
+}
+
+const (
+	// ChatEditActionEdit replaces the text of a chat message
+	ChatEditActionEdit = "edit"
+	// ChatEditActionRetract deletes a chat message for both sides
+	ChatEditActionRetract = "retract"
+)
+
+// ChatEdit is an edit or retraction of a chat message. SignedChat is the
+// signed message which made the change so it can be shown in a dispute.
+type ChatEdit struct {
+	MessageId  string    `json:"messageId"`
+	PeerId     string    `json:"peerId"`
+	Action     string    `json:"action"`
+	Previous   string    `json:"previous"`
+	Message    string    `json:"message"`
+	SignedChat []byte    `json:"signedChat"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
 type ChatConversation struct {
 	PeerId    string    `json:"peerId"`
 	Unread    int       `json:"unread"`
@@ -138,3 +460,65 @@ type UnfundedSale struct {
 	Timestamp   time.Time
 	PaymentCoin string
 }
+
+const (
+	// SigningRequestPending is a signing request waiting for its signatures
+	SigningRequestPending = "pending"
+	// SigningRequestSigned is a signing request holding our signatures
+	SigningRequestSigned = "signed"
+	// SigningRequestBroadcast is a signing request whose transaction was
+	// finished and broadcast by the node
+	SigningRequestBroadcast = "broadcast"
+)
+
+// SigningRequest is a transaction exported as a PSBT to be signed outside
+// the node
+type SigningRequest struct {
+	// ID is the txid of the unsigned transaction
+	ID      string `json:"id"`
+	OrderID string `json:"orderId"`
+	Wallet  string `json:"wallet"`
+	// Action is the operation which needs the signatures, such as a spend
+	// or an order refund
+	Action string `json:"action"`
+	State  string `json:"state"`
+	// PSBT is the serialized packet, holding any signatures collected so far
+	PSBT      []byte    `json:"-"`
+	Address   string    `json:"address"`
+	Memo      string    `json:"memo"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+const (
+	TransactionLabelKindTransaction = "transaction"
+	TransactionLabelKindAddress     = "address"
+)
+
+// TransactionLabel is the user's category and labels for a wallet
+// transaction, or for every transaction paying an address
+type TransactionLabel struct {
+	// Target is the txid or the address labelled
+	Target    string    `json:"target"`
+	Kind      string    `json:"kind"`
+	Category  string    `json:"category"`
+	Labels    []string  `json:"labels"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TransactionFiatValue is the exchange rate of a transaction's coin into the
+// user's local currency when the wallet first saw the transaction
+type TransactionFiatValue struct {
+	Txid     string `json:"txid"`
+	Currency string `json:"currency"`
+	// Rate is the value of one coin in the currency
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ExchangeRate is the value of one coin in another currency at a time
+type ExchangeRate struct {
+	Coin      string    `json:"coin"`
+	Currency  string    `json:"currency"`
+	Rate      float64   `json:"rate"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -232,6 +232,18 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeCaseHandover:
+		var notifier = CaseHandoverNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
+	case NotifierTypeCaseHandoverResponse:
+		var notifier = CaseHandoverResponseNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeCompletionNotification:
 		var notifier = CompletionNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -250,12 +262,24 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeDisputeFallbackPayout:
+		var notifier = DisputeFallbackPayoutNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeDisputeOpenNotification:
 		var notifier = DisputeOpenNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeDisputePanelVote:
+		var notifier = DisputePanelVoteNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeDisputeUpdateNotification:
 		var notifier = DisputeUpdateNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -274,6 +298,12 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeFundingRevoked:
+		var notifier = FundingRevokedNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeModeratorAddNotification:
 		var notifier = ModeratorAddNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -292,6 +322,12 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeModeratorReplacedNotification:
+		var notifier = ModeratorReplacedNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeOrderCancelNotification:
 		var notifier = OrderCancelNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -322,6 +358,12 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypePostComment:
+		var notifier = PostCommentNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeProcessingErrorNotification:
 		var notifier = ProcessingErrorNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -334,6 +376,12 @@ func (n *Notification) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		n.NotifierData = notifier
+	case NotifierTypeSigningRequest:
+		var notifier = SigningRequestNotification{}
+		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
+			return err
+		}
+		n.NotifierData = notifier
 	case NotifierTypeUnfollowNotification:
 		var notifier = UnfollowNotification{}
 		if err := json.Unmarshal(payload.NotifierData, &notifier); err != nil {
@@ -399,6 +447,18 @@ type messageTypingWrapper struct {
 	MessageRead Notifier `json:"messageTyping"`
 }
 
+type messageChangeWrapper struct {
+	MessageChange Notifier `json:"messageChange"`
+}
+
+type chatGroupWrapper struct {
+	ChatGroup Notifier `json:"chatGroup"`
+}
+
+type feedWrapper struct {
+	FeedEntry Notifier `json:"feedEntry"`
+}
+
 type ListingPrice struct {
 	Amount           uint64  `json:"amount"`
 	CurrencyCode     string  `json:"currencyCode"`
@@ -794,6 +854,30 @@ func (n ModeratorRemoveNotification) GetSMTPTitleAndBody() (string, string, bool
 	return "", "", false
 }
 
+// ModeratorReplacedNotification is sent to a vendor when the moderator
+// availability policy swaps an unavailable moderator out of their listings
+type ModeratorReplacedNotification struct {
+	ID          string           `json:"notificationId"`
+	Type        NotificationType `json:"type"`
+	ModeratorID string           `json:"moderatorId"`
+	ReplacedBy  string           `json:"replacedBy"`
+}
+
+func (n ModeratorReplacedNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n ModeratorReplacedNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n ModeratorReplacedNotification) GetID() string { return n.ID }
+func (n ModeratorReplacedNotification) GetType() NotificationType {
+	return NotifierTypeModeratorReplacedNotification
+}
+func (n ModeratorReplacedNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	form := "Moderator %s is unavailable and was replaced on your listings by %s."
+	return "Moderator replaced", fmt.Sprintf(form, n.ModeratorID, n.ReplacedBy), true
+}
+
 type StatusNotification struct {
 	Status string `json:"status"`
 }
@@ -805,13 +889,17 @@ func (n StatusNotification) GetType() NotificationType                   { retur
 func (n StatusNotification) GetSMTPTitleAndBody() (string, string, bool) { return "", "", false }
 
 type ChatMessage struct {
-	MessageId string    `json:"messageId"`
-	PeerId    string    `json:"peerId"`
-	Subject   string    `json:"subject"`
-	Message   string    `json:"message"`
-	Read      bool      `json:"read"`
-	Outgoing  bool      `json:"outgoing"`
-	Timestamp time.Time `json:"timestamp"`
+	MessageId   string           `json:"messageId"`
+	PeerId      string           `json:"peerId"`
+	GroupId     string           `json:"groupId,omitempty"`
+	Subject     string           `json:"subject"`
+	Message     string           `json:"message"`
+	Read        bool             `json:"read"`
+	Outgoing    bool             `json:"outgoing"`
+	Timestamp   time.Time        `json:"timestamp"`
+	Attachments []ChatAttachment `json:"attachments,omitempty"`
+	Reactions   []ChatReaction   `json:"reactions,omitempty"`
+	Edited      bool             `json:"edited,omitempty"`
 }
 
 func (n ChatMessage) Data() ([]byte, error)                       { return json.MarshalIndent(messageWrapper{n}, "", "    ") }
@@ -835,6 +923,7 @@ func (n ChatRead) GetSMTPTitleAndBody() (string, string, bool) { return "", "",
 type ChatTyping struct {
 	MessageId string `json:"messageId"`
 	PeerId    string `json:"peerId"`
+	GroupId   string `json:"groupId,omitempty"`
 	Subject   string `json:"subject"`
 }
 
@@ -846,6 +935,58 @@ func (n ChatTyping) GetID() string                               { return "" } /
 func (n ChatTyping) GetType() NotificationType                   { return NotifierTypeChatTyping }
 func (n ChatTyping) GetSMTPTitleAndBody() (string, string, bool) { return "", "", false }
 
+// ChatChange is pushed when a chat message is edited, retracted or reacted
+// to. Action is one of edit, retract, react or unreact.
+type ChatChange struct {
+	MessageId string    `json:"messageId"`
+	PeerId    string    `json:"peerId"`
+	GroupId   string    `json:"groupId,omitempty"`
+	Subject   string    `json:"subject"`
+	Action    string    `json:"action"`
+	Message   string    `json:"message,omitempty"`
+	Reaction  string    `json:"reaction,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func (n ChatChange) Data() ([]byte, error) {
+	return json.MarshalIndent(messageChangeWrapper{n}, "", "    ")
+}
+func (n ChatChange) WebsocketData() ([]byte, error)              { return n.Data() }
+func (n ChatChange) GetID() string                               { return "" } // Not persisted, ID is ignored
+func (n ChatChange) GetType() NotificationType                   { return NotifierTypeChatChange }
+func (n ChatChange) GetSMTPTitleAndBody() (string, string, bool) { return "", "", false }
+
+// ChatGroupUpdate is pushed when a group conversation is created or its
+// members change. PeerId made the change and PeerIds were added or removed.
+type ChatGroupUpdate struct {
+	Group   ChatGroup `json:"group"`
+	Action  string    `json:"action"`
+	PeerId  string    `json:"peerId"`
+	PeerIds []string  `json:"peerIds"`
+}
+
+func (n ChatGroupUpdate) Data() ([]byte, error) {
+	return json.MarshalIndent(chatGroupWrapper{n}, "", "    ")
+}
+func (n ChatGroupUpdate) WebsocketData() ([]byte, error)              { return n.Data() }
+func (n ChatGroupUpdate) GetID() string                               { return "" } // Not persisted, ID is ignored
+func (n ChatGroupUpdate) GetType() NotificationType                   { return NotifierTypeChatGroupUpdate }
+func (n ChatGroupUpdate) GetSMTPTitleAndBody() (string, string, bool) { return "", "", false }
+
+// FeedEntryNotification is pushed when a peer we follow publishes a new post
+// or listing
+type FeedEntryNotification struct {
+	FeedEntry
+}
+
+func (n FeedEntryNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(feedWrapper{n}, "", "    ")
+}
+func (n FeedEntryNotification) WebsocketData() ([]byte, error)              { return n.Data() }
+func (n FeedEntryNotification) GetID() string                               { return "" } // Not persisted, ID is ignored
+func (n FeedEntryNotification) GetType() NotificationType                   { return NotifierTypeFeedEntry }
+func (n FeedEntryNotification) GetSMTPTitleAndBody() (string, string, bool) { return "", "", false }
+
 type IncomingTransaction struct {
 	Wallet        string    `json:"wallet"`
 	Txid          string    `json:"txid"`
@@ -1014,3 +1155,175 @@ func NewNotificationID() string {
 	nId, _ := mh.Cast(encoded)
 	return nId.B58String()
 }
+
+// CaseHandoverNotification represents a moderator handing a dispute over to a
+// backup moderator. Buyers and vendors receive it when their acceptance is
+// requested and the backup moderator receives it once both parties accepted.
+type CaseHandoverNotification struct {
+	ID              string           `json:"notificationId"`
+	Type            NotificationType `json:"type"`
+	OrderID         string           `json:"orderId"`
+	ModeratorID     string           `json:"moderatorId"`
+	BackupModerator string           `json:"backupModerator"`
+}
+
+func (n CaseHandoverNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n CaseHandoverNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n CaseHandoverNotification) GetID() string             { return n.ID }
+func (n CaseHandoverNotification) GetType() NotificationType { return NotifierTypeCaseHandover }
+func (n CaseHandoverNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	form := "The moderator of order \"%s\" would like to hand the dispute over to %s."
+	return "Dispute handover", fmt.Sprintf(form, n.OrderID, n.BackupModerator), true
+}
+
+// CaseHandoverResponseNotification is sent to the original moderator when
+// the buyer or vendor accepts or rejects a dispute handover
+type CaseHandoverResponseNotification struct {
+	ID       string           `json:"notificationId"`
+	Type     NotificationType `json:"type"`
+	OrderID  string           `json:"orderId"`
+	PeerID   string           `json:"peerId"`
+	Accepted bool             `json:"accepted"`
+}
+
+func (n CaseHandoverResponseNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n CaseHandoverResponseNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n CaseHandoverResponseNotification) GetID() string { return n.ID }
+func (n CaseHandoverResponseNotification) GetType() NotificationType {
+	return NotifierTypeCaseHandoverResponse
+}
+func (n CaseHandoverResponseNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	return "", "", false
+}
+
+// DisputeFallbackPayoutNotification is sent to the buyer and vendor once both
+// have signed a dispute fallback payout and again when it is broadcast
+type DisputeFallbackPayoutNotification struct {
+	ID         string           `json:"notificationId"`
+	Type       NotificationType `json:"type"`
+	OrderID    string           `json:"orderId"`
+	Policy     string           `json:"policy"`
+	ProposedBy string           `json:"proposedBy"`
+	Completed  bool             `json:"completed"`
+}
+
+func (n DisputeFallbackPayoutNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n DisputeFallbackPayoutNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n DisputeFallbackPayoutNotification) GetID() string { return n.ID }
+func (n DisputeFallbackPayoutNotification) GetType() NotificationType {
+	return NotifierTypeDisputeFallbackPayout
+}
+func (n DisputeFallbackPayoutNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	return "", "", false
+}
+
+// DisputePanelVoteNotification is sent to a panel moderator when another
+// member proposes a resolution or endorses the moderator's own proposal
+type DisputePanelVoteNotification struct {
+	ID          string           `json:"notificationId"`
+	Type        NotificationType `json:"type"`
+	OrderID     string           `json:"orderId"`
+	ModeratorID string           `json:"moderatorId"`
+	Endorsed    bool             `json:"endorsed"`
+}
+
+func (n DisputePanelVoteNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n DisputePanelVoteNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n DisputePanelVoteNotification) GetID() string { return n.ID }
+func (n DisputePanelVoteNotification) GetType() NotificationType {
+	return NotifierTypeDisputePanelVote
+}
+func (n DisputePanelVoteNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	return "", "", false
+}
+
+// FundingRevokedNotification is sent to the buyer and vendor when the
+// payment funding an order was reorged out of the chain or double spent
+type FundingRevokedNotification struct {
+	ID        string           `json:"notificationId"`
+	Type      NotificationType `json:"type"`
+	OrderID   string           `json:"orderId"`
+	Txids     []string         `json:"txids"`
+	Thumbnail Thumbnail        `json:"thumbnail"`
+}
+
+func (n FundingRevokedNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n FundingRevokedNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n FundingRevokedNotification) GetID() string             { return n.ID }
+func (n FundingRevokedNotification) GetType() NotificationType { return NotifierTypeFundingRevoked }
+func (n FundingRevokedNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	form := "The payment for order \"%s\" was reversed on the blockchain. Do not ship the order until it is paid again."
+	return "Order payment reversed", fmt.Sprintf(form, n.OrderID), true
+}
+
+// PostCommentNotification is sent when another peer comments on or reposts
+// one of our posts
+type PostCommentNotification struct {
+	ID        string           `json:"notificationId"`
+	Type      NotificationType `json:"type"`
+	PostSlug  string           `json:"postSlug"`
+	CommentID string           `json:"commentId"`
+	Reference string           `json:"reference"`
+	PeerID    string           `json:"peerId"`
+	PostType  string           `json:"postType"`
+	Status    string           `json:"status"`
+}
+
+func (n PostCommentNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n PostCommentNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n PostCommentNotification) GetID() string { return n.ID }
+func (n PostCommentNotification) GetType() NotificationType {
+	return NotifierTypePostComment
+}
+func (n PostCommentNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	return "", "", false
+}
+
+// SigningRequestNotification is sent when a transaction has been exported
+// for signing outside of the node or an imported signature changed its state
+type SigningRequestNotification struct {
+	ID        string           `json:"notificationId"`
+	Type      NotificationType `json:"type"`
+	RequestID string           `json:"requestId"`
+	OrderID   string           `json:"orderId"`
+	Action    string           `json:"action"`
+	State     string           `json:"state"`
+}
+
+func (n SigningRequestNotification) Data() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n SigningRequestNotification) WebsocketData() ([]byte, error) {
+	return json.MarshalIndent(notificationWrapper{n}, "", "    ")
+}
+func (n SigningRequestNotification) GetID() string { return n.ID }
+func (n SigningRequestNotification) GetType() NotificationType {
+	return NotifierTypeSigningRequest
+}
+func (n SigningRequestNotification) GetSMTPTitleAndBody() (string, string, bool) {
+	return "", "", false
+}
@@ -0,0 +1,72 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2018, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	var (
+		night    = &repo.QuietHours{Enabled: true, Start: "22:00", End: "07:30"}
+		lunch    = &repo.QuietHours{Enabled: true, Start: "12:00", End: "13:00"}
+		examples = []struct {
+			quiet    *repo.QuietHours
+			now      time.Time
+			expected bool
+		}{
+			{nil, at(23, 0), false},
+			{&repo.QuietHours{Start: "22:00", End: "07:30"}, at(23, 0), false},
+			{night, at(23, 0), true},
+			{night, at(3, 0), true},
+			{night, at(7, 29), true},
+			{night, at(7, 30), false},
+			{night, at(21, 59), false},
+			{lunch, at(12, 30), true},
+			{lunch, at(13, 0), false},
+			{&repo.QuietHours{Enabled: true, Start: "late", End: "07:30"}, at(3, 0), false},
+		}
+	)
+	for i, e := range examples {
+		if actual := e.quiet.Contains(e.now); actual != e.expected {
+			t.Errorf("example %d: expected %t, got %t", i, e.expected, actual)
+		}
+	}
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	var prefs *repo.NotificationPreferences
+	if channels := prefs.Channels(repo.NotifierTypeOrderNewNotification); !channels.Websocket || !channels.SMTP || !channels.Webhook {
+		t.Error("expected every channel without preferences")
+	}
+	if prefs.IsDigested(repo.NotifierTypeFollowNotification) {
+		t.Error("expected no digest without preferences")
+	}
+
+	prefs = &repo.NotificationPreferences{
+		Types: map[repo.NotificationType]repo.NotificationChannels{
+			repo.NotifierTypeFollowNotification: {Websocket: true},
+		},
+		Digest: &repo.NotificationDigest{Enabled: true},
+	}
+	if channels := prefs.Channels(repo.NotifierTypeFollowNotification); !channels.Websocket || channels.SMTP || channels.Webhook {
+		t.Errorf("unexpected channels: %+v", channels)
+	}
+	if channels := prefs.Channels(repo.NotifierTypeOrderNewNotification); !channels.SMTP {
+		t.Error("expected types without an entry to use every channel")
+	}
+	if !prefs.IsDigested(repo.NotifierTypeFollowNotification) || prefs.IsDigested(repo.NotifierTypeOrderNewNotification) {
+		t.Error("expected the default digest types")
+	}
+	if prefs.Digest.Interval() != 24*time.Hour {
+		t.Error("expected a daily digest by default")
+	}
+	prefs.Digest.Types = []repo.NotificationType{repo.NotifierTypeOrderNewNotification}
+	if prefs.IsDigested(repo.NotifierTypeFollowNotification) || !prefs.IsDigested(repo.NotifierTypeOrderNewNotification) {
+		t.Error("expected the chosen digest types")
+	}
+}
@@ -143,6 +143,66 @@ func createNotificationExamples() []repo.Notifier {
 			Type:    repo.NotifierTypeVendorFinalizedPayment,
 			OrderID: repo.NewNotificationID(),
 		},
+		repo.CaseHandoverNotification{
+			ID:              "caseHandoverID",
+			Type:            repo.NotifierTypeCaseHandover,
+			OrderID:         repo.NewNotificationID(),
+			ModeratorID:     "QmModerator",
+			BackupModerator: "QmBackupModerator",
+		},
+		repo.CaseHandoverResponseNotification{
+			ID:       "caseHandoverResponseID",
+			Type:     repo.NotifierTypeCaseHandoverResponse,
+			OrderID:  repo.NewNotificationID(),
+			PeerID:   "QmBuyer",
+			Accepted: true,
+		},
+		repo.DisputeFallbackPayoutNotification{
+			ID:         "disputeFallbackPayoutID",
+			Type:       repo.NotifierTypeDisputeFallbackPayout,
+			OrderID:    repo.NewNotificationID(),
+			Policy:     "SPLIT",
+			ProposedBy: "QmVendor",
+			Completed:  true,
+		},
+		repo.DisputePanelVoteNotification{
+			ID:          "disputePanelVoteID",
+			Type:        repo.NotifierTypeDisputePanelVote,
+			OrderID:     repo.NewNotificationID(),
+			ModeratorID: "QmModerator",
+			Endorsed:    true,
+		},
+		repo.FundingRevokedNotification{
+			ID:        "fundingRevokedID",
+			Type:      repo.NotifierTypeFundingRevoked,
+			OrderID:   repo.NewNotificationID(),
+			Txids:     []string{"txid"},
+			Thumbnail: repo.Thumbnail{Tiny: "tinyimagehash", Small: "smallimagehash"},
+		},
+		repo.PostCommentNotification{
+			ID:        "postCommentID",
+			Type:      repo.NotifierTypePostComment,
+			PostSlug:  "post",
+			CommentID: "QmCommenter/comment",
+			Reference: "QmSelf/post",
+			PeerID:    "QmCommenter",
+			PostType:  "COMMENT",
+			Status:    "Nice post",
+		},
+		repo.SigningRequestNotification{
+			ID:        "signingRequestID",
+			Type:      repo.NotifierTypeSigningRequest,
+			RequestID: "txid",
+			OrderID:   repo.NewNotificationID(),
+			Action:    "refund",
+			State:     repo.SigningRequestPending,
+		},
+		repo.ModeratorReplacedNotification{
+			ID:          "moderatorReplacedID",
+			Type:        repo.NotifierTypeModeratorReplacedNotification,
+			ModeratorID: "QmModerator",
+			ReplacedBy:  "QmBackupModerator",
+		},
 	},
 		createLegacyNotificationExamples()...)
 }
@@ -0,0 +1,32 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+func TestVacationModeIsActive(t *testing.T) {
+	var (
+		now      = time.Now()
+		future   = now.Add(24 * time.Hour)
+		past     = now.Add(-24 * time.Hour)
+		examples = []struct {
+			vacation *repo.VacationMode
+			expected bool
+		}{
+			{nil, false},
+			{&repo.VacationMode{Enabled: false}, false},
+			{&repo.VacationMode{Enabled: false, ReturnDate: &future}, false},
+			{&repo.VacationMode{Enabled: true}, true},
+			{&repo.VacationMode{Enabled: true, ReturnDate: &future}, true},
+			{&repo.VacationMode{Enabled: true, ReturnDate: &past}, false},
+		}
+	)
+	for i, e := range examples {
+		if actual := e.vacation.IsActive(now); actual != e.expected {
+			t.Errorf("example %d: expected %t, got %t", i, e.expected, actual)
+		}
+	}
+}
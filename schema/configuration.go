@@ -3,6 +3,7 @@ package schema
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -36,6 +37,10 @@ type WalletsConfig struct {
 	LTC *CoinConfig `json:"LTC"`
 	ZEC *CoinConfig `json:"ZEC"`
 	ETH *CoinConfig `json:"ETH"`
+
+	Tokens []*TokenConfig `json:"Tokens,omitempty"`
+
+	Channel *PaymentChannelConfig `json:"Channel,omitempty"`
 }
 
 type CoinConfig struct {
@@ -51,6 +56,101 @@ type CoinConfig struct {
 	WalletOptions    map[string]interface{} `json:"WalletOptions"`
 }
 
+// TokenConfig configures an ERC-20 style token wallet. Contract is the token
+// contract's address and Escrow that of a deployment of the wallet's escrow
+// contract, as made by the deployescrow command. Decimals is the token's
+// on-chain divisibility and Divisibility the precision the node prices it
+// in. API and APITestnet are Ethereum JSON-RPC endpoints and MaxGasPrice is
+// in gwei.
+type TokenConfig struct {
+	Code           string   `json:"Code"`
+	Name           string   `json:"Name"`
+	Contract       string   `json:"Contract"`
+	Escrow         string   `json:"Escrow"`
+	Decimals       uint     `json:"Decimals"`
+	Divisibility   uint     `json:"Divisibility"`
+	ChainID        int64    `json:"ChainID"`
+	APIPool        []string `json:"API"`
+	APITestnetPool []string `json:"APITestnet"`
+	StartBlock     uint64   `json:"StartBlock"`
+	MaxGasPrice    uint64   `json:"MaxGasPrice"`
+	Confirmations  uint32   `json:"Confirmations"`
+}
+
+// WatchOnlyKeys are the public keys a node with external signing runs from
+// instead of its seed, as printed by the publickeys command. MasterKey and
+// the AccountKeys, by coin type, are extended public keys and BitcoinSig is
+// the master key's hex encoded signature of the node's peer ID.
+type WatchOnlyKeys struct {
+	MasterKey   string            `json:"MasterKey"`
+	AccountKeys map[string]string `json:"AccountKeys"`
+	BitcoinSig  string            `json:"BitcoinSig"`
+}
+
+// PaymentChannelConfig is the local payment channel daemon orders in Coin
+// can be paid through. Endpoint is the daemon's REST gateway, TLSCert and
+// Macaroon the paths of its certificate and macaroon and InvoiceExpiry how
+// long an order's invoice can be paid, as a duration string.
+type PaymentChannelConfig struct {
+	Coin          string `json:"Coin"`
+	Endpoint      string `json:"Endpoint"`
+	TLSCert       string `json:"TLSCert,omitempty"`
+	Macaroon      string `json:"Macaroon,omitempty"`
+	InvoiceExpiry string `json:"InvoiceExpiry,omitempty"`
+}
+
+// ExchangeRatesConfig lists the exchange rate feeds orders are priced with.
+// MaxDeviation is the fraction of the median a feed may be off by and
+// MaxAge how old its rates may be, as a duration string.
+type ExchangeRatesConfig struct {
+	Providers    []ExchangeRateProvider `json:"Providers"`
+	MaxDeviation float64                `json:"MaxDeviation"`
+	MaxAge       string                 `json:"MaxAge"`
+	MinSources   int                    `json:"MinSources"`
+}
+
+// ExchangeRateProvider is a feed of type "wallet", "file" or "http"
+type ExchangeRateProvider struct {
+	Type string `json:"Type"`
+	Path string `json:"Path,omitempty"`
+	URL  string `json:"URL,omitempty"`
+}
+
+// FundingConfirmationsConfig is how many confirmations the payment of an
+// order needs before the order is funded. Coins holds the thresholds of a
+// coin by its currency code and Default those of every other coin.
+type FundingConfirmationsConfig struct {
+	Default []ConfirmationThreshold            `json:"Default"`
+	Coins   map[string][]ConfirmationThreshold `json:"Coins,omitempty"`
+}
+
+// ConfirmationThreshold requires Confirmations of the payment of orders
+// worth at least MinValue, in the coin's smallest unit
+type ConfirmationThreshold struct {
+	MinValue      uint64 `json:"MinValue"`
+	Confirmations uint32 `json:"Confirmations"`
+}
+
+// Required returns the confirmations the payment of an order of value in
+// coin needs. On testnet, coins use the thresholds of their mainnet coin.
+func (c *FundingConfirmationsConfig) Required(coin string, testnet bool, value uint64) uint32 {
+	coin = strings.ToUpper(coin)
+	thresholds, ok := c.Coins[coin]
+	if !ok && testnet && strings.HasPrefix(coin, "T") {
+		thresholds, ok = c.Coins[strings.TrimPrefix(coin, "T")]
+	}
+	if !ok {
+		thresholds = c.Default
+	}
+	var confirmations uint32
+	for _, t := range thresholds {
+		if value >= t.MinValue && t.Confirmations > confirmations {
+			confirmations = t.Confirmations
+		}
+	}
+	return confirmations
+}
+
 type DataSharing struct {
 	AcceptStoreRequests bool
 	PushTo              []string
@@ -58,6 +158,14 @@ type DataSharing struct {
 
 var MalformedConfigError = errors.New("config file is malformed")
 
+// DefaultFundingConfirmationsConfig requires one confirmation of the
+// payment of any order
+func DefaultFundingConfirmationsConfig() *FundingConfirmationsConfig {
+	return &FundingConfirmationsConfig{
+		Default: []ConfirmationThreshold{{MinValue: 0, Confirmations: 1}},
+	}
+}
+
 func DefaultWalletsConfig() *WalletsConfig {
 	var feeAPI = "https://btc.fees.openbazaar.org"
 	return &WalletsConfig{
@@ -247,6 +355,73 @@ func GetWalletsConfig(cfgBytes []byte) (*WalletsConfig, error) {
 	return wCfg, nil
 }
 
+// GetExchangeRatesConfig returns the configured exchange rate feeds, or nil
+// when the wallets' own exchange rate providers are used
+func GetExchangeRatesConfig(cfgBytes []byte) (*ExchangeRatesConfig, error) {
+	var cfgIface map[string]interface{}
+	err := json.Unmarshal(cfgBytes, &cfgIface)
+	if err != nil {
+		return nil, MalformedConfigError
+	}
+
+	ratesIface, ok := cfgIface["ExchangeRates"]
+	if !ok || ratesIface == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(ratesIface)
+	if err != nil {
+		return nil, err
+	}
+	rCfg := new(ExchangeRatesConfig)
+	if err := json.Unmarshal(b, rCfg); err != nil {
+		return nil, MalformedConfigError
+	}
+	if rCfg.MaxDeviation < 0 || rCfg.MinSources < 0 {
+		return nil, MalformedConfigError
+	}
+	if rCfg.MaxAge != "" {
+		if _, err := time.ParseDuration(rCfg.MaxAge); err != nil {
+			return nil, MalformedConfigError
+		}
+	}
+	for _, p := range rCfg.Providers {
+		switch {
+		case p.Type == "wallet":
+		case p.Type == "file" && p.Path != "":
+		case p.Type == "http" && p.URL != "":
+		default:
+			return nil, MalformedConfigError
+		}
+	}
+	return rCfg, nil
+}
+
+// GetFundingConfirmationsConfig returns the confirmation thresholds of
+// order payments, or the default ones when none are configured
+func GetFundingConfirmationsConfig(cfgBytes []byte) (*FundingConfirmationsConfig, error) {
+	var cfgIface map[string]interface{}
+	err := json.Unmarshal(cfgBytes, &cfgIface)
+	if err != nil {
+		return nil, MalformedConfigError
+	}
+
+	confIface, ok := cfgIface["FundingConfirmations"]
+	if !ok || confIface == nil {
+		return DefaultFundingConfirmationsConfig(), nil
+	}
+
+	b, err := json.Marshal(confIface)
+	if err != nil {
+		return nil, err
+	}
+	fCfg := new(FundingConfirmationsConfig)
+	if err := json.Unmarshal(b, fCfg); err != nil {
+		return nil, MalformedConfigError
+	}
+	return fCfg, nil
+}
+
 func GetTorConfig(cfgBytes []byte) (*TorConfig, error) {
 	var cfgIface interface{}
 	err := json.Unmarshal(cfgBytes, &cfgIface)
@@ -383,6 +558,86 @@ func GetRepublishInterval(cfgBytes []byte) (time.Duration, error) {
 	return d, nil
 }
 
+// GetExternalSigning returns whether the node runs a watch-only wallet and
+// exports every transaction for signing elsewhere. Configs written before the
+// option existed do not carry the key and default to signing in the node.
+func GetExternalSigning(cfgBytes []byte) (bool, error) {
+	var cfgIface interface{}
+	err := json.Unmarshal(cfgBytes, &cfgIface)
+	if err != nil {
+		return false, MalformedConfigError
+	}
+
+	cfg, ok := cfgIface.(map[string]interface{})
+	if !ok {
+		return false, MalformedConfigError
+	}
+
+	external, ok := cfg["ExternalSigning"]
+	if !ok {
+		return false, nil
+	}
+	externalBool, ok := external.(bool)
+	if !ok {
+		return false, MalformedConfigError
+	}
+	return externalBool, nil
+}
+
+// GetWatchOnlyKeys returns the public keys a node with external signing runs
+// from, or nil when the config has none and the node loads its seed
+func GetWatchOnlyKeys(cfgBytes []byte) (*WatchOnlyKeys, error) {
+	var cfgIface map[string]interface{}
+	err := json.Unmarshal(cfgBytes, &cfgIface)
+	if err != nil {
+		return nil, MalformedConfigError
+	}
+
+	keysIface, ok := cfgIface["WatchOnlyKeys"]
+	if !ok || keysIface == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(keysIface)
+	if err != nil {
+		return nil, err
+	}
+	keys := new(WatchOnlyKeys)
+	if err := json.Unmarshal(b, keys); err != nil {
+		return nil, MalformedConfigError
+	}
+	if keys.MasterKey == "" || keys.BitcoinSig == "" {
+		return nil, MalformedConfigError
+	}
+	return keys, nil
+}
+
+// GetSignerSocket returns the Unix socket of the signing daemon which holds
+// the node's seed and identity key. It is empty, and the keys are held by
+// the node, unless the config sets one.
+func GetSignerSocket(cfgBytes []byte) (string, error) {
+	var cfgIface interface{}
+	err := json.Unmarshal(cfgBytes, &cfgIface)
+	if err != nil {
+		return "", MalformedConfigError
+	}
+
+	cfg, ok := cfgIface.(map[string]interface{})
+	if !ok {
+		return "", MalformedConfigError
+	}
+
+	socket, ok := cfg["SignerSocket"]
+	if !ok || socket == nil {
+		return "", nil
+	}
+	socketStr, ok := socket.(string)
+	if !ok {
+		return "", MalformedConfigError
+	}
+	return socketStr, nil
+}
+
 func GetDataSharing(cfgBytes []byte) (*DataSharing, error) {
 	var cfgIface interface{}
 	err := json.Unmarshal(cfgBytes, &cfgIface)
@@ -145,6 +145,27 @@ func TestGetWalletsConfig(t *testing.T) {
 		t.Error("Expected maxFee to be 200, got ", config.LTC.MaxFee)
 	}
 
+	if len(config.Tokens) != 1 {
+		t.Fatal("Expected one token, got ", len(config.Tokens))
+	}
+	token := config.Tokens[0]
+	if token.Code != "DAI" || token.Contract != "0x6b175474e89094c44da98b954eedeac495271d0f" {
+		t.Error("Token does not equal expected value")
+	}
+	if token.Decimals != 18 || token.Divisibility != 8 {
+		t.Error("Expected token divisibility to be 18/8, got ", token.Decimals, token.Divisibility)
+	}
+	if len(token.APIPool) == 0 || token.APIPool[0] != "http://localhost:8545" || token.ChainID != 1 || token.StartBlock != 100 {
+		t.Error("Token chain settings do not equal expected value")
+	}
+
+	if config.Channel == nil {
+		t.Fatal("Expected a payment channel config")
+	}
+	if config.Channel.Coin != "BTC" || config.Channel.Endpoint != "https://localhost:8080" || config.Channel.InvoiceExpiry != "30m" {
+		t.Error("Payment channel config does not equal expected value")
+	}
+
 	_, err = GetWalletsConfig([]byte{})
 	if err == nil {
 		t.Error("GetWalletsConfig didn't throw an error")
@@ -200,6 +221,128 @@ func TestRepublishInterval(t *testing.T) {
 	}
 }
 
+func TestGetExternalSigning(t *testing.T) {
+	external, err := GetExternalSigning(configFixture())
+	if err != nil {
+		t.Error("GetExternalSigning threw an unexpected error")
+	}
+	if external {
+		t.Error("Expected external signing to default to false")
+	}
+
+	external, err = GetExternalSigning([]byte(`{"ExternalSigning": true}`))
+	if err != nil || !external {
+		t.Error("Expected external signing to be enabled")
+	}
+
+	if _, err = GetExternalSigning([]byte(`{"ExternalSigning": "yes"}`)); err == nil {
+		t.Error("GetExternalSigning didn't throw an error")
+	}
+}
+
+func TestGetWatchOnlyKeys(t *testing.T) {
+	keys, err := GetWatchOnlyKeys(configFixture())
+	if err != nil || keys != nil {
+		t.Error("Expected no watch-only keys by default")
+	}
+
+	keys, err = GetWatchOnlyKeys([]byte(`{"WatchOnlyKeys": {"MasterKey": "xpub", "AccountKeys": {"444": "xpub444"}, "BitcoinSig": "3044"}}`))
+	if err != nil {
+		t.Error("GetWatchOnlyKeys threw an unexpected error")
+	}
+	if keys == nil || keys.MasterKey != "xpub" || keys.AccountKeys["444"] != "xpub444" || keys.BitcoinSig != "3044" {
+		t.Errorf("Unexpected watch-only keys %+v", keys)
+	}
+
+	if _, err = GetWatchOnlyKeys([]byte(`{"WatchOnlyKeys": {"AccountKeys": {}}}`)); err == nil {
+		t.Error("GetWatchOnlyKeys didn't throw an error")
+	}
+}
+
+func TestGetSignerSocket(t *testing.T) {
+	socket, err := GetSignerSocket(configFixture())
+	if err != nil {
+		t.Error("GetSignerSocket threw an unexpected error")
+	}
+	if socket != "" {
+		t.Error("Expected the keys to be held by the node by default")
+	}
+
+	socket, err = GetSignerSocket([]byte(`{"SignerSocket": "/run/ob-signer.sock"}`))
+	if err != nil || socket != "/run/ob-signer.sock" {
+		t.Error("Expected the signer socket to be read from the config")
+	}
+
+	if _, err = GetSignerSocket([]byte(`{"SignerSocket": 1}`)); err == nil {
+		t.Error("GetSignerSocket didn't throw an error")
+	}
+}
+
+func TestGetExchangeRatesConfig(t *testing.T) {
+	cfg, err := GetExchangeRatesConfig(configFixture())
+	if err != nil {
+		t.Error("GetExchangeRatesConfig threw an unexpected error")
+	}
+	if cfg != nil {
+		t.Error("Expected the wallets' exchange rates to be used by default")
+	}
+
+	cfg, err = GetExchangeRatesConfig([]byte(`{"ExchangeRates": {"Providers": [{"Type": "wallet"}, {"Type": "http", "URL": "https://example.com/rates"}], "MaxDeviation": 0.02, "MaxAge": "30m", "MinSources": 2}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Providers) != 2 || cfg.Providers[1].URL != "https://example.com/rates" || cfg.MaxDeviation != 0.02 || cfg.MaxAge != "30m" || cfg.MinSources != 2 {
+		t.Errorf("Unexpected exchange rates config %+v", cfg)
+	}
+
+	for _, bad := range []string{
+		`{"ExchangeRates": {"Providers": [{"Type": "ftp"}]}}`,
+		`{"ExchangeRates": {"Providers": [{"Type": "file"}]}}`,
+		`{"ExchangeRates": {"MaxAge": "soon"}}`,
+		`{"ExchangeRates": {"MaxDeviation": "high"}}`,
+	} {
+		if _, err := GetExchangeRatesConfig([]byte(bad)); err == nil {
+			t.Errorf("GetExchangeRatesConfig didn't throw an error for %s", bad)
+		}
+	}
+}
+
+func TestGetFundingConfirmationsConfig(t *testing.T) {
+	cfg, err := GetFundingConfirmationsConfig(configFixture())
+	if err != nil {
+		t.Error("GetFundingConfirmationsConfig threw an unexpected error")
+	}
+	if cfg.Required("BTC", false, 1) != 1 {
+		t.Error("Expected one confirmation to be required by default")
+	}
+
+	cfg, err = GetFundingConfirmationsConfig([]byte(`{"FundingConfirmations": {"Default": [{"MinValue": 0, "Confirmations": 2}], "Coins": {"BTC": [{"MinValue": 0, "Confirmations": 1}, {"MinValue": 100000000, "Confirmations": 6}], "PHR": [{"MinValue": 0, "Confirmations": 0}]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		coin          string
+		testnet       bool
+		value         uint64
+		confirmations uint32
+	}{
+		{"BTC", false, 1000, 1},
+		{"btc", false, 100000000, 6},
+		{"TBTC", true, 200000000, 6},
+		{"TBTC", false, 200000000, 2},
+		{"PHR", false, 100000000000, 0},
+		{"LTC", false, 1000, 2},
+	} {
+		if c := cfg.Required(test.coin, test.testnet, test.value); c != test.confirmations {
+			t.Errorf("Expected %d confirmations for %d %s, got %d", test.confirmations, test.value, test.coin, c)
+		}
+	}
+
+	if _, err := GetFundingConfirmationsConfig([]byte(`{"FundingConfirmations": {"Default": [{"Confirmations": -1}]}}`)); err == nil {
+		t.Error("GetFundingConfirmationsConfig didn't throw an error")
+	}
+}
+
 func configFixture() []byte {
 	return []byte(`{
   "API": {
@@ -429,6 +572,33 @@ func configFixture() []byte {
         "RinkebyRegistryAddress": "0x403d907982474cdd51687b09a8968346159378f3",
         "RopstenRegistryAddress": "0x403d907982474cdd51687b09a8968346159378f3"
       }
+    },
+    "Tokens": [
+      {
+        "Code": "DAI",
+        "Name": "Dai",
+        "Contract": "0x6b175474e89094c44da98b954eedeac495271d0f",
+        "Escrow": "0x2000000000000000000000000000000000000002",
+        "Decimals": 18,
+        "Divisibility": 8,
+        "ChainID": 1,
+        "API": [
+          "http://localhost:8545"
+        ],
+        "APITestnet": [
+          "http://localhost:8545"
+        ],
+        "StartBlock": 100,
+        "MaxGasPrice": 200,
+        "Confirmations": 12
+      }
+    ],
+    "Channel": {
+      "Coin": "BTC",
+      "Endpoint": "https://localhost:8080",
+      "TLSCert": "/home/user/.lnd/tls.cert",
+      "Macaroon": "/home/user/.lnd/data/chain/bitcoin/mainnet/admin.macaroon",
+      "InvoiceExpiry": "30m"
     }
   }
 }`)
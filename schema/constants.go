@@ -31,11 +31,35 @@ const (
 	CreateIndexDisputedCasesSQL             = "create index index_cases on cases (timestamp);"
 	CreateTableChatSQL                      = "create table chat (messageID text primary key not null, peerID text, subject text, message text, read integer, timestamp integer, outgoing integer);"
 	CreateIndexChatSQL                      = "create index index_chat on chat (peerID, subject, read, timestamp);"
+	CreateTableChatAttachmentsSQL           = "create table chatattachments (messageID text not null, idx integer not null, filename text, mediaType text, size integer, hash text, location text, data blob, primary key (messageID, idx));"
 	CreateTableNotificationsSQL             = "create table notifications (notifID text primary key not null, serializedNotification blob, type text, timestamp integer, read integer);"
 	CreateIndexNotificationsSQL             = "create index index_notifications on notifications (read, type, timestamp);"
 	CreateTableCouponsSQL                   = "create table coupons (slug text, code text, hash text);"
 	CreateIndexCouponsSQL                   = "create index index_coupons on coupons (slug);"
 	CreateTableModeratedStoresSQL           = "create table moderatedstores (peerID text primary key not null);"
+	CreateTableCaseHandoversSQL             = "create table casehandovers (orderID text primary key not null, moderator text, backupModerator text, buyerID text, vendorID text, buyerAccepted integer, vendorAccepted integer, buyerRejected integer, vendorRejected integer, signedHandover blob, timestamp integer, transferSigs blob, transferTxid text);"
+	CreateIndexCaseHandoversSQL             = "create index index_casehandovers on casehandovers (backupModerator, timestamp);"
+	CreateTablePanelVotesSQL                = "create table panelvotes (orderID text not null, moderatorID text not null, vote blob, timestamp integer, primary key (orderID, moderatorID));"
+	CreateTableRatchetSessionsSQL           = "create table ratchetsessions (peerID text primary key not null, state blob, timestamp integer);"
+	CreateTableChatGroupsSQL                = "create table chatgroups (groupID text primary key not null, name text, creator text, members text, version integer, membership blob, active integer, timestamp integer);"
+	CreateTableChatGroupMessagesSQL         = "create table chatgroupmessages (messageID text primary key not null, groupID text, peerID text, message text, read integer, timestamp integer, outgoing integer);"
+	CreateIndexChatGroupMessagesSQL         = "create index index_chatgroupmessages on chatgroupmessages (groupID, read, timestamp);"
+	CreateTableChatEditsSQL                 = "create table chatedits (messageID text not null, peerID text, action text, previous text, message text, signedChat blob, timestamp integer);"
+	CreateIndexChatEditsSQL                 = "create index index_chatedits on chatedits (messageID, timestamp);"
+	CreateTableChatReactionsSQL             = "create table chatreactions (messageID text not null, peerID text not null, reaction text not null, timestamp integer, primary key (messageID, peerID, reaction));"
+	CreateTableWebhookDeliveriesSQL         = "create table webhookdeliveries (deliveryID text primary key not null, url text, type text, payload blob, status text, attempts integer, lastError text, timestamp integer, updated integer);"
+	CreateIndexWebhookDeliveriesSQL         = "create index index_webhookdeliveries on webhookdeliveries (status, timestamp);"
+	CreateTablePostCommentsSQL              = "create table postcomments (commentID text primary key not null, postSlug text not null, reference text, authorID text, postType text, signedPost blob, timestamp integer);"
+	CreateIndexPostCommentsSQL              = "create index index_postcomments on postcomments (postSlug, timestamp);"
+	CreateTableFeedSQL                      = "create table feed (entryID text primary key not null, peerID text not null, entryType text not null, slug text not null, hash text, data blob, timestamp integer);"
+	CreateIndexFeedSQL                      = "create index index_feed on feed (timestamp);"
+	CreateIndexFeedPeerSQL                  = "create index index_feed_peer on feed (peerID);"
+	CreateTableSigningRequestsSQL           = "create table signingrequests (requestID text primary key not null, orderID text, wallet text not null, action text not null, state text not null, psbt blob not null, address text, memo text, timestamp integer);"
+	CreateIndexSigningRequestsSQL           = "create index index_signingrequests on signingrequests (orderID, state);"
+	CreateTableTransactionLabelsSQL         = "create table txlabels (target text primary key not null, kind text not null, category text, labels text, timestamp integer);"
+	CreateTableTransactionFiatValuesSQL     = "create table txfiatvalues (txid text primary key not null, currency text not null, rate real not null, timestamp integer);"
+	CreateTableExchangeRatesSQL             = "create table exchangerates (coin text not null, currency text not null, rate real not null, timestamp integer not null, primary key (coin, currency, timestamp));"
+	CreateTableOrderExchangeRatesSQL        = "create table orderexchangerates (orderID text not null, coin text not null, currency text not null, rate real not null, timestamp integer, primary key (orderID, coin, currency));"
 	// End SQL Statements
 
 	// Configuration defaults
@@ -300,11 +300,35 @@ func InitializeDatabaseSQL(encryptionPassword string) string {
 		CreateIndexDisputedCasesSQL,
 		CreateTableChatSQL,
 		CreateIndexChatSQL,
+		CreateTableChatAttachmentsSQL,
 		CreateTableNotificationsSQL,
 		CreateIndexNotificationsSQL,
 		CreateTableCouponsSQL,
 		CreateIndexCouponsSQL,
 		CreateTableModeratedStoresSQL,
+		CreateTableCaseHandoversSQL,
+		CreateIndexCaseHandoversSQL,
+		CreateTablePanelVotesSQL,
+		CreateTableRatchetSessionsSQL,
+		CreateTableChatGroupsSQL,
+		CreateTableChatGroupMessagesSQL,
+		CreateIndexChatGroupMessagesSQL,
+		CreateTableChatEditsSQL,
+		CreateIndexChatEditsSQL,
+		CreateTableChatReactionsSQL,
+		CreateTableWebhookDeliveriesSQL,
+		CreateIndexWebhookDeliveriesSQL,
+		CreateTablePostCommentsSQL,
+		CreateIndexPostCommentsSQL,
+		CreateTableFeedSQL,
+		CreateIndexFeedSQL,
+		CreateIndexFeedPeerSQL,
+		CreateTableSigningRequestsSQL,
+		CreateIndexSigningRequestsSQL,
+		CreateTableTransactionLabelsSQL,
+		CreateTableTransactionFiatValuesSQL,
+		CreateTableExchangeRatesSQL,
+		CreateTableOrderExchangeRatesSQL,
 	}
 	return strings.Join(initializeStatement, " ")
 }
@@ -301,9 +301,25 @@ func TestInitializeDatabase(t *testing.T) {
 		"watchedscripts",
 		"cases",
 		"chat",
+		"chatattachments",
 		"notifications",
 		"coupons",
 		"moderatedstores",
+		"casehandovers",
+		"panelvotes",
+		"ratchetsessions",
+		"chatgroups",
+		"chatgroupmessages",
+		"chatedits",
+		"chatreactions",
+		"webhookdeliveries",
+		"postcomments",
+		"feed",
+		"signingrequests",
+		"txlabels",
+		"txfiatvalues",
+		"exchangerates",
+		"orderexchangerates",
 	}
 	db, err := subject.OpenDatabase()
 	if err != nil {
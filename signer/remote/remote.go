@@ -0,0 +1,308 @@
+// Package remote talks to a signing daemon which holds the node's keys,
+// over JSON-RPC on a Unix socket. Serve runs the daemon side for any
+// signer.Signer.
+package remote
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/op/go-logging"
+	"github.com/phoreproject/openbazaar-go/signer"
+)
+
+var log = logging.MustGetLogger("signer")
+
+const (
+	serviceName = "Signer"
+	dialTimeout = 5 * time.Second
+	callTimeout = 30 * time.Second
+)
+
+var (
+	// ErrNoSignature is returned when the daemon answers without a signature
+	ErrNoSignature = errors.New("signer: daemon returned no signature")
+
+	// ErrTimeout is returned when the daemon doesn't answer a call in time
+	ErrTimeout = errors.New("signer: daemon did not answer in time")
+
+	// ErrPrivateKey is returned when the daemon sends a private key where a
+	// public key was asked for
+	ErrPrivateKey = errors.New("signer: daemon returned a private key")
+)
+
+// SignArgs are the arguments of a signing call
+type SignArgs struct {
+	Data      []byte `json:"data"`
+	Chaincode []byte `json:"chaincode,omitempty"`
+	Index     uint32 `json:"index,omitempty"`
+	CoinType  uint32 `json:"coinType,omitempty"`
+	Change    uint32 `json:"change,omitempty"`
+}
+
+// SignReply is the reply to a signing call
+type SignReply struct {
+	Signature []byte `json:"signature"`
+}
+
+// PublicKeysArgs are the arguments of a call for the public keys
+type PublicKeysArgs struct {
+	CoinTypes []uint32 `json:"coinTypes"`
+}
+
+// PublicKeysReply holds the serialized public keys of the master key and of
+// the account of each coin type
+type PublicKeysReply struct {
+	Master   string            `json:"master"`
+	Accounts map[uint32]string `json:"accounts"`
+}
+
+// Signer signs with a remote signing daemon. The connection is opened on
+// first use and again after the daemon goes away or fails to answer.
+type Signer struct {
+	socket  string
+	timeout time.Duration
+
+	mtx    sync.Mutex
+	client *rpc.Client
+}
+
+// NewSigner returns a signer for the daemon listening on the Unix socket
+func NewSigner(socket string) *Signer {
+	return &Signer{socket: socket, timeout: callTimeout}
+}
+
+// SetTimeout sets how long a call waits for the daemon's answer
+func (s *Signer) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+}
+
+// SignIdentity asks the daemon to sign data with the identity key
+func (s *Signer) SignIdentity(data []byte) ([]byte, error) {
+	return s.sign("SignIdentity", SignArgs{Data: data})
+}
+
+// SignBitcoin asks the daemon to sign data with the master key
+func (s *Signer) SignBitcoin(data []byte) ([]byte, error) {
+	return s.sign("SignBitcoin", SignArgs{Data: data})
+}
+
+// SignEscrow asks the daemon to sign a hash with the escrow key for the
+// chaincode
+func (s *Signer) SignEscrow(chaincode []byte, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, signer.ErrInvalidHash
+	}
+	return s.sign("SignEscrow", SignArgs{Data: hash, Chaincode: chaincode})
+}
+
+// SignRating asks the daemon to sign a hash with the rating key at the index
+func (s *Signer) SignRating(index uint32, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, signer.ErrInvalidHash
+	}
+	return s.sign("SignRating", SignArgs{Data: hash, Index: index})
+}
+
+// SignWallet asks the daemon to sign a hash with a wallet key
+func (s *Signer) SignWallet(coinType uint32, change uint32, index uint32, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, signer.ErrInvalidHash
+	}
+	return s.sign("SignWallet", SignArgs{Data: hash, CoinType: coinType, Change: change, Index: index})
+}
+
+// PublicKeys asks the daemon for the public keys of the master key and the
+// accounts of the coin types
+func (s *Signer) PublicKeys(coinTypes []uint32) (*signer.PublicKeys, error) {
+	var reply PublicKeysReply
+	if err := s.call("PublicKeys", &PublicKeysArgs{CoinTypes: coinTypes}, &reply); err != nil {
+		return nil, err
+	}
+	master, err := parsePublicKey(reply.Master)
+	if err != nil {
+		return nil, err
+	}
+	pub := &signer.PublicKeys{Master: master, Accounts: make(map[uint32]*hd.ExtendedKey)}
+	for _, coinType := range coinTypes {
+		if pub.Accounts[coinType], err = parsePublicKey(reply.Accounts[coinType]); err != nil {
+			return nil, err
+		}
+	}
+	return pub, nil
+}
+
+// Close closes the connection to the daemon
+func (s *Signer) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+	return err
+}
+
+func (s *Signer) sign(method string, args SignArgs) ([]byte, error) {
+	var reply SignReply
+	if err := s.call(method, &args, &reply); err != nil {
+		return nil, err
+	}
+	if len(reply.Signature) == 0 {
+		return nil, ErrNoSignature
+	}
+	return reply.Signature, nil
+}
+
+// call makes a call to the daemon, giving up on the connection if the
+// daemon doesn't answer before the timeout
+func (s *Signer) call(method string, args interface{}, reply interface{}) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	call := client.Go(serviceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+	case <-timer.C:
+		s.disconnect(client)
+		return ErrTimeout
+	}
+	if call.Error != nil {
+		if _, ok := call.Error.(rpc.ServerError); !ok {
+			s.disconnect(client)
+		}
+		return call.Error
+	}
+	return nil
+}
+
+func (s *Signer) connect() (*rpc.Client, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+	conn, err := net.DialTimeout("unix", s.socket, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s.client = jsonrpc.NewClient(conn)
+	return s.client, nil
+}
+
+// disconnect drops a connection which failed so the next call redials
+func (s *Signer) disconnect(client *rpc.Client) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.client == client {
+		s.client.Close()
+		s.client = nil
+	}
+}
+
+func parsePublicKey(encoded string) (*hd.ExtendedKey, error) {
+	key, err := hd.NewKeyFromString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsPrivate() {
+		return nil, ErrPrivateKey
+	}
+	return key, nil
+}
+
+// Service is the daemon side of the protocol, signing with a signer.Signer
+type Service struct {
+	signer signer.Signer
+}
+
+// SignIdentity signs the data with the identity key
+func (s *Service) SignIdentity(args *SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignIdentity(args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+// SignEscrow signs the hash with the escrow key for the chaincode
+func (s *Service) SignEscrow(args *SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignEscrow(args.Chaincode, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+// SignBitcoin signs the data with the master key
+func (s *Service) SignBitcoin(args *SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignBitcoin(args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+// SignRating signs the hash with the rating key at the index
+func (s *Service) SignRating(args *SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignRating(args.Index, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+// SignWallet signs the hash with the wallet key at the path
+func (s *Service) SignWallet(args *SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignWallet(args.CoinType, args.Change, args.Index, args.Data)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+// PublicKeys returns the serialized public keys of the master key and the
+// accounts of the coin types
+func (s *Service) PublicKeys(args *PublicKeysArgs, reply *PublicKeysReply) error {
+	pub, err := s.signer.PublicKeys(args.CoinTypes)
+	if err != nil {
+		return err
+	}
+	reply.Master = pub.Master.String()
+	reply.Accounts = make(map[uint32]string)
+	for coinType, key := range pub.Accounts {
+		reply.Accounts[coinType] = key.String()
+	}
+	return nil
+}
+
+// Serve answers signing calls on the listener with the signer until the
+// listener is closed
+func Serve(l net.Listener, s signer.Signer) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &Service{signer: s}); err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		log.Debugf("signing connection from %s", conn.RemoteAddr())
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
@@ -0,0 +1,293 @@
+package remote_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/phoreproject/openbazaar-go/signer"
+	"github.com/phoreproject/openbazaar-go/signer/remote"
+	crypto "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+)
+
+// failingSigner is a daemon's signer which has lost its keys
+type failingSigner struct{}
+
+func (failingSigner) SignIdentity(data []byte) ([]byte, error) {
+	return nil, errors.New("identity key unavailable")
+}
+
+func (failingSigner) SignBitcoin(data []byte) ([]byte, error) {
+	return nil, errors.New("master key unavailable")
+}
+
+func (failingSigner) SignEscrow(chaincode []byte, hash []byte) ([]byte, error) {
+	return nil, errors.New("escrow key unavailable")
+}
+
+func (failingSigner) SignRating(index uint32, hash []byte) ([]byte, error) {
+	return nil, errors.New("rating key unavailable")
+}
+
+func (failingSigner) SignWallet(coinType uint32, change uint32, index uint32, hash []byte) ([]byte, error) {
+	return nil, errors.New("wallet key unavailable")
+}
+
+func (failingSigner) PublicKeys(coinTypes []uint32) (*signer.PublicKeys, error) {
+	return nil, errors.New("master key unavailable")
+}
+
+func newLocalSigner(t *testing.T) (*signer.LocalSigner, crypto.PubKey) {
+	identityKey, identityPub, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := hd.NewMaster(bytes.Repeat([]byte{7}, 32), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer.NewLocalSigner(identityKey, master), identityPub
+}
+
+// startDaemon runs a mock signing daemon for the signer and returns the
+// path of its socket
+func startDaemon(t *testing.T, s signer.Signer) (string, func()) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socket := path.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go remote.Serve(l, s)
+	return socket, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSignerMatchesLocalSigner(t *testing.T) {
+	local, identityPub := newLocalSigner(t)
+	socket, stop := startDaemon(t, local)
+	defer stop()
+	s := remote.NewSigner(socket)
+	defer s.Close()
+
+	data := []byte("listing")
+	sig, err := s.SignIdentity(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := identityPub.Verify(data, sig); err != nil || !ok {
+		t.Error("expected the daemon to sign with the identity key")
+	}
+
+	chaincode := bytes.Repeat([]byte{1}, 32)
+	hash := chainhash.DoubleHashB([]byte("escrow release"))
+	remoteSig, err := s.SignEscrow(chaincode, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	localSig, err := local.SignEscrow(chaincode, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(remoteSig, localSig) {
+		t.Error("expected the daemon's escrow signature to match the local one")
+	}
+
+	// The escrow key is the one the wallets derive for any network
+	escrowKey, err := local.EscrowKey(chaincode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := hd.NewMaster(bytes.Repeat([]byte{7}, 32), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterPub, err := master.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletKey, err := hd.NewExtendedKey(chaincfg.RegressionNetParams.HDPublicKeyID[:], masterPub.SerializeCompressed(), chaincode, []byte{0, 0, 0, 0}, 0, 0, false).Child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escrowPub, err := escrowKey.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletPub, err := walletKey.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !escrowPub.IsEqual(walletPub) {
+		t.Fatal("expected the escrow key to match the wallet's child key")
+	}
+	parsed, err := btcec.ParseDERSignature(remoteSig, btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Verify(hash, walletPub) {
+		t.Error("expected the escrow signature to verify against the escrow key")
+	}
+
+	if _, err := s.SignEscrow(chaincode, hash[:20]); err != signer.ErrInvalidHash {
+		t.Errorf("expected a short hash to be rejected, got %v", err)
+	}
+}
+
+func TestSignerMatchesLocalMasterKeys(t *testing.T) {
+	local, _ := newLocalSigner(t)
+	socket, stop := startDaemon(t, local)
+	defer stop()
+	s := remote.NewSigner(socket)
+	defer s.Close()
+
+	pub, err := s.PublicKeys([]uint32{0, 444})
+	if err != nil {
+		t.Fatal(err)
+	}
+	localPub, err := local.PublicKeys([]uint32{0, 444})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.Master.IsPrivate() || pub.Master.String() != localPub.Master.String() {
+		t.Error("expected the daemon to return the master public key")
+	}
+	for _, coinType := range []uint32{0, 444} {
+		if pub.Accounts[coinType] == nil || pub.Accounts[coinType].String() != localPub.Accounts[coinType].String() {
+			t.Errorf("expected the daemon to return the account key of coin type %d", coinType)
+		}
+	}
+
+	masterPub, err := pub.Master.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("QmPeerID")
+	sig, err := s.SignBitcoin(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := btcec.ParseDERSignature(sig, btcec.S256()); err != nil || !parsed.Verify(data, masterPub) {
+		t.Error("expected the daemon to sign with the master key")
+	}
+
+	hash := chainhash.DoubleHashB([]byte("rating"))
+	ratingKey, err := pub.Master.Child(1500000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ratingPub, err := ratingKey.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err = s.SignRating(1500000000, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := btcec.ParseDERSignature(sig, btcec.S256()); err != nil || !parsed.Verify(hash, ratingPub) {
+		t.Error("expected the daemon to sign with the rating key")
+	}
+
+	chain, err := pub.Accounts[444].Child(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletKey, err := chain.Child(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	walletPub, err := walletKey.ECPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err = s.SignWallet(444, 1, 3, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := btcec.ParseDERSignature(sig, btcec.S256()); err != nil || !parsed.Verify(hash, walletPub) {
+		t.Error("expected the daemon to sign with the wallet key")
+	}
+}
+
+func TestSignerTimesOut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	// The daemon accepts the connection and never answers
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	s := remote.NewSigner(socket)
+	defer s.Close()
+	s.SetTimeout(100 * time.Millisecond)
+	if _, err := s.SignIdentity([]byte("order")); err != remote.ErrTimeout {
+		t.Errorf("expected the call to time out, got %v", err)
+	}
+	if _, err := s.SignEscrow(nil, make([]byte, 32)); err != remote.ErrTimeout {
+		t.Errorf("expected the call on a new connection to time out, got %v", err)
+	}
+}
+
+func TestSignerReturnsDaemonErrors(t *testing.T) {
+	socket, stop := startDaemon(t, failingSigner{})
+	defer stop()
+	s := remote.NewSigner(socket)
+	defer s.Close()
+
+	if _, err := s.SignIdentity([]byte("order")); err == nil || err.Error() != "identity key unavailable" {
+		t.Errorf("expected the daemon's error, got %v", err)
+	}
+	// The connection is kept after an error from the daemon
+	if _, err := s.SignEscrow(nil, make([]byte, 32)); err == nil || err.Error() != "escrow key unavailable" {
+		t.Errorf("expected the daemon's error, got %v", err)
+	}
+}
+
+func TestSignerRedialsDaemon(t *testing.T) {
+	local, _ := newLocalSigner(t)
+	s := remote.NewSigner(path.Join(os.TempDir(), "no-such-signer.sock"))
+	if _, err := s.SignIdentity([]byte("order")); err == nil {
+		t.Fatal("expected an error without a daemon")
+	}
+
+	socket, stop := startDaemon(t, local)
+	defer stop()
+	s = remote.NewSigner(socket)
+	if _, err := s.SignIdentity([]byte("order")); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if _, err := s.SignIdentity([]byte("order")); err != nil {
+		t.Errorf("expected the signer to reconnect, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/phoreproject/multiwallet/keys"
+	"github.com/phoreproject/multiwallet/util"
+	crypto "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+)
+
+// ErrInvalidHash is returned when asked to sign something which isn't a 32
+// byte transaction signature hash
+var ErrInvalidHash = errors.New("signer: hash must be 32 bytes")
+
+type Signer interface {
+	/* This interface provides a pluggable mechanism for making the signatures
+	   which need the node's private keys, so the keys can be kept somewhere
+	   other than the node process. The node derives every public key it
+	   needs itself and checks the signatures it gets back.
+
+	   Implementations:
+	   LocalSigner -> the keys are held by the node
+	   remote.Signer -> the keys are held by a signing daemon reached over a
+	   Unix socket
+	   WatchOnlySigner -> the node holds only public keys and its
+	   transactions are signed elsewhere */
+
+	// SignIdentity signs data with the node's identity key, as listings
+	// and orders are
+	SignIdentity(data []byte) ([]byte, error)
+
+	// SignBitcoin signs data with the master key, whose public key is the
+	// Bitcoin key in our listings and orders, and returns the DER encoded
+	// signature
+	SignBitcoin(data []byte) ([]byte, error)
+
+	// SignEscrow signs a 32 byte hash with our key in the escrow of the order
+	// with the chaincode and returns the DER encoded signature
+	SignEscrow(chaincode []byte, hash []byte) ([]byte, error)
+
+	// SignRating signs a 32 byte hash with the rating key at the index, a
+	// child of the master key, and returns the DER encoded signature
+	SignRating(index uint32, hash []byte) ([]byte, error)
+
+	// SignWallet signs a 32 byte hash with the wallet key of the coin type
+	// at the change and index of its BIP44 account and returns the DER
+	// encoded signature
+	SignWallet(coinType uint32, change uint32, index uint32, hash []byte) ([]byte, error)
+
+	// PublicKeys returns the master public key and the public keys of the
+	// accounts of the coin types
+	PublicKeys(coinTypes []uint32) (*PublicKeys, error)
+}
+
+// EscrowKeyHolder is a Signer which can hand our escrow keys to the wallets,
+// letting a wallet sign escrow transactions itself
+type EscrowKeyHolder interface {
+	EscrowKey(chaincode []byte) (*hd.ExtendedKey, error)
+}
+
+// PublicKeys are the keys a node without its seed derives every public key
+// it needs from
+type PublicKeys struct {
+	// Master is the public key of the master key
+	Master *hd.ExtendedKey
+
+	// Accounts holds the public key of the BIP44 account of each coin type
+	Accounts map[uint32]*hd.ExtendedKey
+}
+
+// LocalSigner signs with keys held in the node
+type LocalSigner struct {
+	identityKey crypto.PrivKey
+	masterKey   *hd.ExtendedKey
+}
+
+// NewLocalSigner returns a signer for the node's identity key and the
+// master key its escrow keys are derived from
+func NewLocalSigner(identityKey crypto.PrivKey, masterKey *hd.ExtendedKey) *LocalSigner {
+	return &LocalSigner{identityKey: identityKey, masterKey: masterKey}
+}
+
+// SignIdentity signs data with the identity key
+func (s *LocalSigner) SignIdentity(data []byte) ([]byte, error) {
+	return s.identityKey.Sign(data)
+}
+
+// SignBitcoin signs data with the master key
+func (s *LocalSigner) SignBitcoin(data []byte) ([]byte, error) {
+	priv, err := s.masterKey.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := priv.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
+
+// SignEscrow signs a hash with the escrow key for the chaincode
+func (s *LocalSigner) SignEscrow(chaincode []byte, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	key, err := s.EscrowKey(chaincode)
+	if err != nil {
+		return nil, err
+	}
+	return signHash(key, hash)
+}
+
+// SignRating signs a hash with the rating key at the index
+func (s *LocalSigner) SignRating(index uint32, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	key, err := s.masterKey.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return signHash(key, hash)
+}
+
+// SignWallet signs a hash with the wallet key at the path
+func (s *LocalSigner) SignWallet(coinType uint32, change uint32, index uint32, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	account, err := keys.Bip44Account(s.masterKey, util.ExtCoinType(coinType))
+	if err != nil {
+		return nil, err
+	}
+	chain, err := account.Child(change)
+	if err != nil {
+		return nil, err
+	}
+	key, err := chain.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return signHash(key, hash)
+}
+
+// PublicKeys returns the public keys of the master key and the accounts
+func (s *LocalSigner) PublicKeys(coinTypes []uint32) (*PublicKeys, error) {
+	master, err := s.masterKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
+	pub := &PublicKeys{Master: master, Accounts: make(map[uint32]*hd.ExtendedKey)}
+	for _, coinType := range coinTypes {
+		account, err := keys.Bip44Account(s.masterKey, util.ExtCoinType(coinType))
+		if err != nil {
+			return nil, err
+		}
+		if pub.Accounts[coinType], err = account.Neuter(); err != nil {
+			return nil, err
+		}
+	}
+	return pub, nil
+}
+
+// EscrowKey returns our key in the escrow of the order with the chaincode.
+// It is derived as the wallets' ChildKey derives it, whose key doesn't
+// depend on the network.
+func (s *LocalSigner) EscrowKey(chaincode []byte) (*hd.ExtendedKey, error) {
+	priv, err := s.masterKey.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	parentFP := []byte{0x00, 0x00, 0x00, 0x00}
+	key := hd.NewExtendedKey(chaincfg.MainNetParams.HDPrivateKeyID[:], priv.Serialize(), chaincode, parentFP, 0, 0, true)
+	return key.Child(0)
+}
+
+func signHash(key *hd.ExtendedKey, hash []byte) ([]byte, error) {
+	priv, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := priv.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
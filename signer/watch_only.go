@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	crypto "gx/ipfs/QmTW4SdgBWq9GjsBsHeUx8WuGxzhgzAf88UMH2w62PC8yK/go-libp2p-crypto"
+)
+
+var (
+	// ErrWatchOnly is returned by a watch-only signer for a signature which
+	// needs the node's seed
+	ErrWatchOnly = errors.New("signer: a watch-only node can't make this signature")
+
+	// ErrInvalidBitcoinSig is returned when the signature of the peer ID given
+	// to a watch-only signer wasn't made by its master key
+	ErrInvalidBitcoinSig = errors.New("signer: the peer ID signature doesn't match the master key")
+)
+
+// WatchOnlySigner is the signer of a node which holds its identity key but
+// only the public keys of its seed. Its transactions are exported and signed
+// elsewhere. The master key's signature of the peer ID, which our listings
+// and orders carry, is made ahead of time.
+type WatchOnlySigner struct {
+	identityKey crypto.PrivKey
+	publicKeys  *PublicKeys
+	peerID      string
+	bitcoinSig  []byte
+}
+
+// NewWatchOnlySigner returns a signer for the identity key which only has
+// the public keys of the seed and the master key's signature of the peer ID
+func NewWatchOnlySigner(identityKey crypto.PrivKey, publicKeys *PublicKeys, peerID string, bitcoinSig []byte) (*WatchOnlySigner, error) {
+	masterPub, err := publicKeys.Master.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := btcec.ParseDERSignature(bitcoinSig, btcec.S256())
+	if err != nil || !sig.Verify([]byte(peerID), masterPub) {
+		return nil, ErrInvalidBitcoinSig
+	}
+	return &WatchOnlySigner{identityKey: identityKey, publicKeys: publicKeys, peerID: peerID, bitcoinSig: bitcoinSig}, nil
+}
+
+// SignIdentity signs data with the identity key
+func (s *WatchOnlySigner) SignIdentity(data []byte) ([]byte, error) {
+	return s.identityKey.Sign(data)
+}
+
+// SignBitcoin returns the master key's signature of the peer ID, the only
+// data it was signed ahead of time for
+func (s *WatchOnlySigner) SignBitcoin(data []byte) ([]byte, error) {
+	if string(data) != s.peerID {
+		return nil, ErrWatchOnly
+	}
+	return append([]byte{}, s.bitcoinSig...), nil
+}
+
+// SignEscrow fails, the escrow keys are held by the external signer
+func (s *WatchOnlySigner) SignEscrow(chaincode []byte, hash []byte) ([]byte, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignRating fails, the rating keys are derived from the seed
+func (s *WatchOnlySigner) SignRating(index uint32, hash []byte) ([]byte, error) {
+	return nil, ErrWatchOnly
+}
+
+// SignWallet fails, the wallet keys are held by the external signer
+func (s *WatchOnlySigner) SignWallet(coinType uint32, change uint32, index uint32, hash []byte) ([]byte, error) {
+	return nil, ErrWatchOnly
+}
+
+// PublicKeys returns the master public key and the account keys of the coin
+// types, which must all have been given to the signer
+func (s *WatchOnlySigner) PublicKeys(coinTypes []uint32) (*PublicKeys, error) {
+	pub := &PublicKeys{Master: s.publicKeys.Master, Accounts: make(map[uint32]*hd.ExtendedKey)}
+	for _, coinType := range coinTypes {
+		account, ok := s.publicKeys.Accounts[coinType]
+		if !ok {
+			return nil, fmt.Errorf("signer: no account key for coin type %d", coinType)
+		}
+		pub.Accounts[coinType] = account
+	}
+	return pub, nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/phoreproject/openbazaar-go/ipfs"
 	"github.com/phoreproject/openbazaar-go/net"
 	"github.com/phoreproject/openbazaar-go/net/service"
+	"github.com/phoreproject/openbazaar-go/signer"
 
 	coremock "github.com/ipfs/go-ipfs/core/mock"
 	"github.com/tyler-smith/go-bip39"
@@ -67,6 +68,10 @@ func NewNode() (*core.OpenBazaarNode, error) {
 	if err != nil {
 		return nil, err
 	}
+	mPubKey, err := mPrivKey.Neuter()
+	if err != nil {
+		return nil, err
+	}
 
 	coins := make(map[wi.CoinType]bool)
 	coins[wi.Bitcoin] = true
@@ -93,13 +98,14 @@ func NewNode() (*core.OpenBazaarNode, error) {
 
 	// Put it all together in an OpenBazaarNode
 	node := &core.OpenBazaarNode{
-		RepoPath:         GetRepoPath(),
-		IpfsNode:         ipfsNode,
-		Datastore:        repository.DB,
-		Multiwallet:      mw,
-		BanManager:       net.NewBanManager([]peer.ID{}),
-		MasterPrivateKey: mPrivKey,
-		DHT:              routing,
+		RepoPath:        GetRepoPath(),
+		IpfsNode:        ipfsNode,
+		Datastore:       repository.DB,
+		Multiwallet:     mw,
+		BanManager:      net.NewBanManager([]peer.ID{}),
+		MasterPublicKey: mPubKey,
+		Signer:          signer.NewLocalSigner(ipfsNode.PrivateKey, mPrivKey),
+		DHT:             routing,
 	}
 
 	node.Service = service.New(node, repository.DB)
@@ -26,9 +26,11 @@ import (
 	"github.com/op/go-logging"
 
 	"github.com/btcsuite/btcd/chaincfg"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/phoreproject/openbazaar-go/repo"
 	"github.com/phoreproject/openbazaar-go/repo/db"
 	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/signer"
 
 	"github.com/OpenBazaar/spvwallet"
 	"github.com/OpenBazaar/wallet-interface"
@@ -40,6 +42,20 @@ const InvalidCoinType util.ExtCoinType = util.ExtCoinType(^uint32(0))
 // ErrTrustedPeerRequired is returned when the config is missing the TrustedPeer field
 var ErrTrustedPeerRequired = errors.New("trusted peer required in spv wallet config during regtest use")
 
+// ErrMnemonicRequired is returned for a wallet which can't be built from the
+// node's public keys
+var ErrMnemonicRequired = errors.New("wallet requires the node's mnemonic")
+
+// AccountCoinTypes are the coin types of the BIP44 accounts API wallets
+// derive their keys from
+var AccountCoinTypes = []uint32{
+	uint32(util.CoinTypePhore),
+	uint32(wallet.Bitcoin),
+	uint32(wallet.BitcoinCash),
+	uint32(wallet.Litecoin),
+	uint32(wallet.Zcash),
+}
+
 // WalletConfig describes the options needed to create a MultiWallet
 type WalletConfig struct {
 	// ConfigFile contains the options of each native wallet
@@ -52,6 +68,9 @@ type WalletConfig struct {
 	DB *db.DB
 	// Mnemonic is the string entropy used to generate the wallet's BIP39-compliant seed
 	Mnemonic string
+	// PublicKeys are used instead of the mnemonic by a node which doesn't hold
+	// its seed. Only API wallets can be built from them and they can't sign.
+	PublicKeys *signer.PublicKeys
 	// WalletCreationDate represents the time when new transactions were added by this wallet
 	WalletCreationDate time.Time
 	// Params describe the desired blockchain params to enforce on joining the network
@@ -126,6 +145,18 @@ func NewMultiWallet(cfg *WalletConfig) (multiwallet.MultiWallet, error) {
 		newMultiwallet[actualCoin] = newWallet
 	}
 
+	for _, tokenConfig := range cfg.ConfigFile.Tokens {
+		if tokenConfig == nil {
+			continue
+		}
+		actualCoin, newWallet, err := createTokenWallet(tokenConfig, cfg)
+		if err != nil {
+			logger.Errorf("failed creating wallet for token %s: %s", tokenConfig.Code, err)
+			continue
+		}
+		newMultiwallet[actualCoin] = newWallet
+	}
+
 	return newMultiwallet, nil
 }
 
@@ -134,7 +165,17 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		actualCoin util.ExtCoinType
 		testnet    = cfg.Params.Name != chaincfg.MainNetParams.Name
 		coinConfig = prepareAPICoinConfig(coin, coinConfigOverrides, cfg)
+		master     *hd.ExtendedKey
+		account    *hd.ExtendedKey
+		w          wallet.Wallet
+		err        error
 	)
+	if cfg.PublicKeys != nil {
+		master = cfg.PublicKeys.Master
+		if account = cfg.PublicKeys.Accounts[uint32(coin)]; account == nil {
+			return InvalidCoinType, nil, fmt.Errorf("no account key for %s", coin.String())
+		}
+	}
 
 	switch coin {
 	case util.CoinTypePhore:
@@ -142,19 +183,23 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		if testnet {
 			actualCoin = util.CoinTypePhoreTest
 			params = phore.PhoreTestNetParams
-			err := chaincfg.Register(&phore.PhoreTestNetParams)
+			err = chaincfg.Register(&phore.PhoreTestNetParams)
 			if err != nil {
 				return InvalidCoinType, nil, err
 			}
 		} else {
 			actualCoin = util.CoinTypePhore
 			params = phore.PhoreMainNetParams
-			err := chaincfg.Register(&phore.PhoreMainNetParams)
+			err = chaincfg.Register(&phore.PhoreMainNetParams)
 			if err != nil {
 				return InvalidCoinType, nil, err
 			}
 		}
-		w, err := phore.NewPhoreWallet(*coinConfig, cfg.Mnemonic, &params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		if account != nil {
+			w, err = phore.NewPhoreWatchOnlyWallet(*coinConfig, master, account, &params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		} else {
+			w, err = phore.NewPhoreWallet(*coinConfig, cfg.Mnemonic, &params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		}
 		if err != nil {
 			return InvalidCoinType, nil, err
 		}
@@ -165,7 +210,11 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		} else {
 			actualCoin = util.ExtendCoinType(wallet.Bitcoin)
 		}
-		w, err := bitcoin.NewBitcoinWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		if account != nil {
+			w, err = bitcoin.NewBitcoinWatchOnlyWallet(*coinConfig, master, account, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		} else {
+			w, err = bitcoin.NewBitcoinWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		}
 		if err != nil {
 			return InvalidCoinType, nil, err
 		}
@@ -176,7 +225,11 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		} else {
 			actualCoin = util.ExtendCoinType(wallet.BitcoinCash)
 		}
-		w, err := bitcoincash.NewBitcoinCashWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		if account != nil {
+			w, err = bitcoincash.NewBitcoinCashWatchOnlyWallet(*coinConfig, master, account, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		} else {
+			w, err = bitcoincash.NewBitcoinCashWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		}
 		if err != nil {
 			return InvalidCoinType, nil, err
 		}
@@ -187,7 +240,11 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		} else {
 			actualCoin = wallet.Litecoin
 		}
-		w, err := litecoin.NewLitecoinWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		if account != nil {
+			w, err = litecoin.NewLitecoinWatchOnlyWallet(*coinConfig, master, account, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		} else {
+			w, err = litecoin.NewLitecoinWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		}
 		if err != nil {
 			return InvalidCoinType, nil, err
 		}
@@ -198,7 +255,11 @@ func createAPIWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		} else {
 			actualCoin = wallet.Zcash
 		}
-		w, err := zcash.NewZCashWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		if account != nil {
+			w, err = zcash.NewZCashWatchOnlyWallet(*coinConfig, master, account, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		} else {
+			w, err = zcash.NewZCashWallet(*coinConfig, cfg.Mnemonic, cfg.Params, cfg.Proxy, cache.NewMockCacher(), cfg.DisableExchangeRates)
+		}
 		if err != nil {
 			return InvalidCoinType, nil, err
 		}
@@ -223,6 +284,9 @@ func createSPVWallet(coin util.ExtCoinType, coinConfigOverrides *schema.CoinConf
 		defaultConfigSet   = schema.DefaultWalletsConfig()
 	)
 
+	if cfg.PublicKeys != nil {
+		return InvalidCoinType, nil, ErrMnemonicRequired
+	}
 	if usingRegnet && missingTrustedPeer {
 		return InvalidCoinType, nil, ErrTrustedPeerRequired
 	}
@@ -447,6 +511,15 @@ func (d *WalletDatastore) WatchedScripts() wallet.WatchedScripts {
 	return d.watchedScripts
 }
 
+// CreateTokenWalletDB returns the datastore of a token wallet, which only
+// keeps transactions and watched deposits
+func CreateTokenWalletDB(database *db.DB, code string) *WalletDatastore {
+	return &WalletDatastore{
+		txns:           db.NewTokenTransactionStore(database.SqlDB, database.Lock, code),
+		watchedScripts: db.NewTokenWatchedScriptStore(database.SqlDB, database.Lock, code),
+	}
+}
+
 func CreateWalletDB(database *db.DB, coinType util.ExtCoinType) *WalletDatastore {
 	return &WalletDatastore{
 		keys:           db.NewKeyStore(database.SqlDB, database.Lock, coinType),
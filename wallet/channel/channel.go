@@ -0,0 +1,80 @@
+// Package channel pays and issues the invoices of payment channel orders
+// through a local payment channel daemon.
+//
+// The vendor issues an invoice for the order's total with the order ID as
+// its description and the buyer checks that binding before paying it.
+// Settled invoices are reported to the wallet's transaction listeners as a
+// transaction paying the order, with the payment hash as its txid.
+package channel
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvoiceOrderMismatch is returned when the invoice describes
+	// another order than the one being paid
+	ErrInvoiceOrderMismatch = errors.New("invoice is not for this order")
+
+	// ErrInvoiceAmountMismatch is returned when the invoice requests an
+	// amount different from the order total
+	ErrInvoiceAmountMismatch = errors.New("invoice amount does not match the order total")
+
+	// ErrInvoiceExpired is returned when paying an expired invoice
+	ErrInvoiceExpired = errors.New("invoice has expired")
+
+	// ErrInvoiceNotFound is returned by a daemon that doesn't know the
+	// payment hash
+	ErrInvoiceNotFound = errors.New("invoice not found")
+)
+
+// Invoice is a payment request of a payment channel daemon. Amount is in
+// the base unit of the channel's coin.
+type Invoice struct {
+	PaymentHash    string
+	PaymentRequest string
+	Amount         int64
+	Description    string
+	Created        time.Time
+	Expiry         time.Time
+	Settled        bool
+	SettledAt      time.Time
+}
+
+// Expired returns true if the invoice can no longer be paid at t
+func (i *Invoice) Expired(t time.Time) bool {
+	return !i.Expiry.IsZero() && !t.Before(i.Expiry)
+}
+
+// Daemon is the part of a payment channel daemon's API used to pay and
+// issue the invoices of orders
+type Daemon interface {
+	// AddInvoice creates an invoice for amount which expires after expiry
+	AddInvoice(amount int64, description string, expiry time.Duration) (*Invoice, error)
+
+	// LookupInvoice returns an invoice created by this daemon
+	LookupInvoice(paymentHash string) (*Invoice, error)
+
+	// DecodeInvoice returns the terms of an encoded payment request
+	DecodeInvoice(paymentRequest string) (*Invoice, error)
+
+	// PayInvoice pays the payment request and returns the preimage of
+	// its payment hash
+	PayInvoice(paymentRequest string) (preimage []byte, err error)
+}
+
+// VerifyInvoice checks that the invoice is bound to the order and requests
+// its total
+func VerifyInvoice(invoice *Invoice, orderID string, amount int64) error {
+	if invoice.Description != orderID {
+		return ErrInvoiceOrderMismatch
+	}
+	if invoice.Amount != amount {
+		return ErrInvoiceAmountMismatch
+	}
+	if invoice.Expired(time.Now()) {
+		return ErrInvoiceExpired
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package channel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+const testOrderID = "QmW2ZMxZ4Dik8Fr4jyfuhTTyAdxAnPgUdp8s9dDzMdXGn3"
+
+var (
+	buyerMacaroon  = []byte("buyer macaroon")
+	vendorMacaroon = []byte("vendor macaroon")
+)
+
+func newTestWallets() (*regtestNetwork, *Wallet, *Wallet) {
+	network := newRegtestNetwork()
+	buyer := NewClient(network.addNode("buyer", 100000, buyerMacaroon), buyerMacaroon, nil)
+	vendor := NewClient(network.addNode("vendor", 0, vendorMacaroon), vendorMacaroon, nil)
+	return network, NewWallet(buyer, "TBTC", 0), NewWallet(vendor, "TBTC", 0)
+}
+
+func recordCallbacks(w *Wallet) *[]wallet.TransactionCallback {
+	var callbacks []wallet.TransactionCallback
+	w.AddTransactionListener(func(cb wallet.TransactionCallback) {
+		callbacks = append(callbacks, cb)
+	})
+	return &callbacks
+}
+
+func TestClientInvoiceRoundTrip(t *testing.T) {
+	network := newRegtestNetwork()
+	defer network.close()
+	buyer := NewClient(network.addNode("buyer", 100000, buyerMacaroon), buyerMacaroon, nil)
+	vendor := NewClient(network.addNode("vendor", 0, vendorMacaroon), vendorMacaroon, nil)
+
+	invoice, err := vendor.AddInvoice(25000, testOrderID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := buyer.DecodeInvoice(invoice.PaymentRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.PaymentHash != invoice.PaymentHash || decoded.Amount != 25000 || decoded.Description != testOrderID {
+		t.Errorf("decoded invoice %+v does not match %+v", decoded, invoice)
+	}
+	if decoded.Expired(time.Now()) || !decoded.Expired(time.Now().Add(2*time.Hour)) {
+		t.Errorf("incorrect invoice expiry %s", decoded.Expiry)
+	}
+
+	found, err := vendor.LookupInvoice(invoice.PaymentHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Settled {
+		t.Error("unpaid invoice is settled")
+	}
+
+	preimage, err := buyer.PayInvoice(invoice.PaymentRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256(preimage)
+	if hex.EncodeToString(hash[:]) != invoice.PaymentHash {
+		t.Error("preimage does not match the payment hash")
+	}
+	found, err = vendor.LookupInvoice(invoice.PaymentHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.Settled || found.SettledAt.IsZero() {
+		t.Error("paid invoice is not settled")
+	}
+	if network.balance("buyer") != 75000 || network.balance("vendor") != 25000 {
+		t.Errorf("incorrect channel balances %d and %d", network.balance("buyer"), network.balance("vendor"))
+	}
+
+	if _, err := buyer.PayInvoice(invoice.PaymentRequest); err == nil {
+		t.Error("paid an invoice twice")
+	}
+	if _, err := buyer.LookupInvoice(invoice.PaymentHash); err != ErrInvoiceNotFound {
+		t.Errorf("expected ErrInvoiceNotFound, got %v", err)
+	}
+}
+
+func TestClientMacaroon(t *testing.T) {
+	network := newRegtestNetwork()
+	defer network.close()
+	client := NewClient(network.addNode("vendor", 0, vendorMacaroon), []byte("wrong"), nil)
+
+	if _, err := client.AddInvoice(1000, testOrderID, time.Hour); err == nil {
+		t.Error("daemon accepted the wrong macaroon")
+	}
+}
+
+func TestWalletOrderPayment(t *testing.T) {
+	network, buyer, vendor := newTestWallets()
+	defer network.close()
+	buyerCallbacks := recordCallbacks(buyer)
+	vendorCallbacks := recordCallbacks(vendor)
+
+	invoice, err := vendor.CreateInvoice(testOrderID, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendor.CheckInvoices()
+	if len(*vendorCallbacks) != 0 {
+		t.Fatal("unpaid invoice was reported")
+	}
+
+	if err := buyer.PayInvoice(testOrderID, invoice.PaymentRequest, 40000); err != nil {
+		t.Fatal(err)
+	}
+	if len(*buyerCallbacks) != 1 {
+		t.Fatalf("expected one payment to be reported to the buyer, got %d", len(*buyerCallbacks))
+	}
+	checkCallback(t, (*buyerCallbacks)[0], invoice, -40000)
+
+	vendor.CheckInvoices()
+	if len(*vendorCallbacks) != 1 {
+		t.Fatalf("expected one payment to be reported to the vendor, got %d", len(*vendorCallbacks))
+	}
+	checkCallback(t, (*vendorCallbacks)[0], invoice, 40000)
+
+	vendor.CheckInvoices()
+	if len(*vendorCallbacks) != 1 {
+		t.Error("settled invoice was reported again")
+	}
+}
+
+func checkCallback(t *testing.T, cb wallet.TransactionCallback, invoice *Invoice, value int64) {
+	t.Helper()
+	if cb.Txid != invoice.PaymentHash {
+		t.Errorf("expected txid %s, got %s", invoice.PaymentHash, cb.Txid)
+	}
+	if cb.Value != value {
+		t.Errorf("expected value %d, got %d", value, cb.Value)
+	}
+	if len(cb.Outputs) != 1 {
+		t.Fatalf("expected one output, got %d", len(cb.Outputs))
+	}
+	out := cb.Outputs[0]
+	if out.OrderID != testOrderID || out.Value != invoice.Amount {
+		t.Errorf("output %+v does not pay the order", out)
+	}
+	if out.Address.String() != invoice.PaymentRequest || !bytes.Equal(out.Address.ScriptAddress(), []byte(invoice.PaymentRequest)) {
+		t.Errorf("incorrect output address %s", out.Address)
+	}
+}
+
+func TestWalletPayInvoiceBinding(t *testing.T) {
+	network, buyer, vendor := newTestWallets()
+	defer network.close()
+	callbacks := recordCallbacks(buyer)
+
+	invoice, err := vendor.CreateInvoice(testOrderID, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := buyer.PayInvoice("QmOtherOrder", invoice.PaymentRequest, 40000); err != ErrInvoiceOrderMismatch {
+		t.Errorf("expected ErrInvoiceOrderMismatch, got %v", err)
+	}
+	if err := buyer.PayInvoice(testOrderID, invoice.PaymentRequest, 30000); err != ErrInvoiceAmountMismatch {
+		t.Errorf("expected ErrInvoiceAmountMismatch, got %v", err)
+	}
+	if network.balance("buyer") != 100000 || len(*callbacks) != 0 {
+		t.Error("paid an invoice which is not for the order")
+	}
+
+	large, err := vendor.CreateInvoice(testOrderID, 200000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := buyer.PayInvoice(testOrderID, large.PaymentRequest, 200000); err == nil {
+		t.Error("paid more than the channel balance")
+	}
+	if len(*callbacks) != 0 {
+		t.Error("failed payment was reported")
+	}
+}
+
+func TestWalletWatch(t *testing.T) {
+	network, buyer, vendor := newTestWallets()
+	defer network.close()
+
+	invoice, err := vendor.CreateInvoice(testOrderID, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The node restarted and watches the order's invoice again
+	restarted := NewWallet(vendor.daemon, "TBTC", 0)
+	callbacks := recordCallbacks(restarted)
+	if err := restarted.Watch("QmOtherOrder", invoice.PaymentRequest); err != ErrInvoiceOrderMismatch {
+		t.Errorf("expected ErrInvoiceOrderMismatch, got %v", err)
+	}
+	if err := restarted.Watch(testOrderID, invoice.PaymentRequest); err != nil {
+		t.Fatal(err)
+	}
+	if err := buyer.PayInvoice(testOrderID, invoice.PaymentRequest, 40000); err != nil {
+		t.Fatal(err)
+	}
+	restarted.CheckInvoices()
+	if len(*callbacks) != 1 {
+		t.Fatalf("expected one payment to be reported, got %d", len(*callbacks))
+	}
+	checkCallback(t, (*callbacks)[0], invoice, 40000)
+}
+
+func TestWalletInvoiceExpiry(t *testing.T) {
+	network, _, vendor := newTestWallets()
+	defer network.close()
+	callbacks := recordCallbacks(vendor)
+	var expired []string
+	vendor.AddExpiryListener(func(orderID string) {
+		expired = append(expired, orderID)
+	})
+
+	invoice, err := vendor.CreateInvoice(testOrderID, 40000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendor.CheckInvoices()
+	if len(expired) != 0 {
+		t.Fatal("an invoice which can still be paid was reported as expired")
+	}
+
+	wi := vendor.watched[invoice.PaymentHash]
+	wi.expiry = time.Now().Add(-time.Second)
+	vendor.watched[invoice.PaymentHash] = wi
+	vendor.CheckInvoices()
+	vendor.CheckInvoices()
+	if len(expired) != 1 || expired[0] != testOrderID {
+		t.Errorf("expected the order's invoice to be reported as expired once, got %v", expired)
+	}
+	if len(*callbacks) != 0 {
+		t.Error("expired invoice was reported as paid")
+	}
+}
+
+func TestVerifyInvoice(t *testing.T) {
+	invoice := &Invoice{
+		Amount:      5000,
+		Description: testOrderID,
+		Expiry:      time.Now().Add(time.Minute),
+	}
+	if err := VerifyInvoice(invoice, testOrderID, 5000); err != nil {
+		t.Error(err)
+	}
+	invoice.Expiry = time.Now().Add(-time.Minute)
+	if err := VerifyInvoice(invoice, testOrderID, 5000); err != ErrInvoiceExpired {
+		t.Errorf("expected ErrInvoiceExpired, got %v", err)
+	}
+	invoice.Expiry = time.Time{}
+	if err := VerifyInvoice(invoice, testOrderID, 5000); err != nil {
+		t.Errorf("invoice without expiry: %s", err)
+	}
+}
@@ -0,0 +1,207 @@
+package channel
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 60 * time.Second
+
+// Client talks to the REST gateway of a payment channel daemon using the
+// lnd API. Requests are authenticated with the daemon's macaroon.
+type Client struct {
+	endpoint string
+	macaroon string
+	client   *http.Client
+}
+
+// NewClient returns a client for the REST gateway at endpoint, such as
+// https://localhost:8080. httpClient carries the daemon's TLS certificate
+// and may be nil.
+func NewClient(endpoint string, macaroon []byte, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: requestTimeout}
+	}
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		macaroon: hex.EncodeToString(macaroon),
+		client:   httpClient,
+	}
+}
+
+type addInvoiceRequest struct {
+	Memo   string `json:"memo"`
+	Value  int64  `json:"value,string"`
+	Expiry int64  `json:"expiry,string"`
+}
+
+type addInvoiceResponse struct {
+	RHash          string `json:"r_hash"`
+	PaymentRequest string `json:"payment_request"`
+}
+
+type invoiceResponse struct {
+	Memo           string `json:"memo"`
+	RHash          string `json:"r_hash"`
+	Value          int64  `json:"value,string"`
+	CreationDate   int64  `json:"creation_date,string"`
+	SettleDate     int64  `json:"settle_date,string"`
+	PaymentRequest string `json:"payment_request"`
+	Expiry         int64  `json:"expiry,string"`
+	State          string `json:"state"`
+}
+
+type payReqResponse struct {
+	PaymentHash string `json:"payment_hash"`
+	NumSatoshis int64  `json:"num_satoshis,string"`
+	Timestamp   int64  `json:"timestamp,string"`
+	Expiry      int64  `json:"expiry,string"`
+	Description string `json:"description"`
+}
+
+type sendPaymentRequest struct {
+	PaymentRequest string `json:"payment_request"`
+}
+
+type sendPaymentResponse struct {
+	PaymentError    string `json:"payment_error"`
+	PaymentPreimage string `json:"payment_preimage"`
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// AddInvoice creates an invoice with the daemon
+func (c *Client) AddInvoice(amount int64, description string, expiry time.Duration) (*Invoice, error) {
+	req := addInvoiceRequest{
+		Memo:   description,
+		Value:  amount,
+		Expiry: int64(expiry / time.Second),
+	}
+	var resp addInvoiceResponse
+	if err := c.do(http.MethodPost, "/v1/invoices", req, &resp); err != nil {
+		return nil, err
+	}
+	hash, err := base64.StdEncoding.DecodeString(resp.RHash)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payment hash: %s", err)
+	}
+	now := time.Now()
+	return &Invoice{
+		PaymentHash:    hex.EncodeToString(hash),
+		PaymentRequest: resp.PaymentRequest,
+		Amount:         amount,
+		Description:    description,
+		Created:        now,
+		Expiry:         now.Add(expiry),
+	}, nil
+}
+
+// LookupInvoice returns an invoice created by the daemon
+func (c *Client) LookupInvoice(paymentHash string) (*Invoice, error) {
+	var resp invoiceResponse
+	if err := c.do(http.MethodGet, "/v1/invoice/"+url.PathEscape(paymentHash), nil, &resp); err != nil {
+		return nil, err
+	}
+	created := time.Unix(resp.CreationDate, 0)
+	invoice := &Invoice{
+		PaymentHash:    paymentHash,
+		PaymentRequest: resp.PaymentRequest,
+		Amount:         resp.Value,
+		Description:    resp.Memo,
+		Created:        created,
+		Expiry:         created.Add(time.Duration(resp.Expiry) * time.Second),
+		Settled:        resp.State == "SETTLED",
+	}
+	if invoice.Settled {
+		invoice.SettledAt = time.Unix(resp.SettleDate, 0)
+	}
+	return invoice, nil
+}
+
+// DecodeInvoice asks the daemon for the terms of a payment request
+func (c *Client) DecodeInvoice(paymentRequest string) (*Invoice, error) {
+	var resp payReqResponse
+	if err := c.do(http.MethodGet, "/v1/payreq/"+url.PathEscape(paymentRequest), nil, &resp); err != nil {
+		return nil, err
+	}
+	created := time.Unix(resp.Timestamp, 0)
+	return &Invoice{
+		PaymentHash:    resp.PaymentHash,
+		PaymentRequest: paymentRequest,
+		Amount:         resp.NumSatoshis,
+		Description:    resp.Description,
+		Created:        created,
+		Expiry:         created.Add(time.Duration(resp.Expiry) * time.Second),
+	}, nil
+}
+
+// PayInvoice pays the payment request over the daemon's channels
+func (c *Client) PayInvoice(paymentRequest string) ([]byte, error) {
+	var resp sendPaymentResponse
+	if err := c.do(http.MethodPost, "/v1/channels/transactions", sendPaymentRequest{paymentRequest}, &resp); err != nil {
+		return nil, err
+	}
+	if resp.PaymentError != "" {
+		return nil, fmt.Errorf("payment failed: %s", resp.PaymentError)
+	}
+	preimage, err := base64.StdEncoding.DecodeString(resp.PaymentPreimage)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payment preimage: %s", err)
+	}
+	return preimage, nil
+}
+
+func (c *Client) do(method, path string, body, result interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, c.endpoint+path, &reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.macaroon != "" {
+		req.Header.Set("Grpc-Metadata-macaroon", c.macaroon)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorResponse
+		json.Unmarshal(b, &e)
+		msg := e.Message
+		if msg == "" {
+			msg = e.Error
+		}
+		if resp.StatusCode == http.StatusNotFound || strings.Contains(msg, "unable to locate invoice") {
+			return ErrInvoiceNotFound
+		}
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return errors.New("payment channel daemon: " + msg + " (" + strconv.Itoa(resp.StatusCode) + ")")
+	}
+	return json.Unmarshal(b, result)
+}
@@ -0,0 +1,186 @@
+package channel
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// regtestNetwork stands in for a regtest network of payment channel
+// daemons. Each node serves the REST gateway of an lnd daemon and pays
+// the other nodes' invoices out of its channel balance.
+type regtestNetwork struct {
+	mtx      sync.Mutex
+	invoices map[string]*regtestInvoice
+	balances map[string]int64
+	servers  []*httptest.Server
+}
+
+type regtestInvoice struct {
+	node           string
+	preimage       []byte
+	hash           []byte
+	paymentRequest string
+	memo           string
+	value          int64
+	created        time.Time
+	expiry         int64
+	settled        time.Time
+}
+
+func newRegtestNetwork() *regtestNetwork {
+	return &regtestNetwork{
+		invoices: make(map[string]*regtestInvoice),
+		balances: make(map[string]int64),
+	}
+}
+
+// addNode starts a daemon with balance in its channels and returns its
+// REST endpoint
+func (n *regtestNetwork) addNode(name string, balance int64, macaroon []byte) string {
+	n.mtx.Lock()
+	n.balances[name] = balance
+	n.mtx.Unlock()
+	s := httptest.NewServer(&regtestNode{n, name, hex.EncodeToString(macaroon)})
+	n.servers = append(n.servers, s)
+	return s.URL
+}
+
+func (n *regtestNetwork) balance(name string) int64 {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	return n.balances[name]
+}
+
+func (n *regtestNetwork) close() {
+	for _, s := range n.servers {
+		s.Close()
+	}
+}
+
+type regtestNode struct {
+	network  *regtestNetwork
+	name     string
+	macaroon string
+}
+
+func (d *regtestNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Grpc-Metadata-macaroon") != d.macaroon {
+		writeError(w, http.StatusUnauthorized, "verification failed: signature mismatch")
+		return
+	}
+	n := d.network
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/invoices":
+		var req addInvoiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		preimage := make([]byte, 32)
+		rand.Read(preimage)
+		hash := sha256.Sum256(preimage)
+		inv := &regtestInvoice{
+			node:           d.name,
+			preimage:       preimage,
+			hash:           hash[:],
+			paymentRequest: fmt.Sprintf("lnbcrt%dn1%x", req.Value, hash),
+			memo:           req.Memo,
+			value:          req.Value,
+			created:        time.Now(),
+			expiry:         req.Expiry,
+		}
+		n.invoices[inv.paymentRequest] = inv
+		json.NewEncoder(w).Encode(addInvoiceResponse{
+			RHash:          base64.StdEncoding.EncodeToString(inv.hash),
+			PaymentRequest: inv.paymentRequest,
+		})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/invoice/"):
+		hash := strings.TrimPrefix(r.URL.Path, "/v1/invoice/")
+		for _, inv := range n.invoices {
+			if inv.node != d.name || hex.EncodeToString(inv.hash) != hash {
+				continue
+			}
+			state := "OPEN"
+			var settleDate int64
+			if !inv.settled.IsZero() {
+				state = "SETTLED"
+				settleDate = inv.settled.Unix()
+			}
+			json.NewEncoder(w).Encode(map[string]string{
+				"memo":            inv.memo,
+				"r_hash":          base64.StdEncoding.EncodeToString(inv.hash),
+				"value":           strconv.FormatInt(inv.value, 10),
+				"creation_date":   strconv.FormatInt(inv.created.Unix(), 10),
+				"settle_date":     strconv.FormatInt(settleDate, 10),
+				"payment_request": inv.paymentRequest,
+				"expiry":          strconv.FormatInt(inv.expiry, 10),
+				"state":           state,
+			})
+			return
+		}
+		writeError(w, http.StatusNotFound, "unable to locate invoice")
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/payreq/"):
+		inv, ok := n.invoices[strings.TrimPrefix(r.URL.Path, "/v1/payreq/")]
+		if !ok {
+			writeError(w, http.StatusInternalServerError, "invalid payment request")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"payment_hash": hex.EncodeToString(inv.hash),
+			"num_satoshis": strconv.FormatInt(inv.value, 10),
+			"timestamp":    strconv.FormatInt(inv.created.Unix(), 10),
+			"expiry":       strconv.FormatInt(inv.expiry, 10),
+			"description":  inv.memo,
+		})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/channels/transactions":
+		var req sendPaymentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		inv, ok := n.invoices[req.PaymentRequest]
+		var paymentError string
+		switch {
+		case !ok:
+			paymentError = "invalid payment request"
+		case !inv.settled.IsZero():
+			paymentError = "invoice is already paid"
+		case n.balances[d.name] < inv.value:
+			paymentError = "insufficient local balance"
+		}
+		if paymentError != "" {
+			json.NewEncoder(w).Encode(sendPaymentResponse{PaymentError: paymentError})
+			return
+		}
+		n.balances[d.name] -= inv.value
+		n.balances[inv.node] += inv.value
+		inv.settled = time.Now()
+		json.NewEncoder(w).Encode(sendPaymentResponse{
+			PaymentPreimage: base64.StdEncoding.EncodeToString(inv.preimage),
+		})
+
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: msg, Message: msg})
+}
@@ -0,0 +1,235 @@
+package channel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("channel")
+
+const (
+	// DefaultInvoiceExpiry is how long the invoice of an order can be paid
+	DefaultInvoiceExpiry = time.Hour
+
+	// DefaultPollInterval is how often the daemon is asked whether the
+	// watched invoices are settled
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Wallet pays and issues order invoices with a payment channel daemon.
+// Payments made and invoices settled are reported to the transaction
+// listeners like on-chain payments of the order's coin.
+type Wallet struct {
+	daemon       Daemon
+	coin         string
+	expiry       time.Duration
+	pollInterval time.Duration
+
+	mtx             sync.Mutex
+	watched         map[string]watchedInvoice
+	listeners       []func(wallet.TransactionCallback)
+	expiryListeners []func(orderID string)
+	shutdown        chan struct{}
+}
+
+type watchedInvoice struct {
+	orderID        string
+	paymentRequest string
+	expiry         time.Time
+}
+
+// NewWallet returns a wallet for the daemon's channels in coin. Invoices
+// it issues expire after expiry, or DefaultInvoiceExpiry when zero.
+func NewWallet(daemon Daemon, coin string, expiry time.Duration) *Wallet {
+	if expiry <= 0 {
+		expiry = DefaultInvoiceExpiry
+	}
+	return &Wallet{
+		daemon:       daemon,
+		coin:         coin,
+		expiry:       expiry,
+		pollInterval: DefaultPollInterval,
+		watched:      make(map[string]watchedInvoice),
+		shutdown:     make(chan struct{}),
+	}
+}
+
+// Coin returns the currency code of the daemon's channels
+func (w *Wallet) Coin() string {
+	return w.coin
+}
+
+// AddTransactionListener registers a callback for payments of watched
+// invoices and of invoices we pay
+func (w *Wallet) AddTransactionListener(callback func(wallet.TransactionCallback)) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.listeners = append(w.listeners, callback)
+}
+
+// AddExpiryListener registers a callback for the orders whose watched
+// invoice expired unpaid
+func (w *Wallet) AddExpiryListener(callback func(orderID string)) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.expiryListeners = append(w.expiryListeners, callback)
+}
+
+// CreateInvoice issues an invoice for the order's total, bound to the
+// order by its description, and watches it until it is settled
+func (w *Wallet) CreateInvoice(orderID string, amount int64) (*Invoice, error) {
+	invoice, err := w.daemon.AddInvoice(amount, orderID, w.expiry)
+	if err != nil {
+		return nil, err
+	}
+	w.watch(invoice, orderID)
+	return invoice, nil
+}
+
+// Watch watches an invoice issued earlier for the order, as when the node
+// restarts before it is paid
+func (w *Wallet) Watch(orderID, paymentRequest string) error {
+	invoice, err := w.daemon.DecodeInvoice(paymentRequest)
+	if err != nil {
+		return err
+	}
+	if invoice.Description != orderID {
+		return ErrInvoiceOrderMismatch
+	}
+	w.watch(invoice, orderID)
+	return nil
+}
+
+// CheckInvoice decodes the vendor's invoice and checks that it is for the
+// order and its total
+func (w *Wallet) CheckInvoice(orderID, paymentRequest string, amount int64) (*Invoice, error) {
+	invoice, err := w.daemon.DecodeInvoice(paymentRequest)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyInvoice(invoice, orderID, amount); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// PayInvoice checks that the vendor's invoice is for the order and its
+// total and pays it. The payment is reported to the listeners once made.
+func (w *Wallet) PayInvoice(orderID, paymentRequest string, amount int64) error {
+	invoice, err := w.CheckInvoice(orderID, paymentRequest, amount)
+	if err != nil {
+		return err
+	}
+	if _, err := w.daemon.PayInvoice(paymentRequest); err != nil {
+		return err
+	}
+	w.notify(orderID, invoice, -invoice.Amount, time.Now())
+	return nil
+}
+
+// Start polls the daemon for the settlement of watched invoices until the
+// wallet is closed
+func (w *Wallet) Start() {
+	t := time.NewTicker(w.pollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.CheckInvoices()
+		case <-w.shutdown:
+			return
+		}
+	}
+}
+
+// Close stops polling the daemon
+func (w *Wallet) Close() {
+	close(w.shutdown)
+}
+
+// CheckInvoices reports the watched invoices which were settled and the
+// ones which expired unpaid, and stops watching them
+func (w *Wallet) CheckInvoices() {
+	w.mtx.Lock()
+	watched := make(map[string]watchedInvoice, len(w.watched))
+	for hash, wi := range w.watched {
+		watched[hash] = wi
+	}
+	w.mtx.Unlock()
+
+	now := time.Now()
+	for hash, wi := range watched {
+		invoice, err := w.daemon.LookupInvoice(hash)
+		if err != nil {
+			if err == ErrInvoiceNotFound {
+				w.unwatch(hash)
+			}
+			log.Warningf("Looking up the invoice of order %s: %s", wi.orderID, err)
+			continue
+		}
+		if invoice.Settled {
+			w.unwatch(hash)
+			log.Infof("Invoice of order %s was settled", wi.orderID)
+			invoice.PaymentRequest = wi.paymentRequest
+			w.notify(wi.orderID, invoice, invoice.Amount, invoice.SettledAt)
+			continue
+		}
+		if !wi.expiry.IsZero() && now.After(wi.expiry) {
+			w.unwatch(hash)
+			log.Infof("Invoice of order %s expired unpaid", wi.orderID)
+			w.notifyExpired(wi.orderID)
+		}
+	}
+}
+
+func (w *Wallet) watch(invoice *Invoice, orderID string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.watched[invoice.PaymentHash] = watchedInvoice{
+		orderID:        orderID,
+		paymentRequest: invoice.PaymentRequest,
+		expiry:         invoice.Expiry,
+	}
+}
+
+func (w *Wallet) unwatch(paymentHash string) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	delete(w.watched, paymentHash)
+}
+
+// notify reports a payment of the invoice as a transaction with one output
+// paying the order. value is negative for invoices we paid.
+func (w *Wallet) notify(orderID string, invoice *Invoice, value int64, timestamp time.Time) {
+	cb := wallet.TransactionCallback{
+		Txid: invoice.PaymentHash,
+		Outputs: []wallet.TransactionOutput{{
+			Address: InvoiceAddress(invoice.PaymentRequest),
+			Value:   invoice.Amount,
+			OrderID: orderID,
+		}},
+		Value:     value,
+		Timestamp: timestamp,
+		BlockTime: timestamp,
+	}
+	w.mtx.Lock()
+	listeners := make([]func(wallet.TransactionCallback), len(w.listeners))
+	copy(listeners, w.listeners)
+	w.mtx.Unlock()
+	for _, l := range listeners {
+		l(cb)
+	}
+}
+
+func (w *Wallet) notifyExpired(orderID string) {
+	w.mtx.Lock()
+	listeners := make([]func(string), len(w.expiryListeners))
+	copy(listeners, w.expiryListeners)
+	w.mtx.Unlock()
+	for _, l := range listeners {
+		l(orderID)
+	}
+}
@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/wallet/channel"
+)
+
+// NewPaymentChannel returns a wallet for the configured payment channel
+// daemon. The daemon runs locally so it is never dialed through the proxy.
+func NewPaymentChannel(channelConfig *schema.PaymentChannelConfig, params *chaincfg.Params) (*channel.Wallet, error) {
+	if channelConfig.Endpoint == "" {
+		return nil, errors.New("no payment channel daemon endpoint configured")
+	}
+	code := strings.ToUpper(channelConfig.Coin)
+	if code == "" {
+		return nil, errors.New("no payment channel coin configured")
+	}
+	if params.Name != chaincfg.MainNetParams.Name {
+		code = "T" + code
+	}
+
+	var expiry time.Duration
+	if channelConfig.InvoiceExpiry != "" {
+		var err error
+		expiry, err = time.ParseDuration(channelConfig.InvoiceExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice expiry: %s", err)
+		}
+	}
+
+	var macaroon []byte
+	if channelConfig.Macaroon != "" {
+		var err error
+		macaroon, err = ioutil.ReadFile(channelConfig.Macaroon)
+		if err != nil {
+			return nil, fmt.Errorf("reading macaroon: %s", err)
+		}
+	}
+
+	httpClient := &http.Client{Timeout: time.Minute}
+	if channelConfig.TLSCert != "" {
+		cert, err := ioutil.ReadFile(channelConfig.TLSCert)
+		if err != nil {
+			return nil, fmt.Errorf("reading TLS certificate: %s", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cert) {
+			return nil, errors.New("invalid TLS certificate")
+		}
+		httpClient.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+
+	client := channel.NewClient(channelConfig.Endpoint, macaroon, httpClient)
+	return channel.NewWallet(client, code, expiry), nil
+}
@@ -0,0 +1,278 @@
+// Package exchangerates prices coins with several exchange rate feeds. The
+// rate used is the median of the feeds which agree with each other, and no
+// rate is given when the feeds are stale or disagree.
+package exchangerates
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("exchangerates")
+
+const (
+	// DefaultMaxDeviation is how far, as a fraction of the median, a feed's
+	// rate may be from the median and still count as agreeing with it
+	DefaultMaxDeviation = 0.05
+
+	// DefaultMaxAge is the age after which a feed's rates are stale
+	DefaultMaxAge = time.Duration(1) * time.Hour
+
+	// DefaultCacheTTL is how long the feeds' rates are reused before they
+	// are fetched again
+	DefaultCacheTTL = time.Duration(1) * time.Minute
+)
+
+var (
+	// ErrNoRates is returned when no feed has a rate for the currency
+	ErrNoRates = errors.New("exchangerates: no feed has a rate for the currency")
+
+	// ErrStaleRates is returned when every feed's rate for the currency is
+	// older than the maximum age
+	ErrStaleRates = errors.New("exchangerates: the exchange rates are stale")
+
+	// ErrTooFewRates is returned when fewer feeds than required have a
+	// fresh rate for the currency
+	ErrTooFewRates = errors.New("exchangerates: too few feeds have a rate for the currency")
+
+	// ErrRatesDisagree is returned when no majority of the feeds agree on
+	// the rate
+	ErrRatesDisagree = errors.New("exchangerates: the exchange rate feeds disagree")
+)
+
+// Rates are a feed's values of one coin keyed by currency code, and the time
+// they were published
+type Rates struct {
+	Values    map[string]float64
+	Timestamp time.Time
+}
+
+// Provider is an exchange rate feed
+type Provider interface {
+	// Name identifies the feed in logs
+	Name() string
+
+	// Rates returns the feed's current values of the coin
+	Rates(coin string) (*Rates, error)
+}
+
+// Options are the bounds the feeds are held to. Zero values take the
+// defaults.
+type Options struct {
+	MaxDeviation float64
+	MaxAge       time.Duration
+	CacheTTL     time.Duration
+
+	// MinSources is the number of feeds which must have a fresh rate,
+	// one by default
+	MinSources int
+}
+
+// Feeds aggregates the rates of several providers
+type Feeds struct {
+	providers []Provider
+	opts      Options
+
+	mtx   sync.Mutex
+	coins map[string]*CoinRates
+}
+
+// New returns the aggregate of the providers' rates
+func New(providers []Provider, opts Options) *Feeds {
+	if opts.MaxDeviation <= 0 {
+		opts.MaxDeviation = DefaultMaxDeviation
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = DefaultMaxAge
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = DefaultCacheTTL
+	}
+	if opts.MinSources <= 0 {
+		opts.MinSources = 1
+	}
+	return &Feeds{providers: providers, opts: opts, coins: make(map[string]*CoinRates)}
+}
+
+// ForCoin returns the aggregated rates of a coin with the number of its
+// smallest units in a coin
+func (f *Feeds) ForCoin(coin string, unitsPerCoin int) *CoinRates {
+	coin = strings.ToUpper(coin)
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	c, ok := f.coins[coin]
+	if !ok {
+		c = &CoinRates{feeds: f, coin: coin, unitsPerCoin: unitsPerCoin}
+		f.coins[coin] = c
+	}
+	return c
+}
+
+// CoinRates are the aggregated rates of one coin. It implements
+// wallet.ExchangeRates.
+type CoinRates struct {
+	feeds        *Feeds
+	coin         string
+	unitsPerCoin int
+
+	mtx     sync.Mutex
+	fetched time.Time
+	rates   []*Rates
+}
+
+var _ wallet.ExchangeRates = (*CoinRates)(nil)
+
+// GetExchangeRate returns the aggregated rate of the coin in the currency,
+// fetching the feeds again when the cached rates are too old
+func (c *CoinRates) GetExchangeRate(currencyCode string) (float64, error) {
+	return aggregate(c.fetch(false), currencyCode, c.feeds.opts, time.Now())
+}
+
+// GetLatestRate fetches the feeds and returns the aggregated rate of the
+// coin in the currency. An empty currency code only refreshes the feeds.
+func (c *CoinRates) GetLatestRate(currencyCode string) (float64, error) {
+	rates := c.fetch(true)
+	if currencyCode == "" {
+		return 0, nil
+	}
+	return aggregate(rates, currencyCode, c.feeds.opts, time.Now())
+}
+
+// GetAllRates returns the aggregated rate of each currency the feeds agree
+// on
+func (c *CoinRates) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	rates := c.fetch(!cacheOK)
+	now := time.Now()
+	currencies := make(map[string]bool)
+	for _, r := range rates {
+		for code := range r.Values {
+			currencies[strings.ToUpper(code)] = true
+		}
+	}
+	ret := make(map[string]float64)
+	for code := range currencies {
+		if rate, err := aggregate(rates, code, c.feeds.opts, now); err == nil {
+			ret[code] = rate
+		}
+	}
+	if len(ret) == 0 {
+		return nil, ErrNoRates
+	}
+	return ret, nil
+}
+
+// UnitsPerCoin returns the number of the coin's smallest units in a coin
+func (c *CoinRates) UnitsPerCoin() int {
+	return c.unitsPerCoin
+}
+
+func (c *CoinRates) fetch(force bool) []*Rates {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if !force && c.rates != nil && time.Since(c.fetched) < c.feeds.opts.CacheTTL {
+		return c.rates
+	}
+	var (
+		wg    sync.WaitGroup
+		mtx   sync.Mutex
+		rates []*Rates
+	)
+	for _, p := range c.feeds.providers {
+		wg.Add(1)
+		go func(p Provider) {
+			defer wg.Done()
+			r, err := p.Rates(c.coin)
+			if err != nil {
+				log.Warningf("fetching %s rates from %s: %s", c.coin, p.Name(), err)
+				return
+			}
+			mtx.Lock()
+			rates = append(rates, r)
+			mtx.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	c.rates = rates
+	c.fetched = time.Now()
+	return rates
+}
+
+// aggregate returns the median of the fresh rates for the currency which
+// agree with the median of them all, provided they are a majority
+func aggregate(rates []*Rates, currencyCode string, opts Options, now time.Time) (float64, error) {
+	currencyCode = strings.ToUpper(currencyCode)
+	var (
+		values []float64
+		found  bool
+	)
+	for _, r := range rates {
+		value, ok := lookup(r.Values, currencyCode)
+		if !ok || value <= 0 {
+			continue
+		}
+		found = true
+		if now.Sub(r.Timestamp) > opts.MaxAge {
+			continue
+		}
+		values = append(values, value)
+	}
+	switch {
+	case !found:
+		return 0, ErrNoRates
+	case len(values) == 0:
+		return 0, ErrStaleRates
+	case len(values) < opts.MinSources:
+		return 0, ErrTooFewRates
+	}
+
+	m := median(values)
+	var agreeing []float64
+	for _, v := range values {
+		if deviation(v, m) <= opts.MaxDeviation {
+			agreeing = append(agreeing, v)
+		}
+	}
+	if len(agreeing)*2 <= len(values) && len(values) > 1 {
+		return 0, ErrRatesDisagree
+	}
+	if len(agreeing) < opts.MinSources {
+		return 0, ErrTooFewRates
+	}
+	return median(agreeing), nil
+}
+
+func lookup(values map[string]float64, currencyCode string) (float64, bool) {
+	if v, ok := values[currencyCode]; ok {
+		return v, true
+	}
+	for code, v := range values {
+		if strings.EqualFold(code, currencyCode) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func deviation(v, m float64) float64 {
+	d := (v - m) / m
+	if d < 0 {
+		return -d
+	}
+	return d
+}
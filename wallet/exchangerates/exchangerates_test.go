@@ -0,0 +1,151 @@
+package exchangerates
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixedProvider is a feed with fixed rates
+type fixedProvider struct {
+	name  string
+	rates *Rates
+	err   error
+}
+
+func (p *fixedProvider) Name() string                      { return p.name }
+func (p *fixedProvider) Rates(coin string) (*Rates, error) { return p.rates, p.err }
+
+func feed(usd float64, age time.Duration) *fixedProvider {
+	return &fixedProvider{
+		name:  fmt.Sprintf("feed-%f", usd),
+		rates: &Rates{Values: map[string]float64{"USD": usd}, Timestamp: time.Now().Add(-age)},
+	}
+}
+
+func TestAggregateMedian(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		providers []Provider
+		opts      Options
+		rate      float64
+		err       error
+	}{
+		{"single", []Provider{feed(100, 0)}, Options{}, 100, nil},
+		{"median", []Provider{feed(100, 0), feed(102, 0), feed(101, 0)}, Options{}, 101, nil},
+		{"even", []Provider{feed(100, 0), feed(102, 0)}, Options{}, 101, nil},
+		{"outlier dropped", []Provider{feed(100, 0), feed(101, 0), feed(102, 0), feed(500, 0)}, Options{}, 101, nil},
+		{"stale dropped", []Provider{feed(100, 0), feed(200, 2*time.Hour)}, Options{}, 100, nil},
+		{"failed feed skipped", []Provider{feed(100, 0), &fixedProvider{name: "down", err: fmt.Errorf("down")}}, Options{}, 100, nil},
+		{"disagree", []Provider{feed(100, 0), feed(150, 0)}, Options{}, 0, ErrRatesDisagree},
+		{"wider bound", []Provider{feed(100, 0), feed(150, 0)}, Options{MaxDeviation: 0.5}, 125, nil},
+		{"stale", []Provider{feed(100, 2*time.Hour)}, Options{}, 0, ErrStaleRates},
+		{"max age", []Provider{feed(100, 2*time.Hour)}, Options{MaxAge: 3 * time.Hour}, 100, nil},
+		{"too few", []Provider{feed(100, 0), feed(101, time.Hour*2)}, Options{MinSources: 2}, 0, ErrTooFewRates},
+		{"none", nil, Options{}, 0, ErrNoRates},
+	} {
+		rates := New(c.providers, c.opts).ForCoin("BTC", 100000000)
+		rate, err := rates.GetExchangeRate("usd")
+		if err != c.err {
+			t.Errorf("%s: expected error %v, got %v", c.name, c.err, err)
+			continue
+		}
+		if rate != c.rate {
+			t.Errorf("%s: expected rate %f, got %f", c.name, c.rate, rate)
+		}
+	}
+}
+
+func TestGetAllRatesSkipsDisagreement(t *testing.T) {
+	now := time.Now()
+	rates := New([]Provider{
+		&fixedProvider{name: "a", rates: &Rates{Values: map[string]float64{"USD": 100, "EUR": 90}, Timestamp: now}},
+		&fixedProvider{name: "b", rates: &Rates{Values: map[string]float64{"USD": 101, "EUR": 200}, Timestamp: now}},
+	}, Options{}).ForCoin("BTC", 100000000)
+	all, err := rates.GetAllRates(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all["USD"] != 100.5 {
+		t.Errorf("expected only the agreed USD rate, got %v", all)
+	}
+	if rates.UnitsPerCoin() != 100000000 {
+		t.Errorf("unexpected units per coin %d", rates.UnitsPerCoin())
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exchangerates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rates.json")
+	doc := fmt.Sprintf(`{"timestamp": %d, "rates": {"BTC": {"USD": 4000}}}`, time.Now().Unix())
+	if err := ioutil.WriteFile(path, []byte(doc), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewFileProvider(path)
+	r, err := p.Rates("TBTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Values["USD"] != 4000 {
+		t.Errorf("expected the testnet coin to use the BTC rates, got %v", r.Values)
+	}
+	if _, err := p.Rates("LTC"); err != ErrCoinNotListed {
+		t.Errorf("expected an unlisted coin to error, got %v", err)
+	}
+
+	stale := `{"timestamp": 1000, "rates": {"BTC": {"USD": 4000}}}`
+	if err := ioutil.WriteFile(path, []byte(stale), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New([]Provider{p}, Options{}).ForCoin("BTC", 100000000).GetLatestRate("USD"); err != ErrStaleRates {
+		t.Errorf("expected an old document to be stale, got %v", err)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	usd := 4000
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"rates": {"BTC": {"USD": %d}}}`, usd)
+	}))
+	defer ts.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+
+	if _, err := NewHTTPProvider(down.URL, nil).Rates("BTC"); err == nil {
+		t.Error("expected a failing server to error")
+	}
+
+	rates := New([]Provider{
+		NewHTTPProvider(ts.URL, nil),
+		NewHTTPProvider(down.URL, nil),
+		feed(4040, 0),
+	}, Options{}).ForCoin("BTC", 100000000)
+	rate, err := rates.GetExchangeRate("USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 4020 {
+		t.Errorf("expected the median of the live feeds, got %f", rate)
+	}
+
+	// Cached rates are reused until the feeds are refreshed
+	usd = 8000
+	if rate, _ := rates.GetExchangeRate("USD"); rate != 4020 {
+		t.Errorf("expected the cached rate, got %f", rate)
+	}
+	if _, err := rates.GetLatestRate("USD"); err != ErrRatesDisagree {
+		t.Errorf("expected the refreshed feeds to disagree, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package exchangerates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+)
+
+// ErrCoinNotListed is returned by a feed which has no rates for the coin
+var ErrCoinNotListed = errors.New("exchangerates: the feed has no rates for the coin")
+
+// feedDocument is the document file and HTTP feeds serve. Rates are keyed by
+// coin and then by currency code, and the timestamp is a unix time.
+//
+//	{"timestamp": 1546300800, "rates": {"BTC": {"USD": 3800.5, "EUR": 3300}}}
+type feedDocument struct {
+	Timestamp int64                         `json:"timestamp"`
+	Rates     map[string]map[string]float64 `json:"rates"`
+}
+
+// parseFeed reads a feed document. A document without a timestamp is taken
+// to be as old as fallback.
+func parseFeed(r io.Reader, coin string, fallback time.Time) (*Rates, error) {
+	var doc feedDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	values, ok := findCoin(doc.Rates, coin)
+	if !ok {
+		return nil, ErrCoinNotListed
+	}
+	timestamp := fallback
+	if doc.Timestamp > 0 {
+		timestamp = time.Unix(doc.Timestamp, 0)
+	}
+	return &Rates{Values: values, Timestamp: timestamp}, nil
+}
+
+// findCoin looks the coin up by its code, falling back to the code without
+// the testnet "T" prefix
+func findCoin(rates map[string]map[string]float64, coin string) (map[string]float64, bool) {
+	coin = strings.ToUpper(coin)
+	candidates := []string{coin}
+	if strings.HasPrefix(coin, "T") && len(coin) > 1 {
+		candidates = append(candidates, coin[1:])
+	}
+	for _, c := range candidates {
+		for code, values := range rates {
+			if strings.EqualFold(code, c) {
+				return values, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// FileProvider reads rates from a JSON file on disk. It is meant for tests
+// and for nodes which fetch their rates out of band.
+type FileProvider struct {
+	Path string
+}
+
+// NewFileProvider returns a feed reading the file at the path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+func (p *FileProvider) Name() string { return "file:" + p.Path }
+
+func (p *FileProvider) Rates(coin string) (*Rates, error) {
+	f, err := os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(f, coin, info.ModTime())
+}
+
+// HTTPProvider fetches rates as a JSON document from a URL
+type HTTPProvider struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPProvider returns a feed fetching the document at the URL
+func NewHTTPProvider(url string, client *http.Client) *HTTPProvider {
+	if client == nil {
+		client = &http.Client{Timeout: time.Second * 30}
+	}
+	return &HTTPProvider{URL: url, Client: client}
+}
+
+func (p *HTTPProvider) Name() string { return p.URL }
+
+func (p *HTTPProvider) Rates(coin string) (*Rates, error) {
+	resp, err := p.Client.Get(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("exchangerates: %s returned status %d", p.URL, resp.StatusCode)
+	}
+	return parseFeed(resp.Body, coin, time.Now())
+}
+
+// WalletProvider takes the rates from a wallet's built-in exchange rate
+// provider. Lookup returns the provider of a coin, or nil when the coin has
+// none.
+type WalletProvider struct {
+	Lookup func(coin string) wallet.ExchangeRates
+}
+
+// NewWalletProvider returns a feed of the wallets' built-in rates
+func NewWalletProvider(lookup func(coin string) wallet.ExchangeRates) *WalletProvider {
+	return &WalletProvider{Lookup: lookup}
+}
+
+func (p *WalletProvider) Name() string { return "wallet" }
+
+func (p *WalletProvider) Rates(coin string) (*Rates, error) {
+	rates := p.Lookup(coin)
+	if rates == nil {
+		return nil, ErrCoinNotListed
+	}
+	values, err := rates.GetAllRates(true)
+	if err != nil {
+		return nil, err
+	}
+	// The wallets' providers keep their own cache fresh and don't say how
+	// old their rates are.
+	return &Rates{Values: values, Timestamp: time.Now()}, nil
+}
@@ -0,0 +1,239 @@
+package bitcoin
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+)
+
+const (
+	// FundingCheckRegularInterval is how often the payments of open orders
+	// are checked against the chain
+	FundingCheckRegularInterval = time.Minute
+
+	// FundingCheckTestingInterval is used instead on testnet
+	FundingCheckTestingInterval = 10 * time.Second
+)
+
+// fundingStates are the states of orders whose funding may still change
+var fundingStates = []pb.OrderState{
+	pb.OrderState_AWAITING_PAYMENT,
+	pb.OrderState_PENDING,
+	pb.OrderState_AWAITING_FULFILLMENT,
+	pb.OrderState_FULFILLED,
+}
+
+// requiredConfirmations returns how many confirmations the payments of an
+// order need before the order is funded
+func (l *TransactionListener) requiredConfirmations(contract *pb.RicardianContract) uint32 {
+	if l.confirmations == nil || contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL {
+		return 0
+	}
+	return l.confirmations.Required(contract.BuyerOrder.Payment.Coin, l.testnet, contract.BuyerOrder.Payment.Amount)
+}
+
+// paymentConfirmations returns the confirmations of a payment. Only a payment
+// the wallet marked dead was reorged out or double spent. A payment missing
+// from the wallet, as after a restore or a resync, has no confirmations until
+// the wallet sees it again. Any other error of the wallet is returned.
+func paymentConfirmations(wal wallet.Wallet, txid string) (confirmations uint32, dead bool, err error) {
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return 0, false, err
+	}
+	txn, err := wal.GetTransaction(*hash)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if txn.Height < 0 {
+		return 0, true, nil
+	}
+	if txn.Height == 0 {
+		return 0, false, nil
+	}
+	confirmations, _, err = wal.GetConfirmations(*hash)
+	return confirmations, false, err
+}
+
+// confirmedFunding sums the payments of an order with the confirmations it
+// requires, less what was spent from its payment address
+func (l *TransactionListener) confirmedFunding(wal wallet.Wallet, contract *pb.RicardianContract, records []*wallet.TransactionRecord) (int64, error) {
+	required := l.requiredConfirmations(contract)
+	var funding int64
+	for _, r := range records {
+		if r.Value <= 0 || required == 0 {
+			funding += r.Value
+			continue
+		}
+		confirmations, dead, err := paymentConfirmations(wal, r.Txid)
+		if err != nil {
+			return 0, err
+		}
+		if dead || confirmations < required {
+			continue
+		}
+		funding += r.Value
+	}
+	return funding, nil
+}
+
+// fundingConfirmed returns true if the confirmed payments of an order cover
+// its amount
+func (l *TransactionListener) fundingConfirmed(contract *pb.RicardianContract, records []*wallet.TransactionRecord) bool {
+	if l.requiredConfirmations(contract) == 0 {
+		return true
+	}
+	wal, err := l.multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		log.Errorf("loading the wallet of order payment: %s", err)
+		return false
+	}
+	funding, err := l.confirmedFunding(wal, contract, records)
+	if err != nil {
+		log.Errorf("checking the confirmations of order payment: %s", err)
+		return false
+	}
+	return funding >= int64(contract.BuyerOrder.Payment.Amount)
+}
+
+// CheckFunding funds the open orders whose payments have since confirmed
+// and revokes the funding of those whose payments were reorged out or
+// double spent
+func (l *TransactionListener) CheckFunding() error {
+	l.Lock()
+	defer l.Unlock()
+	sales, _, err := l.db.Sales().GetAll(fundingStates, "", false, false, -1, nil)
+	if err != nil {
+		return err
+	}
+	for _, sale := range sales {
+		l.checkFunding(sale.OrderId, true)
+	}
+	purchases, _, err := l.db.Purchases().GetAll(fundingStates, "", false, false, -1, nil)
+	if err != nil {
+		return err
+	}
+	for _, purchase := range purchases {
+		l.checkFunding(purchase.OrderId, false)
+	}
+	return nil
+}
+
+func (l *TransactionListener) checkFunding(orderID string, isSale bool) {
+	contract, state, funded, records, err := l.getOrderDetails(orderID, nil, isSale)
+	if err != nil || contract.BuyerOrder == nil || contract.BuyerOrder.Payment == nil {
+		return
+	}
+	if contract.BuyerOrder.Payment.Method == pb.Order_Payment_PAYMENT_CHANNEL || len(records) == 0 {
+		return
+	}
+	wal, err := l.multiwallet.WalletForCurrencyCode(contract.BuyerOrder.Payment.Coin)
+	if err != nil {
+		return
+	}
+
+	var (
+		live    []*wallet.TransactionRecord
+		revoked []string
+		funding int64
+	)
+	for _, r := range records {
+		if r.Value > 0 {
+			_, dead, err := paymentConfirmations(wal, r.Txid)
+			if err != nil {
+				log.Errorf("checking payment %s of order %s: %s", r.Txid, orderID, err)
+				return
+			}
+			if dead {
+				revoked = append(revoked, r.Txid)
+				continue
+			}
+		}
+		live = append(live, r)
+		funding += r.Value
+	}
+
+	requestedAmount := int64(contract.BuyerOrder.Payment.Amount)
+	var confirmed int64
+	if !funded && state == pb.OrderState_AWAITING_PAYMENT {
+		if confirmed, err = l.confirmedFunding(wal, contract, live); err != nil {
+			log.Errorf("checking the confirmations of order %s: %s", orderID, err)
+			return
+		}
+	}
+	switch {
+	case funded && len(revoked) > 0 && funding < requestedAmount:
+		log.Warningf("Payment for order %s was reversed", orderID)
+		l.revokeFunding(orderID, contract, state, isSale, live, revoked)
+	case !funded && state == pb.OrderState_AWAITING_PAYMENT && confirmed >= requestedAmount:
+		log.Debugf("Payment for order %s confirmed", orderID)
+		if isSale {
+			l.fundSale(orderID, contract, state)
+			l.db.Sales().UpdateFunding(orderID, true, live)
+		} else {
+			l.fundPurchase(orderID, contract, state)
+			l.db.Purchases().UpdateFunding(orderID, true, live)
+		}
+	case len(revoked) > 0:
+		if isSale {
+			l.db.Sales().UpdateFunding(orderID, funded, live)
+		} else {
+			l.db.Purchases().UpdateFunding(orderID, funded, live)
+		}
+	}
+}
+
+// revokeFunding returns an order whose payment was reversed to awaiting
+// payment, and its items to the inventory, unless it was already fulfilled.
+// The user is notified either way.
+func (l *TransactionListener) revokeFunding(orderID string, contract *pb.RicardianContract, state pb.OrderState, isSale bool, records []*wallet.TransactionRecord, revoked []string) {
+	unfund := state == pb.OrderState_PENDING || state == pb.OrderState_AWAITING_FULFILLMENT
+	if isSale {
+		if unfund {
+			l.db.Sales().Put(orderID, *contract, pb.OrderState_AWAITING_PAYMENT, false)
+			l.adjustInventory(contract, true)
+		}
+		l.db.Sales().UpdateFunding(orderID, false, records)
+	} else {
+		if unfund {
+			l.db.Purchases().Put(orderID, *contract, pb.OrderState_AWAITING_PAYMENT, false)
+		}
+		l.db.Purchases().UpdateFunding(orderID, false, records)
+	}
+
+	var thumbnail repo.Thumbnail
+	if len(contract.VendorListings) > 0 && contract.VendorListings[0].Item != nil && len(contract.VendorListings[0].Item.Images) > 0 {
+		thumbnail = repo.Thumbnail{
+			Tiny:  contract.VendorListings[0].Item.Images[0].Tiny,
+			Small: contract.VendorListings[0].Item.Images[0].Small,
+		}
+	}
+	n := repo.FundingRevokedNotification{
+		ID:        repo.NewNotificationID(),
+		Type:      repo.NotifierTypeFundingRevoked,
+		OrderID:   orderID,
+		Txids:     revoked,
+		Thumbnail: thumbnail,
+	}
+	l.broadcast <- n
+	l.db.Notifications().PutRecord(repo.NewNotification(n, time.Now(), false))
+}
+
+// StartFundingMonitor checks the funding of open orders every interval
+func (l *TransactionListener) StartFundingMonitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			if err := l.CheckFunding(); err != nil {
+				log.Errorf("checking order funding: %s", err)
+			}
+		}
+	}()
+}
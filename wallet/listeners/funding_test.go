@@ -0,0 +1,98 @@
+package bitcoin
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/phoreproject/multiwallet"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/pb"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/test"
+	"github.com/phoreproject/openbazaar-go/test/factory"
+)
+
+const fundingTestTxid = "a8f5b9f8d3ba6b4cb2a5be1c0e1d4a4ea0e2b2b0d9a3c3d4e5f60718293a4b5c"
+
+// fundingTestWallet reports a single payment at height, with confirmations,
+// or fails with err
+type fundingTestWallet struct {
+	wallet.Wallet
+	height        int32
+	confirmations uint32
+	err           error
+}
+
+func (w *fundingTestWallet) CurrencyCode() string { return "BTC" }
+
+func (w *fundingTestWallet) GetTransaction(txid chainhash.Hash) (wallet.Txn, error) {
+	if w.err != nil {
+		return wallet.Txn{}, w.err
+	}
+	return wallet.Txn{Txid: txid.String(), Height: w.height}, nil
+}
+
+func (w *fundingTestWallet) GetConfirmations(txid chainhash.Hash) (uint32, uint32, error) {
+	return w.confirmations, uint32(w.height), nil
+}
+
+func newFundingTestListener(t *testing.T, wal wallet.Wallet, orderID string, funded bool, state pb.OrderState) *TransactionListener {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	contract := factory.NewContract()
+	if err := node.Datastore.Purchases().Put(orderID, *contract, state, false); err != nil {
+		t.Fatal(err)
+	}
+	records := []*wallet.TransactionRecord{{Txid: fundingTestTxid, Value: int64(contract.BuyerOrder.Payment.Amount)}}
+	if err := node.Datastore.Purchases().UpdateFunding(orderID, funded, records); err != nil {
+		t.Fatal(err)
+	}
+	confirmations := &schema.FundingConfirmationsConfig{
+		Default: []schema.ConfirmationThreshold{{MinValue: 0, Confirmations: 2}},
+	}
+	mw := multiwallet.MultiWallet{util.ExtendCoinType(wallet.Bitcoin): wal}
+	return &TransactionListener{make(chan repo.Notifier, 10), node.Datastore, mw, confirmations, false, new(sync.Mutex)}
+}
+
+func TestCheckFunding(t *testing.T) {
+	examples := []struct {
+		name   string
+		wallet *fundingTestWallet
+		funded bool
+		state  pb.OrderState
+		expect pb.OrderState
+	}{
+		{"confirmed", &fundingTestWallet{height: 100, confirmations: 2}, false, pb.OrderState_AWAITING_PAYMENT, pb.OrderState_PENDING},
+		{"below threshold", &fundingTestWallet{height: 100, confirmations: 1}, false, pb.OrderState_AWAITING_PAYMENT, pb.OrderState_AWAITING_PAYMENT},
+		{"unconfirmed", &fundingTestWallet{height: 0}, false, pb.OrderState_AWAITING_PAYMENT, pb.OrderState_AWAITING_PAYMENT},
+		{"reorged out", &fundingTestWallet{height: -1}, true, pb.OrderState_PENDING, pb.OrderState_AWAITING_PAYMENT},
+		{"not found", &fundingTestWallet{err: sql.ErrNoRows}, true, pb.OrderState_PENDING, pb.OrderState_PENDING},
+		{"not found unfunded", &fundingTestWallet{err: sql.ErrNoRows}, false, pb.OrderState_AWAITING_PAYMENT, pb.OrderState_AWAITING_PAYMENT},
+		{"wallet error", &fundingTestWallet{err: errors.New("database is locked")}, true, pb.OrderState_PENDING, pb.OrderState_PENDING},
+		{"wallet error unfunded", &fundingTestWallet{err: errors.New("database is locked")}, false, pb.OrderState_AWAITING_PAYMENT, pb.OrderState_AWAITING_PAYMENT},
+	}
+	for i, e := range examples {
+		// The test repository is shared between runs so the order ID is unique
+		orderID := "funding-order-" + string(rune('a'+i))
+		l := newFundingTestListener(t, e.wallet, orderID, e.funded, e.state)
+		l.checkFunding(orderID, false)
+
+		_, state, funded, _, _, _, err := l.db.Purchases().GetByOrderId(orderID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != e.expect {
+			t.Errorf("%s: expected the order to be %s, got %s", e.name, e.expect, state)
+		}
+		if expectFunded := e.expect == pb.OrderState_PENDING; funded != expectFunded {
+			t.Errorf("%s: expected funded to be %t, got %t", e.name, expectFunded, funded)
+		}
+	}
+}
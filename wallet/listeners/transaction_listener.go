@@ -13,19 +13,25 @@ import (
 	"github.com/phoreproject/openbazaar-go/core"
 	"github.com/phoreproject/openbazaar-go/pb"
 	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/schema"
 )
 
 var log = logging.MustGetLogger("transaction-listener")
 
 type TransactionListener struct {
-	broadcast   chan repo.Notifier
-	db          repo.Datastore
-	multiwallet multiwallet.MultiWallet
+	broadcast     chan repo.Notifier
+	db            repo.Datastore
+	multiwallet   multiwallet.MultiWallet
+	confirmations *schema.FundingConfirmationsConfig
+	testnet       bool
 	*sync.Mutex
 }
 
-func NewTransactionListener(mw multiwallet.MultiWallet, db repo.Datastore, broadcast chan repo.Notifier) *TransactionListener {
-	return &TransactionListener{broadcast, db, mw, new(sync.Mutex)}
+// NewTransactionListener returns a listener funding orders once their
+// payments have the confirmations required by confirmations. A nil config
+// funds orders as soon as their payments are seen.
+func NewTransactionListener(mw multiwallet.MultiWallet, db repo.Datastore, broadcast chan repo.Notifier, confirmations *schema.FundingConfirmationsConfig, testnet bool) *TransactionListener {
+	return &TransactionListener{broadcast, db, mw, confirmations, testnet, new(sync.Mutex)}
 }
 
 func (l *TransactionListener) getOrderDetails(orderID string, address btc.Address, isSales bool) (*pb.RicardianContract, pb.OrderState, bool, []*wallet.TransactionRecord, error) {
@@ -120,7 +126,7 @@ func (l *TransactionListener) OnTransactionReceived(cb wallet.TransactionCallbac
 		if isForSale {
 			l.db.Sales().UpdateFunding(orderId, funded, records)
 			// This is a dispute payout. We should set the order state.
-			if state == pb.OrderState_DECIDED && len(records) > 0 && fundsReleased {
+			if isDisputePayout(state, contract) && len(records) > 0 && fundsReleased {
 				if contract.DisputeAcceptance == nil && contract != nil && contract.BuyerOrder != nil && contract.BuyerOrder.BuyerID != nil {
 					accept := new(pb.DisputeAcceptance)
 					ts, _ := ptypes.TimestampProto(time.Now())
@@ -146,7 +152,7 @@ func (l *TransactionListener) OnTransactionReceived(cb wallet.TransactionCallbac
 			}
 		} else {
 			l.db.Purchases().UpdateFunding(orderId, funded, records)
-			if state == pb.OrderState_DECIDED && len(records) > 0 && fundsReleased {
+			if isDisputePayout(state, contract) && len(records) > 0 && fundsReleased {
 				if contract.DisputeAcceptance == nil && contract != nil && len(contract.VendorListings) > 0 && contract.VendorListings[0].VendorID != nil {
 					accept := new(pb.DisputeAcceptance)
 					ts, _ := ptypes.TimestampProto(time.Now())
@@ -178,6 +184,17 @@ func (l *TransactionListener) OnTransactionReceived(cb wallet.TransactionCallbac
 	}
 }
 
+// isDisputePayout returns true if spending the escrow of an order in this
+// state pays out a dispute, either from the moderator's resolution or from
+// the contract's dispute fallback
+func isDisputePayout(state pb.OrderState, contract *pb.RicardianContract) bool {
+	if state == pb.OrderState_DECIDED {
+		return true
+	}
+	return state == pb.OrderState_DISPUTED && contract != nil && contract.DisputeFallbackPayout != nil &&
+		len(contract.DisputeFallbackPayout.CounterpartySigs) > 0
+}
+
 func (l *TransactionListener) processSalePayment(txid string, output wallet.TransactionOutput, contract *pb.RicardianContract, state pb.OrderState, funded bool, records []*wallet.TransactionRecord) {
 	var funding = output.Value
 	for _, r := range records {
@@ -191,42 +208,6 @@ func (l *TransactionListener) processSalePayment(txid string, output wallet.Tran
 	if err != nil {
 		return
 	}
-	if !funded {
-		requestedAmount := int64(contract.BuyerOrder.Payment.Amount)
-		if funding >= requestedAmount {
-			log.Debugf("Received payment for order %s", orderId)
-			funded = true
-
-			if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation != nil { // Confirmed orders go to AWAITING_FULFILLMENT
-				l.db.Sales().Put(orderId, *contract, pb.OrderState_AWAITING_FULFILLMENT, false)
-			} else if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation == nil { // Unconfirmed orders go into PENDING
-				l.db.Sales().Put(orderId, *contract, pb.OrderState_PENDING, false)
-			}
-			l.adjustInventory(contract)
-
-			n := repo.OrderNotification{
-				BuyerHandle: contract.BuyerOrder.BuyerID.Handle,
-				BuyerID:     contract.BuyerOrder.BuyerID.PeerID,
-				ID:          repo.NewNotificationID(),
-				ListingType: contract.VendorListings[0].Metadata.ContractType.String(),
-				OrderId:     orderId,
-				Price: repo.ListingPrice{
-					Amount:           contract.BuyerOrder.Payment.Amount,
-					CoinDivisibility: currencyDivisibilityFromContract(l.multiwallet, contract),
-					CurrencyCode:     contract.BuyerOrder.Payment.Coin,
-					PriceModifier:    contract.VendorListings[0].Metadata.PriceModifier,
-				},
-				Slug:      contract.VendorListings[0].Slug,
-				Thumbnail: repo.Thumbnail{contract.VendorListings[0].Item.Images[0].Tiny, contract.VendorListings[0].Item.Images[0].Small},
-				Title:     contract.VendorListings[0].Item.Title,
-				Type:      "order",
-			}
-
-			l.broadcast <- n
-			l.db.Notifications().PutRecord(repo.NewNotification(n, time.Now(), false))
-		}
-	}
-
 	record := &wallet.TransactionRecord{
 		Timestamp: time.Now(),
 		Txid:      txid,
@@ -235,6 +216,18 @@ func (l *TransactionListener) processSalePayment(txid string, output wallet.Tran
 		Address:   output.Address.String(),
 	}
 	records = append(records, record)
+	if !funded {
+		requestedAmount := int64(contract.BuyerOrder.Payment.Amount)
+		if funding >= requestedAmount {
+			if l.fundingConfirmed(contract, records) {
+				log.Debugf("Received payment for order %s", orderId)
+				funded = true
+				l.fundSale(orderId, contract, state)
+			} else {
+				log.Debugf("Received payment for order %s, waiting for it to confirm", orderId)
+			}
+		}
+	}
 	l.db.Sales().UpdateFunding(orderId, funded, records)
 
 	// Save tx metadata
@@ -251,6 +244,39 @@ func (l *TransactionListener) processSalePayment(txid string, output wallet.Tran
 	l.db.TxMetadata().Put(repo.Metadata{txid, "", title, orderId, thumbnail, bumpable})
 }
 
+// fundSale moves a sale whose payment is complete on to fulfillment, or to
+// PENDING if the vendor has yet to confirm it, and takes its items out of
+// the inventory
+func (l *TransactionListener) fundSale(orderId string, contract *pb.RicardianContract, state pb.OrderState) {
+	if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation != nil { // Confirmed orders go to AWAITING_FULFILLMENT
+		l.db.Sales().Put(orderId, *contract, pb.OrderState_AWAITING_FULFILLMENT, false)
+	} else if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation == nil { // Unconfirmed orders go into PENDING
+		l.db.Sales().Put(orderId, *contract, pb.OrderState_PENDING, false)
+	}
+	l.adjustInventory(contract, false)
+
+	n := repo.OrderNotification{
+		BuyerHandle: contract.BuyerOrder.BuyerID.Handle,
+		BuyerID:     contract.BuyerOrder.BuyerID.PeerID,
+		ID:          repo.NewNotificationID(),
+		ListingType: contract.VendorListings[0].Metadata.ContractType.String(),
+		OrderId:     orderId,
+		Price: repo.ListingPrice{
+			Amount:           contract.BuyerOrder.Payment.Amount,
+			CoinDivisibility: currencyDivisibilityFromContract(l.multiwallet, contract),
+			CurrencyCode:     contract.BuyerOrder.Payment.Coin,
+			PriceModifier:    contract.VendorListings[0].Metadata.PriceModifier,
+		},
+		Slug:      contract.VendorListings[0].Slug,
+		Thumbnail: repo.Thumbnail{contract.VendorListings[0].Item.Images[0].Tiny, contract.VendorListings[0].Item.Images[0].Small},
+		Title:     contract.VendorListings[0].Item.Title,
+		Type:      "order",
+	}
+
+	l.broadcast <- n
+	l.db.Notifications().PutRecord(repo.NewNotification(n, time.Now(), false))
+}
+
 func currencyDivisibilityFromContract(mw multiwallet.MultiWallet, contract *pb.RicardianContract) uint32 {
 	var currencyDivisibility = contract.VendorListings[0].Metadata.CoinDivisibility
 	if currencyDivisibility != 0 {
@@ -276,15 +302,23 @@ func (l *TransactionListener) processPurchasePayment(txid string, output wallet.
 	if err != nil {
 		return
 	}
+	record := &wallet.TransactionRecord{
+		Txid:      txid,
+		Index:     output.Index,
+		Value:     output.Value,
+		Address:   output.Address.String(),
+		Timestamp: time.Now(),
+	}
+	records = append(records, record)
 	if !funded {
 		requestedAmount := int64(contract.BuyerOrder.Payment.Amount)
 		if funding >= requestedAmount {
-			log.Debugf("Payment for purchase %s detected", orderId)
-			funded = true
-			if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation != nil { // Confirmed orders go to AWAITING_FULFILLMENT
-				l.db.Purchases().Put(orderId, *contract, pb.OrderState_AWAITING_FULFILLMENT, false)
-			} else if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation == nil { // Unconfirmed go into PENDING
-				l.db.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
+			if l.fundingConfirmed(contract, records) {
+				log.Debugf("Payment for purchase %s detected", orderId)
+				funded = true
+				l.fundPurchase(orderId, contract, state)
+			} else {
+				log.Debugf("Payment for purchase %s detected, waiting for it to confirm", orderId)
 			}
 		}
 		n := repo.PaymentNotification{
@@ -297,19 +331,22 @@ func (l *TransactionListener) processPurchasePayment(txid string, output wallet.
 		l.broadcast <- n
 		l.db.Notifications().PutRecord(repo.NewNotification(n, time.Now(), false))
 	}
+	l.db.Purchases().UpdateFunding(orderId, funded, records)
+}
 
-	record := &wallet.TransactionRecord{
-		Txid:      txid,
-		Index:     output.Index,
-		Value:     output.Value,
-		Address:   output.Address.String(),
-		Timestamp: time.Now(),
+// fundPurchase moves a purchase whose payment is complete on to
+// fulfillment, or to PENDING if the vendor has yet to confirm it
+func (l *TransactionListener) fundPurchase(orderId string, contract *pb.RicardianContract, state pb.OrderState) {
+	if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation != nil { // Confirmed orders go to AWAITING_FULFILLMENT
+		l.db.Purchases().Put(orderId, *contract, pb.OrderState_AWAITING_FULFILLMENT, false)
+	} else if state == pb.OrderState_AWAITING_PAYMENT && contract.VendorOrderConfirmation == nil { // Unconfirmed go into PENDING
+		l.db.Purchases().Put(orderId, *contract, pb.OrderState_PENDING, false)
 	}
-	records = append(records, record)
-	l.db.Purchases().UpdateFunding(orderId, funded, records)
 }
 
-func (l *TransactionListener) adjustInventory(contract *pb.RicardianContract) {
+// adjustInventory takes the items of an order out of the inventory, or puts
+// them back if restock is set
+func (l *TransactionListener) adjustInventory(contract *pb.RicardianContract, restock bool) {
 	inventoryUpdated := false
 	for _, item := range contract.BuyerOrder.Items {
 		listing, err := core.ParseContractForListing(item.ListingHash, contract)
@@ -325,13 +362,16 @@ func (l *TransactionListener) adjustInventory(contract *pb.RicardianContract) {
 			continue
 		}
 		q := int64(core.GetOrderQuantity(listing, item))
+		if restock {
+			q = -q
+		}
 		newCount := c - q
 		if c < 0 {
 			newCount = -1
 		} else if newCount < 0 {
 			newCount = 0
 		}
-		if (c == 0) || (c > 0 && c-q < 0) {
+		if !restock && ((c == 0) || (c > 0 && c-q < 0)) {
 			orderId, err := calcOrderId(contract.BuyerOrder)
 			if err != nil {
 				continue
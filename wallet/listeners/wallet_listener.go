@@ -1,24 +1,35 @@
 package bitcoin
 
 import (
+	"time"
+
 	"github.com/OpenBazaar/wallet-interface"
-	"github.com/phoreproject/multiwallet/util"
 	"github.com/phoreproject/openbazaar-go/repo"
 )
 
+// fiatValueMaxAge is how old a transaction can be when the wallet first sees
+// it for the current exchange rate to be its fiat value. Older transactions,
+// such as those found by a rescan, are valued from the rate history instead.
+const fiatValueMaxAge = time.Hour
+
 type WalletListener struct {
 	db        repo.Datastore
 	broadcast chan repo.Notifier
-	coinType  util.ExtCoinType
+	coin      string
+	rates     wallet.ExchangeRates
 }
 
-func NewWalletListener(db repo.Datastore, broadcast chan repo.Notifier, coinType util.ExtCoinType) *WalletListener {
-	l := &WalletListener{db, broadcast, coinType}
+// NewWalletListener returns a listener notifying the user of the wallet's
+// transactions. coin is the wallet's currency code. When rates is not nil the exchange rate of each new
+// transaction is recorded for the accounting export.
+func NewWalletListener(db repo.Datastore, broadcast chan repo.Notifier, coin string, rates wallet.ExchangeRates) *WalletListener {
+	l := &WalletListener{db, broadcast, coin, rates}
 	return l
 }
 
 func (l *WalletListener) OnTransactionReceived(cb wallet.TransactionCallback) {
 	if !cb.WatchOnly {
+		l.recordFiatValue(cb)
 		metadata, _ := l.db.TxMetadata().Get(cb.Txid)
 		status := "UNCONFIRMED"
 		confirmations := 0
@@ -27,7 +38,7 @@ func (l *WalletListener) OnTransactionReceived(cb wallet.TransactionCallback) {
 			confirmations = 1
 		}
 		n := repo.IncomingTransaction{
-			Wallet:        l.coinType.CurrencyCode(),
+			Wallet:        l.coin,
 			Txid:          cb.Txid,
 			Value:         cb.Value,
 			Address:       metadata.Address,
@@ -43,3 +54,36 @@ func (l *WalletListener) OnTransactionReceived(cb wallet.TransactionCallback) {
 		l.broadcast <- n
 	}
 }
+
+// recordFiatValue saves the rate of the transaction's coin in the user's
+// local currency the first time the wallet tells us about a recent
+// transaction
+func (l *WalletListener) recordFiatValue(cb wallet.TransactionCallback) {
+	if l.rates == nil {
+		return
+	}
+	if !cb.Timestamp.IsZero() && time.Since(cb.Timestamp) > fiatValueMaxAge {
+		return
+	}
+	if _, err := l.db.TxFiatValues().Get(cb.Txid); err == nil {
+		return
+	}
+	currency := "USD"
+	if settings, err := l.db.Settings().Get(); err == nil && settings.LocalCurrency != nil && *settings.LocalCurrency != "" {
+		currency = *settings.LocalCurrency
+	}
+	rate, err := l.rates.GetExchangeRate(currency)
+	if err != nil || rate <= 0 {
+		return
+	}
+	timestamp := cb.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	l.db.TxFiatValues().Put(repo.TransactionFiatValue{
+		Txid:      cb.Txid,
+		Currency:  currency,
+		Rate:      rate,
+		Timestamp: timestamp,
+	})
+}
@@ -0,0 +1,45 @@
+package bitcoin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/test"
+)
+
+type fixedExchangeRates float64
+
+func (r fixedExchangeRates) GetExchangeRate(currencyCode string) (float64, error) {
+	return float64(r), nil
+}
+func (r fixedExchangeRates) GetLatestRate(currencyCode string) (float64, error) {
+	return float64(r), nil
+}
+func (r fixedExchangeRates) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	return map[string]float64{"USD": float64(r)}, nil
+}
+func (r fixedExchangeRates) UnitsPerCoin() int { return 100000000 }
+
+func TestWalletListenerRecordsFiatValueOfRecentTransactions(t *testing.T) {
+	node, err := test.NewNode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewWalletListener(node.Datastore, make(chan repo.Notifier, 10), "BTC", fixedExchangeRates(5000))
+
+	// The test repository is shared between runs so the txids are unique
+	suffix := time.Now().Format("150405.000000000")
+	recent := wallet.TransactionCallback{Txid: "recent-" + suffix, Value: 1000, Timestamp: time.Now()}
+	rescanned := wallet.TransactionCallback{Txid: "rescanned-" + suffix, Value: 1000, Timestamp: time.Now().Add(-30 * 24 * time.Hour)}
+	l.OnTransactionReceived(recent)
+	l.OnTransactionReceived(rescanned)
+
+	if v, err := node.Datastore.TxFiatValues().Get(recent.Txid); err != nil || v.Rate != 5000 {
+		t.Errorf("expected the rate of a recent transaction to be recorded, got %+v, %v", v, err)
+	}
+	if _, err := node.Datastore.TxFiatValues().Get(rescanned.Txid); err == nil {
+		t.Error("expected no rate to be recorded for a transaction found by a rescan")
+	}
+}
@@ -0,0 +1,628 @@
+// Package psbt reads and writes BIP174 partially signed bitcoin transactions
+// for the legacy and segwit v0 scripts used by the node's wallets and escrows.
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// Key types from BIP174
+const (
+	globalUnsignedTx = 0x00
+
+	inputNonWitnessUtxo     = 0x00
+	inputWitnessUtxo        = 0x01
+	inputPartialSig         = 0x02
+	inputSighashType        = 0x03
+	inputRedeemScript       = 0x04
+	inputWitnessScript      = 0x05
+	inputBip32Derivation    = 0x06
+	inputFinalScriptSig     = 0x07
+	inputFinalScriptWitness = 0x08
+
+	outputRedeemScript    = 0x00
+	outputWitnessScript   = 0x01
+	outputBip32Derivation = 0x02
+
+	proprietaryType = 0xFC
+
+	// maxEntrySize bounds a single key or value so a malformed packet can't
+	// make us allocate without limit
+	maxEntrySize = 4000000
+)
+
+var magic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+var (
+	// ErrInvalidMagic is returned for data which doesn't start with the PSBT
+	// magic bytes
+	ErrInvalidMagic = errors.New("psbt: invalid magic bytes")
+	// ErrInvalidFormat is returned for a packet which can't be parsed
+	ErrInvalidFormat = errors.New("psbt: invalid format")
+	// ErrDuplicateKey is returned for a map which has the same key twice
+	ErrDuplicateKey = errors.New("psbt: duplicate key")
+	// ErrSignedUnsignedTx is returned when the unsigned transaction has
+	// signature scripts
+	ErrSignedUnsignedTx = errors.New("psbt: unsigned transaction has signature scripts")
+	// ErrMissingPrevOut is returned when an input has no redeem script and no
+	// previous output to sign against
+	ErrMissingPrevOut = errors.New("psbt: input has no previous output")
+)
+
+// Packet is a partially signed transaction
+type Packet struct {
+	UnsignedTx *wire.MsgTx
+	Inputs     []Input
+	Outputs    []Output
+	Unknowns   []Unknown
+}
+
+// Input holds what a signer needs to know about one input of the
+// transaction, and the signatures collected for it
+type Input struct {
+	NonWitnessUtxo     *wire.MsgTx
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []PartialSig
+	SighashType        txscript.SigHashType
+	RedeemScript       []byte
+	WitnessScript      []byte
+	Bip32Derivation    []Bip32Derivation
+	FinalScriptSig     []byte
+	FinalScriptWitness wire.TxWitness
+	Proprietary        []Proprietary
+	Unknowns           []Unknown
+}
+
+// Output holds what a signer needs to know about one output of the
+// transaction
+type Output struct {
+	RedeemScript    []byte
+	WitnessScript   []byte
+	Bip32Derivation []Bip32Derivation
+	Proprietary     []Proprietary
+	Unknowns        []Unknown
+}
+
+// PartialSig is a signature for an input and the public key it was made with
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Bip32Derivation is the BIP32 path of a key from a master key, which is
+// identified by its fingerprint
+type Bip32Derivation struct {
+	PubKey               []byte
+	MasterKeyFingerprint uint32
+	Path                 []uint32
+}
+
+// Proprietary is an application defined entry. The identifier names the
+// application and the subtype the kind of entry.
+type Proprietary struct {
+	Identifier []byte
+	Subtype    uint64
+	KeyData    []byte
+	Value      []byte
+}
+
+// Unknown is an entry of a type this package doesn't interpret. It is kept
+// so it survives a round trip.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// New returns a packet for an unsigned transaction with empty input and
+// output maps
+func New(tx *wire.MsgTx) (*Packet, error) {
+	for _, in := range tx.TxIn {
+		if len(in.SignatureScript) > 0 || len(in.Witness) > 0 {
+			return nil, ErrSignedUnsignedTx
+		}
+	}
+	return &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]Input, len(tx.TxIn)),
+		Outputs:    make([]Output, len(tx.TxOut)),
+	}, nil
+}
+
+// NewFromBase64 parses a base64 encoded packet
+func NewFromBase64(s string) (*Packet, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	return Parse(bytes.NewReader(b))
+}
+
+// Parse reads a serialized packet
+func Parse(r io.Reader) (*Packet, error) {
+	m := make([]byte, len(magic))
+	if _, err := io.ReadFull(r, m); err != nil || !bytes.Equal(m, magic) {
+		return nil, ErrInvalidMagic
+	}
+
+	p := new(Packet)
+	global, err := readMap(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range global {
+		if len(kv.Key) == 1 && kv.Key[0] == globalUnsignedTx {
+			tx := wire.NewMsgTx(1)
+			if err := tx.DeserializeNoWitness(bytes.NewReader(kv.Value)); err != nil {
+				return nil, ErrInvalidFormat
+			}
+			p.UnsignedTx = tx
+			continue
+		}
+		p.Unknowns = append(p.Unknowns, kv)
+	}
+	if p.UnsignedTx == nil {
+		return nil, ErrInvalidFormat
+	}
+	for _, in := range p.UnsignedTx.TxIn {
+		if len(in.SignatureScript) > 0 {
+			return nil, ErrSignedUnsignedTx
+		}
+	}
+
+	for range p.UnsignedTx.TxIn {
+		entries, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+		in, err := parseInput(entries)
+		if err != nil {
+			return nil, err
+		}
+		p.Inputs = append(p.Inputs, *in)
+	}
+	for range p.UnsignedTx.TxOut {
+		entries, err := readMap(r)
+		if err != nil {
+			return nil, err
+		}
+		out, err := parseOutput(entries)
+		if err != nil {
+			return nil, err
+		}
+		p.Outputs = append(p.Outputs, *out)
+	}
+	return p, nil
+}
+
+func parseInput(entries []Unknown) (*Input, error) {
+	in := new(Input)
+	for _, kv := range entries {
+		switch kv.Key[0] {
+		case inputNonWitnessUtxo:
+			tx := wire.NewMsgTx(1)
+			if err := tx.Deserialize(bytes.NewReader(kv.Value)); err != nil {
+				return nil, ErrInvalidFormat
+			}
+			in.NonWitnessUtxo = tx
+		case inputWitnessUtxo:
+			out, err := parseTxOut(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			in.WitnessUtxo = out
+		case inputPartialSig:
+			in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: kv.Key[1:], Signature: kv.Value})
+		case inputSighashType:
+			if len(kv.Value) != 4 {
+				return nil, ErrInvalidFormat
+			}
+			in.SighashType = txscript.SigHashType(binary.LittleEndian.Uint32(kv.Value))
+		case inputRedeemScript:
+			in.RedeemScript = kv.Value
+		case inputWitnessScript:
+			in.WitnessScript = kv.Value
+		case inputBip32Derivation:
+			d, err := parseBip32Derivation(kv)
+			if err != nil {
+				return nil, err
+			}
+			in.Bip32Derivation = append(in.Bip32Derivation, *d)
+		case inputFinalScriptSig:
+			in.FinalScriptSig = kv.Value
+		case inputFinalScriptWitness:
+			witness, err := parseWitness(kv.Value)
+			if err != nil {
+				return nil, err
+			}
+			in.FinalScriptWitness = witness
+		case proprietaryType:
+			prop, err := parseProprietary(kv)
+			if err != nil {
+				return nil, err
+			}
+			in.Proprietary = append(in.Proprietary, *prop)
+		default:
+			in.Unknowns = append(in.Unknowns, kv)
+		}
+	}
+	return in, nil
+}
+
+func parseOutput(entries []Unknown) (*Output, error) {
+	out := new(Output)
+	for _, kv := range entries {
+		switch kv.Key[0] {
+		case outputRedeemScript:
+			out.RedeemScript = kv.Value
+		case outputWitnessScript:
+			out.WitnessScript = kv.Value
+		case outputBip32Derivation:
+			d, err := parseBip32Derivation(kv)
+			if err != nil {
+				return nil, err
+			}
+			out.Bip32Derivation = append(out.Bip32Derivation, *d)
+		case proprietaryType:
+			prop, err := parseProprietary(kv)
+			if err != nil {
+				return nil, err
+			}
+			out.Proprietary = append(out.Proprietary, *prop)
+		default:
+			out.Unknowns = append(out.Unknowns, kv)
+		}
+	}
+	return out, nil
+}
+
+func parseTxOut(b []byte) (*wire.TxOut, error) {
+	if len(b) < 8 {
+		return nil, ErrInvalidFormat
+	}
+	r := bytes.NewReader(b[8:])
+	script, err := wire.ReadVarBytes(r, 0, maxEntrySize, "script")
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	return wire.NewTxOut(int64(binary.LittleEndian.Uint64(b[:8])), script), nil
+}
+
+func parseWitness(b []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(b)
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil || count > maxEntrySize {
+		return nil, ErrInvalidFormat
+	}
+	witness := make(wire.TxWitness, 0, count)
+	for i := uint64(0); i < count; i++ {
+		item, err := wire.ReadVarBytes(r, 0, maxEntrySize, "witness")
+		if err != nil {
+			return nil, ErrInvalidFormat
+		}
+		witness = append(witness, item)
+	}
+	if r.Len() != 0 {
+		return nil, ErrInvalidFormat
+	}
+	return witness, nil
+}
+
+func parseBip32Derivation(kv Unknown) (*Bip32Derivation, error) {
+	if len(kv.Value) < 4 || len(kv.Value)%4 != 0 {
+		return nil, ErrInvalidFormat
+	}
+	d := &Bip32Derivation{
+		PubKey:               kv.Key[1:],
+		MasterKeyFingerprint: binary.LittleEndian.Uint32(kv.Value[:4]),
+	}
+	for i := 4; i < len(kv.Value); i += 4 {
+		d.Path = append(d.Path, binary.LittleEndian.Uint32(kv.Value[i:i+4]))
+	}
+	return d, nil
+}
+
+func parseProprietary(kv Unknown) (*Proprietary, error) {
+	r := bytes.NewReader(kv.Key[1:])
+	identifier, err := wire.ReadVarBytes(r, 0, maxEntrySize, "identifier")
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	subtype, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	keyData := make([]byte, r.Len())
+	r.Read(keyData)
+	return &Proprietary{Identifier: identifier, Subtype: subtype, KeyData: keyData, Value: kv.Value}, nil
+}
+
+// readMap reads key-value pairs up to the separator ending a map
+func readMap(r io.Reader) ([]Unknown, error) {
+	var (
+		entries []Unknown
+		seen    = make(map[string]bool)
+	)
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxEntrySize, "key")
+		if err != nil {
+			return nil, ErrInvalidFormat
+		}
+		if len(key) == 0 {
+			return entries, nil
+		}
+		if seen[string(key)] {
+			return nil, ErrDuplicateKey
+		}
+		seen[string(key)] = true
+		value, err := wire.ReadVarBytes(r, 0, maxEntrySize, "value")
+		if err != nil {
+			return nil, ErrInvalidFormat
+		}
+		entries = append(entries, Unknown{Key: key, Value: value})
+	}
+}
+
+// Serialize writes the packet in the BIP174 binary format
+func (p *Packet) Serialize(w io.Writer) error {
+	if _, err := w.Write(magic); err != nil {
+		return err
+	}
+
+	var tx bytes.Buffer
+	if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+		return err
+	}
+	global := append([]Unknown{{Key: []byte{globalUnsignedTx}, Value: tx.Bytes()}}, p.Unknowns...)
+	if err := writeMap(w, global); err != nil {
+		return err
+	}
+
+	for _, in := range p.Inputs {
+		entries, err := in.entries()
+		if err != nil {
+			return err
+		}
+		if err := writeMap(w, entries); err != nil {
+			return err
+		}
+	}
+	for _, out := range p.Outputs {
+		if err := writeMap(w, out.entries()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bytes returns the serialized packet
+func (p *Packet) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := p.Serialize(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// B64Encode returns the serialized packet in base64, the usual way to pass
+// a packet between programs
+func (p *Packet) B64Encode() (string, error) {
+	b, err := p.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func (in *Input) entries() ([]Unknown, error) {
+	var entries []Unknown
+	if in.NonWitnessUtxo != nil {
+		var buf bytes.Buffer
+		if err := in.NonWitnessUtxo.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		entries = append(entries, Unknown{Key: []byte{inputNonWitnessUtxo}, Value: buf.Bytes()})
+	}
+	if in.WitnessUtxo != nil {
+		var buf bytes.Buffer
+		binary.Write(&buf, binary.LittleEndian, uint64(in.WitnessUtxo.Value))
+		wire.WriteVarBytes(&buf, 0, in.WitnessUtxo.PkScript)
+		entries = append(entries, Unknown{Key: []byte{inputWitnessUtxo}, Value: buf.Bytes()})
+	}
+	for _, sig := range in.PartialSigs {
+		entries = append(entries, Unknown{Key: append([]byte{inputPartialSig}, sig.PubKey...), Value: sig.Signature})
+	}
+	if in.SighashType != 0 {
+		v := make([]byte, 4)
+		binary.LittleEndian.PutUint32(v, uint32(in.SighashType))
+		entries = append(entries, Unknown{Key: []byte{inputSighashType}, Value: v})
+	}
+	if in.RedeemScript != nil {
+		entries = append(entries, Unknown{Key: []byte{inputRedeemScript}, Value: in.RedeemScript})
+	}
+	if in.WitnessScript != nil {
+		entries = append(entries, Unknown{Key: []byte{inputWitnessScript}, Value: in.WitnessScript})
+	}
+	for _, d := range in.Bip32Derivation {
+		entries = append(entries, d.entry(inputBip32Derivation))
+	}
+	if in.FinalScriptSig != nil {
+		entries = append(entries, Unknown{Key: []byte{inputFinalScriptSig}, Value: in.FinalScriptSig})
+	}
+	if in.FinalScriptWitness != nil {
+		var buf bytes.Buffer
+		wire.WriteVarInt(&buf, 0, uint64(len(in.FinalScriptWitness)))
+		for _, item := range in.FinalScriptWitness {
+			wire.WriteVarBytes(&buf, 0, item)
+		}
+		entries = append(entries, Unknown{Key: []byte{inputFinalScriptWitness}, Value: buf.Bytes()})
+	}
+	for _, prop := range in.Proprietary {
+		entries = append(entries, prop.entry())
+	}
+	return append(entries, in.Unknowns...), nil
+}
+
+func (out *Output) entries() []Unknown {
+	var entries []Unknown
+	if out.RedeemScript != nil {
+		entries = append(entries, Unknown{Key: []byte{outputRedeemScript}, Value: out.RedeemScript})
+	}
+	if out.WitnessScript != nil {
+		entries = append(entries, Unknown{Key: []byte{outputWitnessScript}, Value: out.WitnessScript})
+	}
+	for _, d := range out.Bip32Derivation {
+		entries = append(entries, d.entry(outputBip32Derivation))
+	}
+	for _, prop := range out.Proprietary {
+		entries = append(entries, prop.entry())
+	}
+	return append(entries, out.Unknowns...)
+}
+
+func (d Bip32Derivation) entry(keyType byte) Unknown {
+	v := make([]byte, 4*(len(d.Path)+1))
+	binary.LittleEndian.PutUint32(v, d.MasterKeyFingerprint)
+	for i, index := range d.Path {
+		binary.LittleEndian.PutUint32(v[4*(i+1):], index)
+	}
+	return Unknown{Key: append([]byte{keyType}, d.PubKey...), Value: v}
+}
+
+func (prop Proprietary) entry() Unknown {
+	var key bytes.Buffer
+	key.WriteByte(proprietaryType)
+	wire.WriteVarBytes(&key, 0, prop.Identifier)
+	wire.WriteVarInt(&key, 0, prop.Subtype)
+	key.Write(prop.KeyData)
+	return Unknown{Key: key.Bytes(), Value: prop.Value}
+}
+
+func writeMap(w io.Writer, entries []Unknown) error {
+	for _, kv := range entries {
+		if err := wire.WriteVarBytes(w, 0, kv.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, kv.Value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// SigningScript returns the script an input's signature commits to: the
+// witness script of a segwit input, the redeem script of a P2SH input,
+// otherwise the output script it spends
+func (p *Packet) SigningScript(i int) ([]byte, error) {
+	in := p.Inputs[i]
+	if in.WitnessScript != nil {
+		return in.WitnessScript, nil
+	}
+	if in.RedeemScript != nil {
+		return in.RedeemScript, nil
+	}
+	op := p.UnsignedTx.TxIn[i].PreviousOutPoint
+	if in.NonWitnessUtxo != nil {
+		if in.NonWitnessUtxo.TxHash() != op.Hash || int(op.Index) >= len(in.NonWitnessUtxo.TxOut) {
+			return nil, ErrInvalidFormat
+		}
+		return in.NonWitnessUtxo.TxOut[op.Index].PkScript, nil
+	}
+	if in.WitnessUtxo != nil {
+		return in.WitnessUtxo.PkScript, nil
+	}
+	return nil, ErrMissingPrevOut
+}
+
+// SignatureHash returns the SIGHASH_ALL hash an input's signatures sign. A
+// segwit input, one with a witness script, is hashed as in BIP143 and needs
+// the value of the output it spends.
+func (p *Packet) SignatureHash(i int) ([]byte, error) {
+	script, err := p.SigningScript(i)
+	if err != nil {
+		return nil, err
+	}
+	in := p.Inputs[i]
+	if in.WitnessScript == nil {
+		return txscript.CalcSignatureHash(script, txscript.SigHashAll, p.UnsignedTx, i)
+	}
+	if in.WitnessUtxo == nil {
+		return nil, ErrMissingPrevOut
+	}
+	return txscript.CalcWitnessSigHash(script, txscript.NewTxSigHashes(p.UnsignedTx), txscript.SigHashAll, p.UnsignedTx, i, in.WitnessUtxo.Value)
+}
+
+// Sign adds a SIGHASH_ALL signature for an input made with the key
+func (p *Packet) Sign(i int, key *btcec.PrivateKey) error {
+	hash, err := p.SignatureHash(i)
+	if err != nil {
+		return err
+	}
+	signature, err := key.Sign(hash)
+	if err != nil {
+		return err
+	}
+	sig := append(signature.Serialize(), byte(txscript.SigHashAll))
+	return p.AddPartialSig(i, key.PubKey().SerializeCompressed(), sig)
+}
+
+// AddPartialSig adds a signature for an input after checking it was made by
+// the public key over the input's SIGHASH_ALL hash
+func (p *Packet) AddPartialSig(i int, pubKey, sig []byte) error {
+	if err := p.VerifyPartialSig(i, pubKey, sig); err != nil {
+		return err
+	}
+	in := &p.Inputs[i]
+	in.SighashType = txscript.SigHashAll
+	for j, existing := range in.PartialSigs {
+		if bytes.Equal(existing.PubKey, pubKey) {
+			in.PartialSigs[j].Signature = sig
+			return nil
+		}
+	}
+	in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: pubKey, Signature: sig})
+	return nil
+}
+
+// VerifyPartialSig checks a signature for an input was made by the public
+// key over the input's SIGHASH_ALL hash
+func (p *Packet) VerifyPartialSig(i int, pubKey, sig []byte) error {
+	if len(sig) < 2 || txscript.SigHashType(sig[len(sig)-1]) != txscript.SigHashAll {
+		return errors.New("psbt: signature is not SIGHASH_ALL")
+	}
+	hash, err := p.SignatureHash(i)
+	if err != nil {
+		return err
+	}
+	key, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return err
+	}
+	signature, err := btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+	if err != nil {
+		return err
+	}
+	if !signature.Verify(hash, key) {
+		return errors.New("psbt: invalid signature")
+	}
+	return nil
+}
+
+// ProprietaryValue returns the value of an input's proprietary entry, if it
+// has one
+func (in *Input) ProprietaryValue(identifier []byte, subtype uint64, keyData []byte) ([]byte, bool) {
+	for _, prop := range in.Proprietary {
+		if bytes.Equal(prop.Identifier, identifier) && prop.Subtype == subtype && bytes.Equal(prop.KeyData, keyData) {
+			return prop.Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,207 @@
+package psbt_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/phoreproject/openbazaar-go/wallet/psbt"
+)
+
+func newTestKey(t *testing.T) (*btcec.PrivateKey, []byte) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, script
+}
+
+// newTestPacket returns a packet spending the first output of a previous
+// transaction paying to script
+func newTestPacket(t *testing.T, script []byte) *psbt.Packet {
+	prev := wire.NewMsgTx(1)
+	prev.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), []byte{txscript.OP_TRUE}, nil))
+	prev.AddTxOut(wire.NewTxOut(100000, script))
+
+	prevHash := prev.TxHash()
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&prevHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, script))
+
+	p, err := psbt.New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Inputs[0].NonWitnessUtxo = prev
+	return p
+}
+
+func TestPacketRoundTrip(t *testing.T) {
+	key, script := newTestKey(t)
+	p := newTestPacket(t, script)
+	p.Inputs[0].Bip32Derivation = []psbt.Bip32Derivation{{
+		PubKey:               key.PubKey().SerializeCompressed(),
+		MasterKeyFingerprint: 0xdeadbeef,
+		Path:                 []uint32{0x8000002c, 0x800001bc, 0x80000000, 1, 7},
+	}}
+	p.Inputs[0].Proprietary = []psbt.Proprietary{{Identifier: []byte("test"), Subtype: 1, KeyData: []byte{2}, Value: []byte{3}}}
+	p.Outputs[0].Unknowns = []psbt.Unknown{{Key: []byte{0x42}, Value: []byte("kept")}}
+	p.Unknowns = []psbt.Unknown{{Key: []byte{0x70, 1}, Value: []byte("global")}}
+
+	encoded, err := p.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := psbt.NewFromBase64(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reencoded, err := parsed.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reencoded != encoded {
+		t.Error("expected packet to survive a round trip unchanged")
+	}
+
+	in := parsed.Inputs[0]
+	if in.NonWitnessUtxo.TxHash() != p.Inputs[0].NonWitnessUtxo.TxHash() {
+		t.Error("previous transaction was not kept")
+	}
+	if len(in.Bip32Derivation) != 1 || in.Bip32Derivation[0].MasterKeyFingerprint != 0xdeadbeef || len(in.Bip32Derivation[0].Path) != 5 {
+		t.Errorf("unexpected derivation: %+v", in.Bip32Derivation)
+	}
+	if v, ok := in.ProprietaryValue([]byte("test"), 1, []byte{2}); !ok || !bytes.Equal(v, []byte{3}) {
+		t.Error("proprietary entry was not kept")
+	}
+	if len(parsed.Outputs[0].Unknowns) != 1 || len(parsed.Unknowns) != 1 {
+		t.Error("unknown entries were not kept")
+	}
+}
+
+func TestPacketSign(t *testing.T) {
+	key, script := newTestKey(t)
+	other, _ := newTestKey(t)
+	p := newTestPacket(t, script)
+
+	if err := p.Sign(0, key); err != nil {
+		t.Fatal(err)
+	}
+	sig := p.Inputs[0].PartialSigs[0]
+	if err := p.VerifyPartialSig(0, sig.PubKey, sig.Signature); err != nil {
+		t.Errorf("expected signature to verify: %s", err)
+	}
+	if err := p.AddPartialSig(0, other.PubKey().SerializeCompressed(), sig.Signature); err == nil {
+		t.Error("expected signature to be rejected for another key")
+	}
+
+	// The finished input spends the previous output
+	builder := txscript.NewScriptBuilder().AddData(sig.Signature).AddData(sig.PubKey)
+	scriptSig, err := builder.Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := p.UnsignedTx.Copy()
+	tx.TxIn[0].SignatureScript = scriptSig
+	vm, err := txscript.NewEngine(script, tx, 0, txscript.StandardVerifyFlags, nil, nil, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected signed input to be valid: %s", err)
+	}
+}
+
+func TestPacketSignWitness(t *testing.T) {
+	key, _ := newTestKey(t)
+	other, _ := newTestKey(t)
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_1).
+		AddData(key.PubKey().SerializeCompressed()).AddData(other.PubKey().SerializeCompressed()).
+		AddOp(txscript.OP_2).AddOp(txscript.OP_CHECKMULTISIG)
+	witnessScript, err := builder.Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptHash := sha256.Sum256(witnessScript)
+	pkScript := append([]byte{txscript.OP_0, txscript.OP_DATA_32}, scriptHash[:]...)
+
+	p := newTestPacket(t, pkScript)
+	p.Inputs[0].NonWitnessUtxo = nil
+	if err := p.Sign(0, key); err != psbt.ErrMissingPrevOut {
+		t.Errorf("expected missing previous output to be rejected, got %v", err)
+	}
+	p.Inputs[0].WitnessScript = witnessScript
+	if err := p.Sign(0, key); err != psbt.ErrMissingPrevOut {
+		t.Errorf("expected segwit input to need the value it spends, got %v", err)
+	}
+	p.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, pkScript)
+	if err := p.Sign(0, key); err != nil {
+		t.Fatal(err)
+	}
+	sig := p.Inputs[0].PartialSigs[0]
+	p.Inputs[0].FinalScriptWitness = wire.TxWitness{{}, sig.Signature, witnessScript}
+
+	encoded, err := p.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := psbt.NewFromBase64(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := parsed.Inputs[0]
+	if !bytes.Equal(in.WitnessScript, witnessScript) || len(in.FinalScriptWitness) != 3 {
+		t.Fatal("expected segwit fields to survive a round trip")
+	}
+
+	tx := parsed.UnsignedTx.Copy()
+	tx.TxIn[0].Witness = in.FinalScriptWitness
+	vm, err := txscript.NewEngine(pkScript, tx, 0, txscript.StandardVerifyFlags, nil, nil, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Errorf("expected signed segwit input to be valid: %s", err)
+	}
+}
+
+func TestPacketRejectsInvalid(t *testing.T) {
+	_, script := newTestKey(t)
+	p := newTestPacket(t, script)
+	b, err := p.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := psbt.Parse(bytes.NewReader(append([]byte("xsbt"), b[4:]...))); err != psbt.ErrInvalidMagic {
+		t.Errorf("expected invalid magic, got %v", err)
+	}
+	if _, err := psbt.Parse(bytes.NewReader(b[:len(b)-1])); err != psbt.ErrInvalidFormat {
+		t.Errorf("expected truncated packet to be rejected, got %v", err)
+	}
+
+	signed := p.UnsignedTx.Copy()
+	signed.TxIn[0].SignatureScript = []byte{txscript.OP_TRUE}
+	if _, err := psbt.New(signed); err != psbt.ErrSignedUnsignedTx {
+		t.Errorf("expected signed transaction to be rejected, got %v", err)
+	}
+
+	p.Inputs[0].NonWitnessUtxo = nil
+	if _, err := p.SigningScript(0); err != psbt.ErrMissingPrevOut {
+		t.Errorf("expected missing previous output, got %v", err)
+	}
+}
@@ -41,8 +41,8 @@ func (r *ResyncManager) CheckUnfunded() {
 	wallets := make(map[string]time.Time)
 	rollbackTime := time.Unix(2147483647, 0)
 	if r.mw != nil {
-		for cc := range r.mw {
-			wallets[strings.ToUpper(cc.CurrencyCode())] = rollbackTime
+		for _, wal := range r.mw {
+			wallets[strings.ToUpper(wal.CurrencyCode())] = rollbackTime
 		}
 	}
 	for _, uf := range unfunded {
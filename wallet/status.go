@@ -3,6 +3,7 @@ package wallet
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/phoreproject/multiwallet"
@@ -35,7 +36,7 @@ func (s *StatusUpdater) Start() {
 		select {
 		case <-t.C:
 			ret := make(map[string]walletUpdate)
-			for ct, wal := range s.mw {
+			for _, wal := range s.mw {
 				confirmed, unconfirmed := wal.Balance()
 				height, _ := wal.ChainTip()
 				u := walletUpdate{
@@ -43,7 +44,7 @@ func (s *StatusUpdater) Start() {
 					Unconfirmed: unconfirmed,
 					Confirmed:   confirmed,
 				}
-				ret[ct.CurrencyCode()] = u
+				ret[strings.ToUpper(wal.CurrencyCode())] = u
 			}
 			ser, err := json.MarshalIndent(walletUpdateWrapper{ret}, "", "    ")
 			if err != nil {
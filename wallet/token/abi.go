@@ -0,0 +1,41 @@
+package token
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// tokenABI is the part of the ERC-20 interface the wallet uses
+const tokenABI = `[
+	{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"type":"function"},
+	{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":true,"name":"to","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}
+]`
+
+// escrowABI is the interface of contracts/Escrow.evm
+const escrowABI = `[
+	{"constant":false,"inputs":[{"name":"scriptHash","type":"bytes32"},{"name":"token","type":"address"},{"name":"amount","type":"uint256"}],"name":"fund","outputs":[],"type":"function"},
+	{"constant":false,"inputs":[{"name":"script","type":"bytes"},{"name":"sigV","type":"uint8[]"},{"name":"sigR","type":"bytes32[]"},{"name":"sigS","type":"bytes32[]"},{"name":"destinations","type":"address[]"},{"name":"amounts","type":"uint256[]"}],"name":"release","outputs":[],"type":"function"},
+	{"constant":true,"inputs":[{"name":"","type":"bytes32"}],"name":"deposits","outputs":[{"name":"token","type":"address"},{"name":"balance","type":"uint256"},{"name":"fundedAt","type":"uint256"},{"name":"nonce","type":"uint256"}],"type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"scriptHash","type":"bytes32"},{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"amount","type":"uint256"}],"name":"Funded","type":"event"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"scriptHash","type":"bytes32"},{"indexed":false,"name":"destinations","type":"address[]"},{"indexed":false,"name":"amounts","type":"uint256[]"}],"name":"Released","type":"event"}
+]`
+
+var (
+	tokenContract  = mustParseABI(tokenABI)
+	escrowContract = mustParseABI(escrowABI)
+
+	transferEvent = tokenContract.Events["Transfer"]
+	fundedEvent   = escrowContract.Events["Funded"]
+	releasedEvent = escrowContract.Events["Released"]
+)
+
+func mustParseABI(def string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(def))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
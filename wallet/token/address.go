@@ -0,0 +1,70 @@
+package token
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	btc "github.com/btcsuite/btcutil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidAddress is returned for strings which are neither an account
+// nor an escrow address
+var ErrInvalidAddress = errors.New("token: invalid address")
+
+// AccountAddress is an Ethereum account. It implements btcutil.Address so
+// the wallet fits the wallet interface.
+type AccountAddress struct {
+	common.Address
+}
+
+// NewAccountAddress returns the account address
+func NewAccountAddress(addr common.Address) *AccountAddress {
+	return &AccountAddress{addr}
+}
+
+func (a *AccountAddress) EncodeAddress() string          { return a.Hex() }
+func (a *AccountAddress) String() string                 { return a.Hex() }
+func (a *AccountAddress) ScriptAddress() []byte          { return a.Bytes() }
+func (a *AccountAddress) IsForNet(*chaincfg.Params) bool { return true }
+
+// EscrowAddress is a deposit in the escrow contract. It takes the place of a
+// multisig address and is written as the contract address and the deposit's
+// script hash separated by a slash.
+type EscrowAddress struct {
+	Contract   common.Address
+	ScriptHash common.Hash
+}
+
+// NewEscrowAddress returns the address of the deposit
+func NewEscrowAddress(contract common.Address, scriptHash common.Hash) *EscrowAddress {
+	return &EscrowAddress{Contract: contract, ScriptHash: scriptHash}
+}
+
+func (a *EscrowAddress) EncodeAddress() string {
+	return a.Contract.Hex() + "/" + a.ScriptHash.Hex()
+}
+func (a *EscrowAddress) String() string                 { return a.EncodeAddress() }
+func (a *EscrowAddress) ScriptAddress() []byte          { return a.ScriptHash.Bytes() }
+func (a *EscrowAddress) IsForNet(*chaincfg.Params) bool { return true }
+
+// DecodeAddress parses an account or escrow address
+func DecodeAddress(addr string) (btc.Address, error) {
+	parts := strings.Split(strings.TrimSpace(addr), "/")
+	switch len(parts) {
+	case 1:
+		if !common.IsHexAddress(parts[0]) {
+			return nil, ErrInvalidAddress
+		}
+		return NewAccountAddress(common.HexToAddress(parts[0])), nil
+	case 2:
+		hash, err := hex.DecodeString(strings.TrimPrefix(parts[1], "0x"))
+		if err != nil || len(hash) != common.HashLength || !common.IsHexAddress(parts[0]) {
+			return nil, ErrInvalidAddress
+		}
+		return NewEscrowAddress(common.HexToAddress(parts[0]), common.BytesToHash(hash)), nil
+	}
+	return nil, ErrInvalidAddress
+}
@@ -0,0 +1,218 @@
+// +build ignore
+
+// compile assembles the contracts of the token wallet into the Go files
+// holding their bytecode. Run it with go generate in the token package.
+//
+// The contracts are plain EVM assembly, one or more instructions per line:
+//
+//	; comment
+//	NAME = 0x20          defines a constant
+//	label:               marks a jump destination
+//	PUSH 0x20            pushes a hex or decimal number, a constant, or
+//	PUSH @label          the position of a label
+//	JUMP @label          jumps to a label, JUMPI @label if the top is not zero
+//
+// Every other word is an opcode as named by go-ethereum's core/vm. The
+// bytecode is prefixed with the constructor returning it.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var outputs = []struct {
+	source, file, name, doc string
+}{
+	{"contracts/Escrow.evm", "escrow_bytecode.go", "escrowBytecode", "the deployment code of contracts/Escrow.evm"},
+	{"contracts/TestToken.evm", "testtoken_bytecode_test.go", "testTokenBytecode", "the deployment code of contracts/TestToken.evm"},
+}
+
+func main() {
+	for _, o := range outputs {
+		code, err := assemble(o.source)
+		if err != nil {
+			log.Fatalf("%s: %s", o.source, err)
+		}
+		if err := write(o.file, o.name, o.doc, code); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+type instruction struct {
+	line    int
+	op      vm.OpCode
+	operand string
+	label   string
+}
+
+func assemble(source string) ([]byte, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	consts := make(map[string]*big.Int)
+	var prog []instruction
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i]
+		}
+		words := strings.Fields(line)
+		if len(words) == 3 && words[1] == "=" {
+			v, ok := number(words[2])
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid constant %s", n, words[2])
+			}
+			consts[words[0]] = v
+			continue
+		}
+		for i := 0; i < len(words); i++ {
+			w := words[i]
+			if strings.HasSuffix(w, ":") {
+				prog = append(prog, instruction{line: n, op: vm.JUMPDEST, label: strings.TrimSuffix(w, ":")})
+				continue
+			}
+			if w == "PUSH" {
+				if i+1 == len(words) {
+					return nil, fmt.Errorf("line %d: PUSH without an operand", n)
+				}
+				i++
+				prog = append(prog, instruction{line: n, op: vm.PUSH1, operand: words[i]})
+				continue
+			}
+			op := vm.StringToOp(w)
+			if op == vm.STOP && w != "STOP" {
+				return nil, fmt.Errorf("line %d: unknown instruction %s", n, w)
+			}
+			switch {
+			case (op == vm.JUMP || op == vm.JUMPI) && i+1 < len(words) && strings.HasPrefix(words[i+1], "@"):
+				i++
+				prog = append(prog, instruction{line: n, op: vm.PUSH1, operand: words[i]}, instruction{line: n, op: op})
+			case op.IsPush():
+				return nil, fmt.Errorf("line %d: use PUSH instead of %s", n, w)
+			default:
+				prog = append(prog, instruction{line: n, op: op})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Labels are pushed with two bytes, so their positions are known before
+	// the code is written
+	labels := make(map[string]int)
+	pc := 0
+	for _, in := range prog {
+		if in.label != "" {
+			if _, ok := labels[in.label]; ok {
+				return nil, fmt.Errorf("line %d: duplicate label %s", in.line, in.label)
+			}
+			labels[in.label] = pc
+		}
+		size, err := operandSize(in, consts)
+		if err != nil {
+			return nil, err
+		}
+		pc += 1 + size
+	}
+
+	var code []byte
+	for _, in := range prog {
+		if in.op != vm.PUSH1 {
+			code = append(code, byte(in.op))
+			continue
+		}
+		var v []byte
+		if strings.HasPrefix(in.operand, "@") {
+			at, ok := labels[in.operand[1:]]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown label %s", in.line, in.operand)
+			}
+			v = []byte{byte(at >> 8), byte(at)}
+		} else {
+			v = operand(in.operand, consts).Bytes()
+			if len(v) == 0 {
+				v = []byte{0}
+			}
+		}
+		code = append(code, byte(vm.PUSH1)+byte(len(v)-1))
+		code = append(code, v...)
+	}
+	if len(code) > 0xffff {
+		return nil, fmt.Errorf("%d bytes of code is too long", len(code))
+	}
+
+	// The constructor copies the code after it into memory and returns it
+	constructor := []byte{
+		byte(vm.PUSH2), byte(len(code) >> 8), byte(len(code)),
+		byte(vm.DUP1),
+		byte(vm.PUSH1), 0x0c,
+		byte(vm.PUSH1), 0x00,
+		byte(vm.CODECOPY),
+		byte(vm.PUSH1), 0x00,
+		byte(vm.RETURN),
+	}
+	return append(constructor, code...), nil
+}
+
+func operandSize(in instruction, consts map[string]*big.Int) (int, error) {
+	if in.op != vm.PUSH1 {
+		return 0, nil
+	}
+	if strings.HasPrefix(in.operand, "@") {
+		return 2, nil
+	}
+	v := operand(in.operand, consts)
+	if v == nil || v.Sign() < 0 || v.BitLen() > 256 {
+		return 0, fmt.Errorf("line %d: invalid operand %s", in.line, in.operand)
+	}
+	if v.Sign() == 0 {
+		return 1, nil
+	}
+	return len(v.Bytes()), nil
+}
+
+func operand(s string, consts map[string]*big.Int) *big.Int {
+	if v, ok := consts[s]; ok {
+		return v
+	}
+	v, _ := number(s)
+	return v
+}
+
+func number(s string) (*big.Int, bool) {
+	if strings.HasPrefix(s, "0x") {
+		return new(big.Int).SetString(s[2:], 16)
+	}
+	return new(big.Int).SetString(s, 10)
+}
+
+func write(file, name, doc string, code []byte) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by contracts/compile.go. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package token\n\n")
+	fmt.Fprintf(&b, "import \"github.com/ethereum/go-ethereum/common\"\n\n")
+	fmt.Fprintf(&b, "// %s is %s\n", name, doc)
+	fmt.Fprintf(&b, "var %s = common.FromHex(\"0x%s\")\n", name, hex.EncodeToString(code))
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, src, 0644)
+}
@@ -0,0 +1,444 @@
+package token
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// devChain is a local chain running go-ethereum's EVM. It mines a block for
+// every transaction. A faucet deploys contracts/TestToken.evm and
+// contracts/Escrow.evm on it, mints tokens and pays accounts their gas.
+type devChain struct {
+	mtx sync.Mutex
+
+	token, escrow common.Address
+	signer        types.Signer
+	faucet        *ecdsa.PrivateKey
+
+	config *params.ChainConfig
+	engine consensus.Engine
+	db     ethdb.Database
+	chain  *core.BlockChain
+	logs   []types.Log
+}
+
+// testTokenABI is the method contracts/TestToken.evm adds to the token
+const testTokenABI = `[
+	{"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"mint","outputs":[],"type":"function"}
+]`
+
+var testTokenContract = mustParseABI(testTokenABI)
+
+var errReverted = errors.New("devchain: execution reverted")
+
+// gasFunds is the ether the faucet gives an account to pay for gas
+var gasFunds = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func newDevChain() *devChain {
+	faucet, _ := crypto.ToECDSA(bytes.Repeat([]byte{0x01}, 32))
+	c := &devChain{
+		signer: types.NewEIP155Signer(params.AllEthashProtocolChanges.ChainID),
+		faucet: faucet,
+		config: params.AllEthashProtocolChanges,
+		engine: ethash.NewFaker(),
+		db:     ethdb.NewMemDatabase(),
+	}
+	genesis := &core.Genesis{
+		Config:    c.config,
+		Timestamp: 1500000000,
+		GasLimit:  8000000,
+		Alloc: core.GenesisAlloc{
+			crypto.PubkeyToAddress(faucet.PublicKey): {Balance: new(big.Int).Mul(gasFunds, big.NewInt(1000))},
+		},
+	}
+	genesis.MustCommit(c.db)
+	chain, err := core.NewBlockChain(c.db, &core.CacheConfig{Disabled: true}, c.config, c.engine, vm.Config{}, nil)
+	if err != nil {
+		panic(err)
+	}
+	c.chain = chain
+	c.token = c.deploy(testTokenBytecode)
+	c.escrow = c.deploy(escrowBytecode)
+	return c
+}
+
+// deploy creates the contract from the faucet
+func (c *devChain) deploy(code []byte) common.Address {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	receipts := c.mine(0, c.faucetTx(nil, nil, code))
+	if receipts[0].Status != types.ReceiptStatusSuccessful {
+		panic("devchain: deploying contract failed")
+	}
+	return receipts[0].ContractAddress
+}
+
+// fundGas sends the account ether for its gas
+func (c *devChain) fundGas(to common.Address) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.mine(0, c.faucetTx(&to, gasFunds, nil))
+}
+
+// mint credits tokens to the account in a new block
+func (c *devChain) mint(to common.Address, amount *big.Int) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	data, err := testTokenContract.Pack("mint", to, amount)
+	if err != nil {
+		panic(err)
+	}
+	c.mine(0, c.faucetTx(&c.token, nil, data))
+}
+
+// advance mines an empty block at least d after the last one
+func (c *devChain) advance(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.mine(int64(d / time.Second))
+}
+
+// balanceOf returns the account's token balance
+func (c *devChain) balanceOf(addr common.Address) *big.Int {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	data, err := tokenContract.Pack("balanceOf", addr)
+	if err != nil {
+		panic(err)
+	}
+	out, err := c.call(ethereum.CallMsg{To: &c.token, Data: data}, c.chain.CurrentBlock().GasLimit())
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetBytes(out)
+}
+
+// codeAt returns the code of the contract
+func (c *devChain) codeAt(addr common.Address) []byte {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	statedb, err := c.chain.State()
+	if err != nil {
+		panic(err)
+	}
+	return statedb.GetCode(addr)
+}
+
+func (c *devChain) faucetTx(to *common.Address, value *big.Int, data []byte) *types.Transaction {
+	statedb, err := c.chain.State()
+	if err != nil {
+		panic(err)
+	}
+	nonce := statedb.GetNonce(crypto.PubkeyToAddress(c.faucet.PublicKey))
+	tx := types.NewContractCreation(nonce, value, 3000000, new(big.Int), data)
+	if to != nil {
+		tx = types.NewTransaction(nonce, *to, value, 3000000, new(big.Int), data)
+	}
+	signed, err := types.SignTx(tx, c.signer, c.faucet)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+// mine adds a block holding the transactions, offset seconds after the
+// default block time, and keeps their logs for FilterLogs
+func (c *devChain) mine(offset int64, txs ...*types.Transaction) types.Receipts {
+	blocks, receipts := core.GenerateChain(c.config, c.chain.CurrentBlock(), c.engine, c.db, 1, func(i int, b *core.BlockGen) {
+		if offset > 0 {
+			b.OffsetTime(offset)
+		}
+		for _, tx := range txs {
+			b.AddTxWithChain(c.chain, tx)
+		}
+	})
+	if _, err := c.chain.InsertChain(blocks); err != nil {
+		panic(err)
+	}
+	for _, r := range receipts[0] {
+		for _, l := range r.Logs {
+			l.BlockNumber = blocks[0].NumberU64()
+			l.BlockHash = blocks[0].Hash()
+			c.logs = append(c.logs, *l)
+		}
+	}
+	return receipts[0]
+}
+
+// call runs the message on the state of the last block
+func (c *devChain) call(msg ethereum.CallMsg, gas uint64) ([]byte, error) {
+	statedb, err := c.chain.State()
+	if err != nil {
+		return nil, err
+	}
+	value := msg.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	m := types.NewMessage(msg.From, msg.To, 0, value, gas, new(big.Int), msg.Data, false)
+	evm := vm.NewEVM(core.NewEVMContext(m, c.chain.CurrentBlock().Header(), c.chain, nil), statedb, c.config, vm.Config{})
+	ret, _, failed, err := core.NewStateTransition(evm, m, new(core.GasPool).AddGas(math.MaxUint64)).TransitionDb()
+	if err != nil {
+		return nil, err
+	}
+	if failed {
+		return nil, errReverted
+	}
+	return ret, nil
+}
+
+func (c *devChain) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.call(msg, c.chain.CurrentBlock().GasLimit())
+}
+
+func (c *devChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	statedb, err := c.chain.State()
+	if err != nil {
+		return 0, err
+	}
+	return statedb.GetNonce(account), nil
+}
+
+func (c *devChain) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2e9), nil
+}
+
+// EstimateGas finds the least gas the call succeeds with
+func (c *devChain) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	lo, hi := uint64(params.TxGas-1), c.chain.CurrentBlock().GasLimit()
+	if _, err := c.call(msg, hi); err != nil {
+		return 0, err
+	}
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		if _, err := c.call(msg, mid); err != nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+// SendTransaction mines the transaction in a block of its own. Transactions
+// which fail are mined like on any chain.
+func (c *devChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	from, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return err
+	}
+	statedb, err := c.chain.State()
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() != statedb.GetNonce(from) {
+		return errors.New("devchain: invalid nonce")
+	}
+	if statedb.GetBalance(from).Cmp(tx.Cost()) < 0 {
+		return core.ErrInsufficientFunds
+	}
+	intrinsic, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true)
+	if err != nil {
+		return err
+	}
+	if tx.Gas() < intrinsic {
+		return core.ErrIntrinsicGas
+	}
+	if tx.Gas() > c.chain.CurrentBlock().GasLimit() {
+		return core.ErrGasLimitReached
+	}
+	c.mine(0, tx)
+	return nil
+}
+
+func (c *devChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if number == nil {
+		return c.chain.CurrentBlock().Header(), nil
+	}
+	if !number.IsUint64() {
+		return nil, ethereum.NotFound
+	}
+	header := c.chain.GetHeaderByNumber(number.Uint64())
+	if header == nil {
+		return nil, ethereum.NotFound
+	}
+	return header, nil
+}
+
+func (c *devChain) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	var found []types.Log
+	for _, l := range c.logs {
+		if q.FromBlock != nil && l.BlockNumber < q.FromBlock.Uint64() {
+			continue
+		}
+		if q.ToBlock != nil && l.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		if len(q.Addresses) > 0 && !containsAddress(q.Addresses, l.Address) {
+			continue
+		}
+		if matchTopics(q.Topics, l.Topics) {
+			found = append(found, l)
+		}
+	}
+	return found, nil
+}
+
+func containsAddress(addrs []common.Address, a common.Address) bool {
+	for _, addr := range addrs {
+		if addr == a {
+			return true
+		}
+	}
+	return false
+}
+
+func matchTopics(filter [][]common.Hash, topics []common.Hash) bool {
+	if len(filter) > len(topics) {
+		return false
+	}
+	for i, alternatives := range filter {
+		if len(alternatives) == 0 {
+			continue
+		}
+		match := false
+		for _, t := range alternatives {
+			if t == topics[i] {
+				match = true
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// memoryDatastore keeps a wallet's transactions and watched deposits in
+// memory
+type memoryDatastore struct {
+	txns    *memoryTxns
+	scripts *memoryScripts
+}
+
+func newMemoryDatastore() *memoryDatastore {
+	return &memoryDatastore{&memoryTxns{txns: make(map[string]wallet.Txn)}, &memoryScripts{}}
+}
+
+func (m *memoryDatastore) Txns() wallet.Txns                     { return m.txns }
+func (m *memoryDatastore) WatchedScripts() wallet.WatchedScripts { return m.scripts }
+
+type memoryTxns struct {
+	mtx  sync.Mutex
+	txns map[string]wallet.Txn
+}
+
+func (m *memoryTxns) Put(raw []byte, txid string, value, height int, timestamp time.Time, watchOnly bool) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.txns[txid] = wallet.Txn{Txid: txid, Value: int64(value), Height: int32(height), Timestamp: timestamp, WatchOnly: watchOnly, Bytes: raw}
+	return nil
+}
+
+func (m *memoryTxns) Get(txid chainhash.Hash) (wallet.Txn, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	txn, ok := m.txns[txid.String()]
+	if !ok {
+		return txn, errors.New("not found")
+	}
+	return txn, nil
+}
+
+func (m *memoryTxns) GetAll(includeWatchOnly bool) ([]wallet.Txn, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var txns []wallet.Txn
+	for _, txn := range m.txns {
+		if txn.WatchOnly && !includeWatchOnly {
+			continue
+		}
+		txns = append(txns, txn)
+	}
+	return txns, nil
+}
+
+func (m *memoryTxns) UpdateHeight(txid chainhash.Hash, height int, timestamp time.Time) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	txn, ok := m.txns[txid.String()]
+	if !ok {
+		return errors.New("not found")
+	}
+	txn.Height, txn.Timestamp = int32(height), timestamp
+	m.txns[txid.String()] = txn
+	return nil
+}
+
+func (m *memoryTxns) Delete(txid *chainhash.Hash) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.txns, txid.String())
+	return nil
+}
+
+type memoryScripts struct {
+	mtx     sync.Mutex
+	scripts [][]byte
+}
+
+func (m *memoryScripts) Put(script []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.scripts = append(m.scripts, script)
+	return nil
+}
+
+func (m *memoryScripts) GetAll() ([][]byte, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return append([][]byte{}, m.scripts...), nil
+}
+
+func (m *memoryScripts) Delete(script []byte) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for i, s := range m.scripts {
+		if bytes.Equal(s, script) {
+			m.scripts = append(m.scripts[:i], m.scripts[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// ErrInvalidScript is returned for redeem scripts which don't decode
+	ErrInvalidScript = errors.New("token: invalid escrow script")
+
+	// ErrInvalidSignature is returned for release signatures which aren't by
+	// a party to the escrow
+	ErrInvalidSignature = errors.New("token: signature is not by a party to the escrow")
+)
+
+//go:generate go run contracts/compile.go
+
+var scriptArguments = mustArguments("address", "address[]", "uint8", "uint32", "address", "bytes32")
+
+var releaseArguments = mustArguments("address", "bytes32", "uint256", "address[]", "uint256[]")
+
+func mustArguments(types ...string) abi.Arguments {
+	var args abi.Arguments
+	for _, t := range types {
+		typ, err := abi.NewType(t)
+		if err != nil {
+			panic(err)
+		}
+		args = append(args, abi.Argument{Type: typ})
+	}
+	return args
+}
+
+// EscrowScript is what a deposit in the escrow contract is locked to. It is
+// the redeem script of the order, and its keccak256 hash keys the deposit.
+type EscrowScript struct {
+	Token        common.Address
+	Parties      []common.Address
+	Threshold    uint8
+	Timeout      time.Duration
+	TimeoutParty common.Address
+	Nonce        common.Hash
+}
+
+// Serialize returns the ABI encoding of the script the contract decodes
+func (s *EscrowScript) Serialize() ([]byte, error) {
+	return scriptArguments.Pack(s.Token, s.Parties, s.Threshold, uint32(s.Timeout/time.Second), s.TimeoutParty, [32]byte(s.Nonce))
+}
+
+// Hash returns the script hash keying the deposit
+func (s *EscrowScript) Hash() (common.Hash, error) {
+	b, err := s.Serialize()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(b), nil
+}
+
+// IsParty returns whether the address may sign the release
+func (s *EscrowScript) IsParty(addr common.Address) bool {
+	for _, p := range s.Parties {
+		if p == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEscrowScript decodes a serialized script
+func ParseEscrowScript(b []byte) (*EscrowScript, error) {
+	values, err := scriptArguments.UnpackValues(b)
+	if err != nil || len(values) != len(scriptArguments) {
+		return nil, ErrInvalidScript
+	}
+	var (
+		s  EscrowScript
+		ok bool
+	)
+	if s.Token, ok = values[0].(common.Address); !ok {
+		return nil, ErrInvalidScript
+	}
+	if s.Parties, ok = values[1].([]common.Address); !ok {
+		return nil, ErrInvalidScript
+	}
+	if s.Threshold, ok = values[2].(uint8); !ok {
+		return nil, ErrInvalidScript
+	}
+	timeout, ok := values[3].(uint32)
+	if !ok {
+		return nil, ErrInvalidScript
+	}
+	s.Timeout = time.Duration(timeout) * time.Second
+	if s.TimeoutParty, ok = values[4].(common.Address); !ok {
+		return nil, ErrInvalidScript
+	}
+	nonce, ok := values[5].([32]byte)
+	if !ok {
+		return nil, ErrInvalidScript
+	}
+	s.Nonce = common.Hash(nonce)
+	return &s, nil
+}
+
+// Release is a payout of a whole deposit. Nonce is the deposit's count of
+// releases, so the signatures can't release the deposit again once it is
+// funded anew. What the amounts leave of the deposit pays whoever submits
+// the release for its gas.
+type Release struct {
+	Escrow       common.Address
+	ScriptHash   common.Hash
+	Nonce        *big.Int
+	Destinations []common.Address
+	Amounts      []*big.Int
+}
+
+// Hash returns the message hash the parties sign to release the deposit
+func (r *Release) Hash() (common.Hash, error) {
+	nonce := r.Nonce
+	if nonce == nil {
+		nonce = new(big.Int)
+	}
+	b, err := releaseArguments.Pack(r.Escrow, [32]byte(r.ScriptHash), nonce, r.Destinations, r.Amounts)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte("\x19Ethereum Signed Message:\n32"), crypto.Keccak256(b)), nil
+}
+
+// Sign returns the key's 65 byte signature of the release
+func (r *Release) Sign(key *ecdsa.PrivateKey) ([]byte, error) {
+	hash, err := r.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Sign(hash.Bytes(), key)
+}
+
+// Signer returns the address which made the signature
+func (r *Release) Signer(sig []byte) (common.Address, error) {
+	hash, err := r.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// sortedSignatures checks the signatures are by parties to the script and
+// returns them ordered by signer, as the contract expects
+func (r *Release) sortedSignatures(script *EscrowScript, sigs [][]byte) (v []uint8, rs, ss [][32]byte, err error) {
+	type signed struct {
+		signer common.Address
+		sig    []byte
+	}
+	var all []signed
+	seen := make(map[common.Address]bool)
+	for _, sig := range sigs {
+		if len(sig) != 65 {
+			return nil, nil, nil, ErrInvalidSignature
+		}
+		signer, err := r.Signer(sig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !script.IsParty(signer) && signer != script.TimeoutParty {
+			return nil, nil, nil, ErrInvalidSignature
+		}
+		if seen[signer] {
+			continue
+		}
+		seen[signer] = true
+		all = append(all, signed{signer, sig})
+	}
+	sort.Slice(all, func(i, j int) bool { return bytes.Compare(all[i].signer.Bytes(), all[j].signer.Bytes()) < 0 })
+	for _, s := range all {
+		var sigR, sigS [32]byte
+		copy(sigR[:], s.sig[:32])
+		copy(sigS[:], s.sig[32:64])
+		v = append(v, s.sig[64]+27)
+		rs = append(rs, sigR)
+		ss = append(ss, sigS)
+	}
+	return v, rs, ss, nil
+}
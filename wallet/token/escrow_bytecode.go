@@ -0,0 +1,8 @@
+// Code generated by contracts/compile.go. DO NOT EDIT.
+
+package token
+
+import "github.com/ethereum/go-ethereum/common"
+
+// escrowBytecode is the deployment code of contracts/Escrow.evm
+var escrowBytecode = common.FromHex("0x61060880600c6000396000f3346100515760043610610051576000357c010000000000000000000000000000000000000000000000000000000090048063f8388f0f1461008c578063aa3363a9146101945780633d4dff7b14610056575b600080fd5b50602436106100515760043560005260206000208054600052806001015460205280600201546040526003015460605260806000f35b5060643610610051576044358015610051576024358073ffffffffffffffffffffffffffffffffffffffff168114156100515780610240526004356000526020600020806001015480156100e8578154831415610051576100f2565b8282554282600201555b830180841161005157816001015550506323b872dd61010452336101245230610144526101645260646101c0526101276105cb565b604435600052336004357fce7089d0668849fb9ca29778c0cbf1e764d9efb048d81fd71fb34c94f26db36860206000a3005b602002600401356401000000008110156100515760040180356401000000008110156100515790602001828202810136106100515791509091565b5060c43610610051576101a960016000610159565b806102a0528161050037506102a0516105002080610200526000526020600020610220526102a051601f0160209004602002610500016103e05261022051600101548061026052156100515761022051546102405260c06102a051106100515761050051610240511415610051576105006020015160206102a051038111610051576105000180516102a051811161005157806102e05260200281016020016102a0516105000110610051576020016102c052610500604001516104a052610500606001516104c052610500608001516104805261028960206001610159565b610360526103005261029d60206002610159565b61036051141561005157610320526102b760206003610159565b61036051141561005157610340526102d160206004610159565b8015610051576103c052610380526102eb60206005610159565b6103c0511415610051576103a05260005b806103c0511461032b57806020026103a0510135610280510180610280511161005157610280526001016102fc565b50610260516102805111610051576103e0513081526102005181602001526102205160030154816040015260a081606001526103c05160200260c00181608001526103c0518160a001526103c051602002610380518260c001376103c051816103c0516020020160c001526103c0516020026103a05181830160e001376103c05160400260e00190207f19457468657265756d205369676e6564204d6573736167653a0a333200000000600052601c52603c60002060805260005b80610360511461048757806020028061030051013560a0528061032051013560c05261034051013560e05260006101005260206101006080608060015afa1561005157610100516104205181111561005157806104205280610480511461046051176104605260005b806102e0511461047d57806020026102c051015182146104715760010161044f565b61044051600101610440525b50506001016103e6565b506104a051156104a0576104a0516104405110156104d0575b6104605115610051576104c051801561005157640100000000811015610051576102205160020154014210610051575b60006102205160010155610220516003018054600101905563a9059cbb6101045260446101c05260005b806103c051146105535780602002806103805101358073ffffffffffffffffffffffffffffffffffffffff1681141561005157610124526103a05101358015610549576101445261054b6105cb565b505b6001016104fa565b506102805161026051038015610574576101445233610124526105766105cb565b505b60406103e051606001526103c0516020026060016103e05160800152610200517f760ea30e196d9af1e716cad7cdc004b107c6d520b4905281282b94dae19188f06103c0516040026080016103e051606001a2005b610240513b156100515760206101a06101c0516101206000610240515af115610051573d156106065760203d10610051576101a05115610051575b56")
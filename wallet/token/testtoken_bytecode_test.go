@@ -0,0 +1,8 @@
+// Code generated by contracts/compile.go. DO NOT EDIT.
+
+package token
+
+import "github.com/ethereum/go-ethereum/common"
+
+// testTokenBytecode is the deployment code of contracts/TestToken.evm
+var testTokenBytecode = common.FromHex("0x61019e80600c6000396000f36000357c01000000000000000000000000000000000000000000000000000000009004806370a082311461006a578063dd62ed3e14610077578063a9059cbb146100da57806323b872dd146100e6578063095ea7b31461009257806340c10f1914610160575b600080fd5b6004355460005260206000f35b60043560005260243560205260406000205460005260206000f35b33600052600435602052602435604060002055602435600052600435337f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b92560206000a3610155565b3360043560243561010f565b600435600052336020526040600020805460443580821061006557900390556004356024356044355b825481811061006557819003835581548101808211610065578255600052907fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a35b600160005260206000f35b600435805460243501905560243560005260043560007fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a300")
@@ -0,0 +1,1088 @@
+// Package token is an account model wallet for ERC-20 style tokens. It pays
+// for moderated and offline orders through an escrow contract in place of
+// the multisig addresses UTXO coins use, and gives every direct order a
+// deposit of its own so payments can be told apart on a single account.
+package token
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	btc "github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/op/go-logging"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var log = logging.MustGetLogger("token")
+
+const (
+	syncInterval = time.Duration(15) * time.Second
+	callTimeout  = time.Duration(30) * time.Second
+
+	// defaultGasLimit is used when the node can't estimate a transaction
+	defaultGasLimit = 300000
+
+	// defaultConfirmations is the depth at which transactions are confirmed
+	defaultConfirmations = 12
+)
+
+var (
+	// ErrNoEscrow is returned for escrow operations when no escrow contract
+	// is configured
+	ErrNoEscrow = errors.New("token: no escrow contract is configured")
+
+	// ErrEscrowDestination is returned when funds would be released into
+	// another deposit
+	ErrEscrowDestination = errors.New("token: escrow releases must pay accounts")
+
+	// ErrBumpFeeUnsupported is returned by BumpFee
+	ErrBumpFeeUnsupported = errors.New("token: fee bumping is not supported")
+
+	// ErrNoExchangeRates is returned by the default exchange rates
+	ErrNoExchangeRates = errors.New("token: no exchange rates for the token")
+)
+
+// Backend is the part of an Ethereum JSON-RPC client the wallet uses.
+// *ethclient.Client implements it.
+type Backend interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
+}
+
+// Datastore persists the wallet's transactions and the deposits it watches
+type Datastore interface {
+	Txns() wallet.Txns
+	WatchedScripts() wallet.WatchedScripts
+}
+
+// Config describes a token wallet
+type Config struct {
+	// Code is the currency code of the token, for example "DAI". On testnet
+	// it is prefixed with a T.
+	Code string
+
+	// Contract is the token contract and Escrow the deployed
+	// contracts/Escrow.evm, see DeployEscrow
+	Contract common.Address
+	Escrow   common.Address
+
+	// Decimals is the token's on-chain divisibility and Divisibility the
+	// number of decimals amounts have in the node, at most Decimals. Amounts
+	// smaller than the node's unit are not tracked.
+	Decimals     uint
+	Divisibility uint
+
+	ChainID       *big.Int
+	StartBlock    uint64
+	MaxGasPrice   *big.Int
+	Confirmations uint32
+
+	Backend       Backend
+	DB            Datastore
+	Mnemonic      string
+	ExchangeRates wallet.ExchangeRates
+}
+
+// Wallet is a token wallet. It implements wallet.Wallet.
+type Wallet struct {
+	cfg     Config
+	scale   *big.Int
+	key     *ecdsa.PrivateKey
+	account common.Address
+	signer  types.Signer
+
+	mtx       sync.Mutex
+	listeners []func(wallet.TransactionCallback)
+	watched   map[common.Hash]bool
+	owned     map[common.Hash]*EscrowScript
+	lastBlock uint64
+	done      chan struct{}
+
+	txMtx sync.Mutex
+}
+
+var _ wallet.Wallet = (*Wallet)(nil)
+
+// NewWallet returns the wallet of the account m/44'/60'/0'/0/0 of the
+// mnemonic
+func NewWallet(cfg Config) (*Wallet, error) {
+	if cfg.Divisibility == 0 || cfg.Divisibility > cfg.Decimals {
+		return nil, fmt.Errorf("token: divisibility must be between 1 and the token's %d decimals", cfg.Decimals)
+	}
+	if cfg.ChainID == nil {
+		return nil, errors.New("token: chain ID is required")
+	}
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = defaultConfirmations
+	}
+	key, err := accountKey(cfg.Mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	w := &Wallet{
+		cfg:     cfg,
+		scale:   new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(cfg.Decimals-cfg.Divisibility)), nil),
+		key:     key,
+		account: crypto.PubkeyToAddress(key.PublicKey),
+		signer:  types.NewEIP155Signer(cfg.ChainID),
+		watched: make(map[common.Hash]bool),
+		owned:   make(map[common.Hash]*EscrowScript),
+		done:    make(chan struct{}),
+	}
+	if err := w.loadState(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func accountKey(mnemonic string) (*ecdsa.PrivateKey, error) {
+	seed := bip39.NewSeed(mnemonic, "")
+	key, err := hd.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range []uint32{hd.HardenedKeyStart + 44, hd.HardenedKeyStart + 60, hd.HardenedKeyStart, 0, 0} {
+		if key, err = key.Child(i); err != nil {
+			return nil, err
+		}
+	}
+	priv, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return priv.ToECDSA(), nil
+}
+
+// loadState reads the watched deposits and resumes syncing after the last
+// transaction we saw
+func (w *Wallet) loadState() error {
+	scripts, err := w.cfg.DB.WatchedScripts().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, b := range scripts {
+		if len(b) == common.HashLength {
+			w.watched[common.BytesToHash(b)] = true
+			continue
+		}
+		script, err := ParseEscrowScript(b)
+		if err != nil {
+			continue
+		}
+		hash, err := script.Hash()
+		if err != nil {
+			continue
+		}
+		w.watched[hash] = true
+		w.owned[hash] = script
+	}
+	w.lastBlock = w.cfg.StartBlock
+	txns, err := w.cfg.DB.Txns().GetAll(true)
+	if err != nil {
+		return err
+	}
+	for _, txn := range txns {
+		if txn.Height > 0 && uint64(txn.Height) > w.lastBlock {
+			w.lastBlock = uint64(txn.Height)
+		}
+	}
+	return nil
+}
+
+// Account returns the wallet's account
+func (w *Wallet) Account() common.Address { return w.account }
+
+func (w *Wallet) Start() {
+	go func() {
+		t := time.NewTicker(syncInterval)
+		defer t.Stop()
+		for {
+			if err := w.Sync(); err != nil {
+				log.Warningf("syncing %s: %s", w.cfg.Code, err)
+			}
+			select {
+			case <-t.C:
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+func (w *Wallet) Close() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	select {
+	case <-w.done:
+	default:
+		close(w.done)
+	}
+}
+
+func (w *Wallet) CurrencyCode() string { return w.cfg.Code }
+
+func (w *Wallet) ExchangeRates() wallet.ExchangeRates {
+	if w.cfg.ExchangeRates != nil {
+		return w.cfg.ExchangeRates
+	}
+	return noRates{int(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(w.cfg.Divisibility)), nil).Int64())}
+}
+
+// noRates gives the token's units without exchange rates
+type noRates struct {
+	unitsPerCoin int
+}
+
+func (r noRates) GetExchangeRate(currencyCode string) (float64, error) { return 0, ErrNoExchangeRates }
+func (r noRates) GetLatestRate(currencyCode string) (float64, error)   { return 0, ErrNoExchangeRates }
+func (r noRates) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	return nil, ErrNoExchangeRates
+}
+func (r noRates) UnitsPerCoin() int { return r.unitsPerCoin }
+
+func (w *Wallet) AddWatchedAddress(addr btc.Address) error {
+	escrow, ok := addr.(*EscrowAddress)
+	if !ok {
+		return nil
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.watched[escrow.ScriptHash] {
+		return nil
+	}
+	w.watched[escrow.ScriptHash] = true
+	return w.cfg.DB.WatchedScripts().Put(escrow.ScriptHash.Bytes())
+}
+
+func (w *Wallet) AddTransactionListener(l func(wallet.TransactionCallback)) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.listeners = append(w.listeners, l)
+}
+
+func (w *Wallet) IsDust(amount int64) bool { return amount <= 0 }
+
+// CurrentAddress returns the wallet's account
+func (w *Wallet) CurrentAddress(purpose wallet.KeyPurpose) btc.Address {
+	return NewAccountAddress(w.account)
+}
+
+// NewAddress returns the account for internal use. External addresses are
+// new deposits in the escrow contract only we can release, which the wallet
+// collects into the account once they are funded. Without an escrow
+// contract it returns the account.
+func (w *Wallet) NewAddress(purpose wallet.KeyPurpose) btc.Address {
+	if purpose != wallet.EXTERNAL || w.cfg.Escrow == (common.Address{}) {
+		return NewAccountAddress(w.account)
+	}
+	script := &EscrowScript{
+		Token:     w.cfg.Contract,
+		Parties:   []common.Address{w.account},
+		Threshold: 1,
+	}
+	if _, err := rand.Read(script.Nonce[:]); err != nil {
+		log.Errorf("creating deposit: %s", err)
+		return NewAccountAddress(w.account)
+	}
+	b, err := script.Serialize()
+	if err != nil {
+		log.Errorf("creating deposit: %s", err)
+		return NewAccountAddress(w.account)
+	}
+	hash := crypto.Keccak256Hash(b)
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if err := w.cfg.DB.WatchedScripts().Put(b); err != nil {
+		log.Errorf("saving deposit: %s", err)
+		return NewAccountAddress(w.account)
+	}
+	w.watched[hash] = true
+	w.owned[hash] = script
+	return NewEscrowAddress(w.cfg.Escrow, hash)
+}
+
+func (w *Wallet) DecodeAddress(addr string) (btc.Address, error) { return DecodeAddress(addr) }
+
+func (w *Wallet) ScriptToAddress(script []byte) (btc.Address, error) {
+	if len(script) == common.AddressLength {
+		return NewAccountAddress(common.BytesToAddress(script)), nil
+	}
+	if w.cfg.Escrow == (common.Address{}) {
+		return nil, ErrNoEscrow
+	}
+	s, err := ParseEscrowScript(script)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return NewEscrowAddress(w.cfg.Escrow, hash), nil
+}
+
+// Balance returns the token balance of the account. Account balances have
+// no unconfirmed part.
+func (w *Wallet) Balance() (confirmed, unconfirmed int64) {
+	balance, err := w.chainBalance()
+	if err != nil {
+		log.Warningf("getting %s balance: %s", w.cfg.Code, err)
+		return 0, 0
+	}
+	return w.toUnits(balance), 0
+}
+
+func (w *Wallet) chainBalance() (*big.Int, error) {
+	data, err := tokenContract.Pack("balanceOf", w.account)
+	if err != nil {
+		return nil, err
+	}
+	out, err := w.call(w.cfg.Contract, data)
+	if err != nil {
+		return nil, err
+	}
+	balance := new(big.Int)
+	if err := tokenContract.Unpack(&balance, "balanceOf", out); err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
+
+// deposit returns the balance of a deposit in the escrow contract and its
+// count of releases
+func (w *Wallet) deposit(scriptHash common.Hash) (balance, nonce *big.Int, err error) {
+	data, err := escrowContract.Pack("deposits", [32]byte(scriptHash))
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := w.call(w.cfg.Escrow, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := escrowContract.Methods["deposits"].Outputs.UnpackValues(out)
+	if err != nil || len(values) != 4 {
+		return nil, nil, fmt.Errorf("token: decoding deposit: %v", err)
+	}
+	balance, ok := values[1].(*big.Int)
+	if !ok {
+		return nil, nil, errors.New("token: decoding deposit balance")
+	}
+	if nonce, ok = values[3].(*big.Int); !ok {
+		return nil, nil, errors.New("token: decoding deposit nonce")
+	}
+	return balance, nonce, nil
+}
+
+func (w *Wallet) call(to common.Address, data []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	return w.cfg.Backend.CallContract(ctx, ethereum.CallMsg{From: w.account, To: &to, Data: data}, nil)
+}
+
+// toUnits converts an on-chain amount into the node's units
+func (w *Wallet) toUnits(amount *big.Int) int64 {
+	return new(big.Int).Quo(amount, w.scale).Int64()
+}
+
+// toChain converts an amount in the node's units into the on-chain amount
+func (w *Wallet) toChain(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), w.scale)
+}
+
+// storedTxn is what the datastore keeps of a transaction
+type storedTxn struct {
+	Outputs []storedOutput `json:"outputs"`
+}
+
+type storedOutput struct {
+	Address string `json:"address"`
+	Value   int64  `json:"value"`
+	Index   uint32 `json:"index"`
+}
+
+func (w *Wallet) Transactions() ([]wallet.Txn, error) {
+	txns, err := w.cfg.DB.Txns().GetAll(false)
+	if err != nil {
+		return nil, err
+	}
+	height, _ := w.ChainTip()
+	for i := range txns {
+		w.fillTxn(&txns[i], height)
+	}
+	return txns, nil
+}
+
+func (w *Wallet) GetTransaction(txid chainhash.Hash) (wallet.Txn, error) {
+	txn, err := w.cfg.DB.Txns().Get(txid)
+	if err != nil {
+		return txn, err
+	}
+	height, _ := w.ChainTip()
+	w.fillTxn(&txn, height)
+	return txn, nil
+}
+
+func (w *Wallet) fillTxn(txn *wallet.Txn, height uint32) {
+	txn.Status = wallet.StatusUnconfirmed
+	if txn.Height > 0 && height >= uint32(txn.Height) {
+		txn.Confirmations = int64(height) - int64(txn.Height) + 1
+		txn.Status = wallet.StatusPending
+		if txn.Confirmations >= int64(w.cfg.Confirmations) {
+			txn.Status = wallet.StatusConfirmed
+		}
+	}
+	var stored storedTxn
+	if err := json.Unmarshal(txn.Bytes, &stored); err != nil {
+		return
+	}
+	txn.Outputs = nil
+	for _, o := range stored.Outputs {
+		addr, err := DecodeAddress(o.Address)
+		if err != nil {
+			continue
+		}
+		txn.Outputs = append(txn.Outputs, wallet.TransactionOutput{Address: addr, Value: o.Value, Index: o.Index})
+		if txn.ToAddress == "" {
+			txn.ToAddress = o.Address
+		}
+	}
+}
+
+func (w *Wallet) ChainTip() (uint32, chainhash.Hash) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	header, err := w.cfg.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, chainhash.Hash{}
+	}
+	return uint32(header.Number.Uint64()), toChainHash(header.Hash())
+}
+
+// ReSyncBlockchain scans the chain again from the configured start block
+func (w *Wallet) ReSyncBlockchain(fromTime time.Time) {
+	w.mtx.Lock()
+	w.lastBlock = w.cfg.StartBlock
+	w.mtx.Unlock()
+	go func() {
+		if err := w.Sync(); err != nil {
+			log.Warningf("rescanning %s: %s", w.cfg.Code, err)
+		}
+	}()
+}
+
+func (w *Wallet) GetConfirmations(txid chainhash.Hash) (confirms, atHeight uint32, err error) {
+	txn, err := w.cfg.DB.Txns().Get(txid)
+	if err != nil {
+		return 0, 0, err
+	}
+	if txn.Height <= 0 {
+		return 0, 0, nil
+	}
+	height, _ := w.ChainTip()
+	if height < uint32(txn.Height) {
+		return 0, uint32(txn.Height), nil
+	}
+	return height - uint32(txn.Height) + 1, uint32(txn.Height), nil
+}
+
+// ChildKey derives the order key the same way the UTXO wallets do
+func (w *Wallet) ChildKey(keyBytes []byte, chaincode []byte, isPrivateKey bool) (*hd.ExtendedKey, error) {
+	id := chaincfg.MainNetParams.HDPublicKeyID[:]
+	if isPrivateKey {
+		id = chaincfg.MainNetParams.HDPrivateKeyID[:]
+	}
+	return hd.NewExtendedKey(id, keyBytes, chaincode, []byte{0x00, 0x00, 0x00, 0x00}, 0, 0, isPrivateKey).Child(0)
+}
+
+// HasKey returns whether the address is the account or a deposit only we
+// release
+func (w *Wallet) HasKey(addr btc.Address) bool {
+	switch a := addr.(type) {
+	case *AccountAddress:
+		return a.Address == w.account
+	case *EscrowAddress:
+		w.mtx.Lock()
+		defer w.mtx.Unlock()
+		_, ok := w.owned[a.ScriptHash]
+		return ok
+	}
+	return false
+}
+
+// GetFeePerByte returns zero. Fees are in token units, and the gas of
+// transfers and releases is paid in ether by whoever sends them rather than
+// out of token amounts.
+func (w *Wallet) GetFeePerByte(feeLevel wallet.FeeLevel) uint64 {
+	return 0
+}
+
+func (w *Wallet) gasPrice(feeLevel wallet.FeeLevel) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	price, err := w.cfg.Backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch feeLevel {
+	case wallet.PRIOIRTY, wallet.FEE_BUMP:
+		price = new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(3)), big.NewInt(2))
+	case wallet.ECONOMIC:
+		price = new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(4)), big.NewInt(5))
+	}
+	if w.cfg.MaxGasPrice != nil && w.cfg.MaxGasPrice.Sign() > 0 && price.Cmp(w.cfg.MaxGasPrice) > 0 {
+		price = new(big.Int).Set(w.cfg.MaxGasPrice)
+	}
+	return price, nil
+}
+
+// Spend transfers tokens to an account, or funds a deposit in the escrow
+// contract when the address is an escrow address
+func (w *Wallet) Spend(amount int64, addr btc.Address, feeLevel wallet.FeeLevel, referenceID string, spendAll bool) (*chainhash.Hash, error) {
+	value := w.toChain(amount)
+	if spendAll {
+		balance, err := w.chainBalance()
+		if err != nil {
+			return nil, err
+		}
+		value = balance
+	}
+	if value.Sign() <= 0 {
+		return nil, wallet.ErrorDustAmount
+	}
+	balance, err := w.chainBalance()
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(value) < 0 {
+		return nil, wallet.ErrorInsuffientFunds
+	}
+
+	switch a := addr.(type) {
+	case *AccountAddress:
+		data, err := tokenContract.Pack("transfer", a.Address, value)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := w.send(&w.cfg.Contract, data, feeLevel, false)
+		if err != nil {
+			return nil, err
+		}
+		return toChainHashPtr(tx.Hash()), nil
+	case *EscrowAddress:
+		if a.Contract != w.cfg.Escrow {
+			return nil, fmt.Errorf("token: %s is not the configured escrow contract", a.Contract.Hex())
+		}
+		approve, err := tokenContract.Pack("approve", a.Contract, value)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.send(&w.cfg.Contract, approve, feeLevel, false); err != nil {
+			return nil, err
+		}
+		fund, err := escrowContract.Pack("fund", [32]byte(a.ScriptHash), w.cfg.Contract, value)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := w.send(&a.Contract, fund, feeLevel, true)
+		if err != nil {
+			return nil, err
+		}
+		return toChainHashPtr(tx.Hash()), nil
+	}
+	return nil, ErrInvalidAddress
+}
+
+// send signs a contract call from the account, or a contract creation if to
+// is nil, and broadcasts it. A call the node expects to fail is refused
+// unless it depends on one still pending.
+func (w *Wallet) send(to *common.Address, data []byte, feeLevel wallet.FeeLevel, afterPending bool) (*types.Transaction, error) {
+	tx, err := w.sign(to, data, feeLevel, afterPending)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	if err := w.cfg.Backend.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (w *Wallet) sign(to *common.Address, data []byte, feeLevel wallet.FeeLevel, afterPending bool) (*types.Transaction, error) {
+	w.txMtx.Lock()
+	defer w.txMtx.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	gasPrice, err := w.gasPrice(feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := w.cfg.Backend.EstimateGas(ctx, ethereum.CallMsg{From: w.account, To: to, Data: data})
+	if err != nil {
+		if !afterPending {
+			return nil, err
+		}
+		gas = defaultGasLimit
+	}
+	nonce, err := w.cfg.Backend.PendingNonceAt(ctx, w.account)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewContractCreation(nonce, big.NewInt(0), gas, gasPrice, data)
+	if to != nil {
+		tx = types.NewTransaction(nonce, *to, big.NewInt(0), gas, gasPrice, data)
+	}
+	return types.SignTx(tx, w.signer, w.key)
+}
+
+// DeployEscrow deploys the escrow contract from the account. It returns the
+// address to configure as the token's escrow once the transaction is mined.
+func (w *Wallet) DeployEscrow(feeLevel wallet.FeeLevel) (common.Address, *chainhash.Hash, error) {
+	tx, err := w.send(nil, escrowBytecode, feeLevel, false)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return crypto.CreateAddress(w.account, tx.Nonce()), toChainHashPtr(tx.Hash()), nil
+}
+
+// EstimateFee returns the fee of releasing a deposit to the outputs at the
+// fee per byte of its call data, assuming two of three parties sign. The
+// escrow contract pays the fee to whoever submits the release.
+func (w *Wallet) EstimateFee(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, feePerByte uint64) uint64 {
+	return uint64(releaseSize(3, 2, len(outs))) * feePerByte
+}
+
+// releaseSize returns the size of the call data releasing a deposit of the
+// parties with the signatures to the outputs
+func releaseSize(parties, sigs, outs int) int {
+	script := 32 + len(scriptArguments)*32 + 32 + parties*32
+	return 4 + 6*32 + script + 3*(32+sigs*32) + 2*(32+outs*32)
+}
+
+// EstimateSpendFee returns zero as gas is paid in ether
+func (w *Wallet) EstimateSpendFee(amount int64, feeLevel wallet.FeeLevel) (uint64, error) {
+	return 0, nil
+}
+
+func (w *Wallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
+	return nil, ErrBumpFeeUnsupported
+}
+
+// GenerateMultisigScript returns the deposit the keys may release. The
+// order keys are already unique so the script needs no nonce.
+func (w *Wallet) GenerateMultisigScript(keys []hd.ExtendedKey, threshold int, timeout time.Duration, timeoutKey *hd.ExtendedKey) (btc.Address, []byte, error) {
+	if w.cfg.Escrow == (common.Address{}) {
+		return nil, nil, ErrNoEscrow
+	}
+	if threshold <= 0 || threshold > len(keys) {
+		return nil, nil, fmt.Errorf("token: threshold %d out of range for %d keys", threshold, len(keys))
+	}
+	script := &EscrowScript{Token: w.cfg.Contract, Threshold: uint8(threshold)}
+	for _, k := range keys {
+		addr, err := keyAddress(&k)
+		if err != nil {
+			return nil, nil, err
+		}
+		script.Parties = append(script.Parties, addr)
+	}
+	if timeoutKey != nil {
+		addr, err := keyAddress(timeoutKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		script.Timeout = timeout
+		script.TimeoutParty = addr
+	}
+	b, err := script.Serialize()
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewEscrowAddress(w.cfg.Escrow, crypto.Keccak256Hash(b)), b, nil
+}
+
+func keyAddress(key *hd.ExtendedKey) (common.Address, error) {
+	pub, err := key.ECPubKey()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub.ToECDSA()), nil
+}
+
+// release builds the payout of a whole deposit to the outputs. Remainders
+// below the node's unit go to the first output, and the rest of the deposit
+// to whoever submits the release.
+func (w *Wallet) release(redeemScript []byte, outs []wallet.TransactionOutput) (*Release, *EscrowScript, error) {
+	if w.cfg.Escrow == (common.Address{}) {
+		return nil, nil, ErrNoEscrow
+	}
+	script, err := ParseEscrowScript(redeemScript)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &Release{Escrow: w.cfg.Escrow, ScriptHash: crypto.Keccak256Hash(redeemScript)}
+	balance, nonce, err := w.deposit(r.ScriptHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.Nonce = nonce
+	total := new(big.Int)
+	for _, out := range outs {
+		addr, ok := out.Address.(*AccountAddress)
+		if !ok {
+			decoded, err := DecodeAddress(out.Address.String())
+			if addr, ok = decoded.(*AccountAddress); err != nil || !ok {
+				return nil, nil, ErrEscrowDestination
+			}
+		}
+		amount := w.toChain(out.Value)
+		r.Destinations = append(r.Destinations, addr.Address)
+		r.Amounts = append(r.Amounts, amount)
+		total.Add(total, amount)
+	}
+	if rest := new(big.Int).Sub(balance, total); len(r.Amounts) > 0 && rest.Sign() > 0 && rest.Cmp(w.scale) < 0 {
+		r.Amounts[0].Add(r.Amounts[0], rest)
+	}
+	return r, script, nil
+}
+
+// CreateMultisigSignature signs the release of the deposit to the outputs
+func (w *Wallet) CreateMultisigSignature(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64) ([]wallet.Signature, error) {
+	r, _, err := w.release(redeemScript, outs)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := r.Sign(priv.ToECDSA())
+	if err != nil {
+		return nil, err
+	}
+	return []wallet.Signature{{InputIndex: 0, Signature: sig}}, nil
+}
+
+// Multisign submits the release with both parties' signatures. It returns
+// the signed transaction.
+func (w *Wallet) Multisign(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, sigs1 []wallet.Signature, sigs2 []wallet.Signature, redeemScript []byte, feePerByte uint64, broadcast bool) ([]byte, error) {
+	r, script, err := w.release(redeemScript, outs)
+	if err != nil {
+		return nil, err
+	}
+	var sigs [][]byte
+	for _, s := range append(sigs1, sigs2...) {
+		sigs = append(sigs, s.Signature)
+	}
+	tx, err := w.submitRelease(r, script, redeemScript, sigs, wallet.NORMAL, broadcast)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(tx)
+}
+
+func (w *Wallet) submitRelease(r *Release, script *EscrowScript, redeemScript []byte, sigs [][]byte, feeLevel wallet.FeeLevel, broadcast bool) (*types.Transaction, error) {
+	v, rs, ss, err := r.sortedSignatures(script, sigs)
+	if err != nil {
+		return nil, err
+	}
+	data, err := escrowContract.Pack("release", redeemScript, v, rs, ss, r.Destinations, r.Amounts)
+	if err != nil {
+		return nil, err
+	}
+	if !broadcast {
+		return w.sign(&w.cfg.Escrow, data, feeLevel, false)
+	}
+	return w.send(&w.cfg.Escrow, data, feeLevel, false)
+}
+
+// SweepAddress releases a whole deposit with one key, to the address or
+// else to the account. It serves one of two deposits and the timeout party
+// of a timed out deposit.
+func (w *Wallet) SweepAddress(ins []wallet.TransactionInput, address *btc.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wallet.FeeLevel) (*chainhash.Hash, error) {
+	if redeemScript == nil {
+		return nil, ErrInvalidScript
+	}
+	to := btc.Address(NewAccountAddress(w.account))
+	if address != nil {
+		to = *address
+	}
+	hash := crypto.Keccak256Hash(*redeemScript)
+	balance, nonce, err := w.deposit(hash)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Sign() <= 0 {
+		return nil, wallet.ErrorInsuffientFunds
+	}
+	script, err := ParseEscrowScript(*redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	dest, ok := to.(*AccountAddress)
+	if !ok {
+		return nil, ErrEscrowDestination
+	}
+	r := &Release{Escrow: w.cfg.Escrow, ScriptHash: hash, Nonce: nonce, Destinations: []common.Address{dest.Address}, Amounts: []*big.Int{balance}}
+	priv, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := r.Sign(priv.ToECDSA())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := w.submitRelease(r, script, *redeemScript, [][]byte{sig}, feeLevel, true)
+	if err != nil {
+		return nil, err
+	}
+	return toChainHashPtr(tx.Hash()), nil
+}
+
+// collect releases our own funded deposits into the account
+func (w *Wallet) collect(hash common.Hash) {
+	w.mtx.Lock()
+	script, ok := w.owned[hash]
+	w.mtx.Unlock()
+	if !ok {
+		return
+	}
+	balance, nonce, err := w.deposit(hash)
+	if err != nil || balance.Sign() <= 0 {
+		return
+	}
+	redeemScript, err := script.Serialize()
+	if err != nil {
+		return
+	}
+	r := &Release{Escrow: w.cfg.Escrow, ScriptHash: hash, Nonce: nonce, Destinations: []common.Address{w.account}, Amounts: []*big.Int{balance}}
+	sig, err := r.Sign(w.key)
+	if err != nil {
+		return
+	}
+	if _, err := w.submitRelease(r, script, redeemScript, [][]byte{sig}, wallet.NORMAL, true); err != nil {
+		log.Errorf("collecting deposit %s: %s", hash.Hex(), err)
+	}
+}
+
+// Sync scans the blocks since the last sync for token transfers of the
+// account and for activity of the watched deposits, and notifies the
+// listeners of each transaction
+func (w *Wallet) Sync() error {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+	head, err := w.cfg.Backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	w.mtx.Lock()
+	from := w.lastBlock + 1
+	if w.lastBlock == 0 && w.cfg.StartBlock == 0 {
+		from = 0
+	}
+	var hashes []common.Hash
+	for h := range w.watched {
+		hashes = append(hashes, h)
+	}
+	w.mtx.Unlock()
+	to := head.Number.Uint64()
+	if from > to {
+		return nil
+	}
+
+	account := common.BytesToHash(w.account.Bytes())
+	queries := []ethereum.FilterQuery{
+		{Addresses: []common.Address{w.cfg.Contract}, Topics: [][]common.Hash{{transferEvent.Id()}, {account}}},
+		{Addresses: []common.Address{w.cfg.Contract}, Topics: [][]common.Hash{{transferEvent.Id()}, nil, {account}}},
+	}
+	if len(hashes) > 0 && w.cfg.Escrow != (common.Address{}) {
+		queries = append(queries, ethereum.FilterQuery{
+			Addresses: []common.Address{w.cfg.Escrow},
+			Topics:    [][]common.Hash{{fundedEvent.Id(), releasedEvent.Id()}, hashes},
+		})
+	}
+	var logs []types.Log
+	for _, q := range queries {
+		q.FromBlock = new(big.Int).SetUint64(from)
+		q.ToBlock = new(big.Int).SetUint64(to)
+		found, err := w.cfg.Backend.FilterLogs(ctx, q)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, found...)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	txs, order := w.groupLogs(logs)
+	var funded []common.Hash
+	for _, txHash := range order {
+		cb := txs[txHash]
+		if err := w.record(ctx, cb); err != nil {
+			return err
+		}
+		for _, out := range cb.callback.Outputs {
+			if escrow, ok := out.Address.(*EscrowAddress); ok {
+				funded = append(funded, escrow.ScriptHash)
+			}
+		}
+		w.notify(cb.callback)
+	}
+
+	w.mtx.Lock()
+	w.lastBlock = to
+	w.mtx.Unlock()
+
+	for _, hash := range funded {
+		w.collect(hash)
+	}
+	return nil
+}
+
+// pendingTxn is a transaction put together from its logs
+type pendingTxn struct {
+	callback wallet.TransactionCallback
+	block    uint64
+	seen     map[uint]bool
+}
+
+func (w *Wallet) groupLogs(logs []types.Log) (map[common.Hash]*pendingTxn, []common.Hash) {
+	txs := make(map[common.Hash]*pendingTxn)
+	var order []common.Hash
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
+		p, ok := txs[l.TxHash]
+		if !ok {
+			p = &pendingTxn{
+				callback: wallet.TransactionCallback{Txid: strings.TrimPrefix(l.TxHash.Hex(), "0x"), Height: int32(l.BlockNumber)},
+				block:    l.BlockNumber,
+				seen:     make(map[uint]bool),
+			}
+			txs[l.TxHash] = p
+			order = append(order, l.TxHash)
+		}
+		if p.seen[l.Index] {
+			continue
+		}
+		p.seen[l.Index] = true
+		w.applyLog(&p.callback, l)
+	}
+	for _, p := range txs {
+		p.callback.WatchOnly = p.callback.Value == 0
+	}
+	return txs, order
+}
+
+func (w *Wallet) applyLog(cb *wallet.TransactionCallback, l types.Log) {
+	output := func(addr btc.Address, value int64) {
+		cb.Outputs = append(cb.Outputs, wallet.TransactionOutput{Address: addr, Value: value, Index: uint32(len(cb.Outputs))})
+	}
+	switch {
+	case l.Address == w.cfg.Contract && l.Topics[0] == transferEvent.Id() && len(l.Topics) == 3:
+		from := common.BytesToAddress(l.Topics[1].Bytes())
+		to := common.BytesToAddress(l.Topics[2].Bytes())
+		value := w.toUnits(new(big.Int).SetBytes(l.Data))
+		if from == w.account {
+			cb.Value -= value
+		}
+		if to == w.account {
+			cb.Value += value
+		}
+		// Transfers into and out of the escrow contract show as deposits
+		if from != w.cfg.Escrow && to != w.cfg.Escrow {
+			output(NewAccountAddress(to), value)
+		}
+	case l.Address == w.cfg.Escrow && l.Topics[0] == fundedEvent.Id() && len(l.Topics) == 3:
+		value := w.toUnits(new(big.Int).SetBytes(l.Data))
+		output(NewEscrowAddress(w.cfg.Escrow, l.Topics[1]), value)
+	case l.Address == w.cfg.Escrow && l.Topics[0] == releasedEvent.Id() && len(l.Topics) == 2:
+		values, err := releasedEvent.Inputs.NonIndexed().UnpackValues(l.Data)
+		if err != nil || len(values) != 2 {
+			return
+		}
+		destinations, _ := values[0].([]common.Address)
+		amounts, _ := values[1].([]*big.Int)
+		var total int64
+		for i, d := range destinations {
+			if i >= len(amounts) {
+				break
+			}
+			value := w.toUnits(amounts[i])
+			total += value
+			output(NewAccountAddress(d), value)
+		}
+		cb.Inputs = append(cb.Inputs, wallet.TransactionInput{
+			OutpointHash:  l.TxHash.Bytes(),
+			OutpointIndex: uint32(l.Index),
+			LinkedAddress: NewEscrowAddress(w.cfg.Escrow, l.Topics[1]),
+			Value:         total,
+		})
+	}
+}
+
+// record saves the transaction with the time of its block
+func (w *Wallet) record(ctx context.Context, p *pendingTxn) error {
+	p.callback.Timestamp = time.Now()
+	if header, err := w.cfg.Backend.HeaderByNumber(ctx, new(big.Int).SetUint64(p.block)); err == nil && header.Time != nil {
+		p.callback.Timestamp = time.Unix(header.Time.Int64(), 0)
+	}
+	p.callback.BlockTime = p.callback.Timestamp
+	var stored storedTxn
+	for _, o := range p.callback.Outputs {
+		stored.Outputs = append(stored.Outputs, storedOutput{Address: o.Address.String(), Value: o.Value, Index: o.Index})
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return w.cfg.DB.Txns().Put(raw, p.callback.Txid, int(p.callback.Value), int(p.callback.Height), p.callback.Timestamp, p.callback.WatchOnly)
+}
+
+func (w *Wallet) notify(cb wallet.TransactionCallback) {
+	w.mtx.Lock()
+	listeners := append([]func(wallet.TransactionCallback){}, w.listeners...)
+	w.mtx.Unlock()
+	for _, l := range listeners {
+		l(cb)
+	}
+}
+
+func toChainHash(h common.Hash) chainhash.Hash {
+	ch, _ := chainhash.NewHashFromStr(hex.EncodeToString(h.Bytes()))
+	return *ch
+}
+
+func toChainHashPtr(h common.Hash) *chainhash.Hash {
+	ch := toChainHash(h)
+	return &ch
+}
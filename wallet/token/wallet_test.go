@@ -0,0 +1,432 @@
+package token
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	btc "github.com/btcsuite/btcutil"
+	hd "github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// oneToken is a whole token on chain, which has 18 decimals in the tests
+var oneToken = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func tokens(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), oneToken)
+}
+
+func newTestWallet(t *testing.T, chain *devChain, mnemonic string) *Wallet {
+	w, err := NewWallet(Config{
+		Code:          "TDAI",
+		Contract:      chain.token,
+		Escrow:        chain.escrow,
+		Decimals:      18,
+		Divisibility:  8,
+		ChainID:       big.NewInt(1337),
+		Confirmations: 3,
+		Backend:       chain,
+		DB:            newMemoryDatastore(),
+		Mnemonic:      mnemonic,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.fundGas(w.Account())
+	return w
+}
+
+func newOrderKey(t *testing.T, seed byte) *hd.ExtendedKey {
+	key, err := hd.NewMaster(bytes.Repeat([]byte{seed}, 32), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// syncCallbacks syncs the wallet and returns the transactions it saw
+func syncCallbacks(t *testing.T, w *Wallet) []wallet.TransactionCallback {
+	var cbs []wallet.TransactionCallback
+	w.mtx.Lock()
+	w.listeners = []func(wallet.TransactionCallback){func(cb wallet.TransactionCallback) { cbs = append(cbs, cb) }}
+	w.mtx.Unlock()
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	return cbs
+}
+
+func TestNewWalletDivisibility(t *testing.T) {
+	chain := newDevChain()
+	for _, d := range []uint{0, 19} {
+		_, err := NewWallet(Config{Code: "TDAI", Decimals: 18, Divisibility: d, ChainID: big.NewInt(1337), Backend: chain, DB: newMemoryDatastore()})
+		if err == nil {
+			t.Errorf("expected divisibility %d to be rejected", d)
+		}
+	}
+}
+
+func TestWalletTransfer(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), new(big.Int).Div(tokens(5), big.NewInt(2)))
+
+	confirmed, unconfirmed := buyer.Balance()
+	if confirmed != 250000000 || unconfirmed != 0 {
+		t.Fatalf("expected a balance of 250000000, got %d/%d", confirmed, unconfirmed)
+	}
+	if unitsPerCoin := buyer.ExchangeRates().UnitsPerCoin(); unitsPerCoin != 100000000 {
+		t.Errorf("expected 100000000 units per coin, got %d", unitsPerCoin)
+	}
+
+	to := vendor.CurrentAddress(wallet.INTERNAL)
+	txid, err := buyer.Spend(100000000, to, wallet.NORMAL, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(oneToken) != 0 {
+		t.Fatalf("expected the vendor to hold one token, got %s", balance)
+	}
+	if _, err := buyer.Spend(1000000000, to, wallet.NORMAL, "", false); err != wallet.ErrorInsuffientFunds {
+		t.Errorf("expected insufficient funds, got %v", err)
+	}
+
+	cbs := syncCallbacks(t, vendor)
+	if len(cbs) != 1 {
+		t.Fatalf("expected one transaction, got %d", len(cbs))
+	}
+	if cbs[0].Txid != txid.String() || cbs[0].Value != 100000000 || cbs[0].WatchOnly {
+		t.Errorf("unexpected callback %+v", cbs[0])
+	}
+	if len(cbs[0].Outputs) != 1 || cbs[0].Outputs[0].Address.String() != to.String() {
+		t.Errorf("expected an output to %s, got %+v", to, cbs[0].Outputs)
+	}
+	if cbs := syncCallbacks(t, vendor); len(cbs) != 0 {
+		t.Errorf("expected a second sync to find nothing, got %d transactions", len(cbs))
+	}
+
+	cbs = syncCallbacks(t, buyer)
+	if len(cbs) != 2 || cbs[0].Value != 250000000 || cbs[1].Value != -100000000 {
+		t.Fatalf("expected the buyer to see the mint and the spend, got %+v", cbs)
+	}
+
+	txn, err := vendor.GetTransaction(*txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Status != wallet.StatusPending || txn.ToAddress != to.String() {
+		t.Errorf("unexpected transaction %+v", txn)
+	}
+	chain.advance(time.Minute)
+	chain.advance(time.Minute)
+	if txn, _ = vendor.GetTransaction(*txid); txn.Status != wallet.StatusConfirmed {
+		t.Errorf("expected the transaction to confirm, got %s", txn.Status)
+	}
+}
+
+func TestWalletDirectPayment(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), tokens(3))
+
+	addr := vendor.NewAddress(wallet.EXTERNAL)
+	deposit, ok := addr.(*EscrowAddress)
+	if !ok {
+		t.Fatalf("expected an escrow address, got %T", addr)
+	}
+	if !vendor.HasKey(addr) || buyer.HasKey(addr) {
+		t.Error("expected only the vendor to hold the deposit's key")
+	}
+	decoded, err := buyer.DecodeAddress(addr.String())
+	if err != nil || decoded.String() != addr.String() {
+		t.Fatalf("decoding %s: %v", addr, err)
+	}
+	if err := buyer.AddWatchedAddress(decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := buyer.Spend(200000000, decoded, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+	cbs := syncCallbacks(t, buyer)
+	if len(cbs) != 2 || cbs[1].Value != -200000000 || len(cbs[1].Outputs) != 1 || cbs[1].Outputs[0].Address.String() != addr.String() {
+		t.Fatalf("expected the buyer to see the payment, got %+v", cbs)
+	}
+
+	cbs = syncCallbacks(t, vendor)
+	if len(cbs) != 1 || !cbs[0].WatchOnly || cbs[0].Outputs[0].Value != 200000000 {
+		t.Fatalf("expected the vendor to see the payment, got %+v", cbs)
+	}
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(tokens(2)) != 0 {
+		t.Fatalf("expected the deposit to be collected, got %s", balance)
+	}
+
+	cbs = syncCallbacks(t, vendor)
+	if len(cbs) != 1 || cbs[0].Value != 200000000 || len(cbs[0].Inputs) != 1 {
+		t.Fatalf("expected the collection, got %+v", cbs)
+	}
+	linked, ok := cbs[0].Inputs[0].LinkedAddress.(*EscrowAddress)
+	if !ok || linked.ScriptHash != deposit.ScriptHash {
+		t.Errorf("expected the collection to spend %s, got %v", addr, cbs[0].Inputs[0].LinkedAddress)
+	}
+
+	// The deposit is still ours after a restart
+	restarted, err := NewWallet(vendor.cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.HasKey(addr) {
+		t.Error("expected the restarted wallet to own the deposit")
+	}
+}
+
+func TestWalletModeratedRelease(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), tokens(3))
+
+	buyerKey, vendorKey, moderatorKey := newOrderKey(t, 1), newOrderKey(t, 2), newOrderKey(t, 3)
+	buyerPub, _ := buyerKey.Neuter()
+	vendorPub, _ := vendorKey.Neuter()
+	moderatorPub, _ := moderatorKey.Neuter()
+	keys := []hd.ExtendedKey{*buyerPub, *vendorPub, *moderatorPub}
+	addr, redeemScript, err := buyer.GenerateMultisigScript(keys, 2, time.Hour, vendorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorAddr, vendorScript, err := vendor.GenerateMultisigScript(keys, 2, time.Hour, vendorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendorAddr.String() != addr.String() || !bytes.Equal(vendorScript, redeemScript) {
+		t.Fatal("expected both parties to derive the same escrow")
+	}
+	if err := vendor.AddWatchedAddress(addr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buyer.Spend(250000000, addr, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The vendor completes the order, paying itself and refunding the rest
+	outs := []wallet.TransactionOutput{
+		{Address: vendor.CurrentAddress(wallet.INTERNAL), Value: 200000000},
+		{Address: buyer.CurrentAddress(wallet.INTERNAL), Value: 50000000},
+	}
+	buyerSigs, err := buyer.CreateMultisigSignature(nil, outs, buyerKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	moderatorSigs, err := buyer.CreateMultisigSignature(nil, outs, moderatorKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vendor.Multisign(nil, outs, buyerSigs, buyerSigs, redeemScript, 0, true); err == nil {
+		t.Fatal("expected one signature to be refused")
+	}
+	if _, err := vendor.Multisign(nil, outs, buyerSigs, moderatorSigs, redeemScript, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(tokens(2)) != 0 {
+		t.Errorf("expected the vendor to be paid two tokens, got %s", balance)
+	}
+	if balance := chain.balanceOf(buyer.Account()); balance.Cmp(oneToken) != 0 {
+		t.Errorf("expected the buyer to hold the token left and the refund, got %s", balance)
+	}
+
+	cbs := syncCallbacks(t, vendor)
+	if len(cbs) != 2 {
+		t.Fatalf("expected the funding and the release, got %d transactions", len(cbs))
+	}
+	if len(cbs[0].Outputs) != 1 || cbs[0].Outputs[0].Address.String() != addr.String() || cbs[0].Outputs[0].Value != 250000000 {
+		t.Errorf("unexpected funding %+v", cbs[0])
+	}
+	if len(cbs[1].Inputs) != 1 || cbs[1].Inputs[0].LinkedAddress.String() != addr.String() || cbs[1].Value != 200000000 {
+		t.Errorf("unexpected release %+v", cbs[1])
+	}
+}
+
+func TestWalletSweepAfterTimeout(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), tokens(1))
+
+	buyerKey, vendorKey, moderatorKey := newOrderKey(t, 1), newOrderKey(t, 2), newOrderKey(t, 3)
+	buyerPub, _ := buyerKey.Neuter()
+	vendorPub, _ := vendorKey.Neuter()
+	moderatorPub, _ := moderatorKey.Neuter()
+	addr, redeemScript, err := buyer.GenerateMultisigScript([]hd.ExtendedKey{*buyerPub, *vendorPub, *moderatorPub}, 2, time.Hour, vendorPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buyer.Spend(100000000, addr, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	var to btc.Address = vendor.CurrentAddress(wallet.INTERNAL)
+	if _, err := vendor.SweepAddress(nil, &to, vendorKey, &redeemScript, wallet.NORMAL); err == nil {
+		t.Fatal("expected the sweep to fail before the timeout")
+	}
+	chain.advance(time.Hour)
+	if _, err := vendor.SweepAddress(nil, &to, vendorKey, &redeemScript, wallet.NORMAL); err != nil {
+		t.Fatal(err)
+	}
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(oneToken) != 0 {
+		t.Errorf("expected the vendor to sweep one token, got %s", balance)
+	}
+	if _, err := vendor.SweepAddress(nil, &to, vendorKey, &redeemScript, wallet.NORMAL); err != wallet.ErrorInsuffientFunds {
+		t.Errorf("expected the empty deposit to be refused, got %v", err)
+	}
+}
+
+func TestWalletDeployEscrow(t *testing.T) {
+	chain := newDevChain()
+	w := newTestWallet(t, chain, "deployer")
+	escrow, txid, err := w.DeployEscrow(wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txid == nil {
+		t.Fatal("expected the deployment's transaction")
+	}
+	if code := chain.codeAt(escrow); len(code) == 0 || !bytes.Equal(code, escrowBytecode[len(escrowBytecode)-len(code):]) {
+		t.Fatalf("expected the escrow contract at %s", escrow.Hex())
+	}
+	w.cfg.Escrow = escrow
+	balance, nonce, err := w.deposit(common.HexToHash("0x01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Sign() != 0 || nonce.Sign() != 0 {
+		t.Errorf("expected an empty deposit, got %s with nonce %s", balance, nonce)
+	}
+}
+
+func TestWalletReleaseNonce(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), tokens(2))
+
+	buyerKey, vendorKey, moderatorKey := newOrderKey(t, 1), newOrderKey(t, 2), newOrderKey(t, 3)
+	buyerPub, _ := buyerKey.Neuter()
+	vendorPub, _ := vendorKey.Neuter()
+	moderatorPub, _ := moderatorKey.Neuter()
+	addr, redeemScript, err := buyer.GenerateMultisigScript([]hd.ExtendedKey{*buyerPub, *vendorPub, *moderatorPub}, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buyer.Spend(100000000, addr, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+	outs := []wallet.TransactionOutput{{Address: vendor.CurrentAddress(wallet.INTERNAL), Value: 100000000}}
+	buyerSigs, err := buyer.CreateMultisigSignature(nil, outs, buyerKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorSigs, err := vendor.CreateMultisigSignature(nil, outs, vendorKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, script, err := vendor.release(redeemScript, outs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vendor.Multisign(nil, outs, buyerSigs, vendorSigs, redeemScript, 0, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The deposit is funded again. The signatures of the first release must
+	// not pay it out.
+	if _, err := buyer.Spend(100000000, addr, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vendor.Multisign(nil, outs, buyerSigs, vendorSigs, redeemScript, 0, true); err != ErrInvalidSignature {
+		t.Errorf("expected the old signatures to be refused, got %v", err)
+	}
+	sigs := [][]byte{buyerSigs[0].Signature, vendorSigs[0].Signature}
+	if _, err := vendor.submitRelease(r, script, redeemScript, sigs, wallet.NORMAL, true); err != errReverted {
+		t.Errorf("expected the contract to refuse the replayed release, got %v", err)
+	}
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(oneToken) != 0 {
+		t.Errorf("expected the vendor to be paid once, got %s", balance)
+	}
+}
+
+func TestWalletReleaseFee(t *testing.T) {
+	chain := newDevChain()
+	buyer := newTestWallet(t, chain, "buyer")
+	vendor := newTestWallet(t, chain, "vendor")
+	chain.mint(buyer.Account(), tokens(3))
+
+	if fee := vendor.GetFeePerByte(wallet.NORMAL); fee != 0 {
+		t.Errorf("expected no fee per byte, got %d", fee)
+	}
+	outs := []wallet.TransactionOutput{
+		{Address: vendor.CurrentAddress(wallet.INTERNAL), Value: 200000000},
+		{Address: buyer.CurrentAddress(wallet.INTERNAL), Value: 40000000},
+	}
+	if fee := vendor.EstimateFee(nil, outs, 10); fee != uint64(releaseSize(3, 2, 2))*10 {
+		t.Errorf("expected the fee of the release's call data, got %d", fee)
+	}
+
+	buyerKey, vendorKey, moderatorKey := newOrderKey(t, 1), newOrderKey(t, 2), newOrderKey(t, 3)
+	buyerPub, _ := buyerKey.Neuter()
+	vendorPub, _ := vendorKey.Neuter()
+	moderatorPub, _ := moderatorKey.Neuter()
+	addr, redeemScript, err := buyer.GenerateMultisigScript([]hd.ExtendedKey{*buyerPub, *vendorPub, *moderatorPub}, 2, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buyer.Spend(250000000, addr, wallet.NORMAL, "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The outputs leave a tenth of a token, which pays the vendor for
+	// submitting the release
+	buyerSigs, err := buyer.CreateMultisigSignature(nil, outs, buyerKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vendorSigs, err := vendor.CreateMultisigSignature(nil, outs, vendorKey, redeemScript, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vendor.Multisign(nil, outs, buyerSigs, vendorSigs, redeemScript, 0, true); err != nil {
+		t.Fatal(err)
+	}
+	fee := new(big.Int).Div(oneToken, big.NewInt(10))
+	if balance := chain.balanceOf(vendor.Account()); balance.Cmp(new(big.Int).Add(tokens(2), fee)) != 0 {
+		t.Errorf("expected the vendor to be paid two tokens and the fee, got %s", balance)
+	}
+	if balance, _, _ := vendor.deposit(addr.(*EscrowAddress).ScriptHash); balance.Sign() != 0 {
+		t.Errorf("expected the deposit to be spent, got %s", balance)
+	}
+}
+
+func TestDecodeAddress(t *testing.T) {
+	account := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	escrow := NewEscrowAddress(common.HexToAddress("0x2000000000000000000000000000000000000002"), common.HexToHash("0x01"))
+	for _, addr := range []btc.Address{NewAccountAddress(account), escrow} {
+		decoded, err := DecodeAddress(addr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.String() != addr.String() {
+			t.Errorf("expected %s, got %s", addr, decoded)
+		}
+	}
+	for _, s := range []string{"", "0x12", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", escrow.Contract.Hex() + "/0x12"} {
+		if _, err := DecodeAddress(s); err != ErrInvalidAddress {
+			t.Errorf("expected %q to be invalid, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/OpenBazaar/wallet-interface"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/phoreproject/multiwallet/util"
+	"github.com/phoreproject/openbazaar-go/repo"
+	"github.com/phoreproject/openbazaar-go/schema"
+	"github.com/phoreproject/openbazaar-go/wallet/token"
+)
+
+// tokenCoinTypeFlag marks the coin types given to tokens, which have no
+// registered coin type of their own
+const tokenCoinTypeFlag = 1 << 31
+
+// TokenCoinType returns the coin type the multiwallet keeps the token's
+// wallet under
+func TokenCoinType(code string) util.ExtCoinType {
+	return util.ExtCoinType(tokenCoinTypeFlag | crc32.ChecksumIEEE([]byte(strings.ToUpper(code)))&(tokenCoinTypeFlag-1))
+}
+
+func createTokenWallet(tokenConfig *schema.TokenConfig, cfg *WalletConfig) (util.ExtCoinType, wallet.Wallet, error) {
+	w, err := NewTokenWallet(tokenConfig, cfg)
+	if err != nil {
+		return InvalidCoinType, nil, err
+	}
+	return TokenCoinType(w.CurrencyCode()), w, nil
+}
+
+// NewTokenWallet returns the wallet of the configured token
+func NewTokenWallet(tokenConfig *schema.TokenConfig, cfg *WalletConfig) (*token.Wallet, error) {
+	var (
+		testnet   = cfg.Params.Name != chaincfg.MainNetParams.Name
+		code      = strings.ToUpper(tokenConfig.Code)
+		endpoints = tokenConfig.APIPool
+	)
+	if testnet {
+		code = "T" + code
+		endpoints = tokenConfig.APITestnetPool
+	}
+	if cfg.PublicKeys != nil {
+		return nil, ErrMnemonicRequired
+	}
+	if len(endpoints) == 0 {
+		return nil, errors.New("no JSON-RPC endpoint configured")
+	}
+	if !common.IsHexAddress(tokenConfig.Contract) {
+		return nil, fmt.Errorf("invalid token contract %q", tokenConfig.Contract)
+	}
+	if tokenConfig.Escrow != "" && !common.IsHexAddress(tokenConfig.Escrow) {
+		return nil, fmt.Errorf("invalid escrow contract %q", tokenConfig.Escrow)
+	}
+
+	err := repo.RegisterCurrencyDefinition(&repo.CurrencyDefinition{
+		Name:              tokenConfig.Name,
+		Code:              repo.CurrencyCode(strings.ToUpper(tokenConfig.Code)),
+		Divisibility:      tokenConfig.Divisibility,
+		CurrencyType:      repo.Crypto,
+		ChainDivisibility: tokenConfig.Decimals,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("registering currency definition: %s", err)
+	}
+
+	httpClient := new(http.Client)
+	if cfg.Proxy != nil {
+		httpClient.Transport = &http.Transport{Dial: cfg.Proxy.Dial}
+	}
+	client, err := rpc.DialHTTPWithClient(endpoints[0], httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenWalletConfig := token.Config{
+		Code:          code,
+		Contract:      common.HexToAddress(tokenConfig.Contract),
+		Decimals:      tokenConfig.Decimals,
+		Divisibility:  tokenConfig.Divisibility,
+		ChainID:       big.NewInt(tokenConfig.ChainID),
+		StartBlock:    tokenConfig.StartBlock,
+		Confirmations: tokenConfig.Confirmations,
+		Backend:       ethclient.NewClient(client),
+		DB:            CreateTokenWalletDB(cfg.DB, code),
+		Mnemonic:      cfg.Mnemonic,
+	}
+	if tokenConfig.Escrow != "" {
+		tokenWalletConfig.Escrow = common.HexToAddress(tokenConfig.Escrow)
+	}
+	if tokenConfig.MaxGasPrice > 0 {
+		tokenWalletConfig.MaxGasPrice = new(big.Int).Mul(new(big.Int).SetUint64(tokenConfig.MaxGasPrice), big.NewInt(1e9))
+	}
+	return token.NewWallet(tokenWalletConfig)
+}